@@ -0,0 +1,187 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Adaptive Concurrency
+// ============================================================================
+
+// AdaptiveConcurrencySettings configures WithAdaptiveConcurrency.
+type AdaptiveConcurrencySettings struct {
+	// Initial is the starting limit on requests in flight. Defaults to 4.
+	Initial int
+
+	// Min and Max bound the limit. They default to 1 and 100.
+	Min int
+	Max int
+
+	// Backoff is the factor the limit is multiplied by on overload.
+	// Defaults to 0.5.
+	Backoff float64
+
+	// LatencyTolerance is how many times slower than the baseline latency a
+	// response may be before it counts as overload. The baseline tracks the
+	// fastest recent responses. Defaults to 2.
+	LatencyTolerance float64
+
+	// OnLimitChange, if set, is called with the new limit whenever its
+	// whole-number value changes. It must not block.
+	OnLimitChange func(limit int)
+}
+
+// WithAdaptiveConcurrency limits the client's requests in flight with a
+// limit that adapts to the server (AIMD): it grows by about one request per
+// round trip while responses are fast and successful, and is cut by the
+// backoff factor on 429s, 5xx responses, transport errors and latency
+// spikes. Bulk jobs such as backfills then find the sustainable throughput
+// without manual tuning. Requests over the limit wait for a free slot, in
+// priority order; see WithPriority.
+func WithAdaptiveConcurrency(settings AdaptiveConcurrencySettings) ClientOption {
+	return func(c *Client) {
+		c.adaptive = newAdaptiveLimiter(settings)
+	}
+}
+
+// ConcurrencyLimit returns the client's current adaptive concurrency limit,
+// or 0 if adaptive concurrency is not enabled.
+func (c *Client) ConcurrencyLimit() int {
+	return c.adaptive.currentLimit()
+}
+
+// adaptiveLimiter is an AIMD concurrency limiter. A nil limiter admits every
+// request.
+type adaptiveLimiter struct {
+	mu       sync.Mutex
+	settings AdaptiveConcurrencySettings
+	limit    float64
+	inFlight int
+	waiters  waitQueue
+	baseline time.Duration // decaying minimum latency of healthy responses
+	lastCut  time.Time     // when the limit was last cut
+	now      func() time.Time
+}
+
+func newAdaptiveLimiter(settings AdaptiveConcurrencySettings) *adaptiveLimiter {
+	if settings.Min < 1 {
+		settings.Min = 1
+	}
+	if settings.Max < settings.Min {
+		settings.Max = max(100, settings.Min)
+	}
+	if settings.Initial < 1 {
+		settings.Initial = 4
+	}
+	settings.Initial = min(max(settings.Initial, settings.Min), settings.Max)
+	if settings.Backoff <= 0 || settings.Backoff >= 1 {
+		settings.Backoff = 0.5
+	}
+	if settings.LatencyTolerance <= 1 {
+		settings.LatencyTolerance = 2
+	}
+	return &adaptiveLimiter{settings: settings, limit: float64(settings.Initial), now: time.Now}
+}
+
+func (l *adaptiveLimiter) currentLimit() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit)
+}
+
+// acquire waits for a free slot and returns when the request started. Every
+// successful acquire must be followed by a call to done.
+func (l *adaptiveLimiter) acquire(ctx context.Context) (time.Time, error) {
+	if l == nil {
+		return time.Time{}, nil
+	}
+
+	l.mu.Lock()
+	if len(l.waiters) == 0 && l.inFlight < int(l.limit) {
+		l.inFlight++
+		l.mu.Unlock()
+		return l.now(), nil
+	}
+	w := l.waiters.push(PriorityFromContext(ctx))
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return l.now(), nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if !l.waiters.remove(w) {
+			// Admitted while giving up: hand the slot on.
+			l.inFlight--
+			l.admit()
+		}
+		return time.Time{}, ctx.Err()
+	}
+}
+
+// done frees the slot of a request started at start and adjusts the limit
+// from its outcome. A request with no response (resp and err both nil) or
+// cancelled by the caller says nothing about the server and only frees
+// its slot.
+func (l *adaptiveLimiter) done(ctx context.Context, start time.Time, resp *http.Response, err error) {
+	if l == nil {
+		return
+	}
+	now := l.now()
+	latency := now.Sub(start)
+	cancelled := err != nil && errors.Is(ctx.Err(), context.Canceled)
+	overloaded := err != nil || (resp != nil && (resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests))
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	utilized := l.inFlight >= int(l.limit)/2
+	l.inFlight--
+	previous := int(l.limit)
+
+	switch {
+	case cancelled || (resp == nil && err == nil):
+	case overloaded || (l.baseline > 0 && float64(latency) > l.settings.LatencyTolerance*float64(l.baseline)):
+		// Cut at most once per round trip: requests that were already in
+		// flight when the limit was cut reflect the old limit.
+		if !start.Before(l.lastCut) {
+			l.limit = max(float64(l.settings.Min), l.limit*l.settings.Backoff)
+			l.lastCut = now
+		}
+	default:
+		if l.baseline == 0 || latency < l.baseline {
+			l.baseline = latency
+		} else {
+			// Drift up slowly so a lasting change in the server's latency
+			// becomes the new baseline.
+			l.baseline += (latency - l.baseline) / 100
+		}
+		// Only grow when the limit is actually being used; an idle client
+		// says nothing about how much more the server can take.
+		if utilized {
+			l.limit = min(float64(l.settings.Max), l.limit+1/l.limit)
+		}
+	}
+
+	if current := int(l.limit); current != previous && l.settings.OnLimitChange != nil {
+		l.settings.OnLimitChange(current)
+	}
+	l.admit()
+}
+
+// admit hands free slots to waiting requests, highest priority first. The
+// caller must hold l.mu.
+func (l *adaptiveLimiter) admit() {
+	for len(l.waiters) > 0 && l.inFlight < int(l.limit) {
+		close(l.waiters.pop().ready)
+		l.inFlight++
+	}
+}
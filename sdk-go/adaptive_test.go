@@ -0,0 +1,160 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fakeClock is a manually advanced clock for limiter tests.
+type fakeClock struct{ t time.Time }
+
+func (f *fakeClock) now() time.Time          { return f.t }
+func (f *fakeClock) advance(d time.Duration) { f.t = f.t.Add(d) }
+
+func newTestAdaptiveLimiter(settings AdaptiveConcurrencySettings) (*adaptiveLimiter, *fakeClock) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	l := newAdaptiveLimiter(settings)
+	l.now = clock.now
+	return l, clock
+}
+
+// roundTrip runs n concurrent requests that each take latency and finish
+// with status.
+func roundTrip(t *testing.T, l *adaptiveLimiter, clock *fakeClock, n int, latency time.Duration, status int) {
+	t.Helper()
+	ctx := context.Background()
+	starts := make([]time.Time, n)
+	for i := range starts {
+		start, err := l.acquire(ctx)
+		if err != nil {
+			t.Fatalf("acquire() error = %v", err)
+		}
+		starts[i] = start
+	}
+	clock.advance(latency)
+	for _, start := range starts {
+		l.done(ctx, start, &http.Response{StatusCode: status}, nil)
+	}
+}
+
+func TestAdaptiveLimiterGrowsWhileHealthy(t *testing.T) {
+	l, clock := newTestAdaptiveLimiter(AdaptiveConcurrencySettings{Initial: 4, Max: 8})
+
+	for i := 0; i < 10; i++ {
+		roundTrip(t, l, clock, l.currentLimit(), 10*time.Millisecond, http.StatusOK)
+	}
+	if got := l.currentLimit(); got != 8 {
+		t.Errorf("limit = %d, want growth up to Max 8", got)
+	}
+}
+
+func TestAdaptiveLimiterIdleDoesNotGrow(t *testing.T) {
+	l, clock := newTestAdaptiveLimiter(AdaptiveConcurrencySettings{Initial: 10})
+
+	for i := 0; i < 20; i++ {
+		roundTrip(t, l, clock, 1, 10*time.Millisecond, http.StatusOK)
+	}
+	if got := l.currentLimit(); got != 10 {
+		t.Errorf("limit = %d, want 10 while mostly idle", got)
+	}
+}
+
+func TestAdaptiveLimiterBacksOff(t *testing.T) {
+	var changes []int
+	l, clock := newTestAdaptiveLimiter(AdaptiveConcurrencySettings{
+		Initial:       16,
+		OnLimitChange: func(limit int) { changes = append(changes, limit) },
+	})
+
+	// A burst of 429s from one round trip cuts the limit once.
+	roundTrip(t, l, clock, 16, 10*time.Millisecond, http.StatusTooManyRequests)
+	if got := l.currentLimit(); got != 8 {
+		t.Fatalf("limit after 429s = %d, want 8", got)
+	}
+
+	roundTrip(t, l, clock, 8, 10*time.Millisecond, http.StatusServiceUnavailable)
+	if got := l.currentLimit(); got != 4 {
+		t.Fatalf("limit after 503s = %d, want 4", got)
+	}
+
+	// A latency spike also counts as overload.
+	roundTrip(t, l, clock, 4, 10*time.Millisecond, http.StatusOK)
+	roundTrip(t, l, clock, 4, 50*time.Millisecond, http.StatusOK)
+	if got := l.currentLimit(); got != 2 {
+		t.Fatalf("limit after latency spike = %d, want 2", got)
+	}
+
+	for i := 0; i < 5; i++ {
+		roundTrip(t, l, clock, 1, time.Second, http.StatusBadGateway)
+	}
+	if got := l.currentLimit(); got != 1 {
+		t.Errorf("limit = %d, want floor at Min 1", got)
+	}
+	if len(changes) == 0 || changes[len(changes)-1] != 1 {
+		t.Errorf("OnLimitChange calls = %v, want last change to 1", changes)
+	}
+}
+
+func TestAdaptiveLimiterWaitsForSlot(t *testing.T) {
+	l, clock := newTestAdaptiveLimiter(AdaptiveConcurrencySettings{Initial: 1})
+	ctx := context.Background()
+
+	start, err := l.acquire(ctx)
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := l.acquire(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquire() over the limit error = %v, want deadline exceeded", err)
+	}
+
+	admitted := make(chan struct{})
+	go func() {
+		if _, err := l.acquire(ctx); err == nil {
+			close(admitted)
+		}
+	}()
+	select {
+	case <-admitted:
+		t.Fatal("second request admitted while the slot is taken")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.advance(time.Millisecond)
+	l.done(ctx, start, &http.Response{StatusCode: http.StatusOK}, nil)
+	select {
+	case <-admitted:
+	case <-time.After(time.Second):
+		t.Fatal("waiting request not admitted after the slot was freed")
+	}
+}
+
+func TestWithAdaptiveConcurrency(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"code":"RATE_LIMITED","message":"slow down"}}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL),
+		WithAdaptiveConcurrency(AdaptiveConcurrencySettings{Initial: 8}))
+	if got := client.ConcurrencyLimit(); got != 8 {
+		t.Fatalf("ConcurrencyLimit() = %d, want 8", got)
+	}
+
+	if _, err := client.VerifyMath(context.Background(), "1+1=2"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("VerifyMath() error = %v, want rate limited", err)
+	}
+	if got := client.ConcurrencyLimit(); got != 4 {
+		t.Errorf("ConcurrencyLimit() after 429 = %d, want 4", got)
+	}
+
+	if got := NewClient("test-key").ConcurrencyLimit(); got != 0 {
+		t.Errorf("ConcurrencyLimit() without adaptive concurrency = %d, want 0", got)
+	}
+}
@@ -0,0 +1,203 @@
+package qwed
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Attestations
+// ============================================================================
+
+// ErrInvalidAttestation is returned when a response's attestation is
+// missing, malformed, expired, signed with the wrong key, or does not match
+// the response it accompanies.
+var ErrInvalidAttestation = errors.New("qwed: invalid attestation")
+
+// AttestationClaims are the verified contents of a QWED attestation, a JWT
+// signed by the API over the verification result.
+type AttestationClaims struct {
+	ID        string // attestation ID ("jti")
+	Issuer    string // issuer DID
+	Subject   string // hash of the verified query
+	KeyID     string // signing key ID from the JWT header
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+
+	Status     Status
+	Verified   bool
+	Engine     Engine
+	Confidence float64
+	QueryHash  string
+	ProofHash  string
+}
+
+// WithResponseVerification checks the attestation of every verification
+// response against publicKey before returning it, so a "verified" result is
+// known to come from the service. publicKey is an *ecdsa.PublicKey on P-256
+// for ES256 attestations, the API's default, or an ed25519.PublicKey for
+// EdDSA. Responses without a valid attestation of the query sent fail with
+// ErrInvalidAttestation and are not cached.
+func WithResponseVerification(publicKey crypto.PublicKey) ClientOption {
+	return func(c *Client) {
+		c.attestationKey = publicKey
+	}
+}
+
+// checkAttestation verifies resp.Attestation if the client has an
+// attestation key, and that it attests the result of req, so an attestation
+// issued for another query cannot be replayed onto this one.
+func (c *Client) checkAttestation(req *Request, resp *VerificationResponse) error {
+	if c.attestationKey == nil {
+		return nil
+	}
+	if resp.Attestation == "" {
+		return fmt.Errorf("%w: response is not attested", ErrInvalidAttestation)
+	}
+	claims, err := VerifyAttestation(resp.Attestation, c.attestationKey)
+	if err != nil {
+		return err
+	}
+	if claims.Verified != resp.Verified ||
+		(claims.Status != "" && resp.Status != "" && claims.Status != resp.Status) ||
+		(claims.Engine != "" && resp.Engine != "" && claims.Engine != resp.Engine) {
+		return fmt.Errorf("%w: attested result does not match response", ErrInvalidAttestation)
+	}
+	if claims.QueryHash != contentHash([]byte(attestedQuery(req))) {
+		return fmt.Errorf("%w: attestation is for a different query", ErrInvalidAttestation)
+	}
+	return nil
+}
+
+// attestedQuery returns the input the API hashes into an attestation's
+// query_hash: the engine's first required body field, or "query".
+func attestedQuery(req *Request) string {
+	field := "query"
+	if names := requiredFields[req.Engine]; len(names) > 0 {
+		field = names[0]
+	}
+	var fields map[string]interface{}
+	if data, err := json.Marshal(req.Body); err == nil {
+		_ = json.Unmarshal(data, &fields)
+	}
+	query, _ := fields[field].(string)
+	return query
+}
+
+// VerifyAttestation checks the signature and expiry of an attestation JWT,
+// such as VerificationResponse.Attestation, and returns its claims.
+// Downstream systems can use it to trust a result passed on to them.
+func VerifyAttestation(token string, publicKey crypto.PublicKey) (*AttestationClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidAttestation)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidAttestation)
+	}
+	if err := verifySignature(header.Alg, publicKey, []byte(parts[0]+"."+parts[1]), sig, ErrInvalidAttestation); err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Jti  string `json:"jti"`
+		Iss  string `json:"iss"`
+		Sub  string `json:"sub"`
+		Iat  int64  `json:"iat"`
+		Exp  int64  `json:"exp"`
+		Qwed struct {
+			Result struct {
+				Status     Status  `json:"status"`
+				Verified   bool    `json:"verified"`
+				Engine     Engine  `json:"engine"`
+				Confidence float64 `json:"confidence"`
+			} `json:"result"`
+			QueryHash string `json:"query_hash"`
+			ProofHash string `json:"proof_hash"`
+		} `json:"qwed"`
+	}
+	if err := decodeSegment(parts[1], &payload); err != nil {
+		return nil, err
+	}
+	if payload.Exp != 0 && time.Now().Unix() >= payload.Exp {
+		return nil, fmt.Errorf("%w: expired", ErrInvalidAttestation)
+	}
+
+	result := payload.Qwed.Result
+	return &AttestationClaims{
+		ID:         payload.Jti,
+		Issuer:     payload.Iss,
+		Subject:    payload.Sub,
+		KeyID:      header.Kid,
+		IssuedAt:   time.Unix(payload.Iat, 0),
+		ExpiresAt:  time.Unix(payload.Exp, 0),
+		Status:     result.Status,
+		Verified:   result.Verified,
+		Engine:     result.Engine,
+		Confidence: result.Confidence,
+		QueryHash:  payload.Qwed.QueryHash,
+		ProofHash:  payload.Qwed.ProofHash,
+	}, nil
+}
+
+// verifySignature checks a JWS signature, wrapping invalid in the errors it
+// returns. The algorithm must match the key type, so a token cannot pick a
+// weaker algorithm than the key implies.
+func verifySignature(alg string, publicKey crypto.PublicKey, signed, sig []byte, invalid error) error {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if alg != "ES256" || key.Curve != elliptic.P256() {
+			break
+		}
+		if len(sig) != 64 {
+			return fmt.Errorf("%w: bad signature", invalid)
+		}
+		digest := sha256.Sum256(signed)
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(key, digest[:], r, s) {
+			return fmt.Errorf("%w: bad signature", invalid)
+		}
+		return nil
+	case ed25519.PublicKey:
+		if alg != "EdDSA" {
+			break
+		}
+		if !ed25519.Verify(key, signed, sig) {
+			return fmt.Errorf("%w: bad signature", invalid)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported signing key type %T", publicKey)
+	}
+	return fmt.Errorf("%w: algorithm %q does not match key", invalid, alg)
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: malformed token", ErrInvalidAttestation)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: malformed token", ErrInvalidAttestation)
+	}
+	return nil
+}
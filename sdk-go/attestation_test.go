@@ -0,0 +1,163 @@
+package qwed
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signAttestation issues an attestation JWT for query the way the API does.
+func signAttestation(t *testing.T, key crypto.Signer, query string, verified bool, exp time.Time) string {
+	t.Helper()
+
+	alg := "ES256"
+	if _, ok := key.(ed25519.PrivateKey); ok {
+		alg = "EdDSA"
+	}
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "qwed-attestation+jwt", "kid": "key-1"})
+	payload, _ := json.Marshal(map[string]interface{}{
+		"iss": "did:qwed:test",
+		"sub": contentHash([]byte(query)),
+		"iat": time.Now().Unix(),
+		"exp": exp.Unix(),
+		"jti": "att_123",
+		"qwed": map[string]interface{}{
+			"version":    "1.0",
+			"result":     map[string]interface{}{"status": "VERIFIED", "verified": verified, "engine": "math", "confidence": 1.0},
+			"query_hash": contentHash([]byte(query)),
+		},
+	})
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var sig []byte
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(k, []byte(signed))
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func attestedServer(attestation string, verified bool) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(VerificationResponse{
+			Status:      StatusVerified,
+			Verified:    verified,
+			Engine:      "math",
+			Attestation: attestation,
+		})
+	}
+}
+
+func TestResponseVerification(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	valid := signAttestation(t, key, "2+2=4", true, time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name        string
+		attestation string
+		verified    bool
+		wantErr     bool
+	}{
+		{"valid", valid, true, false},
+		{"missing", "", true, true},
+		{"wrong key", signAttestation(t, other, "2+2=4", true, time.Now().Add(time.Hour)), true, true},
+		{"expired", signAttestation(t, key, "2+2=4", true, time.Now().Add(-time.Hour)), true, true},
+		{"tampered verdict", signAttestation(t, key, "2+2=4", false, time.Now().Add(time.Hour)), true, true},
+		{"other query", signAttestation(t, key, "1+1=2", true, time.Now().Add(time.Hour)), true, true},
+		{"malformed", "not-a-jwt", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := mockServer(attestedServer(tt.attestation, tt.verified))
+			defer server.Close()
+
+			cache := NewLRUCache(0)
+			client := NewClient("test-key", WithBaseURL(server.URL), WithResponseVerification(&key.PublicKey), WithCache(cache, time.Minute))
+			resp, err := client.VerifyMath(context.Background(), "2+2=4")
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAttestation) {
+					t.Errorf("expected ErrInvalidAttestation, got %v", err)
+				}
+				if cache.Len() != 0 {
+					t.Error("expected rejected response not to be cached")
+				}
+				return
+			}
+			if err != nil || !resp.Verified || resp.Attestation != valid {
+				t.Errorf("expected attested response, got %+v, %v", resp, err)
+			}
+		})
+	}
+}
+
+func TestResponseVerificationRejectsReplay(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	// The server attests the first query correctly, then replays that
+	// attestation onto a second query.
+	var first string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if first == "" {
+			first = signAttestation(t, key, "2+2=4", true, time.Now().Add(time.Hour))
+		}
+		attestedServer(first, true)(w, r)
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithResponseVerification(&key.PublicKey))
+	if _, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil {
+		t.Fatalf("expected first query to verify, got %v", err)
+	}
+	if _, err := client.VerifyMath(context.Background(), "2+2=5"); !errors.Is(err, ErrInvalidAttestation) {
+		t.Errorf("expected replayed attestation to be rejected, got %v", err)
+	}
+}
+
+func TestVerifyAttestation(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	token := signAttestation(t, priv, "2+2=4", true, time.Now().Add(time.Hour))
+
+	claims, err := VerifyAttestation(token, pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.ID != "att_123" || claims.Issuer != "did:qwed:test" || claims.KeyID != "key-1" ||
+		!claims.Verified || claims.Status != StatusVerified || claims.Engine != "math" || claims.QueryHash != contentHash([]byte("2+2=4")) {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+
+	// An EdDSA token must not verify against an ECDSA key, and vice versa.
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if _, err := VerifyAttestation(token, &ecKey.PublicKey); !errors.Is(err, ErrInvalidAttestation) {
+		t.Errorf("expected algorithm mismatch, got %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(`{"qwed":{"result":{"verified":true}}}`))
+	if _, err := VerifyAttestation(strings.Join(parts, "."), pub); !errors.Is(err, ErrInvalidAttestation) {
+		t.Errorf("expected tampered payload to fail, got %v", err)
+	}
+}
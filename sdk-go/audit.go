@@ -0,0 +1,151 @@
+package qwed
+
+import (
+	"context"
+	"sync"
+)
+
+// ============================================================================
+// Answer Audit
+// ============================================================================
+
+// AuditVerdict is the overall outcome of an answer audit.
+type AuditVerdict string
+
+const (
+	AuditPass         AuditVerdict = "pass"         // every claim verified
+	AuditFail         AuditVerdict = "fail"         // at least one claim failed verification
+	AuditInconclusive AuditVerdict = "inconclusive" // no failures, but some claims could not be checked
+)
+
+// AuditOptions configures AuditAnswer.
+type AuditOptions struct {
+	// Concurrency limits the number of claims verified at once.
+	// Defaults to 4.
+	Concurrency int
+
+	// Fact sets language handling for fact claims.
+	Fact *FactOptions
+}
+
+// ClaimAudit is the verification outcome for one claim of an answer.
+type ClaimAudit struct {
+	Claim    AtomicClaim           `json:"claim"`
+	Verified bool                  `json:"verified"`
+	Response *VerificationResponse `json:"response,omitempty"`
+	Error    string                `json:"error,omitempty"`
+}
+
+// AnswerAudit is the aggregated report produced by AuditAnswer.
+type AnswerAudit struct {
+	Question string       `json:"question,omitempty"`
+	Answer   string       `json:"answer"`
+	Verdict  AuditVerdict `json:"verdict"`
+	Claims   []ClaimAudit `json:"claims"`
+	Verified int          `json:"verified"`
+	Failed   int          `json:"failed"`
+	Errors   int          `json:"errors"` // claims that errored or were inconclusive
+}
+
+// Safe reports whether every claim in the answer was verified.
+func (a *AnswerAudit) Safe() bool {
+	return a.Verdict == AuditPass
+}
+
+// AuditAnswer checks whether an LLM answer is safe to show. The answer is
+// split into atomic claims with DecomposeClaims; arithmetic claims go to the
+// math engine, and factual claims are checked against factContext with
+// VerifyFact, or with natural language verification if factContext is
+// empty. An answer with no decomposable claims, such as "42", is verified
+// together with the question.
+//
+// Per-claim errors are recorded in the report rather than returned; the
+// error result is only set if decomposition fails or ctx is cancelled.
+func (c *Client) AuditAnswer(ctx context.Context, question, answer, factContext string, opts *AuditOptions) (*AnswerAudit, error) {
+	var o AuditOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Concurrency < 1 {
+		o.Concurrency = 4
+	}
+
+	claims, err := DecomposeClaims(ctx, answer)
+	if err != nil {
+		return nil, err
+	}
+	if len(claims) == 0 && answer != "" {
+		claims = []AtomicClaim{{Text: answer, End: len(answer), Type: TypeNaturalLanguage}}
+	}
+
+	audit := &AnswerAudit{
+		Question: question,
+		Answer:   answer,
+		Claims:   make([]ClaimAudit, len(claims)),
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, o.Concurrency)
+	for i, claim := range claims {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, claim AtomicClaim) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, err := c.auditClaim(ctx, question, claim, factContext, o.Fact)
+			result := ClaimAudit{Claim: claim, Response: resp}
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Verified = IsVerified(resp)
+			}
+			audit.Claims[i] = result
+		}(i, claim)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, claim := range audit.Claims {
+		switch {
+		case claim.Error != "", claim.Response.Verdict() == VerdictInconclusive:
+			audit.Errors++
+		case claim.Verified:
+			audit.Verified++
+		default:
+			audit.Failed++
+		}
+	}
+	switch {
+	case audit.Failed > 0:
+		audit.Verdict = AuditFail
+	case audit.Errors > 0 || len(audit.Claims) == 0:
+		audit.Verdict = AuditInconclusive
+	default:
+		audit.Verdict = AuditPass
+	}
+	return audit, nil
+}
+
+// auditClaim routes a claim to the engine matching its type.
+func (c *Client) auditClaim(ctx context.Context, question string, claim AtomicClaim, factContext string, fact *FactOptions) (*VerificationResponse, error) {
+	switch {
+	case claim.Type == TypeMath:
+		return c.VerifyMath(ctx, claim.Text)
+	case claim.Type == TypeNaturalLanguage:
+		return c.Verify(ctx, joinQuestion(question, claim.Text))
+	case factContext != "":
+		return c.VerifyFactWithOptions(ctx, claim.Text, factContext, fact)
+	}
+	return c.Verify(ctx, claim.Text)
+}
+
+func joinQuestion(question, answer string) string {
+	if question == "" {
+		return answer
+	}
+	return question + "\n" + answer
+}
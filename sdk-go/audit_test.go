@@ -0,0 +1,72 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAuditAnswer(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var verified bool
+		switch r.URL.Path {
+		case "/verify/math":
+			verified = strings.Contains(req["expression"].(string), "= 4")
+		case "/verify/fact":
+			verified = strings.Contains(req["claim"].(string), "1889")
+		case "/verify/natural_language":
+			if strings.Contains(req["query"].(string), "timeout") {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"message": "busy"}})
+				return
+			}
+			verified = strings.Contains(req["query"].(string), "Paris")
+		}
+		json.NewEncoder(w).Encode(VerificationResponse{Verified: verified})
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	source := "The Eiffel Tower was completed in 1889."
+
+	audit, err := client.AuditAnswer(ctx, "", "The tower was completed in 1889. 2 + 2 = 4.", source, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if audit.Verdict != AuditPass || !audit.Safe() || audit.Verified != 2 {
+		t.Errorf("expected pass, got %+v", audit)
+	}
+	if audit.Claims[1].Claim.Type != TypeMath {
+		t.Errorf("expected arithmetic claim to be routed to math, got %+v", audit.Claims[1])
+	}
+
+	audit, err = client.AuditAnswer(ctx, "", "The tower was completed in 1887. 2 + 2 = 4.", source, &AuditOptions{Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if audit.Verdict != AuditFail || audit.Failed != 1 || audit.Claims[0].Verified {
+		t.Errorf("expected fail on wrong year, got %+v", audit)
+	}
+
+	audit, err = client.AuditAnswer(ctx, "What is the capital of France?", "Paris", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if audit.Verdict != AuditPass || len(audit.Claims) != 1 || audit.Claims[0].Claim.Type != TypeNaturalLanguage {
+		t.Errorf("expected short answer to be verified with the question, got %+v", audit)
+	}
+
+	audit, err = client.AuditAnswer(ctx, "", "The request will timeout eventually.", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if audit.Verdict != AuditInconclusive || audit.Errors != 1 || audit.Claims[0].Error == "" {
+		t.Errorf("expected inconclusive on API error, got %+v", audit)
+	}
+}
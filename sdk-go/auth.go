@@ -0,0 +1,347 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Bearer Token Authentication
+// ============================================================================
+
+// tokenExpiryDelta is how long before its expiry a token is refreshed, so
+// it does not expire in flight.
+const tokenExpiryDelta = 10 * time.Second
+
+// Token is an OAuth2 or JWT bearer token.
+type Token struct {
+	AccessToken string
+	Expiry      time.Time // zero if the token does not expire
+}
+
+// valid reports whether the token can still be sent at now.
+func (t *Token) valid(now time.Time) bool {
+	return t != nil && t.AccessToken != "" &&
+		(t.Expiry.IsZero() || now.Add(tokenExpiryDelta).Before(t.Expiry))
+}
+
+// TokenSource supplies bearer tokens. ctx is the context of the request
+// needing the token, so a slow token endpoint does not outlive it. A
+// golang.org/x/oauth2 TokenSource can be adapted with TokenSourceFunc:
+//
+//	src := conf.TokenSource(ctx) // an oauth2.TokenSource
+//	client := qwed.NewClient("", qwed.WithTokenSource(qwed.TokenSourceFunc(func(context.Context) (*qwed.Token, error) {
+//		t, err := src.Token()
+//		if err != nil {
+//			return nil, err
+//		}
+//		return &qwed.Token{AccessToken: t.AccessToken, Expiry: t.Expiry}, nil
+//	})))
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource.
+type TokenSourceFunc func(ctx context.Context) (*Token, error)
+
+// Token calls f.
+func (f TokenSourceFunc) Token(ctx context.Context) (*Token, error) { return f(ctx) }
+
+// WithTokenSource authenticates requests with an "Authorization: Bearer"
+// header from src instead of the X-API-Key header, for deployments that
+// front the API with OAuth2. Tokens are reused until shortly before they
+// expire, then fetched again; a token the API rejects with 401 is dropped
+// and the request retried once with a fresh one. API keys passed to
+// NewClient or WithAPIKeys are ignored.
+func WithTokenSource(src TokenSource) ClientOption {
+	return func(c *Client) {
+		c.tokens = &tokenCache{src: src}
+	}
+}
+
+// WithTokenStore saves the tokens of WithTokenSource under key in store,
+// so a restarted process, or other processes sharing the store, reuse a
+// token until it expires instead of fetching their own. A stored token the
+// API rejects with 401 is replaced. Store failures are not fatal: a token
+// that cannot be loaded is fetched from the token source, and one that
+// cannot be saved is still used. Tokens are credentials, so use a store
+// only the service can read.
+func WithTokenStore(store Store, key string) ClientOption {
+	return func(c *Client) {
+		c.tokenStore, c.tokenKey = store, key
+	}
+}
+
+// tokenCache reuses the token from a TokenSource until it expires. Tokens
+// are fetched without holding the lock, once for all concurrent requests.
+type tokenCache struct {
+	src        TokenSource
+	httpClient *http.Client // passed to ClientCredentials through the context
+	store      Store        // nil unless WithTokenStore is used
+	key        string
+
+	mu       sync.Mutex
+	token    *Token
+	rejected string      // the last token the API rejected, ignored in the store
+	fetching *tokenFetch // nil unless a fetch is in flight
+}
+
+// storedToken is a Token as saved by WithTokenStore.
+type storedToken struct {
+	AccessToken string    `json:"access_token"`
+	Expiry      time.Time `json:"expiry,omitempty"`
+}
+
+// tokenFetch is a token fetch shared by concurrent requests.
+type tokenFetch struct {
+	done      chan struct{}
+	err       error
+	abandoned bool // the fetching request's context ended; waiters fetch again
+}
+
+// get returns the cached token. An expired token is replaced from the
+// store, if it holds a valid one, or else from the token source. Requests waiting for another request's fetch stop waiting when ctx is
+// done.
+func (t *tokenCache) get(ctx context.Context) (string, error) {
+	for {
+		t.mu.Lock()
+		if t.token.valid(time.Now()) {
+			access := t.token.AccessToken
+			t.mu.Unlock()
+			return access, nil
+		}
+		if f := t.fetching; f != nil {
+			t.mu.Unlock()
+			select {
+			case <-f.done:
+			case <-ctx.Done():
+				return "", fmt.Errorf("failed to get bearer token: %w", ctx.Err())
+			}
+			if f.err != nil && !f.abandoned {
+				return "", f.err
+			}
+			continue
+		}
+		f := &tokenFetch{done: make(chan struct{})}
+		t.fetching = f
+		rejected := t.rejected
+		t.mu.Unlock()
+
+		var err error
+		token := t.load(ctx, rejected)
+		if token == nil {
+			token, err = t.src.Token(context.WithValue(ctx, tokenHTTPClientKey{}, t.httpClient))
+			if err == nil && (token == nil || token.AccessToken == "") {
+				err = errors.New("token source returned an empty token")
+			}
+			if err == nil {
+				t.save(ctx, token)
+			}
+		}
+
+		t.mu.Lock()
+		t.fetching = nil
+		if err != nil {
+			f.err = fmt.Errorf("failed to get bearer token: %w", err)
+			f.abandoned = ctx.Err() != nil
+		} else {
+			t.token = token
+		}
+		t.mu.Unlock()
+		close(f.done)
+
+		if err != nil {
+			return "", f.err
+		}
+		return token.AccessToken, nil
+	}
+}
+
+// invalidate drops the cached token if it is still access, the token the
+// API rejected, so the next get fetches a new one. Concurrent requests
+// rejected with the same token refresh it once between them.
+func (t *tokenCache) invalidate(access string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token != nil && t.token.AccessToken == access {
+		t.token = nil
+	}
+	t.rejected = access
+}
+
+// load returns the token saved in the store, or nil if there is none that
+// is valid and differs from rejected.
+func (t *tokenCache) load(ctx context.Context, rejected string) *Token {
+	if t.store == nil {
+		return nil
+	}
+	data, err := t.store.Get(ctx, t.key)
+	if err != nil {
+		return nil
+	}
+	var stored storedToken
+	if json.Unmarshal(data, &stored) != nil || stored.AccessToken == rejected {
+		return nil
+	}
+	token := &Token{AccessToken: stored.AccessToken, Expiry: stored.Expiry}
+	if !token.valid(time.Now()) {
+		return nil
+	}
+	return token
+}
+
+// save stores token for other processes. Failures are ignored; the token
+// is fetched again where it could not be loaded.
+func (t *tokenCache) save(ctx context.Context, token *Token) {
+	if t.store == nil {
+		return
+	}
+	data, err := json.Marshal(storedToken{AccessToken: token.AccessToken, Expiry: token.Expiry})
+	if err == nil {
+		t.store.Put(ctx, t.key, data)
+	}
+}
+
+// applyTokens gives the token cache the HTTP client token endpoints are
+// called with: the client's own, unless it dials the API's Unix socket. It
+// also gives it the store of WithTokenStore.
+func (c *Client) applyTokens() {
+	if c.tokens == nil {
+		return
+	}
+	c.tokens.store, c.tokens.key = c.tokenStore, c.tokenKey
+	c.tokens.httpClient = c.httpClient
+	if c.unixSocket != "" {
+		c.tokens.httpClient = &http.Client{Timeout: c.httpClient.Timeout}
+	}
+}
+
+// credential returns the API key or bearer token requests are sent with.
+func (c *Client) credential(ctx context.Context) (string, error) {
+	if c.tokens != nil {
+		return c.tokens.get(ctx)
+	}
+	key, _ := c.keys.current()
+	return key, nil
+}
+
+// setAuth sets the header authenticating req with credential.
+func (c *Client) setAuth(h http.Header, credential string) {
+	if c.tokens != nil {
+		h.Set("Authorization", "Bearer "+credential)
+		return
+	}
+	h.Set("X-API-Key", credential)
+}
+
+// roundTripToken performs roundTrip with a bearer token, retrying once
+// with a fresh token when the API rejects it with 401. A 403 means the
+// token is valid but lacks permission, so it is not refreshed.
+func (c *Client) roundTripToken(ctx context.Context, method, url string, data []byte, result interface{}) error {
+	for retried := false; ; retried = true {
+		token, err := c.tokens.get(ctx)
+		if err != nil {
+			return err
+		}
+		err = c.roundTrip(ctx, method, url, token, data, result)
+		var apiErr *QWEDError
+		if retried || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+			return err
+		}
+		c.tokens.invalidate(token)
+	}
+}
+
+// ============================================================================
+// Client Credentials
+// ============================================================================
+
+// ClientCredentials is a TokenSource for the OAuth2 client credentials
+// grant, for services authenticating as themselves. Use it with
+// WithTokenSource:
+//
+//	client := qwed.NewClient("", qwed.WithTokenSource(&qwed.ClientCredentials{
+//		TokenURL:     "https://auth.example.com/oauth2/token",
+//		ClientID:     id,
+//		ClientSecret: secret,
+//		Scopes:       []string{"qwed.verify"},
+//	}))
+type ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	Audience     string       // sent as the audience parameter, if set
+	HTTPClient   *http.Client // the qwed client's HTTP client if nil
+}
+
+// tokenHTTPClientKey carries the qwed client's HTTP client to
+// ClientCredentials.Token.
+type tokenHTTPClientKey struct{}
+
+// Token requests a new token from the token endpoint.
+func (cc *ClientCredentials) Token(ctx context.Context) (*Token, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(cc.Scopes) > 0 {
+		form.Set("scope", strings.Join(cc.Scopes, " "))
+	}
+	if cc.Audience != "" {
+		form.Set("audience", cc.Audience)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", cc.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(cc.ClientID), url.QueryEscape(cc.ClientSecret))
+
+	httpClient := cc.HTTPClient
+	if httpClient == nil {
+		httpClient, _ = ctx.Value(tokenHTTPClientKey{}).(*http.Client)
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("token request failed: HTTP %d: %s", resp.StatusCode, data)
+	}
+
+	var body struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal token response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access_token")
+	}
+	if body.TokenType != "" && !strings.EqualFold(body.TokenType, "bearer") {
+		return nil, fmt.Errorf("unsupported token type %q", body.TokenType)
+	}
+
+	token := &Token{AccessToken: body.AccessToken}
+	if body.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
@@ -0,0 +1,259 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithTokenSource(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != "" {
+			t.Error("expected no API key with a token source")
+		}
+		if r.Header.Get("Authorization") != "Bearer tok-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	})
+	defer server.Close()
+
+	var fetched int32
+	src := TokenSourceFunc(func(context.Context) (*Token, error) {
+		atomic.AddInt32(&fetched, 1)
+		return &Token{AccessToken: "tok-1", Expiry: time.Now().Add(time.Hour)}, nil
+	})
+	client := NewClient("ignored", WithBaseURL(server.URL), WithTokenSource(src))
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.VerifyMath(context.Background(), "2+2"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&fetched); n != 1 {
+		t.Errorf("expected the token to be reused, fetched %d times", n)
+	}
+}
+
+func TestTokenSourceRefresh(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer revoked" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	})
+	defer server.Close()
+
+	tokens := []*Token{
+		{AccessToken: "expiring", Expiry: time.Now().Add(time.Second)},
+		{AccessToken: "revoked", Expiry: time.Now().Add(time.Hour)},
+		{AccessToken: "fresh", Expiry: time.Now().Add(time.Hour)},
+	}
+	var next int
+	src := TokenSourceFunc(func(context.Context) (*Token, error) {
+		tok := tokens[next]
+		next++
+		return tok, nil
+	})
+	client := NewClient("", WithBaseURL(server.URL), WithTokenSource(src))
+
+	// The first token is within the expiry delta, so it is used once and
+	// replaced; the second is rejected and replaced on retry.
+	for i := 0; i < 2; i++ {
+		if _, err := client.VerifyMath(context.Background(), "2+2"); err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+	}
+	if next != 3 {
+		t.Errorf("expected 3 tokens fetched, got %d", next)
+	}
+}
+
+func TestTokenStore(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer revoked" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	})
+	defer server.Close()
+
+	store := NewMemoryStore()
+	var fetched []string
+	source := func(access string) TokenSource {
+		return TokenSourceFunc(func(context.Context) (*Token, error) {
+			fetched = append(fetched, access)
+			return &Token{AccessToken: access, Expiry: time.Now().Add(time.Hour)}, nil
+		})
+	}
+
+	// A second client sharing the store reuses the first client's token.
+	ctx := context.Background()
+	first := NewClient("", WithBaseURL(server.URL), WithTokenStore(store, "tokens/qwed.json"), WithTokenSource(source("revoked")))
+	if _, err := first.VerifyMath(ctx, "2+2"); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected the revoked token to be rejected, got %v", err)
+	}
+	second := NewClient("", WithBaseURL(server.URL), WithTokenSource(source("fresh")), WithTokenStore(store, "tokens/qwed.json"))
+	if _, err := second.VerifyMath(ctx, "2+2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(fetched, ",") != "revoked,revoked,fresh" {
+		t.Errorf("expected the rejected stored token to be replaced once, fetched %v", fetched)
+	}
+
+	third := NewClient("", WithBaseURL(server.URL), WithTokenSource(source("unused")), WithTokenStore(store, "tokens/qwed.json"))
+	if _, err := third.VerifyMath(ctx, "2+2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fetched) != 3 {
+		t.Errorf("expected the stored token to be reused, fetched %v", fetched)
+	}
+	data, _ := store.Get(ctx, "tokens/qwed.json")
+	if !strings.Contains(string(data), `"access_token":"fresh"`) {
+		t.Errorf("unexpected stored token: %s", data)
+	}
+}
+
+func TestTokenSourceErrors(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	defer server.Close()
+
+	var fetched int
+	client := NewClient("", WithBaseURL(server.URL), WithTokenSource(TokenSourceFunc(func(context.Context) (*Token, error) {
+		fetched++
+		return &Token{AccessToken: "bad"}, nil
+	})))
+	if _, err := client.VerifyMath(context.Background(), "2+2"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if fetched != 2 {
+		t.Errorf("expected one retry with a fresh token, fetched %d", fetched)
+	}
+
+	failing := errors.New("identity provider down")
+	client = NewClient("", WithBaseURL(server.URL), WithTokenSource(TokenSourceFunc(func(context.Context) (*Token, error) {
+		return nil, failing
+	})))
+	if _, err := client.VerifyMath(context.Background(), "2+2"); !errors.Is(err, failing) {
+		t.Errorf("expected the token source error, got %v", err)
+	}
+}
+
+func TestTokenSourceForbiddenNotRefreshed(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	defer server.Close()
+
+	var fetched, requests int32
+	client := NewClient("", WithBaseURL(server.URL), WithTokenSource(TokenSourceFunc(func(context.Context) (*Token, error) {
+		atomic.AddInt32(&fetched, 1)
+		return &Token{AccessToken: "limited"}, nil
+	})), WithHooks(Hooks{BeforeRequest: func(*http.Request, []byte) { atomic.AddInt32(&requests, 1) }}))
+	if _, err := client.VerifyMath(context.Background(), "2+2"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if fetched != 1 || requests != 1 {
+		t.Errorf("expected no refresh or retry on 403, fetched %d tokens for %d requests", fetched, requests)
+	}
+}
+
+func TestTokenSourceHonoursContext(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	})
+	defer server.Close()
+
+	// The token endpoint hangs until the fetching request gives up.
+	started := make(chan struct{})
+	client := NewClient("", WithBaseURL(server.URL), WithTokenSource(TokenSourceFunc(func(ctx context.Context) (*Token, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.VerifyMath(ctx, "2+2")
+		errs <- err
+	}()
+	<-started
+
+	// A second request waiting for the same fetch gives up with its own
+	// context instead of blocking on the first.
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer waitCancel()
+	if _, err := client.VerifyMath(waitCtx, "2+2"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the waiting request to time out, got %v", err)
+	}
+
+	cancel()
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected the fetching request to be canceled, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the token fetch to stop with the request context")
+	}
+}
+
+func TestClientCredentials(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "svc" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.ParseForm()
+		if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("scope") != "qwed.verify qwed.read" {
+			t.Errorf("unexpected form: %v", r.PostForm)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "abc", "token_type": "Bearer", "expires_in": 3600})
+	})
+	defer server.Close()
+
+	cc := &ClientCredentials{TokenURL: server.URL, ClientID: "svc", ClientSecret: "s3cret", Scopes: []string{"qwed.verify", "qwed.read"}}
+	tok, err := cc.Token(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tok.AccessToken != "abc" || time.Until(tok.Expiry) < 59*time.Minute {
+		t.Errorf("unexpected token: %+v", tok)
+	}
+
+	cc.ClientSecret = "wrong"
+	if _, err := cc.Token(context.Background()); err == nil {
+		t.Error("expected an error for rejected credentials")
+	}
+}
+
+func TestClientCredentialsUsesClientHTTPClient(t *testing.T) {
+	hung := make(chan struct{})
+	tokenServer := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		<-hung
+	})
+	defer tokenServer.Close()
+	defer close(hung)
+
+	// The qwed client's timeout bounds the token request.
+	client := NewClient("", WithTimeout(50*time.Millisecond), WithTokenSource(&ClientCredentials{TokenURL: tokenServer.URL, ClientID: "svc"}))
+	start := time.Now()
+	if _, err := client.VerifyMath(context.Background(), "2+2"); err == nil || !strings.Contains(err.Error(), "bearer token") {
+		t.Errorf("expected the token request to fail, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the client timeout to apply, took %v", elapsed)
+	}
+}
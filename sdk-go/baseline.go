@@ -0,0 +1,208 @@
+package qwed
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// Baselines
+// ============================================================================
+
+// BaselineVersion is the current baseline file format version.
+const BaselineVersion = 1
+
+// Baseline is a set of known, accepted code findings. CI gates can filter
+// VerifyCode results through a baseline so they only fail on new issues.
+type Baseline struct {
+	Version  int             `json:"version"`
+	Findings []BaselineEntry `json:"findings"`
+}
+
+// BaselineEntry records one accepted finding. Entries are matched by
+// fingerprint, which covers the file, rule and source line text but not the
+// line number, so unrelated edits that shift code do not invalidate them.
+type BaselineEntry struct {
+	File        string `json:"file,omitempty"`
+	Rule        string `json:"rule"`
+	Line        int    `json:"line,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	Note        string `json:"note,omitempty"`
+}
+
+// NewBaseline creates an empty baseline.
+func NewBaseline() *Baseline {
+	return &Baseline{Version: BaselineVersion}
+}
+
+// LoadBaseline reads a baseline file.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	return parseBaseline(data)
+}
+
+// LoadBaselineFrom reads the baseline stored under key. A missing baseline
+// fails with an error wrapping ErrNotFound.
+func LoadBaselineFrom(ctx context.Context, store Store, key string) (*Baseline, error) {
+	data, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline: %w", err)
+	}
+	return parseBaseline(data)
+}
+
+func parseBaseline(data []byte) (*Baseline, error) {
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline: %w", err)
+	}
+	if b.Version > BaselineVersion {
+		return nil, fmt.Errorf("baseline version %d is newer than supported version %d", b.Version, BaselineVersion)
+	}
+	return &b, nil
+}
+
+// Save writes the baseline to path with entries in a stable order.
+func (b *Baseline) Save(path string) error {
+	data, err := b.marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+// SaveTo stores the baseline under key in the same format as Save.
+func (b *Baseline) SaveTo(ctx context.Context, store Store, key string) error {
+	data, err := b.marshal()
+	if err != nil {
+		return err
+	}
+	if err := store.Put(ctx, key, data); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return nil
+}
+
+func (b *Baseline) marshal() ([]byte, error) {
+	b.sort()
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal baseline: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Add accepts findings reported for file, whose source is code.
+func (b *Baseline) Add(file, code string, findings []CodeFinding) {
+	known := b.fingerprints()
+	for _, f := range findings {
+		fp := FindingFingerprint(file, code, f)
+		if known[fp] {
+			continue
+		}
+		known[fp] = true
+		b.Findings = append(b.Findings, BaselineEntry{
+			File:        file,
+			Rule:        f.Type,
+			Line:        f.Line,
+			Fingerprint: fp,
+		})
+	}
+}
+
+// Update replaces the entries for file with the current findings, dropping
+// entries that no longer occur and keeping notes on entries that still do.
+func (b *Baseline) Update(file, code string, findings []CodeFinding) {
+	notes := make(map[string]string)
+	kept := b.Findings[:0]
+	for _, e := range b.Findings {
+		if e.File == file {
+			notes[e.Fingerprint] = e.Note
+			continue
+		}
+		kept = append(kept, e)
+	}
+	b.Findings = kept
+
+	b.Add(file, code, findings)
+	for i := range b.Findings {
+		e := &b.Findings[i]
+		if e.File == file && e.Note == "" {
+			e.Note = notes[e.Fingerprint]
+		}
+	}
+}
+
+// Filter splits findings for file into those not in the baseline and those
+// already accepted.
+func (b *Baseline) Filter(file, code string, findings []CodeFinding) (fresh, known []CodeFinding) {
+	fps := b.fingerprints()
+	for _, f := range findings {
+		if fps[FindingFingerprint(file, code, f)] {
+			known = append(known, f)
+		} else {
+			fresh = append(fresh, f)
+		}
+	}
+	return fresh, known
+}
+
+func (b *Baseline) fingerprints() map[string]bool {
+	fps := make(map[string]bool, len(b.Findings))
+	for _, e := range b.Findings {
+		fps[e.Fingerprint] = true
+	}
+	return fps
+}
+
+func (b *Baseline) sort() {
+	sort.SliceStable(b.Findings, func(i, j int) bool {
+		x, y := b.Findings[i], b.Findings[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		if x.Line != y.Line {
+			return x.Line < y.Line
+		}
+		return x.Fingerprint < y.Fingerprint
+	})
+}
+
+// FindingFingerprint returns a stable identifier for a finding in file.
+// When the finding has a line number, the trimmed text of that source line
+// is hashed instead of the number itself.
+func FindingFingerprint(file, code string, f CodeFinding) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", file, f.Type, f.Pattern)
+
+	if line := sourceLine(code, f.Line); line != "" {
+		h.Write([]byte(line))
+	} else {
+		h.Write([]byte(f.Description))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// sourceLine returns the whitespace-normalized text of the 1-based line n.
+func sourceLine(code string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	lines := strings.Split(code, "\n")
+	if n > len(lines) {
+		return ""
+	}
+	return strings.Join(strings.Fields(lines[n-1]), " ")
+}
@@ -0,0 +1,113 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestBaselineFilter(t *testing.T) {
+	code := "import os\nos.system(cmd)\neval(x)\n"
+	findings := []CodeFinding{
+		{Severity: SeverityCritical, Type: "os_system", Line: 2},
+		{Severity: SeverityCritical, Type: "eval_usage", Line: 3},
+	}
+
+	b := NewBaseline()
+	b.Add("app.py", code, findings[:1])
+
+	// Shifting the code down a line must not invalidate the accepted finding.
+	shifted := "# header\n" + code
+	moved := []CodeFinding{
+		{Severity: SeverityCritical, Type: "os_system", Line: 3},
+		{Severity: SeverityCritical, Type: "eval_usage", Line: 4},
+	}
+
+	fresh, known := b.Filter("app.py", shifted, moved)
+	if len(known) != 1 || known[0].Type != "os_system" {
+		t.Errorf("expected os_system to be known, got %+v", known)
+	}
+	if len(fresh) != 1 || fresh[0].Type != "eval_usage" {
+		t.Errorf("expected eval_usage to be new, got %+v", fresh)
+	}
+
+	if fresh, _ := b.Filter("other.py", code, findings[:1]); len(fresh) != 1 {
+		t.Error("expected baseline entries to be scoped to their file")
+	}
+}
+
+func TestBaselineUpdateKeepsNotes(t *testing.T) {
+	code := "os.system(a)\nos.system(b)\n"
+	b := NewBaseline()
+	b.Add("a.py", code, []CodeFinding{{Type: "os_system", Line: 1}, {Type: "os_system", Line: 2}})
+	b.Findings[0].Note = "accepted: trusted input"
+
+	b.Update("a.py", code, []CodeFinding{{Type: "os_system", Line: 1}})
+
+	if len(b.Findings) != 1 {
+		t.Fatalf("expected fixed finding to be dropped, got %d entries", len(b.Findings))
+	}
+	if b.Findings[0].Note != "accepted: trusted input" {
+		t.Errorf("expected note to be preserved, got %q", b.Findings[0].Note)
+	}
+}
+
+func TestBaselineSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	b := NewBaseline()
+	b.Add("a.py", "eval(x)", []CodeFinding{{Type: "eval_usage", Line: 1}})
+	if err := b.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Findings) != 1 || loaded.Findings[0].Fingerprint != b.Findings[0].Fingerprint {
+		t.Errorf("unexpected loaded baseline: %+v", loaded)
+	}
+}
+
+func TestBaselineStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	if _, err := LoadBaselineFrom(ctx, store, "baselines/main.json"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	b := NewBaseline()
+	b.Add("app.py", "eval(x)\n", []CodeFinding{{Type: "eval_usage", Line: 1}})
+	if err := b.SaveTo(ctx, store, "baselines/main.json"); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadBaselineFrom(ctx, store, "baselines/main.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Findings) != 1 || loaded.Findings[0].Rule != "eval_usage" {
+		t.Errorf("unexpected baseline: %+v", loaded)
+	}
+}
+
+func TestCodeFindings(t *testing.T) {
+	resp := &VerificationResponse{Result: map[string]interface{}{
+		"issues": []interface{}{
+			map[string]interface{}{"severity": "CRITICAL", "type": "eval_usage", "line_number": float64(3)},
+		},
+		"vulnerabilities": []interface{}{"hardcoded_secret"},
+	}}
+
+	findings := CodeFindings(resp)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+	if findings[0].Line != 3 || findings[0].Severity != SeverityCritical {
+		t.Errorf("unexpected first finding: %+v", findings[0])
+	}
+	if findings[1].Type != "hardcoded_secret" {
+		t.Errorf("unexpected second finding: %+v", findings[1])
+	}
+}
@@ -0,0 +1,116 @@
+package qwed
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Batch Chunking
+// ============================================================================
+
+// chunkedJobPrefix marks the job ID of a composite job, followed by the
+// comma-separated IDs of its chunk jobs.
+const chunkedJobPrefix = "chunked:"
+
+// verifyChunked submits items as jobs of at most opts.ChunkSize items and
+// returns them as one composite job, whose JobID works with GetBatch and
+// StreamBatchResults. An idempotency key gets the chunk index appended so
+// chunks do not share it; derived keys are computed per chunk. If a chunk
+// fails to submit, the composite of the chunks already submitted is
+// returned with the error so they can still be tracked.
+func (c *Client) verifyChunked(ctx context.Context, items []BatchItem, opts *BatchOptions, callOpts ...CallOption) (*BatchResponse, error) {
+	chunks := (len(items) + opts.ChunkSize - 1) / opts.ChunkSize
+
+	parts := make([]*BatchResponse, 0, chunks)
+	for i := 0; i < chunks; i++ {
+		chunk := items[i*opts.ChunkSize : min((i+1)*opts.ChunkSize, len(items))]
+		resp, err := c.submitBatch(ctx, chunk, opts, chunkCallOptions(callOpts, i, true)...)
+		if err != nil {
+			err = fmt.Errorf("failed to submit batch chunk %d of %d: %w", i+1, chunks, err)
+			if len(parts) == 0 {
+				return nil, err
+			}
+			return mergeBatches(parts), err
+		}
+		parts = append(parts, resp)
+	}
+
+	merged := mergeBatches(parts)
+	merged.IdempotencyKey = newCallOptions(callOpts).idempotencyKey
+	return merged, nil
+}
+
+// getChunked fetches the chunk jobs of a composite job and merges them.
+func (c *Client) getChunked(ctx context.Context, jobID string, jobs []string) (*BatchResponse, error) {
+	parts := make([]*BatchResponse, len(jobs))
+	for i, job := range jobs {
+		resp, err := c.GetBatch(ctx, job)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get batch chunk %s: %w", job, err)
+		}
+		parts[i] = resp
+	}
+	merged := mergeBatches(parts)
+	merged.JobID = jobID
+	return merged, nil
+}
+
+// chunkJobs returns the chunk job IDs of a composite job ID.
+func chunkJobs(jobID string) ([]string, bool) {
+	ids, ok := strings.CutPrefix(jobID, chunkedJobPrefix)
+	if !ok || ids == "" {
+		return nil, false
+	}
+	return strings.Split(ids, ","), true
+}
+
+// mergeBatches combines the responses of chunk jobs into a composite job:
+// items in submission order, summed summaries and an overall status.
+func mergeBatches(parts []*BatchResponse) *BatchResponse {
+	merged := &BatchResponse{}
+	ids := make([]string, len(parts))
+	statuses := make([]string, len(parts))
+	for i, part := range parts {
+		ids[i] = part.JobID
+		statuses[i] = part.Status
+		merged.Items = append(merged.Items, part.Items...)
+		merged.SchemaVersion = max(merged.SchemaVersion, part.SchemaVersion)
+		if part.Summary != nil {
+			if merged.Summary == nil {
+				merged.Summary = &BatchSummary{}
+			}
+			merged.Summary.Total += part.Summary.Total
+			merged.Summary.Verified += part.Summary.Verified
+			merged.Summary.Failed += part.Summary.Failed
+		}
+	}
+	if merged.Summary != nil && merged.Summary.Total > 0 {
+		merged.Summary.SuccessRate = float64(merged.Summary.Verified) / float64(merged.Summary.Total)
+	}
+	merged.JobID = chunkedJobPrefix + strings.Join(ids, ",")
+	merged.Status = mergeBatchStatus(statuses)
+	return merged
+}
+
+// mergeBatchStatus is the status of a composite job: pending until a chunk
+// starts, processing until every chunk finishes, then completed or failed
+// if every chunk was, and partial otherwise.
+func mergeBatchStatus(statuses []string) string {
+	counts := make(map[string]int)
+	for _, s := range statuses {
+		counts[s]++
+	}
+	switch {
+	case counts[BatchPending] == len(statuses):
+		return BatchPending
+	case counts[BatchPending]+counts[BatchProcessing] > 0:
+		return BatchProcessing
+	case counts[BatchCompleted] == len(statuses):
+		return BatchCompleted
+	case counts[BatchFailed] == len(statuses):
+		return BatchFailed
+	}
+	return BatchPartial
+}
@@ -0,0 +1,217 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// chunkServer runs batch jobs that complete on submission, numbering jobs
+// in submission order and reporting each item's query as its result ID.
+// The submission after failAfter jobs fails.
+func chunkServer(t *testing.T, failAfter int) (*http.ServeMux, *[]map[string]interface{}) {
+	var (
+		mu     sync.Mutex
+		jobs   = map[string][]BatchItem{}
+		bodies []map[string]interface{}
+	)
+	result := func(item BatchItem) BatchResult {
+		return BatchResult{ID: item.Query, Status: StatusVerified, Verified: item.Query != "q3"}
+	}
+	summary := func(items []BatchItem) *BatchSummary {
+		s := &BatchSummary{Total: len(items)}
+		for _, item := range items {
+			if result(item).Verified {
+				s.Verified++
+			} else {
+				s.Failed++
+			}
+		}
+		return s
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/verify/batch", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Items []BatchItem `json:"items"`
+		}
+		var body map[string]interface{}
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &req)
+		json.Unmarshal(data, &body)
+		if key := r.Header.Get("Idempotency-Key"); key != "" {
+			body["key"] = key
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		bodies = append(bodies, body)
+		if len(jobs) == failAfter {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		id := fmt.Sprintf("job-%d", len(jobs))
+		jobs[id] = req.Items
+		resp := BatchResponse{JobID: id, Status: BatchCompleted, Summary: summary(req.Items)}
+		for _, item := range req.Items {
+			resp.Items = append(resp.Items, result(item))
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	mux.HandleFunc("/verify/batch/", func(w http.ResponseWriter, r *http.Request) {
+		id, results := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/verify/batch/"), "/results")
+		mu.Lock()
+		items, ok := jobs[id]
+		mu.Unlock()
+		if !ok {
+			t.Errorf("unknown job %s", id)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		resp := BatchResponse{JobID: id, Status: BatchCompleted, Summary: summary(items)}
+		if id == "job-1" {
+			resp.Status = BatchPartial
+		}
+		if results {
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			items = items[min(offset, len(items)):min(offset+limit, len(items))]
+			resp.Summary = nil
+		}
+		for _, item := range items {
+			resp.Items = append(resp.Items, result(item))
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	return mux, &bodies
+}
+
+func chunkItems(n int) []BatchItem {
+	items := make([]BatchItem, n)
+	for i := range items {
+		items[i] = BatchItem{Query: fmt.Sprintf("q%d", i)}
+	}
+	return items
+}
+
+func TestVerifyBatchChunked(t *testing.T) {
+	mux, bodies := chunkServer(t, -1)
+	server := mockServer(mux.ServeHTTP)
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyBatch(context.Background(), chunkItems(25), &BatchOptions{ChunkSize: 10}, WithIdempotencyKey("k"))
+	if err != nil {
+		t.Fatalf("VerifyBatch failed: %v", err)
+	}
+
+	if len(*bodies) != 3 {
+		t.Fatalf("submitted %d jobs, want 3", len(*bodies))
+	}
+	for i, body := range *bodies {
+		if n := len(body["items"].([]interface{})); n != []int{10, 10, 5}[i] {
+			t.Errorf("chunk %d has %d items", i, n)
+		}
+		if key := fmt.Sprintf("k-%d", i); body["key"] != key {
+			t.Errorf("chunk %d key = %v, want %s", i, body["key"], key)
+		}
+		if _, ok := body["options"].(map[string]interface{})["ChunkSize"]; ok {
+			t.Error("chunk size sent to the server")
+		}
+	}
+
+	if resp.JobID != "chunked:job-0,job-1,job-2" || resp.Status != BatchCompleted || resp.IdempotencyKey != "k" {
+		t.Errorf("got job %s, status %s, key %s", resp.JobID, resp.Status, resp.IdempotencyKey)
+	}
+	if len(resp.Items) != 25 || resp.Items[24].ID != "q24" {
+		t.Fatalf("got %d items", len(resp.Items))
+	}
+	want := BatchSummary{Total: 25, Verified: 24, Failed: 1, SuccessRate: 24.0 / 25}
+	if *resp.Summary != want {
+		t.Errorf("summary = %+v, want %+v", *resp.Summary, want)
+	}
+
+	got, err := client.GetBatch(context.Background(), resp.JobID)
+	if err != nil {
+		t.Fatalf("GetBatch failed: %v", err)
+	}
+	if got.JobID != resp.JobID || got.Status != BatchPartial || len(got.Items) != 25 || *got.Summary != want {
+		t.Errorf("GetBatch = %s %s, %d items, %+v", got.JobID, got.Status, len(got.Items), *got.Summary)
+	}
+
+	n := 0
+	for r := range client.StreamBatchResults(context.Background(), resp.JobID, &BatchStreamOptions{PageSize: 4, PollInterval: time.Millisecond}) {
+		if r.Err != nil {
+			t.Fatalf("result %d: %v", n, r.Err)
+		}
+		if r.Index != n || r.Result.ID != fmt.Sprintf("q%d", n) {
+			t.Fatalf("result %d: got index %d, ID %s", n, r.Index, r.Result.ID)
+		}
+		n++
+	}
+	if n != 25 {
+		t.Errorf("streamed %d results, want 25", n)
+	}
+}
+
+func TestVerifyBatchUnchunked(t *testing.T) {
+	mux, bodies := chunkServer(t, -1)
+	server := mockServer(mux.ServeHTTP)
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyBatch(context.Background(), chunkItems(10), &BatchOptions{ChunkSize: 10})
+	if err != nil {
+		t.Fatalf("VerifyBatch failed: %v", err)
+	}
+	if len(*bodies) != 1 || resp.JobID != "job-0" {
+		t.Errorf("submitted %d jobs as %s, want one plain job", len(*bodies), resp.JobID)
+	}
+}
+
+func TestVerifyBatchChunkFailure(t *testing.T) {
+	mux, _ := chunkServer(t, 2)
+	server := mockServer(mux.ServeHTTP)
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyBatch(context.Background(), chunkItems(25), &BatchOptions{ChunkSize: 10})
+	if err == nil || !strings.Contains(err.Error(), "chunk 3 of 3") {
+		t.Fatalf("expected chunk error, got %v", err)
+	}
+	var apiErr *QWEDError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("error does not wrap the API error: %v", err)
+	}
+	if resp == nil || resp.JobID != "chunked:job-0,job-1" || len(resp.Items) != 20 {
+		t.Errorf("expected the submitted chunks, got %+v", resp)
+	}
+}
+
+func TestMergeBatchStatus(t *testing.T) {
+	tests := []struct {
+		statuses []string
+		want     string
+	}{
+		{[]string{BatchPending, BatchPending}, BatchPending},
+		{[]string{BatchCompleted, BatchPending}, BatchProcessing},
+		{[]string{BatchProcessing, BatchCompleted}, BatchProcessing},
+		{[]string{BatchCompleted, BatchCompleted}, BatchCompleted},
+		{[]string{BatchFailed, BatchFailed}, BatchFailed},
+		{[]string{BatchCompleted, BatchFailed}, BatchPartial},
+		{[]string{BatchPartial, BatchCompleted}, BatchPartial},
+	}
+	for _, tt := range tests {
+		if got := mergeBatchStatus(tt.statuses); got != tt.want {
+			t.Errorf("mergeBatchStatus(%v) = %s, want %s", tt.statuses, got, tt.want)
+		}
+	}
+}
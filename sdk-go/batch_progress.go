@@ -0,0 +1,92 @@
+package qwed
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ============================================================================
+// Batch Progress
+// ============================================================================
+
+// BatchProgress is the progress of a batch job, reported to
+// BatchOptions.OnProgress.
+type BatchProgress struct {
+	JobID     string
+	Status    string
+	Total     int
+	Completed int // items finished, verified or not
+	Failed    int // finished items that were not verified
+	Pending   int
+	Elapsed   time.Duration
+	// ETA estimates the time until the job finishes from its rate so far.
+	// It is zero until an item has finished.
+	ETA time.Duration
+}
+
+// waitBatch polls a submitted job until it finishes, reporting its
+// progress to opts.OnProgress, and returns the finished job. Transient
+// polling failures are retried like StreamBatchResults does.
+func (c *Client) waitBatch(ctx context.Context, job *BatchResponse, total int, opts *BatchOptions) (*BatchResponse, error) {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	start, key, failures := time.Now(), job.IdempotencyKey, 0
+	for {
+		opts.OnProgress(batchProgress(job, total, time.Since(start)))
+		if job.Status != BatchPending && job.Status != BatchProcessing {
+			job.IdempotencyKey = key
+			return job, nil
+		}
+
+		for {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return job, ctx.Err()
+			}
+			next, err := c.GetBatch(ctx, job.JobID)
+			if err == nil {
+				job, failures = next, 0
+				break
+			}
+			if ctx.Err() != nil {
+				return job, ctx.Err()
+			}
+			if failures++; failures >= batchStreamRetries || !transient(ctx, err) {
+				return job, fmt.Errorf("failed to poll batch job %s: %w", job.JobID, err)
+			}
+			c.log(ctx, slog.LevelInfo, "qwed retrying request", slog.String("job_id", job.JobID), slog.Int("attempt", failures+1), slog.Any("error", err))
+		}
+	}
+}
+
+// batchProgress computes the progress of job, which has total items, after
+// elapsed. Counts come from the job's summary when the server reports one,
+// and from its finished items otherwise.
+func batchProgress(job *BatchResponse, total int, elapsed time.Duration) BatchProgress {
+	p := BatchProgress{JobID: job.JobID, Status: job.Status, Total: total, Elapsed: elapsed}
+	if job.Summary != nil {
+		p.Completed = job.Summary.Verified + job.Summary.Failed
+		p.Failed = job.Summary.Failed
+	} else {
+		p.Completed = len(job.Items)
+		for _, item := range job.Items {
+			if !item.Verified {
+				p.Failed++
+			}
+		}
+	}
+	p.Pending = max(total-p.Completed, 0)
+	if job.Status != BatchPending && job.Status != BatchProcessing {
+		p.Pending = 0
+	}
+	if p.Completed > 0 && p.Pending > 0 {
+		p.ETA = elapsed / time.Duration(p.Completed) * time.Duration(p.Pending)
+	}
+	return p
+}
@@ -0,0 +1,91 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVerifyBatchProgress(t *testing.T) {
+	var polls int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "POST" {
+			var req map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&req)
+			if opts := req["options"].(map[string]interface{}); len(opts) != 1 || opts["max_parallel"] != 2.0 {
+				t.Errorf("unexpected options sent: %v", opts)
+			}
+			json.NewEncoder(w).Encode(BatchResponse{JobID: "job-1", Status: BatchPending})
+			return
+		}
+
+		// Two items finish per poll; the second one of the job fails.
+		n := int(atomic.AddInt32(&polls, 1))
+		done := min(2*n, 4)
+		resp := BatchResponse{JobID: "job-1", Status: BatchProcessing, Summary: &BatchSummary{Total: 4, Verified: done - 1, Failed: 1}}
+		if done == 4 {
+			resp.Status = BatchPartial
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	defer server.Close()
+
+	var progress []BatchProgress
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyBatch(context.Background(), make([]BatchItem, 4), &BatchOptions{
+		MaxParallel:  2,
+		PollInterval: time.Millisecond,
+		OnProgress:   func(p BatchProgress) { progress = append(progress, p) },
+	}, WithIdempotencyKey("k"))
+	if err != nil {
+		t.Fatalf("VerifyBatch failed: %v", err)
+	}
+	if resp.Status != BatchPartial || resp.IdempotencyKey != "k" {
+		t.Errorf("expected the finished job, got %+v", resp)
+	}
+
+	if len(progress) != 3 {
+		t.Fatalf("expected 3 progress reports, got %+v", progress)
+	}
+	if p := progress[0]; p.Status != BatchPending || p.Completed != 0 || p.Pending != 4 || p.ETA != 0 {
+		t.Errorf("unexpected initial progress: %+v", p)
+	}
+	if p := progress[1]; p.Completed != 2 || p.Failed != 1 || p.Pending != 2 || p.ETA <= 0 {
+		t.Errorf("unexpected progress: %+v", p)
+	}
+	if p := progress[2]; p.Status != BatchPartial || p.Completed != 4 || p.Pending != 0 || p.ETA != 0 || p.JobID != "job-1" || p.Total != 4 {
+		t.Errorf("unexpected final progress: %+v", p)
+	}
+}
+
+func TestVerifyBatchProgressCancelled(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(BatchResponse{JobID: "job-1", Status: BatchProcessing})
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyBatch(ctx, make([]BatchItem, 4), &BatchOptions{
+		PollInterval: time.Millisecond,
+		OnProgress:   func(BatchProgress) { cancel() },
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	if resp == nil || resp.JobID != "job-1" {
+		t.Errorf("expected the job to be returned, got %+v", resp)
+	}
+}
+
+func TestBatchProgressFromItems(t *testing.T) {
+	job := &BatchResponse{Status: BatchProcessing, Items: []BatchResult{{Verified: true}, {Verified: false}}}
+	p := batchProgress(job, 6, 4*time.Second)
+	if p.Completed != 2 || p.Failed != 1 || p.Pending != 4 || p.ETA != 8*time.Second {
+		t.Errorf("unexpected progress: %+v", p)
+	}
+}
@@ -0,0 +1,147 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ============================================================================
+// Batch Retries
+// ============================================================================
+
+// ErrNoFailedItems is returned by RetryBatchFailures when no item of the job
+// needs retrying.
+var ErrNoFailedItems = errors.New("qwed: batch job has no failed items")
+
+// RetryOptions configures RetryBatchFailures.
+type RetryOptions struct {
+	// Retry selects the items to resubmit. Defaults to FailedItem.
+	Retry func(BatchResult) bool
+	// Batch configures the new job.
+	Batch *BatchOptions
+}
+
+// FailedItem reports whether a batch item failed to run, because of an
+// engine error or timeout, rather than being refuted.
+func FailedItem(r BatchResult) bool {
+	return r.Error != nil || r.Status == StatusError || r.Status == StatusTimeout
+}
+
+// RetryBatchFailures resubmits the failed items of a finished batch job as
+// a new job, so a large job does not need a full re-run after transient
+// engine failures. The server resubmits the original inputs of the items;
+// the new job keeps their IDs and reports the original job in RetryOf.
+// When several jobs of a chunked batch have failures, their retry jobs form
+// a new composite job.
+//
+// It returns ErrNoFailedItems if nothing needs retrying, and
+// ErrInvalidRequest if the job is still pending or processing.
+func (c *Client) RetryBatchFailures(ctx context.Context, jobID string, opts *RetryOptions, callOpts ...CallOption) (*BatchResponse, error) {
+	var o RetryOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Retry == nil {
+		o.Retry = FailedItem
+	}
+
+	jobs, chunked := chunkJobs(jobID)
+	if !chunked {
+		jobs = []string{jobID}
+	}
+
+	var parts []*BatchResponse
+	for i, job := range jobs {
+		resp, err := c.retryJob(ctx, job, o, chunkCallOptions(callOpts, i, chunked)...)
+		if errors.Is(err, ErrNoFailedItems) {
+			continue
+		}
+		if err != nil {
+			if len(parts) == 0 {
+				return nil, err
+			}
+			return mergeRetries(parts, jobID), err
+		}
+		parts = append(parts, resp)
+	}
+
+	switch len(parts) {
+	case 0:
+		return nil, ErrNoFailedItems
+	case 1:
+		return parts[0], nil
+	}
+	return mergeRetries(parts, jobID), nil
+}
+
+// retryJob resubmits the items of one finished job selected by o.Retry.
+func (c *Client) retryJob(ctx context.Context, jobID string, o RetryOptions, callOpts ...CallOption) (*BatchResponse, error) {
+	job, err := c.GetBatch(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get batch job %s: %w", jobID, err)
+	}
+	if job.Status == BatchPending || job.Status == BatchProcessing {
+		return nil, invalidRequest("batch job %s is still %s", jobID, job.Status)
+	}
+
+	var ids []string
+	for _, item := range job.Items {
+		if o.Retry(item) {
+			ids = append(ids, item.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, ErrNoFailedItems
+	}
+
+	req := map[string]interface{}{
+		"item_ids": ids,
+		"options":  o.Batch,
+	}
+	call := newCallOptions(callOpts)
+	ctx = call.apply(ctx, req)
+	key := call.idempotencyKey
+	if key == "" && c.idempotency {
+		key = batchIdempotencyKey([]interface{}{jobID, req})
+		ctx = context.WithValue(ctx, idempotencyKeyKey{}, key)
+	}
+
+	ctx, end := c.instrument(ctx, "RetryBatchFailures", "batch")
+	start := time.Now()
+	var resp BatchResponse
+	err = c.request(ctx, "POST", "/verify/batch/"+url.PathEscape(jobID)+"/retry", req, &resp)
+	end(nil, err)
+	if c.metrics != nil {
+		c.metrics.RecordBatch(len(ids), time.Since(start), err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry batch job %s: %w", jobID, err)
+	}
+	if resp.RetryOf == "" {
+		resp.RetryOf = jobID
+	}
+	resp.IdempotencyKey = key
+	return &resp, nil
+}
+
+// mergeRetries combines the retry jobs of a chunked batch into a composite
+// job linked to the original composite job.
+func mergeRetries(parts []*BatchResponse, jobID string) *BatchResponse {
+	merged := mergeBatches(parts)
+	merged.RetryOf = jobID
+	return merged
+}
+
+// chunkCallOptions returns the call options for chunk i of a chunked
+// request: an idempotency key gets the chunk index appended so chunks do
+// not share it.
+func chunkCallOptions(callOpts []CallOption, i int, chunked bool) []CallOption {
+	baseKey := newCallOptions(callOpts).idempotencyKey
+	if !chunked || baseKey == "" {
+		return callOpts
+	}
+	return append(callOpts[:len(callOpts):len(callOpts)], WithIdempotencyKey(fmt.Sprintf("%s-%d", baseKey, i)))
+}
@@ -0,0 +1,136 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// retryServer serves finished jobs whose items with IDs in failing errored,
+// recording the retry requests it receives by job.
+func retryServer(t *testing.T, status string, failing map[string][]string) (*http.ServeMux, map[string][]string) {
+	var mu sync.Mutex
+	retried := map[string][]string{}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/verify/batch/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/verify/batch/")
+		if id, ok := strings.CutSuffix(path, "/retry"); ok {
+			var req struct {
+				ItemIDs []string      `json:"item_ids"`
+				Options *BatchOptions `json:"options"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			mu.Lock()
+			retried[id] = req.ItemIDs
+			mu.Unlock()
+
+			resp := BatchResponse{JobID: "retry-" + id, Status: BatchPending, RetryOf: id}
+			for _, item := range req.ItemIDs {
+				resp.Items = append(resp.Items, BatchResult{ID: item})
+			}
+			json.NewEncoder(w).Encode(resp)
+			return
+		}
+
+		resp := BatchResponse{JobID: path, Status: status}
+		for i := 0; i < 4; i++ {
+			id := fmt.Sprintf("%s-%d", path, i)
+			item := BatchResult{ID: id, Status: StatusVerified, Verified: true}
+			for _, f := range failing[path] {
+				if f == id {
+					item = BatchResult{ID: id, Status: StatusError, Error: &ErrorInfo{Code: "ENGINE_ERROR", Message: "solver crashed"}}
+				}
+			}
+			if i == 3 {
+				item = BatchResult{ID: id, Status: StatusFailed}
+			}
+			resp.Items = append(resp.Items, item)
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	return mux, retried
+}
+
+func TestRetryBatchFailures(t *testing.T) {
+	mux, retried := retryServer(t, BatchPartial, map[string][]string{"job-1": {"job-1-0", "job-1-2"}})
+	server := mockServer(mux.ServeHTTP)
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithIdempotencyKeys())
+	resp, err := client.RetryBatchFailures(context.Background(), "job-1", nil)
+	if err != nil {
+		t.Fatalf("RetryBatchFailures failed: %v", err)
+	}
+	if got := strings.Join(retried["job-1"], ","); got != "job-1-0,job-1-2" {
+		t.Errorf("retried %s, want only the errored items", got)
+	}
+	if resp.JobID != "retry-job-1" || resp.RetryOf != "job-1" || len(resp.Items) != 2 {
+		t.Errorf("unexpected retry job: %+v", resp)
+	}
+	if !strings.HasPrefix(resp.IdempotencyKey, "batch-") {
+		t.Errorf("expected a derived idempotency key, got %q", resp.IdempotencyKey)
+	}
+
+	// A custom selector also retries refuted items.
+	resp, err = client.RetryBatchFailures(context.Background(), "job-1", &RetryOptions{
+		Retry: func(r BatchResult) bool { return !r.Verified },
+	})
+	if err != nil {
+		t.Fatalf("RetryBatchFailures failed: %v", err)
+	}
+	if got := strings.Join(retried["job-1"], ","); got != "job-1-0,job-1-2,job-1-3" {
+		t.Errorf("retried %s with custom selector", got)
+	}
+}
+
+func TestRetryBatchFailuresChunked(t *testing.T) {
+	mux, retried := retryServer(t, BatchPartial, map[string][]string{"a": {"a-1"}, "c": {"c-0"}})
+	server := mockServer(mux.ServeHTTP)
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.RetryBatchFailures(context.Background(), "chunked:a,b,c", nil)
+	if err != nil {
+		t.Fatalf("RetryBatchFailures failed: %v", err)
+	}
+	if len(retried) != 2 || retried["a"][0] != "a-1" || retried["c"][0] != "c-0" {
+		t.Errorf("unexpected retries: %v", retried)
+	}
+	if resp.JobID != "chunked:retry-a,retry-c" || resp.RetryOf != "chunked:a,b,c" || len(resp.Items) != 2 {
+		t.Errorf("unexpected composite retry job: %+v", resp)
+	}
+}
+
+func TestRetryBatchFailuresNothingToRetry(t *testing.T) {
+	mux, retried := retryServer(t, BatchCompleted, nil)
+	server := mockServer(mux.ServeHTTP)
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	if _, err := client.RetryBatchFailures(context.Background(), "job-1", nil); !errors.Is(err, ErrNoFailedItems) {
+		t.Errorf("expected ErrNoFailedItems, got %v", err)
+	}
+	if len(retried) != 0 {
+		t.Errorf("expected no retry job, got %v", retried)
+	}
+}
+
+func TestRetryBatchFailuresRunningJob(t *testing.T) {
+	mux, retried := retryServer(t, BatchProcessing, map[string][]string{"job-1": {"job-1-0"}})
+	server := mockServer(mux.ServeHTTP)
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	if _, err := client.RetryBatchFailures(context.Background(), "job-1", nil); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for a running job, got %v", err)
+	}
+	if len(retried) != 0 {
+		t.Errorf("expected no retry job, got %v", retried)
+	}
+}
@@ -0,0 +1,133 @@
+package qwed
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// Batch Result Streaming
+// ============================================================================
+
+// BatchItemResult is one result delivered by StreamBatchResults.
+type BatchItemResult struct {
+	Index  int // position of the item in the batch
+	Result BatchResult
+	Err    error // set on the last result if streaming failed
+}
+
+// BatchStreamOptions configures StreamBatchResults.
+type BatchStreamOptions struct {
+	// PageSize is the number of results fetched per request. Defaults to
+	// 100.
+	PageSize int
+	// PollInterval is how long to wait for more results while the job is
+	// still running. Defaults to 1 second.
+	PollInterval time.Duration
+}
+
+// batchStreamRetries is the number of consecutive transient failures
+// StreamBatchResults tolerates.
+const batchStreamRetries = 3
+
+// StreamBatchResults delivers the results of a batch job as they complete,
+// fetching them page by page instead of loading them all with GetBatch.
+// Results arrive in item order. The channel is closed once every result has
+// been delivered and the job has finished, or after a result carrying the
+// error that ended the stream, or when ctx is done; callers must drain it
+// or cancel ctx. The jobs of a chunked batch are streamed one after
+// another, with results numbered across the whole batch.
+func (c *Client) StreamBatchResults(ctx context.Context, jobID string, opts *BatchStreamOptions) <-chan BatchItemResult {
+	var o BatchStreamOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.PageSize <= 0 {
+		o.PageSize = 100
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = time.Second
+	}
+
+	results := make(chan BatchItemResult, o.PageSize)
+	go func() {
+		defer close(results)
+		send := func(r BatchItemResult) bool {
+			select {
+			case results <- r:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		jobs, ok := chunkJobs(jobID)
+		if !ok {
+			jobs = []string{jobID}
+		}
+		index := 0
+		for _, job := range jobs {
+			if !c.streamJob(ctx, job, o, &index, send) {
+				return
+			}
+		}
+	}()
+	return results
+}
+
+// streamJob delivers the results of one batch job, numbering them from
+// *index. It reports whether the stream should go on to the next job.
+func (c *Client) streamJob(ctx context.Context, jobID string, o BatchStreamOptions, index *int, send func(BatchItemResult) bool) bool {
+	offset, failures := 0, 0
+	for {
+		page, err := c.batchResultsPage(ctx, jobID, offset, o.PageSize)
+		if err != nil {
+			if ctx.Err() != nil {
+				return false
+			}
+			if failures++; failures >= batchStreamRetries || !transient(ctx, err) {
+				send(BatchItemResult{Index: *index, Err: fmt.Errorf("failed to fetch batch results at offset %d: %w", offset, err)})
+				return false
+			}
+			c.log(ctx, slog.LevelInfo, "qwed retrying request", slog.String("job_id", jobID), slog.Int("attempt", failures+1), slog.Any("error", err))
+		} else {
+			failures = 0
+			for _, item := range page.Items {
+				if !send(BatchItemResult{Index: *index, Result: item}) {
+					return false
+				}
+				offset++
+				*index++
+			}
+			if len(page.Items) == o.PageSize {
+				continue
+			}
+			if page.Status != BatchPending && page.Status != BatchProcessing {
+				return true
+			}
+		}
+
+		select {
+		case <-time.After(o.PollInterval):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// batchResultsPage fetches up to limit results of a batch job from offset.
+func (c *Client) batchResultsPage(ctx context.Context, jobID string, offset, limit int) (*BatchResponse, error) {
+	query := url.Values{
+		"offset": {strconv.Itoa(offset)},
+		"limit":  {strconv.Itoa(limit)},
+	}
+	var resp BatchResponse
+	if err := c.request(ctx, "GET", "/verify/batch/"+url.PathEscape(jobID)+"/results?"+query.Encode(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,107 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStreamBatchResults(t *testing.T) {
+	const total = 250
+	var mu sync.Mutex
+	available := 0
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/batch/job-1/results" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		// Results complete 70 at a time.
+		mu.Lock()
+		available = min(total, available+70)
+		end := min(available, offset+limit)
+		status := BatchProcessing
+		if available == total {
+			status = BatchCompleted
+		}
+		mu.Unlock()
+
+		resp := BatchResponse{JobID: "job-1", Status: status}
+		for i := offset; i < end; i++ {
+			resp.Items = append(resp.Items, BatchResult{ID: fmt.Sprint(i), Status: StatusVerified, Verified: true})
+		}
+		json.NewEncoder(w).Encode(resp)
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	results := client.StreamBatchResults(context.Background(), "job-1", &BatchStreamOptions{PageSize: 100, PollInterval: time.Millisecond})
+
+	n := 0
+	for r := range results {
+		if r.Err != nil {
+			t.Fatalf("result %d: %v", n, r.Err)
+		}
+		if r.Index != n || r.Result.ID != fmt.Sprint(n) {
+			t.Fatalf("result %d: got index %d, ID %s", n, r.Index, r.Result.ID)
+		}
+		n++
+	}
+	if n != total {
+		t.Errorf("streamed %d results, want %d", n, total)
+	}
+}
+
+func TestStreamBatchResultsError(t *testing.T) {
+	var calls atomic.Int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			json.NewEncoder(w).Encode(BatchResponse{Status: BatchProcessing, Items: []BatchResult{{ID: "0"}}})
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	var got []BatchItemResult
+	for r := range client.StreamBatchResults(context.Background(), "job-1", &BatchStreamOptions{PollInterval: time.Millisecond}) {
+		got = append(got, r)
+	}
+	if len(got) != 2 || got[0].Err != nil || !errors.Is(got[1].Err, ErrEngineUnavailable) || got[1].Index != 1 {
+		t.Fatalf("got %+v, want one result and then the error", got)
+	}
+	if calls.Load() != 1+batchStreamRetries {
+		t.Errorf("server saw %d calls, want transient errors retried", calls.Load())
+	}
+}
+
+func TestStreamBatchResultsCancel(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(BatchResponse{Status: BatchPending})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	ctx, cancel := context.WithCancel(context.Background())
+	results := client.StreamBatchResults(ctx, "job-1", &BatchStreamOptions{PollInterval: time.Millisecond})
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case _, ok := <-results:
+		if ok {
+			t.Error("expected no results for a pending job")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end after cancellation")
+	}
+}
@@ -0,0 +1,69 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ============================================================================
+// Latency Budget
+// ============================================================================
+
+// ErrBudgetExceeded is returned in HardFail mode when a verification does
+// not complete within the client's latency budget.
+var ErrBudgetExceeded = errors.New("qwed: latency budget exceeded")
+
+// StatusInconclusive is the status of responses the client could not
+// reach a verdict on, such as SoftFail responses when the latency budget is
+// exceeded. It is never sent by the API.
+const StatusInconclusive Status = "INCONCLUSIVE"
+
+// BudgetMode selects what happens when a verification exceeds the latency
+// budget.
+type BudgetMode int
+
+const (
+	// SoftFail returns an unverified StatusInconclusive response without an
+	// error, so the caller can proceed without blocking.
+	SoftFail BudgetMode = iota
+	// HardFail returns ErrBudgetExceeded.
+	HardFail
+)
+
+// WithLatencyBudget abandons verification calls that take longer than
+// budget, making inline verification safe on a product's critical path.
+// Calls over budget are recorded in metrics with BudgetExceeded set. The
+// budget covers the whole call, including interceptors and cache lookups.
+func WithLatencyBudget(budget time.Duration, mode BudgetMode) ClientOption {
+	return func(c *Client) {
+		c.budget = budget
+		c.budgetMode = mode
+	}
+}
+
+// IsInconclusive reports whether resp is a SoftFail response for a call
+// that exceeded the latency budget.
+func IsInconclusive(resp *VerificationResponse) bool {
+	return resp.InconclusiveReason() == ReasonBudgetExceeded
+}
+
+// invokeWithinBudget passes req through the interceptor chain, giving up
+// once the latency budget is spent.
+func (c *Client) invokeWithinBudget(ctx context.Context, req *Request) (*VerificationResponse, error) {
+	bctx, cancel := context.WithTimeoutCause(ctx, c.budget, ErrBudgetExceeded)
+	defer cancel()
+
+	resp, err := c.invoker(bctx, req)
+	if err == nil || ctx.Err() != nil || !errors.Is(context.Cause(bctx), ErrBudgetExceeded) {
+		return resp, err
+	}
+
+	if c.budgetMode == HardFail {
+		return nil, fmt.Errorf("%w (%s)", ErrBudgetExceeded, c.budget)
+	}
+	resp = inconclusive(nil, ReasonBudgetExceeded)
+	resp.Result["budget_ms"] = c.budget.Milliseconds()
+	return resp, nil
+}
@@ -0,0 +1,88 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowServer responds once release is closed.
+func slowServer(release <-chan struct{}) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	}
+}
+
+func TestLatencyBudgetSoftFail(t *testing.T) {
+	release := make(chan struct{})
+	server := mockServer(slowServer(release))
+	defer server.Close()
+	defer close(release)
+
+	metrics := NewPrometheusCollector()
+	client := NewClient("test-key", WithBaseURL(server.URL), WithLatencyBudget(20*time.Millisecond, SoftFail), WithMetrics(metrics))
+
+	start := time.Now()
+	resp, err := client.VerifyMath(context.Background(), "2+2=4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected call to return within budget, took %s", elapsed)
+	}
+	if !IsInconclusive(resp) || resp.Verified || resp.Status == StatusBlocked {
+		t.Errorf("expected inconclusive response, got %+v", resp)
+	}
+
+	var out strings.Builder
+	metrics.WriteTo(&out)
+	if !strings.Contains(out.String(), `qwed_requests_total{method="VerifyMath",engine="math",code="budget_exceeded"} 1`) {
+		t.Errorf("expected budget overrun in metrics:\n%s", out.String())
+	}
+}
+
+func TestLatencyBudgetHardFail(t *testing.T) {
+	release := make(chan struct{})
+	server := mockServer(slowServer(release))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithLatencyBudget(20*time.Millisecond, HardFail))
+	if _, err := client.VerifyLogic(context.Background(), "p OR NOT p"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("expected ErrBudgetExceeded, got %v", err)
+	}
+}
+
+func TestLatencyBudgetWithinBudget(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	server := mockServer(slowServer(release))
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithLatencyBudget(time.Second, SoftFail))
+	resp, err := client.VerifyMath(context.Background(), "2+2=4")
+	if err != nil || !resp.Verified || IsInconclusive(resp) {
+		t.Errorf("expected verified response, got %+v, %v", resp, err)
+	}
+}
+
+func TestLatencyBudgetCallerCancellation(t *testing.T) {
+	release := make(chan struct{})
+	server := mockServer(slowServer(release))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithLatencyBudget(time.Second, SoftFail))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	// The caller's own deadline is an error, not an inconclusive result.
+	if _, err := client.VerifyMath(ctx, "2+2=4"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected caller deadline error, got %v", err)
+	}
+}
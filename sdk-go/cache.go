@@ -0,0 +1,393 @@
+package qwed
+
+import (
+	"bufio"
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Cache
+// ============================================================================
+
+// Cache stores verification responses between calls. Implementations must
+// be safe for concurrent use. LRUCache is the in-memory implementation; the
+// interface is small enough to back with Redis or memcached.
+//
+// Errors from a Cache never fail a verification: a failed Get is treated as
+// a miss and a failed Set is ignored.
+type Cache interface {
+	// Get returns the response stored under key, if present.
+	Get(ctx context.Context, key string) (*VerificationResponse, bool, error)
+
+	// Set stores resp under key for ttl. A zero ttl means no expiry.
+	Set(ctx context.Context, key string, resp *VerificationResponse, ttl time.Duration) error
+}
+
+// LoadingCache is a Cache that also deduplicates concurrent misses. When
+// the client's cache implements it, Load is used instead of Get so that
+// concurrent identical verifications reach the API only once.
+type LoadingCache interface {
+	Cache
+
+	// Load returns the response stored under key. On a miss it calls load,
+	// sharing the result with concurrent callers for the same key. load
+	// stores cacheable results itself.
+	Load(ctx context.Context, key string, load func(context.Context) (*VerificationResponse, error)) (*VerificationResponse, error)
+}
+
+// WithCache enables response caching for verification calls. Responses are
+// kept for ttl; a zero ttl keeps them until the cache evicts them. Keys are
+// built with CacheKey from the engine and normalized query.
+func WithCache(cache Cache, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+// cacheGet looks key up in the client cache, treating errors as misses.
+func (c *Client) cacheGet(ctx context.Context, key string) (*VerificationResponse, bool) {
+	if c.cache == nil || key == "" {
+		return nil, false
+	}
+	resp, ok, err := c.cache.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false
+	}
+	return resp, true
+}
+
+// cacheSet stores resp in the client cache if it is worth reusing.
+func (c *Client) cacheSet(ctx context.Context, key string, resp *VerificationResponse) {
+	if c.cache == nil || key == "" || !cacheable(resp) {
+		return
+	}
+	if resp.RateLimit != nil { // stale by the time the entry is read
+		stored := *resp
+		stored.RateLimit = nil
+		resp = &stored
+	}
+	_ = c.cache.Set(ctx, key, resp, c.cacheTTL)
+}
+
+// ============================================================================
+// LRU Cache
+// ============================================================================
+
+var _ Cache = (*LRUCache)(nil)
+
+// LRUCache is an in-memory Cache that evicts the least recently used entry
+// once it holds capacity entries. It is safe for concurrent use.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // front is most recently used
+	entries  map[string]*list.Element
+
+	hits, misses, expired, evicted uint64
+}
+
+type cacheEntry struct {
+	key     string
+	resp    *VerificationResponse
+	expires time.Time
+}
+
+// NewLRUCache creates a cache holding at most capacity entries.
+// A capacity of zero or less leaves the cache unbounded.
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached response for key, if present and not expired.
+func (l *LRUCache) Get(_ context.Context, key string) (*VerificationResponse, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	elem, ok := l.entries[key]
+	if !ok {
+		l.misses++
+		return nil, false, nil
+	}
+	entry := elem.Value.(*cacheEntry)
+	if entry.expired(time.Now()) {
+		l.remove(elem)
+		l.expired++
+		l.misses++
+		return nil, false, nil
+	}
+	l.order.MoveToFront(elem)
+	l.hits++
+	return entry.resp, true, nil
+}
+
+func (e *cacheEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+// Set stores resp under key, evicting the least recently used entry if the
+// cache is full.
+func (l *LRUCache) Set(_ context.Context, key string, resp *VerificationResponse, ttl time.Duration) error {
+	entry := &cacheEntry{key: key, resp: resp}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if elem, ok := l.entries[key]; ok {
+		elem.Value = entry
+		l.order.MoveToFront(elem)
+		return nil
+	}
+	l.entries[key] = l.order.PushFront(entry)
+	if l.capacity > 0 && l.order.Len() > l.capacity {
+		l.remove(l.order.Back())
+		l.evicted++
+	}
+	return nil
+}
+
+func (l *LRUCache) remove(elem *list.Element) {
+	l.order.Remove(elem)
+	delete(l.entries, elem.Value.(*cacheEntry).key)
+}
+
+// Len returns the number of entries currently held, including expired
+// entries that have not yet been evicted.
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// ============================================================================
+// Inspection and Invalidation
+// ============================================================================
+
+// CacheStats is a point-in-time snapshot of cache usage.
+type CacheStats struct {
+	Entries int    `json:"entries"`
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+	Expired uint64 `json:"expired"`
+	Evicted uint64 `json:"evicted"`
+}
+
+// HitRate returns the fraction of lookups served from the cache.
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// Stats returns current usage counters.
+func (l *LRUCache) Stats() CacheStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return CacheStats{
+		Entries: l.order.Len(),
+		Hits:    l.hits,
+		Misses:  l.misses,
+		Expired: l.expired,
+		Evicted: l.evicted,
+	}
+}
+
+// Keys returns the live keys that start with prefix, in sorted order.
+// Keys begin with the engine name, so Keys("math") lists cached math results.
+func (l *LRUCache) Keys(prefix string) []string {
+	now := time.Now()
+
+	l.mu.Lock()
+	var keys []string
+	for key, elem := range l.entries {
+		if strings.HasPrefix(key, prefix) && !elem.Value.(*cacheEntry).expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	l.mu.Unlock()
+
+	sort.Strings(keys)
+	return keys
+}
+
+// Invalidate removes every entry for which match returns true and reports
+// how many were removed. Use it to purge results whose verdicts may have
+// changed, for example after an engine upgrade:
+//
+//	cache.Invalidate(func(key string, resp *qwed.VerificationResponse) bool {
+//	    return resp.Engine == "code"
+//	})
+func (l *LRUCache) Invalidate(match func(key string, resp *VerificationResponse) bool) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	removed := 0
+	for key, elem := range l.entries {
+		if match(key, elem.Value.(*cacheEntry).resp) {
+			l.remove(elem)
+			removed++
+		}
+	}
+	return removed
+}
+
+// Purge removes all entries.
+func (l *LRUCache) Purge() {
+	l.mu.Lock()
+	l.order.Init()
+	l.entries = make(map[string]*list.Element)
+	l.mu.Unlock()
+}
+
+// ============================================================================
+// Keys
+// ============================================================================
+
+// CacheKey builds the cache key for a verification of the given engine.
+// Whitespace in each part is normalized so trivially different inputs
+// share an entry.
+func CacheKey(engine VerificationType, parts ...string) string {
+	var b strings.Builder
+	b.WriteString(string(engine))
+	for _, part := range parts {
+		b.WriteByte(0)
+		b.WriteString(strings.Join(strings.Fields(part), " "))
+	}
+	return b.String()
+}
+
+// cacheable reports whether a response is stable enough to be reused.
+func cacheable(resp *VerificationResponse) bool {
+	switch resp.Status {
+	case StatusError, StatusTimeout:
+		return false
+	}
+	return resp.Error == nil
+}
+
+// ============================================================================
+// Cache Warming
+// ============================================================================
+
+// WarmCache pre-verifies items through the client so their results are
+// cached, running at most concurrency verifications at a time. It is
+// intended to be called at startup so the first user requests after a
+// deploy are served from cache. It does nothing if no cache is configured.
+//
+// Items that fail to verify are skipped; their errors are joined and
+// returned once all items have been processed.
+func (c *Client) WarmCache(ctx context.Context, items []BatchItem, concurrency int) error {
+	if c.cache == nil {
+		return nil
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+		sem  = make(chan struct{}, concurrency)
+	)
+
+	for _, item := range items {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+
+		wg.Add(1)
+		go func(item BatchItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, err := warmItem(ctx, c, item)
+			if err == nil && cacheable(resp) {
+				return
+			}
+			if err == nil {
+				err = fmt.Errorf("status %s", resp.Status)
+			}
+
+			mu.Lock()
+			errs = append(errs, fmt.Errorf("warm %q: %w", item.Query, err))
+			mu.Unlock()
+		}(item)
+	}
+
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// warmItem verifies a single seed item with the engine matching its type.
+func warmItem(ctx context.Context, v Verifier, item BatchItem) (*VerificationResponse, error) {
+	switch item.Type {
+	case TypeMath:
+		return v.VerifyMath(ctx, item.Query)
+	case TypeLogic:
+		return v.VerifyLogic(ctx, item.Query)
+	case TypeNaturalLanguage, "":
+		return v.Verify(ctx, item.Query)
+	}
+	return nil, fmt.Errorf("unsupported seed type %q", item.Type)
+}
+
+// ReadSeedItems parses seed items from r, one JSON-encoded BatchItem per
+// line. Blank lines and lines starting with '#' are ignored.
+func ReadSeedItems(r io.Reader) ([]BatchItem, error) {
+	var items []BatchItem
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		var item BatchItem
+		if err := json.Unmarshal([]byte(text), &item); err != nil {
+			return nil, fmt.Errorf("seed line %d: %w", line, err)
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read seed items: %w", err)
+	}
+
+	return items, nil
+}
+
+// LoadSeedFile reads seed items from the JSONL file at path.
+func LoadSeedFile(path string) ([]BatchItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open seed file: %w", err)
+	}
+	defer f.Close()
+
+	return ReadSeedItems(f)
+}
@@ -0,0 +1,49 @@
+// Package goredis adapts a go-redis client to the Client interface of the
+// cache/redis package.
+//
+// It is a separate module, so only programs importing it depend on
+// go-redis:
+//
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	cache := qwedredis.New(goredis.New(rdb), qwedredis.WithAPIKey(apiKey))
+//	client := qwed.NewClient(apiKey, qwed.WithCache(cache, time.Hour))
+//
+// New accepts any redis.Cmdable, so cluster and ring clients work too.
+package goredis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	qwedredis "github.com/QWED-AI/qwed-verification/sdk-go/cache/redis"
+)
+
+// Client is a qwedredis.Client backed by go-redis.
+type Client struct {
+	rdb redis.Cmdable
+}
+
+var _ qwedredis.Client = (*Client)(nil)
+
+// New wraps rdb, such as a *redis.Client or *redis.ClusterClient.
+func New(rdb redis.Cmdable) *Client {
+	return &Client{rdb: rdb}
+}
+
+// Get returns the value stored under key, or nil if the key does not exist.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// Set stores value under key, expiring after ttl. A zero ttl means no
+// expiry.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.rdb.Set(ctx, key, value, ttl).Err()
+}
@@ -0,0 +1,63 @@
+package goredis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+	qwedredis "github.com/QWED-AI/qwed-verification/sdk-go/cache/redis"
+)
+
+func TestClient(t *testing.T) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer rdb.Close()
+	client := New(rdb)
+	ctx := context.Background()
+
+	if value, err := client.Get(ctx, "missing"); value != nil || err != nil {
+		t.Fatalf("expected a nil miss, got %q, %v", value, err)
+	}
+	if err := client.Set(ctx, "k", []byte("v"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := client.Get(ctx, "k"); string(value) != "v" || err != nil {
+		t.Errorf("unexpected value: %q, %v", value, err)
+	}
+	if ttl := server.TTL("k"); ttl != time.Minute {
+		t.Errorf("expected a one minute TTL, got %s", ttl)
+	}
+
+	if err := client.Set(ctx, "forever", []byte("v"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := server.TTL("forever"); ttl != 0 {
+		t.Errorf("expected no TTL, got %s", ttl)
+	}
+}
+
+func TestCache(t *testing.T) {
+	server := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer rdb.Close()
+	cache := qwedredis.New(New(rdb))
+	ctx := context.Background()
+
+	want := &qwed.VerificationResponse{Status: qwed.StatusVerified, Verified: true, Engine: "math"}
+	if err := cache.Set(ctx, "k", want, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := cache.Get(ctx, "k")
+	if err != nil || !ok || got.Engine != "math" || !got.Verified {
+		t.Errorf("unexpected cached response: %+v, %v, %v", got, ok, err)
+	}
+
+	server.Close()
+	if _, _, err := cache.Get(ctx, "k"); err == nil {
+		t.Error("expected an error with Redis down")
+	}
+}
@@ -0,0 +1,177 @@
+// Package redis provides a Redis-backed qwed.Cache so verification results
+// are shared between processes.
+//
+// The package does not import a Redis driver. Pass any driver adapted to
+// the two-method Client interface; the cache/redis/goredis module adapts
+// go-redis:
+//
+//	rdb := goredis.NewClient(&goredis.Options{Addr: "localhost:6379"})
+//	cache := redis.New(qwedgoredis.New(rdb), redis.WithAPIKey(apiKey))
+//	client := qwed.NewClient(apiKey, qwed.WithCache(cache, time.Hour))
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// DefaultPrefix is prepended to every key written by the cache.
+const DefaultPrefix = "qwed:"
+
+// Client is the subset of a Redis client used by the cache.
+type Client interface {
+	// Get returns the value stored under key, or a nil slice and nil error
+	// if the key does not exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Set stores value under key, expiring after ttl. A zero ttl means no
+	// expiry.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Cache is a qwed.LoadingCache stored in Redis. It is safe for concurrent
+// use.
+type Cache struct {
+	client    Client
+	prefix    string
+	namespace string
+
+	mu       sync.Mutex
+	inflight map[string]*call
+}
+
+var _ qwed.LoadingCache = (*Cache)(nil)
+
+// call is an in-progress load shared by concurrent callers.
+type call struct {
+	done chan struct{}
+	resp *qwed.VerificationResponse
+	err  error
+}
+
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithPrefix replaces DefaultPrefix.
+func WithPrefix(prefix string) Option {
+	return func(c *Cache) {
+		c.prefix = prefix
+	}
+}
+
+// WithNamespace isolates entries under namespace, so several tenants can
+// share one Redis instance.
+func WithNamespace(namespace string) Option {
+	return func(c *Cache) {
+		c.namespace = namespace
+	}
+}
+
+// WithAPIKey namespaces entries per API key. Only a hash of the key is
+// written to Redis.
+func WithAPIKey(apiKey string) Option {
+	return func(c *Cache) {
+		sum := sha256.Sum256([]byte(apiKey))
+		c.namespace = hex.EncodeToString(sum[:8])
+	}
+}
+
+// New creates a cache backed by client.
+func New(client Client, opts ...Option) *Cache {
+	c := &Cache{
+		client:   client,
+		prefix:   DefaultPrefix,
+		inflight: make(map[string]*call),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ============================================================================
+// Cache
+// ============================================================================
+
+// Get returns the response stored under key, if present.
+func (c *Cache) Get(ctx context.Context, key string) (*qwed.VerificationResponse, bool, error) {
+	data, err := c.client.Get(ctx, c.redisKey(key))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read from redis: %w", err)
+	}
+	if data == nil {
+		return nil, false, nil
+	}
+
+	var resp qwed.VerificationResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, false, fmt.Errorf("failed to decode cached response: %w", err)
+	}
+	return &resp, true, nil
+}
+
+// Set stores resp under key for ttl.
+func (c *Cache) Set(ctx context.Context, key string, resp *qwed.VerificationResponse, ttl time.Duration) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+	if err := c.client.Set(ctx, c.redisKey(key), data, ttl); err != nil {
+		return fmt.Errorf("failed to write to redis: %w", err)
+	}
+	return nil
+}
+
+// Load returns the response stored under key or, on a miss, calls load.
+// Concurrent callers for the same key within this process wait for the
+// first caller's result instead of calling load themselves. Redis errors
+// are treated as misses.
+func (c *Cache) Load(ctx context.Context, key string, load func(context.Context) (*qwed.VerificationResponse, error)) (*qwed.VerificationResponse, error) {
+	if resp, ok, err := c.Get(ctx, key); err == nil && ok {
+		return resp, nil
+	}
+
+	c.mu.Lock()
+	if inflight, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		select {
+		case <-inflight.done:
+			return inflight.resp, inflight.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	cl := &call{done: make(chan struct{})}
+	c.inflight[key] = cl
+	c.mu.Unlock()
+
+	cl.resp, cl.err = load(ctx)
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	c.mu.Unlock()
+	close(cl.done)
+
+	return cl.resp, cl.err
+}
+
+// redisKey maps a qwed cache key to a Redis key. Cache keys can embed whole
+// source files, so they are hashed.
+func (c *Cache) redisKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	if c.namespace == "" {
+		return c.prefix + hex.EncodeToString(sum[:])
+	}
+	return c.prefix + c.namespace + ":" + hex.EncodeToString(sum[:])
+}
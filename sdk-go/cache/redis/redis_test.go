@@ -0,0 +1,119 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// memoryRedis is an in-memory Client recording TTLs.
+type memoryRedis struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	ttls map[string]time.Duration
+}
+
+func newMemoryRedis() *memoryRedis {
+	return &memoryRedis{data: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (m *memoryRedis) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.data[key], nil
+}
+
+func (m *memoryRedis) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[key] = value
+	m.ttls[key] = ttl
+	return nil
+}
+
+func TestCacheRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	rdb := newMemoryRedis()
+	cache := New(rdb)
+
+	if _, ok, err := cache.Get(ctx, "missing"); ok || err != nil {
+		t.Fatalf("expected miss, got %v, %v", ok, err)
+	}
+
+	want := &qwed.VerificationResponse{Status: qwed.StatusVerified, Verified: true, Engine: "math"}
+	if err := cache.Set(ctx, "k", want, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	got, ok, err := cache.Get(ctx, "k")
+	if err != nil || !ok || got.Engine != "math" || !got.Verified {
+		t.Errorf("unexpected cached response: %+v, %v, %v", got, ok, err)
+	}
+
+	for key, ttl := range rdb.ttls {
+		if !strings.HasPrefix(key, DefaultPrefix) || ttl != time.Minute {
+			t.Errorf("unexpected key %q with ttl %v", key, ttl)
+		}
+	}
+}
+
+func TestCacheNamespacePerAPIKey(t *testing.T) {
+	ctx := context.Background()
+	rdb := newMemoryRedis()
+	a := New(rdb, WithAPIKey("key-a"))
+	b := New(rdb, WithAPIKey("key-b"))
+
+	a.Set(ctx, "k", &qwed.VerificationResponse{Engine: "math"}, 0)
+	if _, ok, _ := b.Get(ctx, "k"); ok {
+		t.Error("expected entries to be isolated per API key")
+	}
+	for key := range rdb.data {
+		if strings.Contains(key, "key-a") {
+			t.Errorf("expected API key to be hashed, got %q", key)
+		}
+	}
+}
+
+func TestLoadDeduplicatesConcurrentVerifications(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		json.NewEncoder(w).Encode(qwed.VerificationResponse{Status: qwed.StatusVerified, Verified: true})
+	}))
+	defer server.Close()
+
+	cache := New(newMemoryRedis())
+	client := qwed.NewClient("test-key", qwed.WithBaseURL(server.URL), qwed.WithCache(cache, time.Minute))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resp, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil || !resp.Verified {
+				t.Errorf("unexpected result: %+v, %v", resp, err)
+			}
+		}()
+	}
+
+	// Give the goroutines time to join the in-flight call.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("expected 1 API call, got %d", n)
+	}
+	if _, ok, _ := cache.Get(context.Background(), qwed.CacheKey(qwed.TypeMath, "2+2=4")); !ok {
+		t.Error("expected result to be stored")
+	}
+}
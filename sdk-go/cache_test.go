@@ -0,0 +1,192 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClientCacheHit(t *testing.T) {
+	var calls int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	})
+	defer server.Close()
+
+	cache := NewLRUCache(0)
+	client := NewClient("test-key", WithBaseURL(server.URL), WithCache(cache, time.Minute))
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.VerifyMath(context.Background(), "2 +  2 = 4"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if calls != 1 {
+		t.Errorf("expected 1 API call, got %d", calls)
+	}
+	if _, ok, _ := cache.Get(context.Background(), CacheKey(TypeMath, "2 + 2 = 4")); !ok {
+		t.Error("expected normalized key to be cached")
+	}
+}
+
+func TestLRUCacheExpiry(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(0)
+	cache.Set(ctx, "k", &VerificationResponse{Verified: true}, time.Millisecond)
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok, _ := cache.Get(ctx, "k"); ok {
+		t.Error("expected entry to expire")
+	}
+}
+
+func TestLRUCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(2)
+	cache.Set(ctx, "a", &VerificationResponse{Engine: "a"}, 0)
+	cache.Set(ctx, "b", &VerificationResponse{Engine: "b"}, 0)
+	cache.Get(ctx, "a")
+	cache.Set(ctx, "c", &VerificationResponse{Engine: "c"}, 0)
+
+	if _, ok, _ := cache.Get(ctx, "b"); ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	if _, ok, _ := cache.Get(ctx, "a"); !ok {
+		t.Error("expected recently used entry to survive")
+	}
+	if stats := cache.Stats(); stats.Entries != 2 || stats.Evicted != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+// failingCache is a Cache whose backend is unavailable.
+type failingCache struct{}
+
+func (failingCache) Get(context.Context, string) (*VerificationResponse, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func (failingCache) Set(context.Context, string, *VerificationResponse, time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func TestCacheErrorsDoNotFailVerification(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithCache(failingCache{}, time.Minute))
+	if resp, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil || !resp.Verified {
+		t.Errorf("expected verification to succeed, got %+v, %v", resp, err)
+	}
+}
+
+func TestWarmCache(t *testing.T) {
+	var calls int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true, Engine: "math"})
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	cache := NewLRUCache(0)
+	client := NewClient("test-key", WithBaseURL(server.URL), WithCache(cache, 0))
+	items := []BatchItem{
+		{Query: "2 + 2 = 4", Type: TypeMath},
+		{Query: "(A AND B) implies B", Type: TypeLogic},
+		{Query: "What is 2+2?"},
+		{Query: "SELECT 1", Type: TypeSQL},
+	}
+
+	err := client.WarmCache(ctx, items, 2)
+	if err == nil || !strings.Contains(err.Error(), "unsupported seed type") {
+		t.Errorf("expected unsupported type error, got %v", err)
+	}
+
+	if cache.Len() != 3 {
+		t.Errorf("expected 3 warmed entries, got %d", cache.Len())
+	}
+	if _, err := client.VerifyMath(ctx, "2 + 2 = 4"); err != nil || calls != 3 {
+		t.Errorf("expected warmed math item to be served from cache, got %d calls, %v", calls, err)
+	}
+}
+
+func TestReadSeedItems(t *testing.T) {
+	input := `# common claims
+{"query": "2 + 2 = 4", "type": "math"}
+
+{"query": "Paris is in France"}
+`
+	items, err := ReadSeedItems(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].Type != TypeMath {
+		t.Errorf("expected math type, got %q", items[0].Type)
+	}
+
+	if _, err := ReadSeedItems(strings.NewReader("{bad")); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestCacheStatsAndKeys(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(0)
+	cache.Set(ctx, CacheKey(TypeMath, "1 + 1 = 2"), &VerificationResponse{Engine: "math"}, 0)
+	cache.Set(ctx, CacheKey(TypeCode, "python", "eval(x)"), &VerificationResponse{Engine: "code"}, 0)
+
+	cache.Get(ctx, CacheKey(TypeMath, "1 + 1 = 2"))
+	cache.Get(ctx, "missing")
+
+	stats := cache.Stats()
+	if stats.Entries != 2 || stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if stats.HitRate() != 0.5 {
+		t.Errorf("expected hit rate 0.5, got %v", stats.HitRate())
+	}
+
+	keys := cache.Keys(string(TypeMath))
+	if len(keys) != 1 || keys[0] != CacheKey(TypeMath, "1 + 1 = 2") {
+		t.Errorf("unexpected keys: %q", keys)
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	ctx := context.Background()
+	cache := NewLRUCache(0)
+	cache.Set(ctx, "a", &VerificationResponse{Engine: "code"}, 0)
+	cache.Set(ctx, "b", &VerificationResponse{Engine: "code"}, 0)
+	cache.Set(ctx, "c", &VerificationResponse{Engine: "math"}, 0)
+
+	removed := cache.Invalidate(func(key string, resp *VerificationResponse) bool {
+		return resp.Engine == "code"
+	})
+
+	if removed != 2 {
+		t.Errorf("expected 2 removed, got %d", removed)
+	}
+	if _, ok, _ := cache.Get(ctx, "c"); !ok {
+		t.Error("expected math entry to survive")
+	}
+
+	cache.Purge()
+	if cache.Len() != 0 {
+		t.Error("expected empty cache after purge")
+	}
+}
@@ -0,0 +1,120 @@
+package qwed
+
+import (
+	"context"
+	"time"
+)
+
+// ============================================================================
+// Per-Call Options
+// ============================================================================
+
+// CallOption tunes a single verification call, for example:
+//
+//	client.VerifyMath(ctx, expr, qwed.WithEngineTimeout(5*time.Second))
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	engineTimeout  time.Duration
+	idempotencyKey string
+	metadata       map[string]string
+	noCache        bool
+}
+
+// WithEngineTimeout limits the time the server's engine spends on the call;
+// an engine that runs out returns StatusTimeout. It does not bound how long
+// the client waits, which the context does.
+func WithEngineTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.engineTimeout = d
+	}
+}
+
+// WithIdempotencyKey sends key in the Idempotency-Key header, so retries of
+// the call are not verified twice. It overrides the key generated with
+// WithIdempotencyKeys.
+func WithIdempotencyKey(key string) CallOption {
+	return func(o *callOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// WithMetadata attaches key-value pairs to the request body, for example a
+// tenant or trace label to show up in server-side logs. Repeated options
+// are merged.
+func WithMetadata(m map[string]string) CallOption {
+	return func(o *callOptions) {
+		if o.metadata == nil {
+			o.metadata = make(map[string]string, len(m))
+		}
+		for k, v := range m {
+			o.metadata[k] = v
+		}
+	}
+}
+
+// WithNoCache sends the call to the API even if the response cache holds a
+// response for it. The fresh response replaces the cached one.
+func WithNoCache() CallOption {
+	return func(o *callOptions) {
+		o.noCache = true
+	}
+}
+
+func newCallOptions(opts []CallOption) callOptions {
+	var o callOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// apply adds the options to a request body built by a Verify method and
+// returns ctx carrying the idempotency key for the HTTP request.
+func (o callOptions) apply(ctx context.Context, body interface{}) context.Context {
+	timeoutMs := int(o.engineTimeout.Milliseconds())
+
+	switch b := body.(type) {
+	case *VerificationRequest:
+		if timeoutMs > 0 {
+			b.Options = withTimeoutMs(b.Options, timeoutMs)
+		}
+		if len(o.metadata) > 0 {
+			b.Metadata = o.metadata
+		}
+	case map[string]interface{}:
+		if timeoutMs > 0 {
+			switch opts := b["options"].(type) {
+			case *BatchOptions:
+				var copied BatchOptions
+				if opts != nil {
+					copied = *opts
+				}
+				copied.TimeoutMs = timeoutMs
+				b["options"] = &copied
+			default:
+				ro, _ := opts.(*RequestOptions)
+				b["options"] = withTimeoutMs(ro, timeoutMs)
+			}
+		}
+		if len(o.metadata) > 0 {
+			b["metadata"] = o.metadata
+		}
+	}
+
+	if o.idempotencyKey != "" {
+		ctx = context.WithValue(ctx, idempotencyKeyKey{}, o.idempotencyKey)
+	}
+	return ctx
+}
+
+// withTimeoutMs returns a copy of opts with TimeoutMs set. Options passed
+// by the caller are not modified.
+func withTimeoutMs(opts *RequestOptions, timeoutMs int) *RequestOptions {
+	var copied RequestOptions
+	if opts != nil {
+		copied = *opts
+	}
+	copied.TimeoutMs = timeoutMs
+	return &copied
+}
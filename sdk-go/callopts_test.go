@@ -0,0 +1,68 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCallOptions(t *testing.T) {
+	var body map[string]interface{}
+	var header http.Header
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"status":"VERIFIED","verified":true,"job_id":"job-1"}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	_, err := client.VerifyMath(ctx, "2+2=4",
+		WithEngineTimeout(5*time.Second),
+		WithIdempotencyKey("key-1"),
+		WithMetadata(map[string]string{"tenant": "acme"}),
+		WithMetadata(map[string]string{"run": "42"}))
+	if err != nil {
+		t.Fatalf("VerifyMath() error = %v", err)
+	}
+	if got := header.Get(HeaderIdempotencyKey); got != "key-1" {
+		t.Errorf("Idempotency-Key = %q, want key-1", got)
+	}
+	if opts, _ := body["options"].(map[string]interface{}); opts["timeout_ms"] != float64(5000) {
+		t.Errorf("options = %v, want timeout_ms 5000", body["options"])
+	}
+	if meta, _ := body["metadata"].(map[string]interface{}); meta["tenant"] != "acme" || meta["run"] != "42" {
+		t.Errorf("metadata = %v, want merged metadata", body["metadata"])
+	}
+
+	// Caller options are kept and not modified.
+	opts := &RequestOptions{IncludeProof: true}
+	if _, err := client.VerifyWithOptions(ctx, "claim", opts, WithEngineTimeout(time.Second)); err != nil {
+		t.Fatalf("VerifyWithOptions() error = %v", err)
+	}
+	if sent, _ := body["options"].(map[string]interface{}); sent["timeout_ms"] != float64(1000) || sent["include_proof"] != true {
+		t.Errorf("options = %v, want timeout and proof", body["options"])
+	}
+	if opts.TimeoutMs != 0 {
+		t.Error("caller's RequestOptions were modified")
+	}
+	if header.Get(HeaderIdempotencyKey) != "" {
+		t.Error("idempotency key sent without WithIdempotencyKey")
+	}
+
+	if _, err := client.VerifyBatch(ctx, []BatchItem{{Query: "2+2=4", Type: TypeMath}}, &BatchOptions{MaxParallel: 2},
+		WithEngineTimeout(2*time.Second), WithIdempotencyKey("batch-1")); err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+	if sent, _ := body["options"].(map[string]interface{}); sent["timeout_ms"] != float64(2000) || sent["max_parallel"] != float64(2) {
+		t.Errorf("batch options = %v, want timeout and max_parallel", body["options"])
+	}
+	if got := header.Get(HeaderIdempotencyKey); got != "batch-1" {
+		t.Errorf("batch Idempotency-Key = %q, want batch-1", got)
+	}
+}
@@ -0,0 +1,200 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Engine Pinning and Canaries
+// ============================================================================
+
+// CanaryVersion is the engine version that pins to an engine's canary
+// release, e.g. "code@canary".
+const CanaryVersion = "canary"
+
+// maxCanaryInflight bounds background canary calls; sampled calls beyond
+// it are not sent to the canary.
+const maxCanaryInflight = 100
+
+// enginePinPattern matches RequestOptions.EnginePin: an engine name and a
+// version, e.g. "code@3.2.1".
+var enginePinPattern = regexp.MustCompile(`^[a-z_]+@[A-Za-z0-9._-]+$`)
+
+// EnginePin returns the RequestOptions.EnginePin value pinning engine to
+// version.
+func EnginePin(engine VerificationType, version string) string {
+	return string(engine) + "@" + version
+}
+
+// CanaryResult compares the verdicts of the pinned engine version and its
+// canary release on one sampled call.
+type CanaryResult struct {
+	Request Request
+	Primary *VerificationResponse // the response returned to the caller, nil if the call failed
+	Canary  *VerificationResponse
+	Err     error // the canary call's error, if it failed
+	Diff    VerdictDiff
+	Latency time.Duration // of the canary call
+}
+
+// Disagrees reports whether the canary's verdict differs from the primary.
+func (r CanaryResult) Disagrees() bool {
+	return r.Err == nil && r.Primary != nil && r.Diff.VerdictChanged
+}
+
+// CanarySink receives canary results. It is called from a background
+// goroutine and must be safe for concurrent use.
+type CanarySink func(CanaryResult)
+
+// CanaryStats counts canary activity.
+type CanaryStats struct {
+	Sampled       int64 // calls also sent to a canary
+	Failed        int64 // canary calls that returned an error
+	Disagreements int64 // canary verdicts that differed from the primary
+}
+
+// WithCanaryFraction sends a fraction (0 to 1) of engine's verification
+// calls to the engine's canary release as well, and compares its verdict
+// with the one returned to the caller, so teams can try a new engine
+// version on live traffic while pinning the version they depend on with
+// RequestOptions.EnginePin. The canary runs in the background and never
+// changes the response; results go to sinks added with WithCanarySink and
+// are counted in CanaryStats. Call FlushCanary before exiting to wait for
+// pending canary calls.
+func WithCanaryFraction(engine VerificationType, fraction float64) ClientOption {
+	return func(c *Client) {
+		c.canarySampler().fractions[engine] = fraction
+	}
+}
+
+// WithCanarySink adds a sink receiving the result of every canary call.
+func WithCanarySink(sink CanarySink) ClientOption {
+	return func(c *Client) {
+		s := c.canarySampler()
+		s.sinks = append(s.sinks, sink)
+	}
+}
+
+// canarySampler runs sampled calls against canary releases.
+type canarySampler struct {
+	fractions map[VerificationType]float64
+	sinks     []CanarySink
+	sem       chan struct{}
+	wg        sync.WaitGroup
+
+	mu    sync.Mutex
+	stats CanaryStats
+}
+
+func (c *Client) canarySampler() *canarySampler {
+	if c.canary == nil {
+		c.canary = &canarySampler{
+			fractions: make(map[VerificationType]float64),
+			sem:       make(chan struct{}, maxCanaryInflight),
+		}
+	}
+	return c.canary
+}
+
+// canaryVerify starts the canary call for req if it is sampled, and returns
+// a function that reports the primary response once it is known.
+func (c *Client) canaryVerify(ctx context.Context, req *Request) func(*VerificationResponse) {
+	s := c.canary
+	if rand.Float64() >= s.fractions[req.Engine] {
+		return func(*VerificationResponse) {}
+	}
+	select {
+	case s.sem <- struct{}{}:
+	default:
+		return func(*VerificationResponse) {}
+	}
+
+	canaryReq := *req
+	canaryReq.Body = canaryBody(req.Engine, req.Body)
+	canaryReq.CacheKey = ""
+	primary := make(chan *VerificationResponse, 1)
+
+	// The caller's cancellation must not abort the canary call, but trace
+	// context and other values are kept.
+	ctx = context.WithoutCancel(ctx)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer func() { <-s.sem }()
+
+		start := time.Now()
+		resp, err := c.invoker(ctx, &canaryReq)
+		result := CanaryResult{Request: *req, Canary: resp, Err: err, Latency: time.Since(start)}
+		result.Primary = <-primary
+		result.Diff = DiffResponses(result.Primary, resp)
+
+		s.mu.Lock()
+		s.stats.Sampled++
+		if err != nil {
+			s.stats.Failed++
+		} else if result.Disagrees() {
+			s.stats.Disagreements++
+		}
+		s.mu.Unlock()
+
+		for _, sink := range s.sinks {
+			sink(result)
+		}
+	}()
+	return func(resp *VerificationResponse) { primary <- resp }
+}
+
+// canaryBody returns a copy of body with the engine pinned to its canary
+// release.
+func canaryBody(engine VerificationType, body interface{}) interface{} {
+	var fields map[string]interface{}
+	data, err := json.Marshal(body)
+	if err == nil {
+		err = json.Unmarshal(data, &fields)
+	}
+	if err != nil || fields == nil {
+		fields = make(map[string]interface{})
+	}
+	options, _ := fields["options"].(map[string]interface{})
+	if options == nil {
+		options = make(map[string]interface{})
+	}
+	options["engine_pin"] = EnginePin(engine, CanaryVersion)
+	fields["options"] = options
+	return fields
+}
+
+// CanaryStats returns canary activity counts. It returns zero stats if no
+// canary is configured.
+func (c *Client) CanaryStats() CanaryStats {
+	if c.canary == nil {
+		return CanaryStats{}
+	}
+	c.canary.mu.Lock()
+	defer c.canary.mu.Unlock()
+	return c.canary.stats
+}
+
+// FlushCanary waits for pending canary calls to finish, or until ctx is
+// done. It returns immediately if no canary is configured.
+func (c *Client) FlushCanary(ctx context.Context) error {
+	if c.canary == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		c.canary.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,113 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+func TestEnginePin(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Options *RequestOptions `json:"options"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Options == nil || req.Options.EnginePin != "code@3.2.1" {
+			t.Errorf("expected the engine pin, got %+v", req.Options)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "VERIFIED", "verified": true,
+			"metadata": map[string]interface{}{"engine_version": "3.2.1"},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithStrictValidation())
+	resp, err := client.VerifyCodeWithOptions(context.Background(), "print(1)", "python", &RequestOptions{EnginePin: EnginePin(TypeCode, "3.2.1")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Metadata == nil || resp.Metadata.EngineVersion != "3.2.1" {
+		t.Errorf("expected the engine version in metadata, got %+v", resp.Metadata)
+	}
+
+	_, err = client.VerifyCodeWithOptions(context.Background(), "print(1)", "python", &RequestOptions{EnginePin: "3.2.1"})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for a malformed pin, got %v", err)
+	}
+}
+
+func TestWithCanaryFraction(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Expression string                 `json:"expression"`
+			Options    map[string]interface{} `json:"options"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		// The canary release disagrees with the stable one.
+		verified := req.Options["engine_pin"] != "math@canary"
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   map[bool]string{true: "VERIFIED", false: "FAILED"}[verified],
+			"verified": verified,
+		})
+	})
+	defer server.Close()
+
+	var mu sync.Mutex
+	var results []CanaryResult
+	client := NewClient("test-key", WithBaseURL(server.URL),
+		WithCanaryFraction(TypeMath, 1),
+		WithCanarySink(func(r CanaryResult) {
+			mu.Lock()
+			results = append(results, r)
+			mu.Unlock()
+		}))
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		resp, err := client.VerifyMath(ctx, "2+2=4")
+		if err != nil || !resp.Verified {
+			t.Fatalf("expected the stable verdict to be returned, got %+v, %v", resp, err)
+		}
+	}
+	if _, err := client.VerifyLogic(ctx, "(AND a b)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.FlushCanary(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("expected only math calls to be sent to the canary, got %d", len(results))
+	}
+	if r := results[0]; !r.Disagrees() || r.Primary == nil || !r.Primary.Verified || r.Canary.Verified || r.Request.Op != "VerifyMath" {
+		t.Errorf("unexpected canary result: %+v", r)
+	}
+	if stats := client.CanaryStats(); stats.Sampled != 3 || stats.Disagreements != 3 || stats.Failed != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestCanaryNotSampled(t *testing.T) {
+	var calls int
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithCanaryFraction(TypeMath, 0))
+	if _, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil {
+		t.Fatal(err)
+	}
+	client.FlushCanary(context.Background())
+	if calls != 1 || client.CanaryStats().Sampled != 0 {
+		t.Errorf("expected no canary calls at fraction 0, got %d calls", calls)
+	}
+	if (NewClient("test-key").CanaryStats() != CanaryStats{}) {
+		t.Error("expected zero stats without a canary")
+	}
+}
@@ -0,0 +1,108 @@
+package qwed
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ============================================================================
+// Chart Verification
+// ============================================================================
+
+// ChartKind is the type of chart the engine recognised in an image.
+type ChartKind string
+
+const (
+	ChartBar  ChartKind = "bar"
+	ChartLine ChartKind = "line"
+	ChartPie  ChartKind = "pie"
+)
+
+// chartMediaTypes are the image formats the chart engine reads.
+var chartMediaTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// ChartData is the data the engine extracted from a chart image.
+type ChartData struct {
+	Kind   ChartKind     `json:"kind"`
+	Title  string        `json:"title,omitempty"`
+	Unit   string        `json:"unit,omitempty"` // value axis unit, e.g. "USD millions" or "%"
+	Series []ChartSeries `json:"series"`
+}
+
+// ChartSeries is one series of a chart; a pie chart has a single series.
+type ChartSeries struct {
+	Name   string       `json:"name,omitempty"`
+	Points []ChartPoint `json:"points"`
+}
+
+// ChartPoint is a bar, line point or pie slice.
+type ChartPoint struct {
+	Label string  `json:"label"` // category or x-axis label, e.g. "Q3"
+	Value float64 `json:"value"`
+}
+
+// ChartClaim is the verdict on one claim about a chart.
+type ChartClaim struct {
+	Claim    string `json:"claim"`
+	Verified bool   `json:"verified"`
+	Reason   string `json:"reason,omitempty"` // e.g. "Q4 (41.2) is higher than Q3 (38.5)"
+}
+
+// VerifyChart extracts the data from a bar, line or pie chart image (PNG,
+// JPEG, GIF or WebP) and checks claims made about it, such as "Q3 is the
+// highest quarter" or "revenue doubled between 2020 and 2023". The
+// response is verified when every claim holds. The verdict on each claim is
+// in Result["claims"] and the extracted data in Result["chart"]; use
+// ChartClaims and ExtractedChart to decode them.
+func (c *Client) VerifyChart(ctx context.Context, image []byte, claims []string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return c.VerifyChartWithOptions(ctx, image, claims, nil, callOpts...)
+}
+
+// VerifyChartWithOptions checks claims about a chart image with custom
+// options.
+func (c *Client) VerifyChartWithOptions(ctx context.Context, image []byte, claims []string, opts *RequestOptions, callOpts ...CallOption) (*VerificationResponse, error) {
+	req := map[string]interface{}{
+		"image":      image,
+		"media_type": http.DetectContentType(image),
+		"claims":     claims,
+	}
+
+	if opts = c.requestOptions(opts); opts != nil {
+		req["options"] = opts
+	}
+
+	key := CacheKey(TypeChart, contentHash(image), strings.Join(claims, "\x00"), optionsKey(opts))
+	return c.verify(ctx, "VerifyChart", TypeChart, key, req, callOpts...)
+}
+
+// ChartClaims extracts the verdict on each claim from a VerifyChart
+// response, in the order the claims were given.
+func ChartClaims(resp *VerificationResponse) []ChartClaim {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var claims []ChartClaim
+	decodeResult(resp.Result["claims"], &claims)
+	return claims
+}
+
+// ExtractedChart returns the data the engine read from the chart in a
+// VerifyChart response, or nil if there is none.
+func ExtractedChart(resp *VerificationResponse) *ChartData {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var chart ChartData
+	if !decodeResult(resp.Result["chart"], &chart) {
+		return nil
+	}
+	return &chart
+}
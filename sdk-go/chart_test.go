@@ -0,0 +1,84 @@
+package qwed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+// pngHeader is enough of a PNG for content type detection.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestVerifyChart(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/chart" {
+			t.Errorf("expected path /verify/chart, got %s", r.URL.Path)
+		}
+		var req struct {
+			Image     []byte   `json:"image"`
+			MediaType string   `json:"media_type"`
+			Claims    []string `json:"claims"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if !bytes.Equal(req.Image, pngHeader) || req.MediaType != "image/png" || len(req.Claims) != 2 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": false,
+			"engine":   "chart",
+			"result": map[string]interface{}{
+				"chart": map[string]interface{}{
+					"kind": "bar",
+					"unit": "USD millions",
+					"series": []map[string]interface{}{{
+						"name":   "Revenue",
+						"points": []map[string]interface{}{{"label": "Q1", "value": 30}, {"label": "Q2", "value": 34}, {"label": "Q3", "value": 38.5}, {"label": "Q4", "value": 41.2}},
+					}},
+				},
+				"claims": []map[string]interface{}{
+					{"claim": "Q3 is the highest quarter", "verified": false, "reason": "Q4 (41.2) is higher than Q3 (38.5)"},
+					{"claim": "Revenue grew every quarter", "verified": true},
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyChart(context.Background(), pngHeader, []string{"Q3 is the highest quarter", "Revenue grew every quarter"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Verified {
+		t.Error("expected a false claim to fail the chart")
+	}
+
+	claims := ChartClaims(resp)
+	if len(claims) != 2 || claims[0].Verified || claims[0].Reason == "" || !claims[1].Verified {
+		t.Errorf("unexpected claims: %+v", claims)
+	}
+	chart := ExtractedChart(resp)
+	if chart == nil || chart.Kind != ChartBar || len(chart.Series) != 1 || chart.Series[0].Points[3] != (ChartPoint{Label: "Q4", Value: 41.2}) {
+		t.Errorf("unexpected chart: %+v", chart)
+	}
+	if ExtractedChart(&VerificationResponse{}) != nil {
+		t.Error("expected no chart without a result")
+	}
+}
+
+func TestVerifyChartStrictValidation(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"), WithStrictValidation())
+	ctx := context.Background()
+
+	if _, err := client.VerifyChart(ctx, []byte("not an image"), []string{"Q3 is highest"}); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for a non-image, got %v", err)
+	}
+	if _, err := client.VerifyChart(ctx, pngHeader, nil); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest without claims, got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Circuit Breaker
+// ============================================================================
+
+// ErrCircuitOpen is returned without contacting the API while the circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("qwed: circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every request with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of trial requests through to
+	// probe whether the API has recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerSettings configures WithCircuitBreaker.
+type CircuitBreakerSettings struct {
+	// FailureThreshold is the number of consecutive failures that opens the
+	// circuit. Defaults to 5.
+	FailureThreshold int
+
+	// Cooldown is how long the circuit stays open before half-opening.
+	// Defaults to 30 seconds.
+	Cooldown time.Duration
+
+	// HalfOpenRequests is the number of trial requests allowed at once while
+	// half-open. Defaults to 1.
+	HalfOpenRequests int
+
+	// OnStateChange, if set, is called on every transition. It must not
+	// block.
+	OnStateChange func(from, to CircuitState)
+}
+
+// WithCircuitBreaker stops calling the API after repeated failures, so a
+// degraded service fails fast with ErrCircuitOpen instead of every call
+// waiting for a full timeout. Transport errors, 5xx responses and 429s count
+// as failures; other API errors mean the service is healthy. After the
+// cooldown, trial requests decide whether the circuit closes again.
+func WithCircuitBreaker(settings CircuitBreakerSettings) ClientOption {
+	return func(c *Client) {
+		c.breaker = newCircuitBreaker(settings)
+	}
+}
+
+// CircuitState returns the state of the client's circuit breaker, or
+// CircuitClosed if it has none.
+func (c *Client) CircuitState() CircuitState {
+	return c.breaker.currentState()
+}
+
+// circuitBreaker tracks consecutive failures. A nil breaker allows every
+// request.
+type circuitBreaker struct {
+	mu       sync.Mutex
+	settings CircuitBreakerSettings
+	state    CircuitState
+	failures int       // consecutive failures while closed
+	openedAt time.Time // when the circuit last opened
+	trials   int       // trial requests in flight while half-open
+	gen      uint64    // incremented on every transition
+	now      func() time.Time
+}
+
+func newCircuitBreaker(settings CircuitBreakerSettings) *circuitBreaker {
+	if settings.FailureThreshold < 1 {
+		settings.FailureThreshold = 5
+	}
+	if settings.Cooldown <= 0 {
+		settings.Cooldown = 30 * time.Second
+	}
+	if settings.HalfOpenRequests < 1 {
+		settings.HalfOpenRequests = 1
+	}
+	return &circuitBreaker{settings: settings, now: time.Now}
+}
+
+func (b *circuitBreaker) currentState() CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.settings.Cooldown {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a request may be sent, and returns the breaker
+// generation it was allowed in. Every allowed request must be followed by a
+// call to done with that generation.
+func (b *circuitBreaker) allow() (uint64, error) {
+	if b == nil {
+		return 0, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if b.now().Sub(b.openedAt) < b.settings.Cooldown {
+			return 0, ErrCircuitOpen
+		}
+		b.setState(CircuitHalfOpen)
+		fallthrough
+	case CircuitHalfOpen:
+		if b.trials >= b.settings.HalfOpenRequests {
+			return 0, ErrCircuitOpen
+		}
+		b.trials++
+	}
+	return b.gen, nil
+}
+
+// done records the outcome of a request allowed in generation gen. A
+// request cancelled by the caller says nothing about the API's health and
+// is not counted, and neither is one allowed before the last transition:
+// a call sent while closed is not a half-open trial.
+func (b *circuitBreaker) done(ctx context.Context, gen uint64, resp *http.Response, err error) {
+	if b == nil {
+		return
+	}
+	cancelled := err != nil && errors.Is(ctx.Err(), context.Canceled)
+	failed := err != nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen != b.gen {
+		return
+	}
+
+	switch b.state {
+	case CircuitClosed:
+		switch {
+		case cancelled:
+		case failed:
+			b.failures++
+			if b.failures >= b.settings.FailureThreshold {
+				b.open()
+			}
+		default:
+			b.failures = 0
+		}
+	case CircuitHalfOpen:
+		b.trials--
+		switch {
+		case cancelled:
+		case failed:
+			b.open()
+		default:
+			b.failures = 0
+			b.setState(CircuitClosed)
+		}
+	}
+}
+
+// reset closes the circuit and forgets past failures.
+func (b *circuitBreaker) reset() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.setState(CircuitClosed)
+}
+
+// open trips the circuit. The caller must hold b.mu.
+func (b *circuitBreaker) open() {
+	b.openedAt = b.now()
+	b.setState(CircuitOpen)
+}
+
+// setState transitions to state, starting a new generation with no trials
+// in flight. The caller must hold b.mu.
+func (b *circuitBreaker) setState(state CircuitState) {
+	if b.state == state {
+		return
+	}
+	from := b.state
+	b.state = state
+	b.gen++
+	b.trials = 0
+	if b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(from, state)
+	}
+}
@@ -0,0 +1,185 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	var healthy atomic.Bool
+	var calls atomic.Int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	})
+	defer server.Close()
+
+	var transitions []string
+	client := NewClient("test-key", WithBaseURL(server.URL), WithCircuitBreaker(CircuitBreakerSettings{
+		FailureThreshold: 3,
+		Cooldown:         time.Minute,
+		OnStateChange: func(from, to CircuitState) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	}))
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if _, err := client.VerifyMath(ctx, "2+2=4"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("call %d: expected server error, got %v", i, err)
+		}
+	}
+	if client.CircuitState() != CircuitOpen {
+		t.Fatalf("expected open circuit, got %s", client.CircuitState())
+	}
+
+	if _, err := client.VerifyMath(ctx, "2+2=4"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("expected open circuit to skip the server, got %d calls", calls.Load())
+	}
+
+	// After the cooldown a failed trial reopens the circuit...
+	now = now.Add(time.Minute)
+	if client.CircuitState() != CircuitHalfOpen {
+		t.Fatalf("expected half-open circuit, got %s", client.CircuitState())
+	}
+	if _, err := client.VerifyMath(ctx, "2+2=4"); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected trial to reach the server, got %v", err)
+	}
+	if client.CircuitState() != CircuitOpen {
+		t.Fatalf("expected failed trial to reopen the circuit, got %s", client.CircuitState())
+	}
+
+	// ...and a successful one closes it.
+	now = now.Add(time.Minute)
+	healthy.Store(true)
+	if _, err := client.VerifyMath(ctx, "2+2=4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.CircuitState() != CircuitClosed {
+		t.Fatalf("expected closed circuit, got %s", client.CircuitState())
+	}
+
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(transitions) != len(want) {
+		t.Fatalf("expected transitions %v, got %v", want, transitions)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Errorf("transition %d: expected %s, got %s", i, want[i], transitions[i])
+		}
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]string{"code": "QWED-001", "message": "bad request"},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 1}))
+	for i := 0; i < 3; i++ {
+		if _, err := client.VerifyMath(context.Background(), "2+2=4"); errors.Is(err, ErrCircuitOpen) {
+			t.Fatal("expected 4xx responses not to open the circuit")
+		}
+	}
+}
+
+func TestCircuitBreakerHalfOpenLimit(t *testing.T) {
+	b := newCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 1, Cooldown: time.Second})
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	gen, err := b.allow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.done(ctx, gen, nil, errors.New("connection refused"))
+
+	now = now.Add(time.Second)
+	trial, err := b.allow()
+	if err != nil {
+		t.Fatalf("expected a trial request, got %v", err)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected second trial to be rejected, got %v", err)
+	}
+
+	// A trial cancelled by the caller frees its slot without a verdict.
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	b.done(cancelled, trial, nil, context.Canceled)
+	if b.currentState() != CircuitHalfOpen {
+		t.Fatalf("expected half-open circuit, got %s", b.currentState())
+	}
+	if _, err := b.allow(); err != nil {
+		t.Errorf("expected the freed trial slot, got %v", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresStaleCompletions(t *testing.T) {
+	b := newCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 1, Cooldown: time.Second})
+	now := time.Now()
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	// Two calls are sent while closed; the first fails and opens the
+	// circuit.
+	first, _ := b.allow()
+	slow, _ := b.allow()
+	b.done(ctx, first, nil, errors.New("connection refused"))
+
+	// After the cooldown a trial is in flight when the slow closed-state
+	// call finishes. Its success must neither close the circuit nor free
+	// the trial's slot.
+	now = now.Add(time.Second)
+	trial, err := b.allow()
+	if err != nil {
+		t.Fatalf("expected a trial request, got %v", err)
+	}
+	b.done(ctx, slow, &http.Response{StatusCode: http.StatusOK}, nil)
+	if state := b.currentState(); state != CircuitHalfOpen {
+		t.Fatalf("expected the stale completion to be ignored, got %s", state)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected the trial slot to stay taken, got %v", err)
+	}
+
+	b.done(ctx, trial, nil, errors.New("connection refused"))
+	if state := b.currentState(); state != CircuitOpen {
+		t.Errorf("expected the failed trial to reopen the circuit, got %s", state)
+	}
+}
+
+func TestCircuitBreakerOfflineFallback(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"),
+		WithCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 1, Cooldown: time.Minute}),
+		WithOfflineFallback(TypeMath))
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.VerifyMath(context.Background(), "2+2=4")
+		if err != nil || resp.Engine != "local-math" {
+			t.Fatalf("call %d: expected local fallback, got %+v, %v", i, resp, err)
+		}
+	}
+	if client.CircuitState() != CircuitOpen {
+		t.Errorf("expected open circuit, got %s", client.CircuitState())
+	}
+}
@@ -0,0 +1,461 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ============================================================================
+// Citation Verification
+// ============================================================================
+
+// TypeCitations identifies citation checks. They run in the client and are
+// reported with Engine EngineLocalCitations.
+const TypeCitations VerificationType = "citations"
+
+// EngineLocalCitations is the engine name reported by VerifyCitations.
+const EngineLocalCitations Engine = "local-citations"
+
+// CitationKind classifies a citation found in text.
+type CitationKind string
+
+const (
+	CitationURL       CitationKind = "url"       // an http or https link
+	CitationDOI       CitationKind = "doi"       // a DOI such as 10.1000/xyz123
+	CitationArXiv     CitationKind = "arxiv"     // an arXiv identifier such as arXiv:2106.09685
+	CitationReference CitationKind = "reference" // an author-year reference such as (Smith et al., 2020)
+)
+
+// CitationStatus is the outcome of resolving a citation.
+type CitationStatus string
+
+const (
+	CitationResolved    CitationStatus = "resolved"    // the source exists
+	CitationNotFound    CitationStatus = "not_found"   // the source does not exist: likely fabricated
+	CitationUnreachable CitationStatus = "unreachable" // the source could not be checked, e.g. a timeout or bot block
+	CitationUnchecked   CitationStatus = "unchecked"   // the citation cannot be resolved automatically
+)
+
+// Citation is a source cited in text, with the result of checking it.
+// Start and End are byte offsets into the text.
+type Citation struct {
+	Kind       CitationKind   `json:"kind"`
+	Text       string         `json:"text"`          // as written
+	URL        string         `json:"url,omitempty"` // the address fetched
+	Start      int            `json:"start"`
+	End        int            `json:"end"`
+	Claim      string         `json:"claim,omitempty"` // the sentence the citation supports
+	Status     CitationStatus `json:"status,omitempty"`
+	HTTPStatus int            `json:"http_status,omitempty"`
+	Support    Verdict        `json:"support,omitempty"` // whether the source supports Claim, if checked
+	Error      string         `json:"error,omitempty"`
+}
+
+// CitationOptions configures VerifyCitations.
+type CitationOptions struct {
+	// CheckSupport fetches each resolved source and checks with VerifyFact
+	// that it supports the sentence citing it. This makes one API call per
+	// citation. By default only resolution is checked.
+	CheckSupport bool
+
+	// HTTPClient fetches cited sources. Defaults to a client with a 10
+	// second timeout that refuses private and loopback addresses, so text
+	// from a model cannot make the application probe its own network.
+	HTTPClient *http.Client
+
+	// AllowPrivate lets the default HTTPClient fetch private and loopback
+	// addresses.
+	AllowPrivate bool
+
+	// Concurrency limits the number of sources fetched at once. Defaults
+	// to 4.
+	Concurrency int
+
+	// MaxSourceBytes limits how much of each source is read for support
+	// checks. Defaults to 1 MiB.
+	MaxSourceBytes int64
+
+	// DOIResolver is the base URL DOIs are resolved against. Defaults to
+	// "https://doi.org/".
+	DOIResolver string
+}
+
+// VerifyCitations extracts the URLs, DOIs, arXiv identifiers and
+// author-year references in text, typically an LLM answer, and checks
+// that each resolvable one exists. With CheckSupport, each source is also
+// checked to support the sentence citing it.
+//
+// The response fails if any source does not exist (HTTP 404 or 410, or an
+// unknown host) or contradicts its claim. It is inconclusive, with reason
+// ReasonUnreachable, if some source could not be checked, as when a site
+// blocks automated requests. The citations are reported in
+// Result["citations"], decoded by Citations; author-year references are
+// listed as CitationUnchecked and do not affect the verdict.
+func (c *Client) VerifyCitations(ctx context.Context, text string, opts *CitationOptions) (resp *VerificationResponse, err error) {
+	ctx, end := c.instrument(ctx, "VerifyCitations", TypeCitations)
+	defer func() { end(resp, err) }()
+
+	var o CitationOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.HTTPClient == nil {
+		o.HTTPClient = citationHTTPClient(o.AllowPrivate)
+	}
+	if o.Concurrency < 1 {
+		o.Concurrency = 4
+	}
+	if o.MaxSourceBytes <= 0 {
+		o.MaxSourceBytes = 1 << 20
+	}
+	if o.DOIResolver == "" {
+		o.DOIResolver = "https://doi.org/"
+	}
+
+	citations := ExtractCitations(text)
+	checker := &citationChecker{client: c, opts: o, fetched: make(map[string]*citationSource)}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, o.Concurrency)
+	for i := range citations {
+		if citations[i].Kind == CitationReference {
+			citations[i].Status = CitationUnchecked
+			continue
+		}
+		if citations[i].Kind == CitationDOI {
+			citations[i].URL = strings.TrimSuffix(o.DOIResolver, "/") + "/" + doiName.FindString(citations[i].Text)
+		}
+		wg.Add(1)
+		go func(cite *Citation) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			checker.check(ctx, cite)
+		}(&citations[i])
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return citationResponse(citations), nil
+}
+
+// Citations extracts the citations from a VerifyCitations response.
+func Citations(resp *VerificationResponse) []Citation {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var citations []Citation
+	decodeResult(resp.Result["citations"], &citations)
+	return citations
+}
+
+// citationResponse aggregates the checked citations into a response.
+func citationResponse(citations []Citation) *VerificationResponse {
+	counts := make(map[CitationStatus]int)
+	refuted, inconclusiveSupport := 0, 0
+	for _, cite := range citations {
+		counts[cite.Status]++
+		switch cite.Support {
+		case VerdictRefuted:
+			refuted++
+		case VerdictInconclusive:
+			inconclusiveSupport++
+		}
+	}
+	result := map[string]interface{}{
+		"citations":   citations,
+		"resolved":    counts[CitationResolved],
+		"not_found":   counts[CitationNotFound],
+		"unreachable": counts[CitationUnreachable],
+		"unsupported": refuted,
+	}
+
+	resp := localResponse(EngineLocalCitations, counts[CitationNotFound] == 0 && refuted == 0, result)
+	if resp.Verified && (counts[CitationUnreachable] > 0 || inconclusiveSupport > 0) {
+		return inconclusive(resp, ReasonUnreachable)
+	}
+	return resp
+}
+
+// ============================================================================
+// Extraction
+// ============================================================================
+
+var (
+	citationURL       = regexp.MustCompile(`https?://[^\s<>"'\]]+`)
+	citationDOI       = regexp.MustCompile(`(?i)\b(?:doi:\s*)?10\.\d{4,9}/[^\s"<>\]]+`)
+	citationArXiv     = regexp.MustCompile(`(?i)\barxiv:\s*(\d{4}\.\d{4,5}(?:v\d+)?)`)
+	citationReference = regexp.MustCompile(`\(([A-Z][\p{L}'’-]+(?: et al\.| (?:and|&) [A-Z][\p{L}'’-]+)?),? ((?:19|20)\d{2}[a-z]?)\)`)
+	doiURL            = regexp.MustCompile(`(?i)^https?://(?:dx\.)?doi\.org/10\.`)
+	doiName           = regexp.MustCompile(`10\.\d{4,9}/.+`)
+)
+
+// ExtractCitations finds the citations in text without checking them, in
+// order of appearance. Each is paired with the sentence it supports: the
+// sentence containing it, or the previous one if the citation stands
+// alone, as in "Source: https://...".
+func ExtractCitations(text string) []Citation {
+	var citations []Citation
+	covered := func(start, end int) bool {
+		for _, c := range citations {
+			if start < c.End && end > c.Start {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, m := range citationURL.FindAllStringIndex(text, -1) {
+		raw := trimCitationURL(text[m[0]:m[1]])
+		cite := Citation{Kind: CitationURL, Text: raw, URL: raw, Start: m[0], End: m[0] + len(raw)}
+		if doiURL.MatchString(raw) {
+			cite.Kind, cite.URL = CitationDOI, "" // resolved against CitationOptions.DOIResolver
+		}
+		citations = append(citations, cite)
+	}
+	for _, m := range citationDOI.FindAllStringIndex(text, -1) {
+		raw := strings.TrimRight(text[m[0]:m[1]], ".,;:!?)")
+		if !covered(m[0], m[0]+len(raw)) {
+			citations = append(citations, Citation{Kind: CitationDOI, Text: raw, Start: m[0], End: m[0] + len(raw)})
+		}
+	}
+	for _, m := range citationArXiv.FindAllStringSubmatchIndex(text, -1) {
+		if !covered(m[0], m[1]) {
+			citations = append(citations, Citation{
+				Kind: CitationArXiv, Text: text[m[0]:m[1]], URL: "https://arxiv.org/abs/" + text[m[2]:m[3]], Start: m[0], End: m[1],
+			})
+		}
+	}
+	for _, m := range citationReference.FindAllStringIndex(text, -1) {
+		citations = append(citations, Citation{Kind: CitationReference, Text: text[m[0]:m[1]], Start: m[0], End: m[1]})
+	}
+	sort.Slice(citations, func(i, j int) bool { return citations[i].Start < citations[j].Start })
+
+	sentences := splitSentences(text)
+	for i := range citations {
+		citations[i].Claim = citedClaim(text, sentences, citations, citations[i].Start)
+	}
+	return citations
+}
+
+// trimCitationURL removes trailing punctuation that ends the sentence
+// rather than the URL, keeping balanced parentheses as in Wikipedia links.
+func trimCitationURL(u string) string {
+	for {
+		trimmed := strings.TrimRight(u, ".,;:!?*_")
+		if strings.HasSuffix(trimmed, ")") && strings.Count(trimmed, "(") < strings.Count(trimmed, ")") {
+			trimmed = trimmed[:len(trimmed)-1]
+		}
+		if trimmed == u {
+			return u
+		}
+		u = trimmed
+	}
+}
+
+// citedClaim returns the text of the sentence containing offset, with the
+// citations removed, falling back to the previous sentence if nothing
+// else is left.
+func citedClaim(text string, sentences [][2]int, citations []Citation, offset int) string {
+	for i := len(sentences) - 1; i >= 0; i-- {
+		if sentences[i][0] > offset {
+			continue
+		}
+		for j := i; j >= 0; j-- {
+			if claim := stripCitations(text, sentences[j], citations); claimWords(claim) >= 3 {
+				return claim
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+var (
+	markdownLink = regexp.MustCompile(`\[([^\]]*)\]\(\s*\)`)
+	emptyBracket = regexp.MustCompile(`\(\s*[,;]?\s*\)|\[\s*\]`)
+)
+
+// stripCitations returns the sentence span of text without its citations
+// and the Markdown link syntax around them.
+func stripCitations(text string, span [2]int, citations []Citation) string {
+	var b strings.Builder
+	pos := span[0]
+	for _, c := range citations {
+		if c.Start < span[0] || c.End > span[1] {
+			continue
+		}
+		b.WriteString(text[pos:c.Start])
+		pos = c.End
+	}
+	b.WriteString(text[pos:span[1]])
+
+	s := markdownLink.ReplaceAllString(b.String(), "$1")
+	s = emptyBracket.ReplaceAllString(s, "")
+	s = strings.Join(strings.Fields(s), " ")
+	s = strings.TrimRight(strings.TrimSpace(s), ":-–—")
+	return strings.ReplaceAll(s, " .", ".")
+}
+
+func claimWords(s string) int {
+	n := 0
+	for _, w := range strings.Fields(s) {
+		if len(w) > 1 {
+			n++
+		}
+	}
+	return n
+}
+
+// ============================================================================
+// Resolution
+// ============================================================================
+
+// citationSource is a fetched source, shared by citations of the same URL.
+type citationSource struct {
+	once   sync.Once
+	status CitationStatus
+	code   int
+	text   string
+	err    error
+}
+
+type citationChecker struct {
+	client *Client
+	opts   CitationOptions
+
+	mu      sync.Mutex
+	fetched map[string]*citationSource
+}
+
+// check resolves cite and, if requested, checks that it supports its claim.
+func (k *citationChecker) check(ctx context.Context, cite *Citation) {
+	k.mu.Lock()
+	src, ok := k.fetched[cite.URL]
+	if !ok {
+		src = &citationSource{}
+		k.fetched[cite.URL] = src
+	}
+	k.mu.Unlock()
+	src.once.Do(func() { k.fetch(ctx, cite.URL, src) })
+
+	cite.Status, cite.HTTPStatus = src.status, src.code
+	if src.err != nil {
+		cite.Error = src.err.Error()
+	}
+	if !k.opts.CheckSupport || cite.Status != CitationResolved || cite.Claim == "" {
+		return
+	}
+	if strings.TrimSpace(src.text) == "" {
+		cite.Support = VerdictInconclusive
+		cite.Error = "source has no readable text"
+		return
+	}
+	resp, err := k.client.VerifyFact(ctx, cite.Claim, src.text)
+	if err != nil {
+		cite.Support = VerdictInconclusive
+		cite.Error = fmt.Sprintf("failed to check support: %v", err)
+		return
+	}
+	cite.Support = resp.Verdict()
+}
+
+// fetch retrieves url into src.
+func (k *citationChecker) fetch(ctx context.Context, url string, src *citationSource) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		src.status, src.err = CitationNotFound, fmt.Errorf("invalid URL: %w", err)
+		return
+	}
+	req.Header.Set("User-Agent", "qwed-go-sdk (citation check)")
+	req.Header.Set("Accept", "text/html, text/plain;q=0.9, */*;q=0.5")
+
+	resp, err := k.opts.HTTPClient.Do(req)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			src.status, src.err = CitationNotFound, err
+			return
+		}
+		src.status, src.err = CitationUnreachable, err
+		return
+	}
+	defer resp.Body.Close()
+
+	src.code = resp.StatusCode
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		src.status = CitationNotFound
+		return
+	case resp.StatusCode >= 400:
+		// 401, 403 and 429 are usually bot protection, and 5xx may be
+		// temporary; neither shows the source is missing.
+		src.status = CitationUnreachable
+		return
+	}
+	src.status = CitationResolved
+	if k.opts.CheckSupport {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, k.opts.MaxSourceBytes))
+		if err != nil {
+			src.err = fmt.Errorf("failed to read source: %w", err)
+			return
+		}
+		src.text = sourceText(string(body), resp.Header.Get("Content-Type"))
+	}
+}
+
+var (
+	htmlHidden = regexp.MustCompile(`(?is)<(script|style|noscript|svg|head)\b.*?</(script|style|noscript|svg|head)>`)
+	htmlTag    = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// sourceText reduces a fetched document to its readable text.
+func sourceText(body, contentType string) string {
+	if strings.Contains(contentType, "html") || strings.Contains(strings.ToLower(body[:min(len(body), 512)]), "<html") {
+		body = htmlHidden.ReplaceAllString(body, " ")
+		body = htmlTag.ReplaceAllString(body, " ")
+		body = html.UnescapeString(body)
+	} else if contentType != "" && !strings.HasPrefix(contentType, "text/") {
+		return "" // PDFs and other binary formats
+	}
+	return strings.Join(strings.Fields(body), " ")
+}
+
+var errPrivateAddress = errors.New("refusing to fetch a private or loopback address")
+
+// citationHTTPClient returns the default client for fetching sources.
+func citationHTTPClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+				ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	if !allowPrivate {
+		transport.Proxy = nil // a proxy would hide the address being checked
+	}
+	return &http.Client{Timeout: 10 * time.Second, Transport: transport}
+}
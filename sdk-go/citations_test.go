@@ -0,0 +1,157 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestExtractCitations(t *testing.T) {
+	text := "Transformers were introduced in 2017 (Vaswani et al., 2017). " +
+		"LoRA reduces trainable parameters by 10,000x [paper](https://arxiv.org/abs/2106.09685). " +
+		"See doi:10.1038/nature14539 for a review of deep learning. " +
+		"Mount Everest is 8,849 m tall.\nSource: https://en.wikipedia.org/wiki/Mount_Everest_(mountain).\n" +
+		"Scaling laws were studied in arXiv:2001.08361."
+
+	citations := ExtractCitations(text)
+	want := []struct {
+		kind  CitationKind
+		text  string
+		claim string
+	}{
+		{CitationReference, "(Vaswani et al., 2017)", "Transformers were introduced in 2017."},
+		{CitationURL, "https://arxiv.org/abs/2106.09685", "LoRA reduces trainable parameters by 10,000x paper."},
+		{CitationDOI, "doi:10.1038/nature14539", "See for a review of deep learning."},
+		{CitationURL, "https://en.wikipedia.org/wiki/Mount_Everest_(mountain)", "Mount Everest is 8,849 m tall."},
+		{CitationArXiv, "arXiv:2001.08361", "Scaling laws were studied in."},
+	}
+	if len(citations) != len(want) {
+		t.Fatalf("expected %d citations, got %+v", len(want), citations)
+	}
+	for i, w := range want {
+		c := citations[i]
+		if c.Kind != w.kind || c.Text != w.text || c.Claim != w.claim {
+			t.Errorf("citation %d: expected %s %q for %q, got %s %q for %q", i, w.kind, w.text, w.claim, c.Kind, c.Text, c.Claim)
+		}
+		if text[c.Start:c.End] != c.Text {
+			t.Errorf("citation %d: offsets select %q", i, text[c.Start:c.End])
+		}
+	}
+	if citations[4].URL != "https://arxiv.org/abs/2001.08361" {
+		t.Errorf("unexpected arXiv URL %q", citations[4].URL)
+	}
+}
+
+func TestVerifyCitations(t *testing.T) {
+	var fetches int32
+	sources := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		switch r.URL.Path {
+		case "/real":
+			w.Write([]byte("ok"))
+		case "/blocked":
+			w.WriteHeader(http.StatusForbidden)
+		case "/10.1000/real":
+			w.Write([]byte("paper"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer sources.Close()
+
+	client := NewClient("test")
+	opts := &CitationOptions{AllowPrivate: true, DOIResolver: sources.URL}
+
+	text := "The sky is blue (" + sources.URL + "/real). Water boils at 100 °C (" + sources.URL + "/real). See doi:10.1000/real for more details here."
+	resp, err := client.VerifyCitations(context.Background(), text, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Verified || resp.Engine != EngineLocalCitations || resp.Result["resolved"] != 3 {
+		t.Errorf("expected verified citations, got %s %v", resp.Status, resp.Result)
+	}
+	if fetches != 2 {
+		t.Errorf("expected each source to be fetched once, got %d fetches", fetches)
+	}
+
+	resp, _ = client.VerifyCitations(context.Background(), "A made-up study shows this ("+sources.URL+"/fabricated).", opts)
+	cites := Citations(resp)
+	if resp.Verified || len(cites) != 1 || cites[0].Status != CitationNotFound || cites[0].HTTPStatus != 404 {
+		t.Errorf("expected a fabricated citation to fail, got %s %+v", resp.Status, cites)
+	}
+
+	resp, _ = client.VerifyCitations(context.Background(), "This page blocks bots ("+sources.URL+"/blocked).", opts)
+	if resp.Verdict() != VerdictInconclusive || resp.InconclusiveReason() != ReasonUnreachable {
+		t.Errorf("expected an unreachable source to be inconclusive, got %s %v", resp.Status, resp.Result)
+	}
+}
+
+func TestVerifyCitationsRefusesPrivateAddresses(t *testing.T) {
+	var fetched bool
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { fetched = true }))
+	defer internal.Close()
+
+	resp, err := NewClient("test").VerifyCitations(context.Background(), "Secrets live at "+internal.URL+"/admin for sure.", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cites := Citations(resp)
+	if fetched || len(cites) != 1 || cites[0].Status != CitationUnreachable || !strings.Contains(cites[0].Error, "private or loopback") {
+		t.Errorf("expected the loopback address to be refused, got %+v", cites)
+	}
+}
+
+func TestVerifyCitationsSupport(t *testing.T) {
+	sources := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>x</title><script>var a;</script></head><body><p>Mount Everest is 8,849&nbsp;m tall.</p></body></html>`))
+	}))
+	defer sources.Close()
+
+	var contexts []string
+	api := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		contexts = append(contexts, req["context"].(string))
+		verified := strings.Contains(req["claim"].(string), "8,849")
+		status := StatusFailed
+		if verified {
+			status = StatusVerified
+		}
+		json.NewEncoder(w).Encode(VerificationResponse{Status: status, Verified: verified})
+	})
+	defer api.Close()
+
+	client := NewClient("test", WithBaseURL(api.URL))
+	opts := &CitationOptions{AllowPrivate: true, CheckSupport: true}
+
+	resp, err := client.VerifyCitations(context.Background(), "Mount Everest is 8,849 m tall ("+sources.URL+").", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Verified || Citations(resp)[0].Support != VerdictVerified {
+		t.Errorf("expected a supported citation, got %v", resp.Result)
+	}
+	if len(contexts) != 1 || contexts[0] != "Mount Everest is 8,849 m tall." {
+		t.Errorf("expected the page text as context, got %q", contexts)
+	}
+
+	resp, _ = client.VerifyCitations(context.Background(), "Mount Everest is 9,000 m tall ("+sources.URL+").", opts)
+	if resp.Verified || Citations(resp)[0].Support != VerdictRefuted || resp.Result["unsupported"] != 1 {
+		t.Errorf("expected an unsupported claim to fail, got %v", resp.Result)
+	}
+}
+
+func TestVerifyCitationsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewClient("test").VerifyCitations(ctx, "See https://example.com for details.", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
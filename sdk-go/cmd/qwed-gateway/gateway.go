@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// maxBodyBytes limits the size of request bodies.
+const maxBodyBytes = 10 << 20
+
+// gateway serves the QWED REST surface, answering each call through an
+// embedded client so its cache, policy and fallbacks apply.
+type gateway struct {
+	client  *qwed.Client
+	metrics http.Handler // nil disables /metrics
+}
+
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Honor the caller's deadline; the client forwards what remains of it
+	// upstream.
+	if timeout, ok := qwed.RequestDeadline(r.Header); ok {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		r = r.WithContext(ctx)
+	}
+
+	switch path := r.URL.Path; {
+	case path == "/health" && r.Method == http.MethodGet:
+		result, err := g.client.Health(r.Context())
+		reply(w, result, err)
+	case path == "/usage" && r.Method == http.MethodGet:
+		usage, err := g.client.Usage(r.Context(), qwed.UsagePeriod(r.URL.Query().Get("period")))
+		reply(w, usage, err)
+	case path == "/metrics" && r.Method == http.MethodGet && g.metrics != nil:
+		g.metrics.ServeHTTP(w, r)
+	case path == "/verify/batch" && r.Method == http.MethodPost:
+		var req struct {
+			Items   []qwed.BatchItem   `json:"items"`
+			Options *qwed.BatchOptions `json:"options"`
+		}
+		if decode(w, r, &req) {
+			resp, err := g.client.VerifyBatch(r.Context(), req.Items, req.Options)
+			reply(w, resp, err)
+		}
+	case strings.HasPrefix(path, "/verify/batch/") && strings.HasSuffix(path, "/retry") && r.Method == http.MethodPost:
+		var req struct {
+			Options *qwed.BatchOptions `json:"options"`
+		}
+		if decode(w, r, &req) {
+			jobID := strings.TrimSuffix(strings.TrimPrefix(path, "/verify/batch/"), "/retry")
+			resp, err := g.client.RetryBatchFailures(r.Context(), jobID, &qwed.RetryOptions{Batch: req.Options})
+			reply(w, resp, err)
+		}
+	case strings.HasPrefix(path, "/verify/batch/") && r.Method == http.MethodGet:
+		resp, err := g.client.GetBatch(r.Context(), strings.TrimPrefix(path, "/verify/batch/"))
+		reply(w, resp, err)
+	case path == "/extract" && r.Method == http.MethodPost:
+		var req struct {
+			PDF   []byte `json:"pdf"`
+			Pages string `json:"pages"`
+			OCR   bool   `json:"ocr"`
+		}
+		if decode(w, r, &req) {
+			doc, err := g.client.ExtractContext(r.Context(), req.PDF, qwed.ExtractOptions{Pages: req.Pages, OCR: req.OCR})
+			reply(w, doc, err)
+		}
+	case path == "/reverify" && r.Method == http.MethodPost:
+		var req struct {
+			Filter struct {
+				Domain   string    `json:"domain"`
+				Verified *bool     `json:"is_verified"`
+				Since    time.Time `json:"since"`
+				Until    time.Time `json:"until"`
+				Limit    int       `json:"limit"`
+			} `json:"filter"`
+			Options *qwed.RangeOptions `json:"options"`
+		}
+		if decode(w, r, &req) {
+			job, err := g.client.ReverifyRange(r.Context(), qwed.HistoryFilter(req.Filter), req.Options)
+			reply(w, job, err)
+		}
+	case strings.HasPrefix(path, "/reverify/") && r.Method == http.MethodGet:
+		job, err := g.client.GetReverifyJob(r.Context(), strings.TrimPrefix(path, "/reverify/"))
+		reply(w, job, err)
+	case path == "/feedback/gaps" && r.Method == http.MethodPost:
+		var req qwed.GapReport
+		if decode(w, r, &req) {
+			err := g.client.ReportGap(r.Context(), req.VerificationID, req.Note)
+			reply(w, map[string]interface{}{"reported": err == nil}, err)
+		}
+	case strings.HasPrefix(path, "/verify/") && r.Method == http.MethodPost:
+		g.verify(w, r, qwed.VerificationType(strings.TrimPrefix(path, "/verify/")))
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path))
+	}
+}
+
+// verifyRequest holds the fields of every engine's request body.
+type verifyRequest struct {
+	Query           string                    `json:"query"`
+	Expression      string                    `json:"expression"`
+	CrossCheck      bool                      `json:"cross_check"`
+	Code            string                    `json:"code"`
+	Language        string                    `json:"language"`
+	Claim           string                    `json:"claim"`
+	Context         string                    `json:"context"`
+	ContextLanguage string                    `json:"context_language"`
+	SchemaDDL       string                    `json:"schema_ddl"`
+	Dialect         string                    `json:"dialect"`
+	JSON            string                    `json:"json"`
+	Schema          string                    `json:"schema"`
+	Source          string                    `json:"source"`
+	Extracted       string                    `json:"extracted"`
+	Input           string                    `json:"input"`
+	Content         string                    `json:"content"`
+	Kind            string                    `json:"kind"`
+	SchemaSDL       string                    `json:"schema_sdl"`
+	MaxDepth        int                       `json:"max_depth"`
+	DeniedFields    []string                  `json:"denied_fields"`
+	OperationName   string                    `json:"operation_name"`
+	Image           []byte                    `json:"image"`
+	Claims          []string                  `json:"claims"`
+	Implementation  string                    `json:"implementation"`
+	Tests           string                    `json:"tests"`
+	Variables       []qwed.ConstraintVariable `json:"variables"`
+	Constraints     []string                  `json:"constraints"`
+	Solution        map[string]interface{}    `json:"solution"`
+	Problem         qwed.OptimizationProblem  `json:"problem"`
+	Tolerance       float64                   `json:"tolerance"`
+	Edges           []qwed.Edge               `json:"edges"`
+	Undirected      bool                      `json:"undirected"`
+	Text            string                    `json:"text"`
+	Rules           []qwed.ComplianceRule     `json:"rules"`
+	Options         *qwed.RequestOptions      `json:"options"`
+}
+
+// verify dispatches a verification call to the client method for engine.
+func (g *gateway) verify(w http.ResponseWriter, r *http.Request, engine qwed.VerificationType) {
+	var req verifyRequest
+	if !decode(w, r, &req) {
+		return
+	}
+
+	ctx := r.Context()
+	var resp *qwed.VerificationResponse
+	var err error
+	switch engine {
+	case qwed.TypeNaturalLanguage:
+		resp, err = g.client.VerifyWithOptions(ctx, req.Query, req.Options)
+	case qwed.TypeMath:
+		resp, err = g.client.VerifyMathWithOptions(ctx, req.Expression, &qwed.MathOptions{CrossCheck: req.CrossCheck})
+	case qwed.TypeLogic:
+		resp, err = g.client.VerifyLogic(ctx, req.Query)
+	case qwed.TypeCode:
+		resp, err = g.client.VerifyCodeWithOptions(ctx, req.Code, req.Language, req.Options)
+	case qwed.TypeFact:
+		resp, err = g.client.VerifyFactWithOptions(ctx, req.Claim, req.Context,
+			&qwed.FactOptions{Language: req.Language, ContextLanguage: req.ContextLanguage})
+	case qwed.TypeSQL:
+		resp, err = g.client.VerifySQLWithOptions(ctx, req.Query, req.SchemaDDL, req.Dialect, req.Options)
+	case qwed.TypeJSON:
+		resp, err = g.client.VerifyJSON(ctx, req.JSON, req.Schema)
+	case qwed.TypePromptSafety:
+		resp, err = g.client.VerifyPromptSafety(ctx, req.Input)
+	case qwed.TypeInfra:
+		resp, err = g.client.VerifyInfraWithOptions(ctx, req.Content, qwed.InfraKind(req.Kind), req.Options)
+	case qwed.TypeGraphQL:
+		resp, err = g.client.VerifyGraphQLWithOptions(ctx, req.Query, req.SchemaSDL,
+			&qwed.GraphQLOptions{MaxDepth: req.MaxDepth, DeniedFields: req.DeniedFields, OperationName: req.OperationName})
+	case qwed.TypeChart:
+		resp, err = g.client.VerifyChartWithOptions(ctx, req.Image, req.Claims, req.Options)
+	case qwed.TypeConstraints:
+		resp, err = g.client.VerifyConstraints(ctx, req.Variables, req.Constraints, req.Solution)
+	case qwed.TypeOptimization:
+		resp, err = g.client.VerifyOptimal(ctx, req.Problem, numbers(req.Solution), req.Tolerance)
+	case qwed.TypeGraph:
+		resp, err = g.client.VerifyGraphClaimWithOptions(ctx, req.Edges, req.Claim, &qwed.GraphOptions{Undirected: req.Undirected})
+	case qwed.TypeExtraction:
+		resp, err = g.client.VerifyExtraction(ctx, req.Source, req.Extracted, req.Schema)
+	case qwed.TypeCompliance:
+		resp, err = g.client.VerifyCompliance(ctx, req.Text, req.Rules)
+	case qwed.TypeTests:
+		resp, err = g.client.VerifyTestsWithOptions(ctx, req.Implementation, req.Tests, req.Language, req.Options)
+	default:
+		writeError(w, http.StatusNotFound, "UNSUPPORTED_ENGINE", fmt.Sprintf("engine %q is not supported by the gateway", engine))
+		return
+	}
+	reply(w, resp, err)
+}
+
+// numbers converts the numeric values of a decoded JSON object, dropping
+// the rest.
+func numbers(m map[string]interface{}) map[string]float64 {
+	out := make(map[string]float64, len(m))
+	for k, v := range m {
+		if f, ok := v.(float64); ok {
+			out[k] = f
+		}
+	}
+	return out
+}
+
+// decode reads a JSON request body into v, replying with 400 on failure.
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("failed to decode request: %v", err))
+		return false
+	}
+	return true
+}
+
+// reply writes v as JSON, or the error in the API's error format.
+func reply(w http.ResponseWriter, v interface{}, err error) {
+	if err != nil {
+		status, code := errorStatus(err)
+		writeError(w, status, code, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// errorStatus maps a client error to the status and code returned to the
+// caller. Upstream API errors keep their status and code.
+func errorStatus(err error) (int, string) {
+	var apiErr *qwed.QWEDError
+	switch {
+	case errors.As(err, &apiErr):
+		return apiErr.StatusCode, apiErr.Code
+	case errors.Is(err, qwed.ErrCircuitOpen):
+		return http.StatusServiceUnavailable, "CIRCUIT_OPEN"
+	case errors.Is(err, qwed.ErrBudgetExceeded):
+		return http.StatusGatewayTimeout, "BUDGET_EXCEEDED"
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, "UPSTREAM_TIMEOUT"
+	case errors.Is(err, qwed.ErrInvalidAttestation):
+		return http.StatusBadGateway, "INVALID_ATTESTATION"
+	case errors.Is(err, qwed.ErrNoFailedItems):
+		return http.StatusConflict, "NO_FAILED_ITEMS"
+	}
+	return http.StatusBadGateway, "UPSTREAM_UNAVAILABLE"
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]*qwed.ErrorInfo{"error": {Code: code, Message: message}})
+}
@@ -0,0 +1,236 @@
+// Command qwed-gateway serves the QWED REST API on localhost through an
+// embedded Go client, so services in any language share one integration
+// point with client-side caching, policies, latency budgets, rate limiting
+// and local engine fallbacks applied centrally.
+//
+// Usage:
+//
+//	qwed-gateway --listen 127.0.0.1:8787 --policy strict --offline math,logic
+//
+// Point existing SDKs at the gateway with QWED_BASE_URL=http://127.0.0.1:8787.
+// For sidecar deployments, --listen unix:/run/qwed.sock serves on a Unix
+// domain socket; Go clients reach it with qwed.WithUnixSocket.
+// The gateway authenticates to the upstream API with QWED_API_KEY; callers
+// need no key. If QWED_SIGNING_SECRET is set, upstream requests are signed
+// with it. /metrics exposes Prometheus metrics for the upstream calls.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// shutdownTimeout bounds how long in-flight requests may take to finish
+// after a shutdown signal.
+const shutdownTimeout = 30 * time.Second
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stderr))
+}
+
+// config holds the gateway's command-line settings.
+type config struct {
+	listen         string
+	upstream       string
+	timeout        time.Duration
+	cacheSize      int
+	cacheTTL       time.Duration
+	policy         string
+	offline        string
+	budget         time.Duration
+	budgetMode     string
+	rateLimit      float64
+	circuitBreaker bool
+	metrics        bool
+	signingSecret  string
+}
+
+func parseFlags(args []string, stderr io.Writer) (*config, error) {
+	upstream := os.Getenv("QWED_BASE_URL")
+	if upstream == "" {
+		upstream = "http://localhost:8000"
+	}
+
+	cfg := &config{signingSecret: os.Getenv("QWED_SIGNING_SECRET")}
+	fs := flag.NewFlagSet("qwed-gateway", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&cfg.listen, "listen", "127.0.0.1:8787", "address to serve on, or unix:<path> for a Unix domain socket")
+	fs.StringVar(&cfg.upstream, "upstream", upstream, "QWED API base URL (default: QWED_BASE_URL)")
+	fs.DurationVar(&cfg.timeout, "timeout", 30*time.Second, "upstream request timeout")
+	fs.IntVar(&cfg.cacheSize, "cache-size", 10000, "cached responses kept in memory; 0 disables caching")
+	fs.DurationVar(&cfg.cacheTTL, "cache-ttl", 10*time.Minute, "how long cached responses are served")
+	fs.StringVar(&cfg.policy, "policy", "", "verification policy: strict, standard or permissive")
+	fs.StringVar(&cfg.offline, "offline", "", "engines answered locally when upstream is unreachable, e.g. math,logic")
+	fs.DurationVar(&cfg.budget, "budget", 0, "latency budget per verification; 0 disables")
+	fs.StringVar(&cfg.budgetMode, "budget-mode", "soft", "over-budget behaviour: soft (inconclusive) or hard (504)")
+	fs.Float64Var(&cfg.rateLimit, "rate-limit", 0, "upstream requests per second; 0 disables")
+	fs.BoolVar(&cfg.circuitBreaker, "circuit-breaker", false, "stop calling upstream after repeated failures")
+	fs.BoolVar(&cfg.metrics, "metrics", true, "serve Prometheus metrics on /metrics")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != 0 {
+		return nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return cfg, nil
+}
+
+// clientOptions translates cfg into client options. The returned collector
+// is nil if metrics are disabled.
+func clientOptions(cfg *config) ([]qwed.ClientOption, *qwed.PrometheusCollector, error) {
+	opts := []qwed.ClientOption{qwed.WithBaseURL(cfg.upstream), qwed.WithTimeout(cfg.timeout)}
+
+	if cfg.cacheSize > 0 {
+		opts = append(opts, qwed.WithCache(qwed.NewLRUCache(cfg.cacheSize), cfg.cacheTTL))
+	}
+
+	switch cfg.policy {
+	case "":
+	case "strict":
+		opts = append(opts, qwed.WithPolicy(qwed.PolicyStrict))
+	case "standard":
+		opts = append(opts, qwed.WithPolicy(qwed.PolicyStandard))
+	case "permissive":
+		opts = append(opts, qwed.WithPolicy(qwed.PolicyPermissive))
+	default:
+		return nil, nil, fmt.Errorf("unknown policy %q", cfg.policy)
+	}
+
+	if cfg.offline != "" {
+		var engines []qwed.VerificationType
+		for _, engine := range strings.Split(cfg.offline, ",") {
+			switch engine := qwed.VerificationType(strings.TrimSpace(engine)); engine {
+			case qwed.TypeMath, qwed.TypeLogic:
+				engines = append(engines, engine)
+			default:
+				return nil, nil, fmt.Errorf("no offline fallback for engine %q", engine)
+			}
+		}
+		opts = append(opts, qwed.WithOfflineFallback(engines...))
+	}
+
+	if cfg.budget > 0 {
+		var mode qwed.BudgetMode
+		switch cfg.budgetMode {
+		case "soft":
+			mode = qwed.SoftFail
+		case "hard":
+			mode = qwed.HardFail
+		default:
+			return nil, nil, fmt.Errorf("unknown budget mode %q", cfg.budgetMode)
+		}
+		opts = append(opts, qwed.WithLatencyBudget(cfg.budget, mode))
+	}
+
+	if cfg.rateLimit > 0 {
+		opts = append(opts, qwed.WithRateLimit(cfg.rateLimit, int(math.Ceil(cfg.rateLimit))))
+	}
+	if cfg.circuitBreaker {
+		opts = append(opts, qwed.WithCircuitBreaker(qwed.CircuitBreakerSettings{}))
+	}
+	if cfg.signingSecret != "" {
+		opts = append(opts, qwed.WithRequestSigning(cfg.signingSecret))
+	}
+
+	var collector *qwed.PrometheusCollector
+	if cfg.metrics {
+		collector = qwed.NewPrometheusCollector()
+		opts = append(opts, qwed.WithMetrics(collector))
+	}
+	return opts, collector, nil
+}
+
+// newGateway creates the gateway handler for cfg.
+func newGateway(cfg *config) (http.Handler, error) {
+	opts, collector, err := clientOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	g := &gateway{client: qwed.NewClient(os.Getenv("QWED_API_KEY"), opts...)}
+	if collector != nil {
+		g.metrics = collector
+	}
+	return g, nil
+}
+
+// run serves the gateway until ctx is done and returns the process exit
+// code: 0 after a clean shutdown, 2 on usage or startup errors and 1 if
+// serving fails.
+func run(ctx context.Context, args []string, stderr io.Writer) int {
+	cfg, err := parseFlags(args, stderr)
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(stderr, "qwed-gateway: %v\n", err)
+		}
+		return 2
+	}
+	handler, err := newGateway(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "qwed-gateway: %v\n", err)
+		return 2
+	}
+
+	listener, err := listen(cfg.listen)
+	if err != nil {
+		fmt.Fprintf(stderr, "qwed-gateway: %v\n", err)
+		return 2
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	fmt.Fprintf(stderr, "qwed-gateway: serving on %s, upstream %s\n", listenerURL(listener), cfg.upstream)
+
+	errc := make(chan error, 1)
+	go func() { errc <- server.Serve(listener) }()
+
+	select {
+	case err := <-errc:
+		fmt.Fprintf(stderr, "qwed-gateway: %v\n", err)
+		return 1
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		fmt.Fprintf(stderr, "qwed-gateway: shutdown: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// listen opens the listener for addr: a TCP address, or "unix:" and the
+// path of a Unix domain socket. A socket file left by a previous run is
+// removed first.
+func listen(addr string) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, "unix:")
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+	if info, err := os.Stat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		os.Remove(path)
+	}
+	return net.Listen("unix", path)
+}
+
+// listenerURL describes where l serves, for the startup message.
+func listenerURL(l net.Listener) string {
+	if l.Addr().Network() == "unix" {
+		return "unix:" + l.Addr().String()
+	}
+	return "http://" + l.Addr().String()
+}
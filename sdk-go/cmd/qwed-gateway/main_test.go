@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// upstream returns an API server answering math calls and rejecting
+// everything else with 429, counting the calls it receives.
+func upstream(t *testing.T, calls *int32) string {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		if r.Header.Get("X-API-Key") != "gateway-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/verify/math" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"code": "RATE_LIMITED", "message": "slow down"}})
+			return
+		}
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		verified := req["expression"] == "2+2=4"
+		json.NewEncoder(w).Encode(qwed.VerificationResponse{Verified: verified, Status: map[bool]qwed.Status{true: qwed.StatusVerified, false: qwed.StatusFailed}[verified], Engine: "math"})
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func startGateway(t *testing.T, args ...string) string {
+	t.Setenv("QWED_API_KEY", "gateway-key")
+	cfg, err := parseFlags(args, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, err := newGateway(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestGatewayProxiesThroughClient(t *testing.T) {
+	var calls int32
+	gateway := startGateway(t, "--upstream", upstream(t, &calls))
+	client := qwed.NewClient("", qwed.WithBaseURL(gateway))
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.VerifyMath(context.Background(), "2+2=4")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.Verified || resp.Engine != "math" {
+			t.Errorf("unexpected response: %+v", resp)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the gateway cache to answer the second call, got %d upstream calls", calls)
+	}
+
+	_, err := client.VerifyLogic(context.Background(), "A OR NOT A")
+	if !errors.Is(err, qwed.ErrRateLimited) {
+		t.Fatalf("expected upstream rate limiting to pass through, got %v", err)
+	}
+	var apiErr *qwed.QWEDError
+	if !errors.As(err, &apiErr) || apiErr.Code != "RATE_LIMITED" {
+		t.Errorf("expected upstream error code, got %v", err)
+	}
+
+	// Gap reports are forwarded upstream as well.
+	if err := client.ReportGap(context.Background(), "req-1", "note"); !errors.Is(err, qwed.ErrRateLimited) {
+		t.Errorf("expected gap report to reach upstream, got %v", err)
+	}
+	if _, err := client.RetryBatchFailures(context.Background(), "job-1", nil); !errors.Is(err, qwed.ErrRateLimited) {
+		t.Errorf("expected batch retry to reach upstream, got %v", err)
+	}
+	if _, err := client.Usage(context.Background(), qwed.UsageMonth); !errors.Is(err, qwed.ErrRateLimited) {
+		t.Errorf("expected usage to reach upstream, got %v", err)
+	}
+	if _, err := client.ReverifyRange(context.Background(), qwed.HistoryFilter{Domain: "math"}, nil); !errors.Is(err, qwed.ErrRateLimited) {
+		t.Errorf("expected re-verification to reach upstream, got %v", err)
+	}
+	if _, err := client.GetReverifyJob(context.Background(), "rv-1"); !errors.Is(err, qwed.ErrRateLimited) {
+		t.Errorf("expected re-verification polling to reach upstream, got %v", err)
+	}
+
+	resp, err := http.Get(gateway + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), `engine="math"`) {
+		t.Errorf("expected math metrics, got %s", body)
+	}
+}
+
+func TestGatewayOfflineFallback(t *testing.T) {
+	gateway := startGateway(t, "--upstream", "http://127.0.0.1:1", "--offline", "math", "--cache-size", "0")
+	client := qwed.NewClient("", qwed.WithBaseURL(gateway))
+
+	resp, err := client.VerifyMath(context.Background(), "3 * 4 = 12")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Verified || resp.Engine != "local-math" {
+		t.Errorf("expected a local fallback response, got %+v", resp)
+	}
+
+	_, err = client.VerifySQL(context.Background(), "SELECT 1", "", "postgresql")
+	var apiErr *qwed.QWEDError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway || apiErr.Code != "UPSTREAM_UNAVAILABLE" {
+		t.Errorf("expected 502 for unreachable upstream, got %v", err)
+	}
+}
+
+func TestGatewayForwardsDeadline(t *testing.T) {
+	deadlines := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadlines <- r.Header.Get(qwed.HeaderRequestDeadline)
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	}))
+	t.Cleanup(server.Close)
+	gateway := startGateway(t, "--upstream", server.URL, "--cache-size", "0")
+
+	req, _ := http.NewRequest("POST", gateway+"/verify/math", strings.NewReader(`{"expression":"2+2=4"}`))
+	req.Header.Set(qwed.HeaderRequestDeadline, "5000")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	remaining, ok := qwed.RequestDeadline(http.Header{qwed.HeaderRequestDeadline: {<-deadlines}})
+	if !ok || remaining <= 0 || remaining > 5*time.Second {
+		t.Errorf("expected the caller's remaining deadline upstream, got %v, %v", remaining, ok)
+	}
+}
+
+func TestGatewaySignsUpstreamRequests(t *testing.T) {
+	errs := make(chan error, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		errs <- qwed.VerifyRequestSignature(body, r.Header.Get(qwed.HeaderRequestSignature), "proxy-secret")
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("QWED_SIGNING_SECRET", "proxy-secret")
+	gateway := startGateway(t, "--upstream", server.URL, "--cache-size", "0")
+
+	resp, err := http.Post(gateway+"/verify/math", "application/json", strings.NewReader(`{"expression":"2+2=4"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if err := <-errs; err != nil {
+		t.Errorf("expected a valid upstream signature, got %v", err)
+	}
+}
+
+func TestGatewayListensOnUnixSocket(t *testing.T) {
+	var calls int32
+	t.Setenv("QWED_API_KEY", "gateway-key")
+	cfg, err := parseFlags([]string{"--upstream", upstream(t, &calls)}, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler, err := newGateway(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	socket := filepath.Join(t.TempDir(), "qwed.sock")
+	listener, err := listen("unix:" + socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	if got := listenerURL(listener); got != "unix:"+socket {
+		t.Errorf("unexpected listener URL %s", got)
+	}
+	server := &http.Server{Handler: handler}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	client := qwed.NewClient("", qwed.WithUnixSocket(socket))
+	if resp, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil || !resp.Verified {
+		t.Errorf("expected the gateway to answer over the socket, got %+v, %v", resp, err)
+	}
+}
+
+func TestGatewayRejectsBadRequests(t *testing.T) {
+	gateway := startGateway(t, "--upstream", "http://127.0.0.1:1")
+
+	tests := []struct {
+		method, path, body string
+		status             int
+	}{
+		{"POST", "/verify/math", "{", http.StatusBadRequest},
+		{"POST", "/verify/image", "{}", http.StatusNotFound},
+		{"GET", "/verify/math", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, gateway+tt.path, strings.NewReader(tt.body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s %s: expected %d, got %d", tt.method, tt.path, tt.status, resp.StatusCode)
+		}
+	}
+}
+
+func TestClientOptionsValidation(t *testing.T) {
+	for _, args := range [][]string{
+		{"--policy", "lenient"},
+		{"--offline", "sql"},
+		{"--budget", "100ms", "--budget-mode", "maybe"},
+	} {
+		cfg, err := parseFlags(args, io.Discard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := clientOptions(cfg); err == nil {
+			t.Errorf("expected %v to be rejected", args)
+		}
+	}
+	if _, err := parseFlags([]string{"extra"}, io.Discard); err == nil {
+		t.Error("expected positional arguments to be rejected")
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/QWED-AI/qwed-verification/sdk-go/github"
+)
+
+// actionsReporter emits GitHub Actions workflow commands, job summaries and
+// step outputs. It is nil outside GitHub Actions, and all methods are no-ops
+// on a nil reporter.
+type actionsReporter struct {
+	w io.Writer
+}
+
+// githubActions returns a reporter writing workflow commands to w when
+// running under GitHub Actions (GITHUB_ACTIONS=true), or nil otherwise.
+func githubActions(w io.Writer) *actionsReporter {
+	if os.Getenv("GITHUB_ACTIONS") != "true" {
+		return nil
+	}
+	return &actionsReporter{w: w}
+}
+
+// annotation is a failed verification reported as a workflow error.
+type annotation struct {
+	File    string
+	Line    int
+	Title   string
+	Message string
+}
+
+// error emits an ::error workflow command so the failure is annotated on
+// the file in the pull request.
+func (a *actionsReporter) error(an annotation) {
+	if a == nil {
+		return
+	}
+	var props []string
+	if an.File != "" {
+		props = append(props, "file="+escapeProperty(an.File))
+	}
+	if an.Line > 0 {
+		props = append(props, fmt.Sprintf("line=%d", an.Line))
+	}
+	if an.Title != "" {
+		props = append(props, "title="+escapeProperty(an.Title))
+	}
+	cmd := "::error"
+	if len(props) > 0 {
+		cmd += " " + strings.Join(props, ",")
+	}
+	fmt.Fprintf(a.w, "%s::%s\n", cmd, escapeData(an.Message))
+}
+
+// finish writes the job summary and sets the verified and failed_count
+// step outputs.
+func (a *actionsReporter) finish(summary string, verified bool, failed int) error {
+	if a == nil {
+		return nil
+	}
+	if err := appendEnvFile("GITHUB_STEP_SUMMARY", summary); err != nil {
+		return err
+	}
+	return appendEnvFile("GITHUB_OUTPUT", fmt.Sprintf("verified=%t\nfailed_count=%d\n", verified, failed))
+}
+
+// postPRComment posts report as a comment on the pull request of the
+// current GitHub Actions run. key identifies the comment, so each command
+// updates its own comment on reruns.
+func postPRComment(ctx context.Context, key string, report github.Report) error {
+	reporter, err := github.FromEnv(github.WithKey(key))
+	if err != nil {
+		return fmt.Errorf("failed to create PR reporter: %w", err)
+	}
+	if _, err := reporter.Post(ctx, report); err != nil {
+		return fmt.Errorf("failed to post PR comment: %w", err)
+	}
+	return nil
+}
+
+// appendEnvFile appends text to the file named by the environment variable,
+// if set.
+func appendEnvFile(env, text string) error {
+	path := os.Getenv(env)
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", env, err)
+	}
+	defer f.Close()
+	if _, err := io.WriteString(f, text); err != nil {
+		return fmt.Errorf("failed to write %s: %w", env, err)
+	}
+	return nil
+}
+
+// markdownTable renders a Markdown table for the job summary.
+func markdownTable(header []string, rows [][]string) string {
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.NewReplacer("|", `\|`, "\n", " ").Replace(cell)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+	return b.String()
+}
+
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+const defaultBaselinePath = ".qwed-baseline.json"
+
+func runBaseline(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: qwed baseline generate|update|check [flags] files...")
+		return 2
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("baseline "+action, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("baseline", defaultBaselinePath, "baseline file")
+	lang := fs.String("lang", "", "source language (default: from file extension)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "qwed: no files given")
+		return 2
+	}
+
+	var baseline *qwed.Baseline
+	switch action {
+	case "generate":
+		baseline = qwed.NewBaseline()
+	case "update", "check":
+		b, err := qwed.LoadBaseline(*path)
+		if err != nil && !(action == "update" && errors.Is(err, os.ErrNotExist)) {
+			fmt.Fprintf(stderr, "qwed: %v\n", err)
+			return 2
+		}
+		if b == nil {
+			b = qwed.NewBaseline()
+		}
+		baseline = b
+	default:
+		fmt.Fprintf(stderr, "qwed: unknown baseline command %q\n", action)
+		return 2
+	}
+
+	client := newClient()
+	actions := githubActions(stdout)
+	var rows [][]string
+	newCount := 0
+	for _, file := range fs.Args() {
+		code, findings, err := scanFile(ctx, client, file, *lang)
+		if err != nil {
+			fmt.Fprintf(stderr, "qwed: %s: %v\n", file, err)
+			return 2
+		}
+
+		switch action {
+		case "generate":
+			baseline.Add(file, code, findings)
+		case "update":
+			baseline.Update(file, code, findings)
+		case "check":
+			fresh, _ := baseline.Filter(file, code, findings)
+			for _, f := range fresh {
+				if f.Suppressed {
+					continue
+				}
+				newCount++
+				fmt.Fprintf(stdout, "%s:%d: [%s] %s: %s\n", file, f.Line, f.Severity, f.Type, f.Description)
+				actions.error(annotation{File: file, Line: f.Line, Title: f.Type, Message: f.Description})
+				rows = append(rows, []string{file, fmt.Sprint(f.Line), f.Severity, f.Type, f.Description})
+			}
+		}
+	}
+
+	if action == "check" {
+		summary := fmt.Sprintf("### QWED baseline check\n\n%d new finding(s) not in `%s`.\n\n", newCount, *path)
+		if len(rows) > 0 {
+			summary += markdownTable([]string{"File", "Line", "Severity", "Rule", "Description"}, rows) + "\n"
+		}
+		if err := actions.finish(summary, newCount == 0, newCount); err != nil {
+			fmt.Fprintf(stderr, "qwed: %v\n", err)
+			return 2
+		}
+		if newCount > 0 {
+			fmt.Fprintf(stderr, "%d new finding(s) not in %s\n", newCount, *path)
+			return 1
+		}
+		return 0
+	}
+
+	if err := baseline.Save(*path); err != nil {
+		fmt.Fprintf(stderr, "qwed: %v\n", err)
+		return 2
+	}
+	fmt.Fprintf(stdout, "wrote %d finding(s) to %s\n", len(baseline.Findings), *path)
+	return 0
+}
+
+// scanFile verifies a source file with the code engine.
+func scanFile(ctx context.Context, client *qwed.Client, file, lang string) (string, []qwed.CodeFinding, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return "", nil, err
+	}
+	if lang == "" {
+		lang = languageFor(file)
+	}
+	if lang == "" {
+		return "", nil, fmt.Errorf("cannot determine language; use --lang")
+	}
+
+	code := string(data)
+	resp, err := client.VerifyCode(ctx, code, lang)
+	if err != nil {
+		return "", nil, err
+	}
+	return code, qwed.CodeFindings(resp), nil
+}
@@ -0,0 +1,395 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+	"github.com/QWED-AI/qwed-verification/sdk-go/github"
+)
+
+// maxBatchItems is the largest batch the API accepts in one request.
+const maxBatchItems = 100
+
+// batchLine is one line of batch output.
+type batchLine struct {
+	Index    int                    `json:"index"`
+	Query    string                 `json:"query"`
+	Type     qwed.VerificationType  `json:"type,omitempty"`
+	Status   qwed.Status            `json:"status,omitempty"`
+	Verified bool                   `json:"verified"`
+	Result   map[string]interface{} `json:"result,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+func runBatch(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	concurrency := fs.Int("concurrency", 4, "batches submitted at once")
+	out := fs.String("out", "", "results file (default: stdout)")
+	poll := fs.Duration("poll", time.Second, "interval between job status checks")
+	noProgress := fs.Bool("no-progress", false, "disable the progress bar")
+	prComment := fs.Bool("pr-comment", false, "post a summary as a pull request comment (GitHub Actions, needs GITHUB_TOKEN)")
+	checkpoint := fs.String("checkpoint", "", "directory saving batch progress, so a rerun resumes an interrupted run")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return 2
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "usage: qwed batch [flags] input.jsonl|input.csv")
+		return 2
+	}
+	input := positional[0]
+
+	items, err := readBatchFile(input)
+	if err != nil {
+		fmt.Fprintf(stderr, "qwed: %v\n", err)
+		return 2
+	}
+	if len(items) == 0 {
+		fmt.Fprintln(stderr, "qwed: no items in input")
+		return 2
+	}
+
+	w := stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(stderr, "qwed: %v\n", err)
+			return 2
+		}
+		defer f.Close()
+		w = f
+	}
+
+	var store qwed.Store
+	if *checkpoint != "" {
+		if err := os.MkdirAll(*checkpoint, 0o755); err != nil {
+			fmt.Fprintf(stderr, "qwed: %v\n", err)
+			return 2
+		}
+		store = qwed.NewFileStore(*checkpoint)
+	}
+
+	var bar *progressBar
+	if !*noProgress {
+		bar = &progressBar{w: stderr, total: len(items)}
+	}
+	lines, finished := runBatches(ctx, newClient(), store, items, *concurrency, *poll, bar)
+	bar.finish()
+
+	enc := json.NewEncoder(w)
+	verified, failed, errored := 0, 0, 0
+	for _, line := range lines {
+		if err := enc.Encode(line); err != nil {
+			fmt.Fprintf(stderr, "qwed: %v\n", err)
+			return 2
+		}
+		switch {
+		case line.Error != "":
+			errored++
+		case line.Verified:
+			verified++
+		default:
+			failed++
+		}
+	}
+
+	if store != nil && finished {
+		clearCheckpoints(ctx, store, items)
+	}
+
+	fmt.Fprintf(stderr, "total %d, verified %d, failed %d, errors %d, success rate %.1f%%\n",
+		len(lines), verified, failed, errored, 100*float64(verified)/float64(len(lines)))
+	// The runner reads workflow commands from stderr too, so they go there
+	// when stdout carries the JSONL results.
+	commands := stdout
+	if *out == "" {
+		commands = stderr
+	}
+	if err := reportBatch(githubActions(commands), input, lines, verified); err != nil {
+		fmt.Fprintf(stderr, "qwed: %v\n", err)
+		return 2
+	}
+	if *prComment {
+		report := github.Report{Title: "QWED batch `" + input + "`", Checked: len(lines), Findings: batchFindings(items, lines)}
+		if err := postPRComment(ctx, "batch:"+input, report); err != nil {
+			fmt.Fprintf(stderr, "qwed: %v\n", err)
+			return 2
+		}
+	}
+	if errored > 0 && verified+failed == 0 {
+		return 2
+	}
+	if verified < len(lines) {
+		return 1
+	}
+	return 0
+}
+
+// reportBatch annotates failed items and writes the job summary when
+// running under GitHub Actions.
+func reportBatch(a *actionsReporter, input string, lines []batchLine, verified int) error {
+	if a == nil {
+		return nil
+	}
+
+	var rows [][]string
+	for _, line := range lines {
+		if line.Verified {
+			continue
+		}
+		message := "verification failed: " + line.Query
+		if line.Error != "" {
+			message = line.Error + ": " + line.Query
+		}
+		a.error(annotation{File: input, Title: fmt.Sprintf("qwed batch item %d", line.Index), Message: message})
+		rows = append(rows, []string{fmt.Sprint(line.Index), string(line.Type), line.Query, line.Error})
+	}
+
+	summary := fmt.Sprintf("### QWED batch `%s`\n\n%d of %d items verified.\n\n", input, verified, len(lines))
+	if len(rows) > 0 {
+		summary += markdownTable([]string{"Item", "Type", "Query", "Error"}, rows) + "\n"
+	}
+	return a.finish(summary, verified == len(lines), len(lines)-verified)
+}
+
+// batchFindings returns the PR comment findings for the items that did not
+// verify.
+func batchFindings(items []qwed.BatchItem, lines []batchLine) []github.Finding {
+	batch := &qwed.BatchResponse{Items: make([]qwed.BatchResult, len(lines))}
+	for i, line := range lines {
+		batch.Items[i] = qwed.BatchResult{Status: line.Status, Verified: line.Verified, Result: line.Result}
+		if line.Error != "" {
+			batch.Items[i].Error = &qwed.ErrorInfo{Message: line.Error}
+		}
+	}
+	return github.FromBatch(items, batch)
+}
+
+// runBatches splits items into API-sized batches, submits up to concurrency
+// of them at a time and polls unfinished jobs. Results keep input order.
+// finished reports whether every batch ran to completion.
+func runBatches(ctx context.Context, client *qwed.Client, store qwed.Store, items []qwed.BatchItem, concurrency int, poll time.Duration, bar *progressBar) (lines []batchLine, finished bool) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	lines = make([]batchLine, len(items))
+	for i, item := range items {
+		lines[i] = batchLine{Index: i, Query: item.Query, Type: item.Type}
+	}
+
+	var wg sync.WaitGroup
+	var failed atomic.Bool
+	sem := make(chan struct{}, concurrency)
+	for start := 0; start < len(items); start += maxBatchItems {
+		end := start + maxBatchItems
+		if end > len(items) {
+			end = len(items)
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, err := submitBatch(ctx, client, store, items[start:end], poll)
+			if err != nil {
+				failed.Store(true)
+			}
+			for i := start; i < end; i++ {
+				line := &lines[i]
+				switch {
+				case err != nil:
+					line.Error = err.Error()
+				case i-start < len(resp.Items):
+					r := resp.Items[i-start]
+					line.Status, line.Verified, line.Result = r.Status, r.Verified, r.Result
+					if r.Error != nil {
+						line.Error = r.Error.Message
+					}
+				default:
+					line.Error = "missing from batch results"
+				}
+			}
+			bar.add(end - start)
+		}(start, end)
+	}
+	wg.Wait()
+	return lines, !failed.Load()
+}
+
+// submitBatch runs one batch to completion, polling while the job is
+// pending or processing. With a checkpoint store, the job ID is saved once
+// the batch is submitted and the results once it finishes, so a rerun
+// reuses finished batches and polls submitted ones instead of submitting
+// them again.
+func submitBatch(ctx context.Context, client *qwed.Client, store qwed.Store, items []qwed.BatchItem, poll time.Duration) (*qwed.BatchResponse, error) {
+	key := checkpointKey(items)
+	saved := loadCheckpoint(ctx, store, key)
+	if saved.Response != nil {
+		return saved.Response, nil
+	}
+
+	var resp *qwed.BatchResponse
+	var err error
+	if saved.JobID != "" {
+		resp, err = client.GetBatch(ctx, saved.JobID)
+	}
+	if saved.JobID == "" || err != nil {
+		if resp, err = client.VerifyBatch(ctx, items, nil); err != nil {
+			return nil, err
+		}
+		saveCheckpoint(ctx, store, key, batchCheckpoint{JobID: resp.JobID})
+	}
+	for resp.Status == qwed.BatchPending || resp.Status == qwed.BatchProcessing {
+		select {
+		case <-time.After(poll):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if resp, err = client.GetBatch(ctx, resp.JobID); err != nil {
+			return nil, err
+		}
+	}
+	saveCheckpoint(ctx, store, key, batchCheckpoint{JobID: resp.JobID, Response: resp})
+	return resp, nil
+}
+
+// batchCheckpoint is the progress of one batch saved by --checkpoint: its
+// job ID once submitted, and its results once finished.
+type batchCheckpoint struct {
+	JobID    string              `json:"job_id"`
+	Response *qwed.BatchResponse `json:"response,omitempty"`
+}
+
+// checkpointKey identifies a batch by its items, so a rerun finds it even
+// if other rows of the input changed.
+func checkpointKey(items []qwed.BatchItem) string {
+	data, _ := json.Marshal(items)
+	sum := sha256.Sum256(data)
+	return "batches/" + hex.EncodeToString(sum[:]) + ".json"
+}
+
+// loadCheckpoint returns the saved progress of the batch with key. A
+// missing or unreadable checkpoint starts the batch afresh.
+func loadCheckpoint(ctx context.Context, store qwed.Store, key string) batchCheckpoint {
+	var saved batchCheckpoint
+	if store == nil {
+		return saved
+	}
+	if data, err := store.Get(ctx, key); err == nil && json.Unmarshal(data, &saved) != nil {
+		saved = batchCheckpoint{}
+	}
+	return saved
+}
+
+// saveCheckpoint saves the progress of the batch with key. Failures are
+// ignored; the batch is then submitted again on resume.
+func saveCheckpoint(ctx context.Context, store qwed.Store, key string, saved batchCheckpoint) {
+	if store == nil {
+		return
+	}
+	if data, err := json.Marshal(saved); err == nil {
+		store.Put(ctx, key, data)
+	}
+}
+
+// clearCheckpoints deletes the checkpoints of a run whose batches all
+// finished, so rerunning the same input verifies it again.
+func clearCheckpoints(ctx context.Context, store qwed.Store, items []qwed.BatchItem) {
+	for start := 0; start < len(items); start += maxBatchItems {
+		store.Delete(ctx, checkpointKey(items[start:min(start+maxBatchItems, len(items))]))
+	}
+}
+
+// readBatchFile reads batch items from a JSONL file, or from a CSV file
+// with a header row containing a "query" column and an optional "type"
+// column.
+func readBatchFile(path string) ([]qwed.BatchItem, error) {
+	if strings.ToLower(filepath.Ext(path)) != ".csv" {
+		return qwed.LoadSeedFile(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	queryCol, typeCol := -1, -1
+	for i, name := range rows[0] {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "query":
+			queryCol = i
+		case "type":
+			typeCol = i
+		}
+	}
+	if queryCol < 0 {
+		return nil, errors.New("CSV header has no query column")
+	}
+
+	items := make([]qwed.BatchItem, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		item := qwed.BatchItem{Query: row[queryCol]}
+		if typeCol >= 0 {
+			item.Type = qwed.VerificationType(strings.TrimSpace(row[typeCol]))
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// progressBar draws a single-line progress indicator. A nil bar draws
+// nothing.
+type progressBar struct {
+	mu    sync.Mutex
+	w     io.Writer
+	total int
+	done  int
+}
+
+const progressWidth = 30
+
+func (p *progressBar) add(n int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.done += n
+	filled := progressWidth * p.done / p.total
+	fmt.Fprintf(p.w, "\r[%s%s] %d/%d", strings.Repeat("=", filled), strings.Repeat(" ", progressWidth-filled), p.done, p.total)
+}
+
+func (p *progressBar) finish() {
+	if p == nil {
+		return
+	}
+	fmt.Fprintln(p.w)
+}
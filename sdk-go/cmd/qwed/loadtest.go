@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// loadtestProgressInterval is how often progress is printed during a load
+// test.
+const loadtestProgressInterval = 10 * time.Second
+
+// loadRequest sends one synthetic verification call.
+type loadRequest func(ctx context.Context, v qwed.Verifier) (*qwed.VerificationResponse, error)
+
+// loadGenerators create synthetic requests for each engine. Every request
+// should verify, so failed verifications point at the deployment rather
+// than the traffic.
+var loadGenerators = map[string]func(r *rand.Rand) loadRequest{
+	"math": func(r *rand.Rand) loadRequest {
+		a, b := r.Intn(1000), r.Intn(1000)
+		return func(ctx context.Context, v qwed.Verifier) (*qwed.VerificationResponse, error) {
+			return v.VerifyMath(ctx, fmt.Sprintf("%d + %d = %d", a, b, a+b))
+		}
+	},
+	"logic": func(r *rand.Rand) loadRequest {
+		formulas := []string{"(A AND B) IMPLIES A", "A OR NOT A", "((A IMPLIES B) AND A) IMPLIES B"}
+		formula := formulas[r.Intn(len(formulas))]
+		return func(ctx context.Context, v qwed.Verifier) (*qwed.VerificationResponse, error) {
+			return v.VerifyLogic(ctx, formula)
+		}
+	},
+	"sql": func(r *rand.Rand) loadRequest {
+		id := r.Intn(100000)
+		return func(ctx context.Context, v qwed.Verifier) (*qwed.VerificationResponse, error) {
+			return v.VerifySQL(ctx, fmt.Sprintf("SELECT id, name FROM users WHERE id = %d", id),
+				"CREATE TABLE users (id INT PRIMARY KEY, name TEXT)", "postgresql")
+		}
+	},
+	"fact": func(r *rand.Rand) loadRequest {
+		order, total := r.Intn(100000), r.Intn(1000)
+		return func(ctx context.Context, v qwed.Verifier) (*qwed.VerificationResponse, error) {
+			return v.VerifyFact(ctx, fmt.Sprintf("The total of order %d is %d dollars.", order, total),
+				fmt.Sprintf("Order %d was shipped on Monday. Its total is %d dollars.", order, total))
+		}
+	},
+	"code": func(r *rand.Rand) loadRequest {
+		n := r.Intn(100)
+		return func(ctx context.Context, v qwed.Verifier) (*qwed.VerificationResponse, error) {
+			return v.VerifyCode(ctx, fmt.Sprintf("def add(a, b):\n    return a + b + %d\n", n), "python")
+		}
+	},
+	"nl": func(r *rand.Rand) loadRequest {
+		pct, n := r.Intn(100), r.Intn(1000)
+		return func(ctx context.Context, v qwed.Verifier) (*qwed.VerificationResponse, error) {
+			return v.Verify(ctx, fmt.Sprintf("What is %d%% of %d?", pct, n))
+		}
+	},
+}
+
+// loadMix is a weighted choice of engines.
+type loadMix struct {
+	engines []string
+	weights []float64 // cumulative, ending at 1
+}
+
+// parseMix parses "math=0.5,sql=0.3,fact=0.2". Weights are normalised, so
+// they need not sum to 1.
+func parseMix(s string) (*loadMix, error) {
+	weights := map[string]float64{}
+	total := 0.0
+	for _, part := range strings.Split(s, ",") {
+		engine, weight, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q: want engine=weight", part)
+		}
+		if _, known := loadGenerators[engine]; !known {
+			return nil, fmt.Errorf("unknown engine %q in mix", engine)
+		}
+		w, err := strconv.ParseFloat(weight, 64)
+		if err != nil || w <= 0 {
+			return nil, fmt.Errorf("invalid weight %q for %s", weight, engine)
+		}
+		weights[engine] += w
+		total += w
+	}
+
+	mix := &loadMix{}
+	for engine := range weights {
+		mix.engines = append(mix.engines, engine)
+	}
+	sort.Strings(mix.engines)
+	cumulative := 0.0
+	for _, engine := range mix.engines {
+		cumulative += weights[engine] / total
+		mix.weights = append(mix.weights, cumulative)
+	}
+	mix.weights[len(mix.weights)-1] = 1
+	return mix, nil
+}
+
+// pick chooses an engine.
+func (m *loadMix) pick(r *rand.Rand) string {
+	x := r.Float64()
+	for i, w := range m.weights {
+		if x < w {
+			return m.engines[i]
+		}
+	}
+	return m.engines[len(m.engines)-1]
+}
+
+// loadStats summarises the calls to one engine, or all engines.
+type loadStats struct {
+	Engine     string         `json:"engine"`
+	Requests   int            `json:"requests"`
+	Failed     int            `json:"failed"` // completed but not verified
+	Errors     int            `json:"errors"`
+	ErrorRate  float64        `json:"error_rate"`
+	P50Ms      float64        `json:"p50_ms"`
+	P90Ms      float64        `json:"p90_ms"`
+	P95Ms      float64        `json:"p95_ms"`
+	P99Ms      float64        `json:"p99_ms"`
+	MaxMs      float64        `json:"max_ms"`
+	ErrorKinds map[string]int `json:"error_kinds,omitempty"`
+
+	latencies []time.Duration
+}
+
+func (s *loadStats) record(latency time.Duration, resp *qwed.VerificationResponse, err error) {
+	s.Requests++
+	s.latencies = append(s.latencies, latency)
+	switch {
+	case err != nil:
+		s.Errors++
+		if s.ErrorKinds == nil {
+			s.ErrorKinds = map[string]int{}
+		}
+		s.ErrorKinds[errorKind(err)]++
+	case !resp.Verified:
+		s.Failed++
+	}
+}
+
+// finish computes the rates and percentiles.
+func (s *loadStats) finish() {
+	if s.Requests == 0 {
+		return
+	}
+	s.ErrorRate = float64(s.Errors) / float64(s.Requests)
+	sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+	s.P50Ms = percentileMs(s.latencies, 50)
+	s.P90Ms = percentileMs(s.latencies, 90)
+	s.P95Ms = percentileMs(s.latencies, 95)
+	s.P99Ms = percentileMs(s.latencies, 99)
+	s.MaxMs = percentileMs(s.latencies, 100)
+}
+
+// percentileMs returns the nearest-rank percentile of sorted latencies in
+// milliseconds.
+func percentileMs(sorted []time.Duration, p float64) float64 {
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return float64(sorted[rank].Microseconds()) / 1000
+}
+
+// errorKind classifies a call error for the report.
+func errorKind(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, qwed.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, qwed.ErrQuotaExceeded):
+		return "quota_exceeded"
+	case errors.Is(err, qwed.ErrEngineUnavailable):
+		return "unavailable"
+	case errors.Is(err, qwed.ErrUnauthorized):
+		return "unauthorized"
+	case errors.Is(err, qwed.ErrInvalidRequest):
+		return "invalid_request"
+	}
+	return "other"
+}
+
+// loadReport is the result of a load test.
+type loadReport struct {
+	TargetRPS   float64      `json:"target_rps"`
+	AchievedRPS float64      `json:"achieved_rps"`
+	DurationSec float64      `json:"duration_sec"`
+	Sent        int          `json:"sent"`
+	Dropped     int          `json:"dropped"` // not sent because max-inflight calls were pending
+	Engines     []*loadStats `json:"engines"`
+	Total       *loadStats   `json:"total"`
+}
+
+func runLoadtest(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	rps := fs.Float64("rps", 10, "requests per second to send")
+	duration := fs.Duration("duration", time.Minute, "how long to send traffic")
+	mixFlag := fs.String("mix", "math=1", "engine weights, e.g. math=0.5,sql=0.3,fact=0.2")
+	maxInflight := fs.Int("max-inflight", 256, "pending calls above which requests are dropped")
+	timeout := fs.Duration("timeout", 10*time.Second, "per-request timeout")
+	maxErrorRate := fs.Float64("max-error-rate", 1, "exit 1 if the overall error rate is higher")
+	seed := fs.Int64("seed", 0, "random seed for synthetic traffic (default: time-based)")
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 || *rps <= 0 || *duration <= 0 || *maxInflight <= 0 {
+		fmt.Fprintln(stderr, "usage: qwed loadtest --rps 200 --duration 5m --mix math=0.5,sql=0.3,fact=0.2")
+		return 2
+	}
+	mix, err := parseMix(*mixFlag)
+	if err != nil {
+		fmt.Fprintf(stderr, "qwed: %v\n", err)
+		return 2
+	}
+	if *seed == 0 {
+		*seed = time.Now().UnixNano()
+	}
+
+	report := loadtest(ctx, newClient(), loadtestConfig{
+		rps:         *rps,
+		duration:    *duration,
+		mix:         mix,
+		maxInflight: *maxInflight,
+		timeout:     *timeout,
+		rand:        rand.New(rand.NewSource(*seed)),
+		progress:    stderr,
+	})
+
+	if *asJSON {
+		if err := json.NewEncoder(stdout).Encode(report); err != nil {
+			fmt.Fprintf(stderr, "qwed: %v\n", err)
+			return 2
+		}
+	} else {
+		writeLoadReport(stdout, report)
+	}
+	if report.Total.ErrorRate > *maxErrorRate {
+		fmt.Fprintf(stderr, "error rate %.2f%% exceeds %.2f%%\n", 100*report.Total.ErrorRate, 100*(*maxErrorRate))
+		return 1
+	}
+	return 0
+}
+
+type loadtestConfig struct {
+	rps         float64
+	duration    time.Duration
+	mix         *loadMix
+	maxInflight int
+	timeout     time.Duration
+	rand        *rand.Rand
+	progress    io.Writer
+}
+
+// loadtest sends open-loop traffic at cfg.rps until cfg.duration has passed
+// or ctx is done, then waits for pending calls. Latencies include calls that
+// failed.
+func loadtest(ctx context.Context, v qwed.Verifier, cfg loadtestConfig) *loadReport {
+	var mu sync.Mutex
+	stats := map[string]*loadStats{}
+	total := &loadStats{Engine: "total"}
+	for _, engine := range cfg.mix.engines {
+		stats[engine] = &loadStats{Engine: engine}
+	}
+
+	report := &loadReport{TargetRPS: cfg.rps}
+	inflight := make(chan struct{}, cfg.maxInflight)
+	var wg sync.WaitGroup
+
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.rps))
+	defer ticker.Stop()
+	progress := time.NewTicker(loadtestProgressInterval)
+	defer progress.Stop()
+	start := time.Now()
+	deadline := time.NewTimer(cfg.duration)
+	defer deadline.Stop()
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-deadline.C:
+			break loop
+		case <-progress.C:
+			mu.Lock()
+			fmt.Fprintf(cfg.progress, "%s: %d sent, %d completed, %d errors, %d dropped\n",
+				time.Since(start).Round(time.Second), report.Sent, total.Requests, total.Errors, report.Dropped)
+			mu.Unlock()
+		case <-ticker.C:
+			engine := cfg.mix.pick(cfg.rand)
+			send := loadGenerators[engine](cfg.rand)
+			select {
+			case inflight <- struct{}{}:
+			default:
+				mu.Lock()
+				report.Dropped++
+				mu.Unlock()
+				continue
+			}
+			mu.Lock()
+			report.Sent++
+			mu.Unlock()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-inflight }()
+				rctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+				defer cancel()
+				began := time.Now()
+				resp, err := send(rctx, v)
+				latency := time.Since(began)
+
+				mu.Lock()
+				defer mu.Unlock()
+				stats[engine].record(latency, resp, err)
+				total.record(latency, resp, err)
+			}()
+		}
+	}
+	elapsed := time.Since(start)
+	wg.Wait()
+
+	report.DurationSec = elapsed.Seconds()
+	report.AchievedRPS = float64(report.Sent) / elapsed.Seconds()
+	for _, engine := range cfg.mix.engines {
+		stats[engine].finish()
+		report.Engines = append(report.Engines, stats[engine])
+	}
+	total.finish()
+	report.Total = total
+	return report
+}
+
+// writeLoadReport prints a load test report as a table.
+func writeLoadReport(w io.Writer, r *loadReport) {
+	fmt.Fprintf(w, "sent %d requests in %.1fs (%.1f rps, target %.1f), %d dropped\n\n",
+		r.Sent, r.DurationSec, r.AchievedRPS, r.TargetRPS, r.Dropped)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "engine\trequests\tfailed\terrors\terror%\tp50 ms\tp90 ms\tp95 ms\tp99 ms\tmax ms\t")
+	for _, s := range append(r.Engines, r.Total) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.2f\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t\n",
+			s.Engine, s.Requests, s.Failed, s.Errors, 100*s.ErrorRate, s.P50Ms, s.P90Ms, s.P95Ms, s.P99Ms, s.MaxMs)
+	}
+	tw.Flush()
+
+	if len(r.Total.ErrorKinds) > 0 {
+		kinds := make([]string, 0, len(r.Total.ErrorKinds))
+		for kind, n := range r.Total.ErrorKinds {
+			kinds = append(kinds, fmt.Sprintf("%s=%d", kind, n))
+		}
+		sort.Strings(kinds)
+		fmt.Fprintf(w, "\nerrors: %s\n", strings.Join(kinds, " "))
+	}
+}
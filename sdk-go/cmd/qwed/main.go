@@ -0,0 +1,130 @@
+// Command qwed is a command-line interface to the QWED Verification API.
+//
+// Usage:
+//
+//	qwed verify math "2+2=4"
+//	qwed verify code --lang python file.py
+//	qwed verify sql --schema schema.sql query.sql
+//	qwed verify code --pr-comment file.py
+//	qwed batch --concurrency 8 --out results.jsonl --checkpoint .qwed input.jsonl
+//	qwed baseline generate [flags] files...
+//	qwed baseline update   [flags] files...
+//	qwed baseline check    [flags] files...
+//	qwed replay --base-url https://staging.example.com dump.json
+//	qwed loadtest --rps 200 --duration 5m --mix math=0.5,sql=0.3,fact=0.2
+//
+// verify exits 1 when verification fails; pass --json for the full response.
+// The API key is read from QWED_API_KEY and the base URL from QWED_BASE_URL.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+const usage = `Usage: qwed <command> [arguments]
+
+Commands:
+  verify <engine>     Verify a claim, file or query (math, logic, fact, code, sql, nl)
+  batch <file>        Verify every row of a JSONL or CSV file
+  baseline generate   Record current code findings as accepted
+  baseline update     Refresh a baseline, dropping fixed findings
+  baseline check      Fail only on findings missing from the baseline
+  replay <dump>...    Re-send captured requests and diff the responses
+  loadtest            Send synthetic traffic and report latency percentiles
+
+Environment:
+  QWED_API_KEY    API key
+  QWED_BASE_URL   API base URL (default http://localhost:8000)
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the CLI and returns the process exit code: 0 on success,
+// 1 when verification fails, and 2 on usage or runtime errors.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "verify":
+		return runVerify(ctx, args[1:], stdout, stderr)
+	case "batch":
+		return runBatch(ctx, args[1:], stdout, stderr)
+	case "baseline":
+		return runBaseline(ctx, args[1:], stdout, stderr)
+	case "replay":
+		return runReplay(ctx, args[1:], stdout, stderr)
+	case "loadtest":
+		return runLoadtest(ctx, args[1:], stdout, stderr)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "qwed: unknown command %q\n\n%s", args[0], usage)
+	return 2
+}
+
+// newClient creates a client from the environment. extra options are
+// applied last, so they override the environment.
+func newClient(extra ...qwed.ClientOption) *qwed.Client {
+	var opts []qwed.ClientOption
+	if url := os.Getenv("QWED_BASE_URL"); url != "" {
+		opts = append(opts, qwed.WithBaseURL(url))
+	}
+	return qwed.NewClient(os.Getenv("QWED_API_KEY"), append(opts, extra...)...)
+}
+
+// parseArgs parses flags from args, allowing them before, between and after
+// positional arguments. Arguments following "--" are always positional.
+// The positional arguments are returned in order.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// languageFor guesses the code engine language from a file extension.
+func languageFor(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".py":
+		return "python"
+	case ".js", ".mjs", ".cjs", ".ts", ".tsx", ".jsx":
+		return "javascript"
+	case ".java":
+		return "java"
+	case ".go":
+		return "go"
+	case ".sql":
+		return "sql"
+	}
+	return ""
+}
@@ -0,0 +1,563 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// TestMain disables GitHub Actions output so tests behave the same when the
+// suite itself runs in a workflow.
+func TestMain(m *testing.M) {
+	os.Unsetenv("GITHUB_ACTIONS")
+	os.Exit(m.Run())
+}
+
+// codeServer reports an eval_usage finding on every line containing "eval".
+func codeServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Code string `json:"code"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var issues []map[string]interface{}
+		for i, line := range strings.Split(req.Code, "\n") {
+			if strings.Contains(line, "eval") {
+				issues = append(issues, map[string]interface{}{
+					"severity": "CRITICAL", "type": "eval_usage", "line_number": i + 1,
+				})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": len(issues) == 0,
+			"result":   map[string]interface{}{"issues": issues},
+		})
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("QWED_BASE_URL", server.URL)
+}
+
+func TestBaselineCommands(t *testing.T) {
+	codeServer(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "app.py")
+	baseline := filepath.Join(dir, "baseline.json")
+	os.WriteFile(src, []byte("x = eval(a)\n"), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"baseline", "generate", "--baseline", baseline, src}, &stdout, &stderr); code != 0 {
+		t.Fatalf("generate exited %d: %s", code, stderr.String())
+	}
+
+	if code := run(context.Background(), []string{"baseline", "check", "--baseline", baseline, src}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected baselined finding to pass, exited %d: %s", code, stderr.String())
+	}
+
+	os.WriteFile(src, []byte("x = eval(a)\ny = eval(b)\n"), 0o644)
+	stdout.Reset()
+	if code := run(context.Background(), []string{"baseline", "check", "--baseline", baseline, src}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected new finding to fail with 1, got %d", code)
+	}
+	if !strings.Contains(stdout.String(), "app.py:2: [CRITICAL] eval_usage") {
+		t.Errorf("unexpected check output: %q", stdout.String())
+	}
+}
+
+func TestLanguageFor(t *testing.T) {
+	for file, want := range map[string]string{"a.py": "python", "b.ts": "javascript", "c.go": "go", "d.txt": ""} {
+		if got := languageFor(file); got != want {
+			t.Errorf("languageFor(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestVerifyCommand(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		json.NewDecoder(r.Body).Decode(&got)
+		verified := r.URL.Path == "/verify/math" && got["expression"] == "2+2=4"
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   map[bool]string{true: "VERIFIED", false: "FAILED"}[verified],
+			"verified": verified,
+			"engine":   strings.TrimPrefix(r.URL.Path, "/verify/"),
+		})
+	}))
+	defer server.Close()
+	t.Setenv("QWED_BASE_URL", server.URL)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"verify", "math", "2+2=4"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected verified math to exit 0, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "VERIFIED (math)") {
+		t.Errorf("unexpected output: %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run(context.Background(), []string{"verify", "math", "--json", "2+2=5"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected failed math to exit 1, got %d", code)
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil || resp["verified"] != false {
+		t.Errorf("expected JSON response, got %q", stdout.String())
+	}
+
+	// Flags after the claim are parsed, not sent as part of it.
+	stdout.Reset()
+	if code := run(context.Background(), []string{"verify", "math", "2+2=4", "--json"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected trailing --json to be parsed, got exit %d: %v", code, got)
+	}
+	if got["expression"] != "2+2=4" {
+		t.Errorf("expected the claim without flags, got %v", got["expression"])
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		t.Errorf("expected JSON response, got %q", stdout.String())
+	}
+
+	dir := t.TempDir()
+	query := filepath.Join(dir, "query.sql")
+	schema := filepath.Join(dir, "schema.sql")
+	os.WriteFile(query, []byte("SELECT id FROM users"), 0o644)
+	os.WriteFile(schema, []byte("CREATE TABLE users (id INT)"), 0o644)
+	run(context.Background(), []string{"verify", "sql", "--schema", schema, query}, &stdout, &stderr)
+	if got["query"] != "SELECT id FROM users" || got["schema_ddl"] != "CREATE TABLE users (id INT)" {
+		t.Errorf("unexpected sql request: %v", got)
+	}
+
+	stdin = strings.NewReader("p OR NOT p\n")
+	defer func() { stdin = os.Stdin }()
+	run(context.Background(), []string{"verify", "logic"}, &stdout, &stderr)
+	if got["query"] != "p OR NOT p" {
+		t.Errorf("expected query from stdin, got %v", got)
+	}
+
+	if code := run(context.Background(), []string{"verify", "sql", query}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected missing --schema to exit 2, got %d", code)
+	}
+}
+
+func TestVerifyCodeCommand(t *testing.T) {
+	codeServer(t)
+	src := filepath.Join(t.TempDir(), "app.py")
+	os.WriteFile(src, []byte("x = eval(a)\n"), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"verify", "code", src}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected finding to exit 1, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "line 1: [CRITICAL] eval_usage") {
+		t.Errorf("unexpected output: %q", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run(context.Background(), []string{"verify", "code", src, "--json"}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected trailing --json to be parsed, got exit %d: %s", code, stderr.String())
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		t.Errorf("expected JSON response, got %q", stdout.String())
+	}
+}
+
+func TestVerifyCodeAllSuppressed(t *testing.T) {
+	codeServer(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "app.py")
+	os.WriteFile(src, []byte("x = eval(a)  # qwed:ignore eval_usage reason=trusted input\n"), 0o644)
+	output := filepath.Join(dir, "output")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_STEP_SUMMARY", filepath.Join(dir, "summary.md"))
+	t.Setenv("GITHUB_OUTPUT", output)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"verify", "code", src}, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected suppressed findings to exit 0, got %d: %s", code, stdout.String())
+	}
+	if strings.Contains(stdout.String(), "::error") {
+		t.Errorf("expected no annotations for suppressed findings, got %q", stdout.String())
+	}
+	if out, _ := os.ReadFile(output); string(out) != "verified=true\nfailed_count=0\n" {
+		t.Errorf("unexpected outputs: %q", out)
+	}
+}
+
+// batchJobs is a fake batch API. Items whose query contains "wrong" fail
+// verification, and submissions containing "reject" are refused.
+type batchJobs struct {
+	mu    sync.Mutex
+	jobs  map[string][]map[string]interface{}
+	posts int
+}
+
+func batchServer(t *testing.T) *batchJobs {
+	b := &batchJobs{jobs: make(map[string][]map[string]interface{})}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		if r.Method == "POST" {
+			var req struct {
+				Items []map[string]interface{} `json:"items"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			b.posts++
+			for _, item := range req.Items {
+				if strings.Contains(item["query"].(string), "reject") {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+			}
+			id := fmt.Sprintf("job-%d", len(b.jobs))
+			b.jobs[id] = req.Items
+			json.NewEncoder(w).Encode(map[string]interface{}{"job_id": id, "status": "processing"})
+			return
+		}
+
+		var items []map[string]interface{}
+		for _, item := range b.jobs[strings.TrimPrefix(r.URL.Path, "/verify/batch/")] {
+			verified := !strings.Contains(item["query"].(string), "wrong")
+			items = append(items, map[string]interface{}{"status": "VERIFIED", "verified": verified})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "completed", "items": items})
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("QWED_BASE_URL", server.URL)
+	return b
+}
+
+func TestBatchCommand(t *testing.T) {
+	jobs := batchServer(t).jobs
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.jsonl")
+	var rows strings.Builder
+	for i := 0; i < 150; i++ {
+		fmt.Fprintf(&rows, "{\"query\": \"%d + 1 = %d\", \"type\": \"math\"}\n", i, i+1)
+	}
+	rows.WriteString(`{"query": "wrong", "type": "math"}` + "\n")
+	os.WriteFile(input, []byte(rows.String()), 0o644)
+	out := filepath.Join(dir, "results.jsonl")
+
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"batch", "--poll", "1ms", "--out", out, input}, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected one failed item to exit 1, got %d: %s", code, stderr.String())
+	}
+	if len(jobs) != 2 {
+		t.Errorf("expected input split into 2 batches, got %d", len(jobs))
+	}
+	if !strings.Contains(stderr.String(), "151/151") || !strings.Contains(stderr.String(), "verified 150, failed 1") {
+		t.Errorf("unexpected progress and summary: %q", stderr.String())
+	}
+
+	data, _ := os.ReadFile(out)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 151 {
+		t.Fatalf("expected 151 result lines, got %d", len(lines))
+	}
+	var last batchLine
+	json.Unmarshal([]byte(lines[150]), &last)
+	if last.Index != 150 || last.Query != "wrong" || last.Verified {
+		t.Errorf("unexpected last result: %+v", last)
+	}
+
+	// Flags are also accepted after the input file.
+	trailing := filepath.Join(dir, "trailing.jsonl")
+	stderr.Reset()
+	code = run(context.Background(), []string{"batch", input, "--poll", "1ms", "--no-progress", "--out", trailing}, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected trailing flags to be parsed, got exit %d: %s", code, stderr.String())
+	}
+	if strings.Contains(stderr.String(), "151/151") {
+		t.Errorf("expected --no-progress after the input to disable the progress bar: %q", stderr.String())
+	}
+	if data, _ := os.ReadFile(trailing); strings.Count(string(data), "\n") != 151 {
+		t.Errorf("expected 151 result lines in %s, got %q", trailing, data)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "")
+	out := fs.String("out", "", "")
+
+	got, err := parseArgs(fs, []string{"a", "--json", "b", "--out", "x", "--", "--c", "-d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !*asJSON || *out != "x" || strings.Join(got, " ") != "a b --c -d" {
+		t.Errorf("parseArgs: json=%t out=%q positional=%q", *asJSON, *out, got)
+	}
+}
+
+func TestBatchCheckpoint(t *testing.T) {
+	server := batchServer(t)
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.jsonl")
+	checkpoints := filepath.Join(dir, "checkpoints")
+	writeInput := func(last string) {
+		var rows strings.Builder
+		for i := 0; i < 200; i++ {
+			fmt.Fprintf(&rows, "{\"query\": \"%d + 1 = %d\", \"type\": \"math\"}\n", i, i+1)
+		}
+		fmt.Fprintf(&rows, "{\"query\": %q, \"type\": \"math\"}\n", last)
+		os.WriteFile(input, []byte(rows.String()), 0o644)
+	}
+	args := []string{"batch", "--poll", "1ms", "--no-progress", "--concurrency", "1", "--checkpoint", checkpoints, "--out", filepath.Join(dir, "out.jsonl"), input}
+
+	// The last batch is refused, so the run is left unfinished.
+	writeInput("reject")
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), args, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected the refused batch to exit 1, got %d: %s", code, stderr.String())
+	}
+	saved, _ := filepath.Glob(filepath.Join(checkpoints, "batches", "*.json"))
+	if len(saved) != 2 {
+		t.Fatalf("expected the two finished batches to be saved, got %v", saved)
+	}
+
+	// The rerun reuses the finished batches and submits only the last one.
+	writeInput("fixed")
+	server.posts = 0
+	if code := run(context.Background(), args, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected the resumed run to exit 0, got %d: %s", code, stderr.String())
+	}
+	if server.posts != 1 {
+		t.Errorf("expected one batch submitted on resume, got %d", server.posts)
+	}
+	if saved, _ := filepath.Glob(filepath.Join(checkpoints, "batches", "*.json")); len(saved) != 0 {
+		t.Errorf("expected checkpoints cleared after a finished run, got %v", saved)
+	}
+}
+
+func TestBatchCheckpointResumesSubmittedJob(t *testing.T) {
+	server := batchServer(t)
+	items := []qwed.BatchItem{{Query: "1 + 1 = 2", Type: qwed.TypeMath}}
+	server.jobs["job-submitted"] = []map[string]interface{}{{"query": items[0].Query}}
+
+	store := qwed.NewMemoryStore()
+	saveCheckpoint(context.Background(), store, checkpointKey(items), batchCheckpoint{JobID: "job-submitted"})
+	resp, err := submitBatch(context.Background(), newClient(), store, items, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.posts != 0 || len(resp.Items) != 1 || !resp.Items[0].Verified {
+		t.Errorf("expected the submitted job to be polled, got %d submissions and %+v", server.posts, resp)
+	}
+	if saved := loadCheckpoint(context.Background(), store, checkpointKey(items)); saved.Response == nil {
+		t.Errorf("expected the finished job to be saved, got %+v", saved)
+	}
+}
+
+func TestReadBatchCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.csv")
+	os.WriteFile(path, []byte("type,query\nmath,\"1,000 + 1 = 1,001\"\n,Is Paris in France?\n"), 0o644)
+
+	items, err := readBatchFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].Query != "1,000 + 1 = 1,001" || items[0].Type != "math" || items[1].Type != "" {
+		t.Errorf("unexpected items: %+v", items)
+	}
+}
+
+func TestGitHubActionsMode(t *testing.T) {
+	codeServer(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "app,v2.py")
+	os.WriteFile(src, []byte("ok = 1\nx = eval(a)\n"), 0o644)
+	summary := filepath.Join(dir, "summary.md")
+	output := filepath.Join(dir, "output")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_STEP_SUMMARY", summary)
+	t.Setenv("GITHUB_OUTPUT", output)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"verify", "code", src}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit 1, got %d: %s", code, stderr.String())
+	}
+
+	want := "::error file=" + strings.ReplaceAll(src, ",", "%2C") + ",line=2,title=eval_usage::eval_usage\n"
+	if !strings.Contains(stdout.String(), want) {
+		t.Errorf("expected workflow command %q, got %q", want, stdout.String())
+	}
+
+	md, _ := os.ReadFile(summary)
+	if !strings.Contains(string(md), "### QWED verify code") || !strings.Contains(string(md), "| 2 | CRITICAL | eval_usage |") {
+		t.Errorf("unexpected job summary: %q", md)
+	}
+	out, _ := os.ReadFile(output)
+	if string(out) != "verified=false\nfailed_count=1\n" {
+		t.Errorf("unexpected outputs: %q", out)
+	}
+}
+
+func TestGitHubActionsJSON(t *testing.T) {
+	codeServer(t)
+	dir := t.TempDir()
+	src := filepath.Join(dir, "app.py")
+	os.WriteFile(src, []byte("x = eval(a)\n"), 0o644)
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_STEP_SUMMARY", filepath.Join(dir, "summary.md"))
+	t.Setenv("GITHUB_OUTPUT", filepath.Join(dir, "output"))
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"verify", "code", "--json", src}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit 1, got %d: %s", code, stderr.String())
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		t.Errorf("expected stdout to be JSON, got %q: %v", stdout.String(), err)
+	}
+	if !strings.Contains(stderr.String(), "::error file=") {
+		t.Errorf("expected workflow commands on stderr, got %q", stderr.String())
+	}
+}
+
+func TestEscapeWorkflowCommand(t *testing.T) {
+	if got := escapeData("50%\nmore"); got != "50%25%0Amore" {
+		t.Errorf("escapeData = %q", got)
+	}
+	if got := escapeProperty("a:b,c"); got != "a%3Ab%2Cc" {
+		t.Errorf("escapeProperty = %q", got)
+	}
+}
+
+func TestReplayCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "FAILED", "verified": false, "engine": "math"})
+	}))
+	defer server.Close()
+	t.Setenv("QWED_BASE_URL", "http://127.0.0.1:0")
+
+	dir := t.TempDir()
+	dump := filepath.Join(dir, "dump.json")
+	os.WriteFile(dump, []byte(`{"op":"VerifyMath","engine":"math","path":"/verify/math","body":{"expression":"2+2=4"},"response":{"status":"VERIFIED","verified":true,"engine":"math"}}`), 0o644)
+
+	var stdout, stderr bytes.Buffer
+	if code := run(context.Background(), []string{"replay", "--base-url", server.URL, dump}, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected changed replay to exit 1, got %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), dump+": ") || !strings.Contains(stdout.String(), "VERIFIED") {
+		t.Errorf("expected diff output, got %q", stdout.String())
+	}
+
+	stdout.Reset()
+	run(context.Background(), []string{"replay", "--base-url", server.URL, "--json", dump}, &stdout, &stderr)
+	var result map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil || result["response"] == nil {
+		t.Errorf("expected JSON replay result, got %q", stdout.String())
+	}
+
+	if code := run(context.Background(), []string{"replay", filepath.Join(dir, "missing.json")}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected missing dump to exit 2, got %d", code)
+	}
+}
+
+func TestParseMix(t *testing.T) {
+	mix, err := parseMix("sql=3,math=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(mix.engines, ",") != "math,sql" || mix.weights[0] != 0.25 || mix.weights[1] != 1 {
+		t.Errorf("unexpected mix: %+v", mix)
+	}
+	for _, bad := range []string{"math", "math=0", "math=x", "image=1"} {
+		if _, err := parseMix(bad); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestLoadtestCommand(t *testing.T) {
+	var mu sync.Mutex
+	paths := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths[r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/verify/fact" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	}))
+	defer server.Close()
+	t.Setenv("QWED_BASE_URL", server.URL)
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"loadtest", "--rps", "200", "--duration", "250ms", "--mix", "math=0.5,fact=0.5", "--seed", "1", "--json"}
+	if code := run(context.Background(), args, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d: %s", code, stderr.String())
+	}
+	var report loadReport
+	if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+		t.Fatalf("expected JSON report, got %q", stdout.String())
+	}
+	if report.Sent == 0 || report.Total.Requests != report.Sent || len(report.Engines) != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	fact := report.Engines[0]
+	if fact.Engine != "fact" || fact.Errors != fact.Requests || fact.ErrorKinds["unavailable"] != fact.Requests {
+		t.Errorf("expected every fact call to fail as unavailable, got %+v", fact)
+	}
+	if math := report.Engines[1]; math.Errors != 0 || math.P95Ms <= 0 {
+		t.Errorf("unexpected math stats: %+v", math)
+	}
+	if paths["/verify/math"] == 0 || paths["/verify/fact"] == 0 {
+		t.Errorf("expected traffic to both engines, got %v", paths)
+	}
+
+	stdout.Reset()
+	args = []string{"loadtest", "--rps", "100", "--duration", "100ms", "--mix", "fact=1", "--max-error-rate", "0.5"}
+	if code := run(context.Background(), args, &stdout, &stderr); code != 1 {
+		t.Errorf("expected exit 1 above the error rate limit, got %d", code)
+	}
+	if !strings.Contains(stdout.String(), "p99 ms") || !strings.Contains(stdout.String(), "errors: unavailable=") {
+		t.Errorf("unexpected table output: %q", stdout.String())
+	}
+}
+
+func TestPercentileMs(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	if p := percentileMs(latencies, 95); p != 95 {
+		t.Errorf("p95 = %v", p)
+	}
+	if p := percentileMs(latencies, 100); p != 100 {
+		t.Errorf("max = %v", p)
+	}
+	if p := percentileMs(latencies[:1], 50); p != 1 {
+		t.Errorf("single p50 = %v", p)
+	}
+}
+
+func TestBatchFindings(t *testing.T) {
+	items := []qwed.BatchItem{{Query: "2+2=4", Type: qwed.TypeMath}, {Query: "2+2=5", Type: qwed.TypeMath}, {Query: "x", Type: qwed.TypeLogic}}
+	lines := []batchLine{
+		{Index: 0, Query: "2+2=4", Verified: true},
+		{Index: 1, Query: "2+2=5", Status: qwed.StatusFailed, Result: map[string]interface{}{"reason": "expected 4"}},
+		{Index: 2, Query: "x", Error: "request timed out"},
+	}
+	findings := batchFindings(items, lines)
+	if len(findings) != 2 || findings[0].Message != "`2+2=5`: expected 4" || findings[1].Message != "`x`: request timed out" {
+		t.Errorf("unexpected findings: %+v", findings)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+func runReplay(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	baseURL := fs.String("base-url", "", "environment to replay against (default: QWED_BASE_URL)")
+	asJSON := fs.Bool("json", false, "print each replay result as a JSON line")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "usage: qwed replay [--base-url URL] [--json] dump.json...")
+		return 2
+	}
+
+	var opts []qwed.ClientOption
+	if *baseURL != "" {
+		opts = append(opts, qwed.WithBaseURL(*baseURL))
+	}
+	client := newClient(opts...)
+
+	enc := json.NewEncoder(stdout)
+	changed := 0
+	for _, path := range fs.Args() {
+		dump, err := qwed.LoadDump(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "qwed: %v\n", err)
+			return 2
+		}
+		result, err := client.Replay(ctx, dump)
+		if err != nil {
+			fmt.Fprintf(stderr, "qwed: %v\n", err)
+			return 2
+		}
+		if result.Changed() {
+			changed++
+		}
+
+		if *asJSON {
+			if err := enc.Encode(result); err != nil {
+				fmt.Fprintf(stderr, "qwed: %v\n", err)
+				return 2
+			}
+			continue
+		}
+		switch {
+		case result.Error != "" && dump.Error == "":
+			fmt.Fprintf(stdout, "%s: now fails: %s\n", path, result.Error)
+		case result.Error == "" && dump.Error != "":
+			fmt.Fprintf(stdout, "%s: recorded error no longer occurs; %s\n", path, result.Diff)
+		default:
+			fmt.Fprintf(stdout, "%s: %s\n", path, result.Diff)
+		}
+	}
+
+	if changed > 0 {
+		fmt.Fprintf(stderr, "%d of %d replays differ from the recording\n", changed, fs.NArg())
+		return 1
+	}
+	return 0
+}
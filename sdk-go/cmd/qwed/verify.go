@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+	"github.com/QWED-AI/qwed-verification/sdk-go/github"
+)
+
+// stdin is read when an input argument is "-" or omitted.
+var stdin io.Reader = os.Stdin
+
+const verifyUsage = `usage: qwed verify <engine> [flags] [input]
+
+Engines:
+  math  "2+2=4"                          Verify an arithmetic claim
+  logic "(A AND B) IMPLIES A"            Check a propositional formula
+  fact  --context ctx.txt "claim"        Verify a claim against context
+  code  [--lang python] file.py          Scan source code for issues
+        [--check crypto]                 ... or only for cryptographic misuse
+  sql   --schema schema.sql query.sql    Validate a query against a schema
+  nl    "What is 15% of 200?"            Natural language verification
+
+Text inputs read standard input when omitted or "-". Flags may follow the
+input; put "--" before an input that starts with "-".
+`
+
+func runVerify(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, verifyUsage)
+		return 2
+	}
+	engine := args[0]
+
+	fs := flag.NewFlagSet("verify "+engine, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asJSON := fs.Bool("json", false, "print the full response as JSON")
+	lang := fs.String("lang", "", "code: source language (default: from file extension)")
+	check := fs.String("check", "", "code: check mode, e.g. crypto for cryptographic misuse")
+	schema := fs.String("schema", "", "sql: schema DDL file")
+	dialect := fs.String("dialect", "postgresql", "sql: SQL dialect")
+	factContext := fs.String("context", "", "fact: file containing the context")
+	prComment := fs.Bool("pr-comment", false, "post the result as a pull request comment (GitHub Actions, needs GITHUB_TOKEN)")
+	positional, err := parseArgs(fs, args[1:])
+	if err != nil {
+		return 2
+	}
+
+	client := newClient()
+	var (
+		resp   *qwed.VerificationResponse
+		source string // input file, for annotations
+	)
+	switch engine {
+	case "math", "logic", "nl":
+		var query string
+		if query, err = readText(positional); err != nil {
+			break
+		}
+		switch engine {
+		case "math":
+			resp, err = client.VerifyMath(ctx, query)
+		case "logic":
+			resp, err = client.VerifyLogic(ctx, query)
+		default:
+			resp, err = client.Verify(ctx, query)
+		}
+	case "fact":
+		if *factContext == "" {
+			fmt.Fprintln(stderr, "qwed: fact requires --context")
+			return 2
+		}
+		var claim, contextText string
+		if claim, err = readText(positional); err != nil {
+			break
+		}
+		if contextText, err = readFile(*factContext); err != nil {
+			break
+		}
+		resp, err = client.VerifyFact(ctx, claim, contextText)
+	case "code":
+		if len(positional) != 1 {
+			fmt.Fprintln(stderr, "qwed: code requires exactly one file")
+			return 2
+		}
+		file := positional[0]
+		source = file
+		if *lang == "" {
+			*lang = languageFor(file)
+		}
+		if *lang == "" {
+			fmt.Fprintln(stderr, "qwed: cannot determine language; use --lang")
+			return 2
+		}
+		var code string
+		if code, err = readFile(file); err != nil {
+			break
+		}
+		var opts *qwed.RequestOptions
+		if *check != "" {
+			opts = &qwed.RequestOptions{CheckMode: qwed.CheckMode(*check)}
+		}
+		resp, err = client.VerifyCodeWithOptions(ctx, code, *lang, opts)
+	case "sql":
+		if *schema == "" || len(positional) != 1 {
+			fmt.Fprintln(stderr, "qwed: sql requires --schema and one query file")
+			return 2
+		}
+		var query, ddl string
+		source = positional[0]
+		if query, err = readFile(source); err != nil {
+			break
+		}
+		if ddl, err = readFile(*schema); err != nil {
+			break
+		}
+		resp, err = client.VerifySQL(ctx, query, ddl, *dialect)
+	default:
+		fmt.Fprintf(stderr, "qwed: unknown engine %q\n\n%s", engine, verifyUsage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "qwed: %v\n", err)
+		return 2
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(resp); err != nil {
+			fmt.Fprintf(stderr, "qwed: %v\n", err)
+			return 2
+		}
+	} else {
+		printResponse(stdout, resp)
+	}
+	// The runner reads workflow commands from stderr too, so they go there
+	// when stdout carries JSON.
+	commands := stdout
+	if *asJSON {
+		commands = stderr
+	}
+	if err := reportVerify(githubActions(commands), engine, source, resp); err != nil {
+		fmt.Fprintf(stderr, "qwed: %v\n", err)
+		return 2
+	}
+	if *prComment {
+		report := github.Report{Title: "QWED verify " + engine, Checked: 1, Findings: github.FromCode(source, resp)}
+		if err := postPRComment(ctx, "verify:"+engine+":"+source, report); err != nil {
+			fmt.Fprintf(stderr, "qwed: %v\n", err)
+			return 2
+		}
+	}
+
+	if !passed(resp) {
+		return 1
+	}
+	return 0
+}
+
+// passed reports whether resp should count as a success. A code scan whose
+// findings are all suppressed passes even though the API did not verify it.
+func passed(resp *qwed.VerificationResponse) bool {
+	if len(qwed.CodeFindings(resp)) > 0 {
+		return len(qwed.UnsuppressedFindings(resp)) == 0
+	}
+	return resp.Verified
+}
+
+// printResponse writes a short human-readable summary of resp.
+func printResponse(w io.Writer, resp *qwed.VerificationResponse) {
+	status := resp.Status
+	if status == "" {
+		status = qwed.StatusFailed
+		if resp.Verified {
+			status = qwed.StatusVerified
+		}
+	}
+	if resp.Engine != "" {
+		fmt.Fprintf(w, "%s (%s)\n", status, resp.Engine)
+	} else {
+		fmt.Fprintln(w, status)
+	}
+
+	if reason := resp.InconclusiveReason(); reason != "" {
+		fmt.Fprintf(w, "  inconclusive: %s\n", reason)
+	}
+	if resp.Error != nil {
+		fmt.Fprintf(w, "  error: %s\n", resp.Error.Message)
+	}
+	for _, f := range qwed.CodeFindings(resp) {
+		if f.Suppressed {
+			continue
+		}
+		fmt.Fprintf(w, "  line %d: [%s] %s: %s\n", f.Line, f.Severity, f.Type, f.Description)
+		for _, ref := range f.References {
+			fmt.Fprintf(w, "    see %s\n", ref)
+		}
+	}
+}
+
+// reportVerify annotates failed verifications and writes the job summary
+// when running under GitHub Actions.
+func reportVerify(a *actionsReporter, engine, source string, resp *qwed.VerificationResponse) error {
+	if a == nil {
+		return nil
+	}
+
+	failed := 0
+	var rows [][]string
+	for _, f := range qwed.UnsuppressedFindings(resp) {
+		message := f.Description
+		if message == "" {
+			message = f.Type
+		}
+		a.error(annotation{File: source, Line: f.Line, Title: f.Type, Message: message})
+		rows = append(rows, []string{fmt.Sprint(f.Line), f.Severity, f.Type, message})
+		failed++
+	}
+	ok := passed(resp)
+	if !ok && failed == 0 {
+		message := "verification failed"
+		if resp.Error != nil {
+			message += ": " + resp.Error.Message
+		}
+		a.error(annotation{File: source, Title: "qwed verify " + engine, Message: message})
+		failed = 1
+	}
+
+	status := "Verified"
+	if !ok {
+		status = "Failed"
+	}
+	summary := fmt.Sprintf("### QWED verify %s\n\n**%s**", engine, status)
+	if source != "" {
+		summary += " `" + source + "`"
+	}
+	summary += "\n\n"
+	if len(rows) > 0 {
+		summary += markdownTable([]string{"Line", "Severity", "Rule", "Description"}, rows) + "\n"
+	}
+	return a.finish(summary, ok, failed)
+}
+
+// readText returns the positional arguments joined by spaces, or standard
+// input if there are none or the only argument is "-". Flags have already
+// been removed from args by parseArgs.
+func readText(args []string) (string, error) {
+	if len(args) == 0 || (len(args) == 1 && args[0] == "-") {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return strings.Join(args, " "), nil
+}
+
+// readFile returns the contents of path, or standard input for "-".
+func readFile(path string) (string, error) {
+	if path == "-" {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return "", fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return string(data), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,83 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ============================================================================
+// Compliance Verification
+// ============================================================================
+
+// ComplianceRuleKind is what a compliance rule asks of a text.
+type ComplianceRuleKind string
+
+const (
+	// RuleProhibited forbids content matching the rule's description,
+	// such as medical advice or guaranteed returns, however it is worded.
+	RuleProhibited ComplianceRuleKind = "prohibited"
+	// RuleRequired requires content, such as a disclaimer, in one of the
+	// rule's Phrases or with the meaning of its description.
+	RuleRequired ComplianceRuleKind = "required"
+)
+
+// ComplianceRule is a rule customer-facing text must follow.
+type ComplianceRule struct {
+	ID          string             `json:"id"` // e.g. "no-medical-advice", reported back in ComplianceResult
+	Kind        ComplianceRuleKind `json:"kind"`
+	Description string             `json:"description"` // e.g. "Do not recommend treatments or dosages"
+	// Phrases are exact wordings: for RuleRequired, accepted wordings of
+	// the required content; for RuleProhibited, examples of what is
+	// forbidden, which need not appear verbatim to match.
+	Phrases []string `json:"phrases,omitempty"`
+}
+
+// ComplianceResult is the verdict on one rule.
+type ComplianceResult struct {
+	RuleID string `json:"rule_id"`
+	Passed bool   `json:"passed"`
+	// Evidence quotes the text that breaks a prohibition or satisfies a
+	// requirement, with its byte offsets in the text.
+	Evidence string `json:"evidence,omitempty"`
+	Start    int    `json:"start,omitempty"`
+	End      int    `json:"end,omitempty"`
+	Reason   string `json:"reason,omitempty"` // e.g. "recommends a dosage of ibuprofen"
+}
+
+// VerifyCompliance checks generated customer-facing text against
+// structured compliance rules, for legal review automation:
+//
+//	rules := []qwed.ComplianceRule{
+//		{ID: "no-medical-advice", Kind: qwed.RuleProhibited, Description: "No diagnoses, treatments or dosages"},
+//		{ID: "no-guarantees", Kind: qwed.RuleProhibited, Description: "No guaranteed investment returns"},
+//		{ID: "risk-disclaimer", Kind: qwed.RuleRequired, Description: "Investment risk disclaimer",
+//			Phrases: []string{"Past performance does not guarantee future results."}},
+//	}
+//	resp, err := client.VerifyCompliance(ctx, reply, rules)
+//
+// The response is verified when every rule passes. Use ComplianceResults
+// to decode the verdict on each rule.
+func (c *Client) VerifyCompliance(ctx context.Context, text string, rules []ComplianceRule, callOpts ...CallOption) (*VerificationResponse, error) {
+	req := map[string]interface{}{
+		"text":  text,
+		"rules": rules,
+	}
+
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return nil, invalidRequest("failed to marshal compliance rules: %v", err)
+	}
+	return c.verify(ctx, "VerifyCompliance", TypeCompliance, CacheKey(TypeCompliance, contentHash(data), text), req, callOpts...)
+}
+
+// ComplianceResults extracts the per-rule verdicts from a VerifyCompliance
+// response, in the order of the rules.
+func ComplianceResults(resp *VerificationResponse) []ComplianceResult {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var results []ComplianceResult
+	decodeResult(resp.Result["rules"], &results)
+	return results
+}
@@ -0,0 +1,91 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+var financeRules = []ComplianceRule{
+	{ID: "no-guarantees", Kind: RuleProhibited, Description: "No guaranteed investment returns"},
+	{ID: "risk-disclaimer", Kind: RuleRequired, Description: "Investment risk disclaimer",
+		Phrases: []string{"Past performance does not guarantee future results."}},
+}
+
+func TestVerifyCompliance(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/compliance" {
+			t.Errorf("expected path /verify/compliance, got %s", r.URL.Path)
+		}
+		var req struct {
+			Text  string           `json:"text"`
+			Rules []ComplianceRule `json:"rules"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Text == "" || len(req.Rules) != 2 || req.Rules[1].Kind != RuleRequired || len(req.Rules[1].Phrases) != 1 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": false,
+			"engine":   "compliance",
+			"result": map[string]interface{}{
+				"rules": []map[string]interface{}{
+					{"rule_id": "no-guarantees", "passed": false, "evidence": "guaranteed 12% a year", "start": 18, "end": 39, "reason": "promises a fixed return"},
+					{"rule_id": "risk-disclaimer", "passed": false, "reason": "no risk disclaimer"},
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyCompliance(context.Background(), "This fund returns guaranteed 12% a year.", financeRules)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Verified || resp.Engine != EngineCompliance {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	results := ComplianceResults(resp)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 rule results, got %+v", results)
+	}
+	if r := results[0]; r.RuleID != "no-guarantees" || r.Passed || r.Evidence != "guaranteed 12% a year" || r.Start != 18 || r.End != 39 {
+		t.Errorf("unexpected result: %+v", r)
+	}
+	if r := results[1]; r.RuleID != "risk-disclaimer" || r.Passed || r.Reason == "" {
+		t.Errorf("unexpected result: %+v", r)
+	}
+	if ComplianceResults(&VerificationResponse{}) != nil {
+		t.Error("expected no results without a result")
+	}
+}
+
+func TestVerifyComplianceStrictValidation(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"), WithStrictValidation())
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		text  string
+		rules []ComplianceRule
+	}{
+		{"empty text", " ", financeRules},
+		{"no rules", "Hello.", nil},
+		{"rule without id", "Hello.", []ComplianceRule{{Kind: RuleProhibited, Description: "x"}}},
+		{"duplicate id", "Hello.", []ComplianceRule{financeRules[0], financeRules[0]}},
+		{"unknown kind", "Hello.", []ComplianceRule{{ID: "a", Kind: "preferred", Description: "x"}}},
+		{"prohibition without description", "Hello.", []ComplianceRule{{ID: "a", Kind: RuleProhibited, Phrases: []string{"x"}}}},
+		{"requirement without content", "Hello.", []ComplianceRule{{ID: "a", Kind: RuleRequired}}},
+	}
+	for _, tt := range tests {
+		if _, err := client.VerifyCompliance(ctx, tt.text, tt.rules); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("%s: expected ErrInvalidRequest, got %v", tt.name, err)
+		}
+	}
+}
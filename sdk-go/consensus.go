@@ -0,0 +1,157 @@
+package qwed
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// Consensus Verification
+// ============================================================================
+
+// ConsensusOptions configures VerifyConsensus.
+type ConsensusOptions struct {
+	// Question is the prompt the outputs answer. It is verified together
+	// with outputs that go to the natural-language engine.
+	Question string
+
+	// FactContext, SchemaDDL and Dialect are passed to the Router that picks
+	// each output's engine.
+	FactContext string
+	SchemaDDL   string
+	Dialect     string
+
+	// Answer extracts the final answer compared across outputs. Defaults to
+	// the last number in the output, or the whole output lowercased with
+	// whitespace collapsed if it has no number.
+	Answer func(output string) string
+
+	// Concurrency limits the number of outputs verified at once.
+	// Defaults to 4.
+	Concurrency int
+}
+
+// ConsensusCandidate is one output's verification and agreement.
+type ConsensusCandidate struct {
+	Index     int                   `json:"index"`
+	Output    string                `json:"output"`
+	Answer    string                `json:"answer"`
+	Route     Route                 `json:"route"`
+	Verified  bool                  `json:"verified"`
+	Response  *VerificationResponse `json:"response,omitempty"`
+	Error     string                `json:"error,omitempty"`
+	Agreement float64               `json:"agreement"` // fraction of outputs with the same answer
+}
+
+// ConsensusResult is the report produced by VerifyConsensus.
+type ConsensusResult struct {
+	Candidates []ConsensusCandidate `json:"candidates"`
+
+	// Best is the verified candidate with the highest agreement, earliest
+	// first on ties, or nil if no candidate verified.
+	Best *ConsensusCandidate `json:"best,omitempty"`
+
+	// MajorityAnswer is the most common answer and MajorityAgreement the
+	// fraction of outputs giving it, whether or not it verified.
+	MajorityAnswer    string  `json:"majority_answer"`
+	MajorityAgreement float64 `json:"majority_agreement"`
+}
+
+// VerifyConsensus cross-checks candidate answers to the same prompt, for
+// example from different models or samples. Each output is verified with
+// the engine a Router picks for it, and outputs are grouped by their final
+// answer to score agreement:
+//
+//	result, err := client.VerifyConsensus(ctx, []string{gpt, claude, llama}, &qwed.ConsensusOptions{Question: q})
+//	if result.Best != nil {
+//	    answer = result.Best.Output
+//	}
+//
+// Per-output errors are recorded on the candidate rather than returned; the
+// error result is only set if ctx is cancelled.
+func (c *Client) VerifyConsensus(ctx context.Context, outputs []string, opts *ConsensusOptions) (*ConsensusResult, error) {
+	var o ConsensusOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Concurrency < 1 {
+		o.Concurrency = 4
+	}
+	if o.Answer == nil {
+		o.Answer = finalAnswer
+	}
+	router := NewRouter(c, WithRouterSQLSchema(o.SchemaDDL, o.Dialect), WithRouterFactContext(o.FactContext))
+
+	result := &ConsensusResult{Candidates: make([]ConsensusCandidate, len(outputs))}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, o.Concurrency)
+	for i, output := range outputs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, output string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			route := router.Route(output)
+			var resp *VerificationResponse
+			var err error
+			if route.Engine == TypeNaturalLanguage && o.Question != "" {
+				resp, err = c.Verify(ctx, joinQuestion(o.Question, output))
+			} else {
+				var routed *RoutedResponse
+				if routed, err = router.Verify(ctx, output); err == nil {
+					resp = routed.Response
+				}
+			}
+
+			candidate := ConsensusCandidate{Index: i, Output: output, Answer: o.Answer(output), Route: route, Response: resp}
+			if err != nil {
+				candidate.Error = err.Error()
+			} else {
+				candidate.Verified = IsVerified(resp)
+			}
+			result.Candidates[i] = candidate
+		}(i, output)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, candidate := range result.Candidates {
+		counts[candidate.Answer]++
+	}
+	for i := range result.Candidates {
+		candidate := &result.Candidates[i]
+		candidate.Agreement = float64(counts[candidate.Answer]) / float64(len(outputs))
+		if candidate.Agreement > result.MajorityAgreement {
+			result.MajorityAnswer = candidate.Answer
+			result.MajorityAgreement = candidate.Agreement
+		}
+		if candidate.Verified && (result.Best == nil || candidate.Agreement > result.Best.Agreement) {
+			result.Best = candidate
+		}
+	}
+	return result, nil
+}
+
+// answerNumber matches numbers, including thousands separators.
+var answerNumber = regexp.MustCompile(`-?\d[\d,]*(\.\d+)?`)
+
+// finalAnswer is the default ConsensusOptions.Answer: the last number in
+// output, or the normalised output if it has none.
+func finalAnswer(output string) string {
+	if matches := answerNumber.FindAllString(output, -1); len(matches) > 0 {
+		last := strings.ReplaceAll(strings.TrimRight(matches[len(matches)-1], ","), ",", "")
+		if f, err := strconv.ParseFloat(last, 64); err == nil {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		return last
+	}
+	return strings.TrimRight(strings.ToLower(strings.Join(strings.Fields(output), " ")), ".!")
+}
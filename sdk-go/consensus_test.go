@@ -0,0 +1,98 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestVerifyConsensus(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		verified := false
+		switch r.URL.Path {
+		case "/verify/math":
+			verified = req["expression"] == "6 * 7 = 42"
+		case "/verify/natural_language":
+			query, _ := req["query"].(string)
+			verified = strings.HasPrefix(query, "What is six times seven?\n") && strings.Contains(query, "42")
+		}
+		json.NewEncoder(w).Encode(VerificationResponse{Verified: verified, Status: map[bool]Status{true: StatusVerified, false: StatusFailed}[verified]})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	outputs := []string{
+		"6 * 7 = 42",
+		"The answer is 42.",
+		"6 * 7 = 48",
+		"It is forty-two",
+	}
+	result, err := client.VerifyConsensus(context.Background(), outputs, &ConsensusOptions{Question: "What is six times seven?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Candidates) != 4 {
+		t.Fatalf("expected 4 candidates, got %d", len(result.Candidates))
+	}
+	want := []struct {
+		answer    string
+		engine    VerificationType
+		verified  bool
+		agreement float64
+	}{
+		{"42", TypeMath, true, 0.5},
+		{"42", TypeNaturalLanguage, true, 0.5},
+		{"48", TypeMath, false, 0.25},
+		{"it is forty-two", TypeNaturalLanguage, false, 0.25},
+	}
+	for i, w := range want {
+		c := result.Candidates[i]
+		if c.Answer != w.answer || c.Route.Engine != w.engine || c.Verified != w.verified || c.Agreement != w.agreement {
+			t.Errorf("candidate %d: got %+v", i, c)
+		}
+	}
+	if result.Best == nil || result.Best.Index != 0 {
+		t.Errorf("expected the first output to be best, got %+v", result.Best)
+	}
+	if result.MajorityAnswer != "42" || result.MajorityAgreement != 0.5 {
+		t.Errorf("unexpected majority: %q %v", result.MajorityAnswer, result.MajorityAgreement)
+	}
+}
+
+func TestVerifyConsensusNoneVerified(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	result, err := client.VerifyConsensus(context.Background(), []string{"1 + 1 = 2", "1 + 1 = 2"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Best != nil {
+		t.Errorf("expected no best candidate, got %+v", result.Best)
+	}
+	if result.Candidates[0].Error == "" || result.MajorityAgreement != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestFinalAnswer(t *testing.T) {
+	tests := map[string]string{
+		"Total: $1,200.":            "1200",
+		"2 + 2 = 4.0":               "4",
+		"Paris  is the capital.":    "paris is the capital",
+		"Values are 1, 2, and -3.5": "-3.5",
+	}
+	for output, want := range tests {
+		if got := finalAnswer(output); got != want {
+			t.Errorf("finalAnswer(%q) = %q, want %q", output, got, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ============================================================================
+// Constraint Verification
+// ============================================================================
+
+// ConstraintVariable is a variable of a constraint problem.
+type ConstraintVariable struct {
+	Name string `json:"name"`
+	// Domain lists the values the variable may take, e.g. rooms or time
+	// slots. Empty allows any value; bound numeric variables with
+	// constraints such as "0 <= start_alice <= 16".
+	Domain []interface{} `json:"domain,omitempty"`
+}
+
+// ConstraintViolation is a constraint a proposed solution breaks.
+type ConstraintViolation struct {
+	Index      int                    `json:"index"` // position of the constraint in the request, or -1 for a domain violation
+	Constraint string                 `json:"constraint"`
+	Message    string                 `json:"message,omitempty"`    // e.g. "start_bob (9) < start_alice + 2 (11)"
+	Assignment map[string]interface{} `json:"assignment,omitempty"` // values of the variables involved
+}
+
+// VerifyConstraints checks a proposed solution to a scheduling or
+// allocation problem, such as a plan from an agent, against the problem's
+// constraints. Constraints are QWED-Logic expressions over the variables:
+//
+//	vars := []qwed.ConstraintVariable{
+//		{Name: "alice", Domain: []interface{}{"9:00", "10:00", "11:00"}},
+//		{Name: "bob", Domain: []interface{}{"9:00", "10:00", "11:00"}},
+//	}
+//	resp, err := client.VerifyConstraints(ctx, vars, []string{"alice != bob"},
+//		map[string]interface{}{"alice": "10:00", "bob": "10:00"})
+//
+// The response is verified when the solution assigns every variable a
+// value from its domain and satisfies every constraint. Use
+// ConstraintViolations to decode the constraints it breaks.
+func (c *Client) VerifyConstraints(ctx context.Context, variables []ConstraintVariable, constraints []string, solution map[string]interface{}, callOpts ...CallOption) (*VerificationResponse, error) {
+	req := map[string]interface{}{
+		"variables":   variables,
+		"constraints": constraints,
+		"solution":    solution,
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, invalidRequest("failed to marshal constraint problem: %v", err)
+	}
+	return c.verify(ctx, "VerifyConstraints", TypeConstraints, CacheKey(TypeConstraints, contentHash(data)), req, callOpts...)
+}
+
+// ConstraintViolations extracts the violated constraints from a
+// VerifyConstraints response.
+func ConstraintViolations(resp *VerificationResponse) []ConstraintViolation {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var violations []ConstraintViolation
+	decodeResult(resp.Result["violations"], &violations)
+	return violations
+}
@@ -0,0 +1,86 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyConstraints(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/constraints" {
+			t.Errorf("expected path /verify/constraints, got %s", r.URL.Path)
+		}
+		var req struct {
+			Variables   []ConstraintVariable   `json:"variables"`
+			Constraints []string               `json:"constraints"`
+			Solution    map[string]interface{} `json:"solution"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(req.Variables) != 2 || len(req.Variables[0].Domain) != 3 || len(req.Constraints) != 2 || req.Solution["bob"] != "10:00" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": false,
+			"engine":   "constraints",
+			"result": map[string]interface{}{
+				"violations": []map[string]interface{}{{
+					"index":      0,
+					"constraint": "alice != bob",
+					"message":    "alice and bob are both 10:00",
+					"assignment": map[string]interface{}{"alice": "10:00", "bob": "10:00"},
+				}},
+			},
+		})
+	})
+	defer server.Close()
+
+	slots := []interface{}{"9:00", "10:00", "11:00"}
+	vars := []ConstraintVariable{{Name: "alice", Domain: slots}, {Name: "bob", Domain: slots}}
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyConstraints(context.Background(), vars, []string{"alice != bob", "bob != '9:00'"},
+		map[string]interface{}{"alice": "10:00", "bob": "10:00"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Verified {
+		t.Error("expected a clashing schedule to fail")
+	}
+
+	violations := ConstraintViolations(resp)
+	if len(violations) != 1 || violations[0].Index != 0 || violations[0].Constraint != "alice != bob" || violations[0].Assignment["bob"] != "10:00" {
+		t.Errorf("unexpected violations: %+v", violations)
+	}
+	if ConstraintViolations(&VerificationResponse{}) != nil {
+		t.Error("expected no violations without a result")
+	}
+}
+
+func TestVerifyConstraintsStrictValidation(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"), WithStrictValidation())
+	ctx := context.Background()
+	vars := []ConstraintVariable{{Name: "x"}, {Name: "y"}}
+
+	tests := []struct {
+		name        string
+		variables   []ConstraintVariable
+		constraints []string
+		solution    map[string]interface{}
+	}{
+		{"no variables", nil, []string{"x < y"}, map[string]interface{}{"x": 1}},
+		{"no constraints", vars, nil, map[string]interface{}{"x": 1}},
+		{"no solution", vars, []string{"x < y"}, nil},
+		{"unnamed variable", []ConstraintVariable{{Name: " "}}, []string{"x < y"}, map[string]interface{}{"x": 1}},
+		{"duplicate variable", []ConstraintVariable{{Name: "x"}, {Name: "x"}}, []string{"x < 2"}, map[string]interface{}{"x": 1}},
+		{"undeclared variable", vars, []string{"x < y"}, map[string]interface{}{"z": 1}},
+	}
+	for _, tt := range tests {
+		if _, err := client.VerifyConstraints(ctx, tt.variables, tt.constraints, tt.solution); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("%s: expected ErrInvalidRequest, got %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package qwed
+
+import "context"
+
+// ============================================================================
+// Math Cross-Checking
+// ============================================================================
+
+// MathOptions configures VerifyMathWithOptions.
+type MathOptions struct {
+	// CrossCheck evaluates the expression with two independent engines,
+	// symbolic and numeric, and reports whether they agree. A disagreement
+	// makes the response inconclusive. Cross-checked calls are never
+	// answered by the offline fallback, which has a single engine.
+	CrossCheck bool
+}
+
+// CrossCheck is how the engines evaluated a cross-checked expression.
+type CrossCheck struct {
+	Agreed      bool             `json:"agreed"`
+	Evaluations []MathEvaluation `json:"evaluations"`
+	Message     string           `json:"message,omitempty"` // why the engines disagree, e.g. "results differ by 1.2e-3"
+}
+
+// MathEvaluation is one engine's evaluation of an expression.
+type MathEvaluation struct {
+	Engine   string `json:"engine"` // e.g. "symbolic" or "numeric"
+	Value    string `json:"value,omitempty"`
+	Verified bool   `json:"verified"`
+	Error    string `json:"error,omitempty"` // set if the engine could not evaluate the expression
+}
+
+// VerifyMathWithOptions verifies a mathematical expression with custom
+// options, such as CrossCheck for high-stakes calculations:
+//
+//	resp, err := client.VerifyMathWithOptions(ctx, expr, &qwed.MathOptions{CrossCheck: true})
+//	if check := qwed.MathCrossCheck(resp); check != nil && !check.Agreed {
+//		log.Printf("engines disagree: %s", check.Message)
+//	}
+func (c *Client) VerifyMathWithOptions(ctx context.Context, expression string, opts *MathOptions, callOpts ...CallOption) (*VerificationResponse, error) {
+	req := map[string]interface{}{
+		"expression": expression,
+	}
+
+	if opts != nil && opts.CrossCheck {
+		req["cross_check"] = true
+		return c.verify(ctx, "VerifyMath", TypeMath, CacheKey(TypeMath, expression, "cross_check"), req, callOpts...)
+	}
+
+	resp, err := c.verify(ctx, "VerifyMath", TypeMath, CacheKey(TypeMath, expression), req, callOpts...)
+	return c.fallback(ctx, TypeMath, expression, resp, err)
+}
+
+// MathCrossCheck extracts the cross-check from a VerifyMathWithOptions
+// response, or nil if the expression was not cross-checked.
+func MathCrossCheck(resp *VerificationResponse) *CrossCheck {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var check CrossCheck
+	if !decodeResult(resp.Result["cross_check"], &check) {
+		return nil
+	}
+	return &check
+}
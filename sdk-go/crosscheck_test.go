@@ -0,0 +1,66 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyMathCrossCheck(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["expression"] != "sqrt(2)^2 = 2" || req["cross_check"] != true {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "INCONCLUSIVE",
+			"verified": false,
+			"engine":   "math",
+			"result": map[string]interface{}{
+				"cross_check": map[string]interface{}{
+					"agreed": false,
+					"evaluations": []map[string]interface{}{
+						{"engine": "symbolic", "value": "2", "verified": true},
+						{"engine": "numeric", "value": "2.0000000000000004", "verified": false},
+					},
+					"message": "results differ by 4.4e-16",
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyMathWithOptions(context.Background(), "sqrt(2)^2 = 2", &MathOptions{CrossCheck: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	check := MathCrossCheck(resp)
+	if check == nil || check.Agreed || len(check.Evaluations) != 2 || check.Message == "" {
+		t.Fatalf("unexpected cross-check: %+v", check)
+	}
+	if e := check.Evaluations[1]; e.Engine != "numeric" || e.Verified || e.Value != "2.0000000000000004" {
+		t.Errorf("unexpected evaluation: %+v", e)
+	}
+	if MathCrossCheck(&VerificationResponse{Result: map[string]interface{}{}}) != nil {
+		t.Error("expected no cross-check without one in the result")
+	}
+}
+
+func TestVerifyMathCrossCheckSkipsFallback(t *testing.T) {
+	client := NewClient("test-key",
+		WithBaseURL("http://127.0.0.1:1"),
+		WithOfflineFallback(TypeMath),
+	)
+
+	if _, err := client.VerifyMathWithOptions(context.Background(), "2 + 2 = 4", &MathOptions{CrossCheck: true}); err == nil {
+		t.Error("expected a cross-checked call to fail without the API")
+	}
+	if _, err := client.VerifyMathWithOptions(context.Background(), "2 + 2 = 4", &MathOptions{}); err != nil {
+		t.Errorf("expected local fallback without cross-checking, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package qwed
+
+import "context"
+
+// ============================================================================
+// Cryptographic Misuse Checks
+// ============================================================================
+
+// CheckMode focuses the code engine on one class of issues, set with
+// RequestOptions.CheckMode. The default checks for security issues in
+// general.
+type CheckMode string
+
+const (
+	// CheckCrypto looks for cryptographic misuse, reported as the
+	// Finding* types below with References to recommended constructions.
+	CheckCrypto CheckMode = "crypto"
+)
+
+// checkModes are the modes the code engine accepts.
+var checkModes = map[CheckMode]bool{CheckCrypto: true}
+
+// Finding types reported by CheckCrypto.
+const (
+	FindingECBMode          = "ECB_MODE"           // block cipher in ECB mode; use an AEAD such as AES-GCM
+	FindingShortKey         = "SHORT_KEY"          // RSA under 2048 bits, symmetric keys under 128 bits
+	FindingInsecureRandom   = "INSECURE_RANDOM"    // keys, IVs or tokens from a non-cryptographic or homemade RNG
+	FindingWeakPasswordHash = "WEAK_PASSWORD_HASH" // MD5, SHA-1 or a bare fast hash for passwords; use Argon2id, scrypt or bcrypt
+	FindingMissingSalt      = "MISSING_SALT"       // password hash without a per-password random salt
+	FindingStaticIV         = "STATIC_IV"          // constant or reused IV or nonce
+)
+
+// VerifyCrypto checks generated code for cryptographic misuse: ECB mode,
+// short keys, homemade or non-cryptographic random number generators,
+// fast hashes such as MD5 and SHA-1 for passwords, missing salts and
+// static IVs. It is VerifyCodeWithOptions with CheckMode CheckCrypto;
+// findings carry a Recommendation and References to the recommended
+// construction:
+//
+//	resp, err := client.VerifyCrypto(ctx, code, "python")
+//	for _, f := range qwed.CodeFindings(resp) {
+//		fmt.Println(f.Line, f.Type, f.Recommendation, f.References)
+//	}
+func (c *Client) VerifyCrypto(ctx context.Context, code, language string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return c.VerifyCodeWithOptions(ctx, code, language, &RequestOptions{CheckMode: CheckCrypto}, callOpts...)
+}
@@ -0,0 +1,63 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyCrypto(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/code" {
+			t.Errorf("expected path /verify/code, got %s", r.URL.Path)
+		}
+		var req struct {
+			Code     string          `json:"code"`
+			Language string          `json:"language"`
+			Options  *RequestOptions `json:"options"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Options == nil || req.Options.CheckMode != CheckCrypto || req.Language != "python" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": false,
+			"engine":   "code",
+			"result": map[string]interface{}{
+				"issues": []map[string]interface{}{{
+					"severity":       SeverityCritical,
+					"type":           FindingWeakPasswordHash,
+					"line_number":    2,
+					"description":    "MD5 used to hash a password",
+					"recommendation": "Use Argon2id with a per-password salt",
+					"references":     []string{"https://cheatsheetseries.owasp.org/cheatsheets/Password_Storage_Cheat_Sheet.html"},
+				}},
+			},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	code := "import hashlib\nh = hashlib.md5(password.encode()).hexdigest()\n"
+	resp, err := client.VerifyCrypto(context.Background(), code, "python")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	findings := CodeFindings(resp)
+	if len(findings) != 1 || findings[0].Type != FindingWeakPasswordHash || len(findings[0].References) != 1 {
+		t.Errorf("unexpected findings: %+v", findings)
+	}
+}
+
+func TestCheckModeValidation(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"), WithStrictValidation())
+	_, err := client.VerifyCodeWithOptions(context.Background(), "x = 1", "python", &RequestOptions{CheckMode: "quantum"})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for an unknown check mode, got %v", err)
+	}
+}
@@ -0,0 +1,679 @@
+package qwed
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Date and Time Verification
+// ============================================================================
+
+// TypeDateTime identifies date and time arithmetic checks. They run
+// locally and are reported with Engine EngineLocalDateTime.
+const TypeDateTime VerificationType = "datetime"
+
+// EngineLocalDateTime is the engine name reported by VerifyDateTime.
+const EngineLocalDateTime Engine = "local-datetime"
+
+// DateTimeOptions configures VerifyDateTimeWithOptions.
+type DateTimeOptions struct {
+	// Location is the time zone of dates and times that do not name one.
+	// Defaults to UTC.
+	Location *time.Location
+
+	// Reference is the current time, used for "today", "3 days ago" and
+	// times without a date. Defaults to time.Now().
+	Reference time.Time
+
+	// Weekend lists the days that are not business days. Defaults to
+	// Saturday and Sunday.
+	Weekend []time.Weekday
+
+	// Holidays are skipped when counting business days. Only their dates
+	// are compared.
+	Holidays []time.Time
+
+	// DayFirst reads numeric dates such as 03/04/2024 as day/month/year
+	// instead of month/day/year.
+	DayFirst bool
+}
+
+// VerifyDateTime checks date and time arithmetic such as
+// "90 days after March 3, 2024 is June 1, 2024". It is
+// VerifyDateTimeWithOptions with default options.
+func (c *Client) VerifyDateTime(ctx context.Context, claim string) (*VerificationResponse, error) {
+	return c.VerifyDateTimeWithOptions(ctx, claim, nil)
+}
+
+// VerifyDateTimeWithOptions checks a claim about dates and times. It
+// understands:
+//
+//   - offsets: "90 days after March 3, 2024 is June 1, 2024",
+//     "2024-01-31 plus 1 month is 2024-02-29", "10 business days before 2024-12-31 ..."
+//   - durations: "there are 60 days between Jan 1, 2024 and March 1, 2024"
+//   - weekdays: "July 4, 2026 is a Saturday"
+//   - leap years: "1900 is not a leap year"
+//   - time zones: "3pm EST is 8pm UTC", "09:00 UTC+2 is 07:00 GMT"
+//
+// Adding months or years clamps to the end of shorter months, so January
+// 31 plus one month is February 29 in a leap year. Business days skip
+// opts.Weekend and opts.Holidays. Time zones are given as common
+// abbreviations, UTC offsets or IANA names such as Europe/London.
+//
+// Impossible dates such as February 30 fail the claim; claims that cannot
+// be parsed are reported with StatusUnsupported.
+func (c *Client) VerifyDateTimeWithOptions(ctx context.Context, claim string, opts *DateTimeOptions) (resp *VerificationResponse, err error) {
+	_, end := c.instrument(ctx, "VerifyDateTime", TypeDateTime)
+	defer func() { end(resp, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var o DateTimeOptions
+	if opts != nil {
+		o = *opts
+	}
+	return localVerifyDateTime(claim, o), nil
+}
+
+// localVerifyDateTime evaluates a date or time claim.
+func localVerifyDateTime(claim string, o DateTimeOptions) *VerificationResponse {
+	if o.Location == nil {
+		o.Location = time.UTC
+	}
+	if o.Reference.IsZero() {
+		o.Reference = time.Now()
+	}
+	if o.Weekend == nil {
+		o.Weekend = []time.Weekday{time.Saturday, time.Sunday}
+	}
+	d := &dateParser{opts: o}
+
+	claim = meridiemDots.ReplaceAllString(claim, "${1}m")
+	claim = strings.TrimRight(strings.Join(strings.Fields(claim), " "), ".!")
+
+	resp, err := d.verify(claim)
+	if err != nil {
+		if invalid, ok := err.(*invalidDateError); ok {
+			return localResponse(EngineLocalDateTime, false, map[string]interface{}{"reason": invalid.Error()})
+		}
+		return &VerificationResponse{
+			Status: StatusUnsupported,
+			Engine: EngineLocalDateTime,
+			Result: map[string]interface{}{"reason": err.Error()},
+		}
+	}
+	return resp
+}
+
+// invalidDateError reports a date that does not exist, such as February
+// 30. A claim containing one is false rather than unparseable.
+type invalidDateError struct {
+	date string
+}
+
+func (e *invalidDateError) Error() string {
+	return fmt.Sprintf("%s is not a valid date", e.date)
+}
+
+// errNoDateClaim is returned for claims matching none of the forms.
+var errNoDateClaim = fmt.Errorf("no date or time claim found")
+
+// ============================================================================
+// Claim Forms
+// ============================================================================
+
+const (
+	monthPattern   = `january|february|march|april|may|june|july|august|september|october|november|december|jan|feb|mar|apr|jun|jul|aug|sept|sep|oct|nov|dec`
+	weekdayPattern = `monday|tuesday|wednesday|thursday|friday|saturday|sunday|mon|tues|tue|wed|thurs|thur|thu|fri|sat|sun`
+	countPattern   = `\d+|an?|one|two|three|four|five|six|seven|eight|nine|ten|eleven|twelve`
+	spanPattern    = `(` + countPattern + `) (business |working )?(seconds?|minutes?|hours?|days?|weekdays?|weeks?|fortnights?|months?|years?|decades?)`
+)
+
+var (
+	meridiemDots = regexp.MustCompile(`(?i)\b([ap])\.m\.?`)
+
+	leapYearClaim = regexp.MustCompile(`(?i)^(\d{1,4}) (?:is|was|will be) (not )?a leap year$`)
+	weekdayClaim  = regexp.MustCompile(`(?i)^(.+?) (?:is|was|will be|falls on|fell on|lands on) (?:on )?(?:an? )?(` + weekdayPattern + `|weekday|weekend day|weekend|business day|working day)$`)
+	countFirst    = regexp.MustCompile(`(?i)^(?:there (?:are|were|will be) |it(?:'s| is| was) )?` + spanPattern + ` (?:between|from) (.+?) (?:and|to|until|till|through) (.+)$`)
+	countLast     = regexp.MustCompile(`(?i)^(?:from |between )?(.+?) (?:and|to|until|till|through) (.+?) (?:is|are|was|were|spans|lasts|takes) (?:exactly )?` + spanPattern + `$`)
+	comparisons   = regexp.MustCompile(`(?i) (?:is|was|will be|would be|equals|=|falls on|fell on|lands on) (?:on )?`)
+
+	spanBefore = regexp.MustCompile(`(?i)^` + spanPattern + ` (after|from|later than|before|prior to|earlier than) (.+)$`)
+	spanAround = regexp.MustCompile(`(?i)^(.+) (plus|\+|minus|-) ` + spanPattern + `$`)
+	spanNow    = regexp.MustCompile(`(?i)^` + spanPattern + ` (ago|from now|from today|later)$`)
+	spanIn     = regexp.MustCompile(`(?i)^in ` + spanPattern + `$`)
+)
+
+type dateParser struct {
+	opts DateTimeOptions
+}
+
+// verify tries each claim form in turn.
+func (d *dateParser) verify(claim string) (*VerificationResponse, error) {
+	if m := leapYearClaim.FindStringSubmatch(claim); m != nil {
+		year, _ := strconv.Atoi(m[1])
+		leap := isLeapYear(year)
+		return localResponse(EngineLocalDateTime, leap == (m[2] == ""), map[string]interface{}{
+			"year":      year,
+			"leap_year": leap,
+		}), nil
+	}
+	if m := weekdayClaim.FindStringSubmatch(claim); m != nil {
+		if resp, err := d.verifyWeekday(m[1], strings.ToLower(m[2])); err != errNoDateClaim {
+			return resp, err
+		}
+	}
+	if m := countFirst.FindStringSubmatch(claim); m != nil {
+		if resp, err := d.verifyCount(m[1], m[2], m[3], m[4], m[5]); err != errNoDateClaim {
+			return resp, err
+		}
+	}
+	if m := countLast.FindStringSubmatch(claim); m != nil {
+		if resp, err := d.verifyCount(m[3], m[4], m[5], m[1], m[2]); err != errNoDateClaim {
+			return resp, err
+		}
+	}
+
+	// A comparison between two expressions, split at the first comparison
+	// word both of whose sides parse.
+	var firstErr error
+	for _, loc := range comparisons.FindAllStringIndex(claim, -1) {
+		left, err := d.expression(claim[:loc[0]])
+		if err == nil {
+			var right moment
+			if right, err = d.expression(claim[loc[1]:]); err == nil {
+				return d.compare(left, right), nil
+			}
+		}
+		if _, ok := err.(*invalidDateError); ok {
+			return nil, err
+		}
+		if firstErr == nil || firstErr == errNoDateClaim {
+			firstErr = err
+		}
+	}
+	if firstErr == nil {
+		firstErr = errNoDateClaim
+	}
+	return nil, firstErr
+}
+
+// verifyWeekday checks "<date> is a Saturday" and "... is a weekday".
+func (d *dateParser) verifyWeekday(expr, day string) (*VerificationResponse, error) {
+	m, err := d.expression(expr)
+	if err != nil {
+		if _, ok := err.(*invalidDateError); ok {
+			return nil, err
+		}
+		return nil, errNoDateClaim
+	}
+
+	actual := m.t.Weekday()
+	var verified bool
+	switch day {
+	case "weekend", "weekend day":
+		verified = d.isWeekend(m.t)
+	case "weekday":
+		verified = !d.isWeekend(m.t)
+	case "business day", "working day":
+		verified = d.isBusinessDay(m.t)
+	default:
+		verified = actual == weekdays[day]
+	}
+	return localResponse(EngineLocalDateTime, verified, map[string]interface{}{
+		"date":    m.format(),
+		"weekday": actual.String(),
+		"claimed": day,
+	}), nil
+}
+
+// verifyCount checks "there are 60 days between A and B".
+func (d *dateParser) verifyCount(count, business, unit, from, to string) (*VerificationResponse, error) {
+	a, err := d.expression(from)
+	if err == nil {
+		var b moment
+		if b, err = d.expression(to); err == nil {
+			if b.t.Before(a.t) {
+				a, b = b, a
+			}
+			claimed := parseCount(count)
+			unit, multiple, isBusiness := normalizeUnit(unit, business != "")
+			expected := d.count(a, b, unit, isBusiness) / float64(multiple)
+			return localResponse(EngineLocalDateTime, math.Abs(expected-float64(claimed)) < 1e-9, map[string]interface{}{
+				"from":     a.format(),
+				"to":       b.format(),
+				"unit":     strings.ToLower(business + unit),
+				"expected": expected,
+				"claimed":  claimed,
+			}), nil
+		}
+	}
+	if _, ok := err.(*invalidDateError); ok {
+		return nil, err
+	}
+	return nil, errNoDateClaim
+}
+
+// compare checks that two expressions denote the same instant if both have
+// a time of day or time zone, or else the same date.
+func (d *dateParser) compare(left, right moment) *VerificationResponse {
+	expected := moment{t: left.t.In(right.t.Location()), hasTime: left.hasTime && right.hasTime}
+	verified := sameDate(expected.t, right.t)
+	if expected.hasTime {
+		verified = left.t.Equal(right.t)
+	}
+	return localResponse(EngineLocalDateTime, verified, map[string]interface{}{
+		"expected": expected.format(),
+		"claimed":  right.format(),
+		"weekday":  expected.t.Weekday().String(),
+	})
+}
+
+// ============================================================================
+// Expressions
+// ============================================================================
+
+// moment is a parsed date or time.
+type moment struct {
+	t       time.Time
+	hasTime bool // a time of day or time zone was given
+}
+
+func (m moment) format() string {
+	if m.hasTime {
+		return m.t.Format(time.RFC3339)
+	}
+	return m.t.Format("2006-01-02")
+}
+
+// expression parses a date, optionally offset by a span: "90 days after
+// March 3, 2024", "2024-01-31 plus 1 month", "3 days ago".
+func (d *dateParser) expression(s string) (moment, error) {
+	s = strings.TrimSpace(s)
+	if m := spanBefore.FindStringSubmatch(s); m != nil {
+		base, err := d.expression(m[5])
+		if err != nil {
+			return moment{}, err
+		}
+		return d.shift(base, m[1], m[2], m[3], m[4]), nil
+	}
+	if m := spanAround.FindStringSubmatch(s); m != nil {
+		if base, err := d.expression(m[1]); err == nil {
+			direction := "after"
+			if m[2] == "minus" || m[2] == "-" {
+				direction = "before"
+			}
+			return d.shift(base, m[3], m[4], m[5], direction), nil
+		} else if _, ok := err.(*invalidDateError); ok {
+			return moment{}, err
+		}
+	}
+	if m := spanNow.FindStringSubmatch(s); m != nil {
+		direction := "after"
+		if strings.EqualFold(m[4], "ago") {
+			direction = "before"
+		}
+		return d.shift(d.today(), m[1], m[2], m[3], direction), nil
+	}
+	if m := spanIn.FindStringSubmatch(s); m != nil {
+		return d.shift(d.today(), m[1], m[2], m[3], "after"), nil
+	}
+	return d.moment(s)
+}
+
+func (d *dateParser) today() moment {
+	y, mo, day := d.opts.Reference.In(d.opts.Location).Date()
+	return moment{t: time.Date(y, mo, day, 0, 0, 0, 0, d.opts.Location)}
+}
+
+// shift moves m by a span in direction ("after", "before", ...).
+func (d *dateParser) shift(m moment, count, business, unit, direction string) moment {
+	n := parseCount(count)
+	switch strings.ToLower(direction) {
+	case "before", "prior to", "earlier than":
+		n = -n
+	}
+	unit, multiple, isBusiness := normalizeUnit(unit, business != "")
+	n *= multiple
+	if isBusiness {
+		m.t = d.addBusinessDays(m.t, n)
+		return m
+	}
+	switch unit {
+	case "second":
+		m.t, m.hasTime = m.t.Add(time.Duration(n)*time.Second), true
+	case "minute":
+		m.t, m.hasTime = m.t.Add(time.Duration(n)*time.Minute), true
+	case "hour":
+		m.t, m.hasTime = m.t.Add(time.Duration(n)*time.Hour), true
+	case "day":
+		m.t = m.t.AddDate(0, 0, n)
+	case "week":
+		m.t = m.t.AddDate(0, 0, 7*n)
+	case "month":
+		m.t = addMonths(m.t, n)
+	case "year":
+		m.t = addMonths(m.t, 12*n)
+	}
+	return m
+}
+
+// count returns the number of units from a to b, fractional if b is not
+// a whole number of units after a.
+func (d *dateParser) count(a, b moment, unit string, business bool) float64 {
+	days := float64(civilDays(b.t) - civilDays(a.t))
+	if business {
+		n := 0
+		for t := a.t; civilDays(t) < civilDays(b.t); {
+			t = t.AddDate(0, 0, 1)
+			if d.isBusinessDay(t) {
+				n++
+			}
+		}
+		return float64(n)
+	}
+	switch unit {
+	case "second":
+		return b.t.Sub(a.t).Seconds()
+	case "minute":
+		return b.t.Sub(a.t).Minutes()
+	case "hour":
+		return b.t.Sub(a.t).Hours()
+	case "day":
+		if a.hasTime || b.hasTime {
+			return b.t.Sub(a.t).Hours() / 24
+		}
+		return days
+	case "week":
+		return days / 7
+	}
+
+	months := 0
+	for !addMonths(a.t, months+1).After(b.t) {
+		months++
+	}
+	whole := addMonths(a.t, months)
+	fraction := 0.0
+	if next := addMonths(a.t, months+1); !whole.Equal(b.t) {
+		fraction = float64(b.t.Sub(whole)) / float64(next.Sub(whole))
+	}
+	if unit == "year" {
+		return (float64(months) + fraction) / 12
+	}
+	return float64(months) + fraction
+}
+
+func (d *dateParser) isWeekend(t time.Time) bool {
+	for _, day := range d.opts.Weekend {
+		if t.Weekday() == day {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *dateParser) isBusinessDay(t time.Time) bool {
+	if d.isWeekend(t) {
+		return false
+	}
+	for _, holiday := range d.opts.Holidays {
+		if sameDate(holiday, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// addBusinessDays moves t by n business days, skipping weekends and
+// holidays.
+func (d *dateParser) addBusinessDays(t time.Time, n int) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		t = t.AddDate(0, 0, step)
+		if d.isBusinessDay(t) {
+			n--
+		}
+	}
+	return t
+}
+
+// addMonths adds n months to t, clamping the day to the end of the
+// resulting month.
+func addMonths(t time.Time, n int) time.Time {
+	y, m, day := t.Date()
+	first := time.Date(y, m+time.Month(n), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// civilDays numbers t's calendar date in its own location.
+func civilDays(t time.Time) int {
+	y, m, d := t.Date()
+	return int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+func sameDate(a, b time.Time) bool {
+	return civilDays(a) == civilDays(b)
+}
+
+var countWords = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+	"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+}
+
+func parseCount(s string) int {
+	if n, ok := countWords[strings.ToLower(s)]; ok {
+		return n
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// normalizeUnit returns the singular base unit and how many of it one
+// unit is, so a fortnight is 2 weeks. "weekdays" are business days.
+func normalizeUnit(unit string, business bool) (string, int, bool) {
+	unit = strings.TrimSuffix(strings.ToLower(unit), "s")
+	switch unit {
+	case "weekday":
+		return "day", 1, true
+	case "fortnight":
+		return "week", 2, false
+	case "decade":
+		return "year", 10, false
+	}
+	return unit, 1, business && unit == "day"
+}
+
+// ============================================================================
+// Dates, Times and Zones
+// ============================================================================
+
+var (
+	weekdayPrefix  = regexp.MustCompile(`(?i)^(?:` + weekdayPattern + `),? `)
+	isoDate        = regexp.MustCompile(`^(\d{4})-(\d{1,2})-(\d{1,2})(?:T|\b)`)
+	monthFirstDate = regexp.MustCompile(`(?i)^(` + monthPattern + `)\.? (\d{1,2})(?:st|nd|rd|th)?\b(?:,? (\d{4})\b)?`)
+	dayFirstDate   = regexp.MustCompile(`(?i)^(\d{1,2})(?:st|nd|rd|th)? (?:of )?(` + monthPattern + `)\b\.?(?:,? (\d{4})\b)?`)
+	numericDate    = regexp.MustCompile(`^(\d{1,2})[/.](\d{1,2})[/.](\d{4})\b`)
+	relativeDate   = regexp.MustCompile(`(?i)^(today|tomorrow|yesterday)\b`)
+	clockTime      = regexp.MustCompile(`(?i)^(?:at )?(?:(\d{1,2})(?::(\d{2}))?(?::(\d{2}))? ?([ap]m)\b|(\d{1,2}):(\d{2})(?::(\d{2}))?|(noon|midday|midnight)\b)`)
+	offsetZone     = regexp.MustCompile(`(?i)^(?:utc|gmt)? ?([+-])(\d{1,2})(?::?(\d{2}))?$`)
+)
+
+var months = map[string]time.Month{
+	"january": time.January, "february": time.February, "march": time.March, "april": time.April,
+	"may": time.May, "june": time.June, "july": time.July, "august": time.August,
+	"september": time.September, "october": time.October, "november": time.November, "december": time.December,
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April, "jun": time.June,
+	"jul": time.July, "aug": time.August, "sep": time.September, "sept": time.September,
+	"oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+var weekdays = map[string]time.Weekday{
+	"monday": time.Monday, "tuesday": time.Tuesday, "wednesday": time.Wednesday, "thursday": time.Thursday,
+	"friday": time.Friday, "saturday": time.Saturday, "sunday": time.Sunday,
+	"mon": time.Monday, "tue": time.Tuesday, "tues": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "fri": time.Friday,
+	"sat": time.Saturday, "sun": time.Sunday,
+}
+
+// zoneOffsets are fixed UTC offsets, in hours, of common time zone
+// abbreviations.
+var zoneOffsets = map[string]float64{
+	"UTC": 0, "GMT": 0, "Z": 0, "WET": 0,
+	"BST": 1, "CET": 1, "WEST": 1, "CEST": 2, "EET": 2, "EEST": 3, "MSK": 3,
+	"IST": 5.5, "SGT": 8, "HKT": 8, "JST": 9, "KST": 9,
+	"ACST": 9.5, "AEST": 10, "AEDT": 11, "NZST": 12, "NZDT": 13,
+	"EST": -5, "EDT": -4, "CST": -6, "CDT": -5, "MST": -7, "MDT": -6,
+	"PST": -8, "PDT": -7, "AKST": -9, "AKDT": -8, "HST": -10,
+}
+
+// moment parses a date and/or time of day with an optional time zone:
+// "Monday, March 4, 2024", "2024-03-04T15:00", "3pm EST", "tomorrow at noon".
+func (d *dateParser) moment(s string) (moment, error) {
+	original := s
+	s = weekdayPrefix.ReplaceAllString(s, "")
+
+	var y, day int
+	var mo time.Month
+	hasDate := true
+	switch {
+	case isoDate.MatchString(s):
+		m := isoDate.FindStringSubmatch(s)
+		y, day = atoi(m[1]), atoi(m[3])
+		mo = time.Month(atoi(m[2]))
+		s = s[len(m[0]):]
+	case monthFirstDate.MatchString(s):
+		m := monthFirstDate.FindStringSubmatch(s)
+		mo, day, y = months[strings.ToLower(m[1])], atoi(m[2]), d.year(m[3])
+		s = s[len(m[0]):]
+	case dayFirstDate.MatchString(s):
+		m := dayFirstDate.FindStringSubmatch(s)
+		day, mo, y = atoi(m[1]), months[strings.ToLower(m[2])], d.year(m[3])
+		s = s[len(m[0]):]
+	case numericDate.MatchString(s):
+		m := numericDate.FindStringSubmatch(s)
+		first, second := atoi(m[1]), atoi(m[2])
+		if d.opts.DayFirst {
+			first, second = second, first
+		}
+		mo, day, y = time.Month(first), second, atoi(m[3])
+		s = s[len(m[0]):]
+	case relativeDate.MatchString(s):
+		m := relativeDate.FindStringSubmatch(s)
+		t := d.today().t
+		switch strings.ToLower(m[1]) {
+		case "tomorrow":
+			t = t.AddDate(0, 0, 1)
+		case "yesterday":
+			t = t.AddDate(0, 0, -1)
+		}
+		y, mo, day = t.Date()
+		s = s[len(m[0]):]
+	default:
+		hasDate = false
+	}
+	s = strings.TrimLeft(s, " ,")
+
+	hour, minute, second := 0, 0, 0
+	hasClock := false
+	if m := clockTime.FindStringSubmatch(s); m != nil {
+		hasClock = true
+		switch {
+		case m[8] != "":
+			if strings.EqualFold(m[8], "midnight") {
+				hour = 0
+			} else {
+				hour = 12
+			}
+		case m[4] != "":
+			hour, minute, second = atoi(m[1]), atoi(m[2]), atoi(m[3])
+			if hour < 1 || hour > 12 {
+				return moment{}, fmt.Errorf("invalid time %q", strings.TrimSpace(m[0]))
+			}
+			hour %= 12
+			if strings.EqualFold(m[4], "pm") {
+				hour += 12
+			}
+		default:
+			hour, minute, second = atoi(m[5]), atoi(m[6]), atoi(m[7])
+		}
+		if hour > 23 || minute > 59 || second > 59 {
+			return moment{}, fmt.Errorf("invalid time %q", strings.TrimSpace(m[0]))
+		}
+		s = strings.TrimSpace(s[len(m[0]):])
+	}
+
+	loc := d.opts.Location
+	hasZone := false
+	if s != "" {
+		zone, err := parseZone(strings.TrimPrefix(s, "in "))
+		if err != nil {
+			return moment{}, fmt.Errorf("cannot parse %q as a date or time", original)
+		}
+		loc, hasZone = zone, true
+	}
+	if !hasDate && !hasClock {
+		return moment{}, fmt.Errorf("cannot parse %q as a date or time", original)
+	}
+	if !hasDate {
+		y, mo, day = d.opts.Reference.In(loc).Date()
+	}
+
+	t := time.Date(y, mo, day, hour, minute, second, 0, loc)
+	if mo < time.January || mo > time.December || t.Day() != day || t.Month() != mo {
+		return moment{}, &invalidDateError{date: strings.TrimSpace(original)}
+	}
+	if m := weekdayPrefix.FindString(original); m != "" {
+		if stated := weekdays[strings.ToLower(strings.TrimRight(m, ", "))]; stated != t.Weekday() {
+			return moment{}, &invalidDateError{date: strings.TrimSpace(original) + fmt.Sprintf(" (it is a %s)", t.Weekday())}
+		}
+	}
+	return moment{t: t, hasTime: hasClock || hasZone}, nil
+}
+
+// year parses an explicit year, defaulting to the reference year.
+func (d *dateParser) year(s string) int {
+	if s == "" {
+		return d.opts.Reference.In(d.opts.Location).Year()
+	}
+	return atoi(s)
+}
+
+// parseZone parses an abbreviation, UTC offset or IANA zone name.
+func parseZone(s string) (*time.Location, error) {
+	if hours, ok := zoneOffsets[strings.ToUpper(s)]; ok {
+		return time.FixedZone(strings.ToUpper(s), int(hours*3600)), nil
+	}
+	if m := offsetZone.FindStringSubmatch(s); m != nil {
+		offset := atoi(m[2])*3600 + atoi(m[3])*60
+		if m[1] == "-" {
+			offset = -offset
+		}
+		return time.FixedZone("UTC"+m[1]+m[2], offset), nil
+	}
+	if strings.Contains(s, "/") {
+		return time.LoadLocation(s)
+	}
+	return nil, fmt.Errorf("unknown time zone %q", s)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
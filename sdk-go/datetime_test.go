@@ -0,0 +1,121 @@
+package qwed
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestVerifyDateTime(t *testing.T) {
+	tests := []struct {
+		claim    string
+		verified bool
+	}{
+		{"90 days after March 3, 2024 is June 1, 2024", true},
+		{"90 days after March 3, 2024 is June 2, 2024", false},
+		{"June 1, 2024 is 90 days after March 3, 2024", true},
+		{"2024-01-31 plus 1 month is 2024-02-29", true},
+		{"2023-01-31 + 1 month = 2023-02-28", true},
+		{"February 29, 2024 plus one year is February 28, 2025", true},
+		{"2 weeks before 1 March 2024 was 16 February 2024", true},
+		{"a fortnight after 2024-12-25 is 2025-01-08", true},
+		{"10 business days after Friday, March 1, 2024 is March 15, 2024", true},
+		{"5 working days after 2024-03-01 is 2024-03-06", false},
+		{"July 4, 2026 is a Saturday", true},
+		{"July 4, 2026 falls on a Friday", false},
+		{"2024-03-09 is a weekend", true},
+		{"2000 is a leap year", true},
+		{"1900 is a leap year", false},
+		{"2100 is not a leap year", true},
+		{"there are 60 days between Jan 1, 2024 and March 1, 2024", true},
+		{"there are 59 days between Jan 1, 2023 and March 1, 2023", true},
+		{"from 2024-01-01 to 2024-12-31 is 365 days", true},
+		{"there are 3 months between 2024-01-31 and 2024-04-30", true},
+		{"3pm EST is 8pm UTC", true},
+		{"3 p.m. EST is 9pm UTC", false},
+		{"09:00 UTC+2 is 07:00 GMT", true},
+		{"2024-03-10T01:30 PST plus 2 hours is 2024-03-10T03:30 PST", true},
+		{"3 days ago was 2024-06-07", true},
+		{"tomorrow is June 11, 2024", true},
+	}
+
+	client := NewClient("test")
+	opts := &DateTimeOptions{Reference: time.Date(2024, time.June, 10, 12, 0, 0, 0, time.UTC)}
+	for _, tt := range tests {
+		t.Run(tt.claim, func(t *testing.T) {
+			resp, err := client.VerifyDateTimeWithOptions(context.Background(), tt.claim, opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Status == StatusUnsupported {
+				t.Fatalf("claim not understood: %v", resp.Result)
+			}
+			if resp.Verified != tt.verified {
+				t.Errorf("expected verified=%v, got %v (%v)", tt.verified, resp.Verified, resp.Result)
+			}
+			if resp.Engine != EngineLocalDateTime {
+				t.Errorf("expected engine %s, got %s", EngineLocalDateTime, resp.Engine)
+			}
+		})
+	}
+}
+
+func TestVerifyDateTimeHolidays(t *testing.T) {
+	opts := DateTimeOptions{Holidays: []time.Time{time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC)}}
+	resp := localVerifyDateTime("2 business days after 2024-12-24 is 2024-12-27", opts)
+	if !resp.Verified {
+		t.Errorf("expected the holiday to be skipped, got %v", resp.Result)
+	}
+
+	opts.Weekend = []time.Weekday{time.Friday, time.Saturday}
+	resp = localVerifyDateTime("1 business day after Thursday 2024-03-07 is 2024-03-10", opts)
+	if !resp.Verified {
+		t.Errorf("expected a Friday-Saturday weekend, got %v", resp.Result)
+	}
+}
+
+func TestVerifyDateTimeDayFirst(t *testing.T) {
+	claim := "03/04/2024 is a Wednesday"
+	if resp := localVerifyDateTime(claim, DateTimeOptions{}); resp.Verified {
+		t.Errorf("expected March 4 (a Monday), got %v", resp.Result)
+	}
+	if resp := localVerifyDateTime(claim, DateTimeOptions{DayFirst: true}); !resp.Verified {
+		t.Errorf("expected 3 April (a Wednesday), got %v", resp.Result)
+	}
+}
+
+func TestVerifyDateTimeInvalidDates(t *testing.T) {
+	for _, claim := range []string{
+		"February 30, 2024 is a Friday",
+		"1 day after 2023-02-29 is 2023-03-01",
+		"Monday, March 1, 2024 plus 1 day is March 2, 2024",
+	} {
+		resp := localVerifyDateTime(claim, DateTimeOptions{})
+		if resp.Status != StatusFailed {
+			t.Errorf("%q: expected %s, got %s (%v)", claim, StatusFailed, resp.Status, resp.Result)
+		}
+	}
+}
+
+func TestVerifyDateTimeUnsupported(t *testing.T) {
+	for _, claim := range []string{
+		"",
+		"the meeting is soon",
+		"3pm XYZ is 8pm UTC",
+	} {
+		resp := localVerifyDateTime(claim, DateTimeOptions{})
+		if resp.Status != StatusUnsupported {
+			t.Errorf("%q: expected %s, got %s (%v)", claim, StatusUnsupported, resp.Status, resp.Result)
+		}
+	}
+}
+
+func TestVerifyDateTimeResult(t *testing.T) {
+	resp := localVerifyDateTime("90 days after March 3, 2024 is June 2, 2024", DateTimeOptions{})
+	if resp.Result["expected"] != "2024-06-01" {
+		t.Errorf("expected 2024-06-01, got %v", resp.Result["expected"])
+	}
+	if resp.Result["weekday"] != "Saturday" {
+		t.Errorf("expected Saturday, got %v", resp.Result["weekday"])
+	}
+}
@@ -0,0 +1,42 @@
+package qwed
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// Deadline Propagation
+// ============================================================================
+
+// HeaderRequestDeadline carries the time the client will wait for a
+// response, in whole milliseconds. Every API request made with a context
+// deadline sends it, so server engines can budget solver time and return
+// an inconclusive result instead of computing an answer nobody receives.
+const HeaderRequestDeadline = "X-Request-Deadline"
+
+// setDeadlineHeader adds HeaderRequestDeadline to h if ctx has a deadline.
+func setDeadlineHeader(ctx context.Context, h http.Header) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+	remaining := time.Until(deadline).Milliseconds()
+	if remaining < 0 {
+		remaining = 0
+	}
+	h.Set(HeaderRequestDeadline, strconv.FormatInt(remaining, 10))
+}
+
+// RequestDeadline reads HeaderRequestDeadline from an incoming request, for
+// servers and proxies in front of the API. It reports false if the header
+// is missing or malformed.
+func RequestDeadline(h http.Header) (time.Duration, bool) {
+	ms, err := strconv.ParseInt(h.Get(HeaderRequestDeadline), 10, 64)
+	if err != nil || ms < 0 {
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
@@ -0,0 +1,72 @@
+package qwed
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDeadlineHeader(t *testing.T) {
+	var header string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(HeaderRequestDeadline)
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	})
+	defer server.Close()
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	if _, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil {
+		t.Fatal(err)
+	}
+	if header != "" {
+		t.Errorf("expected no deadline header without a deadline, got %q", header)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := client.VerifyMath(ctx, "2+2=4"); err != nil {
+		t.Fatal(err)
+	}
+	ms, err := strconv.Atoi(header)
+	if err != nil || ms <= 1000 || ms > 2000 {
+		t.Errorf("expected about 2000ms remaining, got %q", header)
+	}
+}
+
+func TestDeadlineHeaderWithinBudget(t *testing.T) {
+	var header string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(HeaderRequestDeadline)
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	})
+	defer server.Close()
+
+	// The latency budget is tighter than the caller's deadline, so it is
+	// what the server sees.
+	client := NewClient("test-key", WithBaseURL(server.URL), WithLatencyBudget(500*time.Millisecond, SoftFail))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if _, err := client.VerifyMath(ctx, "2+2=4"); err != nil {
+		t.Fatal(err)
+	}
+	if ms, err := strconv.Atoi(header); err != nil || ms > 500 {
+		t.Errorf("expected the budget as deadline, got %q", header)
+	}
+}
+
+func TestRequestDeadline(t *testing.T) {
+	h := http.Header{}
+	if _, ok := RequestDeadline(h); ok {
+		t.Error("expected no deadline without the header")
+	}
+	h.Set(HeaderRequestDeadline, "1500")
+	if d, ok := RequestDeadline(h); !ok || d != 1500*time.Millisecond {
+		t.Errorf("expected 1.5s, got %v, %v", d, ok)
+	}
+	h.Set(HeaderRequestDeadline, "soon")
+	if _, ok := RequestDeadline(h); ok {
+		t.Error("expected a malformed header to be ignored")
+	}
+}
@@ -0,0 +1,190 @@
+package qwed
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// ============================================================================
+// Claim Decomposition
+// ============================================================================
+
+// AtomicClaim is a single checkable statement extracted from a longer text.
+// Start and End are byte offsets into the original text, so
+// text[Start:End] == Text.
+type AtomicClaim struct {
+	Text  string           `json:"text"`
+	Start int              `json:"start"`
+	End   int              `json:"end"`
+	Type  VerificationType `json:"type"` // TypeMath for arithmetic, otherwise TypeFact
+}
+
+// DecomposeClaims splits a compound answer into atomic claims that can be
+// verified individually:
+//
+//	claims, _ := qwed.DecomposeClaims(ctx, answer)
+//	for _, claim := range claims {
+//	    switch claim.Type {
+//	    case qwed.TypeMath:
+//	        resp, err = client.VerifyMath(ctx, claim.Text)
+//	    default:
+//	        resp, err = client.VerifyFact(ctx, claim.Text, sources)
+//	    }
+//	}
+//
+// Text is split into sentences, and sentences are split further at
+// semicolons and at conjunctions joining independent clauses. Questions are
+// not claims and are dropped. Decomposition runs locally.
+func DecomposeClaims(ctx context.Context, paragraph string) ([]AtomicClaim, error) {
+	var claims []AtomicClaim
+	for _, sentence := range splitSentences(paragraph) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(paragraph[sentence[0]:sentence[1]], "?") {
+			continue
+		}
+		for _, span := range splitClauses(paragraph, sentence[0], sentence[1]) {
+			text := paragraph[span[0]:span[1]]
+			if len(strings.Fields(text)) < 2 && !isArithmetic(text) {
+				continue
+			}
+			claims = append(claims, AtomicClaim{
+				Text:  text,
+				Start: span[0],
+				End:   span[1],
+				Type:  claimType(text),
+			})
+		}
+	}
+	return claims, nil
+}
+
+// sentenceAbbreviations end with a period that does not end a sentence.
+var sentenceAbbreviations = wordSet("mr mrs ms dr prof sr jr st vs etc e.g i.e inc ltd co corp approx no fig")
+
+// splitSentences returns the trimmed [start, end) spans of the sentences in
+// text.
+func splitSentences(text string) [][2]int {
+	var spans [][2]int
+	start := 0
+	for i, r := range text {
+		end := -1
+		switch r {
+		case '\n':
+			end = i
+		case '.', '!', '?':
+			next := i + 1
+			if next < len(text) && !unicode.IsSpace(rune(text[next])) {
+				continue
+			}
+			if r == '.' && isAbbreviation(text[start:i]) {
+				continue
+			}
+			end = next
+		}
+		if end < 0 {
+			continue
+		}
+		if span, ok := trimSpan(text, start, end); ok {
+			spans = append(spans, span)
+		}
+		start = i + 1
+	}
+	if span, ok := trimSpan(text, start, len(text)); ok {
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+// isAbbreviation reports whether the word before a period is a known
+// abbreviation or a single-letter initial.
+func isAbbreviation(before string) bool {
+	i := strings.LastIndexFunc(before, unicode.IsSpace)
+	word := strings.ToLower(before[i+1:])
+	if len([]rune(word)) == 1 && unicode.IsLetter([]rune(word)[0]) {
+		return true
+	}
+	return sentenceAbbreviations[word]
+}
+
+// clauseConjunctions join clauses that are split into separate claims when
+// both sides are independent.
+var clauseConjunctions = []string{"; ", ", and ", ", but ", ", while ", ", whereas ", ", so ", " and ", " but ", " whereas "}
+
+// clauseVerbs mark a word sequence as an independent clause.
+var clauseVerbs = wordSet("is are was were has have had will would can could does did do became become remains remained contains contained")
+
+// splitClauses splits the sentence text[start:end] at clause boundaries.
+func splitClauses(text string, start, end int) [][2]int {
+	sentence := text[start:end]
+	for _, conj := range clauseConjunctions {
+		i := strings.Index(sentence, conj)
+		if i < 0 {
+			continue
+		}
+		left, right := sentence[:i], sentence[i+len(conj):]
+		if conj != "; " && !(independentClause(left) && independentClause(right)) {
+			continue
+		}
+
+		var spans [][2]int
+		if span, ok := trimSpan(text, start, start+i); ok {
+			spans = append(spans, span)
+		}
+		return append(spans, splitClauses(text, start+i+len(conj), end)...)
+	}
+
+	if span, ok := trimSpan(text, start, end); ok {
+		return [][2]int{span}
+	}
+	return nil
+}
+
+// independentClause reports whether s looks like it has its own subject and
+// verb: at least three words including a common finite verb.
+func independentClause(s string) bool {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) < 3 {
+		return false
+	}
+	for _, w := range words[1:] {
+		if clauseVerbs[w] {
+			return true
+		}
+	}
+	return false
+}
+
+// trimSpan trims surrounding whitespace and trailing commas from
+// text[start:end], reporting false if nothing is left.
+func trimSpan(text string, start, end int) ([2]int, bool) {
+	for start < end && unicode.IsSpace(rune(text[start])) {
+		start++
+	}
+	for end > start && (unicode.IsSpace(rune(text[end-1])) || text[end-1] == ',') {
+		end--
+	}
+	return [2]int{start, end}, start < end
+}
+
+// claimType picks the engine for a claim.
+func claimType(text string) VerificationType {
+	if isArithmetic(text) {
+		return TypeMath
+	}
+	return TypeFact
+}
+
+// isArithmetic reports whether text is an equation or inequality between
+// numeric expressions, such as "2 + 2 = 4" or "15% of 200 = 30".
+func isArithmetic(text string) bool {
+	if !strings.ContainsAny(text, "0123456789") {
+		return false
+	}
+	lhs, op, rhs := splitComparison(strings.TrimRight(text, ".!"))
+	return op != "" && strings.ContainsAny(lhs, "0123456789") && strings.ContainsAny(rhs, "0123456789")
+}
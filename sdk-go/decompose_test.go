@@ -0,0 +1,50 @@
+package qwed
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDecomposeClaims(t *testing.T) {
+	paragraph := "The Eiffel Tower was completed in 1889 and it is 330 metres tall. " +
+		"Dr. Smith says 2 + 2 = 4; the answer is final. Is that right? " +
+		"Paris, Lyon and Nice are French cities.\nRevenue was $5.2M, but costs were higher."
+
+	claims, err := DecomposeClaims(context.Background(), paragraph)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		text string
+		typ  VerificationType
+	}{
+		{"The Eiffel Tower was completed in 1889", TypeFact},
+		{"it is 330 metres tall.", TypeFact},
+		{"Dr. Smith says 2 + 2 = 4", TypeMath},
+		{"the answer is final.", TypeFact},
+		{"Paris, Lyon and Nice are French cities.", TypeFact},
+		{"Revenue was $5.2M", TypeFact},
+		{"costs were higher.", TypeFact},
+	}
+	if len(claims) != len(want) {
+		t.Fatalf("expected %d claims, got %d: %+v", len(want), len(claims), claims)
+	}
+	for i, w := range want {
+		c := claims[i]
+		if c.Text != w.text || c.Type != w.typ {
+			t.Errorf("claim %d: got %q (%s), want %q (%s)", i, c.Text, c.Type, w.text, w.typ)
+		}
+		if paragraph[c.Start:c.End] != c.Text {
+			t.Errorf("claim %d: offsets point at %q", i, paragraph[c.Start:c.End])
+		}
+	}
+}
+
+func TestDecomposeClaimsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := DecomposeClaims(ctx, "A is B."); err == nil {
+		t.Error("expected context error")
+	}
+}
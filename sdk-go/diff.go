@@ -0,0 +1,174 @@
+package qwed
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ============================================================================
+// Verdict Diffing
+// ============================================================================
+
+// VerdictDiff describes how a verification result changed between two runs.
+type VerdictDiff struct {
+	VerdictChanged bool   `json:"verdict_changed"`
+	OldVerified    bool   `json:"old_verified"`
+	NewVerified    bool   `json:"new_verified"`
+	OldStatus      Status `json:"old_status,omitempty"`
+	NewStatus      Status `json:"new_status,omitempty"`
+	OldEngine      Engine `json:"old_engine,omitempty"`
+	NewEngine      Engine `json:"new_engine,omitempty"`
+
+	// ConfidenceDelta is new minus old confidence; only meaningful when
+	// HasConfidence is set, i.e. both results reported a confidence.
+	ConfidenceDelta float64 `json:"confidence_delta,omitempty"`
+	HasConfidence   bool    `json:"has_confidence,omitempty"`
+
+	AddedFindings   []string `json:"added_findings,omitempty"`
+	RemovedFindings []string `json:"removed_findings,omitempty"`
+}
+
+// resultFindingKeys are the Result fields engines use to list findings.
+var resultFindingKeys = []string{"findings", "vulnerabilities", "issues", "violations", "errors"}
+
+// DiffResponses compares two responses for the same input. A nil response is
+// treated as an empty, unverified result.
+func DiffResponses(old, new *VerificationResponse) VerdictDiff {
+	if old == nil {
+		old = &VerificationResponse{}
+	}
+	if new == nil {
+		new = &VerificationResponse{}
+	}
+
+	d := VerdictDiff{
+		OldVerified: old.Verified,
+		NewVerified: new.Verified,
+		OldStatus:   old.Status,
+		NewStatus:   new.Status,
+		OldEngine:   old.Engine,
+		NewEngine:   new.Engine,
+	}
+	d.VerdictChanged = old.Verified != new.Verified || old.Status != new.Status
+
+	oldConf, okOld := confidence(old)
+	newConf, okNew := confidence(new)
+	if okOld && okNew {
+		d.HasConfidence = true
+		d.ConfidenceDelta = newConf - oldConf
+	}
+
+	oldFindings, newFindings := findingSet(old), findingSet(new)
+	for f := range newFindings {
+		if !oldFindings[f] {
+			d.AddedFindings = append(d.AddedFindings, f)
+		}
+	}
+	for f := range oldFindings {
+		if !newFindings[f] {
+			d.RemovedFindings = append(d.RemovedFindings, f)
+		}
+	}
+	sort.Strings(d.AddedFindings)
+	sort.Strings(d.RemovedFindings)
+
+	return d
+}
+
+// Changed reports whether anything meaningful differs.
+func (d VerdictDiff) Changed() bool {
+	return d.VerdictChanged ||
+		d.OldEngine != d.NewEngine ||
+		(d.HasConfidence && d.ConfidenceDelta != 0) ||
+		len(d.AddedFindings) > 0 ||
+		len(d.RemovedFindings) > 0
+}
+
+// String renders the diff as a one-line changelog entry.
+func (d VerdictDiff) String() string {
+	if !d.Changed() {
+		return "no change"
+	}
+
+	var parts []string
+	if d.VerdictChanged {
+		parts = append(parts, fmt.Sprintf("verdict %s -> %s", verdictLabel(d.OldVerified, d.OldStatus), verdictLabel(d.NewVerified, d.NewStatus)))
+	}
+	if d.OldEngine != d.NewEngine {
+		parts = append(parts, fmt.Sprintf("engine %q -> %q", d.OldEngine, d.NewEngine))
+	}
+	if d.HasConfidence && d.ConfidenceDelta != 0 {
+		parts = append(parts, fmt.Sprintf("confidence %+.3f", d.ConfidenceDelta))
+	}
+	if len(d.AddedFindings) > 0 {
+		parts = append(parts, "added "+strings.Join(d.AddedFindings, ", "))
+	}
+	if len(d.RemovedFindings) > 0 {
+		parts = append(parts, "removed "+strings.Join(d.RemovedFindings, ", "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func verdictLabel(verified bool, status Status) string {
+	if status != "" {
+		return string(status)
+	}
+	if verified {
+		return "verified"
+	}
+	return "unverified"
+}
+
+// confidence extracts a numeric "confidence" from the result payload.
+func confidence(resp *VerificationResponse) (float64, bool) {
+	switch v := resp.Result["confidence"].(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// findingSet collects finding identifiers from the result payload. Findings
+// may be plain strings or objects; objects are identified by their "id",
+// "rule_id", "rule" or "type" field, falling back to their JSON encoding.
+func findingSet(resp *VerificationResponse) map[string]bool {
+	set := make(map[string]bool)
+	for _, key := range resultFindingKeys {
+		switch list := resp.Result[key].(type) {
+		case []interface{}:
+			for _, item := range list {
+				set[findingID(item)] = true
+			}
+		case []string:
+			for _, item := range list {
+				set[item] = true
+			}
+		}
+	}
+	return set
+}
+
+func findingID(item interface{}) string {
+	switch v := item.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		for _, field := range []string{"id", "rule_id", "rule", "type"} {
+			if s, ok := v[field].(string); ok && s != "" {
+				if line, ok := v["line"].(float64); ok {
+					return fmt.Sprintf("%s@%d", s, int(line))
+				}
+				return s
+			}
+		}
+	}
+	data, _ := json.Marshal(item)
+	return string(data)
+}
@@ -0,0 +1,51 @@
+package qwed
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiffResponses(t *testing.T) {
+	var old, new VerificationResponse
+	json.Unmarshal([]byte(`{
+		"status": "VERIFIED", "verified": true, "engine": "code",
+		"result": {"confidence": 0.9, "vulnerabilities": ["eval_usage", {"rule_id": "sql_injection", "line": 4}]}
+	}`), &old)
+	json.Unmarshal([]byte(`{
+		"status": "FAILED", "verified": false, "engine": "code",
+		"result": {"confidence": 0.75, "vulnerabilities": [{"rule_id": "sql_injection", "line": 4}, "hardcoded_secret"]}
+	}`), &new)
+
+	d := DiffResponses(&old, &new)
+
+	if !d.VerdictChanged || !d.Changed() {
+		t.Error("expected verdict change")
+	}
+	if !d.HasConfidence || d.ConfidenceDelta > -0.149 || d.ConfidenceDelta < -0.151 {
+		t.Errorf("expected confidence delta -0.15, got %v", d.ConfidenceDelta)
+	}
+	if len(d.AddedFindings) != 1 || d.AddedFindings[0] != "hardcoded_secret" {
+		t.Errorf("unexpected added findings: %v", d.AddedFindings)
+	}
+	if len(d.RemovedFindings) != 1 || d.RemovedFindings[0] != "eval_usage" {
+		t.Errorf("unexpected removed findings: %v", d.RemovedFindings)
+	}
+
+	want := "verdict VERIFIED -> FAILED; confidence -0.150; added hardcoded_secret; removed eval_usage"
+	if d.String() != want {
+		t.Errorf("expected %q, got %q", want, d.String())
+	}
+}
+
+func TestDiffResponsesUnchanged(t *testing.T) {
+	resp := &VerificationResponse{Status: StatusVerified, Verified: true, Engine: "math"}
+
+	d := DiffResponses(resp, resp)
+	if d.Changed() || d.String() != "no change" {
+		t.Errorf("expected no change, got %+v", d)
+	}
+
+	if !DiffResponses(nil, resp).VerdictChanged {
+		t.Error("expected nil old response to differ from verified")
+	}
+}
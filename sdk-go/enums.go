@@ -0,0 +1,136 @@
+package qwed
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Statuses and Engines
+// ============================================================================
+
+// Engine is the engine that produced a response.
+type Engine string
+
+// Engines of the API. Responses produced without the API report the
+// EngineLocal engines.
+const (
+	EngineNaturalLanguage Engine = "natural_language"
+	EngineMath            Engine = "math"
+	EngineLogic           Engine = "logic"
+	EngineStats           Engine = "stats"
+	EngineFact            Engine = "fact"
+	EngineCode            Engine = "code"
+	EngineSQL             Engine = "sql"
+	EngineImage           Engine = "image"
+	EngineReasoning       Engine = "reasoning"
+	EngineJSON            Engine = "json"
+	EnginePromptSafety    Engine = "prompt_safety"
+	EngineInfra           Engine = "infra"
+	EngineGraphQL         Engine = "graphql"
+	EngineChart           Engine = "chart"
+	EngineTests           Engine = "tests"
+	EngineConstraints     Engine = "constraints"
+	EngineOptimization    Engine = "optimization"
+	EngineGraph           Engine = "graph"
+	EngineCompliance      Engine = "compliance"
+	EngineExtraction      Engine = "extraction"
+
+	// UnknownEngine is the engine of responses from an engine this SDK
+	// does not know, such as one added to the API after this release.
+	UnknownEngine Engine = "unknown"
+)
+
+var (
+	knownStatuses = enumSet(StatusVerified, StatusFailed, StatusCorrected, StatusBlocked, StatusError,
+		StatusTimeout, StatusUnsupported, StatusInconclusive, StatusUnknown)
+	knownEngines = enumSet(EngineNaturalLanguage, EngineMath, EngineLogic, EngineStats, EngineFact,
+		EngineCode, EngineSQL, EngineImage, EngineReasoning, EngineJSON, EnginePromptSafety, EngineInfra,
+		EngineGraphQL, EngineChart, EngineTests, EngineConstraints, EngineOptimization, EngineGraph,
+		EngineCompliance, EngineExtraction,
+		EngineLocalMath, EngineLocalLogic, EngineLocalUnits, EngineLocalDateTime, EngineLocalRegex,
+		EngineLocalTable, EngineLocalFormula, EngineLocalCitations, EngineLocalFormat, EngineLocalProbability, UnknownEngine)
+)
+
+// enumSet indexes enum values by their lowercase names.
+func enumSet[T ~string](values ...T) map[string]T {
+	set := make(map[string]T, len(values))
+	for _, v := range values {
+		set[strings.ToLower(string(v))] = v
+	}
+	return set
+}
+
+// String returns the status name, e.g. "VERIFIED".
+func (s Status) String() string {
+	return string(s)
+}
+
+// Known reports whether s is one of the Status constants other than
+// StatusUnknown.
+func (s Status) Known() bool {
+	return s != StatusUnknown && knownStatuses[strings.ToLower(string(s))] == s
+}
+
+// MarshalText encodes the status name, rejecting names that are not valid
+// JSON-safe identifiers.
+func (s Status) MarshalText() ([]byte, error) {
+	return marshalEnum("status", string(s))
+}
+
+// UnmarshalText decodes a status name case-insensitively. Names this SDK
+// does not know decode as StatusUnknown, so a switch over statuses never
+// mistakes a new status for a known one; the name sent by the API stays
+// in the response's Raw field "status".
+func (s *Status) UnmarshalText(text []byte) error {
+	*s = parseEnum(knownStatuses, string(text), StatusUnknown)
+	return nil
+}
+
+// String returns the engine name, e.g. "math".
+func (e Engine) String() string {
+	return string(e)
+}
+
+// Known reports whether e is one of the Engine constants other than
+// UnknownEngine.
+func (e Engine) Known() bool {
+	return e != UnknownEngine && knownEngines[strings.ToLower(string(e))] == e
+}
+
+// MarshalText encodes the engine name, rejecting names that are not valid
+// JSON-safe identifiers.
+func (e Engine) MarshalText() ([]byte, error) {
+	return marshalEnum("engine", string(e))
+}
+
+// UnmarshalText decodes an engine name case-insensitively. Names this SDK
+// does not know decode as UnknownEngine; the name sent by the API stays in
+// the response's Raw field "engine".
+func (e *Engine) UnmarshalText(text []byte) error {
+	*e = parseEnum(knownEngines, string(text), UnknownEngine)
+	return nil
+}
+
+// parseEnum returns the value of set named name, "" for an empty name, or
+// unknown.
+func parseEnum[T ~string](set map[string]T, name string, unknown T) T {
+	if name == "" {
+		return ""
+	}
+	if v, ok := set[strings.ToLower(name)]; ok {
+		return v
+	}
+	return unknown
+}
+
+// marshalEnum checks that an enum name holds only letters, digits, '_' and
+// '-', as every status and engine name does.
+func marshalEnum(kind, name string) ([]byte, error) {
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return nil, fmt.Errorf("qwed: invalid %s name %q", kind, name)
+		}
+	}
+	return []byte(name), nil
+}
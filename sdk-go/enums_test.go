@@ -0,0 +1,104 @@
+package qwed
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnumsDecode(t *testing.T) {
+	var resp VerificationResponse
+	if err := json.Unmarshal([]byte(`{"status":"verified","verified":true,"engine":"Math"}`), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != StatusVerified || resp.Engine != EngineMath {
+		t.Errorf("expected case-insensitive decoding, got %q %q", resp.Status, resp.Engine)
+	}
+	if !resp.Status.Known() || !resp.Engine.Known() || len(resp.Raw) != 0 {
+		t.Errorf("expected known names without raw fields, got %v", resp.Raw)
+	}
+	if resp.Status.String() != "VERIFIED" || resp.Engine.String() != "math" {
+		t.Errorf("unexpected names %s %s", resp.Status, resp.Engine)
+	}
+}
+
+func TestEnumsUnknownFallback(t *testing.T) {
+	data := []byte(`{"status":"QUARANTINED","verified":false,"engine":"quantum","schema_version":2}`)
+
+	var resp VerificationResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != StatusUnknown || resp.Engine != UnknownEngine {
+		t.Fatalf("expected unknown fallbacks, got %q %q", resp.Status, resp.Engine)
+	}
+	if resp.Status.Known() || resp.Engine.Known() {
+		t.Error("expected the fallbacks not to be known")
+	}
+
+	var status, engine string
+	if !resp.RawField("status", &status) || status != "QUARANTINED" {
+		t.Errorf("expected the server's status in Raw, got %q", status)
+	}
+	if !resp.RawField("engine", &engine) || engine != "quantum" {
+		t.Errorf("expected the server's engine in Raw, got %q", engine)
+	}
+
+	out, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(out, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["status"] != "QUARANTINED" || fields["engine"] != "quantum" {
+		t.Errorf("expected a lossless round trip, got %s", out)
+	}
+}
+
+func TestEnumsBatchResultUnknownStatus(t *testing.T) {
+	var r BatchResult
+	if err := json.Unmarshal([]byte(`{"id":"1","status":"DEFERRED","schema_version":2}`), &r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != StatusUnknown {
+		t.Fatalf("expected StatusUnknown, got %q", r.Status)
+	}
+	out, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(out, &fields); err != nil {
+		t.Fatal(err)
+	}
+	if fields["status"] != "DEFERRED" {
+		t.Errorf("expected the server's status written back, got %s", out)
+	}
+}
+
+func TestEnumsMarshal(t *testing.T) {
+	if _, err := json.Marshal(Engine("bad engine\"")); err == nil {
+		t.Error("expected an invalid engine name to fail")
+	}
+	if _, err := json.Marshal(Status("OK!")); err == nil {
+		t.Error("expected an invalid status name to fail")
+	}
+	data, err := json.Marshal(EnginePromptSafety)
+	if err != nil || string(data) != `"prompt_safety"` {
+		t.Errorf("unexpected encoding %s: %v", data, err)
+	}
+	if !EngineLocalMath.Known() || Engine("custom").Known() {
+		t.Error("unexpected Known results")
+	}
+}
+
+func TestEnumsNonString(t *testing.T) {
+	var resp VerificationResponse
+	if err := json.Unmarshal([]byte(`{"status":1}`), &resp); err == nil {
+		t.Error("expected a numeric status to fail")
+	}
+	if err := json.Unmarshal([]byte(`{"status":"VERIFIED","engine":""}`), &resp); err != nil || resp.Engine != "" {
+		t.Errorf("expected an empty engine to stay empty, got %q: %v", resp.Engine, err)
+	}
+}
@@ -0,0 +1,98 @@
+package qwed
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+)
+
+// ============================================================================
+// Context Extraction
+// ============================================================================
+
+// ExtractOptions configures ExtractContext.
+type ExtractOptions struct {
+	// Pages selects the pages to extract, e.g. "1-3,7". Empty extracts all.
+	Pages string
+	// OCR recognises text in scanned pages that have no text layer.
+	OCR bool
+}
+
+// ExtractedContext is the text and tables of a document, ready to be used
+// as verification context.
+type ExtractedContext struct {
+	// DocumentID identifies the document in the service; it prefixes the
+	// IDs of its tables.
+	DocumentID string           `json:"document_id"`
+	Text       string           `json:"text"` // cleaned text of the selected pages, without headers, footers and tables
+	Pages      []ExtractedPage  `json:"pages,omitempty"`
+	Tables     []ExtractedTable `json:"tables,omitempty"`
+}
+
+// ExtractedPage is the cleaned text of one page.
+type ExtractedPage struct {
+	Number int    `json:"number"` // 1-based
+	Text   string `json:"text"`
+}
+
+// ExtractedTable is a table found in a document.
+type ExtractedTable struct {
+	ID      string     `json:"id"` // e.g. "doc_8f2c#t3"
+	Page    int        `json:"page"`
+	Caption string     `json:"caption,omitempty"`
+	Header  []string   `json:"header"`
+	Rows    [][]string `json:"rows"`
+}
+
+// ExtractContext extracts the text and tables of a PDF so they can be used
+// as context for verification: Text for VerifyFact and each table's CSV as
+// the source data of VerifyTable:
+//
+//	doc, err := client.ExtractContext(ctx, pdf, qwed.ExtractOptions{})
+//	resp, err := client.VerifyFact(ctx, claim, doc.Text)
+//	resp, err = client.VerifyTable(ctx, answerTable, doc.Tables[0].CSV())
+//
+// With strict validation, input that is not a PDF is rejected locally.
+func (c *Client) ExtractContext(ctx context.Context, pdf []byte, opts ExtractOptions) (*ExtractedContext, error) {
+	if c.strict && !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		return nil, invalidRequest("input is not a PDF document")
+	}
+
+	req := map[string]interface{}{"pdf": pdf}
+	if opts.Pages != "" {
+		req["pages"] = opts.Pages
+	}
+	if opts.OCR {
+		req["ocr"] = true
+	}
+
+	ctx, end := c.instrument(ctx, "ExtractContext", "")
+	var doc ExtractedContext
+	err := c.request(ctx, "POST", "/extract", req, &doc)
+	end(nil, err)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Table returns the table with the given ID, or nil.
+func (e *ExtractedContext) Table(id string) *ExtractedTable {
+	for i := range e.Tables {
+		if e.Tables[i].ID == id {
+			return &e.Tables[i]
+		}
+	}
+	return nil
+}
+
+// CSV encodes the table as CSV with a header row, the source data format
+// of VerifyTable.
+func (t ExtractedTable) CSV() string {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	w.Write(t.Header)
+	w.WriteAll(t.Rows)
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package qwed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestExtractContext(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n...")
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/extract" {
+			t.Errorf("expected path /extract, got %s", r.URL.Path)
+		}
+		var req struct {
+			PDF   []byte `json:"pdf"`
+			Pages string `json:"pages"`
+			OCR   bool   `json:"ocr"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if !bytes.Equal(req.PDF, pdf) || req.Pages != "1-2" || !req.OCR {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		w.Write([]byte(`{
+			"document_id": "doc_1",
+			"text": "Revenue grew 12% in 2023.",
+			"pages": [{"number": 1, "text": "Revenue grew 12% in 2023."}],
+			"tables": [{"id": "doc_1#t1", "page": 2, "header": ["Region", "Revenue"], "rows": [["EMEA", "1,200"], ["APAC", "900"]]}]
+		}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	doc, err := client.ExtractContext(context.Background(), pdf, ExtractOptions{Pages: "1-2", OCR: true})
+	if err != nil {
+		t.Fatalf("ExtractContext() error = %v", err)
+	}
+	if doc.DocumentID != "doc_1" || doc.Text == "" || len(doc.Pages) != 1 {
+		t.Errorf("unexpected document: %+v", doc)
+	}
+
+	table := doc.Table("doc_1#t1")
+	if table == nil || table.Page != 2 {
+		t.Fatalf("Table() = %+v", table)
+	}
+	if got, want := table.CSV(), "Region,Revenue\nEMEA,\"1,200\"\nAPAC,900\n"; got != want {
+		t.Errorf("CSV() = %q, want %q", got, want)
+	}
+	if doc.Table("doc_1#t9") != nil {
+		t.Error("expected no table for an unknown ID")
+	}
+
+	resp, err := client.VerifyTable(context.Background(), "| Region | Revenue |\n|---|---|\n| EMEA | 1,200 |", table.CSV())
+	if err != nil || !resp.Verified {
+		t.Errorf("VerifyTable() = %+v, %v; want the extracted table usable as source", resp, err)
+	}
+}
+
+func TestExtractContextStrict(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"), WithStrictValidation())
+	if _, err := client.ExtractContext(context.Background(), []byte("<html>"), ExtractOptions{}); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("ExtractContext() error = %v, want ErrInvalidRequest", err)
+	}
+}
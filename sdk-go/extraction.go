@@ -0,0 +1,80 @@
+package qwed
+
+import (
+	"context"
+)
+
+// ============================================================================
+// Extraction Fidelity Verification
+// ============================================================================
+
+// FieldSupport is how an extracted value is backed by the source document.
+type FieldSupport string
+
+const (
+	FieldFound       FieldSupport = "found"       // the value appears in the source
+	FieldDerived     FieldSupport = "derived"     // the value is computed or normalized from the source, e.g. a total or an ISO date
+	FieldUnsupported FieldSupport = "unsupported" // the source does not back the value
+)
+
+// ExtractedField is the verdict on one extracted value.
+type ExtractedField struct {
+	Path    string       `json:"path"` // JSON Pointer to the value, e.g. "/line_items/0/amount"
+	Value   interface{}  `json:"value"`
+	Support FieldSupport `json:"support"`
+	// Evidence quotes the source text backing the value, with its byte
+	// offsets in the source.
+	Evidence string `json:"evidence,omitempty"`
+	Start    int    `json:"start,omitempty"`
+	End      int    `json:"end,omitempty"`
+	Reason   string `json:"reason,omitempty"` // e.g. "sum of line item amounts"
+}
+
+// VerifyExtraction checks that every value in extractedJSON, the output of
+// LLM-powered document processing, appears in or is derivable from
+// sourceDocument, catching hallucinated fields:
+//
+//	resp, err := client.VerifyExtraction(ctx, invoiceText, `{"invoice_no": "INV-042", "total": 1250.00}`, schema)
+//	for _, f := range qwed.UnsupportedFields(resp) {
+//		log.Printf("%s: %v is not in the invoice", f.Path, f.Value)
+//	}
+//
+// schema is the JSON Schema the extraction followed, or empty. When given,
+// the extraction is also checked against it; use SchemaViolations to
+// decode the violations. The response is verified when every value is
+// supported and the extraction conforms to the schema.
+func (c *Client) VerifyExtraction(ctx context.Context, sourceDocument, extractedJSON, schema string, callOpts ...CallOption) (*VerificationResponse, error) {
+	req := map[string]interface{}{
+		"source":    sourceDocument,
+		"extracted": extractedJSON,
+	}
+	if schema != "" {
+		req["schema"] = schema
+	}
+
+	return c.verify(ctx, "VerifyExtraction", TypeExtraction, CacheKey(TypeExtraction, contentHash([]byte(sourceDocument)), extractedJSON, schema), req, callOpts...)
+}
+
+// ExtractedFields extracts the per-value verdicts from a VerifyExtraction
+// response.
+func ExtractedFields(resp *VerificationResponse) []ExtractedField {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var fields []ExtractedField
+	decodeResult(resp.Result["fields"], &fields)
+	return fields
+}
+
+// UnsupportedFields returns the extracted values the source document does
+// not back.
+func UnsupportedFields(resp *VerificationResponse) []ExtractedField {
+	var unsupported []ExtractedField
+	for _, f := range ExtractedFields(resp) {
+		if f.Support == FieldUnsupported {
+			unsupported = append(unsupported, f)
+		}
+	}
+	return unsupported
+}
@@ -0,0 +1,75 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const invoiceText = "Invoice INV-042\nDate: 3 March 2025\nWidgets 2 x 500.00\nTotal due: 1,000.00 EUR"
+
+func TestVerifyExtraction(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/extraction" {
+			t.Errorf("expected path /verify/extraction, got %s", r.URL.Path)
+		}
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["source"] != invoiceText || req["extracted"] == "" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+		if _, ok := req["schema"]; ok {
+			t.Error("expected no schema when none is given")
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": false,
+			"engine":   "extraction",
+			"result": map[string]interface{}{
+				"fields": []map[string]interface{}{
+					{"path": "/invoice_no", "value": "INV-042", "support": "found", "evidence": "INV-042", "start": 8, "end": 15},
+					{"path": "/date", "value": "2025-03-03", "support": "derived", "reason": "normalized from 3 March 2025"},
+					{"path": "/vendor", "value": "Acme GmbH", "support": "unsupported", "reason": "no vendor in the source"},
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	extracted := `{"invoice_no": "INV-042", "date": "2025-03-03", "vendor": "Acme GmbH"}`
+	resp, err := client.VerifyExtraction(context.Background(), invoiceText, extracted, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Verified || resp.Engine != EngineExtraction {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	fields := ExtractedFields(resp)
+	if len(fields) != 3 || fields[0].Support != FieldFound || fields[0].End != 15 || fields[1].Support != FieldDerived {
+		t.Errorf("unexpected fields: %+v", fields)
+	}
+	unsupported := UnsupportedFields(resp)
+	if len(unsupported) != 1 || unsupported[0].Path != "/vendor" || unsupported[0].Value != "Acme GmbH" {
+		t.Errorf("unexpected unsupported fields: %+v", unsupported)
+	}
+	if ExtractedFields(&VerificationResponse{}) != nil {
+		t.Error("expected no fields without a result")
+	}
+}
+
+func TestVerifyExtractionStrictValidation(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"), WithStrictValidation())
+	ctx := context.Background()
+
+	if _, err := client.VerifyExtraction(ctx, " ", `{"a": 1}`, ""); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest without a source, got %v", err)
+	}
+	if _, err := client.VerifyExtraction(ctx, invoiceText, `{"invoice_no": `, ""); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for malformed JSON, got %v", err)
+	}
+}
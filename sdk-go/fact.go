@@ -0,0 +1,413 @@
+package qwed
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ============================================================================
+// Fact Verification
+// ============================================================================
+
+// FactOptions controls language handling for VerifyFactWithOptions.
+// Languages are ISO 639-1 codes such as "en" or "de".
+type FactOptions struct {
+	// Language is the language of the claim. Empty detects it.
+	Language string
+
+	// ContextLanguage is the language of the context. Empty detects it.
+	// It may differ from Language; the request is then sent in
+	// cross-lingual mode.
+	ContextLanguage string
+
+	// DisableDetection sends only the languages set explicitly.
+	DisableDetection bool
+}
+
+// VerifyFactWithOptions verifies a factual claim against context with
+// explicit language settings. Claims and contexts in different languages,
+// for example an English claim checked against a German source, are
+// supported.
+func (c *Client) VerifyFactWithOptions(ctx context.Context, claim, factContext string, opts *FactOptions, callOpts ...CallOption) (*VerificationResponse, error) {
+	var o FactOptions
+	if opts != nil {
+		o = *opts
+	}
+	if !o.DisableDetection {
+		if o.Language == "" {
+			o.Language = DetectLanguage(claim)
+		}
+		if o.ContextLanguage == "" {
+			o.ContextLanguage = DetectLanguage(factContext)
+		}
+	}
+
+	req := map[string]interface{}{
+		"claim":   claim,
+		"context": factContext,
+	}
+	if o.Language != "" {
+		req["language"] = o.Language
+	}
+	if o.ContextLanguage != "" {
+		req["context_language"] = o.ContextLanguage
+	}
+	if o.Language != "" && o.ContextLanguage != "" && o.Language != o.ContextLanguage {
+		req["cross_lingual"] = true
+	}
+
+	key := CacheKey(TypeFact, claim, factContext, o.Language, o.ContextLanguage)
+	resp, err := c.verify(ctx, "VerifyFact", TypeFact, key, req, callOpts...)
+	if err == nil {
+		resp = annotateAlignment(claim, factContext, resp)
+	}
+	return resp, err
+}
+
+// ============================================================================
+// Language Detection
+// ============================================================================
+
+// scriptLanguages maps writing systems used by a single common language to
+// that language.
+var scriptLanguages = []struct {
+	table *unicode.RangeTable
+	lang  string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Greek, "el"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+}
+
+// stopwords lists frequent function words of Latin-script languages, in
+// tie-breaking order.
+var stopwords = []struct {
+	lang  string
+	words map[string]bool
+}{
+	{"en", wordSet("the is are was were of and to in that it with for on as by this be has not")},
+	{"de", wordSet("der die das und ist sind nicht ein eine von mit den dem des zu auf für im war auch")},
+	{"fr", wordSet("le la les est sont et un une des du de que pas pour dans sur au avec ce il")},
+	{"es", wordSet("el la los las es son y un una de del que no por para en con se fue está")},
+	{"it", wordSet("il lo la gli le è sono e un una di del che non per in con della si")},
+	{"pt", wordSet("o a os as é são e um uma de do da que não para em com no na foi")},
+	{"nl", wordSet("de het een is zijn en van niet dat op met voor in te ook was wordt aan bij die")},
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+// DetectLanguage guesses the ISO 639-1 language of text. Non-Latin scripts
+// are identified by their characters and Latin-script text by common
+// function words. It returns "" if the language cannot be determined.
+func DetectLanguage(text string) string {
+	letters := 0
+	counts := make(map[string]int)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range scriptLanguages {
+			if unicode.Is(s.table, r) {
+				counts[s.lang]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+
+	// Japanese mixes kana with Han characters, so any kana decides.
+	if counts["ja"] > 0 {
+		return "ja"
+	}
+	for _, s := range scriptLanguages {
+		if counts[s.lang]*2 > letters {
+			if s.lang == "ru" && strings.ContainsAny(strings.ToLower(text), "іїєґ") {
+				return "uk"
+			}
+			return s.lang
+		}
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	best, bestScore := "", 0
+	for _, sw := range stopwords {
+		score := 0
+		for _, w := range words {
+			if sw.words[w] {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = sw.lang, score
+		}
+	}
+	return best
+}
+
+// ============================================================================
+// Entity Alignment
+// ============================================================================
+
+// EntityKind classifies an entity extracted from a claim.
+type EntityKind string
+
+const (
+	EntityNumber EntityKind = "number"
+	EntityYear   EntityKind = "year"
+	EntityName   EntityKind = "name"
+)
+
+// AlignmentStatus describes how a claim entity relates to the context.
+type AlignmentStatus string
+
+const (
+	AlignmentMatched  AlignmentStatus = "matched"  // the context states the same value
+	AlignmentMissing  AlignmentStatus = "missing"  // the context does not mention it
+	AlignmentConflict AlignmentStatus = "conflict" // the context states a different value
+)
+
+// EntityAlignment links an entity in the claim to the context. Start and
+// End are byte offsets into the claim so UIs can highlight the span.
+type EntityAlignment struct {
+	Kind    EntityKind      `json:"kind"`
+	Text    string          `json:"text"`
+	Start   int             `json:"start"`
+	End     int             `json:"end"`
+	Status  AlignmentStatus `json:"status"`
+	Context string          `json:"context,omitempty"` // matching or conflicting text in the context
+}
+
+// FactAlignment extracts the entity alignment from a VerifyFact response.
+func FactAlignment(resp *VerificationResponse) []EntityAlignment {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var alignment []EntityAlignment
+	decodeResult(resp.Result["alignment"], &alignment)
+	return alignment
+}
+
+// annotateAlignment returns a copy of a VerifyFact response with the
+// locally computed alignment, unless the server already provided one. resp
+// may be a cached response shared with other callers, so it is not
+// modified.
+func annotateAlignment(claim, factContext string, resp *VerificationResponse) *VerificationResponse {
+	if resp == nil {
+		return resp
+	}
+	if _, ok := resp.Result["alignment"]; ok {
+		return resp
+	}
+
+	out := *resp
+	out.Result = make(map[string]interface{}, len(resp.Result)+1)
+	for k, v := range resp.Result {
+		out.Result[k] = v
+	}
+	out.Result["alignment"] = AlignEntities(claim, factContext)
+	return &out
+}
+
+// AlignEntities extracts numbers, years and proper names from claim and
+// reports whether each is matched, missing or contradicted in factContext.
+// A number conflicts when the context attaches a different number to the
+// same word, as in "330 metres" against "324 metres".
+func AlignEntities(claim, factContext string) []EntityAlignment {
+	claimTokens := scanEntityTokens(claim)
+	contextTokens := scanEntityTokens(factContext)
+
+	names := make(map[string]bool)
+	common := make(map[string]bool)
+	for _, tokens := range [][]entityToken{claimTokens, contextTokens} {
+		for _, t := range tokens {
+			if !t.capitalized {
+				common[t.text] = true
+			}
+		}
+	}
+	for _, t := range contextTokens {
+		if t.capitalized {
+			names[t.text] = true
+		}
+	}
+
+	alignment := []EntityAlignment{}
+	for i, t := range claimTokens {
+		a := EntityAlignment{Text: t.text, Start: t.start, End: t.end, Status: AlignmentMissing}
+		switch {
+		case t.number:
+			a.Kind = EntityNumber
+			if t.year() {
+				a.Kind = EntityYear
+			}
+			if match, ok := findNumber(contextTokens, t.value); ok {
+				a.Status, a.Context = AlignmentMatched, match
+			} else if other, ok := conflictingNumber(claimTokens, i, contextTokens); ok {
+				a.Status, a.Context = AlignmentConflict, other
+			}
+		case t.capitalized:
+			// A capitalized sentence-initial word is taken as a name unless
+			// it is a function word or also appears in lower case.
+			if t.sentenceStart && (isStopword(t.text) || common[strings.ToLower(t.text)]) {
+				continue
+			}
+			a.Kind = EntityName
+			if names[t.text] {
+				a.Status, a.Context = AlignmentMatched, t.text
+			}
+		default:
+			continue
+		}
+		alignment = append(alignment, a)
+	}
+	return alignment
+}
+
+// entityToken is a word or number in a claim or context.
+type entityToken struct {
+	text          string
+	start, end    int
+	number        bool
+	value         float64
+	capitalized   bool
+	sentenceStart bool
+}
+
+func (t entityToken) year() bool {
+	return len(t.text) == 4 && (t.text[0] == '1' || t.text[0] == '2')
+}
+
+// scanEntityTokens splits text into words and numbers. Thousands
+// separators and decimal points are kept inside numbers.
+func scanEntityTokens(text string) []entityToken {
+	var tokens []entityToken
+	sentenceStart := true
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(text) {
+				c := text[j]
+				if c >= '0' && c <= '9' || (c == ',' || c == '.') && j+1 < len(text) && text[j+1] >= '0' && text[j+1] <= '9' {
+					j++
+					continue
+				}
+				break
+			}
+			raw := text[i:j]
+			value, err := strconv.ParseFloat(strings.ReplaceAll(raw, ",", ""), 64)
+			if err == nil {
+				tokens = append(tokens, entityToken{text: raw, start: i, end: j, number: true, value: value})
+			}
+			sentenceStart = false
+			i = j
+		case unicode.IsLetter(r):
+			j := i
+			for j < len(text) {
+				r, size := utf8.DecodeRuneInString(text[j:])
+				if !unicode.IsLetter(r) {
+					break
+				}
+				j += size
+			}
+			tokens = append(tokens, entityToken{
+				text:          text[i:j],
+				start:         i,
+				end:           j,
+				capitalized:   unicode.IsUpper(r),
+				sentenceStart: sentenceStart,
+			})
+			sentenceStart = false
+			i = j
+		default:
+			if r == '.' || r == '!' || r == '?' || r == '\n' {
+				sentenceStart = true
+			}
+			i += size
+		}
+	}
+	return tokens
+}
+
+// findNumber returns the text of a context number equal to value.
+func findNumber(tokens []entityToken, value float64) (string, bool) {
+	for _, t := range tokens {
+		if t.number && t.value == value {
+			return t.text, true
+		}
+	}
+	return "", false
+}
+
+// conflictingNumber looks for a context number attached to the same word
+// as the claim number at index i, e.g. the unit or counted noun.
+func conflictingNumber(claim []entityToken, i int, context []entityToken) (string, bool) {
+	anchors := neighborWords(claim, i)
+	if len(anchors) == 0 {
+		return "", false
+	}
+	for j, t := range context {
+		if !t.number || t.year() != claim[i].year() {
+			continue
+		}
+		for w := range neighborWords(context, j) {
+			if anchors[w] {
+				return t.text, true
+			}
+		}
+	}
+	return "", false
+}
+
+// neighborWords returns the lower-cased words directly before and after the
+// token at i, skipping articles and prepositions.
+func neighborWords(tokens []entityToken, i int) map[string]bool {
+	words := make(map[string]bool)
+	for _, j := range []int{i - 1, i + 1} {
+		if j < 0 || j >= len(tokens) || tokens[j].number {
+			continue
+		}
+		w := strings.ToLower(tokens[j].text)
+		if !anchorStopwords[w] {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+// isStopword reports whether word is a function word in any language known
+// to DetectLanguage.
+func isStopword(word string) bool {
+	word = strings.ToLower(word)
+	for _, sw := range stopwords {
+		if sw.words[word] {
+			return true
+		}
+	}
+	return false
+}
+
+var anchorStopwords = wordSet("a an the of in on at by to for and or is was are were has had")
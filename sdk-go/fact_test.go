@@ -0,0 +1,201 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"The Eiffel Tower is in Paris.", "en"},
+		{"Berlin ist die Hauptstadt von Deutschland.", "de"},
+		{"Paris est la capitale de la France.", "fr"},
+		{"La capital de Francia es París.", "es"},
+		{"Roma è la capitale d'Italia.", "it"},
+		{"Lisboa é a capital de Portugal.", "pt"},
+		{"Amsterdam is de hoofdstad van het land.", "nl"},
+		{"Москва является столицей России.", "ru"},
+		{"Київ є столицею України.", "uk"},
+		{"東京は日本の首都です。", "ja"},
+		{"北京是中国的首都。", "zh"},
+		{"서울은 한국의 수도입니다.", "ko"},
+		{"Η Αθήνα είναι η πρωτεύουσα της Ελλάδας.", "el"},
+		{"12345", ""},
+	}
+
+	for _, tt := range tests {
+		if got := DetectLanguage(tt.text); got != tt.want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyFactCrossLingual(t *testing.T) {
+	var got map[string]interface{}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/fact" {
+			t.Errorf("expected path /verify/fact, got %s", r.URL.Path)
+		}
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true, Engine: "fact"})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyFact(context.Background(),
+		"Berlin is the capital of Germany.",
+		"Berlin ist die Hauptstadt und ein Land der Bundesrepublik Deutschland.",
+	)
+	if err != nil || !resp.Verified {
+		t.Fatalf("unexpected result: %+v, %v", resp, err)
+	}
+
+	if got["language"] != "en" || got["context_language"] != "de" {
+		t.Errorf("expected detected languages en/de, got %v/%v", got["language"], got["context_language"])
+	}
+	if got["cross_lingual"] != true {
+		t.Errorf("expected cross-lingual mode, got %v", got)
+	}
+}
+
+func TestVerifyFactWithOptions(t *testing.T) {
+	var got map[string]interface{}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	if _, err := client.VerifyFactWithOptions(ctx, "Paris est en France.", "Paris est la capitale de la France.",
+		&FactOptions{Language: "fr"}); err != nil {
+		t.Fatal(err)
+	}
+	if got["language"] != "fr" || got["context_language"] != "fr" || got["cross_lingual"] != nil {
+		t.Errorf("expected monolingual French request, got %v", got)
+	}
+
+	if _, err := client.VerifyFactWithOptions(ctx, "claim", "context",
+		&FactOptions{DisableDetection: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := got["language"]; ok {
+		t.Errorf("expected no language with detection disabled, got %v", got)
+	}
+}
+
+func TestAlignEntities(t *testing.T) {
+	claim := "The Eiffel Tower in Paris is 330 metres tall and opened in 1889 with 2,000 visitors."
+	context := "The Eiffel Tower stands 324 metres tall. It opened in 1889 and welcomed 2000 visitors in Paris."
+
+	byText := make(map[string]EntityAlignment)
+	for _, a := range AlignEntities(claim, context) {
+		byText[a.Text] = a
+	}
+
+	tests := []struct {
+		text    string
+		kind    EntityKind
+		status  AlignmentStatus
+		context string
+	}{
+		{"Eiffel", EntityName, AlignmentMatched, "Eiffel"},
+		{"Paris", EntityName, AlignmentMatched, "Paris"},
+		{"330", EntityNumber, AlignmentConflict, "324"},
+		{"1889", EntityYear, AlignmentMatched, "1889"},
+		{"2,000", EntityNumber, AlignmentMatched, "2000"},
+	}
+	for _, tt := range tests {
+		a, ok := byText[tt.text]
+		if !ok {
+			t.Errorf("expected alignment for %q", tt.text)
+			continue
+		}
+		if a.Kind != tt.kind || a.Status != tt.status || a.Context != tt.context {
+			t.Errorf("%q: got %+v", tt.text, a)
+		}
+		if claim[a.Start:a.End] != tt.text {
+			t.Errorf("%q: offsets point at %q", tt.text, claim[a.Start:a.End])
+		}
+	}
+	if _, ok := byText["The"]; ok {
+		t.Error("expected sentence-initial common word to be ignored")
+	}
+
+	missing := AlignEntities("Tokyo hosted the games in 1964.", "The games were held in Asia.")
+	if len(missing) != 2 || missing[0].Status != AlignmentMissing || missing[1].Kind != EntityYear || missing[1].Status != AlignmentMissing {
+		t.Errorf("expected missing entities, got %+v", missing)
+	}
+}
+
+func TestVerifyFactIncludesAlignment(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusFailed, Engine: "fact"})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyFact(context.Background(), "Revenue grew 12% in 2023.", "Revenue grew 9% in 2023.")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	alignment := FactAlignment(resp)
+	if len(alignment) != 3 {
+		t.Fatalf("expected 3 aligned entities, got %+v", alignment)
+	}
+	if alignment[1].Text != "12" || alignment[1].Status != AlignmentConflict || alignment[1].Context != "9" {
+		t.Errorf("expected percentage conflict, got %+v", alignment[1])
+	}
+	if alignment[2].Kind != EntityYear || alignment[2].Status != AlignmentMatched {
+		t.Errorf("expected matched year, got %+v", alignment[2])
+	}
+}
+
+func TestVerifyFactAlignmentLeavesCacheUnchanged(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusFailed, Engine: "fact", Result: map[string]interface{}{}})
+	})
+	defer server.Close()
+
+	cache := NewLRUCache(10)
+	client := NewClient("test-key", WithBaseURL(server.URL), WithCache(cache, time.Minute))
+	claim, factContext := "Revenue grew 12% in 2023.", "Revenue grew 9% in 2023."
+
+	// Concurrent callers share the cached response; run with -race.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.VerifyFact(context.Background(), claim, factContext)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if len(FactAlignment(resp)) != 3 {
+				t.Errorf("expected 3 aligned entities, got %v", resp.Result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	key := CacheKey(TypeFact, claim, factContext, DetectLanguage(claim), DetectLanguage(factContext))
+	cached, ok, _ := cache.Get(context.Background(), key)
+	if !ok {
+		t.Fatal("expected response to be cached")
+	}
+	if _, ok := cached.Result["alignment"]; ok {
+		t.Errorf("expected cached response to be unannotated, got %v", cached.Result)
+	}
+}
@@ -0,0 +1,272 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Failover
+// ============================================================================
+
+// ErrNoStandby is returned by Failover when the client has no standby
+// deployment.
+var ErrNoStandby = errors.New("qwed: no standby deployment configured")
+
+// FailoverSettings configures WithFailover.
+type FailoverSettings struct {
+	// Standby is the base URL of the standby QWED deployment. The primary
+	// is the client's base URL.
+	Standby string
+
+	// HealthInterval is how often MonitorHealth checks both deployments.
+	// Defaults to 10 seconds.
+	HealthInterval time.Duration
+
+	// AutoFailover makes MonitorHealth fail over when the active deployment
+	// turns unhealthy while the other is healthy.
+	AutoFailover bool
+
+	// OnHealthChange, if set, is called when MonitorHealth sees a
+	// deployment turn healthy or unhealthy. It must not block.
+	OnHealthChange func(HealthTransition)
+
+	// OnFailover, if set, is called with the base URLs of the previous and
+	// the new active deployment on every failover. It must not block.
+	OnFailover func(from, to string)
+}
+
+// HealthTransition reports a deployment turning healthy or unhealthy.
+type HealthTransition struct {
+	Endpoint string // base URL of the deployment
+	Healthy  bool
+	Err      error // why the health check failed, if unhealthy
+	At       time.Time
+}
+
+// WithFailover adds a warm standby deployment. Requests go to the primary
+// until Failover switches them, and MonitorHealth keeps checking both so
+// operators learn of health changes and can fail over, or have it done
+// automatically.
+func WithFailover(settings FailoverSettings) ClientOption {
+	return func(c *Client) {
+		if settings.HealthInterval <= 0 {
+			settings.HealthInterval = 10 * time.Second
+		}
+		c.failover = &failoverState{settings: settings, healthy: [2]bool{true, true}}
+	}
+}
+
+// failoverState tracks the active deployment and the requests in flight on
+// each. Slot 0 is the primary and slot 1 the standby. A nil state always
+// uses the client's base URL.
+type failoverState struct {
+	mu       sync.Mutex
+	settings FailoverSettings
+	active   int
+	inFlight [2]int
+	drained  [2]chan struct{} // closed when the slot has no requests in flight
+	healthy  [2]bool
+}
+
+// ActiveEndpoint returns the base URL new requests are sent to.
+func (c *Client) ActiveEndpoint() string {
+	if c.failover == nil {
+		return c.baseURL
+	}
+	c.failover.mu.Lock()
+	defer c.failover.mu.Unlock()
+	return c.endpointURL(c.failover.active)
+}
+
+// endpointURL returns the base URL of slot.
+func (c *Client) endpointURL(slot int) string {
+	if slot == 1 {
+		return c.failover.settings.Standby
+	}
+	return c.baseURL
+}
+
+// endpoint returns the base URL for a new request, its slot, and a function
+// to call once the request is done.
+func (c *Client) endpoint() (string, int, func()) {
+	f := c.failover
+	if f == nil {
+		return c.baseURL, 0, func() {}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	slot := f.active
+	f.inFlight[slot]++
+	return c.endpointURL(slot), slot, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.inFlight[slot]--
+		if f.inFlight[slot] == 0 && f.drained[slot] != nil {
+			close(f.drained[slot])
+			f.drained[slot] = nil
+		}
+	}
+}
+
+// migrate reports whether a request that failed with err on slot should
+// be retried on the new active deployment: the client failed over while it
+// was in flight and the old deployment was unreachable.
+func (f *failoverState) migrate(ctx context.Context, slot int, err error) bool {
+	if f == nil || err == nil || ctx.Err() != nil || !unreachable(err) {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active != slot
+}
+
+// Failover switches the client to the other deployment. New requests go to
+// it at once; requests in flight on the previous deployment finish there,
+// and those that fail because it is unreachable are retried once on the
+// new one. Failover returns when the previous deployment has drained, or
+// with ctx's error if ctx is done first; the switch stands either way.
+func (c *Client) Failover(ctx context.Context) error {
+	drained, err := c.switchOver(ctx, -1)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// switchOver makes the other deployment active, unless from is not -1 and
+// is no longer the active slot. It returns a channel closed once the
+// previous deployment has no requests in flight.
+func (c *Client) switchOver(ctx context.Context, from int) (<-chan struct{}, error) {
+	f := c.failover
+	if f == nil || f.settings.Standby == "" {
+		return nil, ErrNoStandby
+	}
+
+	f.mu.Lock()
+	if from != -1 && f.active != from {
+		f.mu.Unlock()
+		return closedChan, nil
+	}
+	prev := f.active
+	f.active = 1 - prev
+	drained := closedChan
+	if f.inFlight[prev] > 0 {
+		if f.drained[prev] == nil {
+			f.drained[prev] = make(chan struct{})
+		}
+		drained = f.drained[prev]
+	}
+	fromURL, toURL := c.endpointURL(prev), c.endpointURL(f.active)
+	f.mu.Unlock()
+
+	// Failures of the previous deployment say nothing about the new one.
+	c.breaker.reset()
+	c.log(ctx, slog.LevelWarn, "qwed failover", slog.String("from", fromURL), slog.String("to", toURL))
+	if f.settings.OnFailover != nil {
+		f.settings.OnFailover(fromURL, toURL)
+	}
+	return drained, nil
+}
+
+var closedChan = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// MonitorHealth checks the health of the primary and standby deployments
+// every HealthInterval until ctx is done, reporting changes to
+// OnHealthChange and failing over if AutoFailover is set. It returns
+// ctx's error, or ErrNoStandby if the client has no standby.
+func (c *Client) MonitorHealth(ctx context.Context) error {
+	f := c.failover
+	if f == nil || f.settings.Standby == "" {
+		return ErrNoStandby
+	}
+
+	ticker := time.NewTicker(f.settings.HealthInterval)
+	defer ticker.Stop()
+	for {
+		c.checkDeployments(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkDeployments runs one round of health checks.
+func (c *Client) checkDeployments(ctx context.Context) {
+	f := c.failover
+	var errs [2]error
+	for slot := range errs {
+		errs[slot] = c.checkHealth(ctx, c.endpointURL(slot))
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	f.mu.Lock()
+	var transitions []HealthTransition
+	for slot, err := range errs {
+		if healthy := err == nil; healthy != f.healthy[slot] {
+			f.healthy[slot] = healthy
+			transitions = append(transitions, HealthTransition{Endpoint: c.endpointURL(slot), Healthy: healthy, Err: err, At: time.Now()})
+		}
+	}
+	active := f.active
+	failover := f.settings.AutoFailover && !f.healthy[active] && f.healthy[1-active]
+	f.mu.Unlock()
+
+	if f.settings.OnHealthChange != nil {
+		for _, t := range transitions {
+			f.settings.OnHealthChange(t)
+		}
+	}
+	if failover {
+		c.switchOver(ctx, active)
+	}
+}
+
+// checkHealth calls the health endpoint of the deployment at baseURL
+// directly, bypassing rate limiting and the circuit breaker but not hooks.
+func (c *Client) checkHealth(ctx context.Context, baseURL string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/health", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	credential, err := c.credential(ctx)
+	if err != nil {
+		return err
+	}
+	c.setAuth(req.Header, credential)
+	c.signRequest(req.Header, nil)
+
+	c.beforeRequest(req, nil)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.afterResponse(req, nil, nil, err)
+		return fmt.Errorf("request failed: %w", err)
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	c.afterResponse(req, resp, data, err)
+	if resp.StatusCode >= 400 {
+		return &QWEDError{Code: fmt.Sprintf("HTTP-%d", resp.StatusCode), Message: "health check failed", StatusCode: resp.StatusCode}
+	}
+	return nil
+}
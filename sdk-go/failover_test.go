@@ -0,0 +1,259 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// deployment is a mock QWED deployment whose verify responses can be held
+// and whose health can be toggled.
+type deployment struct {
+	calls   atomic.Int32
+	down    atomic.Bool
+	started chan struct{}
+	release chan struct{}
+}
+
+func newDeployment(hold bool) *deployment {
+	d := &deployment{}
+	if hold {
+		d.started = make(chan struct{}, 10)
+		d.release = make(chan struct{})
+	}
+	return d
+}
+
+func (d *deployment) handler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/health" {
+		d.calls.Add(1)
+		if d.release != nil {
+			d.started <- struct{}{}
+			<-d.release
+		}
+	}
+	if d.down.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+}
+
+func TestFailoverDrainsInFlight(t *testing.T) {
+	primary, standby := newDeployment(true), newDeployment(false)
+	ps, ss := mockServer(primary.handler), mockServer(standby.handler)
+	defer ps.Close()
+	defer ss.Close()
+
+	var switched []string
+	client := NewClient("test-key", WithBaseURL(ps.URL), WithFailover(FailoverSettings{
+		Standby:    ss.URL,
+		OnFailover: func(from, to string) { switched = append(switched, from, to) },
+	}))
+	ctx := context.Background()
+
+	inFlight := make(chan error)
+	go func() {
+		_, err := client.VerifyMath(ctx, "2+2=4")
+		inFlight <- err
+	}()
+	<-primary.started
+
+	failedOver := make(chan error)
+	go func() { failedOver <- client.Failover(ctx) }()
+
+	// New requests go to the standby while the primary drains.
+	deadline := time.Now().Add(time.Second)
+	for client.ActiveEndpoint() != ss.URL {
+		if time.Now().After(deadline) {
+			t.Fatal("client did not switch to the standby")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := client.VerifyMath(ctx, "3+3=6"); err != nil {
+		t.Fatalf("VerifyMath() error = %v", err)
+	}
+	if standby.calls.Load() != 1 {
+		t.Errorf("standby calls = %d, want 1", standby.calls.Load())
+	}
+	select {
+	case err := <-failedOver:
+		t.Fatalf("Failover() returned %v before the primary drained", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(primary.release)
+	if err := <-inFlight; err != nil {
+		t.Errorf("in-flight request error = %v", err)
+	}
+	if err := <-failedOver; err != nil {
+		t.Errorf("Failover() error = %v", err)
+	}
+	if primary.calls.Load() != 1 {
+		t.Errorf("primary calls = %d, want 1", primary.calls.Load())
+	}
+	if len(switched) != 2 || switched[0] != ps.URL || switched[1] != ss.URL {
+		t.Errorf("OnFailover = %v, want primary -> standby", switched)
+	}
+}
+
+func TestFailoverMigratesUnreachable(t *testing.T) {
+	primary, standby := newDeployment(true), newDeployment(false)
+	primary.down.Store(true)
+	ps, ss := mockServer(primary.handler), mockServer(standby.handler)
+	defer ps.Close()
+	defer ss.Close()
+
+	client := NewClient("test-key", WithBaseURL(ps.URL), WithFailover(FailoverSettings{Standby: ss.URL}))
+	ctx := context.Background()
+
+	inFlight := make(chan error)
+	go func() {
+		_, err := client.VerifyMath(ctx, "2+2=4")
+		inFlight <- err
+	}()
+	<-primary.started
+
+	failedOver := make(chan error)
+	go func() { failedOver <- client.Failover(ctx) }()
+	for client.ActiveEndpoint() != ss.URL {
+		time.Sleep(time.Millisecond)
+	}
+	close(primary.release)
+
+	if err := <-inFlight; err != nil {
+		t.Errorf("migrated request error = %v", err)
+	}
+	if err := <-failedOver; err != nil {
+		t.Errorf("Failover() error = %v", err)
+	}
+	if standby.calls.Load() != 1 {
+		t.Errorf("standby calls = %d, want the request retried once", standby.calls.Load())
+	}
+
+	// Without a failover, an unreachable deployment is an error as usual.
+	standby.down.Store(true)
+	if _, err := client.VerifyMath(ctx, "2+2=4"); statusCode(err) != http.StatusServiceUnavailable {
+		t.Errorf("VerifyMath() error = %v, want 503", err)
+	}
+	if standby.calls.Load() != 2 {
+		t.Errorf("standby calls = %d, want 2", standby.calls.Load())
+	}
+}
+
+func TestFailoverTimeout(t *testing.T) {
+	primary := newDeployment(true)
+	ps := mockServer(primary.handler)
+	defer ps.Close()
+	defer close(primary.release)
+
+	client := NewClient("test-key", WithBaseURL(ps.URL), WithFailover(FailoverSettings{Standby: "http://standby.invalid"}))
+	go client.VerifyMath(context.Background(), "2+2=4")
+	<-primary.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := client.Failover(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Failover() error = %v, want deadline exceeded", err)
+	}
+	if client.ActiveEndpoint() != "http://standby.invalid" {
+		t.Error("switch should stand when the drain times out")
+	}
+}
+
+func TestFailoverNoStandby(t *testing.T) {
+	client := NewClient("test-key")
+	if err := client.Failover(context.Background()); !errors.Is(err, ErrNoStandby) {
+		t.Errorf("Failover() error = %v, want ErrNoStandby", err)
+	}
+	if err := client.MonitorHealth(context.Background()); !errors.Is(err, ErrNoStandby) {
+		t.Errorf("MonitorHealth() error = %v, want ErrNoStandby", err)
+	}
+	if client.ActiveEndpoint() != client.baseURL {
+		t.Errorf("ActiveEndpoint() = %q, want the base URL", client.ActiveEndpoint())
+	}
+}
+
+func TestFailoverResetsCircuit(t *testing.T) {
+	client := NewClient("test-key", WithCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 1}),
+		WithFailover(FailoverSettings{Standby: "http://standby.invalid"}))
+	client.breaker.mu.Lock()
+	client.breaker.open()
+	client.breaker.mu.Unlock()
+
+	if err := client.Failover(context.Background()); err != nil {
+		t.Fatalf("Failover() error = %v", err)
+	}
+	if client.CircuitState() != CircuitClosed {
+		t.Errorf("circuit = %s, want closed", client.CircuitState())
+	}
+}
+
+func TestMonitorHealth(t *testing.T) {
+	primary, standby := newDeployment(false), newDeployment(false)
+	ps, ss := mockServer(primary.handler), mockServer(standby.handler)
+	defer ps.Close()
+	defer ss.Close()
+
+	var mu sync.Mutex
+	var transitions []HealthTransition
+	failovers := make(chan string, 1)
+	client := NewClient("test-key", WithBaseURL(ps.URL), WithFailover(FailoverSettings{
+		Standby:        ss.URL,
+		HealthInterval: time.Hour,
+		AutoFailover:   true,
+		OnHealthChange: func(ht HealthTransition) {
+			mu.Lock()
+			transitions = append(transitions, ht)
+			mu.Unlock()
+		},
+		OnFailover: func(from, to string) { failovers <- to },
+	}))
+	ctx := context.Background()
+
+	client.checkDeployments(ctx)
+	if len(transitions) != 0 {
+		t.Fatalf("transitions = %v, want none while healthy", transitions)
+	}
+
+	primary.down.Store(true)
+	client.checkDeployments(ctx)
+	if len(transitions) != 1 || transitions[0].Endpoint != ps.URL || transitions[0].Healthy || transitions[0].Err == nil {
+		t.Fatalf("transitions = %+v, want primary unhealthy", transitions)
+	}
+	if to := <-failovers; to != ss.URL {
+		t.Errorf("failed over to %q, want standby", to)
+	}
+	if client.ActiveEndpoint() != ss.URL {
+		t.Errorf("ActiveEndpoint() = %q, want standby", client.ActiveEndpoint())
+	}
+
+	// A recovered primary is reported but the client stays on the standby.
+	primary.down.Store(false)
+	client.checkDeployments(ctx)
+	if len(transitions) != 2 || !transitions[1].Healthy {
+		t.Fatalf("transitions = %+v, want primary healthy again", transitions)
+	}
+	if client.ActiveEndpoint() != ss.URL {
+		t.Error("client should not fail back while the standby is healthy")
+	}
+
+	// With both down there is nowhere to go.
+	primary.down.Store(true)
+	standby.down.Store(true)
+	client.checkDeployments(ctx)
+	if client.ActiveEndpoint() != ss.URL {
+		t.Error("client should not fail over to an unhealthy deployment")
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := client.MonitorHealth(cctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("MonitorHealth() error = %v, want canceled", err)
+	}
+}
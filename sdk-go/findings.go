@@ -0,0 +1,56 @@
+package qwed
+
+// ============================================================================
+// Code Findings
+// ============================================================================
+
+// Severity levels reported by the code engine.
+const (
+	SeverityCritical = "CRITICAL"
+	SeverityWarning  = "WARNING"
+	SeverityInfo     = "INFO"
+)
+
+// CodeFinding is a single issue reported by the code security engine.
+type CodeFinding struct {
+	Severity       string `json:"severity"`
+	Type           string `json:"type"`
+	Pattern        string `json:"pattern,omitempty"`
+	Description    string `json:"description,omitempty"`
+	Line           int    `json:"line_number,omitempty"`
+	Recommendation string `json:"recommendation,omitempty"`
+	// References link to the recommended construction, such as an OWASP
+	// cheat sheet or NIST publication.
+	References []string `json:"references,omitempty"`
+
+	// Suppressed is set when an inline qwed:ignore comment covers the finding.
+	Suppressed        bool   `json:"suppressed,omitempty"`
+	SuppressionReason string `json:"suppression_reason,omitempty"`
+}
+
+// CodeFindings extracts the findings from a VerifyCode response. Findings
+// listed under "issues" are decoded in full; bare rule names listed under
+// "vulnerabilities" become findings with only Type set.
+func CodeFindings(resp *VerificationResponse) []CodeFinding {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var findings []CodeFinding
+	decodeResult(resp.Result["issues"], &findings)
+
+	switch list := resp.Result["vulnerabilities"].(type) {
+	case []interface{}:
+		for _, v := range list {
+			if name, ok := v.(string); ok {
+				findings = append(findings, CodeFinding{Type: name})
+			}
+		}
+	case []string:
+		for _, name := range list {
+			findings = append(findings, CodeFinding{Type: name})
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,1496 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ============================================================================
+// Spreadsheet Formula Verification
+// ============================================================================
+
+// TypeFormula identifies spreadsheet formula checks. They run locally and
+// are reported with Engine EngineLocalFormula.
+const TypeFormula VerificationType = "formula"
+
+// EngineLocalFormula is the engine name reported by VerifyFormula.
+const EngineLocalFormula Engine = "local-formula"
+
+// VerifyFormula evaluates an Excel or Google Sheets formula, typically
+// LLM-generated, and checks that it produces expected.
+//
+// inputs holds the cells and named ranges the formula refers to: keys are
+// references such as "A1", "$B$2" or "Sheet2!C3", or names such as
+// "tax_rate". Values are numbers, strings, booleans or nil for blank
+// cells; a slice is a named range, and a slice of slices a 2D one. Ranges
+// such as A1:A10 are read cell by cell, with missing cells blank.
+//
+// expected is a number, string, boolean or an error value such as
+// "#DIV/0!". An expected number with decimals matches to the precision it
+// is written with, so 33.33 matches =100/3; whole numbers must match
+// exactly. The computed value is returned in Result["value"].
+//
+// A formula that does not parse fails. Formulas using functions the local
+// evaluator does not implement return StatusUnsupported with the function
+// named in Result["reason"].
+func (c *Client) VerifyFormula(ctx context.Context, formula string, inputs map[string]interface{}, expected interface{}) (resp *VerificationResponse, err error) {
+	_, end := c.instrument(ctx, "VerifyFormula", TypeFormula)
+	defer func() { end(resp, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return localVerifyFormula(formula, inputs, expected), nil
+}
+
+// localVerifyFormula evaluates formula against inputs and compares the
+// result with expected.
+func localVerifyFormula(formula string, inputs map[string]interface{}, expected interface{}) *VerificationResponse {
+	result := map[string]interface{}{"formula": formula, "expected": expected}
+
+	expr, err := parseFormula(formula)
+	var unsupported *unsupportedFunctionError
+	switch {
+	case errors.As(err, &unsupported):
+		result["reason"] = err.Error()
+		result["unsupported"] = UnsupportedInput{Construct: "function " + unsupported.name, Reason: err.Error()}
+		return &VerificationResponse{Status: StatusUnsupported, Engine: EngineLocalFormula, Result: result}
+	case err != nil:
+		result["error"] = err.Error()
+		return localResponse(EngineLocalFormula, false, result)
+	}
+
+	env, err := newFormulaEnv(inputs)
+	if err != nil {
+		result["reason"] = err.Error()
+		return &VerificationResponse{Status: StatusUnsupported, Engine: EngineLocalFormula, Result: result}
+	}
+
+	value := scalar(expr(env))
+	if value == nil {
+		value = 0.0 // a formula referring to a blank cell shows 0
+	}
+	if e, ok := value.(formulaError); ok {
+		result["value"] = string(e)
+	} else {
+		result["value"] = value
+	}
+	return localResponse(EngineLocalFormula, formulaValueMatches(value, expected), result)
+}
+
+// formulaValueMatches compares a computed value with the expected one.
+func formulaValueMatches(value, expected interface{}) bool {
+	switch v := value.(type) {
+	case float64:
+		want, decimals, ok := expectedNumber(expected)
+		if !ok {
+			return false
+		}
+		tolerance := 1e-9 * math.Max(1, math.Abs(want))
+		if decimals > 0 {
+			tolerance += 0.5 * math.Pow(10, -float64(decimals))
+		}
+		return math.Abs(v-want) <= tolerance
+	case bool:
+		switch e := expected.(type) {
+		case bool:
+			return v == e
+		case string:
+			return strings.EqualFold(e, formulaText(v))
+		}
+	case formulaError:
+		e, ok := expected.(string)
+		return ok && strings.EqualFold(strings.TrimSpace(e), string(v))
+	case string:
+		e, ok := expected.(string)
+		return ok && e == v
+	}
+	return false
+}
+
+// expectedNumber converts expected to a number and the decimal places it is
+// written with.
+func expectedNumber(expected interface{}) (float64, int, bool) {
+	var s string
+	switch e := expected.(type) {
+	case string:
+		s = strings.TrimSpace(strings.ReplaceAll(e, ",", ""))
+	default:
+		v, ok := formulaInputNumber(expected)
+		if !ok {
+			return 0, 0, false
+		}
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	decimals := 0
+	if _, frac, ok := strings.Cut(s, "."); ok {
+		decimals = len(frac)
+	}
+	return v, decimals, true
+}
+
+// ============================================================================
+// Values
+// ============================================================================
+
+// Formula values are float64, string, bool, formulaError, nil for a blank
+// cell, or *formulaRange.
+
+// formulaError is a spreadsheet error value such as #DIV/0!.
+type formulaError string
+
+const (
+	errDiv0  formulaError = "#DIV/0!"
+	errValue formulaError = "#VALUE!"
+	errName  formulaError = "#NAME?"
+	errNA    formulaError = "#N/A"
+	errNum   formulaError = "#NUM!"
+	errRef   formulaError = "#REF!"
+)
+
+var formulaErrors = []formulaError{errDiv0, errValue, errName, errNA, errNum, errRef, "#NULL!"}
+
+// formulaRange is a rectangular block of values in row-major order.
+type formulaRange struct {
+	rows, cols int
+	values     []interface{}
+}
+
+func (r *formulaRange) at(row, col int) interface{} {
+	return r.values[row*r.cols+col]
+}
+
+// scalar reduces a single-cell range to its value. Larger ranges are
+// #VALUE! where one value is needed.
+func scalar(v interface{}) interface{} {
+	if r, ok := v.(*formulaRange); ok {
+		if len(r.values) == 1 {
+			return r.values[0]
+		}
+		return errValue
+	}
+	return v
+}
+
+// toNumber coerces v as spreadsheet arithmetic does.
+func toNumber(v interface{}) (float64, formulaError) {
+	switch v := scalar(v).(type) {
+	case float64:
+		return v, ""
+	case bool:
+		if v {
+			return 1, ""
+		}
+		return 0, ""
+	case nil:
+		return 0, ""
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return f, ""
+		}
+		return 0, errValue
+	case formulaError:
+		return 0, v
+	}
+	return 0, errValue
+}
+
+// toBool coerces v to a condition.
+func toBool(v interface{}) (bool, formulaError) {
+	switch v := scalar(v).(type) {
+	case bool:
+		return v, ""
+	case float64:
+		return v != 0, ""
+	case nil:
+		return false, ""
+	case string:
+		switch strings.ToUpper(v) {
+		case "TRUE":
+			return true, ""
+		case "FALSE":
+			return false, ""
+		}
+		return false, errValue
+	case formulaError:
+		return false, v
+	}
+	return false, errValue
+}
+
+// formulaText formats v as the text a cell would show.
+func formulaText(v interface{}) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'g', 15, 64)
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case string:
+		return v
+	case formulaError:
+		return string(v)
+	}
+	return ""
+}
+
+// toText coerces v to text, propagating errors.
+func toText(v interface{}) (string, formulaError) {
+	v = scalar(v)
+	if e, ok := v.(formulaError); ok {
+		return "", e
+	}
+	return formulaText(v), ""
+}
+
+// compareValues orders two scalars as spreadsheets do: numbers before text
+// before booleans, text compared without case.
+func compareValues(a, b interface{}) int {
+	rank := func(v interface{}) int {
+		switch v.(type) {
+		case string:
+			return 1
+		case bool:
+			return 2
+		}
+		return 0
+	}
+	if a == nil {
+		a = blankLike(b)
+	}
+	if b == nil {
+		b = blankLike(a)
+	}
+	if ra, rb := rank(a), rank(b); ra != rb {
+		return ra - rb
+	}
+	switch a := a.(type) {
+	case float64:
+		b := b.(float64)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b.(string)))
+	case bool:
+		ab, bb := 0, 0
+		if a {
+			ab = 1
+		}
+		if b.(bool) {
+			bb = 1
+		}
+		return ab - bb
+	}
+	return 0
+}
+
+// blankLike returns the value a blank cell compares as next to other.
+func blankLike(other interface{}) interface{} {
+	switch other.(type) {
+	case string:
+		return ""
+	case bool:
+		return false
+	}
+	return 0.0
+}
+
+// ============================================================================
+// Inputs
+// ============================================================================
+
+// formulaEnv resolves references against the caller's inputs.
+type formulaEnv struct {
+	cells map[string]interface{} // normalized reference or name -> value
+}
+
+func newFormulaEnv(inputs map[string]interface{}) (*formulaEnv, error) {
+	env := &formulaEnv{cells: make(map[string]interface{}, len(inputs))}
+	for key, v := range inputs {
+		value, err := formulaInput(v)
+		if err != nil {
+			return nil, fmt.Errorf("input %s: %w", key, err)
+		}
+		env.cells[normalizeReference(key)] = value
+	}
+	return env, nil
+}
+
+// normalizeReference folds "$a$1" and "A1" to the same key.
+func normalizeReference(ref string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(ref), "$", ""))
+}
+
+// formulaInput converts a Go input value to a formula value.
+func formulaInput(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if e, ok := parseFormulaError(v); ok {
+			return e, nil
+		}
+		return v, nil
+	case bool:
+		return v, nil
+	}
+	if f, ok := formulaInputNumber(v); ok {
+		return f, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("unsupported value of type %T", v)
+	}
+	r := &formulaRange{rows: rv.Len(), cols: 1}
+	for i := 0; i < rv.Len(); i++ {
+		row := reflect.ValueOf(rv.Index(i).Interface())
+		if row.Kind() == reflect.Slice || row.Kind() == reflect.Array {
+			if i == 0 {
+				r.cols = row.Len()
+			} else if row.Len() != r.cols {
+				return nil, fmt.Errorf("rows of a 2D range must have the same length")
+			}
+			for j := 0; j < row.Len(); j++ {
+				cell, err := formulaInput(row.Index(j).Interface())
+				if err != nil {
+					return nil, err
+				}
+				r.values = append(r.values, cell)
+			}
+			continue
+		}
+		cell, err := formulaInput(rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		if _, nested := cell.(*formulaRange); nested {
+			return nil, fmt.Errorf("ranges cannot be nested")
+		}
+		r.values = append(r.values, cell)
+	}
+	if len(r.values) != r.rows*r.cols {
+		return nil, fmt.Errorf("cannot mix cells and rows in a range")
+	}
+	return r, nil
+}
+
+// formulaInputNumber converts Go numeric types to float64.
+func formulaInputNumber(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func parseFormulaError(s string) (formulaError, bool) {
+	for _, e := range formulaErrors {
+		if strings.EqualFold(s, string(e)) {
+			return e, true
+		}
+	}
+	return "", false
+}
+
+var cellReference = regexp.MustCompile(`^(?:(.+)!)?\$?([A-Za-z]{1,3})\$?([0-9]+)$`)
+
+// parseCell splits a reference such as "Sheet1!$B$3" into its sheet prefix
+// ("SHEET1!"), 0-based column and 1-based row.
+func parseCell(ref string) (sheet string, col, row int, ok bool) {
+	m := cellReference.FindStringSubmatch(ref)
+	if m == nil {
+		return "", 0, 0, false
+	}
+	if m[1] != "" {
+		sheet = strings.ToUpper(strings.Trim(m[1], "'")) + "!"
+	}
+	for _, r := range strings.ToUpper(m[2]) {
+		col = col*26 + int(r-'A') + 1
+	}
+	row, err := strconv.Atoi(m[3])
+	if err != nil || row < 1 || col > 16384 {
+		return "", 0, 0, false
+	}
+	return sheet, col - 1, row, true
+}
+
+func columnLetters(col int) string {
+	var s []byte
+	for col++; col > 0; col = (col - 1) / 26 {
+		s = append([]byte{byte('A' + (col-1)%26)}, s...)
+	}
+	return string(s)
+}
+
+// cell returns the value of a single-cell reference or name.
+func (e *formulaEnv) cell(ref string) interface{} {
+	if v, ok := e.cells[normalizeReference(ref)]; ok {
+		return v
+	}
+	if _, _, _, ok := parseCell(ref); ok {
+		return nil // blank cell
+	}
+	return errName
+}
+
+// rangeOf reads the block between two cell references.
+func (e *formulaEnv) rangeOf(from, to string) interface{} {
+	sheet, c1, r1, ok1 := parseCell(from)
+	_, c2, r2, ok2 := parseCell(to)
+	if !ok1 || !ok2 {
+		return errRef
+	}
+	if c1 > c2 {
+		c1, c2 = c2, c1
+	}
+	if r1 > r2 {
+		r1, r2 = r2, r1
+	}
+	if (r2-r1+1)*(c2-c1+1) > 1_000_000 {
+		return errRef
+	}
+	r := &formulaRange{rows: r2 - r1 + 1, cols: c2 - c1 + 1}
+	for row := r1; row <= r2; row++ {
+		for col := c1; col <= c2; col++ {
+			r.values = append(r.values, e.cells[sheet+columnLetters(col)+strconv.Itoa(row)])
+		}
+	}
+	return r
+}
+
+// ============================================================================
+// Parser
+// ============================================================================
+
+// formulaExpr evaluates a parsed formula, or part of one.
+type formulaExpr func(*formulaEnv) interface{}
+
+// unsupportedFunctionError reports a function the evaluator lacks.
+type unsupportedFunctionError struct {
+	name string
+}
+
+func (e *unsupportedFunctionError) Error() string {
+	return fmt.Sprintf("function %s is not supported locally", e.name)
+}
+
+type formulaTokenKind int
+
+const (
+	tokNumber formulaTokenKind = iota
+	tokString
+	tokName // reference, range, name, boolean or function
+	tokError
+	tokOp
+	tokEnd
+)
+
+type formulaToken struct {
+	kind formulaTokenKind
+	text string
+	num  float64
+}
+
+// lexFormula splits a formula into tokens.
+func lexFormula(formula string) ([]formulaToken, error) {
+	s := strings.TrimSpace(formula)
+	s = strings.TrimPrefix(s, "=")
+	var tokens []formulaToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(s) && s[i+1] >= '0' && s[i+1] <= '9':
+			j := i
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			if j < len(s) && (s[j] == 'e' || s[j] == 'E') {
+				k := j + 1
+				if k < len(s) && (s[k] == '+' || s[k] == '-') {
+					k++
+				}
+				if k < len(s) && s[k] >= '0' && s[k] <= '9' {
+					for j = k; j < len(s) && s[j] >= '0' && s[j] <= '9'; j++ {
+					}
+				}
+			}
+			v, err := strconv.ParseFloat(s[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", s[i:j])
+			}
+			tokens = append(tokens, formulaToken{kind: tokNumber, text: s[i:j], num: v})
+			i = j
+		case c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(s); j++ {
+				if s[j] == '"' {
+					if j+1 < len(s) && s[j+1] == '"' {
+						b.WriteByte('"')
+						j++
+						continue
+					}
+					break
+				}
+				b.WriteByte(s[j])
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, formulaToken{kind: tokString, text: b.String()})
+			i = j + 1
+		case c == '#':
+			matched := false
+			for _, e := range formulaErrors {
+				if strings.HasPrefix(strings.ToUpper(s[i:]), string(e)) {
+					tokens = append(tokens, formulaToken{kind: tokError, text: string(e)})
+					i += len(e)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected %q", s[i:])
+			}
+		case c == '\'' || c == '$' || c == '_' || unicode.IsLetter(rune(c)) || c >= 0x80:
+			j := i
+			if c == '\'' { // quoted sheet name
+				end := strings.Index(s[i+1:], "'!")
+				if end < 0 {
+					return nil, fmt.Errorf("unterminated sheet name")
+				}
+				j = i + 1 + end + 2
+			}
+			for j < len(s) && (isFormulaNameByte(s[j]) || s[j] == '!' || s[j] == ':') {
+				j++
+			}
+			tokens = append(tokens, formulaToken{kind: tokName, text: s[i:j]})
+			i = j
+		default:
+			op := string(c)
+			if i+1 < len(s) {
+				if two := s[i : i+2]; two == "<>" || two == "<=" || two == ">=" {
+					op = two
+				}
+			}
+			if !strings.Contains("+-*/^&=<>%(),;<=>=", op) {
+				return nil, fmt.Errorf("unexpected %q", op)
+			}
+			tokens = append(tokens, formulaToken{kind: tokOp, text: op})
+			i += len(op)
+		}
+	}
+	return append(tokens, formulaToken{kind: tokEnd}), nil
+}
+
+func isFormulaNameByte(c byte) bool {
+	return c == '_' || c == '.' || c == '$' || c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= 0x80
+}
+
+type formulaParser struct {
+	tokens []formulaToken
+	pos    int
+}
+
+// parseFormula parses a formula, with or without its leading "=".
+func parseFormula(formula string) (formulaExpr, error) {
+	tokens, err := lexFormula(formula)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return nil, fmt.Errorf("empty formula")
+	}
+	p := &formulaParser{tokens: tokens}
+	expr, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEnd {
+		return nil, fmt.Errorf("unexpected %q", t.text)
+	}
+	return expr, nil
+}
+
+func (p *formulaParser) peek() formulaToken { return p.tokens[p.pos] }
+
+func (p *formulaParser) next() formulaToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokEnd {
+		p.pos++
+	}
+	return t
+}
+
+// acceptOp consumes the next token if it is one of ops.
+func (p *formulaParser) acceptOp(ops ...string) (string, bool) {
+	t := p.peek()
+	if t.kind != tokOp {
+		return "", false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			p.pos++
+			return op, true
+		}
+	}
+	return "", false
+}
+
+// binary parses a left-associative level of binary operators.
+func (p *formulaParser) binary(operand func() (formulaExpr, error), apply func(op string, a, b interface{}) interface{}, ops ...string) (formulaExpr, error) {
+	left, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.acceptOp(ops...)
+		if !ok {
+			return left, nil
+		}
+		right, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(e *formulaEnv) interface{} { return apply(op, l(e), right(e)) }
+	}
+}
+
+func (p *formulaParser) parseComparison() (formulaExpr, error) {
+	return p.binary(p.parseConcat, func(op string, a, b interface{}) interface{} {
+		a, b = scalar(a), scalar(b)
+		if e, ok := a.(formulaError); ok {
+			return e
+		}
+		if e, ok := b.(formulaError); ok {
+			return e
+		}
+		c := compareValues(a, b)
+		switch op {
+		case "=":
+			return c == 0
+		case "<>":
+			return c != 0
+		case "<":
+			return c < 0
+		case ">":
+			return c > 0
+		case "<=":
+			return c <= 0
+		}
+		return c >= 0
+	}, "=", "<>", "<=", ">=", "<", ">")
+}
+
+func (p *formulaParser) parseConcat() (formulaExpr, error) {
+	return p.binary(p.parseAdditive, func(_ string, a, b interface{}) interface{} {
+		x, err := toText(a)
+		if err != "" {
+			return err
+		}
+		y, err := toText(b)
+		if err != "" {
+			return err
+		}
+		return x + y
+	}, "&")
+}
+
+func (p *formulaParser) parseAdditive() (formulaExpr, error) {
+	return p.binary(p.parseMultiplicative, arithmetic, "+", "-")
+}
+
+func (p *formulaParser) parseMultiplicative() (formulaExpr, error) {
+	return p.binary(p.parsePower, arithmetic, "*", "/")
+}
+
+func (p *formulaParser) parsePower() (formulaExpr, error) {
+	return p.binary(p.parseUnary, arithmetic, "^")
+}
+
+// arithmetic applies a numeric binary operator.
+func arithmetic(op string, a, b interface{}) interface{} {
+	x, err := toNumber(a)
+	if err != "" {
+		return err
+	}
+	y, err := toNumber(b)
+	if err != "" {
+		return err
+	}
+	var v float64
+	switch op {
+	case "+":
+		v = x + y
+	case "-":
+		v = x - y
+	case "*":
+		v = x * y
+	case "/":
+		if y == 0 {
+			return errDiv0
+		}
+		v = x / y
+	case "^":
+		if x == 0 && y == 0 {
+			return errNum
+		}
+		v = math.Pow(x, y)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return errNum
+	}
+	return v
+}
+
+// parseUnary binds sign tighter than ^, as spreadsheets do: =-2^2 is 4.
+func (p *formulaParser) parseUnary() (formulaExpr, error) {
+	if op, ok := p.acceptOp("-", "+"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if op == "+" {
+			return operand, nil
+		}
+		return func(e *formulaEnv) interface{} {
+			v, err := toNumber(operand(e))
+			if err != "" {
+				return err
+			}
+			return -v
+		}, nil
+	}
+	return p.parsePercent()
+}
+
+func (p *formulaParser) parsePercent() (formulaExpr, error) {
+	expr, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.acceptOp("%"); !ok {
+			return expr, nil
+		}
+		inner := expr
+		expr = func(e *formulaEnv) interface{} { return arithmetic("/", inner(e), 100.0) }
+	}
+}
+
+func (p *formulaParser) parsePrimary() (formulaExpr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return constant(t.num), nil
+	case tokString:
+		return constant(t.text), nil
+	case tokError:
+		return constant(formulaError(t.text)), nil
+	case tokOp:
+		if t.text == "(" {
+			expr, err := p.parseComparison()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.acceptOp(")"); !ok {
+				return nil, fmt.Errorf("missing )")
+			}
+			return expr, nil
+		}
+	case tokName:
+		if _, ok := p.acceptOp("("); ok {
+			return p.parseCall(t.text)
+		}
+		return p.reference(t.text)
+	case tokEnd:
+		return nil, fmt.Errorf("unexpected end of formula")
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+func constant(v interface{}) formulaExpr {
+	return func(*formulaEnv) interface{} { return v }
+}
+
+// reference resolves a name token: a boolean, cell, range or named input.
+func (p *formulaParser) reference(name string) (formulaExpr, error) {
+	switch strings.ToUpper(name) {
+	case "TRUE":
+		return constant(true), nil
+	case "FALSE":
+		return constant(false), nil
+	}
+	if from, to, ok := strings.Cut(name, ":"); ok {
+		// The sheet of the first cell applies to the whole range.
+		_, _, _, ok1 := parseCell(from)
+		_, _, _, ok2 := parseCell(to)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("invalid range %q", name)
+		}
+		return func(e *formulaEnv) interface{} { return e.rangeOf(from, to) }, nil
+	}
+	return func(e *formulaEnv) interface{} { return e.cell(name) }, nil
+}
+
+// parseCall parses the arguments of a function call after its "(".
+func (p *formulaParser) parseCall(name string) (formulaExpr, error) {
+	upper := strings.ToUpper(name)
+	upper = strings.TrimPrefix(upper, "_XLFN.") // prefix Excel adds to newer functions
+	fn, ok := formulaFuncs[upper]
+	if !ok {
+		return nil, &unsupportedFunctionError{name: upper}
+	}
+
+	var args []formulaExpr
+	if _, ok := p.acceptOp(")"); !ok {
+		for {
+			// An empty argument, as in IF(A1,,1), is blank.
+			if t := p.peek(); t.kind == tokOp && (t.text == "," || t.text == ";" || t.text == ")") {
+				args = append(args, constant(nil))
+			} else {
+				arg, err := p.parseComparison()
+				if err != nil {
+					return nil, err
+				}
+				args = append(args, arg)
+			}
+			if _, ok := p.acceptOp(",", ";"); ok {
+				continue
+			}
+			if _, ok := p.acceptOp(")"); ok {
+				break
+			}
+			return nil, fmt.Errorf("missing ) after arguments to %s", upper)
+		}
+	}
+	if len(args) < fn.min || fn.max >= 0 && len(args) > fn.max {
+		return nil, fmt.Errorf("wrong number of arguments to %s", upper)
+	}
+	return func(e *formulaEnv) interface{} { return fn.call(e, args) }, nil
+}
+
+// ============================================================================
+// Functions
+// ============================================================================
+
+// formulaFunc is a spreadsheet function taking min to max arguments (-1 for
+// no limit). Arguments are evaluated by the function, so IF and IFERROR
+// evaluate only the branch they return.
+type formulaFunc struct {
+	min, max int
+	call     func(e *formulaEnv, args []formulaExpr) interface{}
+}
+
+var formulaFuncs map[string]formulaFunc
+
+func init() {
+	formulaFuncs = map[string]formulaFunc{
+		"SUM": {1, -1, aggregate(func(xs []float64) interface{} {
+			var sum float64
+			for _, x := range xs {
+				sum += x
+			}
+			return sum
+		})},
+		"PRODUCT": {1, -1, aggregate(func(xs []float64) interface{} {
+			product := 1.0
+			for _, x := range xs {
+				product *= x
+			}
+			return product
+		})},
+		"AVERAGE": {1, -1, aggregate(func(xs []float64) interface{} {
+			if len(xs) == 0 {
+				return errDiv0
+			}
+			var sum float64
+			for _, x := range xs {
+				sum += x
+			}
+			return sum / float64(len(xs))
+		})},
+		"MIN": {1, -1, aggregate(func(xs []float64) interface{} {
+			if len(xs) == 0 {
+				return 0.0
+			}
+			min := xs[0]
+			for _, x := range xs {
+				min = math.Min(min, x)
+			}
+			return min
+		})},
+		"MAX": {1, -1, aggregate(func(xs []float64) interface{} {
+			if len(xs) == 0 {
+				return 0.0
+			}
+			max := xs[0]
+			for _, x := range xs {
+				max = math.Max(max, x)
+			}
+			return max
+		})},
+		"MEDIAN": {1, -1, aggregate(func(xs []float64) interface{} {
+			if len(xs) == 0 {
+				return errNum
+			}
+			sort.Float64s(xs)
+			if n := len(xs); n%2 == 0 {
+				return (xs[n/2-1] + xs[n/2]) / 2
+			}
+			return xs[len(xs)/2]
+		})},
+		"COUNT":      {1, -1, count(func(v interface{}) bool { _, ok := v.(float64); return ok })},
+		"COUNTA":     {1, -1, count(func(v interface{}) bool { return v != nil })},
+		"COUNTBLANK": {1, 1, count(func(v interface{}) bool { return v == nil || v == "" })},
+
+		"ABS":       {1, 1, math1(math.Abs)},
+		"INT":       {1, 1, math1(math.Floor)},
+		"SQRT":      {1, 1, math1(math.Sqrt)},
+		"EXP":       {1, 1, math1(math.Exp)},
+		"LN":        {1, 1, math1(math.Log)},
+		"PI":        {0, 0, func(*formulaEnv, []formulaExpr) interface{} { return math.Pi }},
+		"POWER":     {2, 2, func(e *formulaEnv, args []formulaExpr) interface{} { return arithmetic("^", args[0](e), args[1](e)) }},
+		"ROUND":     {1, 2, round(math.Round)},
+		"ROUNDUP":   {1, 2, round(func(x float64) float64 { return math.Copysign(math.Ceil(math.Abs(x)), x) })},
+		"ROUNDDOWN": {1, 2, round(math.Trunc)},
+		"LOG": {1, 2, func(e *formulaEnv, args []formulaExpr) interface{} {
+			xs, err := numberArgs(e, args)
+			if err != "" {
+				return err
+			}
+			base := 10.0
+			if len(xs) == 2 {
+				base = xs[1]
+			}
+			return checkNumber(math.Log(xs[0]) / math.Log(base))
+		}},
+		"MOD": {2, 2, func(e *formulaEnv, args []formulaExpr) interface{} {
+			xs, err := numberArgs(e, args)
+			if err != "" {
+				return err
+			}
+			if xs[1] == 0 {
+				return errDiv0
+			}
+			return xs[0] - xs[1]*math.Floor(xs[0]/xs[1])
+		}},
+
+		"IF": {1, 3, func(e *formulaEnv, args []formulaExpr) interface{} {
+			cond, err := toBool(args[0](e))
+			if err != "" {
+				return err
+			}
+			switch {
+			case cond && len(args) > 1:
+				return args[1](e)
+			case cond:
+				return true
+			case len(args) > 2:
+				return args[2](e)
+			}
+			return false
+		}},
+		"IFERROR": {2, 2, func(e *formulaEnv, args []formulaExpr) interface{} {
+			v := scalar(args[0](e))
+			if _, ok := v.(formulaError); ok {
+				return args[1](e)
+			}
+			return v
+		}},
+		"AND": {1, -1, logical(true)},
+		"OR":  {1, -1, logical(false)},
+		"NOT": {1, 1, func(e *formulaEnv, args []formulaExpr) interface{} {
+			b, err := toBool(args[0](e))
+			if err != "" {
+				return err
+			}
+			return !b
+		}},
+		"TRUE":  {0, 0, func(*formulaEnv, []formulaExpr) interface{} { return true }},
+		"FALSE": {0, 0, func(*formulaEnv, []formulaExpr) interface{} { return false }},
+
+		"ISBLANK":  {1, 1, is(func(v interface{}) bool { return v == nil })},
+		"ISNUMBER": {1, 1, is(func(v interface{}) bool { _, ok := v.(float64); return ok })},
+		"ISTEXT":   {1, 1, is(func(v interface{}) bool { _, ok := v.(string); return ok })},
+		"ISERROR":  {1, 1, is(func(v interface{}) bool { _, ok := v.(formulaError); return ok })},
+
+		"CONCATENATE": {1, -1, concat},
+		"CONCAT":      {1, -1, concat},
+		"LEN":         {1, 1, text1(func(s string) interface{} { return float64(len([]rune(s))) })},
+		"UPPER":       {1, 1, text1(func(s string) interface{} { return strings.ToUpper(s) })},
+		"LOWER":       {1, 1, text1(func(s string) interface{} { return strings.ToLower(s) })},
+		"TRIM":        {1, 1, text1(func(s string) interface{} { return strings.Join(strings.Fields(s), " ") })},
+		"LEFT":        {1, 2, substring(func(s []rune, n int) []rune { return s[:min(n, len(s))] })},
+		"RIGHT":       {1, 2, substring(func(s []rune, n int) []rune { return s[len(s)-min(n, len(s)):] })},
+		"MID": {3, 3, func(e *formulaEnv, args []formulaExpr) interface{} {
+			s, err := toText(args[0](e))
+			if err != "" {
+				return err
+			}
+			nums, err := numberArgs(e, args[1:])
+			if err != "" {
+				return err
+			}
+			start, n := int(nums[0]), int(nums[1])
+			if start < 1 || n < 0 {
+				return errValue
+			}
+			r := []rune(s)
+			if start > len(r) {
+				return ""
+			}
+			return string(r[start-1 : min(start-1+n, len(r))])
+		}},
+
+		"SUMIF": {2, 3, conditional(func(xs []float64) interface{} { return sumFloats(xs) })},
+		"AVERAGEIF": {2, 3, conditional(func(xs []float64) interface{} {
+			if len(xs) == 0 {
+				return errDiv0
+			}
+			return sumFloats(xs) / float64(len(xs))
+		})},
+		"COUNTIF": {2, 2, func(e *formulaEnv, args []formulaExpr) interface{} {
+			r := asRange(args[0](e))
+			criterion := scalar(args[1](e))
+			var n float64
+			for _, v := range r.values {
+				if matchesCriterion(v, criterion) {
+					n++
+				}
+			}
+			return n
+		}},
+
+		"VLOOKUP": {3, 4, vlookup},
+		"MATCH":   {2, 3, match},
+		"INDEX":   {2, 3, index},
+	}
+}
+
+// aggregate builds a function over the numbers in its arguments. Text,
+// booleans and blanks in ranges are skipped; direct arguments are coerced.
+func aggregate(fn func([]float64) interface{}) func(*formulaEnv, []formulaExpr) interface{} {
+	return func(e *formulaEnv, args []formulaExpr) interface{} {
+		var xs []float64
+		for _, arg := range args {
+			v := arg(e)
+			if r, ok := v.(*formulaRange); ok {
+				for _, cell := range r.values {
+					switch cell := cell.(type) {
+					case float64:
+						xs = append(xs, cell)
+					case formulaError:
+						return cell
+					}
+				}
+				continue
+			}
+			x, err := toNumber(v)
+			if err != "" {
+				return err
+			}
+			xs = append(xs, x)
+		}
+		return fn(xs)
+	}
+}
+
+// count builds a function counting the values in its arguments that
+// satisfy ok.
+func count(ok func(interface{}) bool) func(*formulaEnv, []formulaExpr) interface{} {
+	return func(e *formulaEnv, args []formulaExpr) interface{} {
+		var n float64
+		for _, arg := range args {
+			for _, v := range asRange(arg(e)).values {
+				if ok(v) {
+					n++
+				}
+			}
+		}
+		return n
+	}
+}
+
+// asRange treats a scalar as a one-cell range.
+func asRange(v interface{}) *formulaRange {
+	if r, ok := v.(*formulaRange); ok {
+		return r
+	}
+	return &formulaRange{rows: 1, cols: 1, values: []interface{}{v}}
+}
+
+// numberArgs evaluates every argument as a number.
+func numberArgs(e *formulaEnv, args []formulaExpr) ([]float64, formulaError) {
+	xs := make([]float64, len(args))
+	for i, arg := range args {
+		x, err := toNumber(arg(e))
+		if err != "" {
+			return nil, err
+		}
+		xs[i] = x
+	}
+	return xs, ""
+}
+
+// checkNumber turns NaN and infinities into #NUM!.
+func checkNumber(v float64) interface{} {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return errNum
+	}
+	return v
+}
+
+func sumFloats(xs []float64) float64 {
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum
+}
+
+func math1(fn func(float64) float64) func(*formulaEnv, []formulaExpr) interface{} {
+	return func(e *formulaEnv, args []formulaExpr) interface{} {
+		x, err := toNumber(args[0](e))
+		if err != "" {
+			return err
+		}
+		return checkNumber(fn(x))
+	}
+}
+
+// round builds ROUND and its variants: fn rounds to an integer, applied
+// after scaling by the number of digits.
+func round(fn func(float64) float64) func(*formulaEnv, []formulaExpr) interface{} {
+	return func(e *formulaEnv, args []formulaExpr) interface{} {
+		xs, err := numberArgs(e, args)
+		if err != "" {
+			return err
+		}
+		digits := 0.0
+		if len(xs) == 2 {
+			digits = math.Trunc(xs[1])
+		}
+		scale := math.Pow(10, digits)
+		// Round to 15 significant digits first, as spreadsheets store
+		// numbers, so 2.675 rounds to 2.68 rather than 2.67.
+		scaled, _ := strconv.ParseFloat(strconv.FormatFloat(xs[0]*scale, 'g', 15, 64), 64)
+		return checkNumber(fn(scaled) / scale)
+	}
+}
+
+// logical builds AND (all true) and OR (any true).
+func logical(all bool) func(*formulaEnv, []formulaExpr) interface{} {
+	return func(e *formulaEnv, args []formulaExpr) interface{} {
+		seen := false
+		for _, arg := range args {
+			for _, v := range asRange(arg(e)).values {
+				if _, text := v.(string); text || v == nil {
+					continue // ignored in ranges
+				}
+				b, err := toBool(v)
+				if err != "" {
+					return err
+				}
+				seen = true
+				if b != all {
+					return !all
+				}
+			}
+		}
+		if !seen {
+			return errValue
+		}
+		return all
+	}
+}
+
+func is(test func(interface{}) bool) func(*formulaEnv, []formulaExpr) interface{} {
+	return func(e *formulaEnv, args []formulaExpr) interface{} {
+		return test(scalar(args[0](e)))
+	}
+}
+
+func concat(e *formulaEnv, args []formulaExpr) interface{} {
+	var b strings.Builder
+	for _, arg := range args {
+		for _, v := range asRange(arg(e)).values {
+			s, err := toText(v)
+			if err != "" {
+				return err
+			}
+			b.WriteString(s)
+		}
+	}
+	return b.String()
+}
+
+func text1(fn func(string) interface{}) func(*formulaEnv, []formulaExpr) interface{} {
+	return func(e *formulaEnv, args []formulaExpr) interface{} {
+		s, err := toText(args[0](e))
+		if err != "" {
+			return err
+		}
+		return fn(s)
+	}
+}
+
+// substring builds LEFT and RIGHT, whose count defaults to 1.
+func substring(fn func([]rune, int) []rune) func(*formulaEnv, []formulaExpr) interface{} {
+	return func(e *formulaEnv, args []formulaExpr) interface{} {
+		s, err := toText(args[0](e))
+		if err != "" {
+			return err
+		}
+		n := 1.0
+		if len(args) == 2 {
+			if n, err = toNumber(args[1](e)); err != "" {
+				return err
+			}
+		}
+		if n < 0 {
+			return errValue
+		}
+		return string(fn([]rune(s), int(n)))
+	}
+}
+
+// conditional builds SUMIF and AVERAGEIF: fn receives the numbers of
+// sum_range, or range, whose range cell matches the criterion.
+func conditional(fn func([]float64) interface{}) func(*formulaEnv, []formulaExpr) interface{} {
+	return func(e *formulaEnv, args []formulaExpr) interface{} {
+		r := asRange(args[0](e))
+		criterion := scalar(args[1](e))
+		values := r
+		if len(args) == 3 {
+			values = asRange(args[2](e))
+		}
+		var xs []float64
+		for i, v := range r.values {
+			if !matchesCriterion(v, criterion) || i >= len(values.values) {
+				continue
+			}
+			if x, ok := values.values[i].(float64); ok {
+				xs = append(xs, x)
+			}
+		}
+		return fn(xs)
+	}
+}
+
+var criterionOperator = regexp.MustCompile(`^(<=|>=|<>|<|>|=)?(.*)$`)
+
+// matchesCriterion applies a COUNTIF-style criterion such as ">5", "<>x"
+// or "app*" to v.
+func matchesCriterion(v, criterion interface{}) bool {
+	s, ok := criterion.(string)
+	if !ok {
+		return v != nil && compareValues(v, criterion) == 0 && sameKind(v, criterion)
+	}
+	m := criterionOperator.FindStringSubmatch(s)
+	op, operand := m[1], m[2]
+
+	var want interface{} = operand
+	if f, err := strconv.ParseFloat(strings.TrimSpace(operand), 64); err == nil {
+		want = f
+	} else if b, err := strconv.ParseBool(operand); err == nil && (strings.EqualFold(operand, "true") || strings.EqualFold(operand, "false")) {
+		want = b
+	}
+
+	if str, ok := want.(string); ok && (op == "" || op == "=" || op == "<>") {
+		text, isText := v.(string)
+		matched := isText && wildcardMatch(strings.ToLower(str), strings.ToLower(text))
+		if str == "" {
+			matched = v == nil || v == ""
+		}
+		return matched != (op == "<>")
+	}
+	if !sameKind(v, want) {
+		return op == "<>"
+	}
+	c := compareValues(v, want)
+	switch op {
+	case "", "=":
+		return c == 0
+	case "<>":
+		return c != 0
+	case "<":
+		return c < 0
+	case ">":
+		return c > 0
+	case "<=":
+		return c <= 0
+	}
+	return c >= 0
+}
+
+func sameKind(a, b interface{}) bool {
+	return reflect.TypeOf(a) == reflect.TypeOf(b)
+}
+
+// wildcardMatch matches text against a pattern with * and ? wildcards and
+// ~ escapes.
+func wildcardMatch(pattern, text string) bool {
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '~' && i+1 < len(pattern):
+			i++
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		case c == '*':
+			re.WriteString("(?s:.*)")
+		case c == '?':
+			re.WriteString("(?s:.)")
+		default:
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	re.WriteString("$")
+	matched, _ := regexp.MatchString(re.String(), text)
+	return matched
+}
+
+// lookupPosition finds want in values, exactly or, for sorted values, the
+// last value not greater than want. It returns -1 if there is none.
+func lookupPosition(values []interface{}, want interface{}, exact bool) int {
+	found := -1
+	for i, v := range values {
+		if exact {
+			if v != nil && sameKind(v, want) && compareValues(v, want) == 0 {
+				return i
+			}
+			if s, ok := want.(string); ok {
+				if text, ok := v.(string); ok && strings.ContainsAny(s, "*?") && wildcardMatch(strings.ToLower(s), strings.ToLower(text)) {
+					return i
+				}
+			}
+			continue
+		}
+		if v == nil || !sameKind(v, want) {
+			continue
+		}
+		if compareValues(v, want) > 0 {
+			break
+		}
+		found = i
+	}
+	return found
+}
+
+func vlookup(e *formulaEnv, args []formulaExpr) interface{} {
+	want := scalar(args[0](e))
+	if err, ok := want.(formulaError); ok {
+		return err
+	}
+	table := asRange(args[1](e))
+	col, err := toNumber(args[2](e))
+	if err != "" {
+		return err
+	}
+	approximate := true
+	if len(args) == 4 {
+		if approximate, err = toBool(args[3](e)); err != "" {
+			return err
+		}
+	}
+	if int(col) < 1 {
+		return errValue
+	}
+	if int(col) > table.cols {
+		return errRef
+	}
+
+	first := make([]interface{}, table.rows)
+	for r := range first {
+		first[r] = table.at(r, 0)
+	}
+	row := lookupPosition(first, want, !approximate)
+	if row < 0 {
+		return errNA
+	}
+	return table.at(row, int(col)-1)
+}
+
+func match(e *formulaEnv, args []formulaExpr) interface{} {
+	want := scalar(args[0](e))
+	if err, ok := want.(formulaError); ok {
+		return err
+	}
+	r := asRange(args[1](e))
+	if r.rows > 1 && r.cols > 1 {
+		return errNA
+	}
+	kind := 1.0
+	if len(args) == 3 {
+		var err formulaError
+		if kind, err = toNumber(args[2](e)); err != "" {
+			return err
+		}
+	}
+	if kind < 0 {
+		// Descending order; the position of the last value not smaller.
+		found := -1
+		for i, v := range r.values {
+			if v != nil && sameKind(v, want) && compareValues(v, want) < 0 {
+				break
+			}
+			found = i
+		}
+		if found < 0 {
+			return errNA
+		}
+		return float64(found + 1)
+	}
+	i := lookupPosition(r.values, want, kind == 0)
+	if i < 0 {
+		return errNA
+	}
+	return float64(i + 1)
+}
+
+func index(e *formulaEnv, args []formulaExpr) interface{} {
+	r := asRange(args[0](e))
+	nums, err := numberArgs(e, args[1:])
+	if err != "" {
+		return err
+	}
+	row, col := int(nums[0]), 1
+	if len(nums) == 2 {
+		col = int(nums[1])
+	} else if r.rows == 1 {
+		row, col = 1, row // a single row is indexed by column
+	}
+	if row < 1 || col < 1 || row > r.rows || col > r.cols {
+		return errRef
+	}
+	return r.at(row-1, col-1)
+}
@@ -0,0 +1,148 @@
+package qwed
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVerifyFormula(t *testing.T) {
+	client := NewClient("test")
+	inputs := map[string]interface{}{"A1": 10, "A2": 20, "A3": 30, "tax_rate": 0.2}
+
+	resp, err := client.VerifyFormula(context.Background(), "=SUM(A1:A3)*(1+tax_rate)", inputs, 72)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Verified || resp.Engine != EngineLocalFormula || resp.Result["value"] != 72.0 {
+		t.Errorf("expected 72 verified by %s, got %+v", EngineLocalFormula, resp)
+	}
+
+	resp, _ = client.VerifyFormula(context.Background(), "=SUM(A1:A3)*tax_rate", inputs, 72)
+	if resp.Verified || resp.Result["value"] != 12.0 {
+		t.Errorf("expected a wrong formula to fail with value 12, got %v", resp.Result)
+	}
+}
+
+func TestFormulaEvaluation(t *testing.T) {
+	inputs := map[string]interface{}{
+		"A1": 10, "A2": 20, "A3": "n/a", "A4": nil, "A5": -5,
+		"B1": "apple", "B2": "banana", "B3": "apricot", "B4": "cherry", "B5": "apple",
+		"C1": 1.5, "C2": 2.5, "C3": 3.5, "C4": 4.5, "C5": 5.5,
+		"Sheet2!A1": 7,
+		"prices":    []float64{1, 2, 3},
+		"grid":      [][]interface{}{{"a", 1, "x"}, {"b", 2, "y"}, {"c", 3, "z"}},
+	}
+	tests := []struct {
+		formula  string
+		expected interface{}
+	}{
+		// Arithmetic and precedence
+		{"=1+2*3", 7},
+		{"=-2^2", 4},
+		{"=2^3^2", 64},
+		{"=50%*A1", 5},
+		{"=100/3", 33.33},
+		{"=100/3", "33.333"},
+		{"=1/0", "#DIV/0!"},
+		{"=A3+1", "#VALUE!"},
+		{"=A4+1", 1},
+		{"=$A$1+Sheet2!A1", 17},
+		{"=unknown_name*2", "#NAME?"},
+
+		// Comparison and text
+		{`="abc"="ABC"`, true},
+		{"=A1>A2", false},
+		{`=B1&" pie"`, "apple pie"},
+		{`=CONCATENATE(B1,"-",A1)`, "apple-10"},
+		{`=UPPER(LEFT(B2,3))`, "BAN"},
+		{`=MID("spreadsheet",7,5)`, "sheet"},
+		{`=LEN(TRIM("  a   b  "))`, 3},
+
+		// Aggregates skip text and blanks in ranges
+		{"=SUM(A1:A5)", 25},
+		{"=AVERAGE(A1:A5)", 8.33},
+		{"=COUNT(A1:A5)", 3},
+		{"=COUNTA(A1:A5)", 4},
+		{"=MAX(A1:A5)-MIN(A1:A5)", 25},
+		{"=MEDIAN(C1:C5)", 3.5},
+		{"=SUM(prices)", 6},
+		{"=PRODUCT(prices;2)", 12},
+
+		// Rounding
+		{"=ROUND(2.675,2)", "2.68"},
+		{"=ROUNDUP(-2.1,0)", -3},
+		{"=ROUNDDOWN(2.99,1)", "2.9"},
+		{"=ROUND(1234,-2)", 1200},
+		{"=MOD(-3,2)", 1},
+		{"=INT(-2.5)", -3},
+
+		// Logic
+		{`=IF(A1>50,"big","small")`, "small"},
+		{`=IF(A2>15,"big","small")`, "big"},
+		{`=IF(A1>5,1/0,"fine")`, "#DIV/0!"},
+		{`=IF(A1<5,1/0,"lazy")`, "lazy"},
+		{`=IFERROR(1/0,"n/a")`, "n/a"},
+		{`=AND(A1>0,A2>0)`, true},
+		{`=OR(A1>100,NOT(TRUE))`, false},
+		{`=ISBLANK(A4)`, true},
+
+		// Conditional aggregates
+		{`=SUMIF(A1:A5,">0")`, 30},
+		{`=COUNTIF(B1:B5,"ap*")`, 3},
+		{`=COUNTIF(B1:B5,"<>apple")`, 3},
+		{`=SUMIF(B1:B5,"apple",C1:C5)`, 7},
+		{`=AVERAGEIF(B1:B5,"apple",C1:C5)`, 3.5},
+
+		// Lookups
+		{`=VLOOKUP("b",grid,3,FALSE)`, "y"},
+		{`=VLOOKUP("d",grid,2,FALSE)`, "#N/A"},
+		{`=VLOOKUP(3,C1:C5,1)`, 2.5},
+		{`=INDEX(C1:C5,MATCH(4.5,C1:C5,0))`, 4.5},
+		{`=INDEX(grid,2,2)`, 2},
+		{`=MATCH("cherry",B1:B5,0)`, 4},
+		{`=_xlfn.CONCAT(B1,B2)`, "applebanana"},
+	}
+	for _, tt := range tests {
+		resp := localVerifyFormula(tt.formula, inputs, tt.expected)
+		if !resp.Verified {
+			t.Errorf("%s: expected %v, got %v (%v)", tt.formula, tt.expected, resp.Result["value"], resp.Result)
+		}
+	}
+}
+
+func TestFormulaTolerance(t *testing.T) {
+	if resp := localVerifyFormula("=12.4", nil, 12); resp.Verified {
+		t.Error("expected a whole number to require an exact match")
+	}
+	if resp := localVerifyFormula("=2/3", nil, 0.67); !resp.Verified {
+		t.Errorf("expected 0.67 to match 2/3, got %v", resp.Result)
+	}
+	if resp := localVerifyFormula("=2/3", nil, 0.66); resp.Verified {
+		t.Error("expected 0.66 not to match 2/3")
+	}
+}
+
+func TestFormulaErrors(t *testing.T) {
+	tests := []struct {
+		formula string
+		status  Status
+	}{
+		{"=SUM(A1:A3", StatusFailed},
+		{"=1+", StatusFailed},
+		{`="unterminated`, StatusFailed},
+		{"=ROUND()", StatusFailed},
+		{"=", StatusFailed},
+		{"=XLOOKUP(A1,B1:B3,C1:C3)", StatusUnsupported},
+	}
+	for _, tt := range tests {
+		resp := localVerifyFormula(tt.formula, nil, 0)
+		if resp.Status != tt.status {
+			t.Errorf("%s: expected %s, got %s (%v)", tt.formula, tt.status, resp.Status, resp.Result)
+		}
+	}
+
+	resp := localVerifyFormula("=A1", map[string]interface{}{"A1": struct{}{}}, 0)
+	if resp.Status != StatusUnsupported {
+		t.Errorf("expected an unsupported input type, got %s", resp.Status)
+	}
+}
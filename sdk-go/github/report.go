@@ -0,0 +1,206 @@
+package github
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// ============================================================================
+// Reports
+// ============================================================================
+
+// Finding is one problem to report on the pull request. Findings with a
+// path and line are also posted as inline review comments when the line is
+// part of the diff.
+type Finding struct {
+	Path     string // file path relative to the repository root
+	Line     int    // 1-based line in the PR's head version; 0 if unknown
+	Severity string // qwed.SeverityCritical, SeverityWarning or SeverityInfo
+	Rule     string // rule or engine, e.g. "weak_hash"
+	Message  string
+}
+
+// Report is the outcome of a verification run to summarize on a pull
+// request.
+type Report struct {
+	// Title heads the summary comment. Defaults to "QWED verification".
+	Title string
+	// Checked is the number of files or items verified.
+	Checked  int
+	Findings []Finding
+}
+
+// FromCode returns the unsuppressed findings of a VerifyCode response for
+// the file at path. A failed response without findings is reported as one
+// finding on the file.
+func FromCode(path string, resp *qwed.VerificationResponse) []Finding {
+	if resp == nil {
+		return nil
+	}
+	var findings []Finding
+	for _, f := range qwed.UnsuppressedFindings(resp) {
+		message := f.Description
+		if message == "" {
+			message = f.Type
+		}
+		if f.Recommendation != "" {
+			message += "\n\n" + f.Recommendation
+		}
+		findings = append(findings, Finding{Path: path, Line: f.Line, Severity: f.Severity, Rule: f.Type, Message: message})
+	}
+	if len(findings) == 0 && !resp.Verified {
+		findings = append(findings, Finding{Path: path, Severity: qwed.SeverityCritical, Rule: resp.Engine.String(), Message: failure(resp.Error, resp.Result)})
+	}
+	return findings
+}
+
+// FromBatch returns a finding for each item of a batch job that did not
+// verify. items are the items the batch was submitted with, in order.
+func FromBatch(items []qwed.BatchItem, batch *qwed.BatchResponse) []Finding {
+	if batch == nil {
+		return nil
+	}
+	var findings []Finding
+	for i, item := range batch.Items {
+		if item.Verified {
+			continue
+		}
+		var query string
+		var engine qwed.VerificationType
+		if i < len(items) {
+			query, engine = items[i].Query, items[i].Type
+		}
+		message := failure(item.Error, item.Result)
+		if query != "" {
+			message = "`" + oneLine(query) + "`: " + message
+		}
+		findings = append(findings, Finding{Severity: qwed.SeverityCritical, Rule: string(engine), Message: message})
+	}
+	return findings
+}
+
+// failure explains a failed verification.
+func failure(e *qwed.ErrorInfo, result map[string]interface{}) string {
+	if e != nil && e.Message != "" {
+		return e.Message
+	}
+	for _, key := range []string{"reason", "error", "explanation"} {
+		if s, ok := result[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return "verification failed"
+}
+
+// maxSummaryRows limits the findings listed in the summary comment.
+const maxSummaryRows = 50
+
+// summary renders the report as the Markdown body of the summary comment,
+// starting with marker so the comment can be found and updated.
+func (r Report) summary(marker string) string {
+	title := r.Title
+	if title == "" {
+		title = "QWED verification"
+	}
+
+	var b strings.Builder
+	b.WriteString(marker + "\n")
+	if len(r.Findings) == 0 {
+		fmt.Fprintf(&b, "### %s: passed\n\n", title)
+		if r.Checked > 0 {
+			fmt.Fprintf(&b, "All %d checked %s verified.\n", r.Checked, plural(r.Checked, "item"))
+		}
+		return b.String()
+	}
+
+	counts := make(map[string]int)
+	for _, f := range r.Findings {
+		counts[f.Severity]++
+	}
+	var parts []string
+	for _, s := range []string{qwed.SeverityCritical, qwed.SeverityWarning, qwed.SeverityInfo} {
+		if counts[s] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[s], strings.ToLower(s)))
+		}
+	}
+	fmt.Fprintf(&b, "### %s: %d %s\n\n", title, len(r.Findings), plural(len(r.Findings), "issue"))
+	if r.Checked > 0 {
+		fmt.Fprintf(&b, "%d %s checked. ", r.Checked, plural(r.Checked, "item"))
+	}
+	if len(parts) > 0 {
+		fmt.Fprintf(&b, "Issues by severity: %s.", strings.Join(parts, ", "))
+	}
+	b.WriteString("\n\n| Severity | Location | Rule | Message |\n| --- | --- | --- | --- |\n")
+
+	findings := sortedFindings(r.Findings)
+	for i, f := range findings {
+		if i == maxSummaryRows {
+			fmt.Fprintf(&b, "\n…and %d more.\n", len(findings)-maxSummaryRows)
+			break
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", f.Severity, cell(location(f)), cell(f.Rule), cell(f.Message))
+	}
+	return b.String()
+}
+
+// inline renders a finding as an inline review comment body.
+func (f Finding) inline(marker string) string {
+	return fmt.Sprintf("%s\n**%s** `%s`\n\n%s", marker, f.Severity, f.Rule, f.Message)
+}
+
+// sortedFindings orders findings by severity, then location.
+func sortedFindings(findings []Finding) []Finding {
+	sorted := append([]Finding(nil), findings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if ra, rb := severityRank(a.Severity), severityRank(b.Severity); ra != rb {
+			return ra > rb
+		}
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Line < b.Line
+	})
+	return sorted
+}
+
+func location(f Finding) string {
+	switch {
+	case f.Path == "":
+		return ""
+	case f.Line > 0:
+		return fmt.Sprintf("`%s:%d`", f.Path, f.Line)
+	default:
+		return "`" + f.Path + "`"
+	}
+}
+
+func cell(s string) string {
+	return strings.ReplaceAll(oneLine(s), "|", `\|`)
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return noun
+	}
+	return noun + "s"
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case qwed.SeverityCritical:
+		return 3
+	case qwed.SeverityWarning:
+		return 2
+	case qwed.SeverityInfo:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,67 @@
+package github
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+func TestFromCode(t *testing.T) {
+	resp := &qwed.VerificationResponse{Status: qwed.StatusFailed, Engine: "code", Result: map[string]interface{}{
+		"issues": []interface{}{
+			map[string]interface{}{"severity": "CRITICAL", "type": "eval", "description": "eval of input", "line_number": 3, "recommendation": "Use ast.literal_eval"},
+			map[string]interface{}{"severity": "WARNING", "type": "weak_hash", "line_number": 9, "suppressed": true},
+		},
+	}}
+	findings := FromCode("app.py", resp)
+	if len(findings) != 1 || findings[0].Line != 3 || findings[0].Rule != "eval" || !strings.Contains(findings[0].Message, "literal_eval") {
+		t.Errorf("unexpected findings: %+v", findings)
+	}
+
+	resp = &qwed.VerificationResponse{Status: qwed.StatusError, Engine: "code", Error: &qwed.ErrorInfo{Message: "syntax error"}}
+	if findings := FromCode("app.py", resp); len(findings) != 1 || findings[0].Message != "syntax error" {
+		t.Errorf("expected the failure as a file-level finding, got %+v", findings)
+	}
+}
+
+func TestFromBatch(t *testing.T) {
+	items := []qwed.BatchItem{{Query: "2+2=4", Type: qwed.TypeMath}, {Query: "2+2=5", Type: qwed.TypeMath}}
+	findings := FromBatch(items, &qwed.BatchResponse{Items: []qwed.BatchResult{
+		{Status: qwed.StatusVerified, Verified: true},
+		{Status: qwed.StatusFailed, Result: map[string]interface{}{"reason": "expected 4"}},
+	}})
+	if len(findings) != 1 || findings[0].Rule != "math" || findings[0].Message != "`2+2=5`: expected 4" {
+		t.Errorf("unexpected findings: %+v", findings)
+	}
+}
+
+func TestReportSummary(t *testing.T) {
+	report := Report{Checked: 3, Findings: []Finding{
+		{Path: "b.py", Line: 2, Severity: qwed.SeverityWarning, Rule: "weak_hash", Message: "MD5 | SHA1"},
+		{Path: "a.py", Line: 7, Severity: qwed.SeverityCritical, Rule: "eval", Message: "eval\nof input"},
+	}}
+	summary := report.summary("<!-- m -->")
+	for _, want := range []string{
+		"<!-- m -->\n### QWED verification: 2 issues",
+		"Issues by severity: 1 critical, 1 warning.",
+		"| CRITICAL | `a.py:7` | eval | eval of input |\n| WARNING | `b.py:2` | weak_hash | MD5 \\| SHA1 |",
+	} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("expected %q in summary:\n%s", want, summary)
+		}
+	}
+
+	if summary := (Report{Checked: 1}).summary(""); !strings.Contains(summary, "passed") || !strings.Contains(summary, "All 1 checked item verified.") {
+		t.Errorf("unexpected passing summary:\n%s", summary)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	patch := "@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n@@ -20,2 +20,3 @@ func f() {\n x\n+y\n z\n\\ No newline at end of file"
+	want := map[int]bool{1: true, 2: true, 3: true, 20: true, 21: true, 22: true}
+	if got := diffLines(patch); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffLines = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,441 @@
+// Package github posts QWED verification results to GitHub pull requests:
+// one summary comment, updated in place on every run, and inline review
+// comments on the affected lines of the diff.
+//
+// Example usage in a GitHub Actions job:
+//
+//	reporter, err := github.FromEnv()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	resp, err := client.VerifyCode(ctx, code, "python")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	_, err = reporter.Post(ctx, github.Report{
+//	    Checked:  1,
+//	    Findings: github.FromCode("app/handlers.py", resp),
+//	})
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Reporter
+// ============================================================================
+
+// DefaultBaseURL is the GitHub REST API endpoint.
+const DefaultBaseURL = "https://api.github.com"
+
+const (
+	defaultMaxInline = 30
+	perPage          = 100
+)
+
+// Reporter posts reports to one pull request.
+type Reporter struct {
+	token string
+	owner string
+	repo  string
+	pr    int
+
+	baseURL    string
+	httpClient *http.Client
+	key        string
+	inline     bool
+	maxInline  int
+}
+
+// Option configures a Reporter.
+type Option func(*Reporter)
+
+// WithBaseURL sets the API endpoint, such as
+// "https://github.example.com/api/v3" for GitHub Enterprise Server.
+func WithBaseURL(url string) Option {
+	return func(r *Reporter) {
+		r.baseURL = strings.TrimSuffix(url, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to call the API.
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *Reporter) {
+		r.httpClient = client
+	}
+}
+
+// WithKey distinguishes the comments of several jobs reporting on the same
+// pull request, each of which keeps its own summary comment.
+func WithKey(key string) Option {
+	return func(r *Reporter) {
+		r.key = key
+	}
+}
+
+// WithInline enables or disables inline review comments. Enabled by
+// default.
+func WithInline(enabled bool) Option {
+	return func(r *Reporter) {
+		r.inline = enabled
+	}
+}
+
+// WithMaxInline limits the inline comments posted per run. Defaults to 30;
+// further findings appear only in the summary.
+func WithMaxInline(n int) Option {
+	return func(r *Reporter) {
+		r.maxInline = n
+	}
+}
+
+// NewReporter creates a reporter for pull request pr of repository, given
+// as "owner/name". token needs permission to write pull requests.
+func NewReporter(token, repository string, pr int, opts ...Option) (*Reporter, error) {
+	owner, repo, ok := strings.Cut(repository, "/")
+	if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return nil, fmt.Errorf("repository must be owner/name, got %q", repository)
+	}
+	if pr <= 0 {
+		return nil, fmt.Errorf("invalid pull request number %d", pr)
+	}
+	r := &Reporter{
+		token:      token,
+		owner:      owner,
+		repo:       repo,
+		pr:         pr,
+		baseURL:    DefaultBaseURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		inline:     true,
+		maxInline:  defaultMaxInline,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r, nil
+}
+
+// FromEnv creates a reporter for the pull request that triggered the
+// current GitHub Actions run, from GITHUB_TOKEN, GITHUB_REPOSITORY,
+// GITHUB_API_URL and the pull request event in GITHUB_EVENT_PATH.
+func FromEnv(opts ...Option) (*Reporter, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GITHUB_TOKEN is not set")
+	}
+
+	pr := 0
+	if path := os.Getenv("GITHUB_EVENT_PATH"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read event: %w", err)
+		}
+		var event struct {
+			Number      int `json:"number"`
+			PullRequest struct {
+				Number int `json:"number"`
+			} `json:"pull_request"`
+		}
+		if err := json.Unmarshal(data, &event); err != nil {
+			return nil, fmt.Errorf("failed to parse event: %w", err)
+		}
+		pr = event.PullRequest.Number
+		if pr == 0 {
+			pr = event.Number
+		}
+	}
+	if pr == 0 {
+		// refs/pull/<number>/merge
+		if rest, ok := strings.CutPrefix(os.Getenv("GITHUB_REF"), "refs/pull/"); ok {
+			pr, _ = strconv.Atoi(strings.TrimSuffix(rest, "/merge"))
+		}
+	}
+	if pr == 0 {
+		return nil, fmt.Errorf("not running for a pull request")
+	}
+
+	if api := os.Getenv("GITHUB_API_URL"); api != "" {
+		opts = append([]Option{WithBaseURL(api)}, opts...)
+	}
+	return NewReporter(token, os.Getenv("GITHUB_REPOSITORY"), pr, opts...)
+}
+
+// Result describes what Post changed on the pull request.
+type Result struct {
+	CommentURL string // the summary comment
+	Updated    bool   // the summary comment existed and was updated
+	Inline     int    // inline comments posted
+	Skipped    int    // findings not posted inline: outside the diff, already commented, or over the limit
+}
+
+// Post creates or updates the summary comment for report and posts inline
+// review comments for findings on lines changed in the pull request.
+// Findings already commented on by a previous run are not posted again.
+func (r *Reporter) Post(ctx context.Context, report Report) (*Result, error) {
+	result := &Result{}
+	if err := r.postSummary(ctx, report, result); err != nil {
+		return result, err
+	}
+	if r.inline {
+		if err := r.postInline(ctx, report.Findings, result); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// marker is the hidden HTML comment identifying the summary comment.
+func (r *Reporter) marker() string {
+	if r.key == "" {
+		return "<!-- qwed-report -->"
+	}
+	return "<!-- qwed-report:" + r.key + " -->"
+}
+
+type comment struct {
+	ID      int64  `json:"id"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+func (r *Reporter) postSummary(ctx context.Context, report Report, result *Result) error {
+	body := report.summary(r.marker())
+
+	var existing *comment
+	err := r.list(ctx, r.repoPath("/issues/%d/comments", r.pr), func(page json.RawMessage) (int, error) {
+		var comments []comment
+		if err := json.Unmarshal(page, &comments); err != nil {
+			return 0, err
+		}
+		for i := range comments {
+			if strings.HasPrefix(comments[i].Body, r.marker()) {
+				existing = &comments[i]
+			}
+		}
+		return len(comments), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	var posted comment
+	if existing != nil {
+		err = r.do(ctx, "PATCH", r.repoPath("/issues/comments/%d", existing.ID), map[string]string{"body": body}, &posted)
+		result.Updated = true
+	} else {
+		err = r.do(ctx, "POST", r.repoPath("/issues/%d/comments", r.pr), map[string]string{"body": body}, &posted)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to post summary comment: %w", err)
+	}
+	result.CommentURL = posted.HTMLURL
+	return nil
+}
+
+func (r *Reporter) postInline(ctx context.Context, findings []Finding, result *Result) error {
+	var candidates []Finding
+	for _, f := range sortedFindings(findings) {
+		if f.Path != "" && f.Line > 0 {
+			candidates = append(candidates, f)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	var pull struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := r.do(ctx, "GET", r.repoPath("/pulls/%d", r.pr), nil, &pull); err != nil {
+		return fmt.Errorf("failed to get pull request: %w", err)
+	}
+
+	// Review comments can only be placed on lines in the diff.
+	changed := make(map[string]map[int]bool)
+	err := r.list(ctx, r.repoPath("/pulls/%d/files", r.pr), func(page json.RawMessage) (int, error) {
+		var files []struct {
+			Filename string `json:"filename"`
+			Patch    string `json:"patch"`
+		}
+		if err := json.Unmarshal(page, &files); err != nil {
+			return 0, err
+		}
+		for _, f := range files {
+			changed[f.Filename] = diffLines(f.Patch)
+		}
+		return len(files), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list changed files: %w", err)
+	}
+
+	posted := make(map[string]bool)
+	err = r.list(ctx, r.repoPath("/pulls/%d/comments", r.pr), func(page json.RawMessage) (int, error) {
+		var comments []comment
+		if err := json.Unmarshal(page, &comments); err != nil {
+			return 0, err
+		}
+		for _, c := range comments {
+			if first, _, _ := strings.Cut(c.Body, "\n"); strings.HasPrefix(first, "<!-- qwed-finding:") {
+				posted[first] = true
+			}
+		}
+		return len(comments), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list review comments: %w", err)
+	}
+
+	type reviewComment struct {
+		Path string `json:"path"`
+		Line int    `json:"line"`
+		Side string `json:"side"`
+		Body string `json:"body"`
+	}
+	var comments []reviewComment
+	for _, f := range candidates {
+		marker := findingMarker(f)
+		if !changed[f.Path][f.Line] || posted[marker] || len(comments) >= r.maxInline {
+			result.Skipped++
+			continue
+		}
+		posted[marker] = true
+		comments = append(comments, reviewComment{Path: f.Path, Line: f.Line, Side: "RIGHT", Body: f.inline(marker)})
+	}
+	if len(comments) == 0 {
+		return nil
+	}
+
+	review := map[string]interface{}{
+		"commit_id": pull.Head.SHA,
+		"event":     "COMMENT",
+		"comments":  comments,
+	}
+	if err := r.do(ctx, "POST", r.repoPath("/pulls/%d/reviews", r.pr), review, nil); err != nil {
+		return fmt.Errorf("failed to post review comments: %w", err)
+	}
+	result.Inline = len(comments)
+	return nil
+}
+
+// findingMarker identifies a finding across runs.
+func findingMarker(f Finding) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s\x00%s", f.Path, f.Line, f.Rule, f.Message)))
+	return "<!-- qwed-finding:" + hex.EncodeToString(sum[:8]) + " -->"
+}
+
+// diffLines returns the lines of the new file that a unified diff patch
+// shows, added or unchanged, which are the lines review comments can be
+// placed on.
+func diffLines(patch string) map[int]bool {
+	lines := make(map[int]bool)
+	next := 0
+	for _, line := range strings.Split(patch, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			// @@ -old,count +new,count @@
+			next = 0
+			if i := strings.Index(line, " +"); i >= 0 {
+				fmt.Sscanf(line[i+2:], "%d", &next)
+			}
+		case strings.HasPrefix(line, "-"), strings.HasPrefix(line, `\`):
+		default:
+			if next > 0 {
+				lines[next] = true
+				next++
+			}
+		}
+	}
+	return lines
+}
+
+// ============================================================================
+// API Requests
+// ============================================================================
+
+func (r *Reporter) repoPath(format string, args ...interface{}) string {
+	return fmt.Sprintf("/repos/%s/%s", r.owner, r.repo) + fmt.Sprintf(format, args...)
+}
+
+// list calls fn with each page of a list endpoint until a page has fewer
+// than perPage entries. fn returns the number of entries on the page.
+func (r *Reporter) list(ctx context.Context, path string, fn func(json.RawMessage) (int, error)) error {
+	for page := 1; ; page++ {
+		var raw json.RawMessage
+		if err := r.do(ctx, "GET", fmt.Sprintf("%s?per_page=%d&page=%d", path, perPage, page), nil, &raw); err != nil {
+			return err
+		}
+		n, err := fn(raw)
+		if err != nil {
+			return fmt.Errorf("failed to parse response: %w", err)
+		}
+		if n < perPage {
+			return nil
+		}
+	}
+}
+
+func (r *Reporter) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, r.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode/100 != 2 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(data, &apiErr)
+		if apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, apiErr.Message)
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// fakeGitHub serves the pull request endpoints used by Reporter.
+type fakeGitHub struct {
+	mu             sync.Mutex
+	issueComments  []comment
+	reviewComments []comment
+	reviews        int
+	nextID         int64
+}
+
+func (g *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if r.Header.Get("Authorization") != "Bearer test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"message":"Bad credentials"}`))
+		return
+	}
+
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	switch route := r.Method + " " + r.URL.Path; route {
+	case "GET /repos/acme/app/issues/7/comments":
+		json.NewEncoder(w).Encode(g.issueComments)
+	case "POST /repos/acme/app/issues/7/comments":
+		g.nextID++
+		c := comment{ID: g.nextID, Body: body["body"].(string), HTMLURL: fmt.Sprintf("https://github.com/acme/app/pull/7#issuecomment-%d", g.nextID)}
+		g.issueComments = append(g.issueComments, c)
+		json.NewEncoder(w).Encode(c)
+	case "GET /repos/acme/app/pulls/7":
+		w.Write([]byte(`{"head":{"sha":"abc123"}}`))
+	case "GET /repos/acme/app/pulls/7/files":
+		w.Write([]byte(`[{"filename":"app/db.py","patch":"@@ -10,3 +10,4 @@ def q():\n     a = 1\n-    b = 2\n+    b = md5(x)\n+    c = 3\n     return a"}]`))
+	case "GET /repos/acme/app/pulls/7/comments":
+		json.NewEncoder(w).Encode(g.reviewComments)
+	case "POST /repos/acme/app/pulls/7/reviews":
+		if body["commit_id"] != "abc123" || body["event"] != "COMMENT" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		g.reviews++
+		for _, c := range body["comments"].([]interface{}) {
+			c := c.(map[string]interface{})
+			g.reviewComments = append(g.reviewComments, comment{Body: c["body"].(string)})
+		}
+		w.Write([]byte(`{}`))
+	default:
+		if id, ok := strings.CutPrefix(route, "PATCH /repos/acme/app/issues/comments/"); ok {
+			for i := range g.issueComments {
+				if fmt.Sprint(g.issueComments[i].ID) == id {
+					g.issueComments[i].Body = body["body"].(string)
+					json.NewEncoder(w).Encode(g.issueComments[i])
+					return
+				}
+			}
+		}
+		http.NotFound(w, r)
+	}
+}
+
+func TestReporterPost(t *testing.T) {
+	gh := &fakeGitHub{}
+	server := httptest.NewServer(gh)
+	defer server.Close()
+
+	reporter, err := NewReporter("test-token", "acme/app", 7, WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := Report{Checked: 2, Findings: []Finding{
+		{Path: "app/db.py", Line: 12, Severity: qwed.SeverityCritical, Rule: "weak_hash", Message: "MD5 is broken"},
+		{Path: "app/db.py", Line: 40, Severity: qwed.SeverityWarning, Rule: "sql_concat", Message: "query built by concatenation"},
+	}}
+
+	ctx := context.Background()
+	result, err := reporter.Post(ctx, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Updated || result.CommentURL == "" || result.Inline != 1 || result.Skipped != 1 {
+		t.Errorf("expected a new comment and one inline comment, got %+v", result)
+	}
+	if len(gh.reviewComments) != 1 || !strings.Contains(gh.reviewComments[0].Body, "MD5 is broken") {
+		t.Errorf("expected an inline comment on the changed line, got %+v", gh.reviewComments)
+	}
+
+	report.Findings = report.Findings[:1]
+	result, err = reporter.Post(ctx, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Updated || result.Inline != 0 || gh.reviews != 1 {
+		t.Errorf("expected the summary to be updated without repeating inline comments, got %+v", result)
+	}
+	if len(gh.issueComments) != 1 || !strings.Contains(gh.issueComments[0].Body, "1 issue") {
+		t.Errorf("expected one updated summary comment, got %+v", gh.issueComments)
+	}
+
+	// A second job keeps its own summary comment.
+	other, _ := NewReporter("test-token", "acme/app", 7, WithBaseURL(server.URL), WithKey("sql"))
+	if _, err := other.Post(ctx, Report{Title: "SQL checks", Checked: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if len(gh.issueComments) != 2 || !strings.Contains(gh.issueComments[1].Body, "SQL checks: passed") {
+		t.Errorf("expected a separate comment for the keyed reporter, got %+v", gh.issueComments)
+	}
+}
+
+func TestReporterAPIError(t *testing.T) {
+	server := httptest.NewServer(&fakeGitHub{})
+	defer server.Close()
+
+	reporter, _ := NewReporter("wrong-token", "acme/app", 7, WithBaseURL(server.URL))
+	_, err := reporter.Post(context.Background(), Report{})
+	if err == nil || !strings.Contains(err.Error(), "Bad credentials") {
+		t.Errorf("expected the API error message, got %v", err)
+	}
+}
+
+func TestNewReporterValidation(t *testing.T) {
+	if _, err := NewReporter("t", "acme", 1); err == nil {
+		t.Error("expected an error for a repository without owner")
+	}
+	if _, err := NewReporter("t", "acme/app", 0); err == nil {
+		t.Error("expected an error for a missing pull request number")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	event := filepath.Join(t.TempDir(), "event.json")
+	os.WriteFile(event, []byte(`{"pull_request":{"number":42}}`), 0o644)
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	t.Setenv("GITHUB_REPOSITORY", "acme/app")
+	t.Setenv("GITHUB_API_URL", "https://github.example.com/api/v3")
+	t.Setenv("GITHUB_EVENT_PATH", event)
+
+	reporter, err := FromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reporter.pr != 42 || reporter.owner != "acme" || reporter.baseURL != "https://github.example.com/api/v3" {
+		t.Errorf("unexpected reporter: %+v", reporter)
+	}
+
+	t.Setenv("GITHUB_EVENT_PATH", "")
+	t.Setenv("GITHUB_REF", "refs/pull/9/merge")
+	if reporter, err := FromEnv(); err != nil || reporter.pr != 9 {
+		t.Errorf("expected the PR number from GITHUB_REF, got %v", err)
+	}
+
+	t.Setenv("GITHUB_REF", "refs/heads/main")
+	if _, err := FromEnv(); err == nil {
+		t.Error("expected an error outside a pull request")
+	}
+}
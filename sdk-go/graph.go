@@ -0,0 +1,77 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ============================================================================
+// Graph Claim Verification
+// ============================================================================
+
+// Edge is an edge of a graph, from one node to another.
+type Edge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label,omitempty"` // relationship, e.g. "reports_to" or "depends_on"
+}
+
+// GraphOptions configures VerifyGraphClaimWithOptions.
+type GraphOptions struct {
+	// Undirected treats every edge as going both ways. Edges are directed
+	// by default.
+	Undirected bool
+}
+
+// GraphEvidence is what the engine found when checking a graph claim.
+type GraphEvidence struct {
+	Path   []string `json:"path,omitempty"`   // a shortest path between the nodes of the claim
+	Cycle  []string `json:"cycle,omitempty"`  // a cycle, first node repeated last
+	Reason string   `json:"reason,omitempty"` // e.g. "shortest path from ceo to intern has 4 hops"
+}
+
+// VerifyGraphClaim checks a claim about a graph given as edges, such as
+// "there is no cycle", "billing is reachable from auth in at most 3 hops"
+// or "alice reports to carol", as made when reasoning over org charts,
+// dependency graphs and knowledge graphs. Node names in the claim must
+// match those of the edges. Use GraphClaimEvidence to decode the path or
+// cycle that proves or refutes the claim.
+func (c *Client) VerifyGraphClaim(ctx context.Context, edges []Edge, claim string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return c.VerifyGraphClaimWithOptions(ctx, edges, claim, nil, callOpts...)
+}
+
+// VerifyGraphClaimWithOptions checks a claim about a graph with custom
+// options, such as an undirected graph.
+func (c *Client) VerifyGraphClaimWithOptions(ctx context.Context, edges []Edge, claim string, opts *GraphOptions, callOpts ...CallOption) (*VerificationResponse, error) {
+	var o GraphOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	req := map[string]interface{}{
+		"edges": edges,
+		"claim": claim,
+	}
+	if o.Undirected {
+		req["undirected"] = true
+	}
+
+	data, _ := json.Marshal(edges) // strings only; cannot fail
+	key := CacheKey(TypeGraph, contentHash(data), claim, fmt.Sprint(o.Undirected))
+	return c.verify(ctx, "VerifyGraphClaim", TypeGraph, key, req, callOpts...)
+}
+
+// GraphClaimEvidence extracts the evidence from a VerifyGraphClaim
+// response, or nil if there is none.
+func GraphClaimEvidence(resp *VerificationResponse) *GraphEvidence {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var evidence GraphEvidence
+	if !decodeResult(resp.Result["evidence"], &evidence) {
+		return nil
+	}
+	return &evidence
+}
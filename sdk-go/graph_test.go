@@ -0,0 +1,83 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+var orgChart = []Edge{
+	{From: "alice", To: "bob", Label: "manages"},
+	{From: "bob", To: "carol", Label: "manages"},
+	{From: "carol", To: "dave", Label: "manages"},
+}
+
+func TestVerifyGraphClaim(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/graph" {
+			t.Errorf("expected path /verify/graph, got %s", r.URL.Path)
+		}
+		var req struct {
+			Edges      []Edge `json:"edges"`
+			Claim      string `json:"claim"`
+			Undirected bool   `json:"undirected"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if len(req.Edges) != 3 || req.Edges[0].Label != "manages" || req.Claim == "" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		verified := req.Undirected
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   map[bool]string{true: "VERIFIED", false: "FAILED"}[verified],
+			"verified": verified,
+			"engine":   "graph",
+			"result": map[string]interface{}{
+				"evidence": map[string]interface{}{
+					"path":   []string{"dave", "carol", "bob", "alice"},
+					"reason": "shortest path from alice to dave has 3 hops",
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyGraphClaim(context.Background(), orgChart, "alice is reachable from dave in at most 3 hops")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Verified {
+		t.Error("expected the claim to fail on a directed graph")
+	}
+
+	evidence := GraphClaimEvidence(resp)
+	if evidence == nil || len(evidence.Path) != 4 || evidence.Reason == "" || evidence.Cycle != nil {
+		t.Errorf("unexpected evidence: %+v", evidence)
+	}
+
+	resp, err = client.VerifyGraphClaimWithOptions(context.Background(), orgChart, "alice is reachable from dave in at most 3 hops", &GraphOptions{Undirected: true})
+	if err != nil || !resp.Verified {
+		t.Errorf("expected the claim to hold on an undirected graph, got %+v, %v", resp, err)
+	}
+	if GraphClaimEvidence(&VerificationResponse{}) != nil {
+		t.Error("expected no evidence without a result")
+	}
+}
+
+func TestVerifyGraphClaimStrictValidation(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"), WithStrictValidation())
+	ctx := context.Background()
+
+	if _, err := client.VerifyGraphClaim(ctx, nil, "there is no cycle"); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest without edges, got %v", err)
+	}
+	if _, err := client.VerifyGraphClaim(ctx, []Edge{{From: "a"}}, "there is no cycle"); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for an edge without a node, got %v", err)
+	}
+	if _, err := client.VerifyGraphClaim(ctx, orgChart, " "); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest without a claim, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package qwed
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// GraphQL Verification
+// ============================================================================
+
+// Kinds of GraphQLError.
+const (
+	GraphQLSyntax            = "syntax"             // the query does not parse
+	GraphQLUnknownField      = "unknown_field"      // the field is not defined on its type
+	GraphQLInvalidArgument   = "invalid_argument"   // unknown, missing or mistyped argument
+	GraphQLDepthLimit        = "depth_limit"        // the selection nests deeper than MaxDepth
+	GraphQLUnauthorizedField = "unauthorized_field" // the field is in DeniedFields
+)
+
+// GraphQLOptions limits what a generated query may select.
+type GraphQLOptions struct {
+	// MaxDepth is the deepest selection allowed; the fields of the root
+	// operation are at depth 1. Zero means no limit.
+	MaxDepth int
+	// DeniedFields are fields the query must not select, as "Type.field",
+	// e.g. "User.passwordHash", or "Type.*" for every field of a type.
+	DeniedFields []string
+	// OperationName selects the operation to check in a document with
+	// several. Empty checks all of them.
+	OperationName string
+}
+
+// GraphQLError is a problem with one selection of a GraphQL query.
+type GraphQLError struct {
+	Kind    string `json:"kind"`
+	Path    string `json:"path,omitempty"`  // response path of the selection, e.g. "user.posts.author"
+	Field   string `json:"field,omitempty"` // schema coordinate, e.g. "Post.author"
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// VerifyGraphQL validates an LLM-generated GraphQL query against a schema
+// in SDL. A valid query is verified; otherwise the problems are in
+// Result["errors"], one per selection. Use GraphQLErrors to decode them.
+func (c *Client) VerifyGraphQL(ctx context.Context, query, schemaSDL string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return c.VerifyGraphQLWithOptions(ctx, query, schemaSDL, nil, callOpts...)
+}
+
+// VerifyGraphQLWithOptions validates a GraphQL query and also enforces a
+// depth limit and denied fields.
+func (c *Client) VerifyGraphQLWithOptions(ctx context.Context, query, schemaSDL string, opts *GraphQLOptions, callOpts ...CallOption) (*VerificationResponse, error) {
+	var o GraphQLOptions
+	if opts != nil {
+		o = *opts
+	}
+
+	req := map[string]interface{}{
+		"query":      query,
+		"schema_sdl": schemaSDL,
+	}
+	if o.MaxDepth > 0 {
+		req["max_depth"] = o.MaxDepth
+	}
+	if len(o.DeniedFields) > 0 {
+		req["denied_fields"] = o.DeniedFields
+	}
+	if o.OperationName != "" {
+		req["operation_name"] = o.OperationName
+	}
+
+	key := CacheKey(TypeGraphQL, schemaSDL, query, fmt.Sprint(o.MaxDepth), strings.Join(o.DeniedFields, ","), o.OperationName)
+	return c.verify(ctx, "VerifyGraphQL", TypeGraphQL, key, req, callOpts...)
+}
+
+// GraphQLErrors extracts the per-selection errors from a VerifyGraphQL
+// response.
+func GraphQLErrors(resp *VerificationResponse) []GraphQLError {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var errs []GraphQLError
+	decodeResult(resp.Result["errors"], &errs)
+	return errs
+}
@@ -0,0 +1,56 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyGraphQL(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/graphql" {
+			t.Errorf("expected path /verify/graphql, got %s", r.URL.Path)
+		}
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["schema_sdl"] == nil || req["max_depth"] != float64(3) {
+			t.Errorf("unexpected request: %v", req)
+		}
+		if denied, _ := req["denied_fields"].([]interface{}); len(denied) != 1 || denied[0] != "User.passwordHash" {
+			t.Errorf("expected denied fields, got %v", req["denied_fields"])
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": false,
+			"engine":   "graphql",
+			"result": map[string]interface{}{
+				"errors": []map[string]interface{}{
+					{"kind": "unauthorized_field", "path": "user.passwordHash", "field": "User.passwordHash", "message": "field User.passwordHash is not allowed", "line": 1, "column": 20},
+					{"kind": "unknown_field", "path": "user.avatar", "field": "User.avatar", "message": "field avatar is not defined on type User", "line": 1, "column": 38},
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyGraphQLWithOptions(context.Background(),
+		"{ user(id: 1) { passwordHash avatar } }", "type Query { user(id: ID!): User } type User { id: ID! passwordHash: String }",
+		&GraphQLOptions{MaxDepth: 3, DeniedFields: []string{"User.passwordHash"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Verified {
+		t.Error("expected invalid query to fail")
+	}
+
+	errs := GraphQLErrors(resp)
+	if len(errs) != 2 || errs[0].Kind != GraphQLUnauthorizedField || errs[1].Kind != GraphQLUnknownField {
+		t.Fatalf("unexpected errors: %+v", errs)
+	}
+	if errs[1].Path != "user.avatar" || errs[1].Column != 38 {
+		t.Errorf("unexpected selection: %+v", errs[1])
+	}
+}
@@ -0,0 +1,203 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Check Groups
+// ============================================================================
+
+// GroupResult is the outcome of one check in a Group.
+type GroupResult struct {
+	Index    int              // position in the order the check was added
+	Type     VerificationType // engine, or the label passed to Go
+	Input    string
+	Response *VerificationResponse
+	Err      error
+}
+
+// GroupOption configures a Group.
+type GroupOption func(*Group)
+
+// WithGroupTimeout sets a deadline shared by all checks in the group,
+// measured from NewGroup.
+func WithGroupTimeout(d time.Duration) GroupOption {
+	return func(g *Group) {
+		g.timeout = d
+	}
+}
+
+// WithGroupConcurrency limits how many checks run at once. The default is
+// unlimited.
+func WithGroupConcurrency(n int) GroupOption {
+	return func(g *Group) {
+		if n > 0 {
+			g.sem = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithGroupFailFast cancels the remaining checks as soon as one returns an
+// error. Checks that complete with a failed verification are not errors.
+func WithGroupFailFast() GroupOption {
+	return func(g *Group) {
+		g.failFast = true
+	}
+}
+
+// Group runs heterogeneous verification checks concurrently under one
+// context and collects their results:
+//
+//	g := qwed.NewGroup(ctx, client, qwed.WithGroupTimeout(5*time.Second))
+//	g.Math(expr)
+//	g.Code(code, "go")
+//	g.SQL(query, ddl, "mysql")
+//	results, err := g.Wait()
+//
+// Each check starts when it is added. A Group must not be reused after
+// Wait.
+type Group struct {
+	v        Verifier
+	ctx      context.Context
+	cancel   context.CancelFunc
+	timeout  time.Duration
+	failFast bool
+	sem      chan struct{}
+
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	results []GroupResult
+}
+
+// NewGroup creates a group whose checks run through v and are cancelled
+// when ctx is done.
+func NewGroup(ctx context.Context, v Verifier, opts ...GroupOption) *Group {
+	g := &Group{v: v}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.timeout > 0 {
+		g.ctx, g.cancel = context.WithTimeout(ctx, g.timeout)
+	} else {
+		g.ctx, g.cancel = context.WithCancel(ctx)
+	}
+	return g
+}
+
+// Verify adds a natural-language check and returns its index.
+func (g *Group) Verify(query string) int {
+	return g.Go(TypeNaturalLanguage, query, func(ctx context.Context) (*VerificationResponse, error) {
+		return g.v.Verify(ctx, query)
+	})
+}
+
+// Math adds a math check and returns its index.
+func (g *Group) Math(expression string) int {
+	return g.Go(TypeMath, expression, func(ctx context.Context) (*VerificationResponse, error) {
+		return g.v.VerifyMath(ctx, expression)
+	})
+}
+
+// Logic adds a logic check and returns its index.
+func (g *Group) Logic(query string) int {
+	return g.Go(TypeLogic, query, func(ctx context.Context) (*VerificationResponse, error) {
+		return g.v.VerifyLogic(ctx, query)
+	})
+}
+
+// Code adds a code check and returns its index.
+func (g *Group) Code(code, language string) int {
+	return g.Go(TypeCode, code, func(ctx context.Context) (*VerificationResponse, error) {
+		return g.v.VerifyCode(ctx, code, language)
+	})
+}
+
+// Fact adds a fact check and returns its index.
+func (g *Group) Fact(claim, factContext string) int {
+	return g.Go(TypeFact, claim, func(ctx context.Context) (*VerificationResponse, error) {
+		return g.v.VerifyFact(ctx, claim, factContext)
+	})
+}
+
+// SQL adds a SQL check and returns its index.
+func (g *Group) SQL(query, schemaDDL, dialect string) int {
+	return g.Go(TypeSQL, query, func(ctx context.Context) (*VerificationResponse, error) {
+		return g.v.VerifySQL(ctx, query, schemaDDL, dialect)
+	})
+}
+
+// Go adds a custom check, for example a Client method outside the Verifier
+// interface such as VerifyJSON or VerifyUnits, and returns its index. fn
+// receives the group's context.
+func (g *Group) Go(typ VerificationType, input string, fn func(ctx context.Context) (*VerificationResponse, error)) int {
+	g.mu.Lock()
+	index := len(g.results)
+	g.results = append(g.results, GroupResult{Index: index, Type: typ, Input: input})
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+
+		resp, err := g.run(fn)
+		if err != nil && g.failFast {
+			g.cancel()
+		}
+
+		g.mu.Lock()
+		g.results[index].Response = resp
+		g.results[index].Err = err
+		g.mu.Unlock()
+	}()
+	return index
+}
+
+// run calls fn once a concurrency slot is free.
+func (g *Group) run(fn func(ctx context.Context) (*VerificationResponse, error)) (*VerificationResponse, error) {
+	if g.sem != nil {
+		select {
+		case g.sem <- struct{}{}:
+			defer func() { <-g.sem }()
+		case <-g.ctx.Done():
+			return nil, g.ctx.Err()
+		}
+	}
+	if err := g.ctx.Err(); err != nil {
+		return nil, err
+	}
+	return fn(g.ctx)
+}
+
+// Wait waits for all checks and returns their results in the order they
+// were added. The error joins the error of every check that failed, each
+// prefixed with its engine and index; results are returned either way.
+func (g *Group) Wait() ([]GroupResult, error) {
+	g.wg.Wait()
+	g.cancel()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var errs []error
+	for _, result := range g.results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("%s check %d: %w", result.Type, result.Index, result.Err))
+		}
+	}
+	return g.results, errors.Join(errs...)
+}
+
+// AllVerified reports whether every check completed without error and
+// verified.
+func AllVerified(results []GroupResult) bool {
+	for _, result := range results {
+		if result.Err != nil || !IsVerified(result.Response) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	var inflight, peak int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inflight, 1)
+		defer atomic.AddInt32(&inflight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+
+		if r.URL.Path == "/verify/code" {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": ErrorInfo{Code: "ENGINE_ERROR", Message: "boom"}})
+			return
+		}
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true, Engine: Engine(strings.TrimPrefix(r.URL.Path, "/verify/"))})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	g := NewGroup(context.Background(), client, WithGroupConcurrency(2))
+	g.Math("2 + 2 = 4")
+	code := g.Code("package main", "go")
+	g.SQL("SELECT 1", "", "mysql")
+	g.Go(TypeUnits, "1 km = 1000 m", func(ctx context.Context) (*VerificationResponse, error) {
+		return client.VerifyUnits(ctx, "1 km = 1000 m")
+	})
+
+	results, err := g.Wait()
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	if err == nil || !strings.Contains(err.Error(), "code check 1") {
+		t.Errorf("expected the code check's error, got %v", err)
+	}
+	var apiErr *QWEDError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected the joined error to wrap a QWEDError, got %v", err)
+	}
+	if results[code].Err == nil {
+		t.Error("expected the code check to record its error")
+	}
+	for _, i := range []int{0, 2, 3} {
+		if results[i].Err != nil || !IsVerified(results[i].Response) {
+			t.Errorf("result %d: expected verified, got %+v", i, results[i])
+		}
+	}
+	if results[2].Type != TypeSQL || results[2].Input != "SELECT 1" {
+		t.Errorf("unexpected result: %+v", results[2])
+	}
+	if AllVerified(results) {
+		t.Error("expected AllVerified to be false")
+	}
+	if peak > 2 {
+		t.Errorf("expected at most 2 concurrent requests, got %d", peak)
+	}
+}
+
+func TestGroupTimeout(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(300 * time.Millisecond):
+		}
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	g := NewGroup(context.Background(), client, WithGroupTimeout(50*time.Millisecond))
+	g.Math("1 + 1 = 2")
+	g.Logic("A or not A")
+
+	start := time.Now()
+	results, err := g.Wait()
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("expected the shared deadline to stop both checks, took %v", elapsed)
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+	for _, result := range results {
+		if result.Err == nil {
+			t.Errorf("expected %s check to fail", result.Type)
+		}
+	}
+}
+
+func TestGroupFailFast(t *testing.T) {
+	g := NewGroup(context.Background(), NewClient("test-key"), WithGroupFailFast())
+	g.Go(TypeMath, "fails", func(ctx context.Context) (*VerificationResponse, error) {
+		return nil, errors.New("boom")
+	})
+	g.Go(TypeLogic, "waits", func(ctx context.Context) (*VerificationResponse, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+			return &VerificationResponse{Verified: true}, nil
+		}
+	})
+
+	results, err := g.Wait()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !errors.Is(results[1].Err, context.Canceled) {
+		t.Errorf("expected the second check to be cancelled, got %v", results[1].Err)
+	}
+}
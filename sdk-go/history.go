@@ -0,0 +1,223 @@
+package qwed
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// HistoryEntry is a single record from the account's verification history.
+type HistoryEntry struct {
+	ID        int64  `json:"id"`
+	Query     string `json:"query"`
+	Verified  bool   `json:"is_verified"`
+	Domain    string `json:"domain"`
+	Timestamp string `json:"timestamp"`
+}
+
+// at parses the entry's timestamp. The API sends RFC 3339 timestamps, or
+// ISO 8601 ones without a zone, which are in UTC.
+func (e HistoryEntry) at() (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, e.Timestamp); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05.999999999", e.Timestamp, time.UTC)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse history timestamp %q: %w", e.Timestamp, err)
+	}
+	return t, nil
+}
+
+// HistoryFilter restricts which history entries are exported.
+// Zero-valued fields do not filter.
+type HistoryFilter struct {
+	Domain   string    // engine/domain name, e.g. "math"
+	Verified *bool     // only verified (true) or unverified (false) entries
+	Since    time.Time // entries at or after this time
+	Until    time.Time // entries before this time
+	Limit    int       // maximum number of entries to export
+}
+
+// ExportFormat selects the encoding used by ExportHistory.
+type ExportFormat string
+
+const (
+	ExportJSONL ExportFormat = "jsonl"
+	ExportCSV   ExportFormat = "csv"
+)
+
+const (
+	historyPageSize = 100
+	historyRetries  = 3
+)
+
+// ============================================================================
+// Export
+// ============================================================================
+
+// ExportHistory streams the account's verification history matching filter
+// to w, fetching it page by page. Transient failures while fetching a page
+// are retried. It returns the number of entries written.
+func (c *Client) ExportHistory(ctx context.Context, filter HistoryFilter, format ExportFormat, w io.Writer) (int, error) {
+	var write func(HistoryEntry) error
+	var flush func() error
+
+	switch format {
+	case ExportJSONL, "":
+		enc := json.NewEncoder(w)
+		write = func(e HistoryEntry) error { return enc.Encode(e) }
+		flush = func() error { return nil }
+	case ExportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"id", "query", "verified", "domain", "timestamp"}); err != nil {
+			return 0, err
+		}
+		write = func(e HistoryEntry) error {
+			return cw.Write([]string{
+				strconv.FormatInt(e.ID, 10),
+				e.Query,
+				strconv.FormatBool(e.Verified),
+				e.Domain,
+				e.Timestamp,
+			})
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	default:
+		return 0, fmt.Errorf("unsupported export format %q", format)
+	}
+
+	written := 0
+	err := c.eachHistory(ctx, filter, func(entry HistoryEntry) (bool, error) {
+		if err := write(entry); err != nil {
+			return false, fmt.Errorf("failed to write history: %w", err)
+		}
+		written++
+		return filter.Limit <= 0 || written < filter.Limit, nil
+	})
+	if err != nil {
+		flush()
+		return written, err
+	}
+	return written, flush()
+}
+
+// eachHistory calls fn with each history entry matching filter, fetching
+// the history page by page, until fn returns false or an error. The filter
+// is re-applied locally, and paging stops at the first page with no entries
+// not already seen, so servers that ignore the offset or filter parameters
+// and return the same newest entries on every page are handled.
+func (c *Client) eachHistory(ctx context.Context, filter HistoryFilter, fn func(HistoryEntry) (bool, error)) error {
+	seen := make(map[HistoryEntry]bool)
+	for offset := 0; ; offset += historyPageSize {
+		page, err := c.historyPage(ctx, filter, offset)
+		if err != nil {
+			return err
+		}
+
+		fresh := 0
+		for _, entry := range page {
+			if seen[entry] {
+				continue
+			}
+			seen[entry] = true
+			fresh++
+			if !filter.matches(entry) {
+				continue
+			}
+			more, err := fn(entry)
+			if err != nil || !more {
+				return err
+			}
+		}
+
+		if len(page) < historyPageSize || fresh == 0 {
+			return nil
+		}
+	}
+}
+
+// historyPage fetches one page of logs, retrying transient failures.
+func (c *Client) historyPage(ctx context.Context, filter HistoryFilter, offset int) ([]HistoryEntry, error) {
+	path := "/logs?" + filter.query(offset).Encode()
+
+	var err error
+	for attempt := 0; attempt < historyRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(100<<attempt) * time.Millisecond):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		var resp struct {
+			Logs []HistoryEntry `json:"logs"`
+		}
+		err = c.request(ctx, "GET", path, nil, &resp)
+		if err == nil {
+			return resp.Logs, nil
+		}
+		if !transient(ctx, err) {
+			break
+		}
+		if attempt+1 < historyRetries {
+			c.log(ctx, slog.LevelInfo, "qwed retrying request", slog.String("path", path), slog.Int("attempt", attempt+2), slog.Any("error", err))
+		}
+	}
+	return nil, fmt.Errorf("failed to fetch history at offset %d: %w", offset, err)
+}
+
+func (f HistoryFilter) query(offset int) url.Values {
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(historyPageSize))
+	q.Set("offset", strconv.Itoa(offset))
+	if f.Domain != "" {
+		q.Set("domain", f.Domain)
+	}
+	if f.Verified != nil {
+		q.Set("is_verified", strconv.FormatBool(*f.Verified))
+	}
+	if !f.Since.IsZero() {
+		q.Set("since", f.Since.UTC().Format(time.RFC3339))
+	}
+	if !f.Until.IsZero() {
+		q.Set("until", f.Until.UTC().Format(time.RFC3339))
+	}
+	return q
+}
+
+// matches re-applies the filter locally in case the server ignores it.
+// Entries whose timestamp does not parse are excluded by a time range.
+func (f HistoryFilter) matches(e HistoryEntry) bool {
+	if f.Domain != "" && e.Domain != f.Domain {
+		return false
+	}
+	if f.Verified != nil && e.Verified != *f.Verified {
+		return false
+	}
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		at, err := e.at()
+		if err != nil || (!f.Since.IsZero() && at.Before(f.Since)) || (!f.Until.IsZero() && !at.Before(f.Until)) {
+			return false
+		}
+	}
+	return true
+}
+
+// transient reports whether err is worth retrying.
+func transient(ctx context.Context, err error) bool {
+	return ctx.Err() == nil && IsRetryable(err)
+}
@@ -0,0 +1,139 @@
+package qwed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func historyServer(t *testing.T, total int, failFirst bool) (*Client, func()) {
+	failed := false
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/logs" {
+			t.Errorf("expected path /logs, got %s", r.URL.Path)
+		}
+		if failFirst && !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var logs []HistoryEntry
+		for i := offset; i < total && i < offset+limit; i++ {
+			logs = append(logs, HistoryEntry{
+				ID:       int64(i + 1),
+				Query:    "q" + strconv.Itoa(i),
+				Verified: i%2 == 0,
+				Domain:   "math",
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"logs": logs})
+	})
+
+	return NewClient("test-key", WithBaseURL(server.URL)), server.Close
+}
+
+func TestExportHistoryJSONL(t *testing.T) {
+	client, done := historyServer(t, 250, true)
+	defer done()
+
+	var buf bytes.Buffer
+	n, err := client.ExportHistory(context.Background(), HistoryFilter{}, ExportJSONL, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n != 250 {
+		t.Errorf("expected 250 entries, got %d", n)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 250 {
+		t.Errorf("expected 250 lines, got %d", lines)
+	}
+}
+
+func TestExportHistoryCSVFiltered(t *testing.T) {
+	client, done := historyServer(t, 20, false)
+	defer done()
+
+	verified := true
+	var buf bytes.Buffer
+	n, err := client.ExportHistory(context.Background(),
+		HistoryFilter{Verified: &verified, Limit: 5}, ExportCSV, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n != 5 {
+		t.Errorf("expected 5 entries, got %d", n)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "id,query,verified,domain,timestamp" {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if len(lines) != 6 || !strings.HasPrefix(lines[1], "1,q0,true") {
+		t.Errorf("unexpected CSV output:\n%s", buf.String())
+	}
+}
+
+func TestExportHistoryBadFormat(t *testing.T) {
+	client := NewClient("test-key")
+	if _, err := client.ExportHistory(context.Background(), HistoryFilter{}, "xml", &bytes.Buffer{}); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestExportHistoryServerIgnoringOffset(t *testing.T) {
+	// Like the API's /logs, the server ignores offset and filters and
+	// always returns the newest entries.
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	requests := 0
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if requests++; requests > 10 {
+			t.Error("expected paging to stop")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var logs []HistoryEntry
+		for i := 0; i < historyPageSize; i++ {
+			logs = append(logs, HistoryEntry{
+				ID:        int64(historyPageSize - i),
+				Query:     "q" + strconv.Itoa(i),
+				Domain:    "math",
+				Timestamp: base.Add(-time.Duration(i) * time.Hour).Format("2006-01-02T15:04:05.000000"),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"logs": logs})
+	})
+	defer server.Close()
+	client := NewClient("test-key", WithBaseURL(server.URL))
+
+	var buf bytes.Buffer
+	n, err := client.ExportHistory(context.Background(), HistoryFilter{}, ExportJSONL, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != historyPageSize {
+		t.Errorf("expected %d entries without duplicates, got %d", historyPageSize, n)
+	}
+
+	buf.Reset()
+	filter := HistoryFilter{Since: base.Add(-10 * time.Hour), Until: base.Add(-5 * time.Hour)}
+	n, err = client.ExportHistory(context.Background(), filter, ExportCSV, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 5 {
+		t.Errorf("expected 5 entries in range, got %d:\n%s", n, buf.String())
+	}
+	if !strings.Contains(buf.String(), ",q10,") || strings.Contains(buf.String(), ",q5,") {
+		t.Errorf("expected entries 6-10 hours old, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,140 @@
+package qwed
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Hooks
+// ============================================================================
+
+// Hooks observe the HTTP exchanges with the API. Unlike interceptors, which
+// wrap verification calls, hooks see every request the client sends,
+// including failover retries, batch jobs and health checks, with the raw
+// JSON bodies. Either field may be nil.
+type Hooks struct {
+	// BeforeRequest is called just before req is sent, with its body.
+	// It may add headers to req but must not modify body.
+	BeforeRequest func(req *http.Request, body []byte)
+
+	// AfterResponse is called once the response body has been read, or with
+	// a nil resp and the error if the request failed. The response body has
+	// already been closed; body holds its contents.
+	AfterResponse func(req *http.Request, resp *http.Response, body []byte, err error)
+}
+
+// WithHooks adds hooks around every HTTP request. Hooks run in the order
+// they are added.
+func WithHooks(h Hooks) ClientOption {
+	return func(c *Client) {
+		c.hooks = append(c.hooks, h)
+	}
+}
+
+// beforeRequest runs the BeforeRequest hooks.
+func (c *Client) beforeRequest(req *http.Request, body []byte) {
+	for _, h := range c.hooks {
+		if h.BeforeRequest != nil {
+			h.BeforeRequest(req, body)
+		}
+	}
+}
+
+// afterResponse runs the AfterResponse hooks.
+func (c *Client) afterResponse(req *http.Request, resp *http.Response, body []byte, err error) {
+	for _, h := range c.hooks {
+		if h.AfterResponse != nil {
+			h.AfterResponse(req, resp, body, err)
+		}
+	}
+}
+
+// ============================================================================
+// Debug Logger
+// ============================================================================
+
+// redacted replaces secrets in debug logs.
+const redacted = "[REDACTED]"
+
+// DebugLogger returns hooks that log every request and response, with
+// headers and bodies, using logf, for example log.Printf. The API key is
+// always redacted, as are JSON body fields named in redactFields at any
+// depth (matched case-insensitively):
+//
+//	client := qwed.NewClient(apiKey, qwed.WithHooks(qwed.DebugLogger(log.Printf, "context", "code")))
+func DebugLogger(logf func(format string, args ...interface{}), redactFields ...string) Hooks {
+	fields := make(map[string]bool, len(redactFields))
+	for _, f := range redactFields {
+		fields[strings.ToLower(f)] = true
+	}
+	var started sync.Map // *http.Request -> time.Time
+
+	return Hooks{
+		BeforeRequest: func(req *http.Request, body []byte) {
+			started.Store(req, time.Now())
+			logf("qwed: --> %s %s headers=%s body=%s", req.Method, req.URL, redactHeaders(req.Header), redactBody(body, fields))
+		},
+		AfterResponse: func(req *http.Request, resp *http.Response, body []byte, err error) {
+			var elapsed time.Duration
+			if start, ok := started.LoadAndDelete(req); ok {
+				elapsed = time.Since(start.(time.Time)).Round(time.Microsecond)
+			}
+			if resp == nil {
+				logf("qwed: <-- %s %s latency=%s error=%v", req.Method, req.URL, elapsed, err)
+				return
+			}
+			logf("qwed: <-- %d %s %s latency=%s headers=%s body=%s", resp.StatusCode, req.Method, req.URL, elapsed, redactHeaders(resp.Header), redactBody(body, fields))
+		},
+	}
+}
+
+// redactHeaders formats headers for logging with credentials redacted.
+func redactHeaders(h http.Header) string {
+	h = h.Clone()
+	for _, name := range []string{"X-API-Key", "Authorization", "Cookie", "Set-Cookie"} {
+		if h.Get(name) != "" {
+			h.Set(name, redacted)
+		}
+	}
+	data, _ := json.Marshal(h)
+	return string(data)
+}
+
+// redactBody returns body with the named JSON fields redacted. Bodies that
+// are not JSON are returned as they are.
+func redactBody(body []byte, fields map[string]bool) string {
+	if len(fields) == 0 || len(body) == 0 {
+		return string(body)
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+	data, err := json.Marshal(redactValue(v, fields))
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
+
+func redactValue(v interface{}, fields map[string]bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			if fields[strings.ToLower(k)] {
+				v[k] = redacted
+			} else {
+				v[k] = redactValue(val, fields)
+			}
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = redactValue(val, fields)
+		}
+	}
+	return v
+}
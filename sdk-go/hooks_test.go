@@ -0,0 +1,92 @@
+package qwed
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Trace") != "t-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	})
+	defer server.Close()
+
+	var events []string
+	client := NewClient("test-key", WithBaseURL(server.URL),
+		WithHooks(Hooks{
+			BeforeRequest: func(req *http.Request, body []byte) {
+				req.Header.Set("X-Trace", "t-1")
+				events = append(events, "before "+req.URL.Path+" "+string(body))
+			},
+			AfterResponse: func(req *http.Request, resp *http.Response, body []byte, err error) {
+				events = append(events, fmt.Sprintf("after %d %s", resp.StatusCode, body))
+			},
+		}),
+		WithHooks(Hooks{AfterResponse: func(req *http.Request, resp *http.Response, body []byte, err error) {
+			events = append(events, "second")
+		}}))
+
+	if _, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil {
+		t.Fatalf("VerifyMath() error = %v", err)
+	}
+	want := []string{
+		`before /verify/math {"expression":"2+2=4"}`,
+		`after 200 {"status":"VERIFIED","verified":true}`,
+		"second",
+	}
+	if strings.Join(events, "\n") != strings.Join(want, "\n") {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+}
+
+func TestHooksTransportError(t *testing.T) {
+	var gotErr error
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"), WithHooks(Hooks{
+		AfterResponse: func(req *http.Request, resp *http.Response, body []byte, err error) {
+			if resp != nil {
+				t.Error("expected nil response")
+			}
+			gotErr = err
+		},
+	}))
+	if _, err := client.VerifyMath(context.Background(), "2+2=4"); err == nil {
+		t.Fatal("expected error")
+	}
+	if gotErr == nil {
+		t.Error("AfterResponse did not receive the error")
+	}
+}
+
+func TestDebugLogger(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"VERIFIED","verified":true,"result":{"context":"secret source"}}`))
+	})
+	defer server.Close()
+
+	var lines []string
+	logf := func(format string, args ...interface{}) { lines = append(lines, fmt.Sprintf(format, args...)) }
+	client := NewClient("super-secret-key", WithBaseURL(server.URL), WithHooks(DebugLogger(logf, "Context")))
+
+	if _, err := client.VerifyFact(context.Background(), "Paris is in France", "secret source"); err != nil {
+		t.Fatalf("VerifyFact() error = %v", err)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("logged %d lines, want 2: %q", len(lines), lines)
+	}
+	out := strings.Join(lines, "\n")
+	if strings.Contains(out, "super-secret-key") || strings.Contains(out, "secret source") {
+		t.Errorf("secrets not redacted:\n%s", out)
+	}
+	for _, want := range []string{"--> POST " + server.URL + "/verify/fact", "Paris is in France", "<-- 200 POST", "latency=", redacted} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log missing %q:\n%s", want, out)
+		}
+	}
+}
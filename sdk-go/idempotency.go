@@ -0,0 +1,89 @@
+package qwed
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ============================================================================
+// Idempotency Keys
+// ============================================================================
+
+// HeaderIdempotencyKey carries a call's idempotency key, so the server can
+// recognize a retried request and return the original result instead of
+// verifying, and billing, it again.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// WithIdempotencyKeys gives every verification call an idempotency key
+// unless one is set with WithIdempotencyKey. Single verifications get a
+// random UUID, which interceptors retrying the call reuse. Batches get a key
+// derived from their items and options, so resubmitting the same batch is
+// deduplicated by the server. The key is reported in
+// ResponseMetadata.IdempotencyKey and BatchResponse.IdempotencyKey.
+func WithIdempotencyKeys() ClientOption {
+	return func(c *Client) {
+		c.idempotency = true
+	}
+}
+
+type idempotencyKeyKey struct{}
+
+// IdempotencyKeyFromContext returns the idempotency key of the call in
+// progress, for interceptors that log or retry calls. It returns "" outside
+// a call or if the call has no key.
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyKey{}).(string)
+	return key
+}
+
+// setIdempotencyHeader adds HeaderIdempotencyKey to h if ctx carries a key.
+func setIdempotencyHeader(ctx context.Context, h http.Header) {
+	if key := IdempotencyKeyFromContext(ctx); key != "" {
+		h.Set(HeaderIdempotencyKey, key)
+	}
+}
+
+// newIdempotencyKey returns a random version 4 UUID.
+func newIdempotencyKey() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand does not fail on supported platforms; a missing key
+		// only loses deduplication.
+		return ""
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// batchIdempotencyKey derives a key from a batch request body, so the same
+// batch submitted again gets the same key.
+func batchIdempotencyKey(body interface{}) string {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return "batch-" + hex.EncodeToString(sum[:16])
+}
+
+// withIdempotencyKey returns a copy of resp reporting key. Responses may be
+// shared through the cache and are not modified.
+func withIdempotencyKey(resp *VerificationResponse, key string) *VerificationResponse {
+	if resp == nil || key == "" {
+		return resp
+	}
+	out := *resp
+	var meta ResponseMetadata
+	if resp.Metadata != nil {
+		meta = *resp.Metadata
+	}
+	meta.IdempotencyKey = key
+	out.Metadata = &meta
+	return &out
+}
@@ -0,0 +1,99 @@
+package qwed
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sync"
+	"testing"
+)
+
+func TestWithIdempotencyKeys(t *testing.T) {
+	var mu sync.Mutex
+	var keys []string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(HeaderIdempotencyKey))
+		mu.Unlock()
+		w.Write([]byte(`{"status":"VERIFIED","verified":true,"metadata":{"request_id":"req-1"},"job_id":"job-1"}`))
+	})
+	defer server.Close()
+
+	// A retrying interceptor resends the call with the same key.
+	retry := func(ctx context.Context, req *Request, next Invoker) (*VerificationResponse, error) {
+		next(ctx, req)
+		return next(ctx, req)
+	}
+	client := NewClient("test-key", WithBaseURL(server.URL), WithIdempotencyKeys(), WithInterceptor(retry))
+	ctx := context.Background()
+
+	resp, err := client.VerifyMath(ctx, "2+2=4")
+	if err != nil {
+		t.Fatalf("VerifyMath() error = %v", err)
+	}
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	if len(keys) != 2 || !uuid.MatchString(keys[0]) || keys[0] != keys[1] {
+		t.Fatalf("keys = %q, want the same UUID on both attempts", keys)
+	}
+	if resp.Metadata.IdempotencyKey != keys[0] || resp.Metadata.RequestID != "req-1" {
+		t.Errorf("metadata = %+v, want the key alongside the server metadata", resp.Metadata)
+	}
+
+	client.VerifyMath(ctx, "2+2=4")
+	if keys[2] == keys[0] {
+		t.Error("separate calls share an idempotency key")
+	}
+
+	resp, _ = client.VerifyMath(ctx, "2+2=4", WithIdempotencyKey("mine"))
+	if keys[4] != "mine" || resp.Metadata.IdempotencyKey != "mine" {
+		t.Errorf("explicit key = %q, response key = %q, want mine", keys[4], resp.Metadata.IdempotencyKey)
+	}
+}
+
+func TestBatchIdempotencyKeys(t *testing.T) {
+	var keys []string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(HeaderIdempotencyKey))
+		w.Write([]byte(`{"job_id":"job-1","status":"completed"}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithIdempotencyKeys())
+	ctx := context.Background()
+	items := []BatchItem{{Query: "2+2=4", Type: TypeMath}}
+
+	first, err := client.VerifyBatch(ctx, items, nil)
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+	client.VerifyBatch(ctx, items, nil)
+	client.VerifyBatch(ctx, []BatchItem{{Query: "3+3=6", Type: TypeMath}}, nil)
+
+	if keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("keys = %q, want resubmissions to share a key", keys)
+	}
+	if keys[2] == keys[0] {
+		t.Error("different batches share a key")
+	}
+	if first.IdempotencyKey != keys[0] {
+		t.Errorf("IdempotencyKey = %q, want %q", first.IdempotencyKey, keys[0])
+	}
+}
+
+func TestIdempotencyKeysDisabledByDefault(t *testing.T) {
+	var key string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		key = r.Header.Get(HeaderIdempotencyKey)
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyMath(context.Background(), "2+2=4")
+	if err != nil {
+		t.Fatalf("VerifyMath() error = %v", err)
+	}
+	if key != "" || resp.Metadata != nil {
+		t.Errorf("key = %q, metadata = %+v, want none", key, resp.Metadata)
+	}
+}
@@ -0,0 +1,74 @@
+package qwed
+
+import (
+	"context"
+	"sort"
+)
+
+// ============================================================================
+// Infrastructure as Code Verification
+// ============================================================================
+
+// InfraKind is the format of an infrastructure definition.
+type InfraKind string
+
+const (
+	InfraDockerfile InfraKind = "dockerfile"
+	InfraTerraform  InfraKind = "terraform"
+	InfraKubernetes InfraKind = "kubernetes" // YAML manifests, multi-document allowed
+)
+
+// InfraViolation is a misconfiguration found in an infrastructure
+// definition.
+type InfraViolation struct {
+	Severity       string `json:"severity"`
+	Rule           string `json:"rule"`               // e.g. "privileged_container", "open_security_group", "latest_tag"
+	Resource       string `json:"resource,omitempty"` // e.g. "aws_security_group.web" or "Deployment/api"
+	Description    string `json:"description,omitempty"`
+	Line           int    `json:"line_number,omitempty"`
+	Recommendation string `json:"recommendation,omitempty"`
+}
+
+// VerifyInfra checks a Dockerfile, Terraform configuration or Kubernetes
+// manifest for misconfigurations such as privileged containers, security
+// groups open to the internet and unpinned "latest" image tags. Content
+// without violations is verified; otherwise the violations are in
+// Result["violations"]. Use InfraViolations to decode them.
+func (c *Client) VerifyInfra(ctx context.Context, content string, kind InfraKind, callOpts ...CallOption) (*VerificationResponse, error) {
+	return c.VerifyInfraWithOptions(ctx, content, kind, nil, callOpts...)
+}
+
+// VerifyInfraWithOptions checks an infrastructure definition with custom
+// options. A policy's FailOnSeverity applies to its violations as it does
+// to code findings.
+func (c *Client) VerifyInfraWithOptions(ctx context.Context, content string, kind InfraKind, opts *RequestOptions, callOpts ...CallOption) (*VerificationResponse, error) {
+	req := map[string]interface{}{
+		"content": content,
+		"kind":    kind,
+	}
+
+	if opts = c.requestOptions(opts); opts != nil {
+		req["options"] = opts
+	}
+
+	return c.verify(ctx, "VerifyInfra", TypeInfra, CacheKey(TypeInfra, string(kind), content, optionsKey(opts)), req, callOpts...)
+}
+
+// InfraViolations extracts the violations from a VerifyInfra response,
+// most severe first and then in line order.
+func InfraViolations(resp *VerificationResponse) []InfraViolation {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var violations []InfraViolation
+	decodeResult(resp.Result["violations"], &violations)
+	sort.SliceStable(violations, func(i, j int) bool {
+		ri, rj := severityRank(violations[i].Severity), severityRank(violations[j].Severity)
+		if ri != rj {
+			return ri > rj
+		}
+		return violations[i].Line < violations[j].Line
+	})
+	return violations
+}
@@ -0,0 +1,68 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyInfra(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/infra" {
+			t.Errorf("expected path /verify/infra, got %s", r.URL.Path)
+		}
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["kind"] != "dockerfile" {
+			t.Errorf("expected kind dockerfile, got %q", req["kind"])
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": false,
+			"engine":   "infra",
+			"result": map[string]interface{}{
+				"violations": []map[string]interface{}{
+					{"severity": "WARNING", "rule": "latest_tag", "description": "image tag is not pinned", "line_number": 1},
+					{"severity": "CRITICAL", "rule": "root_user", "description": "container runs as root", "line_number": 3},
+					{"severity": "WARNING", "rule": "add_instead_of_copy", "line_number": 2},
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyInfra(context.Background(), "FROM python:latest\nADD . /app\nUSER root\n", InfraDockerfile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Verified {
+		t.Error("expected misconfigured Dockerfile to fail")
+	}
+
+	violations := InfraViolations(resp)
+	if len(violations) != 3 {
+		t.Fatalf("expected 3 violations, got %+v", violations)
+	}
+	if violations[0].Rule != "root_user" || violations[1].Rule != "latest_tag" || violations[2].Rule != "add_instead_of_copy" {
+		t.Errorf("expected violations by severity then line, got %+v", violations)
+	}
+}
+
+func TestVerifyInfraPolicySeverity(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"VERIFIED","verified":true,"engine":"infra","result":{"violations":[{"severity":"WARNING","rule":"latest_tag","line_number":4}]}}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithPolicy(Policy{Name: "strict", FailOnSeverity: SeverityWarning}))
+	resp, err := client.VerifyInfra(context.Background(), "image: nginx:latest", InfraKubernetes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Verified || resp.Result["policy"] != "strict" {
+		t.Errorf("expected policy to fail the warning, got %+v", resp)
+	}
+}
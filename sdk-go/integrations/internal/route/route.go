@@ -0,0 +1,123 @@
+// Package route sends LLM output to the QWED engine matching each part of
+// it. It is shared by the framework integrations.
+package route
+
+import (
+	"context"
+	"strings"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// ============================================================================
+// Routing
+// ============================================================================
+
+// Check is the verification of one part of an LLM output.
+type Check struct {
+	Engine   qwed.VerificationType
+	Input    string // the code block or claim sent to the engine
+	Response *qwed.VerificationResponse
+	Err      error
+}
+
+// Verify routes text to the matching engines: fenced code blocks with a
+// language to the code engine, arithmetic claims to the math engine, and
+// other claims to the fact engine if factContext is not empty. Engine errors
+// are recorded on the check; Verify only returns an error if ctx is done.
+func Verify(ctx context.Context, v qwed.Verifier, text, factContext string) ([]Check, error) {
+	prose, blocks := splitCodeBlocks(text)
+
+	var checks []Check
+	for _, block := range blocks {
+		if block.lang == "" {
+			continue
+		}
+		resp, err := v.VerifyCode(ctx, block.code, block.lang)
+		checks = append(checks, Check{Engine: qwed.TypeCode, Input: block.code, Response: resp, Err: err})
+	}
+
+	claims, err := qwed.DecomposeClaims(ctx, prose)
+	if err != nil {
+		return nil, err
+	}
+	for _, claim := range claims {
+		check := Check{Engine: claim.Type, Input: claim.Text}
+		switch {
+		case claim.Type == qwed.TypeMath:
+			check.Response, check.Err = v.VerifyMath(ctx, claim.Text)
+		case factContext != "":
+			check.Response, check.Err = v.VerifyFact(ctx, claim.Text, factContext)
+		default:
+			continue
+		}
+		checks = append(checks, check)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return checks, nil
+}
+
+// Refuted returns the checks the engines refuted.
+func Refuted(checks []Check) []Check {
+	var refuted []Check
+	for _, c := range checks {
+		if c.Err == nil && c.Response.Verdict() == qwed.VerdictRefuted {
+			refuted = append(refuted, c)
+		}
+	}
+	return refuted
+}
+
+// Unchecked returns the checks that could not run or were inconclusive.
+func Unchecked(checks []Check) []Check {
+	var unchecked []Check
+	for _, c := range checks {
+		if c.Err != nil || c.Response.Verdict() == qwed.VerdictInconclusive {
+			unchecked = append(unchecked, c)
+		}
+	}
+	return unchecked
+}
+
+// ============================================================================
+// Code Blocks
+// ============================================================================
+
+type codeBlock struct {
+	lang string
+	code string
+}
+
+// splitCodeBlocks separates fenced code blocks from the surrounding prose.
+// An unterminated fence runs to the end of the text.
+func splitCodeBlocks(text string) (string, []codeBlock) {
+	var prose strings.Builder
+	var blocks []codeBlock
+	var code strings.Builder
+	lang, inBlock := "", false
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			if inBlock {
+				blocks = append(blocks, codeBlock{lang: lang, code: code.String()})
+				code.Reset()
+			} else {
+				lang = qwed.FenceLanguage(strings.TrimPrefix(trimmed, "```"))
+			}
+			inBlock = !inBlock
+			continue
+		}
+		if inBlock {
+			code.WriteString(line)
+		} else {
+			prose.WriteString(line)
+		}
+	}
+	if inBlock && code.Len() > 0 {
+		blocks = append(blocks, codeBlock{lang: lang, code: code.String()})
+	}
+	return prose.String(), blocks
+}
@@ -0,0 +1,13 @@
+package route
+
+import "testing"
+
+func TestSplitCodeBlocks(t *testing.T) {
+	prose, blocks := splitCodeBlocks("Intro\n```Go\nfmt.Println()\n```\nOutro\n```\nplain\n")
+	if prose != "Intro\nOutro\n" {
+		t.Errorf("unexpected prose %q", prose)
+	}
+	if len(blocks) != 2 || blocks[0].lang != "go" || blocks[0].code != "fmt.Println()\n" || blocks[1].lang != "" {
+		t.Errorf("unexpected blocks: %+v", blocks)
+	}
+}
@@ -0,0 +1,238 @@
+// Package langchaingo verifies LangChainGo generations and tool outputs.
+//
+// Handler is a LangChainGo callbacks.Handler. Attach it to the model and
+// chain, and read the results from the Run attached to the context:
+//
+//	handler := qwedlc.NewHandler(client)
+//	llm, err := openai.New(openai.WithCallback(handler))
+//	chain := chains.NewLLMChain(llm, prompt, chains.WithCallback(handler))
+//
+//	ctx, run := qwedlc.StartRun(ctx)
+//	outputs, err := chains.Call(ctx, chain, inputs)
+//	if !run.Verified() { ... }
+//
+// The package is a separate module, so only programs importing it depend
+// on LangChainGo.
+package langchaingo
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+	"github.com/QWED-AI/qwed-verification/sdk-go/integrations/internal/route"
+)
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// Step is the kind of chain step an output came from.
+type Step string
+
+const (
+	StepLLM   Step = "llm"   // a model generation
+	StepTool  Step = "tool"  // a tool's output
+	StepChain Step = "chain" // a chain's final outputs
+)
+
+// Check is the verification of one part of a step's output.
+type Check = route.Check
+
+// StepResult is the verification of one step's output.
+type StepResult struct {
+	Step     Step
+	Output   string
+	Checks   []Check
+	Verified bool // no check was refuted
+}
+
+// Run collects the step results of one chain invocation.
+type Run struct {
+	mu      sync.Mutex
+	results []StepResult
+}
+
+type runKey struct{}
+
+// StartRun attaches a new Run to ctx. Pass the returned context to the
+// chain so the handler records results on the run.
+func StartRun(ctx context.Context) (context.Context, *Run) {
+	run := &Run{}
+	return context.WithValue(ctx, runKey{}, run), run
+}
+
+// RunFromContext returns the Run attached to ctx, or nil.
+func RunFromContext(ctx context.Context) *Run {
+	run, _ := ctx.Value(runKey{}).(*Run)
+	return run
+}
+
+// Results returns the step results recorded so far, in order.
+func (r *Run) Results() []StepResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]StepResult(nil), r.results...)
+}
+
+// Verified reports whether no step output was refuted.
+func (r *Run) Verified() bool {
+	for _, result := range r.Results() {
+		if !result.Verified {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Run) add(result StepResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, result)
+}
+
+// ============================================================================
+// Handler
+// ============================================================================
+
+// Handler is a LangChainGo callbacks.Handler verifying chain step outputs.
+// Generations and tool outputs are verified by default; chain outputs only
+// with WithStep(StepChain, ...). Other callbacks are ignored.
+type Handler struct {
+	callbacks.SimpleHandler
+
+	verifier    qwed.Verifier
+	verifiers   map[Step]qwed.Verifier
+	factContext func(ctx context.Context) string
+	outputKey   string
+	onResult    func(ctx context.Context, result StepResult)
+}
+
+var _ callbacks.Handler = (*Handler)(nil)
+
+// Option configures a Handler.
+type Option func(*Handler)
+
+// WithStep verifies outputs of step with v, for example a client created
+// with a stricter qwed.Policy for tool outputs. A nil v uses the handler's
+// default verifier.
+func WithStep(step Step, v qwed.Verifier) Option {
+	return func(h *Handler) {
+		if v == nil {
+			v = h.verifier
+		}
+		h.verifiers[step] = v
+	}
+}
+
+// SkipStep disables verification of step outputs.
+func SkipStep(step Step) Option {
+	return func(h *Handler) {
+		delete(h.verifiers, step)
+	}
+}
+
+// WithFactContext supplies source text, such as retrieved documents, for
+// fact checks. Without it, only code and arithmetic are checked.
+func WithFactContext(fn func(ctx context.Context) string) Option {
+	return func(h *Handler) {
+		h.factContext = fn
+	}
+}
+
+// WithOutputKey makes HandleChainEnd add the run's results to the chain
+// outputs under key, attaching them to the chain's return value.
+func WithOutputKey(key string) Option {
+	return func(h *Handler) {
+		h.outputKey = key
+	}
+}
+
+// OnResult registers a function called with every step result, for
+// example to log or block on failures.
+func OnResult(fn func(ctx context.Context, result StepResult)) Option {
+	return func(h *Handler) {
+		h.onResult = fn
+	}
+}
+
+// NewHandler creates a handler verifying with v.
+func NewHandler(v qwed.Verifier, opts ...Option) *Handler {
+	h := &Handler{verifier: v, verifiers: map[Step]qwed.Verifier{StepLLM: v, StepTool: v}}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// HandleLLMGenerateContentEnd implements the LangChainGo callback for model
+// generations, verifying each choice.
+func (h *Handler) HandleLLMGenerateContentEnd(ctx context.Context, res *llms.ContentResponse) {
+	if res == nil {
+		return
+	}
+	for _, choice := range res.Choices {
+		if choice != nil {
+			h.HandleGeneration(ctx, choice.Content)
+		}
+	}
+}
+
+// HandleGeneration verifies a model generation, for models called outside
+// LangChainGo's callbacks.
+func (h *Handler) HandleGeneration(ctx context.Context, text string) {
+	h.verify(ctx, StepLLM, text)
+}
+
+// HandleToolEnd implements the LangChainGo callback for tool outputs.
+func (h *Handler) HandleToolEnd(ctx context.Context, output string) {
+	h.verify(ctx, StepTool, output)
+}
+
+// HandleChainEnd implements the LangChainGo callback for chain outputs. It
+// verifies string outputs if chain verification is enabled, then attaches
+// the run's results to outputs if an output key is set.
+func (h *Handler) HandleChainEnd(ctx context.Context, outputs map[string]any) {
+	keys := make([]string, 0, len(outputs))
+	for key := range outputs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if text, ok := outputs[key].(string); ok && key != h.outputKey {
+			h.verify(ctx, StepChain, text)
+		}
+	}
+	if run := RunFromContext(ctx); run != nil && h.outputKey != "" && outputs != nil {
+		outputs[h.outputKey] = run.Results()
+	}
+}
+
+// verify checks a step output and records the result.
+func (h *Handler) verify(ctx context.Context, step Step, text string) {
+	v := h.verifiers[step]
+	if v == nil || text == "" {
+		return
+	}
+
+	factContext := ""
+	if h.factContext != nil {
+		factContext = h.factContext(ctx)
+	}
+	checks, err := route.Verify(ctx, v, text, factContext)
+	if err != nil {
+		return
+	}
+
+	result := StepResult{Step: step, Output: text, Checks: checks, Verified: len(route.Refuted(checks)) == 0}
+	if run := RunFromContext(ctx); run != nil {
+		run.add(result)
+	}
+	if h.onResult != nil {
+		h.onResult(ctx, result)
+	}
+}
@@ -0,0 +1,103 @@
+package langchaingo
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/prompts"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// mathServer verifies expressions equal to 4 and counts calls per path.
+func mathServer(t *testing.T, calls map[string]int) *qwed.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		calls[r.URL.Path]++
+
+		verified := true
+		if expr, ok := req["expression"]; ok {
+			verified = strings.HasSuffix(strings.TrimRight(strings.ReplaceAll(expr, " ", ""), "."), "=4")
+		}
+		json.NewEncoder(w).Encode(qwed.VerificationResponse{Verified: verified})
+	}))
+	t.Cleanup(server.Close)
+	return qwed.NewClient("test-key", qwed.WithBaseURL(server.URL))
+}
+
+func TestHandlerRecordsRun(t *testing.T) {
+	calls := map[string]int{}
+	h := NewHandler(mathServer(t, calls), WithOutputKey("qwed"))
+
+	ctx, run := StartRun(context.Background())
+	h.HandleGeneration(ctx, "2 + 2 = 4.")
+	h.HandleToolEnd(ctx, "The total is 2 + 2 = 5.")
+	outputs := map[string]any{"text": "2 + 2 = 4."}
+	h.HandleChainEnd(ctx, outputs)
+
+	results := run.Results()
+	if len(results) != 2 || results[0].Step != StepLLM || !results[0].Verified || results[1].Step != StepTool || results[1].Verified {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if run.Verified() {
+		t.Error("expected run with a refuted tool output not to be verified")
+	}
+	if attached, ok := outputs["qwed"].([]StepResult); !ok || len(attached) != 2 {
+		t.Errorf("expected results attached to chain outputs, got %#v", outputs["qwed"])
+	}
+	if calls["/verify/math"] != 2 {
+		t.Errorf("expected chain outputs not to be verified by default, got %d math calls", calls["/verify/math"])
+	}
+}
+
+func TestHandlerStepConfiguration(t *testing.T) {
+	calls := map[string]int{}
+	defaultClient := mathServer(t, calls)
+	chainCalls := map[string]int{}
+	chainClient := mathServer(t, chainCalls)
+
+	var got []StepResult
+	h := NewHandler(defaultClient,
+		SkipStep(StepTool),
+		WithStep(StepChain, chainClient),
+		WithFactContext(func(ctx context.Context) string { return "Paris is the capital of France." }),
+		OnResult(func(ctx context.Context, r StepResult) { got = append(got, r) }),
+	)
+
+	ctx := context.Background()
+	h.HandleToolEnd(ctx, "2 + 2 = 5.")
+	h.HandleChainEnd(ctx, map[string]any{"answer": "Paris is the capital of France.", "count": 3})
+
+	if len(got) != 1 || got[0].Step != StepChain || len(got[0].Checks) != 1 || got[0].Checks[0].Engine != qwed.TypeFact {
+		t.Fatalf("unexpected results: %+v", got)
+	}
+	if calls["/verify/math"] != 0 || chainCalls["/verify/fact"] != 1 {
+		t.Errorf("expected only the chain verifier to be called, got %v and %v", calls, chainCalls)
+	}
+}
+
+func TestHandlerCallbacks(t *testing.T) {
+	calls := map[string]int{}
+	var handler callbacks.Handler = NewHandler(mathServer(t, calls), WithStep(StepChain, nil))
+
+	ctx, run := StartRun(context.Background())
+	handler.HandleLLMGenerateContentEnd(ctx, &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "2 + 2 = 4."}}})
+	chain := chains.NewLLMChain(fake.NewFakeLLM([]string{"2 + 2 = 5."}), prompts.NewPromptTemplate("{{.question}}", []string{"question"}), chains.WithCallback(handler))
+	if _, err := chains.Call(ctx, chain, map[string]any{"question": "What is 2 + 2?"}); err != nil {
+		t.Fatal(err)
+	}
+
+	results := run.Results()
+	if len(results) != 2 || results[0].Step != StepLLM || !results[0].Verified || results[1].Step != StepChain || results[1].Verified {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+}
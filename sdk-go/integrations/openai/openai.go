@@ -0,0 +1,179 @@
+// Package openai adds QWED verification to OpenAI chat completions.
+//
+// The package does not import an OpenAI client. Wrap takes the completion
+// call and a function returning the assistant's text, so it works with
+// github.com/openai/openai-go or any compatible client:
+//
+//	create := qwedopenai.Wrap(qwedClient,
+//	    func(ctx context.Context, p openai.ChatCompletionNewParams) (*openai.ChatCompletion, error) {
+//	        return oai.Chat.Completions.New(ctx, p)
+//	    },
+//	    func(c *openai.ChatCompletion) string { return c.Choices[0].Message.Content },
+//	    &qwedopenai.Config[openai.ChatCompletionNewParams, *openai.ChatCompletion]{Action: qwedopenai.Block},
+//	)
+//
+//	completion, err := create(ctx, params) // *qwedopenai.BlockedError if verification fails
+//
+// Each completion is routed to the matching engine: fenced code blocks to
+// the code engine, arithmetic claims to the math engine, and other claims to
+// the fact engine when Config.FactContext supplies source text.
+package openai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+	"github.com/QWED-AI/qwed-verification/sdk-go/integrations/internal/route"
+)
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// Action selects what the wrapper does when a completion fails
+// verification.
+type Action int
+
+const (
+	// Annotate returns the completion unchanged and reports the result to
+	// Config.OnVerify.
+	Annotate Action = iota
+	// Block returns a *BlockedError instead of the completion.
+	Block
+	// Retry generates a new completion, up to Config.MaxRetries times, and
+	// blocks if none passes.
+	Retry
+)
+
+// ErrBlocked is matched by errors.Is for a *BlockedError.
+var ErrBlocked = errors.New("qwed: completion failed verification")
+
+// BlockedError is returned when a completion fails verification in Block or
+// Retry mode.
+type BlockedError struct {
+	Result *Result
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("%v after %d attempts: %d of %d checks failed, %d errored",
+		ErrBlocked, e.Result.Attempts, len(e.Result.Failed()), len(e.Result.Checks), len(e.Result.Errored()))
+}
+
+func (e *BlockedError) Unwrap() error {
+	return ErrBlocked
+}
+
+// Check is the verification of one part of a completion.
+type Check = route.Check
+
+// Result is the verification of a completion.
+type Result struct {
+	Checks   []Check
+	Verified bool
+	Attempts int // completions generated, including retries
+}
+
+// Failed returns the checks the engines refuted.
+func (r *Result) Failed() []Check {
+	return route.Refuted(r.Checks)
+}
+
+// Errored returns the checks that could not run or were inconclusive.
+func (r *Result) Errored() []Check {
+	return route.Unchecked(r.Checks)
+}
+
+// Config configures Wrap. P and R are the completion parameter and response
+// types of the OpenAI client.
+type Config[P, R any] struct {
+	Action Action
+
+	// MaxRetries is the number of extra completions tried in Retry mode.
+	// Defaults to 2.
+	MaxRetries int
+
+	// Revise returns the parameters for a retry, for example with a message
+	// telling the model what failed. Defaults to reusing params unchanged.
+	Revise func(params P, completion R, result *Result) P
+
+	// FactContext returns the source text for fact checks, such as
+	// retrieved documents. Without it, non-arithmetic claims are not checked.
+	FactContext func(params P) string
+
+	// FailClosed treats checks that could not run or were inconclusive, for
+	// example because the API is unreachable, as failures. By default they
+	// are ignored.
+	FailClosed bool
+
+	// OnVerify, if set, receives every completion's verification result.
+	OnVerify func(ctx context.Context, completion R, result *Result)
+}
+
+// ============================================================================
+// Wrapper
+// ============================================================================
+
+// Wrap returns complete with verification added. content extracts the
+// assistant's text from a completion. A nil cfg annotates only.
+func Wrap[P, R any](v qwed.Verifier, complete func(context.Context, P) (R, error), content func(R) string, cfg *Config[P, R]) func(context.Context, P) (R, error) {
+	if cfg == nil {
+		cfg = &Config[P, R]{}
+	}
+	return func(ctx context.Context, params P) (R, error) {
+		var zero R
+		attempts := 1
+		if cfg.Action == Retry {
+			retries := cfg.MaxRetries
+			if retries <= 0 {
+				retries = 2
+			}
+			attempts += retries
+		}
+
+		var result *Result
+		for attempt := 1; attempt <= attempts; attempt++ {
+			completion, err := complete(ctx, params)
+			if err != nil {
+				return zero, err
+			}
+
+			factContext := ""
+			if cfg.FactContext != nil {
+				factContext = cfg.FactContext(params)
+			}
+			result, err = Verify(ctx, v, content(completion), factContext)
+			if err != nil {
+				return zero, err
+			}
+			result.Attempts = attempt
+			if cfg.FailClosed && len(result.Errored()) > 0 {
+				result.Verified = false
+			}
+			if cfg.OnVerify != nil {
+				cfg.OnVerify(ctx, completion, result)
+			}
+
+			if result.Verified || cfg.Action == Annotate {
+				return completion, nil
+			}
+			if cfg.Action == Retry && cfg.Revise != nil {
+				params = cfg.Revise(params, completion, result)
+			}
+		}
+		return zero, &BlockedError{Result: result}
+	}
+}
+
+// Verify routes text to the matching engines: fenced code blocks with a
+// language to the code engine, arithmetic claims to the math engine, and
+// other claims to the fact engine if factContext is not empty. Engine errors
+// are recorded on the check; Verify only returns an error if ctx is done.
+func Verify(ctx context.Context, v qwed.Verifier, text, factContext string) (*Result, error) {
+	checks, err := route.Verify(ctx, v, text, factContext)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Checks: checks, Verified: len(route.Refuted(checks)) == 0}, nil
+}
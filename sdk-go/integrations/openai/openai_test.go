@@ -0,0 +1,122 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// guardServer verifies math equal to 4, code without eval, and facts found
+// verbatim in the context.
+func guardServer(t *testing.T) *qwed.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var verified bool
+		switch r.URL.Path {
+		case "/verify/math":
+			verified = strings.HasSuffix(strings.TrimRight(strings.ReplaceAll(req["expression"], " ", ""), "."), "=4")
+		case "/verify/code":
+			verified = !strings.Contains(req["code"], "eval(") && req["language"] == "python"
+		case "/verify/fact":
+			verified = strings.Contains(req["context"], strings.TrimSuffix(req["claim"], "."))
+		}
+		json.NewEncoder(w).Encode(qwed.VerificationResponse{Verified: verified})
+	}))
+	t.Cleanup(server.Close)
+	return qwed.NewClient("test-key", qwed.WithBaseURL(server.URL))
+}
+
+func TestVerifyRouting(t *testing.T) {
+	text := "The answer is 2 + 2 = 5.\n\n```py\nprint(eval(input()))\n```\nParis is the capital of France."
+	result, err := Verify(context.Background(), guardServer(t), text, "Paris is the capital of France")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engines := map[qwed.VerificationType]bool{}
+	for _, c := range result.Checks {
+		engines[c.Engine] = qwed.IsVerified(c.Response)
+	}
+	if len(result.Checks) != 3 || engines[qwed.TypeMath] || engines[qwed.TypeCode] || !engines[qwed.TypeFact] {
+		t.Errorf("unexpected checks: %+v", result.Checks)
+	}
+	if result.Verified || len(result.Failed()) != 2 {
+		t.Errorf("expected two failures, got %+v", result)
+	}
+}
+
+func TestWrapBlock(t *testing.T) {
+	complete := func(ctx context.Context, prompt string) (string, error) { return "So 2 + 2 = 5.", nil }
+	create := Wrap(guardServer(t), complete, func(s string) string { return s }, &Config[string, string]{Action: Block})
+
+	_, err := create(context.Background(), "what is 2+2?")
+	var blocked *BlockedError
+	if !errors.As(err, &blocked) || !errors.Is(err, ErrBlocked) {
+		t.Fatalf("expected BlockedError, got %v", err)
+	}
+	if blocked.Result.Attempts != 1 || len(blocked.Result.Failed()) != 1 {
+		t.Errorf("unexpected result: %+v", blocked.Result)
+	}
+}
+
+func TestWrapRetry(t *testing.T) {
+	answers := []string{"So 2 + 2 = 5.", "So 2 + 2 = 4."}
+	var prompts []string
+	complete := func(ctx context.Context, prompt string) (string, error) {
+		prompts = append(prompts, prompt)
+		return answers[len(prompts)-1], nil
+	}
+
+	create := Wrap(guardServer(t), complete, func(s string) string { return s }, &Config[string, string]{
+		Action: Retry,
+		Revise: func(prompt, completion string, result *Result) string {
+			return prompt + "\nYour previous answer was wrong: " + result.Failed()[0].Input
+		},
+	})
+
+	completion, err := create(context.Background(), "what is 2+2?")
+	if err != nil || completion != "So 2 + 2 = 4." {
+		t.Fatalf("expected verified retry, got %q, %v", completion, err)
+	}
+	if len(prompts) != 2 || !strings.Contains(prompts[1], "was wrong: So 2 + 2 = 5.") {
+		t.Errorf("unexpected prompts: %q", prompts)
+	}
+}
+
+func TestWrapAnnotate(t *testing.T) {
+	var got *Result
+	complete := func(ctx context.Context, prompt string) (string, error) { return "So 2 + 2 = 5.", nil }
+	create := Wrap(guardServer(t), complete, func(s string) string { return s }, &Config[string, string]{
+		OnVerify: func(ctx context.Context, completion string, result *Result) { got = result },
+	})
+
+	completion, err := create(context.Background(), "what is 2+2?")
+	if err != nil || completion != "So 2 + 2 = 5." {
+		t.Fatalf("expected completion to pass through, got %q, %v", completion, err)
+	}
+	if got == nil || got.Verified {
+		t.Errorf("expected failed verification to be reported, got %+v", got)
+	}
+}
+
+func TestWrapFailClosed(t *testing.T) {
+	v := qwed.NewClient("test-key", qwed.WithBaseURL("http://127.0.0.1:1"))
+	complete := func(ctx context.Context, prompt string) (string, error) { return "So 2 + 2 = 4.", nil }
+	identity := func(s string) string { return s }
+
+	if _, err := Wrap(v, complete, identity, &Config[string, string]{Action: Block})(context.Background(), ""); err != nil {
+		t.Errorf("expected unreachable API to fail open, got %v", err)
+	}
+	_, err := Wrap(v, complete, identity, &Config[string, string]{Action: Block, FailClosed: true})(context.Background(), "")
+	if !errors.Is(err, ErrBlocked) {
+		t.Errorf("expected FailClosed to block, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package qwed
+
+import (
+	"context"
+	"time"
+)
+
+// ============================================================================
+// Interceptors
+// ============================================================================
+
+// Request describes a verification call as seen by interceptors.
+// Interceptors may modify it before calling the next Invoker.
+type Request struct {
+	Op       string           // public method name, e.g. "VerifyMath"
+	Engine   VerificationType // verification engine
+	Path     string           // API path, e.g. "/verify/math"
+	Body     interface{}      // JSON request body
+	CacheKey string           // response cache key; empty disables caching
+	NoCache  bool             // skip the cache lookup; the response is still cached
+}
+
+// Invoker performs a verification call.
+type Invoker func(ctx context.Context, req *Request) (*VerificationResponse, error)
+
+// Interceptor wraps verification calls, in the style of gRPC unary
+// interceptors. It must call next to continue the chain, or may return a
+// response without calling it.
+type Interceptor func(ctx context.Context, req *Request, next Invoker) (*VerificationResponse, error)
+
+// WithInterceptor adds an interceptor around every verification call.
+// Interceptors run in the order they are added, the first being outermost.
+func WithInterceptor(i Interceptor) ClientOption {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, i)
+	}
+}
+
+// chain composes interceptors around final.
+func chain(interceptors []Interceptor, final Invoker) Invoker {
+	next := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := interceptors[i], next
+		next = func(ctx context.Context, req *Request) (*VerificationResponse, error) {
+			return interceptor(ctx, req, inner)
+		}
+	}
+	return next
+}
+
+// ============================================================================
+// Built-in Interceptors
+// ============================================================================
+
+// LoggingInterceptor logs each verification call with its engine, outcome
+// and latency using logf, for example log.Printf.
+func LoggingInterceptor(logf func(format string, args ...interface{})) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) (*VerificationResponse, error) {
+		start := time.Now()
+		resp, err := next(ctx, req)
+		elapsed := time.Since(start).Round(time.Microsecond)
+
+		if err != nil {
+			logf("qwed: %s engine=%s latency=%s error=%v", req.Op, req.Engine, elapsed, err)
+		} else {
+			logf("qwed: %s engine=%s latency=%s status=%s verified=%t", req.Op, req.Engine, elapsed, resp.Status, resp.Verified)
+		}
+		return resp, err
+	}
+}
+
+// LatencyInterceptor reports the latency of each verification call to
+// observe.
+func LatencyInterceptor(observe func(req *Request, latency time.Duration, err error)) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) (*VerificationResponse, error) {
+		start := time.Now()
+		resp, err := next(ctx, req)
+		observe(req, time.Since(start), err)
+		return resp, err
+	}
+}
@@ -0,0 +1,95 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInterceptorOrderAndMutation(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(VerificationResponse{
+			Status:   StatusVerified,
+			Verified: body["expression"] == "2 + 2 = 4",
+		})
+	})
+	defer server.Close()
+
+	var order []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, req *Request, next Invoker) (*VerificationResponse, error) {
+			order = append(order, name+">")
+			resp, err := next(ctx, req)
+			order = append(order, "<"+name)
+			return resp, err
+		}
+	}
+	normalize := func(ctx context.Context, req *Request, next Invoker) (*VerificationResponse, error) {
+		body := req.Body.(map[string]interface{})
+		body["expression"] = strings.ReplaceAll(body["expression"].(string), "plus", "+")
+		return next(ctx, req)
+	}
+
+	client := NewClient("test-key", WithBaseURL(server.URL),
+		WithInterceptor(record("a")),
+		WithInterceptor(record("b")),
+		WithInterceptor(normalize),
+	)
+
+	resp, err := client.VerifyMath(context.Background(), "2 plus 2 = 4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Verified {
+		t.Error("expected mutated request to verify")
+	}
+	if got := strings.Join(order, " "); got != "a> b> <b <a" {
+		t.Errorf("unexpected interceptor order: %s", got)
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"),
+		WithInterceptor(func(ctx context.Context, req *Request, next Invoker) (*VerificationResponse, error) {
+			return &VerificationResponse{Status: StatusBlocked, Engine: Engine(req.Engine)}, nil
+		}),
+	)
+
+	resp, err := client.VerifyFact(context.Background(), "claim", "context")
+	if err != nil || resp.Status != StatusBlocked || resp.Engine != "fact" {
+		t.Errorf("expected short-circuit response, got %+v, %v", resp, err)
+	}
+}
+
+func TestBuiltinInterceptors(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	})
+	defer server.Close()
+
+	var logs []string
+	var observed time.Duration
+	client := NewClient("test-key", WithBaseURL(server.URL),
+		WithInterceptor(LoggingInterceptor(func(format string, args ...interface{}) {
+			logs = append(logs, fmt.Sprintf(format, args...))
+		})),
+		WithInterceptor(LatencyInterceptor(func(req *Request, d time.Duration, err error) {
+			observed = d
+		})),
+	)
+
+	client.VerifyLogic(context.Background(), "A -> A")
+
+	if len(logs) != 1 || !strings.Contains(logs[0], "VerifyLogic engine=logic") || !strings.Contains(logs[0], "verified=true") {
+		t.Errorf("unexpected logs: %q", logs)
+	}
+	if observed <= 0 {
+		t.Error("expected latency to be observed")
+	}
+}
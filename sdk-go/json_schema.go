@@ -0,0 +1,40 @@
+package qwed
+
+import (
+	"context"
+)
+
+// ============================================================================
+// JSON Schema Verification
+// ============================================================================
+
+// SchemaViolation is a single JSON Schema validation failure.
+type SchemaViolation struct {
+	Path    string `json:"path"`              // JSON Pointer to the offending value, e.g. "/items/0/price"
+	Keyword string `json:"keyword,omitempty"` // failing schema keyword, e.g. "required"
+	Message string `json:"message"`
+}
+
+// VerifyJSON checks that an LLM-generated JSON document conforms to a JSON
+// Schema. Violations are reported in Result["violations"]; use
+// SchemaViolations to decode them.
+func (c *Client) VerifyJSON(ctx context.Context, jsonDoc, schema string, callOpts ...CallOption) (*VerificationResponse, error) {
+	req := map[string]interface{}{
+		"json":   jsonDoc,
+		"schema": schema,
+	}
+
+	return c.verify(ctx, "VerifyJSON", TypeJSON, CacheKey(TypeJSON, schema, jsonDoc), req, callOpts...)
+}
+
+// SchemaViolations extracts the path-level violations from a VerifyJSON
+// response.
+func SchemaViolations(resp *VerificationResponse) []SchemaViolation {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var violations []SchemaViolation
+	decodeResult(resp.Result["violations"], &violations)
+	return violations
+}
@@ -0,0 +1,47 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyJSON(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/json" {
+			t.Errorf("expected path /verify/json, got %s", r.URL.Path)
+		}
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["schema"] == "" || req["json"] == "" {
+			t.Errorf("expected json and schema in request, got %v", req)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": false,
+			"engine":   "json",
+			"result": map[string]interface{}{
+				"violations": []map[string]string{
+					{"path": "/price", "keyword": "type", "message": "expected number, got string"},
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyJSON(context.Background(),
+		`{"price": "ten"}`,
+		`{"type": "object", "properties": {"price": {"type": "number"}}}`,
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	violations := SchemaViolations(resp)
+	if len(violations) != 1 || violations[0].Path != "/price" || violations[0].Keyword != "type" {
+		t.Errorf("unexpected violations: %+v", violations)
+	}
+}
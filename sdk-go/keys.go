@@ -0,0 +1,111 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+// ============================================================================
+// API Keys
+// ============================================================================
+
+// WithAPIKeys sets several API keys, replacing the one passed to NewClient.
+// Requests use one key until the API rejects it with 401 or 429; the client
+// then moves on to the next key, in order and wrapping around, and retries
+// the request with it. Each key is tried at most once per request. Use it
+// to spread load across keys, or to keep running while a revoked key is
+// replaced.
+func WithAPIKeys(keys ...string) ClientOption {
+	return func(c *Client) {
+		c.keys.set(keys)
+	}
+}
+
+// SetAPIKey replaces the client's API keys with key, so long-lived
+// services can rotate credentials without creating a new client. It is
+// safe for concurrent use; requests already sent finish with the old key.
+func (c *Client) SetAPIKey(key string) {
+	c.keys.set([]string{key})
+}
+
+// keyRing holds the client's API keys and which is in use.
+type keyRing struct {
+	mu     sync.Mutex
+	keys   []string
+	active int
+	gen    int // incremented by set, so rotations of replaced keys are ignored
+}
+
+// keyPos identifies the key a request was sent with.
+type keyPos struct {
+	index, gen int
+}
+
+func newKeyRing(key string) *keyRing {
+	return &keyRing{keys: []string{key}}
+}
+
+// set replaces the keys, starting again from the first.
+func (r *keyRing) set(keys []string) {
+	if len(keys) == 0 {
+		keys = []string{""}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = append([]string(nil), keys...)
+	r.active = 0
+	r.gen++
+}
+
+// current returns the key in use.
+func (r *keyRing) current() (string, keyPos) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.keys[r.active], keyPos{r.active, r.gen}
+}
+
+func (r *keyRing) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.keys)
+}
+
+// rotate moves past the key at pos if it is still in use, and returns the
+// key now in use. Concurrent requests rejected with the same key rotate
+// once between them.
+func (r *keyRing) rotate(pos keyPos) (string, keyPos) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if pos.gen == r.gen && pos.index == r.active {
+		r.active = (r.active + 1) % len(r.keys)
+	}
+	return r.keys[r.active], keyPos{r.active, r.gen}
+}
+
+// roundTripKeys performs roundTrip with the key in use, moving on to the
+// next key and retrying when the API rejects it.
+func (c *Client) roundTripKeys(ctx context.Context, method, url string, data []byte, result interface{}) error {
+	if c.tokens != nil {
+		return c.roundTripToken(ctx, method, url, data, result)
+	}
+	key, pos := c.keys.current()
+	for tried := 1; ; tried++ {
+		err := c.roundTrip(ctx, method, url, key, data, result)
+		if tried >= c.keys.len() || !keyRejected(err) {
+			return err
+		}
+		key, pos = c.keys.rotate(pos)
+		c.log(ctx, slog.LevelWarn, "qwed rotating API key", slog.Int("key_index", pos.index), slog.Any("error", err))
+	}
+}
+
+// keyRejected reports whether err is the API refusing the key: invalid or
+// revoked (401), or over its rate limit (429).
+func keyRejected(err error) bool {
+	var apiErr *QWEDError
+	return errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusTooManyRequests)
+}
@@ -0,0 +1,129 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// keyServer accepts requests made with one of valid and rejects others
+// with status, recording the keys it sees.
+type keyServer struct {
+	mu    sync.Mutex
+	valid map[string]bool
+	seen  []string
+}
+
+func newKeyServer(t *testing.T, status int, valid ...string) (*keyServer, *httptest.Server) {
+	k := &keyServer{valid: make(map[string]bool)}
+	for _, key := range valid {
+		k.valid[key] = true
+	}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		k.mu.Lock()
+		k.seen = append(k.seen, key)
+		ok := k.valid[key]
+		k.mu.Unlock()
+		if !ok {
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"code": "REJECTED", "message": "key rejected"}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	})
+	t.Cleanup(server.Close)
+	return k, server
+}
+
+func (k *keyServer) keys() []string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return append([]string(nil), k.seen...)
+}
+
+func TestWithAPIKeysRotatesOnRejection(t *testing.T) {
+	for _, status := range []int{http.StatusUnauthorized, http.StatusTooManyRequests} {
+		k, server := newKeyServer(t, status, "k2")
+		client := NewClient("ignored", WithBaseURL(server.URL), WithAPIKeys("k1", "k2"))
+
+		for i := 0; i < 2; i++ {
+			if _, err := client.VerifyMath(context.Background(), "2+2"); err != nil {
+				t.Fatalf("status %d: unexpected error: %v", status, err)
+			}
+		}
+		if got := k.keys(); len(got) != 3 || got[0] != "k1" || got[1] != "k2" || got[2] != "k2" {
+			t.Errorf("status %d: expected k1 once, then k2 for every request, got %v", status, got)
+		}
+	}
+}
+
+func TestWithAPIKeysTriesEachKeyOnce(t *testing.T) {
+	k, server := newKeyServer(t, http.StatusUnauthorized)
+	client := NewClient("", WithBaseURL(server.URL), WithAPIKeys("k1", "k2", "k3"))
+
+	_, err := client.VerifyMath(context.Background(), "2+2")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("expected ErrUnauthorized, got %v", err)
+	}
+	if got := k.keys(); len(got) != 3 {
+		t.Errorf("expected each key tried once, got %v", got)
+	}
+}
+
+func TestAPIKeyNotRotatedOnOtherErrors(t *testing.T) {
+	k, server := newKeyServer(t, http.StatusBadRequest)
+	client := NewClient("", WithBaseURL(server.URL), WithAPIKeys("k1", "k2"))
+
+	if _, err := client.VerifyMath(context.Background(), "2+2"); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest, got %v", err)
+	}
+	if got := k.keys(); len(got) != 1 {
+		t.Errorf("expected no rotation on a bad request, got %v", got)
+	}
+}
+
+func TestSetAPIKey(t *testing.T) {
+	k, server := newKeyServer(t, http.StatusUnauthorized, "old", "new")
+	client := NewClient("old", WithBaseURL(server.URL))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Verify(context.Background(), "q"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	client.SetAPIKey("new")
+	wg.Wait()
+
+	if _, err := client.VerifyMath(context.Background(), "2+2"); err != nil {
+		t.Fatal(err)
+	}
+	if got := k.keys(); got[len(got)-1] != "new" {
+		t.Errorf("expected the new key after SetAPIKey, got %v", got)
+	}
+}
+
+func TestKeyRingRotateOnce(t *testing.T) {
+	r := newKeyRing("")
+	r.set([]string{"a", "b", "c"})
+
+	_, pos := r.current()
+	r.rotate(pos)
+	if key, _ := r.rotate(pos); key != "b" {
+		t.Errorf("expected concurrent rejections of a to rotate once, got %s", key)
+	}
+
+	r.set([]string{"x"})
+	if key, _ := r.rotate(pos); key != "x" {
+		t.Errorf("expected a rotation from replaced keys to be ignored, got %s", key)
+	}
+}
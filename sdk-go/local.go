@@ -0,0 +1,641 @@
+package qwed
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ============================================================================
+// Local Engines
+// ============================================================================
+
+// Engine names reported by responses produced without the API.
+const (
+	EngineLocalMath  Engine = "local-math"
+	EngineLocalLogic Engine = "local-logic"
+)
+
+// mathTolerance is the relative tolerance used when comparing both sides of
+// an equation locally.
+const mathTolerance = 1e-9
+
+// localVerifyMath evaluates an arithmetic claim such as "2 + 2 = 4" with the
+// embedded deterministic evaluator. A bare expression such as "2 + 2" makes
+// no claim to check: it is reported as StatusUnsupported with its value.
+func localVerifyMath(expression string) (*VerificationResponse, error) {
+	lhs, op, rhs := splitComparison(expression)
+
+	left, err := evalArithmetic(lhs)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{"offline": true, "lhs": left}
+
+	if op == "" {
+		result["value"] = left
+		result["reason"] = "expression has no comparison to check"
+		return &VerificationResponse{Status: StatusUnsupported, Engine: EngineLocalMath, Result: result}, nil
+	}
+	right, err := evalArithmetic(rhs)
+	if err != nil {
+		return nil, err
+	}
+	result["rhs"] = right
+	result["operator"] = op
+	verified := compare(left, op, right)
+
+	return localResponse(EngineLocalMath, verified, result), nil
+}
+
+// localVerifyLogic checks that a propositional formula such as
+// "(A AND B) implies B" is a tautology by exhaustive evaluation.
+func localVerifyLogic(query string) (*VerificationResponse, error) {
+	p := &logicParser{tokens: tokenizeLogic(query)}
+	expr, err := p.parseIff()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q in logic expression", p.tokens[p.pos])
+	}
+
+	vars := make(map[string]bool)
+	expr.vars(vars)
+	if len(vars) > 20 {
+		return nil, fmt.Errorf("too many variables for local logic check: %d", len(vars))
+	}
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := map[string]interface{}{"offline": true, "variables": names}
+	env := make(map[string]bool, len(names))
+	for mask := 0; mask < 1<<len(names); mask++ {
+		for i, name := range names {
+			env[name] = mask&(1<<i) != 0
+		}
+		if !expr.eval(env) {
+			counter := make(map[string]interface{}, len(env))
+			for k, v := range env {
+				counter[k] = v
+			}
+			result["counterexample"] = counter
+			return localResponse(EngineLocalLogic, false, result), nil
+		}
+	}
+	return localResponse(EngineLocalLogic, true, result), nil
+}
+
+func localResponse(engine Engine, verified bool, result map[string]interface{}) *VerificationResponse {
+	status := StatusVerified
+	if !verified {
+		status = StatusFailed
+	}
+	return &VerificationResponse{
+		Status:   status,
+		Verified: verified,
+		Engine:   engine,
+		Result:   result,
+	}
+}
+
+// ============================================================================
+// Arithmetic Evaluator
+// ============================================================================
+
+var comparisonOps = []string{"==", "!=", "<=", ">=", "≠", "≤", "≥", "=", "<", ">"}
+
+// splitComparison splits "lhs op rhs" at the first comparison operator.
+func splitComparison(expr string) (lhs, op, rhs string) {
+	best := -1
+	for _, candidate := range comparisonOps {
+		if i := strings.Index(expr, candidate); i >= 0 && (best < 0 || i < best) {
+			best, op = i, candidate
+		}
+	}
+	if best < 0 {
+		return expr, "", ""
+	}
+	return expr[:best], op, expr[best+len(op):]
+}
+
+func compare(a float64, op string, b float64) bool {
+	equal := math.Abs(a-b) <= mathTolerance*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+	switch op {
+	case "=", "==":
+		return equal
+	case "!=", "≠":
+		return !equal
+	case "<":
+		return a < b && !equal
+	case "<=", "≤":
+		return a < b || equal
+	case ">":
+		return a > b && !equal
+	case ">=", "≥":
+		return a > b || equal
+	}
+	return false
+}
+
+// evalArithmetic evaluates an arithmetic expression supporting + - * / % ^,
+// parentheses, unary minus, the constants pi and e, and common functions.
+func evalArithmetic(expr string) (float64, error) {
+	p := &arithParser{src: strings.NewReplacer("×", "*", "÷", "/", "−", "-", "**", "^").Replace(expr)}
+	v, err := p.parseSum()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return 0, fmt.Errorf("unexpected %q at position %d", p.src[p.pos:], p.pos)
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("expression %q is not a finite number", strings.TrimSpace(expr))
+	}
+	return v, nil
+}
+
+type arithParser struct {
+	src string
+	pos int
+}
+
+func (p *arithParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+func (p *arithParser) peek() byte {
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *arithParser) parseSum() (float64, error) {
+	v, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek() {
+		case '+':
+			p.pos++
+			r, err := p.parseProduct()
+			if err != nil {
+				return 0, err
+			}
+			v += r
+		case '-':
+			p.pos++
+			r, err := p.parseProduct()
+			if err != nil {
+				return 0, err
+			}
+			v -= r
+		default:
+			return v, nil
+		}
+	}
+}
+
+func (p *arithParser) parseProduct() (float64, error) {
+	v, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' && op != '%' {
+			return v, nil
+		}
+		p.pos++
+		r, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '*':
+			v *= r
+		case '/':
+			if r == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			v /= r
+		case '%':
+			if r == 0 {
+				return 0, fmt.Errorf("modulo by zero")
+			}
+			v = math.Mod(v, r)
+		}
+	}
+}
+
+func (p *arithParser) parseUnary() (float64, error) {
+	switch p.peek() {
+	case '-':
+		p.pos++
+		v, err := p.parseUnary()
+		return -v, err
+	case '+':
+		p.pos++
+		return p.parseUnary()
+	}
+	return p.parsePower()
+}
+
+func (p *arithParser) parsePower() (float64, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return 0, err
+	}
+	if p.peek() == '^' {
+		p.pos++
+		exp, err := p.parseUnary() // right-associative
+		if err != nil {
+			return 0, err
+		}
+		return math.Pow(base, exp), nil
+	}
+	return base, nil
+}
+
+func (p *arithParser) parsePrimary() (float64, error) {
+	c := p.peek()
+	switch {
+	case c == '(':
+		p.pos++
+		v, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return v, nil
+	case isDigit(c) || c == '.':
+		start := p.pos
+		for p.pos < len(p.src) && (isDigit(p.src[p.pos]) || p.src[p.pos] == '.' || p.thousandsSeparator()) {
+			p.pos++
+		}
+		if exp := p.exponentLen(); exp > 0 {
+			p.pos += exp
+		}
+		text := strings.ReplaceAll(p.src[start:p.pos], ",", "")
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q", text)
+		}
+		if p.peek() == '%' && !p.operandFollows() {
+			p.pos++
+			v /= 100
+		}
+		return v, nil
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		start := p.pos
+		for p.pos < len(p.src) && (unicode.IsLetter(rune(p.src[p.pos])) || isDigit(p.src[p.pos])) {
+			p.pos++
+		}
+		return p.parseIdent(strings.ToLower(p.src[start:p.pos]))
+	case c == 0:
+		return 0, fmt.Errorf("unexpected end of expression")
+	}
+	return 0, fmt.Errorf("unexpected %q at position %d", c, p.pos)
+}
+
+// exponentLen returns the length of a scientific-notation exponent such as
+// "e-3" at pos, or 0 if there is none.
+func (p *arithParser) exponentLen() int {
+	rest := p.src[p.pos:]
+	if len(rest) < 2 || rest[0] != 'e' && rest[0] != 'E' {
+		return 0
+	}
+	n := 1
+	if rest[n] == '+' || rest[n] == '-' {
+		n++
+	}
+	start := n
+	for n < len(rest) && isDigit(rest[n]) {
+		n++
+	}
+	if n == start {
+		return 0
+	}
+	return n
+}
+
+// thousandsSeparator reports whether the byte at pos is a comma grouping
+// exactly three digits, as in "1,000". Other commas separate arguments.
+func (p *arithParser) thousandsSeparator() bool {
+	rest := p.src[p.pos:]
+	if len(rest) < 4 || rest[0] != ',' || !isDigit(rest[1]) || !isDigit(rest[2]) || !isDigit(rest[3]) {
+		return false
+	}
+	return len(rest) == 4 || !isDigit(rest[4])
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// operandFollows reports whether the '%' at pos is a binary modulo, i.e. it
+// is followed by another operand rather than ending a percentage literal.
+func (p *arithParser) operandFollows() bool {
+	rest := strings.TrimSpace(p.src[p.pos+1:])
+	if rest == "" {
+		return false
+	}
+	c := rest[0]
+	return isDigit(c) || c == '(' || c == '.' || unicode.IsLetter(rune(c))
+}
+
+var arithConstants = map[string]float64{"pi": math.Pi, "e": math.E}
+
+var arithFuncs = map[string]func(args []float64) (float64, error){
+	"sqrt":  unary(math.Sqrt),
+	"abs":   unary(math.Abs),
+	"ln":    unary(math.Log),
+	"log":   unary(math.Log10),
+	"exp":   unary(math.Exp),
+	"sin":   unary(math.Sin),
+	"cos":   unary(math.Cos),
+	"tan":   unary(math.Tan),
+	"floor": unary(math.Floor),
+	"ceil":  unary(math.Ceil),
+	"round": unary(math.Round),
+	"min": func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("min requires arguments")
+		}
+		m := args[0]
+		for _, a := range args[1:] {
+			m = math.Min(m, a)
+		}
+		return m, nil
+	},
+	"max": func(args []float64) (float64, error) {
+		if len(args) == 0 {
+			return 0, fmt.Errorf("max requires arguments")
+		}
+		m := args[0]
+		for _, a := range args[1:] {
+			m = math.Max(m, a)
+		}
+		return m, nil
+	},
+}
+
+func unary(f func(float64) float64) func([]float64) (float64, error) {
+	return func(args []float64) (float64, error) {
+		if len(args) != 1 {
+			return 0, fmt.Errorf("expected 1 argument, got %d", len(args))
+		}
+		return f(args[0]), nil
+	}
+}
+
+func (p *arithParser) parseIdent(name string) (float64, error) {
+	if v, ok := arithConstants[name]; ok {
+		return v, nil
+	}
+	fn, ok := arithFuncs[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown identifier %q", name)
+	}
+	if p.peek() != '(' {
+		return 0, fmt.Errorf("function %s requires arguments", name)
+	}
+	p.pos++
+
+	var args []float64
+	if p.peek() != ')' {
+		for {
+			v, err := p.parseSum()
+			if err != nil {
+				return 0, err
+			}
+			args = append(args, v)
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+		}
+	}
+	if p.peek() != ')' {
+		return 0, fmt.Errorf("missing closing parenthesis for %s", name)
+	}
+	p.pos++
+
+	v, err := fn(args)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", name, err)
+	}
+	return v, nil
+}
+
+// ============================================================================
+// Propositional Logic Evaluator
+// ============================================================================
+
+type logicExpr interface {
+	eval(env map[string]bool) bool
+	vars(into map[string]bool)
+}
+
+type (
+	logicVar   string
+	logicConst bool
+	logicNot   struct{ x logicExpr }
+	logicBin   struct {
+		op   string
+		l, r logicExpr
+	}
+)
+
+func (v logicVar) eval(env map[string]bool) bool { return env[string(v)] }
+func (v logicVar) vars(into map[string]bool)     { into[string(v)] = true }
+
+func (c logicConst) eval(map[string]bool) bool { return bool(c) }
+func (c logicConst) vars(map[string]bool)      {}
+
+func (n logicNot) eval(env map[string]bool) bool { return !n.x.eval(env) }
+func (n logicNot) vars(into map[string]bool)     { n.x.vars(into) }
+
+func (b logicBin) eval(env map[string]bool) bool {
+	l, r := b.l.eval(env), b.r.eval(env)
+	switch b.op {
+	case "AND":
+		return l && r
+	case "OR":
+		return l || r
+	case "XOR":
+		return l != r
+	case "IMPLIES":
+		return !l || r
+	case "IFF":
+		return l == r
+	}
+	return false
+}
+
+func (b logicBin) vars(into map[string]bool) {
+	b.l.vars(into)
+	b.r.vars(into)
+}
+
+var logicAliases = map[string]string{
+	"&&": "AND", "&": "AND", "∧": "AND",
+	"||": "OR", "|": "OR", "∨": "OR",
+	"!": "NOT", "~": "NOT", "¬": "NOT",
+	"->": "IMPLIES", "=>": "IMPLIES", "→": "IMPLIES",
+	"<->": "IFF", "<=>": "IFF", "↔": "IFF",
+	"^": "XOR", "⊕": "XOR",
+}
+
+func tokenizeLogic(src string) []string {
+	var tokens []string
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			word := string(runes[i:j])
+			switch upper := strings.ToUpper(word); upper {
+			case "AND", "OR", "NOT", "XOR", "IMPLIES", "IFF", "TRUE", "FALSE":
+				word = upper
+			}
+			tokens = append(tokens, word)
+			i = j
+		default:
+			matched := false
+			for _, n := range []int{3, 2, 1} {
+				if i+n <= len(runes) {
+					if op, ok := logicAliases[string(runes[i:i+n])]; ok {
+						tokens = append(tokens, op)
+						i += n
+						matched = true
+						break
+					}
+				}
+			}
+			if !matched {
+				tokens = append(tokens, string(r))
+				i++
+			}
+		}
+	}
+	return tokens
+}
+
+type logicParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *logicParser) next() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *logicParser) binary(op string, operand func() (logicExpr, error), rightAssoc bool) (logicExpr, error) {
+	l, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.next() == op {
+		p.pos++
+		var r logicExpr
+		if rightAssoc {
+			r, err = p.binary(op, operand, true)
+		} else {
+			r, err = operand()
+		}
+		if err != nil {
+			return nil, err
+		}
+		l = logicBin{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *logicParser) parseIff() (logicExpr, error) {
+	return p.binary("IFF", p.parseImplies, false)
+}
+
+func (p *logicParser) parseImplies() (logicExpr, error) {
+	return p.binary("IMPLIES", p.parseOr, true)
+}
+
+func (p *logicParser) parseOr() (logicExpr, error) {
+	return p.binary("OR", p.parseXor, false)
+}
+
+func (p *logicParser) parseXor() (logicExpr, error) {
+	return p.binary("XOR", p.parseAnd, false)
+}
+
+func (p *logicParser) parseAnd() (logicExpr, error) {
+	return p.binary("AND", p.parseNot, false)
+}
+
+func (p *logicParser) parseNot() (logicExpr, error) {
+	if p.next() == "NOT" {
+		p.pos++
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return logicNot{x}, nil
+	}
+	return p.parseAtom()
+}
+
+func (p *logicParser) parseAtom() (logicExpr, error) {
+	tok := p.next()
+	switch tok {
+	case "":
+		return nil, fmt.Errorf("unexpected end of logic expression")
+	case "(":
+		p.pos++
+		x, err := p.parseIff()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return x, nil
+	case "TRUE", "FALSE":
+		p.pos++
+		return logicConst(tok == "TRUE"), nil
+	}
+
+	r := []rune(tok)[0]
+	if !unicode.IsLetter(r) && r != '_' {
+		return nil, fmt.Errorf("unexpected %q in logic expression", tok)
+	}
+	p.pos++
+	return logicVar(tok), nil
+}
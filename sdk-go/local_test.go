@@ -0,0 +1,96 @@
+package qwed
+
+import (
+	"testing"
+)
+
+func TestLocalVerifyMath(t *testing.T) {
+	tests := []struct {
+		expr     string
+		verified bool
+	}{
+		{"2 + 2 = 4", true},
+		{"2 + 2 = 5", false},
+		{"2 * (3 + 4) == 14", true},
+		{"2^10 = 1024", true},
+		{"-3 ** 2 = -9", true},
+		{"0.1 + 0.2 = 0.3", true},
+		{"1,000 * 3 = 3000", true},
+		{"15% * 200 = 30", true},
+		{"10 % 3 = 1", true},
+		{"sqrt(16) + max(1, 7) = 11", true},
+		{"pi > 3.14", true},
+		{"1e3 >= 999", true},
+		{"7 ÷ 2 ≠ 3", true},
+		{"5 < 5", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			resp, err := localVerifyMath(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Verified != tt.verified {
+				t.Errorf("expected verified=%v, got %v (%v)", tt.verified, resp.Verified, resp.Result)
+			}
+			if resp.Engine != EngineLocalMath {
+				t.Errorf("expected engine %s, got %s", EngineLocalMath, resp.Engine)
+			}
+		})
+	}
+}
+
+func TestLocalVerifyMathBareExpression(t *testing.T) {
+	resp, err := localVerifyMath("2+2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Verified || resp.Status != StatusUnsupported {
+		t.Errorf("expected an unverified, unsupported response, got %s verified=%v", resp.Status, resp.Verified)
+	}
+	if resp.Result["value"] != 4.0 {
+		t.Errorf("expected value 4, got %v", resp.Result["value"])
+	}
+}
+
+func TestLocalVerifyMathErrors(t *testing.T) {
+	for _, expr := range []string{"", "1 / 0 = 1", "2 + = 4", "foo(2) = 2", "(1 + 2 = 3"} {
+		if _, err := localVerifyMath(expr); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestLocalVerifyLogic(t *testing.T) {
+	tests := []struct {
+		query    string
+		verified bool
+	}{
+		{"(A AND B) implies B", true},
+		{"A or not A", true},
+		{"A -> B", false},
+		{"(P -> Q) && P -> Q", true},
+		{"(A <-> B) iff ((A -> B) and (B -> A))", true},
+		{"TRUE", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			resp, err := localVerifyLogic(tt.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Verified != tt.verified {
+				t.Errorf("expected verified=%v, got %v", tt.verified, resp.Verified)
+			}
+			if !tt.verified && resp.Result["counterexample"] == nil {
+				t.Error("expected counterexample")
+			}
+		})
+	}
+
+	if _, err := localVerifyLogic("(A AND"); err == nil {
+		t.Error("expected parse error")
+	}
+}
@@ -0,0 +1,73 @@
+package qwed
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ============================================================================
+// Logging
+// ============================================================================
+
+// WithLogger makes the client log to logger. API calls are logged at debug
+// level when they start and finish and at warn level when they fail; cache
+// hits and rate limit and engine queue waits at debug level; retries at
+// info level; and failovers at warn level. Choose what is emitted with the
+// handler's level:
+//
+//	handler := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})
+//	client := qwed.NewClient(apiKey, qwed.WithLogger(slog.New(handler)))
+//
+// Without a logger the client is silent.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// log emits a record if the client has a logger enabled for level.
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.logger == nil || !c.logger.Enabled(ctx, level) {
+		return
+	}
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// startLog logs the start of op and returns a function that logs its
+// outcome. Without a logger both are no-ops.
+func (c *Client) startLog(ctx context.Context, op string, engine VerificationType) func(*VerificationResponse, error) {
+	if c.logger == nil {
+		return func(*VerificationResponse, error) {}
+	}
+
+	attrs := []slog.Attr{slog.String("op", op)}
+	if engine != "" {
+		attrs = append(attrs, slog.String("engine", string(engine)))
+	}
+	c.log(ctx, slog.LevelDebug, "qwed request started", attrs...)
+
+	start := time.Now()
+	return func(resp *VerificationResponse, err error) {
+		attrs := append(attrs, slog.Duration("latency", time.Since(start)))
+		if err != nil {
+			if code := statusCode(err); code != 0 {
+				attrs = append(attrs, slog.Int("status_code", code))
+			}
+			c.log(ctx, slog.LevelWarn, "qwed request failed", append(attrs, slog.Any("error", err))...)
+			return
+		}
+		if resp != nil {
+			attrs = append(attrs, slog.String("status", string(resp.Status)), slog.Bool("verified", resp.Verified))
+		}
+		c.log(ctx, slog.LevelDebug, "qwed request finished", attrs...)
+	}
+}
+
+// logWait logs a wait of at least a millisecond for the rate limiter or an
+// engine queue.
+func (c *Client) logWait(ctx context.Context, msg string, start time.Time, attrs ...slog.Attr) {
+	if waited := time.Since(start); waited >= time.Millisecond {
+		c.log(ctx, slog.LevelDebug, msg, append(attrs, slog.Duration("wait", waited))...)
+	}
+}
@@ -0,0 +1,75 @@
+package qwed
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithLogger(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "logic") {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"BAD_QUERY","message":"cannot parse"}}`))
+			return
+		}
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient("test-key", WithBaseURL(server.URL), WithLogger(logger),
+		WithCache(NewLRUCache(10), time.Minute), WithRateLimit(5, 1))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.VerifyMath(ctx, "2+2=4"); err != nil {
+			t.Fatalf("VerifyMath() error = %v", err)
+		}
+	}
+	// Take the bucket's token, so the next request waits about 200ms
+	// however long the calls above took.
+	for !client.limiter.Allow() {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := client.VerifyLogic(ctx, "(A"); err == nil {
+		t.Fatal("expected error")
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		`level=DEBUG msg="qwed request started" op=VerifyMath engine=math`,
+		`level=DEBUG msg="qwed request finished" op=VerifyMath engine=math latency=`,
+		`status=VERIFIED verified=true`,
+		`level=DEBUG msg="qwed cache hit" op=VerifyMath engine=math`,
+		`level=DEBUG msg="qwed rate limit wait" path=/verify/logic wait=`,
+		`level=WARN msg="qwed request failed" op=VerifyLogic engine=logic latency=`,
+		`status_code=400`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWithLoggerLevel(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	client := NewClient("test-key", WithBaseURL(server.URL), WithLogger(logger))
+	if _, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil {
+		t.Fatalf("VerifyMath() error = %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected no records above debug level, got:\n%s", buf.String())
+	}
+}
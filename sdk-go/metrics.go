@@ -0,0 +1,227 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Metrics
+// ============================================================================
+
+// RequestMetric describes a completed API call.
+type RequestMetric struct {
+	Op         string        // public method name, e.g. "VerifyMath"
+	Engine     string        // verification engine, empty for non-engine calls
+	StatusCode int           // HTTP status; 0 if the request never completed
+	Latency    time.Duration // wall time including cache lookups
+	Err        error
+
+	// BudgetExceeded is set when the call was abandoned for exceeding the
+	// client's latency budget.
+	BudgetExceeded bool
+}
+
+// Collector receives client metrics. Implementations must be safe for
+// concurrent use.
+type Collector interface {
+	RecordRequest(m RequestMetric)
+	RecordBatch(items int, duration time.Duration, err error)
+}
+
+// WithMetrics reports every API call to the collector.
+func WithMetrics(collector Collector) ClientOption {
+	return func(c *Client) {
+		c.metrics = collector
+	}
+}
+
+// instrument starts tracing, logging and timing for an API call. The returned
+// function must be called with the outcome.
+func (c *Client) instrument(ctx context.Context, op string, engine VerificationType) (context.Context, func(*VerificationResponse, error)) {
+	ctx, endSpan := c.startSpan(ctx, op, engine)
+	endLog := c.startLog(ctx, op, engine)
+	end := func(resp *VerificationResponse, err error) {
+		endLog(resp, err)
+		endSpan(resp, err)
+	}
+	if c.metrics == nil {
+		return ctx, end
+	}
+
+	start := time.Now()
+	return ctx, func(resp *VerificationResponse, err error) {
+		end(resp, err)
+		c.metrics.RecordRequest(RequestMetric{
+			Op:             op,
+			Engine:         string(engine),
+			StatusCode:     statusCode(err),
+			Latency:        time.Since(start),
+			Err:            err,
+			BudgetExceeded: IsInconclusive(resp) || errors.Is(err, ErrBudgetExceeded),
+		})
+	}
+}
+
+// statusCode returns the HTTP status for the outcome of a call: 200 on
+// success, the API status for a QWEDError, and 0 for transport failures.
+func statusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var qwedErr *QWEDError
+	if errors.As(err, &qwedErr) {
+		return qwedErr.StatusCode
+	}
+	return 0
+}
+
+// ============================================================================
+// Prometheus Collector
+// ============================================================================
+
+// DefaultLatencyBuckets are the histogram bounds, in seconds, used by
+// PrometheusCollector.
+var DefaultLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// PrometheusCollector is a Collector that serves its metrics in the
+// Prometheus text exposition format. Mount it on a metrics endpoint:
+//
+//	collector := qwed.NewPrometheusCollector()
+//	client := qwed.NewClient(key, qwed.WithMetrics(collector))
+//	http.Handle("/metrics", collector)
+type PrometheusCollector struct {
+	mu       sync.Mutex
+	buckets  []float64
+	requests map[[3]string]uint64 // op, engine, code
+	latency  map[string]*histogram
+	batches  map[string]*histogram // "ok" or "error"
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, non-cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusCollector creates a collector using DefaultLatencyBuckets.
+func NewPrometheusCollector() *PrometheusCollector {
+	return &PrometheusCollector{
+		buckets:  DefaultLatencyBuckets,
+		requests: make(map[[3]string]uint64),
+		latency:  make(map[string]*histogram),
+		batches:  make(map[string]*histogram),
+	}
+}
+
+// RecordRequest implements Collector.
+func (p *PrometheusCollector) RecordRequest(m RequestMetric) {
+	code := "error"
+	switch {
+	case m.BudgetExceeded:
+		code = "budget_exceeded"
+	case m.StatusCode != 0:
+		code = strconv.Itoa(m.StatusCode)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests[[3]string{m.Op, m.Engine, code}]++
+	if m.Engine != "" {
+		p.observe(p.latency, m.Engine, m.Latency)
+	}
+}
+
+// RecordBatch implements Collector.
+func (p *PrometheusCollector) RecordBatch(items int, duration time.Duration, err error) {
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.observe(p.batches, outcome, duration)
+}
+
+func (p *PrometheusCollector) observe(hs map[string]*histogram, label string, d time.Duration) {
+	h := hs[label]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		hs[label] = h
+	}
+
+	v := d.Seconds()
+	for i, bound := range p.buckets {
+		if v <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// ServeHTTP writes the metrics in Prometheus text format.
+func (p *PrometheusCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	p.WriteTo(w)
+}
+
+// WriteTo writes the metrics in Prometheus text format to w.
+func (p *PrometheusCollector) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP qwed_requests_total QWED API calls by method, engine and status code.\n")
+	b.WriteString("# TYPE qwed_requests_total counter\n")
+	keys := make([][3]string, 0, len(p.requests))
+	for k := range p.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return strings.Join(keys[i][:], "\x00") < strings.Join(keys[j][:], "\x00")
+	})
+	for _, k := range keys {
+		fmt.Fprintf(&b, "qwed_requests_total{method=%q,engine=%q,code=%q} %d\n", k[0], k[1], k[2], p.requests[k])
+	}
+
+	p.writeHistogram(&b, "qwed_request_duration_seconds", "QWED API call latency by engine.", "engine", p.latency)
+	p.writeHistogram(&b, "qwed_batch_duration_seconds", "QWED batch submission duration by outcome.", "outcome", p.batches)
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (p *PrometheusCollector) writeHistogram(b *strings.Builder, name, help, label string, hs map[string]*histogram) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+
+	labels := make([]string, 0, len(hs))
+	for l := range hs {
+		labels = append(labels, l)
+	}
+	sort.Strings(labels)
+
+	for _, l := range labels {
+		h := hs[l]
+		var cumulative uint64
+		for i, bound := range p.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(b, "%s_bucket{%s=%q,le=%q} %d\n", name, label, l, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(b, "%s_bucket{%s=%q,le=\"+Inf\"} %d\n", name, label, l, h.count)
+		fmt.Fprintf(b, "%s_sum{%s=%q} %g\n", name, label, l, h.sum)
+		fmt.Fprintf(b, "%s_count{%s=%q} %d\n", name, label, l, h.count)
+	}
+}
@@ -0,0 +1,57 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPrometheusCollector(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/verify/code" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	})
+	defer server.Close()
+
+	collector := NewPrometheusCollector()
+	client := NewClient("test-key", WithBaseURL(server.URL), WithMetrics(collector))
+
+	client.VerifyMath(context.Background(), "2 + 2 = 4")
+	client.VerifyMath(context.Background(), "3 * 3 = 9")
+	client.VerifyCode(context.Background(), "x", "python")
+	client.VerifyBatch(context.Background(), []BatchItem{{Query: "1 + 1 = 2"}}, nil)
+
+	rec := httptest.NewRecorder()
+	collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, want := range []string{
+		`qwed_requests_total{method="VerifyMath",engine="math",code="200"} 2`,
+		`qwed_requests_total{method="VerifyCode",engine="code",code="429"} 1`,
+		`qwed_request_duration_seconds_count{engine="math"} 2`,
+		`qwed_request_duration_seconds_bucket{engine="math",le="+Inf"} 2`,
+		`qwed_batch_duration_seconds_count{outcome="ok"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("missing %q in output:\n%s", want, body)
+		}
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	if statusCode(nil) != 200 {
+		t.Error("expected 200 for nil error")
+	}
+	if statusCode(&QWEDError{StatusCode: 503}) != 503 {
+		t.Error("expected QWEDError status")
+	}
+	if statusCode(context.Canceled) != 0 {
+		t.Error("expected 0 for transport error")
+	}
+}
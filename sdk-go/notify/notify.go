@@ -0,0 +1,464 @@
+// Package notify posts alerts about verification failures to Slack and
+// Microsoft Teams incoming webhooks. Bursts of failures are grouped into
+// one message, and each webhook is posted to at most once per window, so
+// a failing pipeline or batch job does not flood the channel.
+//
+//	alerts := notify.NewSlack(os.Getenv("SLACK_WEBHOOK_URL"),
+//	    notify.WithSource("nightly-kb-audit"),
+//	    notify.WithLinkTemplate("https://qwed.example.com/verifications/{request_id}"))
+//	defer alerts.Flush(context.Background())
+//
+//	client := qwed.NewClient(key, qwed.WithInterceptor(alerts.Interceptor()))
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// Alert is a single verification failure.
+type Alert struct {
+	Claim     string // the verified text
+	Engine    string
+	Verdict   qwed.Verdict
+	Severity  string // qwed.SeverityCritical, SeverityWarning or SeverityInfo
+	Reason    string
+	RequestID string
+	Link      string // link to the stored verification, if configured
+	Time      time.Time
+}
+
+// Group is a set of alerts with the same engine, verdict and severity.
+type Group struct {
+	Engine   string
+	Verdict  qwed.Verdict
+	Severity string
+	Count    int
+	Samples  []Alert // the first alerts of the group, up to the sample limit
+}
+
+// Digest is the content of one webhook message.
+type Digest struct {
+	Source     string // the pipeline or job, from WithSource
+	Groups     []Group
+	Total      int // alerts in the digest
+	Dropped    int // alerts dropped because too many were pending
+	Start, End time.Time
+}
+
+// Format renders a digest as a webhook payload.
+type Format func(Digest) ([]byte, error)
+
+const (
+	defaultWindow     = time.Minute
+	defaultSamples    = 5
+	defaultMaxPending = 1000
+	maxClaimLength    = 300
+)
+
+// ============================================================================
+// Notifier
+// ============================================================================
+
+// Notifier posts alerts to one webhook. The first alert after a quiet
+// period is posted immediately; alerts arriving within the window after a
+// post are grouped into the next one. It is safe for concurrent use.
+type Notifier struct {
+	url    string
+	format Format
+	opts   options
+
+	mu      sync.Mutex
+	pending []Alert
+	dropped int
+	last    time.Time
+	timer   *time.Timer
+}
+
+type options struct {
+	httpClient  *http.Client
+	minSeverity string
+	window      time.Duration
+	samples     int
+	maxPending  int
+	source      string
+	link        func(Alert) string
+	onError     func(error)
+}
+
+// Option configures a Notifier.
+type Option func(*options)
+
+// WithHTTPClient sets the client used to post to the webhook.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+// WithMinSeverity only alerts on failures at or above severity. Refuted
+// claims are critical, except code with only warning findings; inconclusive
+// results are info. Defaults to qwed.SeverityCritical.
+func WithMinSeverity(severity string) Option {
+	return func(o *options) {
+		o.minSeverity = strings.ToUpper(severity)
+	}
+}
+
+// WithWindow sets the minimum time between posts; alerts arriving in
+// between are grouped. Defaults to one minute.
+func WithWindow(window time.Duration) Option {
+	return func(o *options) {
+		o.window = window
+	}
+}
+
+// WithSamples sets how many alerts of each group are listed in a message.
+// Defaults to 5.
+func WithSamples(n int) Option {
+	return func(o *options) {
+		o.samples = n
+	}
+}
+
+// WithMaxPending caps the alerts held for the next post; further alerts are
+// counted as dropped. Defaults to 1000.
+func WithMaxPending(n int) Option {
+	return func(o *options) {
+		o.maxPending = n
+	}
+}
+
+// WithSource names the pipeline or job in message titles.
+func WithSource(source string) Option {
+	return func(o *options) {
+		o.source = source
+	}
+}
+
+// WithLinkTemplate links each alert to the stored verification. The
+// template's "{request_id}" is replaced with the response's request ID;
+// alerts without one are not linked.
+func WithLinkTemplate(template string) Option {
+	return WithLink(func(a Alert) string {
+		if a.RequestID == "" {
+			return ""
+		}
+		return strings.ReplaceAll(template, "{request_id}", a.RequestID)
+	})
+}
+
+// WithLink sets a function returning the link for an alert.
+func WithLink(link func(Alert) string) Option {
+	return func(o *options) {
+		o.link = link
+	}
+}
+
+// WithErrorHandler is called when posting to the webhook fails. Alerts are
+// not retried.
+func WithErrorHandler(fn func(error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// New creates a notifier posting to webhookURL in the given format.
+func New(webhookURL string, format Format, opts ...Option) *Notifier {
+	o := options{
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+		minSeverity: qwed.SeverityCritical,
+		window:      defaultWindow,
+		samples:     defaultSamples,
+		maxPending:  defaultMaxPending,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Notifier{url: webhookURL, format: format, opts: o}
+}
+
+// NewSlack creates a notifier for a Slack incoming webhook.
+func NewSlack(webhookURL string, opts ...Option) *Notifier {
+	return New(webhookURL, Slack, opts...)
+}
+
+// NewTeams creates a notifier for a Microsoft Teams incoming webhook or
+// workflow.
+func NewTeams(webhookURL string, opts ...Option) *Notifier {
+	return New(webhookURL, Teams, opts...)
+}
+
+// Notify queues alert if it meets the minimum severity.
+func (n *Notifier) Notify(alert Alert) {
+	if severityRank(alert.Severity) < severityRank(n.opts.minSeverity) {
+		return
+	}
+	if alert.Time.IsZero() {
+		alert.Time = time.Now()
+	}
+	if alert.Link == "" && n.opts.link != nil {
+		alert.Link = n.opts.link(alert)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.pending) >= n.opts.maxPending {
+		n.dropped++
+		return
+	}
+	n.pending = append(n.pending, alert)
+	if n.timer == nil {
+		delay := time.Until(n.last.Add(n.opts.window))
+		n.timer = time.AfterFunc(max(delay, 0), func() {
+			if err := n.Flush(context.Background()); err != nil && n.opts.onError != nil {
+				n.opts.onError(err)
+			}
+		})
+	}
+}
+
+// NotifyResponse alerts on resp if it is not verified. claim is the text
+// that was verified.
+func (n *Notifier) NotifyResponse(claim string, engine qwed.VerificationType, resp *qwed.VerificationResponse) {
+	if resp == nil || resp.Verdict() == qwed.VerdictVerified {
+		return
+	}
+	alert := Alert{
+		Claim:    claim,
+		Engine:   string(engine),
+		Verdict:  resp.Verdict(),
+		Severity: severity(resp),
+		Reason:   reason(resp),
+	}
+	if resp.Engine != "" {
+		alert.Engine = resp.Engine.String()
+	}
+	if resp.Metadata != nil {
+		alert.RequestID = resp.Metadata.RequestID
+	}
+	n.Notify(alert)
+}
+
+// NotifyBatch alerts on the failed items of a batch job. items are the
+// items the batch was submitted with, in order.
+func (n *Notifier) NotifyBatch(items []qwed.BatchItem, batch *qwed.BatchResponse) {
+	if batch == nil {
+		return
+	}
+	for i, item := range batch.Items {
+		var claim string
+		var engine qwed.VerificationType
+		if i < len(items) {
+			claim, engine = items[i].Query, items[i].Type
+		}
+		resp := &qwed.VerificationResponse{Status: item.Status, Verified: item.Verified, Result: item.Result, Error: item.Error}
+		if item.ID != "" {
+			resp.Metadata = &qwed.ResponseMetadata{RequestID: item.ID}
+		}
+		n.NotifyResponse(claim, engine, resp)
+	}
+}
+
+// Interceptor returns a client interceptor alerting on every verification
+// call that is not verified. Calls that return an error are not alerted
+// on; they are reported to the caller.
+func (n *Notifier) Interceptor() qwed.Interceptor {
+	return func(ctx context.Context, req *qwed.Request, next qwed.Invoker) (*qwed.VerificationResponse, error) {
+		resp, err := next(ctx, req)
+		if err == nil && resp != nil && resp.Status != qwed.StatusShadow {
+			n.NotifyResponse(claimText(req.Body), req.Engine, resp)
+		}
+		return resp, err
+	}
+}
+
+// Flush posts pending alerts now, ignoring the window. Call it before a
+// batch job exits.
+func (n *Notifier) Flush(ctx context.Context) error {
+	n.mu.Lock()
+	if n.timer != nil {
+		n.timer.Stop()
+		n.timer = nil
+	}
+	pending, dropped := n.pending, n.dropped
+	n.pending, n.dropped = nil, 0
+	if len(pending) == 0 && dropped == 0 {
+		n.mu.Unlock()
+		return nil
+	}
+	n.last = time.Now()
+	n.mu.Unlock()
+
+	payload, err := n.format(digest(n.opts.source, pending, dropped, n.opts.samples))
+	if err != nil {
+		return fmt.Errorf("failed to format alert: %w", err)
+	}
+	return n.post(ctx, payload)
+}
+
+func (n *Notifier) post(ctx context.Context, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", n.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.opts.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("failed to post alert: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// ============================================================================
+// Grouping
+// ============================================================================
+
+// digest groups alerts by engine, verdict and severity, most severe and
+// most frequent first.
+func digest(source string, alerts []Alert, dropped, samples int) Digest {
+	d := Digest{Source: source, Total: len(alerts), Dropped: dropped}
+	index := make(map[string]int)
+	for _, a := range alerts {
+		if d.Start.IsZero() || a.Time.Before(d.Start) {
+			d.Start = a.Time
+		}
+		if a.Time.After(d.End) {
+			d.End = a.Time
+		}
+		key := a.Engine + "\x00" + string(a.Verdict) + "\x00" + a.Severity
+		i, ok := index[key]
+		if !ok {
+			i = len(d.Groups)
+			index[key] = i
+			d.Groups = append(d.Groups, Group{Engine: a.Engine, Verdict: a.Verdict, Severity: a.Severity})
+		}
+		g := &d.Groups[i]
+		g.Count++
+		if len(g.Samples) < samples {
+			g.Samples = append(g.Samples, a)
+		}
+	}
+	sort.SliceStable(d.Groups, func(i, j int) bool {
+		if ri, rj := severityRank(d.Groups[i].Severity), severityRank(d.Groups[j].Severity); ri != rj {
+			return ri > rj
+		}
+		return d.Groups[i].Count > d.Groups[j].Count
+	})
+	return d
+}
+
+// severity classifies a failed response.
+func severity(resp *qwed.VerificationResponse) string {
+	if resp.Verdict() != qwed.VerdictRefuted {
+		return qwed.SeverityInfo
+	}
+	findings := qwed.CodeFindings(resp)
+	if len(findings) == 0 {
+		return qwed.SeverityCritical
+	}
+	worst := qwed.SeverityInfo
+	for _, f := range findings {
+		if !f.Suppressed && severityRank(f.Severity) > severityRank(worst) {
+			worst = f.Severity
+		}
+	}
+	return worst
+}
+
+// reason explains a failed response.
+func reason(resp *qwed.VerificationResponse) string {
+	if resp.Error != nil && resp.Error.Message != "" {
+		return resp.Error.Message
+	}
+	for _, key := range []string{"reason", "error", "explanation", "message"} {
+		if s, ok := resp.Result[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	if findings := qwed.CodeFindings(resp); len(findings) > 0 {
+		return findings[0].Type + ": " + findings[0].Description
+	}
+	return string(resp.InconclusiveReason())
+}
+
+// claimText extracts the verified text from a request body.
+func claimText(body interface{}) string {
+	var fields map[string]interface{}
+	data, err := json.Marshal(body)
+	if err != nil || json.Unmarshal(data, &fields) != nil {
+		return ""
+	}
+	for _, name := range []string{"claim", "expression", "query", "code", "json"} {
+		if s, ok := fields[name].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case qwed.SeverityCritical:
+		return 3
+	case qwed.SeverityWarning:
+		return 2
+	case qwed.SeverityInfo:
+		return 1
+	}
+	return 0
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+// title describes a digest in one line.
+func title(d Digest) string {
+	count, noun := d.Total+d.Dropped, "failures"
+	if count == 1 {
+		noun = "failure"
+	}
+	t := fmt.Sprintf("QWED: %d verification %s", count, noun)
+	if d.Source != "" {
+		t += " in " + d.Source
+	}
+	return t
+}
+
+// footer summarizes the digest's time span and dropped alerts.
+func footer(d Digest) string {
+	var parts []string
+	if !d.Start.IsZero() {
+		parts = append(parts, fmt.Sprintf("%s – %s UTC", d.Start.UTC().Format("2006-01-02 15:04:05"), d.End.UTC().Format("15:04:05")))
+	}
+	if d.Dropped > 0 {
+		parts = append(parts, fmt.Sprintf("%d alerts dropped by the rate limit", d.Dropped))
+	}
+	return strings.Join(parts, " · ")
+}
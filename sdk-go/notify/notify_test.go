@@ -0,0 +1,175 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// webhook records the payloads posted to it.
+type webhook struct {
+	*httptest.Server
+	mu    sync.Mutex
+	posts []string
+	got   chan struct{}
+}
+
+func newWebhook(t *testing.T) *webhook {
+	w := &webhook{got: make(chan struct{}, 100)}
+	w.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var body json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("webhook received invalid JSON: %v", err)
+		}
+		w.mu.Lock()
+		w.posts = append(w.posts, string(body))
+		w.mu.Unlock()
+		w.got <- struct{}{}
+	}))
+	t.Cleanup(w.Close)
+	return w
+}
+
+func (w *webhook) wait(t *testing.T) string {
+	t.Helper()
+	select {
+	case <-w.got:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook post")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.posts[len(w.posts)-1]
+}
+
+func (w *webhook) count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.posts)
+}
+
+func TestNotifierGroupsWithinWindow(t *testing.T) {
+	hook := newWebhook(t)
+	n := NewSlack(hook.URL, WithWindow(time.Hour), WithSamples(2), WithSource("nightly"))
+
+	n.Notify(Alert{Claim: "first", Engine: "math", Verdict: qwed.VerdictRefuted, Severity: qwed.SeverityCritical})
+	if post := hook.wait(t); !strings.Contains(post, "1 verification failure in nightly") {
+		t.Errorf("expected the first alert to be posted immediately, got %s", post)
+	}
+
+	for _, claim := range []string{"a", "b", "c"} {
+		n.Notify(Alert{Claim: claim, Engine: "fact", Verdict: qwed.VerdictRefuted, Severity: qwed.SeverityCritical})
+	}
+	n.Notify(Alert{Claim: "d", Engine: "math", Verdict: qwed.VerdictRefuted, Severity: qwed.SeverityCritical})
+	n.Notify(Alert{Claim: "low", Engine: "math", Verdict: qwed.VerdictInconclusive, Severity: qwed.SeverityInfo})
+
+	time.Sleep(50 * time.Millisecond)
+	if hook.count() != 1 {
+		t.Fatalf("expected alerts within the window to be held, got %d posts", hook.count())
+	}
+
+	if err := n.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	post := hook.wait(t)
+	if !strings.Contains(post, "4 verification failures") || strings.Contains(post, "low") {
+		t.Errorf("expected 4 grouped alerts without the info one, got %s", post)
+	}
+	if !strings.Contains(post, "and 1 more") || strings.Index(post, "*fact*") > strings.Index(post, "*math*") {
+		t.Errorf("expected the larger fact group first with 2 samples, got %s", post)
+	}
+}
+
+func TestNotifierDropsWhenFull(t *testing.T) {
+	hook := newWebhook(t)
+	n := NewSlack(hook.URL, WithWindow(time.Hour), WithMaxPending(2))
+	n.mu.Lock()
+	n.last = time.Now() // inside the window, so nothing is posted yet
+	n.mu.Unlock()
+
+	for i := 0; i < 5; i++ {
+		n.Notify(Alert{Claim: "x", Engine: "math", Verdict: qwed.VerdictRefuted, Severity: qwed.SeverityCritical})
+	}
+	if err := n.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if post := hook.wait(t); !strings.Contains(post, "5 verification failures") || !strings.Contains(post, "3 alerts dropped") {
+		t.Errorf("expected dropped alerts to be reported, got %s", post)
+	}
+}
+
+func TestNotifierInterceptor(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		switch req["code"] {
+		case "":
+			w.Write([]byte(`{"status":"FAILED","verified":false,"engine":"math","result":{"reason":"2+2 is 4"},"metadata":{"request_id":"req-1"}}`))
+		default:
+			w.Write([]byte(`{"status":"FAILED","verified":false,"engine":"code","result":{"issues":[{"severity":"WARNING","type":"weak_hash"}]}}`))
+		}
+	}))
+	defer api.Close()
+
+	hook := newWebhook(t)
+	n := NewTeams(hook.URL, WithLinkTemplate("https://qwed.example.com/v/{request_id}"))
+	client := qwed.NewClient("test-key", qwed.WithBaseURL(api.URL), qwed.WithInterceptor(n.Interceptor()))
+
+	client.VerifyCode(context.Background(), "import md5", "python") // warning only, below the minimum
+	client.VerifyMath(context.Background(), "2+2=5")
+
+	post := hook.wait(t)
+	for _, want := range []string{"2+2=5", "2+2 is 4", "https://qwed.example.com/v/req-1"} {
+		if !strings.Contains(post, want) {
+			t.Errorf("expected %q in the alert, got %s", want, post)
+		}
+	}
+	if strings.Contains(post, "import md5") {
+		t.Errorf("expected the warning-level failure to be filtered, got %s", post)
+	}
+}
+
+func TestNotifyBatch(t *testing.T) {
+	hook := newWebhook(t)
+	var errs []error
+	n := NewSlack(hook.URL, WithWindow(time.Hour), WithErrorHandler(func(err error) { errs = append(errs, err) }))
+	n.mu.Lock()
+	n.last = time.Now()
+	n.mu.Unlock()
+
+	items := []qwed.BatchItem{{Query: "2+2=4", Type: qwed.TypeMath}, {Query: "2+2=5", Type: qwed.TypeMath}}
+	n.NotifyBatch(items, &qwed.BatchResponse{Items: []qwed.BatchResult{
+		{ID: "0", Status: qwed.StatusVerified, Verified: true},
+		{ID: "1", Status: qwed.StatusFailed, Result: map[string]interface{}{"reason": "expected 4"}},
+	}})
+	n.Flush(context.Background())
+
+	post := hook.wait(t)
+	if !strings.Contains(post, "2+2=5") || strings.Contains(post, "2+2=4") || !strings.Contains(post, "expected 4") {
+		t.Errorf("expected only the failed item, got %s", post)
+	}
+	if len(errs) != 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestNotifierPostError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	n := NewSlack(server.URL)
+	n.Notify(Alert{Claim: "x", Severity: qwed.SeverityCritical})
+	err := n.Flush(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("expected the webhook error, got %v", err)
+	}
+}
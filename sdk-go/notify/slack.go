@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Slack
+// ============================================================================
+
+// Slack formats digests as Slack Block Kit messages.
+func Slack(d Digest) ([]byte, error) {
+	type text struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	type block struct {
+		Type     string `json:"type"`
+		Text     *text  `json:"text,omitempty"`
+		Elements []text `json:"elements,omitempty"`
+	}
+
+	blocks := []block{{Type: "header", Text: &text{Type: "plain_text", Text: title(d)}}}
+	for _, g := range d.Groups {
+		var b strings.Builder
+		fmt.Fprintf(&b, "*%s* · %s · %s · %d", slackEscape(g.Engine), g.Verdict, g.Severity, g.Count)
+		for _, a := range g.Samples {
+			claim := "`" + slackEscape(truncate(a.Claim, maxClaimLength)) + "`"
+			if a.Link != "" {
+				claim += " <" + a.Link + "|view>"
+			}
+			fmt.Fprintf(&b, "\n• %s", claim)
+			if a.Reason != "" {
+				fmt.Fprintf(&b, "\n    _%s_", slackEscape(truncate(a.Reason, maxClaimLength)))
+			}
+		}
+		if more := g.Count - len(g.Samples); more > 0 {
+			fmt.Fprintf(&b, "\n…and %d more", more)
+		}
+		blocks = append(blocks, block{Type: "section", Text: &text{Type: "mrkdwn", Text: b.String()}})
+	}
+	if footer := footer(d); footer != "" {
+		blocks = append(blocks, block{Type: "context", Elements: []text{{Type: "mrkdwn", Text: footer}}})
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"text":   title(d),
+		"blocks": blocks,
+	})
+}
+
+// slackEscape escapes the characters Slack treats as markup.
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "`", "'").Replace(s)
+}
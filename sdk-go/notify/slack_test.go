@@ -0,0 +1,41 @@
+package notify
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+func TestSlackFormat(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	payload, err := Slack(digest("etl", []Alert{
+		{Claim: "revenue <b>grew</b> 12%", Engine: "fact", Verdict: qwed.VerdictRefuted, Severity: qwed.SeverityCritical,
+			Reason: "source says 8%", Link: "https://qwed.example.com/v/1", Time: at},
+	}, 0, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var msg struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Type string `json:"type"`
+			Text struct {
+				Text string `json:"text"`
+			} `json:"text"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Text != "QWED: 1 verification failure in etl" || len(msg.Blocks) != 3 {
+		t.Fatalf("unexpected message: %s", payload)
+	}
+	section := msg.Blocks[1].Text.Text
+	if !strings.Contains(section, "revenue &lt;b&gt;grew&lt;/b&gt; 12%") || !strings.Contains(section, "<https://qwed.example.com/v/1|view>") {
+		t.Errorf("expected an escaped claim and a link, got %q", section)
+	}
+}
@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ============================================================================
+// Microsoft Teams
+// ============================================================================
+
+// Teams formats digests as Adaptive Cards, accepted by Teams incoming
+// webhooks and workflows.
+func Teams(d Digest) ([]byte, error) {
+	body := []map[string]interface{}{{
+		"type":   "TextBlock",
+		"text":   title(d),
+		"size":   "Large",
+		"weight": "Bolder",
+		"color":  "Attention",
+		"wrap":   true,
+	}}
+	for _, g := range d.Groups {
+		body = append(body, map[string]interface{}{
+			"type":      "TextBlock",
+			"text":      fmt.Sprintf("**%s** · %s · %s · %d", g.Engine, g.Verdict, g.Severity, g.Count),
+			"separator": true,
+			"wrap":      true,
+		})
+		for _, a := range g.Samples {
+			facts := []map[string]string{{"title": "Claim", "value": truncate(a.Claim, maxClaimLength)}}
+			if a.Reason != "" {
+				facts = append(facts, map[string]string{"title": "Reason", "value": truncate(a.Reason, maxClaimLength)})
+			}
+			if a.Link != "" {
+				facts = append(facts, map[string]string{"title": "Details", "value": "[View verification](" + a.Link + ")"})
+			}
+			body = append(body, map[string]interface{}{"type": "FactSet", "facts": facts})
+		}
+		if more := g.Count - len(g.Samples); more > 0 {
+			body = append(body, map[string]interface{}{
+				"type":     "TextBlock",
+				"text":     fmt.Sprintf("…and %d more", more),
+				"isSubtle": true,
+			})
+		}
+	}
+	if footer := footer(d); footer != "" {
+		body = append(body, map[string]interface{}{"type": "TextBlock", "text": footer, "isSubtle": true, "wrap": true})
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"type": "message",
+		"attachments": []map[string]interface{}{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]interface{}{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    body,
+			},
+		}},
+	})
+}
@@ -0,0 +1,42 @@
+package notify
+
+import (
+	"encoding/json"
+	"testing"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+func TestTeamsFormat(t *testing.T) {
+	payload, err := Teams(digest("", []Alert{
+		{Claim: "SELECT * FROM users", Engine: "sql", Verdict: qwed.VerdictRefuted, Severity: qwed.SeverityCritical, Reason: "unknown table"},
+	}, 0, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var msg struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Type string                   `json:"type"`
+				Body []map[string]interface{} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg.Type != "message" || len(msg.Attachments) != 1 || msg.Attachments[0].ContentType != "application/vnd.microsoft.card.adaptive" {
+		t.Fatalf("unexpected message: %s", payload)
+	}
+	body := msg.Attachments[0].Content.Body
+	if body[0]["text"] != "QWED: 1 verification failure" {
+		t.Errorf("unexpected title: %v", body[0])
+	}
+	facts, _ := body[2]["facts"].([]interface{})
+	if len(facts) != 2 || facts[0].(map[string]interface{})["value"] != "SELECT * FROM users" {
+		t.Errorf("expected claim and reason facts, got %v", body[2])
+	}
+}
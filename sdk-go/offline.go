@@ -0,0 +1,60 @@
+package qwed
+
+import (
+	"context"
+	"net/http"
+)
+
+// ============================================================================
+// Offline Fallback
+// ============================================================================
+
+// WithOfflineFallback makes the listed engines fall back to the embedded
+// deterministic evaluators when the API cannot be reached. Supported engines
+// are TypeMath (arithmetic claims such as "2 + 2 = 4") and TypeLogic
+// (propositional tautologies). Fallback responses report Engine
+// "local-math" or "local-logic" and carry "offline": true in Result.
+func WithOfflineFallback(engines ...VerificationType) ClientOption {
+	return func(c *Client) {
+		if c.offline == nil {
+			c.offline = make(map[VerificationType]bool)
+		}
+		for _, engine := range engines {
+			c.offline[engine] = true
+		}
+	}
+}
+
+// fallback answers a failed call locally when offline fallback is enabled
+// for engine and err indicates the API was unreachable. It returns the
+// original response and error otherwise.
+func (c *Client) fallback(ctx context.Context, engine VerificationType, query string, resp *VerificationResponse, err error) (*VerificationResponse, error) {
+	if err == nil || !c.offline[engine] || ctx.Err() != nil || !unreachable(err) {
+		return resp, err
+	}
+
+	var local *VerificationResponse
+	var localErr error
+	switch engine {
+	case TypeMath:
+		local, localErr = localVerifyMath(query)
+	case TypeLogic:
+		local, localErr = localVerifyLogic(query)
+	default:
+		return resp, err
+	}
+	if localErr != nil {
+		return resp, err
+	}
+	return local, nil
+}
+
+// unreachable reports whether err means the API could not serve the request
+// at all, as opposed to rejecting it.
+func unreachable(err error) bool {
+	switch statusCode(err) {
+	case 0, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
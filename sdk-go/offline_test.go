@@ -0,0 +1,43 @@
+package qwed
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestOfflineFallback(t *testing.T) {
+	client := NewClient("test-key",
+		WithBaseURL("http://127.0.0.1:1"),
+		WithOfflineFallback(TypeMath, TypeLogic),
+	)
+
+	resp, err := client.VerifyMath(context.Background(), "2 + 2 = 4")
+	if err != nil {
+		t.Fatalf("expected local fallback, got error: %v", err)
+	}
+	if resp.Engine != EngineLocalMath || !resp.Verified {
+		t.Errorf("unexpected fallback response: %+v", resp)
+	}
+
+	resp, err = client.VerifyLogic(context.Background(), "A -> A")
+	if err != nil || resp.Engine != EngineLocalLogic {
+		t.Errorf("expected local logic fallback, got %+v, %v", resp, err)
+	}
+
+	if _, err := client.VerifyCode(context.Background(), "x", "python"); err == nil {
+		t.Error("expected code verification to fail without fallback")
+	}
+}
+
+func TestOfflineFallbackSkipsAPIErrors(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	defer server.Close()
+
+	client := NewClient("bad-key", WithBaseURL(server.URL), WithOfflineFallback(TypeMath))
+	if _, err := client.VerifyMath(context.Background(), "2 + 2 = 4"); err == nil {
+		t.Error("expected auth error to be returned, not masked by fallback")
+	}
+}
@@ -0,0 +1,84 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ============================================================================
+// Optimization Verification
+// ============================================================================
+
+// OptimizationSense is the direction of an optimization problem.
+type OptimizationSense string
+
+const (
+	Maximize OptimizationSense = "maximize"
+	Minimize OptimizationSense = "minimize"
+)
+
+// OptimizationProblem is a linear or integer program.
+type OptimizationProblem struct {
+	Sense     OptimizationSense `json:"sense"`
+	Objective string            `json:"objective"` // linear expression, e.g. "3*x + 2*y"
+	// Constraints are linear inequalities and equations, including bounds,
+	// e.g. "x + y <= 4" or "x >= 0".
+	Constraints []string `json:"constraints"`
+	// Integer lists the variables restricted to integer values.
+	Integer []string `json:"integer,omitempty"`
+}
+
+// OptimalityReport is the verdict on a proposed solution from VerifyOptimal.
+type OptimalityReport struct {
+	Feasible       bool    `json:"feasible"`
+	ObjectiveValue float64 `json:"objective_value"` // of the proposed solution
+	OptimalValue   float64 `json:"optimal_value"`   // found by the solver
+	// Gap is the relative distance of the proposed solution from the
+	// optimum, |objective - optimal| / max(|optimal|, 1).
+	Gap        float64               `json:"gap"`
+	Solution   map[string]float64    `json:"solution,omitempty"` // an optimal solution
+	Violations []ConstraintViolation `json:"violations,omitempty"`
+}
+
+// VerifyOptimal checks a solution proposed for a linear or integer
+// program, such as a model's claim that an allocation is optimal. The
+// solution must satisfy every constraint and be within tolerance of the
+// optimum, as a relative gap: 0.01 accepts solutions within 1% of the
+// optimum and 0 requires an optimal one.
+//
+//	problem := qwed.OptimizationProblem{
+//		Sense:       qwed.Maximize,
+//		Objective:   "40*chairs + 30*tables",
+//		Constraints: []string{"2*chairs + tables <= 100", "chairs + tables <= 80", "chairs >= 0", "tables >= 0"},
+//		Integer:     []string{"chairs", "tables"},
+//	}
+//	resp, err := client.VerifyOptimal(ctx, problem, map[string]float64{"chairs": 40, "tables": 20}, 0.01)
+//
+// Use Optimality to decode the feasibility, gap and an optimal solution.
+func (c *Client) VerifyOptimal(ctx context.Context, problem OptimizationProblem, solution map[string]float64, tolerance float64, callOpts ...CallOption) (*VerificationResponse, error) {
+	req := map[string]interface{}{
+		"problem":   problem,
+		"solution":  solution,
+		"tolerance": tolerance,
+	}
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, invalidRequest("failed to marshal optimization problem: %v", err)
+	}
+	return c.verify(ctx, "VerifyOptimal", TypeOptimization, CacheKey(TypeOptimization, contentHash(data)), req, callOpts...)
+}
+
+// Optimality extracts the verdict from a VerifyOptimal response, or nil if
+// there is none.
+func Optimality(resp *VerificationResponse) *OptimalityReport {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var report OptimalityReport
+	if !decodeResult(resp.Result["optimality"], &report) {
+		return nil
+	}
+	return &report
+}
@@ -0,0 +1,94 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+var furniture = OptimizationProblem{
+	Sense:       Maximize,
+	Objective:   "40*chairs + 30*tables",
+	Constraints: []string{"2*chairs + tables <= 100", "chairs + tables <= 80", "chairs >= 0", "tables >= 0"},
+	Integer:     []string{"chairs", "tables"},
+}
+
+func TestVerifyOptimal(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/optimization" {
+			t.Errorf("expected path /verify/optimization, got %s", r.URL.Path)
+		}
+		var req struct {
+			Problem   OptimizationProblem `json:"problem"`
+			Solution  map[string]float64  `json:"solution"`
+			Tolerance float64             `json:"tolerance"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Problem.Sense != Maximize || len(req.Problem.Constraints) != 4 || req.Solution["chairs"] != 40 || req.Tolerance != 0.01 {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": false,
+			"engine":   "optimization",
+			"result": map[string]interface{}{
+				"optimality": map[string]interface{}{
+					"feasible":        true,
+					"objective_value": 2200,
+					"optimal_value":   2600,
+					"gap":             0.1538,
+					"solution":        map[string]interface{}{"chairs": 20, "tables": 60},
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyOptimal(context.Background(), furniture, map[string]float64{"chairs": 40, "tables": 20}, 0.01)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Verified {
+		t.Error("expected a suboptimal solution to fail")
+	}
+
+	report := Optimality(resp)
+	if report == nil || !report.Feasible || report.OptimalValue != 2600 || report.Gap <= 0.01 || report.Solution["tables"] != 60 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+	if Optimality(&VerificationResponse{}) != nil {
+		t.Error("expected no report without a result")
+	}
+}
+
+func TestVerifyOptimalStrictValidation(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"), WithStrictValidation())
+	ctx := context.Background()
+	solution := map[string]float64{"chairs": 40, "tables": 20}
+
+	noSense := furniture
+	noSense.Sense = ""
+	noObjective := furniture
+	noObjective.Objective = " "
+
+	tests := []struct {
+		name      string
+		problem   OptimizationProblem
+		solution  map[string]float64
+		tolerance float64
+	}{
+		{"no sense", noSense, solution, 0},
+		{"no objective", noObjective, solution, 0},
+		{"no solution", furniture, nil, 0},
+		{"negative tolerance", furniture, solution, -0.1},
+	}
+	for _, tt := range tests {
+		if _, err := client.VerifyOptimal(ctx, tt.problem, tt.solution, tt.tolerance); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("%s: expected ErrInvalidRequest, got %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ============================================================================
+// Parallel Verification
+// ============================================================================
+
+// ErrSkipped is the error of items VerifyAll did not run because an earlier
+// item failed with FailFast set.
+var ErrSkipped = errors.New("qwed: skipped after an earlier failure")
+
+// ParallelOptions configures VerifyAll.
+type ParallelOptions struct {
+	// Concurrency is the most verifications in flight at once. Defaults to 8.
+	Concurrency int
+	// FailFast stops at the first item that fails with an error: items in
+	// flight are cancelled and the rest are skipped. A response that is not
+	// verified is not a failure.
+	FailFast bool
+}
+
+// ParallelResult is the outcome of one VerifyAll item.
+type ParallelResult struct {
+	Item     BatchItem
+	Response *VerificationResponse
+	Err      error
+}
+
+// VerifyAll verifies items from the client with bounded concurrency,
+// instead of submitting them as a server-side batch job, and returns their
+// results in item order. Each item goes through the full client (cache,
+// rate limiting, policies and fallbacks) as a call to Verify, VerifyMath,
+// VerifyLogic or VerifyPromptSafety, chosen by its type.
+//
+// Per-item errors are reported in the results. The returned error is the
+// first item error when FailFast is set, or ctx's error if ctx is done
+// before every item has run. callOpts apply to every item; an idempotency
+// key gets the item index appended so items do not share it.
+func (c *Client) VerifyAll(ctx context.Context, items []BatchItem, opts ParallelOptions, callOpts ...CallOption) ([]ParallelResult, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+	baseKey := newCallOptions(callOpts).idempotencyKey
+
+	results := make([]ParallelResult, len(items))
+	for i, item := range items {
+		results[i] = ParallelResult{Item: item, Err: ErrSkipped}
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		next     = make(chan int)
+	)
+	for w := 0; w < min(opts.Concurrency, len(items)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				itemOpts := callOpts
+				if baseKey != "" {
+					itemOpts = append(callOpts[:len(callOpts):len(callOpts)], WithIdempotencyKey(fmt.Sprintf("%s-%d", baseKey, i)))
+				}
+				resp, err := c.verifyItem(runCtx, items[i], itemOpts...)
+				results[i].Response, results[i].Err = resp, err
+				if err != nil && opts.FailFast {
+					once.Do(func() {
+						firstErr = fmt.Errorf("item %d: %w", i, err)
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range items {
+		select {
+		case next <- i:
+		case <-runCtx.Done():
+			break dispatch
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+	return results, ctx.Err()
+}
+
+// verifyItem verifies a batch item with the method matching its type.
+func (c *Client) verifyItem(ctx context.Context, item BatchItem, callOpts ...CallOption) (*VerificationResponse, error) {
+	switch item.Type {
+	case TypeMath:
+		return c.VerifyMath(ctx, item.Query, callOpts...)
+	case TypeLogic:
+		return c.VerifyLogic(ctx, item.Query, callOpts...)
+	case TypePromptSafety:
+		return c.VerifyPromptSafety(ctx, item.Query, callOpts...)
+	case TypeNaturalLanguage, "":
+		return c.Verify(ctx, item.Query, callOpts...)
+	}
+	return nil, invalidRequest("unsupported item type %q", item.Type)
+}
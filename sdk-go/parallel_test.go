@@ -0,0 +1,120 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVerifyAll(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	var mu sync.Mutex
+	keys := make(map[string]bool)
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		mu.Lock()
+		keys[r.Header.Get(HeaderIdempotencyKey)] = true
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["expression"] == "1/0" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true, Engine: Engine(strings.TrimPrefix(r.URL.Path, "/verify/"))})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	var items []BatchItem
+	for i := 0; i < 20; i++ {
+		items = append(items, BatchItem{Query: "2+2=4", Type: TypeMath})
+	}
+	items[3] = BatchItem{Query: "A OR NOT A", Type: TypeLogic}
+	items[7] = BatchItem{Query: "1/0", Type: TypeMath}
+	items[9] = BatchItem{Query: "x", Type: TypeImage}
+
+	results, err := client.VerifyAll(context.Background(), items, ParallelOptions{Concurrency: 4}, WithIdempotencyKey("run"))
+	if err != nil {
+		t.Fatalf("VerifyAll() error = %v", err)
+	}
+	if len(results) != len(items) {
+		t.Fatalf("got %d results, want %d", len(results), len(items))
+	}
+	for i, r := range results {
+		switch i {
+		case 7:
+			if !errors.Is(r.Err, ErrInvalidRequest) || r.Item != items[7] {
+				t.Errorf("item 7: got %+v, want server error", r)
+			}
+		case 9:
+			if !errors.Is(r.Err, ErrInvalidRequest) {
+				t.Errorf("item 9: error = %v, want unsupported type", r.Err)
+			}
+		default:
+			if r.Err != nil || !r.Response.Verified {
+				t.Errorf("item %d: got %+v", i, r)
+			}
+		}
+	}
+	if results[3].Response.Engine != "logic" {
+		t.Errorf("item 3 engine = %q, want logic", results[3].Response.Engine)
+	}
+	if peak.Load() > 4 {
+		t.Errorf("peak concurrency = %d, want at most 4", peak.Load())
+	}
+	if len(keys) != 19 || !keys["run-0"] || !keys["run-19"] {
+		t.Errorf("expected a distinct idempotency key per item, got %d", len(keys))
+	}
+}
+
+func TestVerifyAllFailFast(t *testing.T) {
+	var calls atomic.Int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	items := make([]BatchItem, 50)
+	for i := range items {
+		items[i] = BatchItem{Query: "2+2=5", Type: TypeMath}
+	}
+
+	results, err := client.VerifyAll(context.Background(), items, ParallelOptions{Concurrency: 2, FailFast: true})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("VerifyAll() error = %v, want the first item error", err)
+	}
+	if calls.Load() > 4 {
+		t.Errorf("server saw %d calls, want the run stopped early", calls.Load())
+	}
+	if !errors.Is(results[len(results)-1].Err, ErrSkipped) {
+		t.Errorf("last item error = %v, want ErrSkipped", results[len(results)-1].Err)
+	}
+}
+
+func TestVerifyAllContextDone(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := client.VerifyAll(ctx, []BatchItem{{Query: "2+2=4", Type: TypeMath}}, ParallelOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("VerifyAll() error = %v, want canceled", err)
+	}
+	if len(results) != 1 || results[0].Err == nil {
+		t.Errorf("results = %+v, want the item to report an error", results)
+	}
+}
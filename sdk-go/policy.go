@@ -0,0 +1,219 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// ============================================================================
+// Policies
+// ============================================================================
+
+// Policy bundles request options, verdict thresholds and rule configuration
+// into a named strictness level. Use one of the presets or build your own.
+type Policy struct {
+	Name string `json:"name"`
+
+	// Options are the defaults sent with requests that accept options.
+	// Per-call options take precedence.
+	Options RequestOptions `json:"options"`
+
+	// MinConfidence marks verified results whose reported confidence is
+	// below the threshold as unverified and inconclusive, with
+	// ReasonLowConfidence. Zero disables the check.
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+
+	// FailOnSeverity marks code results with an unsuppressed finding, and
+	// infrastructure results with a violation, at or above this severity as
+	// unverified. Empty leaves the engine verdict.
+	FailOnSeverity string `json:"fail_on_severity,omitempty"`
+
+	// Rules is the default rule configuration for code and SQL checks.
+	Rules *RuleConfig `json:"rules,omitempty"`
+}
+
+// Preset policies.
+var (
+	// PolicyStrict requests proofs and attestations, requires high
+	// confidence and fails code on any warning.
+	PolicyStrict = Policy{
+		Name:           "strict",
+		Options:        RequestOptions{IncludeProof: true, IncludeAttestation: true},
+		MinConfidence:  0.95,
+		FailOnSeverity: SeverityWarning,
+		Rules:          (&RuleConfig{}).SetSeverity("context_dependent", SeverityCritical),
+	}
+
+	// PolicyStandard mirrors the engine defaults with a moderate confidence
+	// floor and fails code on critical findings.
+	PolicyStandard = Policy{
+		Name:           "standard",
+		MinConfidence:  0.8,
+		FailOnSeverity: SeverityCritical,
+	}
+
+	// PolicyPermissive only fails on engine verdicts and treats
+	// context-dependent code findings as informational.
+	PolicyPermissive = Policy{
+		Name:  "permissive",
+		Rules: (&RuleConfig{}).SetSeverity("context_dependent", SeverityInfo),
+	}
+)
+
+// WithPolicy applies a policy to every verification call. Rules set with
+// WithRuleConfig are merged on top of the policy's rules. A rule pack
+// loaded with WithRulePacks replaces the policy while it defines one of the
+// same name.
+func WithPolicy(p Policy) ClientOption {
+	return func(c *Client) {
+		c.policy = &p
+	}
+}
+
+// applyPolicy installs policy enforcement once all options have been
+// applied.
+func (c *Client) applyPolicy() {
+	if c.policy == nil {
+		return
+	}
+	c.interceptors = append(c.interceptors, func(ctx context.Context, req *Request, next Invoker) (*VerificationResponse, error) {
+		return c.currentPolicy().enforce(ctx, req, next)
+	})
+}
+
+// currentPolicy returns the policy in effect: the rule pack's version of
+// the client's policy if the loaded pack has one, otherwise the policy
+// itself. It returns nil if the client has no policy.
+func (c *Client) currentPolicy() *Policy {
+	if c.policy == nil {
+		return nil
+	}
+	if pack := c.rulePack(); pack != nil {
+		if p, ok := pack.Policies[c.policy.Name]; ok {
+			p.Name = c.policy.Name
+			return &p
+		}
+	}
+	return c.policy
+}
+
+// requestOptions returns the options to send: the per-call options merged
+// over the policy defaults, with the client's rule configuration attached.
+// It returns nil if there is nothing to send.
+func (c *Client) requestOptions(opts *RequestOptions) *RequestOptions {
+	if policy := c.currentPolicy(); policy != nil {
+		merged := policy.Options
+		if opts != nil {
+			merged.merge(opts)
+		}
+		opts = &merged
+	}
+	opts = c.withRules(opts)
+	if opts != nil && *opts == (RequestOptions{}) {
+		return nil
+	}
+	return opts
+}
+
+// merge overlays the fields set in other onto o.
+func (o *RequestOptions) merge(other *RequestOptions) {
+	if other.TimeoutMs != 0 {
+		o.TimeoutMs = other.TimeoutMs
+	}
+	if other.IncludeProof {
+		o.IncludeProof = true
+	}
+	if other.IncludeAttestation {
+		o.IncludeAttestation = true
+	}
+	if other.OutputFormat != "" {
+		o.OutputFormat = other.OutputFormat
+	}
+	if other.CheckMode != "" {
+		o.CheckMode = other.CheckMode
+	}
+	if other.EnginePin != "" {
+		o.EnginePin = other.EnginePin
+	}
+	if other.Rules != nil {
+		o.Rules = other.Rules
+	}
+}
+
+// optionsKey returns a cache key component identifying opts.
+func optionsKey(opts *RequestOptions) string {
+	if opts == nil {
+		return ""
+	}
+	data, _ := json.Marshal(opts)
+	return string(data)
+}
+
+// enforce is an Interceptor applying the policy thresholds to responses.
+// The response is copied before being modified so cached results keep the
+// engine's original verdict.
+func (p *Policy) enforce(ctx context.Context, req *Request, next Invoker) (*VerificationResponse, error) {
+	resp, err := next(ctx, req)
+	if err != nil || resp == nil || !resp.Verified {
+		return resp, err
+	}
+
+	if conf, ok := confidence(resp); ok && p.MinConfidence > 0 && conf < p.MinConfidence {
+		// Low confidence means the claim could not be checked reliably, not
+		// that it is wrong.
+		out := inconclusive(resp, ReasonLowConfidence)
+		out.Result["policy"] = p.Name
+		out.Result["policy_reason"] = "confidence below policy threshold"
+		return out, nil
+	}
+
+	reason := ""
+	if p.FailOnSeverity != "" && req.Engine == TypeCode {
+		findings := CodeFindings(resp)
+		if body, ok := req.Body.(map[string]interface{}); ok {
+			if code, ok := body["code"].(string); ok {
+				findings = ApplySuppressions(code, findings)
+			}
+		}
+		for _, f := range findings {
+			if !f.Suppressed && severityRank(f.Severity) >= severityRank(p.FailOnSeverity) {
+				reason = "finding " + f.Type + " at or above " + p.FailOnSeverity
+				break
+			}
+		}
+	}
+	if p.FailOnSeverity != "" && req.Engine == TypeInfra {
+		for _, v := range InfraViolations(resp) {
+			if severityRank(v.Severity) >= severityRank(p.FailOnSeverity) {
+				reason = "violation " + v.Rule + " at or above " + p.FailOnSeverity
+				break
+			}
+		}
+	}
+	if reason == "" {
+		return resp, nil
+	}
+
+	failed := *resp
+	failed.Verified = false
+	failed.Status = StatusFailed
+	failed.Result = make(map[string]interface{}, len(resp.Result)+2)
+	for k, v := range resp.Result {
+		failed.Result[k] = v
+	}
+	failed.Result["policy"] = p.Name
+	failed.Result["policy_reason"] = reason
+	return &failed, nil
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	}
+	return 0
+}
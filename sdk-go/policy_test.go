@@ -0,0 +1,139 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPolicyDefaultOptionsSent(t *testing.T) {
+	var got map[string]interface{}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithPolicy(PolicyStrict))
+	if _, err := client.VerifyWithOptions(context.Background(), "Is 2+2=4?", &RequestOptions{TimeoutMs: 500}); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, ok := got["options"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected options to be sent, got %v", got)
+	}
+	if opts["include_proof"] != true || opts["include_attestation"] != true {
+		t.Errorf("expected policy defaults, got %v", opts)
+	}
+	if opts["timeout_ms"] != float64(500) {
+		t.Errorf("expected per-call timeout to be kept, got %v", opts["timeout_ms"])
+	}
+}
+
+func TestPolicyMinConfidence(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(VerificationResponse{
+			Status:   StatusVerified,
+			Verified: true,
+			Result:   map[string]interface{}{"confidence": 0.9},
+		})
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	strict := NewClient("test-key", WithBaseURL(server.URL), WithPolicy(PolicyStrict))
+	resp, err := strict.VerifyMath(ctx, "2+2=4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Verified || resp.Verdict() != VerdictInconclusive || resp.InconclusiveReason() != ReasonLowConfidence || resp.Result["policy"] != "strict" {
+		t.Errorf("expected strict policy to make low confidence inconclusive, got %+v", resp)
+	}
+
+	standard := NewClient("test-key", WithBaseURL(server.URL), WithPolicy(PolicyStandard))
+	if resp, err := standard.VerifyMath(ctx, "2+2=4"); err != nil || !resp.Verified {
+		t.Errorf("expected standard policy to pass, got %+v, %v", resp, err)
+	}
+}
+
+func TestPolicyFailOnSeverity(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(VerificationResponse{
+			Status:   StatusVerified,
+			Verified: true,
+			Result: map[string]interface{}{
+				"issues": []interface{}{
+					map[string]interface{}{"severity": "WARNING", "type": "weak_hash", "line_number": 1},
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	code := "hashlib.md5(data)"
+
+	strict := NewClient("test-key", WithBaseURL(server.URL), WithPolicy(PolicyStrict))
+	resp, err := strict.VerifyCode(ctx, code, "python")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Verified || resp.Result["policy_reason"] == nil {
+		t.Errorf("expected warning to fail strict policy, got %+v", resp)
+	}
+
+	suppressed := "hashlib.md5(data) # qwed:ignore weak_hash"
+	if resp, err := strict.VerifyCode(ctx, suppressed, "python"); err != nil || !resp.Verified {
+		t.Errorf("expected suppressed finding to be ignored, got %+v, %v", resp, err)
+	}
+
+	standard := NewClient("test-key", WithBaseURL(server.URL), WithPolicy(PolicyStandard))
+	if resp, err := standard.VerifyCode(ctx, code, "python"); err != nil || !resp.Verified {
+		t.Errorf("expected warning to pass standard policy, got %+v, %v", resp, err)
+	}
+}
+
+func TestPolicyKeepsCachedVerdict(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(VerificationResponse{
+			Status:   StatusVerified,
+			Verified: true,
+			Result:   map[string]interface{}{"confidence": 0.5},
+		})
+	})
+	defer server.Close()
+
+	cache := NewLRUCache(0)
+	client := NewClient("test-key", WithBaseURL(server.URL), WithCache(cache, time.Minute), WithPolicy(PolicyStandard))
+	if resp, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil || resp.Verified {
+		t.Fatalf("expected policy failure, got %+v, %v", resp, err)
+	}
+
+	cached, ok, _ := cache.Get(context.Background(), CacheKey(TypeMath, "2+2=4"))
+	if !ok {
+		t.Fatal("expected response to be cached")
+	}
+	if !cached.Verified || cached.Result["policy"] != nil {
+		t.Errorf("expected cached response to keep engine verdict, got %+v", cached)
+	}
+}
+
+func TestPolicyRulesMergedWithClientRules(t *testing.T) {
+	client := NewClient("test-key",
+		WithRuleConfig((&RuleConfig{}).Disable("weak_hash")),
+		WithPolicy(PolicyPermissive))
+
+	opts := client.requestOptions(nil)
+	if opts == nil || opts.Rules == nil {
+		t.Fatal("expected rules to be attached")
+	}
+	if opts.Rules.Rules["context_dependent"].Severity != SeverityInfo {
+		t.Errorf("expected policy rule, got %+v", opts.Rules.Rules)
+	}
+	if s := opts.Rules.Rules["weak_hash"]; s.Enabled == nil || *s.Enabled {
+		t.Errorf("expected client rule, got %+v", s)
+	}
+}
@@ -0,0 +1,96 @@
+package qwed
+
+import "context"
+
+// ============================================================================
+// Request Priority
+// ============================================================================
+
+// Priority orders requests waiting inside the client: in engine queues,
+// for rate limit tokens and for adaptive concurrency slots. Waiting
+// requests are admitted highest priority first, and in arrival order within
+// a priority.
+type Priority int
+
+const (
+	// Low is for background work such as backfills and re-verification.
+	Low Priority = -1
+	// Normal is the priority of requests without one set.
+	Normal Priority = 0
+	// High is for interactive, user-facing verification.
+	High Priority = 1
+)
+
+func (p Priority) String() string {
+	switch p {
+	case Low:
+		return "low"
+	case Normal:
+		return "normal"
+	case High:
+		return "high"
+	}
+	return "unknown"
+}
+
+type priorityKey struct{}
+
+// WithPriority marks calls made with the returned context with priority p,
+// so interactive verifications sharing a client with background jobs do not
+// wait behind them:
+//
+//	resp, err := client.VerifyFact(qwed.WithPriority(ctx, qwed.High), claim, source)
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority set with WithPriority, or
+// Normal.
+func PriorityFromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return Normal
+}
+
+// waiter is a request waiting to be admitted; ready is closed to admit it.
+type waiter struct {
+	priority Priority
+	ready    chan struct{}
+}
+
+// waitQueue holds waiters in admission order: highest priority first, and
+// first come first served within a priority.
+type waitQueue []*waiter
+
+// push adds a waiter with priority p behind every waiter of the same or
+// higher priority.
+func (q *waitQueue) push(p Priority) *waiter {
+	w := &waiter{priority: p, ready: make(chan struct{})}
+	i := len(*q)
+	for i > 0 && (*q)[i-1].priority < p {
+		i--
+	}
+	*q = append(*q, nil)
+	copy((*q)[i+1:], (*q)[i:])
+	(*q)[i] = w
+	return w
+}
+
+// pop removes and returns the first waiter.
+func (q *waitQueue) pop() *waiter {
+	w := (*q)[0]
+	*q = (*q)[1:]
+	return w
+}
+
+// remove drops w from the queue, reporting whether it was still queued.
+func (q *waitQueue) remove(w *waiter) bool {
+	for i, queued := range *q {
+		if queued == w {
+			*q = append((*q)[:i], (*q)[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
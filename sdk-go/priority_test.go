@@ -0,0 +1,133 @@
+package qwed
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPriorityFromContext(t *testing.T) {
+	ctx := context.Background()
+	if got := PriorityFromContext(ctx); got != Normal {
+		t.Errorf("default priority = %v, want normal", got)
+	}
+	if got := PriorityFromContext(WithPriority(ctx, High)); got != High {
+		t.Errorf("priority = %v, want high", got)
+	}
+}
+
+func TestWaitQueueOrder(t *testing.T) {
+	var q waitQueue
+	low := q.push(Low)
+	normal1 := q.push(Normal)
+	high := q.push(High)
+	normal2 := q.push(Normal)
+
+	if !q.remove(normal1) || q.remove(normal1) {
+		t.Fatal("remove should succeed once")
+	}
+	q.push(Normal)
+
+	want := []Priority{High, Normal, Normal, Low}
+	var got []Priority
+	var order []*waiter
+	for len(q) > 0 {
+		w := q.pop()
+		got = append(got, w.priority)
+		order = append(order, w)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("priorities = %v, want %v", got, want)
+	}
+	if order[0] != high || order[1] != normal2 || order[3] != low {
+		t.Error("waiters of equal priority are not first come first served")
+	}
+}
+
+// admissionOrder starts a waiter per priority, in order, while wait blocks
+// on a held resource, then frees it and returns the order the waiters were
+// admitted in.
+func admissionOrder(t *testing.T, priorities []Priority, wait func(ctx context.Context) error, free func()) []Priority {
+	t.Helper()
+	var mu sync.Mutex
+	var order []Priority
+	var wg sync.WaitGroup
+	for _, p := range priorities {
+		wg.Add(1)
+		go func(p Priority) {
+			defer wg.Done()
+			if err := wait(WithPriority(context.Background(), p)); err != nil {
+				t.Errorf("wait error = %v", err)
+				return
+			}
+			mu.Lock()
+			order = append(order, p)
+			mu.Unlock()
+			free()
+		}(p)
+		time.Sleep(5 * time.Millisecond)
+	}
+	free()
+	wg.Wait()
+	return order
+}
+
+func TestRateLimiterPriority(t *testing.T) {
+	l := NewRateLimiter(20, 1)
+	l.Allow()
+
+	order := admissionOrder(t, []Priority{Low, Normal, High}, l.Wait, func() {})
+	if want := []Priority{High, Normal, Low}; !reflect.DeepEqual(order, want) {
+		t.Errorf("admission order = %v, want %v", order, want)
+	}
+}
+
+func TestAdaptiveLimiterPriority(t *testing.T) {
+	l := newAdaptiveLimiter(AdaptiveConcurrencySettings{Initial: 1, Max: 1})
+	ctx := context.Background()
+	if _, err := l.acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	order := admissionOrder(t, []Priority{Low, Normal, High},
+		func(ctx context.Context) error { _, err := l.acquire(ctx); return err },
+		func() { l.done(ctx, time.Time{}, nil, nil) })
+	if want := []Priority{High, Normal, Low}; !reflect.DeepEqual(order, want) {
+		t.Errorf("admission order = %v, want %v", order, want)
+	}
+}
+
+func TestEngineQueuesPriority(t *testing.T) {
+	q := newEngineQueues(map[VerificationType]EngineLimit{TypeMath: {MaxConcurrent: 1}})
+	ctx := context.Background()
+	release, err := q.acquire(ctx, TypeMath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var releases []func()
+	order := admissionOrder(t, []Priority{Low, Normal, High},
+		func(ctx context.Context) error {
+			r, err := q.acquire(ctx, TypeMath, nil)
+			mu.Lock()
+			releases = append(releases, r)
+			mu.Unlock()
+			return err
+		},
+		func() {
+			mu.Lock()
+			defer mu.Unlock()
+			if release != nil {
+				release()
+				release = nil
+				return
+			}
+			releases[len(releases)-1]()
+		})
+	if want := []Priority{High, Normal, Low}; !reflect.DeepEqual(order, want) {
+		t.Errorf("dispatch order = %v, want %v", order, want)
+	}
+}
@@ -0,0 +1,564 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// Probability Verification
+// ============================================================================
+
+// TypeProbability identifies probability and combinatorics checks. They
+// run locally and are reported with Engine EngineLocalProbability.
+const TypeProbability VerificationType = "probability"
+
+// EngineLocalProbability is the engine name reported by VerifyProbability.
+const EngineLocalProbability Engine = "local-probability"
+
+// Limits keeping exact arithmetic fast.
+const (
+	maxProbabilityItems = 1000 // trials, draws and items to count
+	maxProbabilityDice  = 100  // dice summed
+)
+
+// VerifyProbability checks a probability or counting claim with exact
+// rational arithmetic, such as "the chance of two heads in three flips is
+// 3/8". It understands:
+//
+//   - coins: "the probability of at least one head in 4 tosses is 15/16"
+//   - dice: "the chance of rolling a sum of 7 with two dice is 1/6",
+//     "the probability of at least one six in 4 rolls is 51.8%"
+//   - cards: "the probability of drawing two aces without replacement is 1/221"
+//   - independent trials: "the probability of exactly 2 successes in 5
+//     trials with probability 0.3 is 0.3087"
+//   - counting: "10 choose 3 is 120", "C(10,3) = 120", "5! = 120",
+//     "there are 720 ways to arrange 3 of 10 books"
+//
+// "there is a 37.5% chance of ..." and "the odds of ... are 1 to 5" are
+// read as the equivalent probability. Counts may be qualified with
+// exactly, at least, at most, more than or fewer than; an unqualified
+// count means exactly. Cards are drawn from a standard 52-card deck,
+// without replacement unless the claim says otherwise.
+//
+// The stated value may be a fraction, "1 in 6", a decimal or a
+// percentage, and is compared allowing for the precision it is written
+// with: 1/3 verifies as "0.333" or "33.3%" but not as "0.334" or "0.34".
+// Words such as "about" or "≈" widen the tolerance to 5%. The exact value
+// is reported in Result["expected"] as a fraction. Claims that cannot be
+// parsed are reported with StatusUnsupported.
+func (c *Client) VerifyProbability(ctx context.Context, claim string) (resp *VerificationResponse, err error) {
+	_, end := c.instrument(ctx, "VerifyProbability", TypeProbability)
+	defer func() { end(resp, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return localVerifyProbability(claim), nil
+}
+
+// errNoProbabilityClaim is returned for claims matching none of the forms.
+var errNoProbabilityClaim = errors.New("no probability or counting claim found")
+
+// localVerifyProbability evaluates a probability or counting claim.
+func localVerifyProbability(claim string) *VerificationResponse {
+	text := strings.ToLower(strings.TrimRight(strings.Join(strings.Fields(claim), " "), ".!;"))
+	text = chanceFirst.ReplaceAllString(text, "the $2 of $3 is $1")
+	text = waysFirst.ReplaceAllString(text, "the number of $2 $3 is $1")
+
+	// Split at the last comparison word both of whose sides parse, as the
+	// event itself may contain "is" or "=".
+	err := errNoProbabilityClaim
+	locs := probabilityComparisons.FindAllStringIndex(text, -1)
+	for i := len(locs) - 1; i >= 0; i-- {
+		ev, evErr := parseProbabilityEvent(text[:locs[i][0]])
+		if evErr == nil {
+			approx := strings.Contains(text[locs[i][0]:locs[i][1]], "≈")
+			value, valueErr := parseClaimedValue(text[locs[i][1]:], ev.odds)
+			if valueErr == nil {
+				value.approx = value.approx || approx
+				return compareProbability(ev, value)
+			}
+			evErr = valueErr
+		}
+		if err == errNoProbabilityClaim {
+			err = evErr
+		}
+	}
+	return &VerificationResponse{
+		Status: StatusUnsupported,
+		Engine: EngineLocalProbability,
+		Result: map[string]interface{}{"reason": err.Error()},
+	}
+}
+
+// probabilityEvent is the exact value of the event side of a claim.
+type probabilityEvent struct {
+	kind  string // e.g. "coins", "dice_sum" or "combinations"
+	value *big.Rat
+	count bool // a number of ways rather than a probability
+	odds  bool // the claim states odds, "1 to 5"
+}
+
+// claimedValue is the value side of a claim.
+type claimedValue struct {
+	text  string
+	value *big.Rat
+	// precision is half a unit in the last written digit, or nil for
+	// exact values such as fractions and integers.
+	precision *big.Rat
+	approx    bool
+}
+
+// compareProbability checks a claimed value against the exact one.
+func compareProbability(ev probabilityEvent, claimed claimedValue) *VerificationResponse {
+	result := map[string]interface{}{
+		"kind":     ev.kind,
+		"expected": ev.value.RatString(),
+		"claimed":  claimed.text,
+	}
+	if f, _ := ev.value.Float64(); !math.IsInf(f, 0) {
+		result["expected_value"] = f
+	}
+	if f, _ := claimed.value.Float64(); !math.IsInf(f, 0) {
+		result["claimed_value"] = f
+	}
+
+	if !ev.count && (claimed.value.Sign() < 0 || claimed.value.Cmp(big.NewRat(1, 1)) > 0) {
+		result["reason"] = fmt.Sprintf("%s is not a probability between 0 and 1", claimed.text)
+		return localResponse(EngineLocalProbability, false, result)
+	}
+
+	tolerance := new(big.Rat)
+	if claimed.precision != nil {
+		tolerance.Set(claimed.precision)
+	}
+	if claimed.approx {
+		approx := new(big.Rat).SetFloat64(approxTolerance)
+		approx.Mul(approx, new(big.Rat).Abs(claimed.value))
+		if approx.Cmp(tolerance) > 0 {
+			tolerance = approx
+		}
+	}
+	diff := new(big.Rat).Sub(ev.value, claimed.value)
+	verified := diff.Abs(diff).Cmp(tolerance) <= 0
+	if !verified {
+		expected := ev.value.RatString()
+		if f, _ := ev.value.Float64(); !ev.value.IsInt() {
+			expected += fmt.Sprintf(" (%.6g)", f)
+		}
+		result["reason"] = fmt.Sprintf("expected %s, not %s", expected, claimed.text)
+	}
+	return localResponse(EngineLocalProbability, verified, result)
+}
+
+// ============================================================================
+// Claim Forms
+// ============================================================================
+
+const (
+	qualifierPattern  = `(?:(exactly|at least|at most|more than|fewer than|less than|no more than|no fewer than|no less than|up to) )?`
+	eventCountPattern = `(` + countPattern + `|no|zero|all|both)`
+	facePattern       = `(\d|one|two|three|four|five|six)(?:'?s|es)?`
+	cardPattern       = `((?:the )?(?:ace|king|queen|jack) of (?:hearts|spades|diamonds|clubs)|aces?|kings?|queens?|jacks?|hearts?|spades?|diamonds?|clubs?|face cards?|red cards?|black cards?)`
+)
+
+var (
+	probabilityComparisons = regexp.MustCompile(` (?:is|are|was|equals|comes to|works out to) |\s*(?:=|≈)\s*`)
+
+	chanceFirst = regexp.MustCompile(`^there(?:'s| is) an? (.+?) (chance|probability|likelihood) (?:of|that) (.+)$`)
+	waysFirst   = regexp.MustCompile(`^there are ([\d,]+) (ways|combinations|permutations|arrangements) (.+)$`)
+
+	probabilityPrefix = regexp.MustCompile(`^(?:the )?(probability|chance|likelihood|odds)(?: of| that| for)? (.+)$`)
+
+	coinEvent = regexp.MustCompile(`^(?:getting |flipping |tossing |seeing |landing )?` + qualifierPattern + eventCountPattern +
+		` (?:heads?|tails?) (?:(?:in|with|from|on|out of) (` + countPattern + `) (?:fair )?(?:coin )?(?:flips?|toss(?:es)?|throws?|coins?|tries)|when (?:flipping|tossing) a (?:fair )?coin (` + countPattern + `) times?)$`)
+
+	diceSumEvent = regexp.MustCompile(`^(?:getting |rolling |throwing )?(?:an? )?(?:(?:sum|total) of )?` + qualifierPattern +
+		`(\d+) (?:with|on|from|using|when rolling|when throwing) (` + countPattern + `) (?:fair )?(?:six-sided )?dice$`)
+	diceSumToEvent = regexp.MustCompile(`^(` + countPattern + `) (?:fair )?(?:six-sided )?dice (?:sum to|add up to|total) ` + qualifierPattern + `(\d+)$`)
+	diceFaceEvent  = regexp.MustCompile(`^(?:getting |rolling |throwing |seeing )?` + qualifierPattern + eventCountPattern + ` ` + facePattern +
+		`(?: (?:in|with|from|on|out of) (` + countPattern + `) (?:fair )?(?:six-sided )?(?:rolls?|throws?|dice|die|die rolls)(?: of a (?:fair )?(?:six-sided )?die)?)?$`)
+
+	cardEvent = regexp.MustCompile(`^(?:drawing |getting |being dealt |pulling |picking )?` + qualifierPattern + `(?:` + eventCountPattern + ` )?` + cardPattern +
+		`(?: in (?:a row|(` + countPattern + `) (?:draws|cards)))?(?: (?:from|out of) (?:a|the) (?:standard |shuffled |well-shuffled |regular )?(?:52-card )?deck(?: of (?:52 )?cards)?)?(?: (with|without) replacement)?$`)
+
+	trialsEvent = regexp.MustCompile(`^` + qualifierPattern + eventCountPattern +
+		` (?:successes|success|hits|wins|times) in (` + countPattern + `) (?:independent )?(?:trials?|attempts?|tries|try|games?|shots?),? (?:with|where|if|given) (?:a |the )?(?:success )?(?:probability|chance|p)(?: of success)?(?: of| is| =)? ?(.+)$`)
+
+	chooseEvents = []*regexp.Regexp{
+		regexp.MustCompile(`^\(?(\d+) choose (\d+)\)?$`),
+		regexp.MustCompile(`^c\((\d+), ?(\d+)\)$`),
+		regexp.MustCompile(`^(\d+)c(\d+)$`),
+	}
+	permuteEvents = []*regexp.Regexp{
+		regexp.MustCompile(`^(\d+) permute (\d+)$`),
+		regexp.MustCompile(`^p\((\d+), ?(\d+)\)$`),
+		regexp.MustCompile(`^(\d+)p(\d+)$`),
+	}
+	factorialEvent = regexp.MustCompile(`^(\d+)!$`)
+	waysEvent      = regexp.MustCompile(`^(?:the )?number of (?:ways|combinations|permutations|arrangements) (?:to |of )?(choose|choosing|pick|picking|select|selecting|arrange|arranging|order|ordering) (` +
+		countPattern + `) (?:[a-z]+ )?(?:from|of|out of|among) (?:a (?:group|set|team|pool|class|list) of )?(` + countPattern + `)(?: [a-z]+)?$`)
+	arrangeAllEvent = regexp.MustCompile(`^(?:the )?number of (?:ways to (?:arrange|order|line up|seat|permute)|permutations of|arrangements of|orderings of) (` + countPattern + `) [a-z ]+$`)
+)
+
+// parseProbabilityEvent evaluates the event side of a claim.
+func parseProbabilityEvent(s string) (probabilityEvent, error) {
+	s = strings.TrimSpace(s)
+	if ev, err := countingEvent(s); err != errNoProbabilityClaim {
+		return ev, err
+	}
+
+	m := probabilityPrefix.FindStringSubmatch(s)
+	if m == nil {
+		return probabilityEvent{}, errNoProbabilityClaim
+	}
+	ev, err := chanceEvent(m[2])
+	ev.odds = m[1] == "odds"
+	return ev, err
+}
+
+// countingEvent evaluates combinations, permutations and factorials.
+func countingEvent(s string) (probabilityEvent, error) {
+	count := func(kind string, v *big.Int) (probabilityEvent, error) {
+		return probabilityEvent{kind: kind, value: new(big.Rat).SetInt(v), count: true}, nil
+	}
+	for _, re := range chooseEvents {
+		if m := re.FindStringSubmatch(s); m != nil {
+			n, k, err := itemCounts(m[1], m[2])
+			if err != nil {
+				return probabilityEvent{}, err
+			}
+			return count("combinations", new(big.Int).Binomial(int64(n), int64(k)))
+		}
+	}
+	for _, re := range permuteEvents {
+		if m := re.FindStringSubmatch(s); m != nil {
+			n, k, err := itemCounts(m[1], m[2])
+			if err != nil {
+				return probabilityEvent{}, err
+			}
+			return count("permutations", permutations(n, k))
+		}
+	}
+	if m := factorialEvent.FindStringSubmatch(s); m != nil {
+		n, _, err := itemCounts(m[1], "0")
+		if err != nil {
+			return probabilityEvent{}, err
+		}
+		return count("factorial", permutations(n, n))
+	}
+	if m := waysEvent.FindStringSubmatch(s); m != nil {
+		n, k, err := itemCounts(m[3], m[2])
+		if err != nil {
+			return probabilityEvent{}, err
+		}
+		switch m[1] {
+		case "choose", "choosing", "pick", "picking", "select", "selecting":
+			return count("combinations", new(big.Int).Binomial(int64(n), int64(k)))
+		}
+		return count("permutations", permutations(n, k))
+	}
+	if m := arrangeAllEvent.FindStringSubmatch(s); m != nil {
+		n, _, err := itemCounts(m[1], "0")
+		if err != nil {
+			return probabilityEvent{}, err
+		}
+		return count("permutations", permutations(n, n))
+	}
+	return probabilityEvent{}, errNoProbabilityClaim
+}
+
+// chanceEvent evaluates the event of a probability, after "the
+// probability of".
+func chanceEvent(s string) (probabilityEvent, error) {
+	half := big.NewRat(1, 2)
+	if m := coinEvent.FindStringSubmatch(s); m != nil {
+		n := parseCount(m[3] + m[4])
+		if n > maxProbabilityItems {
+			return probabilityEvent{}, tooMany(n, "coin flips", maxProbabilityItems)
+		}
+		return chance("coins", binomialProbability(n, eventCount(m[2], n), m[1], half)), nil
+	}
+	if m := diceSumEvent.FindStringSubmatch(s); m != nil {
+		return diceSum(parseCount(m[3]), parseCount(m[2]), m[1])
+	}
+	if m := diceSumToEvent.FindStringSubmatch(s); m != nil {
+		return diceSum(parseCount(m[1]), parseCount(m[3]), m[2])
+	}
+	if m := diceFaceEvent.FindStringSubmatch(s); m != nil {
+		if face := parseCount(m[3]); face < 1 || face > 6 {
+			return probabilityEvent{}, fmt.Errorf("a die has no face %s", m[3])
+		}
+		n := 1
+		if m[4] != "" {
+			n = parseCount(m[4])
+		}
+		if n > maxProbabilityItems {
+			return probabilityEvent{}, tooMany(n, "rolls", maxProbabilityItems)
+		}
+		return chance("dice", binomialProbability(n, eventCount(m[2], n), m[1], big.NewRat(1, 6))), nil
+	}
+	if m := cardEvent.FindStringSubmatch(s); m != nil {
+		return cardDraw(m[1], m[2], m[3], m[4], m[5])
+	}
+	if m := trialsEvent.FindStringSubmatch(s); m != nil {
+		n := parseCount(m[3])
+		if n > maxProbabilityItems {
+			return probabilityEvent{}, tooMany(n, "trials", maxProbabilityItems)
+		}
+		p, err := parseClaimedValue(m[4], false)
+		if err != nil {
+			return probabilityEvent{}, fmt.Errorf("invalid success probability %q: %v", m[4], err)
+		}
+		if p.value.Sign() < 0 || p.value.Cmp(big.NewRat(1, 1)) > 0 {
+			return probabilityEvent{}, fmt.Errorf("success probability %s is not between 0 and 1", p.text)
+		}
+		return chance("binomial", binomialProbability(n, eventCount(m[2], n), m[1], p.value)), nil
+	}
+	return probabilityEvent{}, errNoProbabilityClaim
+}
+
+func chance(kind string, p *big.Rat) probabilityEvent {
+	return probabilityEvent{kind: kind, value: p}
+}
+
+// diceSum evaluates the probability of a sum of six-sided dice.
+func diceSum(dice, target int, qualifier string) (probabilityEvent, error) {
+	if dice > maxProbabilityDice {
+		return probabilityEvent{}, tooMany(dice, "dice", maxProbabilityDice)
+	}
+
+	// ways[s] is the number of ways the dice so far sum to s.
+	ways := []*big.Int{big.NewInt(1)}
+	for d := 0; d < dice; d++ {
+		next := make([]*big.Int, len(ways)+6)
+		for i := range next {
+			next[i] = new(big.Int)
+		}
+		for s, w := range ways {
+			for face := 1; face <= 6; face++ {
+				next[s+face].Add(next[s+face], w)
+			}
+		}
+		ways = next
+	}
+
+	hits := new(big.Int)
+	for s, w := range ways {
+		if qualifies(s, target, qualifier) {
+			hits.Add(hits, w)
+		}
+	}
+	total := new(big.Int).Exp(big.NewInt(6), big.NewInt(int64(dice)), nil)
+	return chance("dice_sum", new(big.Rat).SetFrac(hits, total)), nil
+}
+
+// cardDraw evaluates the probability of drawing cards of a kind from a
+// standard deck.
+func cardDraw(qualifier, countText, card, drawsText, replacement string) (probabilityEvent, error) {
+	card = strings.TrimPrefix(card, "the ")
+	var matching int64
+	switch {
+	case strings.Contains(card, " of "):
+		matching = 1
+	case strings.HasPrefix(card, "face card"):
+		matching = 12
+	case strings.HasPrefix(card, "red card"), strings.HasPrefix(card, "black card"):
+		matching = 26
+	case strings.HasPrefix(card, "heart"), strings.HasPrefix(card, "spade"), strings.HasPrefix(card, "diamond"), strings.HasPrefix(card, "club"):
+		matching = 13
+	default:
+		matching = 4
+	}
+
+	k := 1
+	if countText != "" {
+		k = eventCount(countText, 0)
+	}
+	draws := k
+	if drawsText != "" {
+		draws = parseCount(drawsText)
+	}
+	if countText == "all" || countText == "both" {
+		k = draws
+	}
+	if draws < k {
+		return probabilityEvent{}, fmt.Errorf("cannot draw %d cards in %d draws", k, draws)
+	}
+
+	if replacement == "with" {
+		if draws > maxProbabilityItems {
+			return probabilityEvent{}, tooMany(draws, "draws", maxProbabilityItems)
+		}
+		return chance("cards", binomialProbability(draws, k, qualifier, big.NewRat(matching, 52))), nil
+	}
+	if draws > 52 {
+		return probabilityEvent{}, fmt.Errorf("cannot draw %d cards from a 52-card deck without replacement", draws)
+	}
+	hits := new(big.Int)
+	for i := 0; i <= draws; i++ {
+		if qualifies(i, k, qualifier) {
+			ways := new(big.Int).Binomial(matching, int64(i))
+			hits.Add(hits, ways.Mul(ways, new(big.Int).Binomial(52-matching, int64(draws-i))))
+		}
+	}
+	return chance("cards", new(big.Rat).SetFrac(hits, new(big.Int).Binomial(52, int64(draws)))), nil
+}
+
+// ============================================================================
+// Exact Arithmetic
+// ============================================================================
+
+// binomialProbability returns the probability that the number of
+// successes in n independent trials with success probability p is
+// qualified relative to k, e.g. at least k.
+func binomialProbability(n, k int, qualifier string, p *big.Rat) *big.Rat {
+	q := new(big.Rat).Sub(big.NewRat(1, 1), p)
+	total := new(big.Rat)
+	for i := 0; i <= n; i++ {
+		if !qualifies(i, k, qualifier) {
+			continue
+		}
+		term := new(big.Rat).SetInt(new(big.Int).Binomial(int64(n), int64(i)))
+		term.Mul(term, ratPow(p, i))
+		term.Mul(term, ratPow(q, n-i))
+		total.Add(total, term)
+	}
+	return total
+}
+
+// qualifies reports whether i satisfies qualifier relative to k; an empty
+// qualifier means exactly k.
+func qualifies(i, k int, qualifier string) bool {
+	switch qualifier {
+	case "at least", "no fewer than", "no less than":
+		return i >= k
+	case "at most", "no more than", "up to":
+		return i <= k
+	case "more than":
+		return i > k
+	case "fewer than", "less than":
+		return i < k
+	}
+	return i == k
+}
+
+// permutations returns n!/(n-k)!, the number of ordered selections of k
+// of n items.
+func permutations(n, k int) *big.Int {
+	if k > n {
+		return new(big.Int)
+	}
+	return new(big.Int).MulRange(int64(n-k+1), int64(n))
+}
+
+func ratPow(r *big.Rat, n int) *big.Rat {
+	e := big.NewInt(int64(n))
+	return new(big.Rat).SetFrac(new(big.Int).Exp(r.Num(), e, nil), new(big.Int).Exp(r.Denom(), e, nil))
+}
+
+// itemCounts parses the n and k of a counting claim.
+func itemCounts(nText, kText string) (n, k int, err error) {
+	n, k = parseCount(nText), parseCount(kText)
+	if n > maxProbabilityItems {
+		return 0, 0, tooMany(n, "items", maxProbabilityItems)
+	}
+	return n, k, nil
+}
+
+// eventCount parses the count of an event out of n trials: "no" is none
+// and "all" or "both" is n.
+func eventCount(s string, n int) int {
+	switch s {
+	case "no", "zero":
+		return 0
+	case "all", "both":
+		return n
+	}
+	return parseCount(s)
+}
+
+func tooMany(n int, what string, limit int) error {
+	return fmt.Errorf("%d %s is more than the %d supported", n, what, limit)
+}
+
+// ============================================================================
+// Claimed Values
+// ============================================================================
+
+var (
+	approxPrefix  = regexp.MustCompile(`^(?:(about|around|roughly|approximately|approx\.?|nearly|almost|~|≈)|exactly)\s*`)
+	trailingNote  = regexp.MustCompile(`\s*\([^)]*\)$`)
+	percentValue  = regexp.MustCompile(`^(\d*)(?:\.(\d+))?\s*(?:%|percent|per cent)$`)
+	fractionValue = regexp.MustCompile(`^(\d+)\s*/\s*(\d+)$`)
+	inValue       = regexp.MustCompile(`^(` + countPattern + `) (?:in|out of) (` + countPattern + `)$`)
+	oddsValue     = regexp.MustCompile(`^(` + countPattern + `)\s*(?:to|:)\s*(` + countPattern + `)$`)
+	decimalValue  = regexp.MustCompile(`^(\d*)\.(\d+)$`)
+	integerValue  = regexp.MustCompile(`^\d{1,3}(?:,\d{3})+$|^\d+$`)
+)
+
+// parseClaimedValue parses a stated probability or count. With odds set,
+// "a to b" and "a:b" are read as the probability a/(a+b).
+func parseClaimedValue(s string, odds bool) (claimedValue, error) {
+	s = trailingNote.ReplaceAllString(strings.TrimSpace(s), "")
+	v := claimedValue{text: s}
+	if m := approxPrefix.FindStringSubmatch(s); m != nil {
+		v.approx = m[1] != ""
+		s = s[len(m[0]):]
+	}
+
+	switch {
+	case odds && oddsValue.MatchString(s):
+		m := oddsValue.FindStringSubmatch(s)
+		a, b := parseCount(m[1]), parseCount(m[2])
+		if a+b == 0 {
+			return v, fmt.Errorf("invalid odds %q", s)
+		}
+		v.value = big.NewRat(int64(a), int64(a+b))
+	case fractionValue.MatchString(s):
+		m := fractionValue.FindStringSubmatch(s)
+		return ratio(v, m[1], m[2])
+	case inValue.MatchString(s):
+		m := inValue.FindStringSubmatch(s)
+		return ratio(v, fmt.Sprint(parseCount(m[1])), fmt.Sprint(parseCount(m[2])))
+	case percentValue.MatchString(s):
+		m := percentValue.FindStringSubmatch(s)
+		if m[1] == "" && m[2] == "" {
+			return v, fmt.Errorf("invalid percentage %q", s)
+		}
+		v.value, _ = new(big.Rat).SetString("0" + m[1] + "." + m[2] + "0")
+		v.value.Quo(v.value, big.NewRat(100, 1))
+		v.precision = halfUnit(len(m[2]) + 2)
+	case decimalValue.MatchString(s):
+		m := decimalValue.FindStringSubmatch(s)
+		v.value, _ = new(big.Rat).SetString("0" + s)
+		v.precision = halfUnit(len(m[2]))
+	case integerValue.MatchString(s):
+		v.value, _ = new(big.Rat).SetString(strings.ReplaceAll(s, ",", ""))
+	default:
+		return v, fmt.Errorf("cannot parse value %q", s)
+	}
+	return v, nil
+}
+
+// ratio sets v to the fraction num/den.
+func ratio(v claimedValue, num, den string) (claimedValue, error) {
+	r, ok := new(big.Rat).SetString(num + "/" + den)
+	if !ok {
+		return v, fmt.Errorf("invalid fraction %s/%s", num, den)
+	}
+	v.value = r
+	return v, nil
+}
+
+// halfUnit returns half of 10^-digits, the rounding error of a value
+// written with that many decimal places.
+func halfUnit(digits int) *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(1), new(big.Int).Mul(big.NewInt(2), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)))
+}
@@ -0,0 +1,111 @@
+package qwed
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestVerifyProbability(t *testing.T) {
+	tests := []struct {
+		claim    string
+		verified bool
+	}{
+		{"The chance of two heads in three flips is 3/8.", true},
+		{"The chance of two heads in three flips is 1/2.", false},
+		{"the probability of at least one head in 4 tosses is 15/16", true},
+		{"the chance of no heads when flipping a coin 5 times is 1/32", true},
+		{"there is a 37.5% chance of getting exactly two heads in three coin flips", true},
+		{"the chance of rolling a sum of 7 with two dice is 1/6", true},
+		{"the probability of rolling a 7 with two dice is 16.7%", true},
+		{"the probability of rolling a 12 with two dice is 1/18", false},
+		{"the probability that two dice sum to at least 10 is 1/6", true},
+		{"the probability of at least one six in 4 rolls is 51.8%", true},
+		{"the probability of at least one six in 4 rolls is 2/3", false},
+		{"the probability of rolling a 6 is 1 in 6", true},
+		{"the odds of rolling a 6 are 1 to 5", true},
+		{"the probability of drawing two aces without replacement is 1/221", true},
+		{"the probability of drawing two aces is 1/169", false},
+		{"the probability of drawing two aces with replacement is 1/169", true},
+		{"the probability of drawing the ace of spades is 1/52", true},
+		{"the probability of drawing a heart from a standard deck is 25%", true},
+		{"the probability of exactly 2 successes in 5 trials with probability 0.3 is 0.3087", true},
+		{"the probability of exactly 2 successes in 5 trials with p = 0.3 is 0.31", true},
+		{"10 choose 3 is 120", true},
+		{"C(10,3) = 210", false},
+		{"P(10,3) = 720", true},
+		{"10! = 3,628,800", true},
+		{"there are 120 ways to choose 3 people from 10", true},
+		{"there are 120 ways to arrange 3 of 10 books", false},
+		{"the number of ways to arrange 5 books is 120", true},
+	}
+
+	client := NewClient("test")
+	for _, tt := range tests {
+		t.Run(tt.claim, func(t *testing.T) {
+			resp, err := client.VerifyProbability(context.Background(), tt.claim)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Verified != tt.verified {
+				t.Errorf("expected verified=%v, got %v (%v)", tt.verified, resp.Verified, resp.Result)
+			}
+			if resp.Engine != EngineLocalProbability {
+				t.Errorf("expected engine %s, got %s", EngineLocalProbability, resp.Engine)
+			}
+		})
+	}
+}
+
+func TestVerifyProbabilityPrecision(t *testing.T) {
+	tests := []struct {
+		claimed  string
+		verified bool
+	}{
+		{"1/3", true},
+		{"2/6", true},
+		{"0.333", true},
+		{"33.3%", true},
+		{"0.3333333", true},
+		{"0.334", false},
+		{"0.34", false},
+		{"about 0.34", true},
+		{"about 0.35", true},
+		{"about 0.4", false},
+	}
+	for _, tt := range tests {
+		resp := localVerifyProbability("the probability of exactly 1 success in 1 trial with probability 1/3 is " + tt.claimed)
+		if resp.Verified != tt.verified {
+			t.Errorf("%q: expected verified=%v, got %v (%v)", tt.claimed, tt.verified, resp.Verified, resp.Result)
+		}
+	}
+}
+
+func TestVerifyProbabilityResult(t *testing.T) {
+	resp := localVerifyProbability("the probability of two heads in three flips is 0.5")
+	if resp.Status != StatusFailed || resp.Result["expected"] != "3/8" || resp.Result["kind"] != "coins" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if reason, _ := resp.Result["reason"].(string); !strings.Contains(reason, "3/8 (0.375)") {
+		t.Errorf("expected the exact value in the reason, got %q", reason)
+	}
+
+	resp = localVerifyProbability("the probability of at least one head in 3 flips is 1.5")
+	if reason, _ := resp.Result["reason"].(string); resp.Verified || !strings.Contains(reason, "between 0 and 1") {
+		t.Errorf("expected an out-of-range probability to fail, got %v", resp.Result)
+	}
+}
+
+func TestVerifyProbabilityUnsupported(t *testing.T) {
+	for _, claim := range []string{
+		"the probability of rain tomorrow is 40%",
+		"the probability of rolling a 9 with one die is 0",
+		"the probability of two heads in three flips is likely",
+		"2000! = 1",
+	} {
+		resp := localVerifyProbability(claim)
+		if resp.Status != StatusUnsupported || resp.Result["reason"] == "" {
+			t.Errorf("%q: expected %s, got %s (%v)", claim, StatusUnsupported, resp.Status, resp.Result)
+		}
+	}
+}
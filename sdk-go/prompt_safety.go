@@ -0,0 +1,70 @@
+package qwed
+
+import (
+	"context"
+	"sort"
+)
+
+// ============================================================================
+// Prompt Injection Detection
+// ============================================================================
+
+// AttackCategory classifies a prompt injection or jailbreak attempt.
+type AttackCategory string
+
+const (
+	// AttackInstructionOverride tells the model to ignore or replace its
+	// instructions ("ignore all previous instructions").
+	AttackInstructionOverride AttackCategory = "instruction_override"
+	// AttackJailbreak uses role-play or persona framing, such as "DAN",
+	// to lift safety restrictions.
+	AttackJailbreak AttackCategory = "jailbreak"
+	// AttackPromptLeak tries to extract the system prompt or hidden
+	// instructions.
+	AttackPromptLeak AttackCategory = "prompt_leak"
+	// AttackDataExfiltration tries to send data out, for example through
+	// markdown image URLs or tool calls.
+	AttackDataExfiltration AttackCategory = "data_exfiltration"
+	// AttackEncoded hides instructions in Base64, Unicode tricks or other
+	// encodings.
+	AttackEncoded AttackCategory = "encoded_payload"
+	// AttackDelimiter forges chat-template or system-message delimiters.
+	AttackDelimiter AttackCategory = "delimiter_injection"
+)
+
+// PromptThreat is one attack detected in user input.
+type PromptThreat struct {
+	Category   AttackCategory `json:"category"`
+	Confidence float64        `json:"confidence"`         // 0 to 1
+	Evidence   string         `json:"evidence,omitempty"` // the matching span of the input
+	Start      int            `json:"start,omitempty"`    // byte offsets of Evidence
+	End        int            `json:"end,omitempty"`
+}
+
+// VerifyPromptSafety checks untrusted user input for prompt injection and
+// jailbreak attempts before it reaches a model or agent. Safe input is
+// verified; input with an attack is blocked, with the detected attacks in
+// Result["threats"] and the highest confidence in Result["confidence"].
+// Use PromptThreats to decode them.
+func (c *Client) VerifyPromptSafety(ctx context.Context, userInput string, callOpts ...CallOption) (*VerificationResponse, error) {
+	req := map[string]interface{}{
+		"input": userInput,
+	}
+
+	return c.verify(ctx, "VerifyPromptSafety", TypePromptSafety, CacheKey(TypePromptSafety, userInput), req, callOpts...)
+}
+
+// PromptThreats extracts the detected attacks from a VerifyPromptSafety
+// response, most confident first.
+func PromptThreats(resp *VerificationResponse) []PromptThreat {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var threats []PromptThreat
+	decodeResult(resp.Result["threats"], &threats)
+	sort.SliceStable(threats, func(i, j int) bool {
+		return threats[i].Confidence > threats[j].Confidence
+	})
+	return threats
+}
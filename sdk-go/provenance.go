@@ -0,0 +1,343 @@
+package qwed
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Provenance Embedding
+// ============================================================================
+
+// ErrNoProvenance is returned when an artifact carries no provenance record.
+var ErrNoProvenance = errors.New("qwed: no provenance record")
+
+// ErrProvenanceMismatch is returned when an artifact was modified after its
+// provenance record was embedded.
+var ErrProvenanceMismatch = errors.New("qwed: artifact does not match its provenance record")
+
+// Provenance is a compact record of how a generated artifact was verified,
+// embedded in the artifact or stored next to it so downstream consumers can
+// trace it back to the verification.
+type Provenance struct {
+	Verdict     Verdict   `json:"verdict"`
+	Status      Status    `json:"status,omitempty"`
+	Engine      Engine    `json:"engine,omitempty"`
+	Certificate string    `json:"certificate,omitempty"` // attestation ID, or the API request ID
+	Attestation string    `json:"attestation,omitempty"` // signed attestation JWT, see VerifyAttestation
+	ContentHash string    `json:"content_hash"`          // "sha256:<hex>" of the artifact without the record
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// NewProvenance records resp as the verification of content, the artifact
+// bytes before any record is embedded.
+func NewProvenance(resp *VerificationResponse, content []byte) *Provenance {
+	p := &Provenance{
+		Verdict:     resp.Verdict(),
+		ContentHash: contentHash(content),
+		Timestamp:   time.Now().UTC().Truncate(time.Second),
+	}
+	if resp == nil {
+		return p
+	}
+	p.Status, p.Engine, p.Attestation = resp.Status, resp.Engine, resp.Attestation
+	if id := attestationID(resp.Attestation); id != "" {
+		p.Certificate = id
+	} else if resp.Metadata != nil {
+		p.Certificate = resp.Metadata.RequestID
+	}
+	return p
+}
+
+// Matches reports whether content is the artifact p was recorded for.
+func (p *Provenance) Matches(content []byte) bool {
+	return p != nil && p.ContentHash == contentHash(content)
+}
+
+func contentHash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// attestationID returns the "jti" of an attestation JWT without checking
+// its signature, or "" if token is not one.
+func attestationID(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+	var payload struct {
+		Jti string `json:"jti"`
+	}
+	if decodeSegment(parts[1], &payload) != nil {
+		return ""
+	}
+	return payload.Jti
+}
+
+// checked returns p if it matches content, or ErrProvenanceMismatch.
+func (p *Provenance) checked(content []byte) (*Provenance, error) {
+	if !p.Matches(content) {
+		return p, ErrProvenanceMismatch
+	}
+	return p, nil
+}
+
+// ============================================================================
+// JSON Sidecars
+// ============================================================================
+
+// SidecarPath returns the conventional sidecar file name for an artifact:
+// "report.md" is described by "report.md.qwed.json".
+func SidecarPath(artifact string) string {
+	return artifact + ".qwed.json"
+}
+
+// MarshalSidecar encodes p as a JSON sidecar document, for artifacts such as
+// text that cannot carry the record themselves.
+func MarshalSidecar(p *Provenance) ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// ParseSidecar decodes a JSON sidecar and checks it against content. A
+// record that does not match is returned with ErrProvenanceMismatch.
+func ParseSidecar(sidecar, content []byte) (*Provenance, error) {
+	var p Provenance
+	if err := json.Unmarshal(sidecar, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode provenance sidecar: %w", err)
+	}
+	return p.checked(content)
+}
+
+// ============================================================================
+// SQL Comments
+// ============================================================================
+
+// sqlProvenancePrefix starts the comment line carrying a SQL record.
+const sqlProvenancePrefix = "-- qwed:provenance "
+
+// EmbedSQL prepends p to query as a single-line comment, replacing any
+// record already present. Databases ignore the comment, and it shows up in
+// query logs.
+func EmbedSQL(query string, p *Provenance) (string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode provenance: %w", err)
+	}
+	_, query = splitSQLProvenance(query)
+	return sqlProvenancePrefix + string(data) + "\n" + query, nil
+}
+
+// ExtractSQL returns the record embedded by EmbedSQL and the query without
+// it, checking the record against the query.
+func ExtractSQL(query string) (*Provenance, string, error) {
+	line, rest := splitSQLProvenance(query)
+	if line == "" {
+		return nil, query, ErrNoProvenance
+	}
+	var p Provenance
+	if err := json.Unmarshal([]byte(line), &p); err != nil {
+		return nil, rest, fmt.Errorf("failed to decode provenance comment: %w", err)
+	}
+	record, err := p.checked([]byte(rest))
+	return record, rest, err
+}
+
+func splitSQLProvenance(query string) (string, string) {
+	if !strings.HasPrefix(query, sqlProvenancePrefix) {
+		return "", query
+	}
+	line, rest, _ := strings.Cut(strings.TrimPrefix(query, sqlProvenancePrefix), "\n")
+	return line, rest
+}
+
+// ============================================================================
+// PNG and JPEG Metadata
+// ============================================================================
+
+// provenanceKeyword names the PNG text chunk and prefixes the JPEG comment
+// carrying a record.
+const provenanceKeyword = "qwed:provenance"
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// EmbedPNG adds p to a PNG image, such as a rendered chart, as a tEXt chunk
+// before the image end, replacing any record already present. Image
+// viewers ignore the chunk.
+func EmbedPNG(png []byte, p *Provenance) ([]byte, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode provenance: %w", err)
+	}
+	chunks, err := pngChunks(png)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.Write(pngSignature)
+	for _, c := range chunks {
+		if c.provenance() {
+			continue
+		}
+		if c.typ == "IEND" {
+			writePNGChunk(&out, "tEXt", append([]byte(provenanceKeyword+"\x00"), data...))
+		}
+		out.Write(c.raw)
+	}
+	return out.Bytes(), nil
+}
+
+// ExtractPNG returns the record embedded by EmbedPNG, checking it against
+// the image without the record.
+func ExtractPNG(png []byte) (*Provenance, error) {
+	chunks, err := pngChunks(png)
+	if err != nil {
+		return nil, err
+	}
+
+	var record []byte
+	var original bytes.Buffer
+	original.Write(pngSignature)
+	for _, c := range chunks {
+		if c.provenance() {
+			record = c.data[len(provenanceKeyword)+1:]
+			continue
+		}
+		original.Write(c.raw)
+	}
+	if record == nil {
+		return nil, ErrNoProvenance
+	}
+
+	var p Provenance
+	if err := json.Unmarshal(record, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode provenance chunk: %w", err)
+	}
+	return p.checked(original.Bytes())
+}
+
+type pngChunk struct {
+	typ  string
+	data []byte
+	raw  []byte // length, type, data and CRC
+}
+
+func (c pngChunk) provenance() bool {
+	return c.typ == "tEXt" && bytes.HasPrefix(c.data, []byte(provenanceKeyword+"\x00"))
+}
+
+// pngChunks splits a PNG file into its chunks.
+func pngChunks(png []byte) ([]pngChunk, error) {
+	if !bytes.HasPrefix(png, pngSignature) {
+		return nil, fmt.Errorf("not a PNG image")
+	}
+	var chunks []pngChunk
+	for rest := png[len(pngSignature):]; len(rest) > 0; {
+		if len(rest) < 12 {
+			return nil, fmt.Errorf("truncated PNG chunk")
+		}
+		n := int(binary.BigEndian.Uint32(rest))
+		if len(rest) < 12+n {
+			return nil, fmt.Errorf("truncated PNG chunk")
+		}
+		chunks = append(chunks, pngChunk{typ: string(rest[4:8]), data: rest[8 : 8+n], raw: rest[:12+n]})
+		rest = rest[12+n:]
+	}
+	if len(chunks) == 0 || chunks[len(chunks)-1].typ != "IEND" {
+		return nil, fmt.Errorf("PNG image has no IEND chunk")
+	}
+	return chunks, nil
+}
+
+func writePNGChunk(w *bytes.Buffer, typ string, data []byte) {
+	var length [4]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(data)))
+	w.Write(length[:])
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+	w.WriteString(typ)
+	w.Write(data)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+	w.Write(sum[:])
+}
+
+// EmbedJPEG adds p to a JPEG image as a comment (COM) segment after the
+// start-of-image marker, replacing any record already present.
+func EmbedJPEG(jpeg []byte, p *Provenance) ([]byte, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode provenance: %w", err)
+	}
+	payload := append([]byte(provenanceKeyword+" "), data...)
+	if len(payload)+2 > 0xFFFF {
+		return nil, fmt.Errorf("provenance record too large for a JPEG comment")
+	}
+	original, _, err := splitJPEGProvenance(jpeg)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.Write(original[:2])
+	out.Write([]byte{0xFF, 0xFE, byte((len(payload) + 2) >> 8), byte(len(payload) + 2)})
+	out.Write(payload)
+	out.Write(original[2:])
+	return out.Bytes(), nil
+}
+
+// ExtractJPEG returns the record embedded by EmbedJPEG, checking it against
+// the image without the record.
+func ExtractJPEG(jpeg []byte) (*Provenance, error) {
+	original, record, err := splitJPEGProvenance(jpeg)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, ErrNoProvenance
+	}
+	var p Provenance
+	if err := json.Unmarshal(record, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode provenance comment: %w", err)
+	}
+	return p.checked(original)
+}
+
+// splitJPEGProvenance removes the provenance comment from a JPEG file,
+// returning the remaining image and the record, if any. Only the header
+// segments before the image data are searched.
+func splitJPEGProvenance(jpeg []byte) ([]byte, []byte, error) {
+	if len(jpeg) < 4 || jpeg[0] != 0xFF || jpeg[1] != 0xD8 {
+		return nil, nil, fmt.Errorf("not a JPEG image")
+	}
+	prefix := []byte(provenanceKeyword + " ")
+	for i := 2; i+4 <= len(jpeg); {
+		if jpeg[i] != 0xFF {
+			return nil, nil, fmt.Errorf("malformed JPEG segment")
+		}
+		marker := jpeg[i+1]
+		if marker == 0xDA || marker == 0xD9 { // start of scan or end of image
+			break
+		}
+		n := int(jpeg[i+2])<<8 | int(jpeg[i+3])
+		if n < 2 || i+2+n > len(jpeg) {
+			return nil, nil, fmt.Errorf("truncated JPEG segment")
+		}
+		if segment := jpeg[i+4 : i+2+n]; marker == 0xFE && bytes.HasPrefix(segment, prefix) {
+			original := append(append([]byte{}, jpeg[:i]...), jpeg[i+2+n:]...)
+			return original, segment[len(prefix):], nil
+		}
+		i += 2 + n
+	}
+	return jpeg, nil, nil
+}
@@ -0,0 +1,142 @@
+package qwed
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func testProvenance(content []byte) *Provenance {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"jti":"att_123"}`))
+	return NewProvenance(&VerificationResponse{
+		Status:      StatusVerified,
+		Verified:    true,
+		Engine:      "sql",
+		Attestation: "eyJhbGciOiJFUzI1NiJ9." + payload + ".sig",
+	}, content)
+}
+
+func TestNewProvenance(t *testing.T) {
+	p := testProvenance([]byte("SELECT 1"))
+	if p.Verdict != VerdictVerified || p.Engine != "sql" || p.Certificate != "att_123" || p.Timestamp.IsZero() {
+		t.Errorf("unexpected provenance: %+v", p)
+	}
+	if !p.Matches([]byte("SELECT 1")) || p.Matches([]byte("SELECT 2")) {
+		t.Error("expected the content hash to match only the original content")
+	}
+
+	p = NewProvenance(&VerificationResponse{Status: StatusFailed, Metadata: &ResponseMetadata{RequestID: "req_9"}}, nil)
+	if p.Verdict != VerdictRefuted || p.Certificate != "req_9" {
+		t.Errorf("expected the request ID as certificate, got %+v", p)
+	}
+}
+
+func TestSidecar(t *testing.T) {
+	content := []byte("The answer is 42.")
+	data, err := MarshalSidecar(testProvenance(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := ParseSidecar(data, content)
+	if err != nil || p.Certificate != "att_123" {
+		t.Errorf("expected the sidecar to round-trip, got %+v, %v", p, err)
+	}
+	if _, err := ParseSidecar(data, []byte("The answer is 43.")); !errors.Is(err, ErrProvenanceMismatch) {
+		t.Errorf("expected ErrProvenanceMismatch, got %v", err)
+	}
+	if SidecarPath("out/report.md") != "out/report.md.qwed.json" {
+		t.Errorf("unexpected sidecar path %q", SidecarPath("out/report.md"))
+	}
+}
+
+func TestEmbedSQL(t *testing.T) {
+	query := "SELECT name\nFROM users WHERE id = 1"
+	embedded, err := EmbedSQL(query, testProvenance([]byte(query)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(embedded, "-- qwed:provenance {") || !strings.HasSuffix(embedded, query) {
+		t.Errorf("unexpected embedded query %q", embedded)
+	}
+
+	// Re-embedding replaces the record rather than stacking comments.
+	embedded, _ = EmbedSQL(embedded, testProvenance([]byte(query)))
+	if strings.Count(embedded, "qwed:provenance") != 1 {
+		t.Errorf("expected one record, got %q", embedded)
+	}
+
+	p, rest, err := ExtractSQL(embedded)
+	if err != nil || rest != query || p.Engine != "sql" {
+		t.Errorf("expected the record and original query, got %+v %q %v", p, rest, err)
+	}
+	if _, _, err := ExtractSQL(strings.Replace(embedded, "id = 1", "id = 2", 1)); !errors.Is(err, ErrProvenanceMismatch) {
+		t.Errorf("expected ErrProvenanceMismatch for an edited query, got %v", err)
+	}
+	if _, _, err := ExtractSQL(query); !errors.Is(err, ErrNoProvenance) {
+		t.Errorf("expected ErrNoProvenance, got %v", err)
+	}
+}
+
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 1, color.RGBA{R: 255, A: 255})
+	return img
+}
+
+func TestEmbedPNG(t *testing.T) {
+	var buf bytes.Buffer
+	png.Encode(&buf, testImage())
+	original := buf.Bytes()
+
+	embedded, err := EmbedPNG(original, testProvenance(original))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(bytes.NewReader(embedded)); err != nil {
+		t.Fatalf("embedded PNG does not decode: %v", err)
+	}
+	embedded, _ = EmbedPNG(embedded, testProvenance(original))
+	if bytes.Count(embedded, []byte("qwed:provenance")) != 1 {
+		t.Error("expected re-embedding to replace the record")
+	}
+
+	p, err := ExtractPNG(embedded)
+	if err != nil || p.Certificate != "att_123" {
+		t.Errorf("expected the record, got %+v, %v", p, err)
+	}
+	if _, err := ExtractPNG(original); !errors.Is(err, ErrNoProvenance) {
+		t.Errorf("expected ErrNoProvenance, got %v", err)
+	}
+	if _, err := EmbedPNG([]byte("GIF89a"), p); err == nil {
+		t.Error("expected an error for a non-PNG file")
+	}
+}
+
+func TestEmbedJPEG(t *testing.T) {
+	var buf bytes.Buffer
+	jpeg.Encode(&buf, testImage(), nil)
+	original := buf.Bytes()
+
+	embedded, err := EmbedJPEG(original, testProvenance(original))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(embedded)); err != nil {
+		t.Fatalf("embedded JPEG does not decode: %v", err)
+	}
+	p, err := ExtractJPEG(embedded)
+	if err != nil || p.Engine != "sql" {
+		t.Errorf("expected the record, got %+v, %v", p, err)
+	}
+
+	tampered, _ := EmbedJPEG(original[:len(original)-10], p)
+	if _, err := ExtractJPEG(tampered); !errors.Is(err, ErrProvenanceMismatch) {
+		t.Errorf("expected ErrProvenanceMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package qwed
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Engine Queues
+// ============================================================================
+
+// EngineLimit configures an engine's share of a client's requests.
+type EngineLimit struct {
+	// Weight is the engine's share of dispatches relative to the other
+	// engines with queued requests. Zero means 1.
+	Weight int
+	// MaxConcurrent caps the engine's requests in flight. Zero means no
+	// cap.
+	MaxConcurrent int
+}
+
+// WithEngineLimits queues requests per engine and dispatches them with
+// weighted fair scheduling, so a flood of requests to one engine cannot
+// starve another behind the shared rate limiter. Requests marked with
+// WithPriority go ahead of lower priorities regardless of engine. Engines
+// missing from limits get weight 1 and no concurrency cap. Cached
+// responses and batch jobs are not queued.
+func WithEngineLimits(limits map[VerificationType]EngineLimit) ClientOption {
+	return func(c *Client) {
+		c.queues = newEngineQueues(limits)
+	}
+}
+
+// engineQueues is a stride scheduler over per-engine request queues. One
+// dispatched request at a time holds the turn while it waits on the rate
+// limiter, so the limiter admits requests in fair order rather than
+// arrival order.
+type engineQueues struct {
+	mu      sync.Mutex
+	limits  map[VerificationType]EngineLimit
+	engines map[VerificationType]*engineQueue
+	busy    bool    // a dispatched request holds the turn
+	vtime   float64 // pass of the last dispatch
+}
+
+type engineQueue struct {
+	engine  VerificationType
+	limit   EngineLimit
+	waiters waitQueue
+	active  int
+	pass    float64
+}
+
+func newEngineQueues(limits map[VerificationType]EngineLimit) *engineQueues {
+	return &engineQueues{
+		limits:  limits,
+		engines: make(map[VerificationType]*engineQueue),
+	}
+}
+
+// acquire waits for engine's turn and a concurrency slot, then for the rate
+// limiter. The returned function frees the slot once the request is done.
+func (q *engineQueues) acquire(ctx context.Context, engine VerificationType, limiter *RateLimiter) (func(), error) {
+	q.mu.Lock()
+	e := q.queue(engine)
+	if len(e.waiters) == 0 {
+		// An engine returning from idle must not spend credit it saved up
+		// while others were busy.
+		e.pass = max(e.pass, q.vtime)
+	}
+	w := e.waiters.push(PriorityFromContext(ctx))
+	q.dispatch()
+	q.mu.Unlock()
+
+	select {
+	case <-w.ready:
+	case <-ctx.Done():
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if !e.waiters.remove(w) {
+			// Dispatched while giving up: hand the turn and slot back.
+			q.busy = false
+			e.active--
+		}
+		q.dispatch()
+		return nil, ctx.Err()
+	}
+
+	err := limiter.Wait(ctx)
+
+	q.mu.Lock()
+	q.busy = false
+	if err != nil {
+		e.active--
+	}
+	q.dispatch()
+	q.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		q.mu.Lock()
+		e.active--
+		q.dispatch()
+		q.mu.Unlock()
+	}, nil
+}
+
+// queue returns engine's queue, creating it on first use. The caller must
+// hold q.mu.
+func (q *engineQueues) queue(engine VerificationType) *engineQueue {
+	e, ok := q.engines[engine]
+	if !ok {
+		e = &engineQueue{engine: engine, limit: q.limits[engine], pass: q.vtime}
+		if e.limit.Weight <= 0 {
+			e.limit.Weight = 1
+		}
+		q.engines[engine] = e
+	}
+	return e
+}
+
+// dispatch hands the turn to the highest priority request at the head of
+// an engine queue below its concurrency cap, choosing the engine with the
+// lowest pass among equal priorities. The caller must hold q.mu.
+func (q *engineQueues) dispatch() {
+	if q.busy {
+		return
+	}
+
+	var next *engineQueue
+	for _, e := range q.engines {
+		if len(e.waiters) == 0 || (e.limit.MaxConcurrent > 0 && e.active >= e.limit.MaxConcurrent) {
+			continue
+		}
+		if next == nil {
+			next = e
+			continue
+		}
+		p, np := e.waiters[0].priority, next.waiters[0].priority
+		if p > np || (p == np && (e.pass < next.pass || (e.pass == next.pass && e.engine < next.engine))) {
+			next = e
+		}
+	}
+	if next == nil {
+		return
+	}
+
+	w := next.waiters.pop()
+	next.active++
+	q.vtime = next.pass
+	next.pass += 1 / float64(next.limit.Weight)
+	q.busy = true
+	close(w.ready)
+}
+
+// queued waits for a slot in engine's queue when engine limits are
+// configured, and returns ctx with rate limiting already applied.
+func (c *Client) queued(ctx context.Context, engine VerificationType) (context.Context, func(), error) {
+	if c.queues == nil {
+		return ctx, func() {}, nil
+	}
+	start := time.Now()
+	release, err := c.queues.acquire(ctx, engine, c.rateLimiter(ctx))
+	if err != nil {
+		return ctx, nil, fmt.Errorf("engine queue wait failed: %w", err)
+	}
+	c.logWait(ctx, "qwed engine queue wait", start, slog.String("engine", string(engine)))
+	return ContextWithRateLimiter(ctx, nil), release, nil
+}
@@ -0,0 +1,182 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// dispatchOrder queues n requests per engine and returns the order the
+// scheduler dispatches them in.
+func dispatchOrder(q *engineQueues, n map[VerificationType]int) []VerificationType {
+	waiters := map[chan struct{}]VerificationType{}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.busy = true
+	for engine, count := range n {
+		e := q.queue(engine)
+		for i := 0; i < count; i++ {
+			waiters[e.waiters.push(Normal).ready] = engine
+		}
+	}
+
+	var order []VerificationType
+	for len(waiters) > 0 {
+		q.busy = false
+		q.dispatch()
+		for ready, engine := range waiters {
+			select {
+			case <-ready:
+				order = append(order, engine)
+				delete(waiters, ready)
+			default:
+			}
+		}
+	}
+	return order
+}
+
+func TestEngineQueuesWeights(t *testing.T) {
+	q := newEngineQueues(map[VerificationType]EngineLimit{TypeSQL: {Weight: 3}})
+	order := dispatchOrder(q, map[VerificationType]int{TypeMath: 4, TypeSQL: 12})
+
+	// In every window of four dispatches, SQL gets three.
+	for i := 0; i+4 <= 16; i += 4 {
+		sql := 0
+		for _, engine := range order[i : i+4] {
+			if engine == TypeSQL {
+				sql++
+			}
+		}
+		if sql != 3 {
+			t.Fatalf("dispatch order %v: window %d has %d SQL requests, want 3", order, i/4, sql)
+		}
+	}
+}
+
+func TestEngineQueuesIdleEngineGetsNoBacklogCredit(t *testing.T) {
+	q := newEngineQueues(nil)
+	dispatchOrder(q, map[VerificationType]int{TypeMath: 10})
+
+	order := dispatchOrder(q, map[VerificationType]int{TypeMath: 4, TypeSQL: 4})
+	for i := 0; i < len(order); i += 2 {
+		if order[i] == order[i+1] {
+			t.Fatalf("dispatch order %v, want engines to alternate", order)
+		}
+	}
+}
+
+func TestWithEngineLimitsPreventsStarvation(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, strings.TrimPrefix(r.URL.Path, "/verify/"))
+		mu.Unlock()
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithRateLimit(200, 1), WithEngineLimits(nil))
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client.VerifyMath(ctx, "1+1="+strings.Repeat("1", i+1))
+		}(i)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := client.VerifySQL(ctx, "SELECT 1", "", "postgres"); err != nil {
+		t.Fatalf("VerifySQL() error = %v", err)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, engine := range arrivals {
+		if engine == "sql" {
+			if i > 10 {
+				t.Errorf("SQL request served at position %d of %d, behind the math flood", i, len(arrivals))
+			}
+			return
+		}
+	}
+	t.Fatal("SQL request not served")
+}
+
+func TestWithEngineLimitsConcurrencyCap(t *testing.T) {
+	var inFlight, peak int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL),
+		WithEngineLimits(map[VerificationType]EngineLimit{TypeMath: {MaxConcurrent: 2}}))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := client.VerifyMath(context.Background(), "2+2="+strings.Repeat("4", i+1)); err != nil {
+				t.Errorf("VerifyMath() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak)
+	}
+}
+
+func TestWithEngineLimitsCancelWhileQueued(t *testing.T) {
+	release := make(chan struct{})
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL),
+		WithEngineLimits(map[VerificationType]EngineLimit{TypeMath: {MaxConcurrent: 1}}))
+
+	done := make(chan error)
+	go func() {
+		_, err := client.VerifyMath(context.Background(), "1+1=2")
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.VerifyMath(ctx, "2+2=4"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("queued VerifyMath() error = %v, want deadline exceeded", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("VerifyMath() error = %v", err)
+	}
+	if _, err := client.VerifyMath(context.Background(), "3+3=6"); err != nil {
+		t.Errorf("VerifyMath() after cancellation error = %v", err)
+	}
+}
@@ -8,15 +8,28 @@
 //	    log.Fatal(err)
 //	}
 //	fmt.Println(result.Verified) // true
+//
+// VerifyDateTime, VerifyFormat, VerifyFormula, VerifyProbability,
+// VerifyRegex, VerifyTable and VerifyUnits run locally without calling the
+// API. They are traced and recorded in metrics like the other verification
+// calls.
 package qwed
 
 import (
 	"bytes"
 	"context"
+	"crypto"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -37,45 +50,92 @@ const (
 	TypeSQL             VerificationType = "sql"
 	TypeImage           VerificationType = "image"
 	TypeReasoning       VerificationType = "reasoning"
+	TypeJSON            VerificationType = "json"
+	TypePromptSafety    VerificationType = "prompt_safety"
+	TypeInfra           VerificationType = "infra"
+	TypeGraphQL         VerificationType = "graphql"
+	TypeChart           VerificationType = "chart"
+	TypeTests           VerificationType = "tests"
+	TypeConstraints     VerificationType = "constraints"
+	TypeOptimization    VerificationType = "optimization"
+	TypeGraph           VerificationType = "graph"
+	TypeCompliance      VerificationType = "compliance"
+	TypeExtraction      VerificationType = "extraction"
 )
 
-// VerificationStatus represents the result status.
-type VerificationStatus string
+// Status represents the result status.
+type Status string
+
+// VerificationStatus is the former name of Status.
+//
+// Deprecated: Use Status.
+type VerificationStatus = Status
 
 const (
-	StatusVerified    VerificationStatus = "VERIFIED"
-	StatusFailed      VerificationStatus = "FAILED"
-	StatusCorrected   VerificationStatus = "CORRECTED"
-	StatusBlocked     VerificationStatus = "BLOCKED"
-	StatusError       VerificationStatus = "ERROR"
-	StatusTimeout     VerificationStatus = "TIMEOUT"
-	StatusUnsupported VerificationStatus = "UNSUPPORTED"
+	StatusVerified    Status = "VERIFIED"
+	StatusFailed      Status = "FAILED"
+	StatusCorrected   Status = "CORRECTED"
+	StatusBlocked     Status = "BLOCKED"
+	StatusError       Status = "ERROR"
+	StatusTimeout     Status = "TIMEOUT"
+	StatusUnsupported Status = "UNSUPPORTED"
+
+	// StatusUnknown is the status of results whose status this SDK does
+	// not know, such as one added to the API after this release.
+	StatusUnknown Status = "UNKNOWN"
 )
 
 // VerificationRequest represents a verification request.
 type VerificationRequest struct {
-	Query   string                 `json:"query"`
-	Type    VerificationType       `json:"type,omitempty"`
-	Params  map[string]interface{} `json:"params,omitempty"`
-	Options *RequestOptions        `json:"options,omitempty"`
+	Query    string                 `json:"query"`
+	Type     VerificationType       `json:"type,omitempty"`
+	Params   map[string]interface{} `json:"params,omitempty"`
+	Options  *RequestOptions        `json:"options,omitempty"`
+	Metadata map[string]string      `json:"metadata,omitempty"`
 }
 
 // RequestOptions configures request behavior.
 type RequestOptions struct {
-	TimeoutMs            int  `json:"timeout_ms,omitempty"`
-	IncludeProof         bool `json:"include_proof,omitempty"`
-	IncludeAttestation   bool `json:"include_attestation,omitempty"`
+	TimeoutMs          int          `json:"timeout_ms,omitempty"`
+	IncludeProof       bool         `json:"include_proof,omitempty"`
+	IncludeAttestation bool         `json:"include_attestation,omitempty"`
+	OutputFormat       OutputFormat `json:"output_format,omitempty"`
+	CheckMode          CheckMode    `json:"check_mode,omitempty"`
+	// EnginePin runs the request on one engine version, e.g. "code@3.2.1",
+	// for reproducible results. Build it with EnginePin; the API ignores a
+	// pin naming another engine.
+	EnginePin string      `json:"engine_pin,omitempty"`
+	Rules     *RuleConfig `json:"rule_config,omitempty"`
 }
 
+// OutputFormat selects an alternative result encoding from the API.
+type OutputFormat string
+
+const (
+	// OutputSARIF asks the code engine to include a SARIF 2.1.0 log in
+	// Result["sarif"].
+	OutputSARIF OutputFormat = "sarif"
+)
+
 // VerificationResponse represents the API response.
 type VerificationResponse struct {
-	Status      VerificationStatus     `json:"status"`
+	Status      Status                 `json:"status"`
 	Verified    bool                   `json:"verified"`
-	Engine      string                 `json:"engine,omitempty"`
+	Engine      Engine                 `json:"engine,omitempty"`
 	Result      map[string]interface{} `json:"result,omitempty"`
 	Attestation string                 `json:"attestation,omitempty"`
 	Error       *ErrorInfo             `json:"error,omitempty"`
 	Metadata    *ResponseMetadata      `json:"metadata,omitempty"`
+
+	// RateLimit is the API rate limit state when the response was
+	// received, or nil for responses from the cache or computed locally.
+	RateLimit *RateLimit `json:"-"`
+
+	// SchemaVersion is the response schema version; see
+	// CurrentSchemaVersion. Raw holds fields this SDK does not know,
+	// which are kept when the response is encoded again.
+	SchemaVersion int                        `json:"schema_version,omitempty"`
+	Raw           map[string]json.RawMessage `json:"-"`
 }
 
 // ErrorInfo contains error details.
@@ -90,12 +150,16 @@ type ResponseMetadata struct {
 	RequestID       string  `json:"request_id,omitempty"`
 	LatencyMs       float64 `json:"latency_ms,omitempty"`
 	ProtocolVersion string  `json:"protocol_version,omitempty"`
+	EngineVersion   string  `json:"engine_version,omitempty"` // version of the engine that ran, e.g. "3.2.1"
+
+	// IdempotencyKey is the key the request was sent with, if any.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
 // BatchRequest represents a batch verification request.
 type BatchRequest struct {
-	Items   []BatchItem    `json:"items"`
-	Options *BatchOptions  `json:"options,omitempty"`
+	Items   []BatchItem   `json:"items"`
+	Options *BatchOptions `json:"options,omitempty"`
 }
 
 // BatchItem represents a single item in a batch.
@@ -108,6 +172,21 @@ type BatchItem struct {
 type BatchOptions struct {
 	MaxParallel int  `json:"max_parallel,omitempty"`
 	FailFast    bool `json:"fail_fast,omitempty"`
+	TimeoutMs   int  `json:"timeout_ms,omitempty"` // engine time limit per item
+
+	// WebhookURL receives a BatchEvent when the job finishes; see
+	// WebhookHandler. Each job of a chunked batch sends its own event.
+	WebhookURL string `json:"webhook_url,omitempty"`
+
+	// ChunkSize splits batches of more items into jobs of at most ChunkSize
+	// items, tracked together as one composite job. Zero submits one job.
+	ChunkSize int `json:"-"`
+
+	// OnProgress makes VerifyBatch wait for the job to finish, polling it
+	// every PollInterval (default 1 second) and reporting its progress
+	// after each poll. VerifyBatch then returns the finished job.
+	OnProgress   func(BatchProgress) `json:"-"`
+	PollInterval time.Duration       `json:"-"`
 }
 
 // BatchResponse represents the batch API response.
@@ -116,6 +195,14 @@ type BatchResponse struct {
 	Status  string        `json:"status"`
 	Summary *BatchSummary `json:"summary,omitempty"`
 	Items   []BatchResult `json:"items,omitempty"`
+
+	// IdempotencyKey is the key the batch was submitted with, if any.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// RetryOf is the job whose failed items this job retries, if any.
+	RetryOf string `json:"retry_of,omitempty"`
+
+	SchemaVersion int                        `json:"schema_version,omitempty"`
+	Raw           map[string]json.RawMessage `json:"-"`
 }
 
 // BatchSummary contains batch statistics.
@@ -129,27 +216,78 @@ type BatchSummary struct {
 // BatchResult represents a single batch item result.
 type BatchResult struct {
 	ID       string                 `json:"id"`
-	Status   VerificationStatus     `json:"status"`
+	Status   Status                 `json:"status"`
 	Verified bool                   `json:"verified"`
 	Result   map[string]interface{} `json:"result,omitempty"`
 	Error    *ErrorInfo             `json:"error,omitempty"`
+
+	SchemaVersion int                        `json:"schema_version,omitempty"`
+	Raw           map[string]json.RawMessage `json:"-"`
 }
 
 // ============================================================================
 // Errors
 // ============================================================================
 
+// Sentinel errors classifying API failures. A QWEDError unwraps to one of
+// them, so callers can test the kind of failure with errors.Is:
+//
+//	if errors.Is(err, qwed.ErrRateLimited) { ... }
+var (
+	ErrUnauthorized      = errors.New("qwed: unauthorized")
+	ErrRateLimited       = errors.New("qwed: rate limited")
+	ErrQuotaExceeded     = errors.New("qwed: quota exceeded")
+	ErrInvalidRequest    = errors.New("qwed: invalid request")
+	ErrEngineUnavailable = errors.New("qwed: engine unavailable")
+)
+
 // QWEDError represents a QWED API error.
 type QWEDError struct {
 	Code       string
 	Message    string
 	StatusCode int
+	RateLimit  *RateLimit // the rate limit state reported with the error, if any
 }
 
 func (e *QWEDError) Error() string {
 	return fmt.Sprintf("QWED Error [%s]: %s", e.Code, e.Message)
 }
 
+// Unwrap returns the sentinel error matching the failure, or nil if the
+// failure is not classified.
+func (e *QWEDError) Unwrap() error {
+	if strings.Contains(strings.ToUpper(e.Code), "QUOTA") {
+		return ErrQuotaExceeded
+	}
+	switch {
+	case e.StatusCode == http.StatusUnauthorized, e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusPaymentRequired:
+		return ErrQuotaExceeded
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode == http.StatusBadRequest, e.StatusCode == http.StatusUnprocessableEntity:
+		return ErrInvalidRequest
+	case e.StatusCode >= 500:
+		return ErrEngineUnavailable
+	}
+	return nil
+}
+
+// IsRetryable reports whether retrying the call that returned err may
+// succeed: rate limiting, server errors and network failures. Cancelled or
+// timed-out contexts and ErrCircuitOpen are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, ErrEngineUnavailable) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
 // ============================================================================
 // Verifier Interface (for mocking in tests)
 // ============================================================================
@@ -158,14 +296,14 @@ func (e *QWEDError) Error() string {
 // Users can implement this interface to create mock clients for testing.
 type Verifier interface {
 	Health(ctx context.Context) (map[string]interface{}, error)
-	Verify(ctx context.Context, query string) (*VerificationResponse, error)
-	VerifyWithOptions(ctx context.Context, query string, opts *RequestOptions) (*VerificationResponse, error)
-	VerifyMath(ctx context.Context, expression string) (*VerificationResponse, error)
-	VerifyLogic(ctx context.Context, query string) (*VerificationResponse, error)
-	VerifyCode(ctx context.Context, code, language string) (*VerificationResponse, error)
-	VerifyFact(ctx context.Context, claim, factContext string) (*VerificationResponse, error)
-	VerifySQL(ctx context.Context, query, schemaDDL, dialect string) (*VerificationResponse, error)
-	VerifyBatch(ctx context.Context, items []BatchItem, opts *BatchOptions) (*BatchResponse, error)
+	Verify(ctx context.Context, query string, callOpts ...CallOption) (*VerificationResponse, error)
+	VerifyWithOptions(ctx context.Context, query string, opts *RequestOptions, callOpts ...CallOption) (*VerificationResponse, error)
+	VerifyMath(ctx context.Context, expression string, callOpts ...CallOption) (*VerificationResponse, error)
+	VerifyLogic(ctx context.Context, query string, callOpts ...CallOption) (*VerificationResponse, error)
+	VerifyCode(ctx context.Context, code, language string, callOpts ...CallOption) (*VerificationResponse, error)
+	VerifyFact(ctx context.Context, claim, factContext string, callOpts ...CallOption) (*VerificationResponse, error)
+	VerifySQL(ctx context.Context, query, schemaDDL, dialect string, callOpts ...CallOption) (*VerificationResponse, error)
+	VerifyBatch(ctx context.Context, items []BatchItem, opts *BatchOptions, callOpts ...CallOption) (*BatchResponse, error)
 }
 
 // Ensure Client implements Verifier
@@ -177,9 +315,44 @@ var _ Verifier = (*Client)(nil)
 
 // Client is the QWED API client.
 type Client struct {
-	apiKey     string
-	baseURL    string
-	httpClient *http.Client
+	keys        *keyRing
+	tokens      *tokenCache // nil unless WithTokenSource is used
+	tokenStore  Store
+	tokenKey    string
+	baseURL     string
+	httpClient  *http.Client
+	cache       Cache
+	cacheTTL    time.Duration
+	tracer      Tracer
+	metrics     Collector
+	offline     map[VerificationType]bool
+	rules       *RuleConfig
+	policy      *Policy
+	rulePacks   *RulePackLoader
+	limiter     *RateLimiter
+	queues      *engineQueues
+	adaptive    *adaptiveLimiter
+	strict      bool
+	idempotency bool
+	failover    *failoverState
+	hooks       []Hooks
+	logger      *slog.Logger
+	breaker     *circuitBreaker
+	shadow      *shadowSampler
+	canary      *canarySampler
+	budget      time.Duration
+	budgetMode  BudgetMode
+
+	attestationKey crypto.PublicKey
+	tlsConfig      *tls.Config
+	proxy          *url.URL
+	unixSocket     string
+	optionErr      error // from loading TLS files or parsing options, returned by every request
+	signingSecret  string
+	rateLimit      atomic.Pointer[RateLimit] // from the latest response
+
+	interceptors []Interceptor
+	invoker      Invoker
 }
 
 // ClientOption configures the client.
@@ -209,7 +382,7 @@ func WithHTTPClient(client *http.Client) ClientOption {
 // NewClient creates a new QWED client.
 func NewClient(apiKey string, opts ...ClientOption) *Client {
 	c := &Client{
-		apiKey:  apiKey,
+		keys:    newKeyRing(apiKey),
 		baseURL: "http://localhost:8000",
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
@@ -219,6 +392,10 @@ func NewClient(apiKey string, opts ...ClientOption) *Client {
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.applyPolicy()
+	c.applyTransport()
+	c.applyTokens()
+	c.invoker = chain(c.interceptors, c.send)
 
 	return c
 }
@@ -229,141 +406,343 @@ func NewClient(apiKey string, opts ...ClientOption) *Client {
 
 // Health checks the API health status.
 func (c *Client) Health(ctx context.Context) (map[string]interface{}, error) {
+	ctx, end := c.instrument(ctx, "Health", "")
 	var result map[string]interface{}
 	err := c.request(ctx, "GET", "/health", nil, &result)
+	end(nil, err)
 	return result, err
 }
 
 // Verify performs a natural language verification.
-func (c *Client) Verify(ctx context.Context, query string) (*VerificationResponse, error) {
-	return c.VerifyWithOptions(ctx, query, nil)
+func (c *Client) Verify(ctx context.Context, query string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return c.VerifyWithOptions(ctx, query, nil, callOpts...)
 }
 
 // VerifyWithOptions performs verification with custom options.
-func (c *Client) VerifyWithOptions(ctx context.Context, query string, opts *RequestOptions) (*VerificationResponse, error) {
+func (c *Client) VerifyWithOptions(ctx context.Context, query string, opts *RequestOptions, callOpts ...CallOption) (*VerificationResponse, error) {
+	opts = c.requestOptions(opts)
 	req := &VerificationRequest{
 		Query:   query,
 		Type:    TypeNaturalLanguage,
 		Options: opts,
 	}
 
-	var resp VerificationResponse
-	err := c.request(ctx, "POST", "/verify/natural_language", req, &resp)
-	return &resp, err
+	return c.verify(ctx, "Verify", TypeNaturalLanguage, CacheKey(TypeNaturalLanguage, query, optionsKey(opts)), req, callOpts...)
 }
 
 // VerifyMath verifies a mathematical expression.
-func (c *Client) VerifyMath(ctx context.Context, expression string) (*VerificationResponse, error) {
-	req := map[string]interface{}{
-		"expression": expression,
-	}
-
-	var resp VerificationResponse
-	err := c.request(ctx, "POST", "/verify/math", req, &resp)
-	return &resp, err
+func (c *Client) VerifyMath(ctx context.Context, expression string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return c.VerifyMathWithOptions(ctx, expression, nil, callOpts...)
 }
 
 // VerifyLogic verifies a QWED-Logic DSL expression.
-func (c *Client) VerifyLogic(ctx context.Context, query string) (*VerificationResponse, error) {
+func (c *Client) VerifyLogic(ctx context.Context, query string, callOpts ...CallOption) (*VerificationResponse, error) {
 	req := map[string]interface{}{
 		"query": query,
 	}
 
-	var resp VerificationResponse
-	err := c.request(ctx, "POST", "/verify/logic", req, &resp)
-	return &resp, err
+	resp, err := c.verify(ctx, "VerifyLogic", TypeLogic, CacheKey(TypeLogic, query), req, callOpts...)
+	return c.fallback(ctx, TypeLogic, query, resp, err)
+}
+
+// VerifyCode checks code for security vulnerabilities. Findings covered by
+// inline "qwed:ignore" comments in code are marked as suppressed.
+func (c *Client) VerifyCode(ctx context.Context, code, language string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return c.VerifyCodeWithOptions(ctx, code, language, nil, callOpts...)
 }
 
-// VerifyCode checks code for security vulnerabilities.
-func (c *Client) VerifyCode(ctx context.Context, code, language string) (*VerificationResponse, error) {
+// VerifyCodeWithOptions checks code with custom options, for example
+// OutputFormat: OutputSARIF to receive findings as SARIF.
+func (c *Client) VerifyCodeWithOptions(ctx context.Context, code, language string, opts *RequestOptions, callOpts ...CallOption) (*VerificationResponse, error) {
 	req := map[string]interface{}{
 		"code":     code,
 		"language": language,
 	}
 
-	var resp VerificationResponse
-	err := c.request(ctx, "POST", "/verify/code", req, &resp)
-	return &resp, err
-}
+	if opts = c.requestOptions(opts); opts != nil {
+		req["options"] = opts
+	}
 
-// VerifyFact verifies a factual claim against context.
-func (c *Client) VerifyFact(ctx context.Context, claim, factContext string) (*VerificationResponse, error) {
-	req := map[string]interface{}{
-		"claim":   claim,
-		"context": factContext,
+	resp, err := c.verify(ctx, "VerifyCode", TypeCode, CacheKey(TypeCode, language, code, optionsKey(opts)), req, callOpts...)
+	if err == nil {
+		resp = annotateSuppressions(code, resp)
 	}
+	return resp, err
+}
 
-	var resp VerificationResponse
-	err := c.request(ctx, "POST", "/verify/fact", req, &resp)
-	return &resp, err
+// VerifyFact verifies a factual claim against context. The languages of
+// the claim and context are detected automatically.
+func (c *Client) VerifyFact(ctx context.Context, claim, factContext string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return c.VerifyFactWithOptions(ctx, claim, factContext, nil, callOpts...)
 }
 
 // VerifySQL validates a SQL query against a schema.
-func (c *Client) VerifySQL(ctx context.Context, query, schemaDDL, dialect string) (*VerificationResponse, error) {
+func (c *Client) VerifySQL(ctx context.Context, query, schemaDDL, dialect string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return c.VerifySQLWithOptions(ctx, query, schemaDDL, dialect, nil, callOpts...)
+}
+
+// VerifySQLWithOptions validates a SQL query with custom options, such as a
+// per-call RuleConfig.
+func (c *Client) VerifySQLWithOptions(ctx context.Context, query, schemaDDL, dialect string, opts *RequestOptions, callOpts ...CallOption) (*VerificationResponse, error) {
 	req := map[string]interface{}{
 		"query":      query,
 		"schema_ddl": schemaDDL,
 		"dialect":    dialect,
 	}
 
-	var resp VerificationResponse
-	err := c.request(ctx, "POST", "/verify/sql", req, &resp)
-	return &resp, err
+	if opts = c.requestOptions(opts); opts != nil {
+		req["options"] = opts
+	}
+
+	return c.verify(ctx, "VerifySQL", TypeSQL, CacheKey(TypeSQL, dialect, schemaDDL, query, optionsKey(opts)), req, callOpts...)
+}
+
+// VerifyBatch processes multiple verifications concurrently. With
+// BatchOptions.ChunkSize set, larger batches are submitted as several jobs
+// and reported as one composite job; see verifyChunked. With
+// BatchOptions.OnProgress set, it waits for the job to finish.
+func (c *Client) VerifyBatch(ctx context.Context, items []BatchItem, opts *BatchOptions, callOpts ...CallOption) (*BatchResponse, error) {
+	var resp *BatchResponse
+	var err error
+	if opts != nil && opts.ChunkSize > 0 && len(items) > opts.ChunkSize {
+		resp, err = c.verifyChunked(ctx, items, opts, callOpts...)
+	} else {
+		resp, err = c.submitBatch(ctx, items, opts, callOpts...)
+	}
+	if err != nil || opts == nil || opts.OnProgress == nil {
+		return resp, err
+	}
+	return c.waitBatch(ctx, resp, len(items), opts)
 }
 
-// VerifyBatch processes multiple verifications concurrently.
-func (c *Client) VerifyBatch(ctx context.Context, items []BatchItem, opts *BatchOptions) (*BatchResponse, error) {
+// submitBatch submits items as one batch job.
+func (c *Client) submitBatch(ctx context.Context, items []BatchItem, opts *BatchOptions, callOpts ...CallOption) (*BatchResponse, error) {
+	if c.strict {
+		if err := validateBatch(items); err != nil {
+			return nil, err
+		}
+	}
+
 	req := map[string]interface{}{
 		"items":   items,
 		"options": opts,
 	}
+	call := newCallOptions(callOpts)
+	ctx = call.apply(ctx, req)
+	key := call.idempotencyKey
+	if key == "" && c.idempotency {
+		key = batchIdempotencyKey(req)
+		ctx = context.WithValue(ctx, idempotencyKeyKey{}, key)
+	}
 
+	ctx, end := c.instrument(ctx, "VerifyBatch", "batch")
+	start := time.Now()
 	var resp BatchResponse
 	err := c.request(ctx, "POST", "/verify/batch", req, &resp)
+	end(nil, err)
+	if c.metrics != nil {
+		c.metrics.RecordBatch(len(items), time.Since(start), err)
+	}
+	if err == nil && key != "" {
+		resp.IdempotencyKey = key
+	}
 	return &resp, err
 }
 
+// Batch job states reported in BatchResponse.Status.
+const (
+	BatchPending    = "pending"
+	BatchProcessing = "processing"
+	BatchCompleted  = "completed"
+	BatchPartial    = "partial"
+	BatchFailed     = "failed"
+)
+
+// GetBatch fetches the status and results of a batch job, for polling
+// batches that are still pending or processing.
+func (c *Client) GetBatch(ctx context.Context, jobID string) (*BatchResponse, error) {
+	if jobs, ok := chunkJobs(jobID); ok {
+		return c.getChunked(ctx, jobID, jobs)
+	}
+	var resp BatchResponse
+	if err := c.request(ctx, "GET", "/verify/batch/"+url.PathEscape(jobID), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
 // ============================================================================
 // HTTP Helpers
 // ============================================================================
 
+// verify posts a verification request to the engine's endpoint through the
+// interceptor chain, or samples it for background verification in shadow
+// mode. op names the public method for tracing and key is the cache key,
+// empty if the call must not be cached.
+func (c *Client) verify(ctx context.Context, op string, engine VerificationType, key string, body interface{}, callOpts ...CallOption) (*VerificationResponse, error) {
+	call := newCallOptions(callOpts)
+	if call.idempotencyKey == "" && c.idempotency {
+		call.idempotencyKey = newIdempotencyKey()
+	}
+	ctx = call.apply(ctx, body)
+	req := &Request{
+		Op:       op,
+		Engine:   engine,
+		Path:     "/verify/" + string(engine),
+		Body:     body,
+		CacheKey: key,
+		NoCache:  call.noCache,
+	}
+	if c.strict {
+		if err := validateRequest(engine, body); err != nil {
+			return nil, err
+		}
+	}
+	if c.shadow != nil {
+		return withIdempotencyKey(c.shadowVerify(ctx, req), call.idempotencyKey), nil
+	}
+	if c.canary != nil {
+		report := c.canaryVerify(ctx, req)
+		resp, err := c.invoke(ctx, req)
+		report(resp)
+		return withIdempotencyKey(resp, call.idempotencyKey), err
+	}
+	resp, err := c.invoke(ctx, req)
+	return withIdempotencyKey(resp, call.idempotencyKey), err
+}
+
+// invoke instruments req and passes it through the interceptor chain.
+func (c *Client) invoke(ctx context.Context, req *Request) (resp *VerificationResponse, err error) {
+	ctx, end := c.instrument(ctx, req.Op, req.Engine)
+	defer func() { end(resp, err) }()
+
+	if c.budget > 0 {
+		return c.invokeWithinBudget(ctx, req)
+	}
+	return c.invoker(ctx, req)
+}
+
+// send is the innermost Invoker: it consults the response cache and
+// performs the HTTP request.
+func (c *Client) send(ctx context.Context, req *Request) (*VerificationResponse, error) {
+	fetch := func(ctx context.Context) (*VerificationResponse, error) {
+		ctx, release, err := c.queued(ctx, req.Engine)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		resp := &VerificationResponse{}
+		if err := c.request(ctx, "POST", req.Path, req.Body, resp); err != nil {
+			return resp, err
+		}
+		if err := c.checkAttestation(req, resp); err != nil {
+			return nil, err
+		}
+		c.cacheSet(ctx, req.CacheKey, resp)
+		return resp, nil
+	}
+
+	if req.NoCache {
+		return fetch(ctx)
+	}
+	if loader, ok := c.cache.(LoadingCache); ok && req.CacheKey != "" {
+		return loader.Load(ctx, req.CacheKey, fetch)
+	}
+	if cached, ok := c.cacheGet(ctx, req.CacheKey); ok {
+		c.log(ctx, slog.LevelDebug, "qwed cache hit", slog.String("op", req.Op), slog.String("engine", string(req.Engine)))
+		return cached, nil
+	}
+	return fetch(ctx)
+}
+
 func (c *Client) request(ctx context.Context, method, path string, body, result interface{}) error {
-	var bodyReader io.Reader
+	if c.optionErr != nil {
+		return c.optionErr
+	}
+
+	var data []byte
 	if body != nil {
-		data, err := json.Marshal(body)
-		if err != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
 			return fmt.Errorf("failed to marshal request: %w", err)
 		}
+	}
+
+	start := time.Now()
+	if err := c.rateLimiter(ctx).Wait(ctx); err != nil {
+		return fmt.Errorf("rate limit wait failed: %w", err)
+	}
+	c.logWait(ctx, "qwed rate limit wait", start, slog.String("path", path))
+
+	for migrated := false; ; migrated = true {
+		base, slot, done := c.endpoint()
+		err := c.roundTripKeys(ctx, method, base+path, data, result)
+		done()
+		if migrated || !c.failover.migrate(ctx, slot, err) {
+			return err
+		}
+		c.log(ctx, slog.LevelInfo, "qwed retrying request after failover",
+			slog.String("path", path), slog.String("endpoint", c.ActiveEndpoint()), slog.Any("error", err))
+	}
+}
+
+// roundTrip performs one HTTP request with key, an API key or bearer
+// token, and decodes the response into result.
+func (c *Client) roundTrip(ctx context.Context, method, url, key string, data []byte, result interface{}) error {
+	var bodyReader io.Reader
+	if data != nil {
 		bodyReader = bytes.NewReader(data)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", c.apiKey)
+	c.setAuth(req.Header, key)
+	c.signRequest(req.Header, data)
+	setDeadlineHeader(ctx, req.Header)
+	setIdempotencyHeader(ctx, req.Header)
 
+	start, err := c.adaptive.acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("concurrency limit wait failed: %w", err)
+	}
+	gen, err := c.breaker.allow()
+	if err != nil {
+		c.adaptive.done(ctx, start, nil, nil)
+		return err
+	}
+	c.beforeRequest(req, data)
 	resp, err := c.httpClient.Do(req)
+	c.adaptive.done(ctx, start, resp, err)
+	c.breaker.done(ctx, gen, resp, err)
 	if err != nil {
+		c.afterResponse(req, nil, nil, err)
 		return fmt.Errorf("request failed: %w", err)
 	}
-	defer resp.Body.Close()
-
-	data, err := io.ReadAll(resp.Body)
+	respData, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
 	if err != nil {
+		c.afterResponse(req, nil, nil, err)
 		return fmt.Errorf("failed to read response: %w", err)
 	}
+	c.afterResponse(req, resp, respData, nil)
+	limit := parseRateLimit(resp.Header, time.Now())
+	if limit != nil {
+		c.rateLimit.Store(limit)
+	}
 
 	if resp.StatusCode >= 400 {
 		var errResp struct {
 			Error *ErrorInfo `json:"error"`
 		}
-		json.Unmarshal(data, &errResp)
+		json.Unmarshal(respData, &errResp)
 
 		code := fmt.Sprintf("HTTP-%d", resp.StatusCode)
-		message := string(data)
+		message := string(respData)
 		if errResp.Error != nil {
 			code = errResp.Error.Code
 			message = errResp.Error.Message
@@ -373,13 +752,17 @@ func (c *Client) request(ctx context.Context, method, path string, body, result
 			Code:       code,
 			Message:    message,
 			StatusCode: resp.StatusCode,
+			RateLimit:  limit,
 		}
 	}
 
 	if result != nil {
-		if err := json.Unmarshal(data, result); err != nil {
+		if err := json.Unmarshal(respData, result); err != nil {
 			return fmt.Errorf("failed to unmarshal response: %w", err)
 		}
+		if r, ok := result.(rateLimited); ok && limit != nil {
+			r.setRateLimit(limit)
+		}
 	}
 
 	return nil
@@ -393,3 +776,16 @@ func (c *Client) request(ctx context.Context, method, path string, body, result
 func IsVerified(resp *VerificationResponse) bool {
 	return resp != nil && resp.Verified
 }
+
+// decodeResult re-decodes a loosely-typed Result field into v. It leaves v
+// untouched if the field is missing or has an unexpected shape.
+func decodeResult(field interface{}, v interface{}) bool {
+	if field == nil {
+		return false
+	}
+	data, err := json.Marshal(field)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
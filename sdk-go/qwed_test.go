@@ -3,6 +3,8 @@ package qwed
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -28,8 +30,8 @@ func TestNewClient(t *testing.T) {
 		t.Fatal("expected non-nil client")
 	}
 
-	if client.apiKey != "test-api-key" {
-		t.Errorf("expected apiKey 'test-api-key', got '%s'", client.apiKey)
+	if key, _ := client.keys.current(); key != "test-api-key" {
+		t.Errorf("expected apiKey 'test-api-key', got '%s'", key)
 	}
 
 	if client.baseURL != "http://localhost:8000" {
@@ -170,6 +172,27 @@ func TestVerifyCode(t *testing.T) {
 	}
 }
 
+func TestVerifyCodeSARIFOption(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		opts, _ := req["options"].(map[string]interface{})
+		if opts["output_format"] != "sarif" {
+			t.Errorf("expected output_format sarif, got %v", req["options"])
+		}
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	_, err := client.VerifyCodeWithOptions(context.Background(), "print(1)", "python",
+		&RequestOptions{OutputFormat: OutputSARIF})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
 func TestVerifyFact(t *testing.T) {
 	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -225,6 +248,30 @@ func TestVerifySQL(t *testing.T) {
 	}
 }
 
+func TestGetBatch(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/verify/batch/job-1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(BatchResponse{
+			JobID:   "job-1",
+			Status:  BatchCompleted,
+			Summary: &BatchSummary{Total: 1, Verified: 1, SuccessRate: 1},
+			Items:   []BatchResult{{ID: "0", Status: StatusVerified, Verified: true}},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.GetBatch(context.Background(), "job-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Status != BatchCompleted || len(resp.Items) != 1 || !resp.Items[0].Verified {
+		t.Errorf("unexpected batch: %+v", resp)
+	}
+}
+
 // ============================================================================
 // Error Handling Tests
 // ============================================================================
@@ -258,6 +305,63 @@ func TestAPIError(t *testing.T) {
 	}
 }
 
+func TestAPIErrorSentinels(t *testing.T) {
+	tests := []struct {
+		status    int
+		code      string
+		sentinel  error
+		retryable bool
+	}{
+		{http.StatusUnauthorized, "INVALID_API_KEY", ErrUnauthorized, false},
+		{http.StatusForbidden, "FORBIDDEN", ErrUnauthorized, false},
+		{http.StatusTooManyRequests, "RATE_LIMITED", ErrRateLimited, true},
+		{http.StatusTooManyRequests, "QUOTA_EXCEEDED", ErrQuotaExceeded, false},
+		{http.StatusPaymentRequired, "HTTP-402", ErrQuotaExceeded, false},
+		{http.StatusUnprocessableEntity, "VALIDATION_ERROR", ErrInvalidRequest, false},
+		{http.StatusServiceUnavailable, "ENGINE_DOWN", ErrEngineUnavailable, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"error": map[string]interface{}{"code": tt.code, "message": "failed"},
+				})
+			})
+			defer server.Close()
+
+			client := NewClient("test-key", WithBaseURL(server.URL))
+			_, err := client.VerifyMath(context.Background(), "2+2=4")
+
+			if !errors.Is(err, tt.sentinel) {
+				t.Errorf("expected %v, got %v", tt.sentinel, err)
+			}
+			var qwedErr *QWEDError
+			if !errors.As(err, &qwedErr) || qwedErr.StatusCode != tt.status {
+				t.Errorf("expected QWEDError with status %d, got %v", tt.status, err)
+			}
+			if IsRetryable(err) != tt.retryable {
+				t.Errorf("expected IsRetryable %v", tt.retryable)
+			}
+		})
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"))
+	_, err := client.Health(context.Background())
+	if !IsRetryable(err) {
+		t.Errorf("expected connection failure to be retryable: %v", err)
+	}
+
+	for _, err := range []error{nil, context.Canceled, fmt.Errorf("wrapped: %w", context.DeadlineExceeded), ErrCircuitOpen, errors.New("other")} {
+		if IsRetryable(err) {
+			t.Errorf("expected %v not to be retryable", err)
+		}
+	}
+}
+
 func TestContextCancellation(t *testing.T) {
 	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(100 * time.Millisecond)
@@ -315,38 +419,38 @@ func (m *MockClient) Health(ctx context.Context) (map[string]interface{}, error)
 	return map[string]interface{}{"status": "mock"}, nil
 }
 
-func (m *MockClient) Verify(ctx context.Context, query string) (*VerificationResponse, error) {
+func (m *MockClient) Verify(ctx context.Context, query string, callOpts ...CallOption) (*VerificationResponse, error) {
 	return &VerificationResponse{Verified: true}, nil
 }
 
-func (m *MockClient) VerifyWithOptions(ctx context.Context, query string, opts *RequestOptions) (*VerificationResponse, error) {
+func (m *MockClient) VerifyWithOptions(ctx context.Context, query string, opts *RequestOptions, callOpts ...CallOption) (*VerificationResponse, error) {
 	return &VerificationResponse{Verified: true}, nil
 }
 
-func (m *MockClient) VerifyMath(ctx context.Context, expression string) (*VerificationResponse, error) {
+func (m *MockClient) VerifyMath(ctx context.Context, expression string, callOpts ...CallOption) (*VerificationResponse, error) {
 	if m.VerifyMathFunc != nil {
 		return m.VerifyMathFunc(ctx, expression)
 	}
 	return &VerificationResponse{Verified: true, Engine: "math"}, nil
 }
 
-func (m *MockClient) VerifyLogic(ctx context.Context, query string) (*VerificationResponse, error) {
+func (m *MockClient) VerifyLogic(ctx context.Context, query string, callOpts ...CallOption) (*VerificationResponse, error) {
 	return &VerificationResponse{Verified: true, Engine: "logic"}, nil
 }
 
-func (m *MockClient) VerifyCode(ctx context.Context, code, language string) (*VerificationResponse, error) {
+func (m *MockClient) VerifyCode(ctx context.Context, code, language string, callOpts ...CallOption) (*VerificationResponse, error) {
 	return &VerificationResponse{Verified: true, Engine: "code"}, nil
 }
 
-func (m *MockClient) VerifyFact(ctx context.Context, claim, factContext string) (*VerificationResponse, error) {
+func (m *MockClient) VerifyFact(ctx context.Context, claim, factContext string, callOpts ...CallOption) (*VerificationResponse, error) {
 	return &VerificationResponse{Verified: true, Engine: "fact"}, nil
 }
 
-func (m *MockClient) VerifySQL(ctx context.Context, query, schemaDDL, dialect string) (*VerificationResponse, error) {
+func (m *MockClient) VerifySQL(ctx context.Context, query, schemaDDL, dialect string, callOpts ...CallOption) (*VerificationResponse, error) {
 	return &VerificationResponse{Verified: true, Engine: "sql"}, nil
 }
 
-func (m *MockClient) VerifyBatch(ctx context.Context, items []BatchItem, opts *BatchOptions) (*BatchResponse, error) {
+func (m *MockClient) VerifyBatch(ctx context.Context, items []BatchItem, opts *BatchOptions, callOpts ...CallOption) (*BatchResponse, error) {
 	return &BatchResponse{Status: "complete"}, nil
 }
 
@@ -0,0 +1,122 @@
+package qwedeval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// ============================================================================
+// Comparison
+// ============================================================================
+
+// Disagreement is an example on which the two configurations returned
+// different verdicts, or only one of them failed with an error.
+type Disagreement struct {
+	Example Example `json:"example"`
+	A       Result  `json:"a"`
+	B       Result  `json:"b"`
+}
+
+// Comparison is the outcome of running one dataset through two
+// configurations. Deltas are B minus A, so a negative latency delta means B
+// is faster.
+type Comparison struct {
+	A *Report `json:"a"`
+	B *Report `json:"b"`
+
+	Agreements    int            `json:"agreements"`
+	Disagreements []Disagreement `json:"disagreements"`
+
+	MeanLatencyDeltaMs float64 `json:"mean_latency_delta_ms"`
+	P95LatencyDeltaMs  float64 `json:"p95_latency_delta_ms"`
+	CostDelta          float64 `json:"cost_delta"`
+}
+
+// AgreementRate returns the fraction of examples on which both
+// configurations agreed.
+func (c *Comparison) AgreementRate() float64 {
+	return ratio(c.Agreements, c.Agreements+len(c.Disagreements))
+}
+
+// Compare runs the same examples through two configurations, such as two
+// API versions or a cloud and an on-prem deployment, and reports where their
+// verdicts disagree along with latency and cost deltas. Configuration A runs
+// to completion before B so the latencies do not interfere. Compare only
+// returns an error if ctx is done.
+func Compare(ctx context.Context, a, b qwed.Verifier, examples []Example, opts *Options) (*Comparison, error) {
+	resultsA, err := evaluate(ctx, a, examples, opts)
+	if err != nil {
+		return nil, err
+	}
+	resultsB, err := evaluate(ctx, b, examples, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Comparison{A: newReport(resultsA), B: newReport(resultsB)}
+	for i, ex := range examples {
+		ra, rb := resultsA[i], resultsB[i]
+		if ra.Predicted == rb.Predicted && (ra.Error == "") == (rb.Error == "") {
+			c.Agreements++
+			continue
+		}
+		c.Disagreements = append(c.Disagreements, Disagreement{Example: ex, A: ra, B: rb})
+	}
+	c.MeanLatencyDeltaMs = c.B.MeanLatencyMs - c.A.MeanLatencyMs
+	c.P95LatencyDeltaMs = c.B.P95LatencyMs - c.A.P95LatencyMs
+	c.CostDelta = c.B.TotalCost - c.A.TotalCost
+	return c, nil
+}
+
+// WriteJSON encodes the comparison as indented JSON.
+func (c *Comparison) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+// WriteMarkdown writes a side-by-side summary followed by the examples the
+// configurations disagree on.
+func (c *Comparison) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# QWED Comparison\n\n")
+	fmt.Fprintf(&b, "Agreement: %d of %d examples (%.1f%%)\n\n",
+		c.Agreements, c.Agreements+len(c.Disagreements), 100*c.AgreementRate())
+
+	b.WriteString("| Metric | A | B | Delta |\n| --- | --- | --- | --- |\n")
+	fmt.Fprintf(&b, "| Accuracy | %.3f | %.3f | %+.3f |\n",
+		c.A.Overall.Accuracy, c.B.Overall.Accuracy, c.B.Overall.Accuracy-c.A.Overall.Accuracy)
+	fmt.Fprintf(&b, "| F1 | %.3f | %.3f | %+.3f |\n",
+		c.A.Overall.F1, c.B.Overall.F1, c.B.Overall.F1-c.A.Overall.F1)
+	fmt.Fprintf(&b, "| Errors | %d | %d | %+d |\n",
+		c.A.Overall.Errors, c.B.Overall.Errors, c.B.Overall.Errors-c.A.Overall.Errors)
+	fmt.Fprintf(&b, "| Mean latency (ms) | %.1f | %.1f | %+.1f |\n",
+		c.A.MeanLatencyMs, c.B.MeanLatencyMs, c.MeanLatencyDeltaMs)
+	fmt.Fprintf(&b, "| p95 latency (ms) | %.1f | %.1f | %+.1f |\n",
+		c.A.P95LatencyMs, c.B.P95LatencyMs, c.P95LatencyDeltaMs)
+	fmt.Fprintf(&b, "| Cost | %.4f | %.4f | %+.4f |\n", c.A.TotalCost, c.B.TotalCost, c.CostDelta)
+
+	if len(c.Disagreements) > 0 {
+		b.WriteString("\n## Disagreements\n\n| ID | Engine | Expected | A | B | Query |\n| --- | --- | --- | --- | --- | --- |\n")
+		for _, d := range c.Disagreements {
+			fmt.Fprintf(&b, "| %s | %s | %t | %s | %s | %s |\n", d.Example.ID, d.Example.Type, d.Example.Expected,
+				markdownCell(verdict(d.A)), markdownCell(verdict(d.B)), markdownCell(d.Example.Query))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// verdict describes a result for a Markdown cell.
+func verdict(r Result) string {
+	if r.Error != "" {
+		return "error: " + r.Error
+	}
+	return fmt.Sprint(r.Predicted)
+}
@@ -0,0 +1,88 @@
+package qwedeval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// strictServer verifies only math ending in "=4" and facts mentioning
+// France, reporting a cost of 0.5 per call.
+func strictServer(t *testing.T) *qwed.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+
+		verified := strings.HasSuffix(req["expression"], "=4") || strings.Contains(req["claim"], "France")
+		json.NewEncoder(w).Encode(qwed.VerificationResponse{
+			Verified: verified,
+			Result:   map[string]interface{}{"cost": 0.5},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return qwed.NewClient("test-key", qwed.WithBaseURL(server.URL))
+}
+
+func TestCompare(t *testing.T) {
+	examples, err := ReadDataset(strings.NewReader(dataset))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Compare(context.Background(), evalServer(t), strictServer(t), examples, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "10/4=2" flips to rejected, and "boom" errors only on A.
+	if c.Agreements != 4 || len(c.Disagreements) != 2 {
+		t.Fatalf("unexpected agreement: %d agreed, %+v", c.Agreements, c.Disagreements)
+	}
+	if d := c.Disagreements[0]; d.Example.Query != "10/4=2" || !d.A.Predicted || d.B.Predicted {
+		t.Errorf("unexpected first disagreement: %+v", d)
+	}
+	if d := c.Disagreements[1]; d.Example.Query != "boom" || d.A.Error == "" || d.B.Error != "" {
+		t.Errorf("unexpected second disagreement: %+v", d)
+	}
+	if !approx(c.AgreementRate(), 4.0/6) {
+		t.Errorf("unexpected agreement rate %v", c.AgreementRate())
+	}
+	if c.A.TotalCost != 0 || !approx(c.B.TotalCost, 3) || !approx(c.CostDelta, 3) {
+		t.Errorf("unexpected costs: a=%v b=%v delta=%v", c.A.TotalCost, c.B.TotalCost, c.CostDelta)
+	}
+	if c.B.Overall.Accuracy <= c.A.Overall.Accuracy {
+		t.Errorf("expected B to be more accurate: a=%v b=%v", c.A.Overall.Accuracy, c.B.Overall.Accuracy)
+	}
+	if c.A.MeanLatencyMs <= 0 || !approx(c.MeanLatencyDeltaMs, c.B.MeanLatencyMs-c.A.MeanLatencyMs) {
+		t.Errorf("unexpected latencies: %+v", c)
+	}
+
+	var md bytes.Buffer
+	if err := c.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Agreement: 4 of 6", "| Cost | 0.0000 | 3.0000 | +3.0000 |", "## Disagreements", "| 4 | math | false | true | false | 10/4=2 |"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("markdown missing %q:\n%s", want, md.String())
+		}
+	}
+}
+
+func TestCompareCustomCost(t *testing.T) {
+	examples := []Example{{ID: "1", Type: qwed.TypeMath, Query: "2+2=4", Expected: true}}
+	opts := &Options{Cost: func(*qwed.VerificationResponse) float64 { return 2 }}
+
+	c, err := Compare(context.Background(), evalServer(t), strictServer(t), examples, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.A.TotalCost != 2 || c.B.TotalCost != 2 || c.CostDelta != 0 || len(c.Disagreements) != 0 {
+		t.Errorf("unexpected comparison: %+v", c)
+	}
+}
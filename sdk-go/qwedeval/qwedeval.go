@@ -0,0 +1,370 @@
+// Package qwedeval measures verification quality on a labeled dataset, so
+// teams can evaluate QWED on their own domain before rollout.
+//
+// Each example is sent through a qwed.Verifier and the verdict compared to
+// the expected label. A verified verdict is the positive class: a false
+// positive is an incorrect claim that QWED passed.
+//
+// Example usage:
+//
+//	examples, err := qwedeval.LoadDataset("claims.jsonl")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	report, err := qwedeval.Run(ctx, client, examples, &qwedeval.Options{Concurrency: 8})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	report.WriteMarkdown(os.Stdout)
+package qwedeval
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// ============================================================================
+// Dataset
+// ============================================================================
+
+// Example is a labeled claim. The meaning of Context and Language depends
+// on Type:
+//
+//   - fact: Context is the source text
+//   - code: Query is the source code and Language its language
+//   - sql:  Context is the schema DDL and Language the dialect
+type Example struct {
+	ID       string                `json:"id,omitempty"`
+	Type     qwed.VerificationType `json:"type"`
+	Query    string                `json:"query"`
+	Context  string                `json:"context,omitempty"`
+	Language string                `json:"language,omitempty"`
+	Expected bool                  `json:"expected"` // true if the claim is correct
+}
+
+// ReadDataset parses examples from r, one JSON object per line. Blank lines
+// and lines starting with '#' are ignored.
+func ReadDataset(r io.Reader) ([]Example, error) {
+	var examples []Example
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		var ex Example
+		if err := json.Unmarshal([]byte(text), &ex); err != nil {
+			return nil, fmt.Errorf("dataset line %d: %w", line, err)
+		}
+		if ex.Type == "" {
+			ex.Type = qwed.TypeNaturalLanguage
+		}
+		if ex.ID == "" {
+			ex.ID = fmt.Sprint(line)
+		}
+		examples = append(examples, ex)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dataset: %w", err)
+	}
+	return examples, nil
+}
+
+// LoadDataset reads examples from the JSONL file at path.
+func LoadDataset(path string) ([]Example, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open dataset: %w", err)
+	}
+	defer f.Close()
+
+	return ReadDataset(f)
+}
+
+// ============================================================================
+// Metrics
+// ============================================================================
+
+// Metrics is a confusion matrix with the scores derived from it.
+type Metrics struct {
+	TruePositives  int `json:"true_positives"`
+	FalsePositives int `json:"false_positives"`
+	TrueNegatives  int `json:"true_negatives"`
+	FalseNegatives int `json:"false_negatives"`
+	Errors         int `json:"errors"`
+
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	F1        float64 `json:"f1"`
+	Accuracy  float64 `json:"accuracy"`
+}
+
+// Total returns the number of examples with a verdict.
+func (m *Metrics) Total() int {
+	return m.TruePositives + m.FalsePositives + m.TrueNegatives + m.FalseNegatives
+}
+
+func (m *Metrics) add(expected, predicted bool) {
+	switch {
+	case expected && predicted:
+		m.TruePositives++
+	case !expected && predicted:
+		m.FalsePositives++
+	case !expected && !predicted:
+		m.TrueNegatives++
+	default:
+		m.FalseNegatives++
+	}
+}
+
+// compute fills in the derived scores. Undefined ratios are zero.
+func (m *Metrics) compute() {
+	m.Precision = ratio(m.TruePositives, m.TruePositives+m.FalsePositives)
+	m.Recall = ratio(m.TruePositives, m.TruePositives+m.FalseNegatives)
+	if m.Precision+m.Recall > 0 {
+		m.F1 = 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+	}
+	m.Accuracy = ratio(m.TruePositives+m.TrueNegatives, m.Total())
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// ============================================================================
+// Evaluation
+// ============================================================================
+
+// Options configures Run and Compare.
+type Options struct {
+	// Concurrency limits the number of examples verified at once.
+	// Defaults to 4.
+	Concurrency int
+
+	// Cost returns the cost of a verification, in any unit. Defaults to
+	// DefaultCost.
+	Cost func(resp *qwed.VerificationResponse) float64
+}
+
+// DefaultCost reads the cost reported in Result["cost"], or zero if the
+// backend does not report one.
+func DefaultCost(resp *qwed.VerificationResponse) float64 {
+	if resp == nil {
+		return 0
+	}
+	cost, _ := resp.Result["cost"].(float64)
+	return cost
+}
+
+// Result is the outcome for one example.
+type Result struct {
+	Example   Example `json:"example"`
+	Predicted bool    `json:"predicted"`
+	Correct   bool    `json:"correct"`
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+	Cost      float64 `json:"cost,omitempty"`
+}
+
+// Report is the evaluation of a dataset.
+type Report struct {
+	Overall Metrics                            `json:"overall"`
+	Engines map[qwed.VerificationType]*Metrics `json:"engines"`
+	Results []Result                           `json:"results"`
+
+	MeanLatencyMs float64 `json:"mean_latency_ms"`
+	P95LatencyMs  float64 `json:"p95_latency_ms"`
+	TotalCost     float64 `json:"total_cost"`
+}
+
+// Run verifies every example with v and scores the verdicts. Examples that
+// fail with an error are counted in Metrics.Errors and excluded from the
+// scores. Run only returns an error if ctx is done.
+func Run(ctx context.Context, v qwed.Verifier, examples []Example, opts *Options) (*Report, error) {
+	results, err := evaluate(ctx, v, examples, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newReport(results), nil
+}
+
+// evaluate verifies every example with v, preserving order.
+func evaluate(ctx context.Context, v qwed.Verifier, examples []Example, opts *Options) ([]Result, error) {
+	concurrency, cost := 4, DefaultCost
+	if opts != nil && opts.Concurrency > 0 {
+		concurrency = opts.Concurrency
+	}
+	if opts != nil && opts.Cost != nil {
+		cost = opts.Cost
+	}
+
+	results := make([]Result, len(examples))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i, ex := range examples {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, ctx.Err()
+		}
+		wg.Add(1)
+		go func(i int, ex Example) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result := Result{Example: ex}
+			start := time.Now()
+			resp, err := verify(ctx, v, ex)
+			result.LatencyMs = float64(time.Since(start)) / float64(time.Millisecond)
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Predicted = qwed.IsVerified(resp)
+				result.Correct = result.Predicted == ex.Expected
+				result.Cost = cost(resp)
+			}
+			results[i] = result
+		}(i, ex)
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// newReport scores results.
+func newReport(results []Result) *Report {
+	report := &Report{Engines: make(map[qwed.VerificationType]*Metrics), Results: results}
+	latencies := make([]float64, 0, len(results))
+	for _, r := range results {
+		latencies = append(latencies, r.LatencyMs)
+		report.TotalCost += r.Cost
+
+		engine := report.Engines[r.Example.Type]
+		if engine == nil {
+			engine = &Metrics{}
+			report.Engines[r.Example.Type] = engine
+		}
+		if r.Error != "" {
+			engine.Errors++
+			report.Overall.Errors++
+			continue
+		}
+		engine.add(r.Example.Expected, r.Predicted)
+		report.Overall.add(r.Example.Expected, r.Predicted)
+	}
+	report.Overall.compute()
+	for _, m := range report.Engines {
+		m.compute()
+	}
+
+	if len(latencies) > 0 {
+		sort.Float64s(latencies)
+		sum := 0.0
+		for _, l := range latencies {
+			sum += l
+		}
+		report.MeanLatencyMs = sum / float64(len(latencies))
+		report.P95LatencyMs = latencies[(len(latencies)*95+99)/100-1]
+	}
+	return report
+}
+
+// verify sends an example to the engine matching its type.
+func verify(ctx context.Context, v qwed.Verifier, ex Example) (*qwed.VerificationResponse, error) {
+	switch ex.Type {
+	case qwed.TypeMath:
+		return v.VerifyMath(ctx, ex.Query)
+	case qwed.TypeLogic:
+		return v.VerifyLogic(ctx, ex.Query)
+	case qwed.TypeFact:
+		return v.VerifyFact(ctx, ex.Query, ex.Context)
+	case qwed.TypeCode:
+		return v.VerifyCode(ctx, ex.Query, ex.Language)
+	case qwed.TypeSQL:
+		return v.VerifySQL(ctx, ex.Query, ex.Context, ex.Language)
+	case qwed.TypeNaturalLanguage:
+		return v.Verify(ctx, ex.Query)
+	}
+	return nil, fmt.Errorf("unsupported example type %q", ex.Type)
+}
+
+// ============================================================================
+// Output
+// ============================================================================
+
+// WriteJSON encodes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteMarkdown writes a summary table per engine followed by the
+// misclassified examples.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("# QWED Evaluation\n\n")
+	b.WriteString("| Engine | Examples | TP | FP | TN | FN | Errors | Precision | Recall | F1 | Accuracy |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- | --- | --- | --- |\n")
+
+	engines := make([]string, 0, len(r.Engines))
+	for engine := range r.Engines {
+		engines = append(engines, string(engine))
+	}
+	sort.Strings(engines)
+	for _, engine := range engines {
+		writeMetricsRow(&b, engine, r.Engines[qwed.VerificationType(engine)])
+	}
+	writeMetricsRow(&b, "**overall**", &r.Overall)
+
+	var wrong []Result
+	for _, res := range r.Results {
+		if !res.Correct {
+			wrong = append(wrong, res)
+		}
+	}
+	if len(wrong) > 0 {
+		b.WriteString("\n## Misclassified\n\n| ID | Engine | Expected | Got | Query |\n| --- | --- | --- | --- | --- |\n")
+		for _, res := range wrong {
+			fmt.Fprintf(&b, "| %s | %s | %t | %s | %s |\n", res.Example.ID, res.Example.Type, res.Example.Expected,
+				markdownCell(verdict(res)), markdownCell(res.Example.Query))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMetricsRow(b *strings.Builder, name string, m *Metrics) {
+	fmt.Fprintf(b, "| %s | %d | %d | %d | %d | %d | %d | %.3f | %.3f | %.3f | %.3f |\n",
+		name, m.Total()+m.Errors, m.TruePositives, m.FalsePositives, m.TrueNegatives, m.FalseNegatives,
+		m.Errors, m.Precision, m.Recall, m.F1, m.Accuracy)
+}
+
+func markdownCell(s string) string {
+	if r := []rune(s); len(r) > 80 {
+		s = string(r[:77]) + "..."
+	}
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
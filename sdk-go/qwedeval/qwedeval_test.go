@@ -0,0 +1,109 @@
+package qwedeval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+const dataset = `# labeled claims
+{"type": "math", "query": "2+2=4", "expected": true}
+{"type": "math", "query": "2+2=5", "expected": false}
+{"type": "math", "query": "10/4=2", "expected": false}
+{"type": "fact", "query": "Paris is in France", "context": "Paris is the capital of France.", "expected": true}
+{"type": "fact", "query": "Paris is in Spain", "context": "Paris is the capital of France.", "expected": false}
+{"type": "fact", "query": "boom", "expected": true}
+`
+
+// evalServer verifies math containing "=4" or "=2", and facts mentioning
+// France. "boom" returns a server error.
+func evalServer(t *testing.T) *qwed.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+
+		var verified bool
+		switch r.URL.Path {
+		case "/verify/math":
+			verified = strings.HasSuffix(req["expression"], "=4") || strings.HasSuffix(req["expression"], "=2")
+		case "/verify/fact":
+			if req["claim"] == "boom" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			verified = strings.Contains(req["claim"], "France")
+		}
+		json.NewEncoder(w).Encode(qwed.VerificationResponse{Verified: verified})
+	}))
+	t.Cleanup(server.Close)
+	return qwed.NewClient("test-key", qwed.WithBaseURL(server.URL))
+}
+
+func TestRun(t *testing.T) {
+	examples, err := ReadDataset(strings.NewReader(dataset))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(examples) != 6 || examples[0].ID != "2" {
+		t.Fatalf("unexpected examples: %+v", examples)
+	}
+
+	report, err := Run(context.Background(), evalServer(t), examples, &Options{Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	math := report.Engines[qwed.TypeMath]
+	if math.TruePositives != 1 || math.FalsePositives != 1 || math.TrueNegatives != 1 || math.FalseNegatives != 0 {
+		t.Errorf("unexpected math confusion matrix: %+v", math)
+	}
+	if !approx(math.Precision, 0.5) || !approx(math.Recall, 1) || !approx(math.F1, 2.0/3) {
+		t.Errorf("unexpected math scores: %+v", math)
+	}
+
+	fact := report.Engines[qwed.TypeFact]
+	if fact.TruePositives != 1 || fact.TrueNegatives != 1 || fact.Errors != 1 {
+		t.Errorf("unexpected fact metrics: %+v", fact)
+	}
+	if report.Overall.Total() != 5 || report.Overall.Errors != 1 || !approx(report.Overall.Accuracy, 0.8) {
+		t.Errorf("unexpected overall metrics: %+v", report.Overall)
+	}
+}
+
+func TestReportOutput(t *testing.T) {
+	examples, _ := ReadDataset(strings.NewReader(dataset))
+	report, err := Run(context.Background(), evalServer(t), examples, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var md bytes.Buffer
+	if err := report.WriteMarkdown(&md); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"| fact | 3 | 1 | 0 | 1 | 0 | 1 |", "| math | 3 | 1 | 1 | 1 | 0 | 0 | 0.500 | 1.000 | 0.667 |", "## Misclassified", "10/4=2"} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("expected markdown to contain %q:\n%s", want, md.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded.Engines[qwed.TypeMath].FalsePositives != 1 {
+		t.Errorf("expected JSON round trip, got %v", err)
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,150 @@
+package qwed
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Rate Limiting
+// ============================================================================
+
+// RateLimiter is a token bucket limiting the rate of API requests. It is
+// safe for concurrent use; every goroutine using a Client shares its
+// limiter. Waiting requests get tokens highest priority first; see
+// WithPriority.
+type RateLimiter struct {
+	mu      sync.Mutex
+	rps     float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+	waiters waitQueue
+	timer   *time.Timer // admits the next waiter once a token accrues
+}
+
+// NewRateLimiter creates a limiter allowing rps requests per second on
+// average with bursts of up to burst requests. A non-positive rps disables
+// limiting.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rps:    rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// WithRateLimit limits the client to rps requests per second with bursts of
+// up to burst requests, so bulk loops stay under the server's rate limit.
+// Cached responses do not count against the limit.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) {
+		c.limiter = NewRateLimiter(rps, burst)
+	}
+}
+
+type rateLimiterKey struct{}
+
+// ContextWithRateLimiter overrides the client's rate limiter for calls made
+// with the returned context. A nil limiter disables rate limiting for those
+// calls, e.g. for latency-sensitive requests alongside a throttled bulk job.
+func ContextWithRateLimiter(ctx context.Context, l *RateLimiter) context.Context {
+	return context.WithValue(ctx, rateLimiterKey{}, l)
+}
+
+// rateLimiter returns the limiter applying to a call made with ctx.
+func (c *Client) rateLimiter(ctx context.Context) *RateLimiter {
+	if l, ok := ctx.Value(rateLimiterKey{}).(*RateLimiter); ok {
+		return l
+	}
+	return c.limiter
+}
+
+// Allow reports whether a request may be made now, consuming a token if so.
+func (l *RateLimiter) Allow() bool {
+	if l == nil || l.rps <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(time.Now())
+	if len(l.waiters) > 0 || l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Wait blocks until a request may be made or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil || l.rps <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	l.refill(time.Now())
+	if len(l.waiters) == 0 && l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+	w := l.waiters.push(PriorityFromContext(ctx))
+	l.schedule()
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if !l.waiters.remove(w) {
+			// Granted while giving up: pass the token on.
+			l.tokens++
+			l.grant()
+		}
+		return ctx.Err()
+	}
+}
+
+// grant hands available tokens to waiters in priority order and schedules
+// the next grant. The caller must hold l.mu.
+func (l *RateLimiter) grant() {
+	for len(l.waiters) > 0 && l.tokens >= 1 {
+		l.tokens--
+		close(l.waiters.pop().ready)
+	}
+	l.schedule()
+}
+
+// schedule arms the timer for when the next token accrues, if requests are
+// waiting. The caller must hold l.mu.
+func (l *RateLimiter) schedule() {
+	if l.timer != nil || len(l.waiters) == 0 {
+		return
+	}
+	delay := time.Duration((1 - l.tokens) / l.rps * float64(time.Second))
+	l.timer = time.AfterFunc(delay, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.timer = nil
+		l.refill(time.Now())
+		l.grant()
+	})
+}
+
+// refill adds the tokens accrued since the last update. The caller must
+// hold l.mu.
+func (l *RateLimiter) refill(now time.Time) {
+	l.tokens += now.Sub(l.last).Seconds() * l.rps
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+}
@@ -0,0 +1,93 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	l := NewRateLimiter(1, 2)
+	if !l.Allow() || !l.Allow() {
+		t.Fatal("expected burst to be allowed")
+	}
+	if l.Allow() {
+		t.Error("expected limiter to be exhausted")
+	}
+
+	var unlimited *RateLimiter
+	if !unlimited.Allow() || unlimited.Wait(context.Background()) != nil {
+		t.Error("expected nil limiter to allow everything")
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	l := NewRateLimiter(0.1, 1)
+	l.Allow()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestWithRateLimitSharedAcrossGoroutines(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithRateLimit(100, 2))
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.VerifyLogic(context.Background(), "p OR NOT p"); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Two requests use the burst; the remaining four wait 10ms each.
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("expected requests to be throttled, finished in %v", elapsed)
+	}
+}
+
+func TestContextWithRateLimiterOverride(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithRateLimit(0.001, 1))
+	if _, err := client.VerifyLogic(context.Background(), "p"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := ContextWithRateLimiter(context.Background(), nil)
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.VerifyLogic(ctx, "p"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected unlimited calls, took %v", elapsed)
+	}
+
+	timeout, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.VerifyLogic(timeout, "p"); err == nil {
+		t.Error("expected exhausted client limiter to block until the deadline")
+	}
+}
@@ -0,0 +1,272 @@
+package qwed
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ============================================================================
+// Regex Verification
+// ============================================================================
+
+// TypeRegex identifies regular expression checks. They run locally and are
+// reported with Engine EngineLocalRegex.
+const TypeRegex VerificationType = "regex"
+
+// EngineLocalRegex is the engine name reported by VerifyRegex.
+const EngineLocalRegex Engine = "local-regex"
+
+// RegexDialect is the regular expression syntax a pattern is written in.
+type RegexDialect string
+
+const (
+	RegexRE2  RegexDialect = "re2"  // Go regexp and RE2 (default)
+	RegexPCRE RegexDialect = "pcre" // PCRE, as used by PHP, Python, JavaScript and most tools
+)
+
+// RegexCase is an example input and whether the pattern should match it.
+type RegexCase struct {
+	Input       string `json:"input"`
+	ShouldMatch bool   `json:"should_match"`
+}
+
+// RegexCaseResult is the outcome of one RegexCase.
+type RegexCaseResult struct {
+	Input       string `json:"input"`
+	ShouldMatch bool   `json:"should_match"`
+	Matched     bool   `json:"matched"`
+	Match       string `json:"match,omitempty"` // the matched text, for partial matches
+}
+
+// RegexOptions configures VerifyRegexWithOptions.
+type RegexOptions struct {
+	// Dialect is the syntax of the pattern. Defaults to RegexRE2.
+	Dialect RegexDialect
+
+	// FullMatch requires the pattern to match each input in its entirety,
+	// as validation regexes are usually meant to. By default a match
+	// anywhere in the input counts.
+	FullMatch bool
+}
+
+// VerifyRegex checks a regular expression, typically LLM-generated,
+// against examples it should and should not match. It is
+// VerifyRegexWithOptions with default options.
+func (c *Client) VerifyRegex(ctx context.Context, pattern string, testCases []RegexCase) (*VerificationResponse, error) {
+	return c.VerifyRegexWithOptions(ctx, pattern, testCases, nil)
+}
+
+// VerifyRegexWithOptions checks pattern against testCases. The response is
+// verified if the pattern compiles in the chosen dialect and every case
+// behaves as expected; the cases that do not are reported in
+// Result["failures"], decoded by RegexFailures.
+//
+// Patterns are evaluated with Go's RE2 engine. A PCRE pattern is translated
+// where the dialects differ only in syntax, such as (?<name>...) groups.
+// PCRE features RE2 cannot evaluate, such as lookarounds and
+// backreferences, make the result StatusUnsupported; in the RE2 dialect
+// they are a compile error and fail the check, with the offending features
+// listed in Result["pcre_features"].
+func (c *Client) VerifyRegexWithOptions(ctx context.Context, pattern string, testCases []RegexCase, opts *RegexOptions) (resp *VerificationResponse, err error) {
+	_, end := c.instrument(ctx, "VerifyRegex", TypeRegex)
+	defer func() { end(resp, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var o RegexOptions
+	if opts != nil {
+		o = *opts
+	}
+	return localVerifyRegex(pattern, testCases, o), nil
+}
+
+// RegexFailures extracts the failing cases from a VerifyRegex response.
+func RegexFailures(resp *VerificationResponse) []RegexCaseResult {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var failures []RegexCaseResult
+	decodeResult(resp.Result["failures"], &failures)
+	return failures
+}
+
+// localVerifyRegex evaluates pattern against cases.
+func localVerifyRegex(pattern string, cases []RegexCase, o RegexOptions) *VerificationResponse {
+	if o.Dialect == "" {
+		o.Dialect = RegexRE2
+	}
+	result := map[string]interface{}{"dialect": string(o.Dialect)}
+
+	source, features := translatePCRE(pattern)
+	switch o.Dialect {
+	case RegexRE2:
+		source = pattern
+	case RegexPCRE:
+		if len(features) > 0 {
+			result["reason"] = fmt.Sprintf("pattern uses %s, which cannot be evaluated locally", strings.Join(features, ", "))
+			result["pcre_features"] = features
+			return &VerificationResponse{Status: StatusUnsupported, Engine: EngineLocalRegex, Result: result}
+		}
+	default:
+		result["reason"] = fmt.Sprintf("unknown regex dialect %q", o.Dialect)
+		return &VerificationResponse{Status: StatusUnsupported, Engine: EngineLocalRegex, Result: result}
+	}
+
+	if o.FullMatch {
+		source = `^(?:` + source + `)$`
+	}
+	re, err := regexp.Compile(source)
+	if err != nil {
+		result["error"] = fmt.Sprintf("invalid %s pattern: %v", o.Dialect, err)
+		if len(features) > 0 {
+			result["pcre_features"] = features
+		}
+		return localResponse(EngineLocalRegex, false, result)
+	}
+
+	failures := []RegexCaseResult{}
+	for _, tc := range cases {
+		match := re.FindStringIndex(tc.Input)
+		if matched := match != nil; matched != tc.ShouldMatch {
+			failure := RegexCaseResult{Input: tc.Input, ShouldMatch: tc.ShouldMatch, Matched: matched}
+			if matched {
+				failure.Match = tc.Input[match[0]:match[1]]
+			}
+			failures = append(failures, failure)
+		}
+	}
+	result["failures"] = failures
+	result["passed"] = len(cases) - len(failures)
+	result["total"] = len(cases)
+	return localResponse(EngineLocalRegex, len(failures) == 0, result)
+}
+
+// translatePCRE rewrites PCRE syntax that RE2 spells differently and lists
+// the PCRE features RE2 does not support at all.
+func translatePCRE(pattern string) (string, []string) {
+	var b strings.Builder
+	var features []string
+	seen := make(map[string]bool)
+	feature := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			features = append(features, name)
+		}
+	}
+
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		ch := pattern[i]
+		rest := pattern[i:]
+		switch {
+		case ch == '\\' && i+1 < len(pattern):
+			next := pattern[i+1]
+			switch {
+			case !inClass && next >= '1' && next <= '9', !inClass && (next == 'k' || next == 'g'):
+				feature("backreferences")
+			case next == 'Z':
+				b.WriteString(`(?:\n?\z)`)
+				i++
+				continue
+			case next == 'G' || next == 'R' || next == 'X' || next == 'K':
+				feature(`\` + string(next))
+			case next == 'h' && inClass:
+				b.WriteString(`\t\p{Zs}`)
+				i++
+				continue
+			case next == 'h':
+				b.WriteString(`[\t\p{Zs}]`)
+				i++
+				continue
+			}
+			b.WriteString(pattern[i : i+2])
+			i++
+			continue
+		case inClass:
+			if ch == ']' {
+				inClass = false
+			}
+		case ch == '[':
+			inClass = true
+			b.WriteByte(ch)
+			// A leading ] (or ^]) is a literal, not the end of the class.
+			if strings.HasPrefix(rest, "[^]") {
+				b.WriteString("^]")
+				i += 2
+			} else if strings.HasPrefix(rest, "[]") {
+				b.WriteByte(']')
+				i++
+			}
+			continue
+		case strings.HasPrefix(rest, "(?=") || strings.HasPrefix(rest, "(?!"):
+			feature("lookahead")
+		case strings.HasPrefix(rest, "(?<=") || strings.HasPrefix(rest, "(?<!"):
+			feature("lookbehind")
+		case strings.HasPrefix(rest, "(?>"):
+			feature("atomic groups")
+		case strings.HasPrefix(rest, "(?<") || strings.HasPrefix(rest, "(?'"):
+			// Named groups: (?<name>...) and (?'name'...) are (?P<name>...).
+			closing := byte('>')
+			if rest[2] == '\'' {
+				closing = '\''
+			}
+			if end := strings.IndexByte(rest[3:], closing); end >= 0 {
+				b.WriteString("(?P<" + rest[3:3+end] + ">")
+				i += 3 + end
+				continue
+			}
+		case strings.HasPrefix(rest, "(?P="):
+			feature("backreferences")
+		case strings.HasPrefix(rest, "(?("):
+			feature("conditionals")
+		case isRecursion(rest):
+			feature("recursion")
+		case strings.HasPrefix(rest, "(?#"):
+			feature("comments")
+		case ch == '(' && strings.HasPrefix(rest, "(?") && inlineFlags(rest[2:], 'x'):
+			feature("extended mode")
+		case (ch == '*' || ch == '+' || ch == '?' || ch == '}') && i+1 < len(pattern) && pattern[i+1] == '+':
+			feature("possessive quantifiers")
+		}
+		b.WriteByte(ch)
+	}
+	return b.String(), features
+}
+
+// isRecursion reports whether s starts with a recursive or subroutine
+// call such as (?R), (?1), (?-1) or (?&name).
+func isRecursion(s string) bool {
+	for _, prefix := range []string{"(?R)", "(?&", "(?P>"} {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	if !strings.HasPrefix(s, "(?") {
+		return false
+	}
+	s = s[2:]
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	return len(s) > 0 && s[0] >= '0' && s[0] <= '9'
+}
+
+// inlineFlags reports whether the flag group at the start of s, such as
+// "ix)" or "x:", sets flag.
+func inlineFlags(s string, flag byte) bool {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == ')' || c == ':' || c == '-':
+			return false
+		case c == flag:
+			return true
+		case c < 'a' || c > 'z':
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package qwed
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestVerifyRegex(t *testing.T) {
+	cases := []RegexCase{
+		{Input: "user@example.com", ShouldMatch: true},
+		{Input: "first.last+tag@sub.example.org", ShouldMatch: true},
+		{Input: "not an email", ShouldMatch: false},
+		{Input: "missing@tld", ShouldMatch: false},
+	}
+
+	client := NewClient("test")
+	resp, err := client.VerifyRegex(context.Background(), `^[\w.+-]+@[\w-]+(\.[\w-]+)+$`, cases)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Verified || resp.Engine != EngineLocalRegex {
+		t.Errorf("expected verified by %s, got %+v", EngineLocalRegex, resp)
+	}
+	if resp.Result["passed"] != 4 || len(RegexFailures(resp)) != 0 {
+		t.Errorf("unexpected result: %v", resp.Result)
+	}
+
+	// Without the TLD group the pattern accepts "missing@tld".
+	resp, _ = client.VerifyRegex(context.Background(), `^[\w.+-]+@[\w.-]+$`, cases)
+	if resp.Verified {
+		t.Fatal("expected the loose pattern to fail")
+	}
+	want := []RegexCaseResult{{Input: "missing@tld", ShouldMatch: false, Matched: true, Match: "missing@tld"}}
+	if got := RegexFailures(resp); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected failures %+v, got %+v", want, got)
+	}
+}
+
+func TestVerifyRegexFullMatch(t *testing.T) {
+	cases := []RegexCase{{Input: "12345", ShouldMatch: true}, {Input: "12345abc", ShouldMatch: false}}
+
+	if resp := localVerifyRegex(`\d+`, cases, RegexOptions{}); resp.Verified {
+		t.Error("expected an unanchored search to match 12345abc")
+	}
+	if resp := localVerifyRegex(`\d+`, cases, RegexOptions{FullMatch: true}); !resp.Verified {
+		t.Errorf("expected a full match to reject 12345abc, got %v", resp.Result)
+	}
+	if resp := localVerifyRegex(`a|\d+`, []RegexCase{{Input: "a1", ShouldMatch: false}}, RegexOptions{FullMatch: true}); !resp.Verified {
+		t.Errorf("expected alternatives to be anchored together, got %v", resp.Result)
+	}
+}
+
+func TestVerifyRegexDialects(t *testing.T) {
+	password := []RegexCase{{Input: "abc123", ShouldMatch: true}, {Input: "abcdef", ShouldMatch: false}}
+
+	// Lookahead is PCRE-only: invalid for RE2, unsupported locally for PCRE.
+	resp := localVerifyRegex(`^(?=.*\d)\w+$`, password, RegexOptions{})
+	if resp.Status != StatusFailed || resp.Result["error"] == nil {
+		t.Errorf("expected an RE2 compile failure, got %s %v", resp.Status, resp.Result)
+	}
+	if features, _ := resp.Result["pcre_features"].([]string); !reflect.DeepEqual(features, []string{"lookahead"}) {
+		t.Errorf("expected lookahead to be reported, got %v", resp.Result["pcre_features"])
+	}
+	resp = localVerifyRegex(`^(?=.*\d)\w+$`, password, RegexOptions{Dialect: RegexPCRE})
+	if resp.Status != StatusUnsupported || resp.Verdict() != VerdictInconclusive {
+		t.Errorf("expected an inconclusive result, got %s %v", resp.Status, resp.Result)
+	}
+
+	// PCRE syntax with an RE2 equivalent is translated.
+	dates := []RegexCase{{Input: "2024-06-01", ShouldMatch: true}, {Input: "2024-06-01\n", ShouldMatch: true}, {Input: "2024-6-1", ShouldMatch: false}}
+	resp = localVerifyRegex(`^(?'year'\d{4})-(?<month>\d{2})-\d{2}\Z`, dates, RegexOptions{Dialect: RegexPCRE})
+	if !resp.Verified {
+		t.Errorf("expected the translated pattern to verify, got %s %v", resp.Status, resp.Result)
+	}
+
+	if resp := localVerifyRegex(`a`, nil, RegexOptions{Dialect: "posix"}); resp.Status != StatusUnsupported {
+		t.Errorf("expected an unknown dialect to be unsupported, got %s", resp.Status)
+	}
+}
+
+func TestTranslatePCRE(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		source   string
+		features []string
+	}{
+		{`(\w)\1`, `(\w)\1`, []string{"backreferences"}},
+		{`[\1]`, `[\1]`, nil},
+		{`(?<!x)y`, `(?<!x)y`, []string{"lookbehind"}},
+		{`a++b*+`, `a++b*+`, []string{"possessive quantifiers"}},
+		{`(?>a|ab)c`, `(?>a|ab)c`, []string{"atomic groups"}},
+		{`\((?1)?\)`, `\((?1)?\)`, []string{"recursion"}},
+		{`(?x) a b`, `(?x) a b`, []string{"extended mode"}},
+		{`(?i)abc`, `(?i)abc`, nil},
+		{`[]a]\h`, `[]a][\t\p{Zs}]`, nil},
+		{`(?<n>a)(?P<m>b)`, `(?P<n>a)(?P<m>b)`, nil},
+	}
+	for _, tt := range tests {
+		source, features := translatePCRE(tt.pattern)
+		if source != tt.source || !reflect.DeepEqual(features, tt.features) {
+			t.Errorf("%q: expected %q %v, got %q %v", tt.pattern, tt.source, tt.features, source, features)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ============================================================================
+// Claim Registry
+// ============================================================================
+
+// defaultRegistryTTL is how long a verdict is trusted when
+// ClaimRegistryOptions.TTL is zero.
+const defaultRegistryTTL = 24 * time.Hour
+
+// ClaimRegistryOptions configures a ClaimRegistry.
+type ClaimRegistryOptions struct {
+	// Store holds the entries, under "claims/<hash>". Services sharing a
+	// store share verdicts. Defaults to a MemoryStore.
+	Store Store
+	// TTL is how long a verdict is trusted before the claim is verified
+	// again. Defaults to 24 hours.
+	TTL time.Duration
+}
+
+// RegistryEntry is the latest verdict on a canonicalized claim.
+type RegistryEntry struct {
+	Type       VerificationType      `json:"type"`
+	Claim      string                `json:"claim"` // canonical form, see CanonicalClaim
+	Verdict    Verdict               `json:"verdict"`
+	Response   *VerificationResponse `json:"response,omitempty"` // nil for entries synced from history
+	VerifiedAt time.Time             `json:"verified_at"`
+	ExpiresAt  time.Time             `json:"expires_at"`
+}
+
+// Expired reports whether the entry's verdict is no longer trusted at now.
+func (e *RegistryEntry) Expired(now time.Time) bool {
+	return !now.Before(e.ExpiresAt)
+}
+
+// ClaimRegistry is a verification memo shared across services: it stores
+// canonicalized claims with their latest verdict and expiry, so a claim
+// verified by one service is not sent to the API again by another until
+// its verdict expires.
+//
+//	registry := qwed.NewClaimRegistry(client, qwed.ClaimRegistryOptions{Store: sharedStore})
+//	entries, err := registry.Verify(ctx, qwed.TypeNaturalLanguage, claims)
+//
+// Unlike the response cache, which is keyed by the exact request, entries
+// are keyed by the canonical claim, record when they were verified, and
+// can be seeded from the account's history with Sync.
+type ClaimRegistry struct {
+	client *Client
+	store  Store
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewClaimRegistry creates a registry that verifies claims through client.
+func NewClaimRegistry(client *Client, opts ClaimRegistryOptions) *ClaimRegistry {
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = defaultRegistryTTL
+	}
+	return &ClaimRegistry{client: client, store: opts.Store, ttl: opts.TTL, now: time.Now}
+}
+
+// CanonicalClaim returns the form claims of engine are deduplicated by:
+// lowercased, with typographic quotes folded, whitespace collapsed and
+// trailing punctuation removed. Math expressions also lose all
+// whitespace, so "2 + 2 = 4" and "2+2=4" are the same claim.
+func CanonicalClaim(engine VerificationType, claim string) string {
+	claim = strings.ToLower(claim)
+	claim = strings.NewReplacer("‘", "'", "’", "'", "“", `"`, "”", `"`).Replace(claim)
+	claim = strings.Join(strings.Fields(claim), " ")
+	claim = strings.TrimRightFunc(claim, func(r rune) bool {
+		return r == '.' || r == '!' || r == '?' || unicode.IsSpace(r)
+	})
+	if engine == TypeMath {
+		claim = strings.ReplaceAll(claim, " ", "")
+	}
+	return claim
+}
+
+// Lookup returns the unexpired entries for claims, keyed by the claims as
+// given. Claims with no entry, or an expired one, are missing from the
+// map; call it before the API to find out which claims need verifying.
+func (r *ClaimRegistry) Lookup(ctx context.Context, engine VerificationType, claims []string) (map[string]*RegistryEntry, error) {
+	found := make(map[string]*RegistryEntry)
+	now := r.now()
+	for _, claim := range claims {
+		if _, ok := found[claim]; ok {
+			continue
+		}
+		entry, err := r.get(ctx, engine, CanonicalClaim(engine, claim))
+		if err != nil {
+			return found, err
+		}
+		if entry != nil && !entry.Expired(now) {
+			found[claim] = entry
+		}
+	}
+	return found, nil
+}
+
+// Verify returns an entry for each of claims, keyed by the claims as
+// given. Claims without an unexpired entry are verified through the
+// client, once per canonical claim, and recorded. engine is TypeMath,
+// TypeLogic or TypeNaturalLanguage.
+//
+// Claims that fail to verify, or whose responses are errors or timeouts,
+// are missing from the map; their errors are joined and returned with the
+// entries of the others.
+func (r *ClaimRegistry) Verify(ctx context.Context, engine VerificationType, claims []string) (map[string]*RegistryEntry, error) {
+	entries, err := r.Lookup(ctx, engine, claims)
+	if err != nil {
+		return entries, err
+	}
+
+	var errs []error
+	verified := make(map[string]*RegistryEntry)
+	for _, claim := range claims {
+		if _, ok := entries[claim]; ok {
+			continue
+		}
+		canonical := CanonicalClaim(engine, claim)
+		if entry, ok := verified[canonical]; ok {
+			entries[claim] = entry
+			continue
+		}
+
+		resp, err := warmItem(ctx, r.client, BatchItem{Type: engine, Query: claim})
+		if err == nil && !cacheable(resp) {
+			err = fmt.Errorf("status %s", resp.Status)
+		}
+		var entry *RegistryEntry
+		if err == nil {
+			entry, err = r.Record(ctx, engine, claim, resp)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("verify %q: %w", claim, err))
+			continue
+		}
+		verified[canonical] = entry
+		entries[claim] = entry
+	}
+	return entries, errors.Join(errs...)
+}
+
+// Record stores resp as the latest verdict on claim, replacing any
+// previous entry, and returns the new entry.
+func (r *ClaimRegistry) Record(ctx context.Context, engine VerificationType, claim string, resp *VerificationResponse) (*RegistryEntry, error) {
+	now := r.now()
+	entry := &RegistryEntry{
+		Type:       engine,
+		Claim:      CanonicalClaim(engine, claim),
+		Verdict:    resp.Verdict(),
+		Response:   resp,
+		VerifiedAt: now,
+		ExpiresAt:  now.Add(r.ttl),
+	}
+	return entry, r.put(ctx, entry)
+}
+
+// Sync imports the verdicts in the account's verification history matching
+// filter, so claims verified before the registry existed, or by services
+// not using it, are not verified again. An entry is only replaced by a more
+// recent verdict, and verdicts older than the TTL are skipped. It returns
+// the number of entries imported.
+func (r *ClaimRegistry) Sync(ctx context.Context, filter HistoryFilter) (int, error) {
+	imported := 0
+	now := r.now()
+	err := r.client.eachHistory(ctx, filter, func(h HistoryEntry) (bool, error) {
+		at, err := h.at()
+		if err != nil || now.Sub(at) >= r.ttl {
+			return true, nil
+		}
+
+		engine := VerificationType(h.Domain)
+		if engine == "" {
+			engine = TypeNaturalLanguage
+		}
+		canonical := CanonicalClaim(engine, h.Query)
+		existing, err := r.get(ctx, engine, canonical)
+		if err != nil {
+			return false, err
+		}
+		if existing != nil && !existing.VerifiedAt.Before(at) {
+			return true, nil
+		}
+
+		entry := &RegistryEntry{
+			Type:       engine,
+			Claim:      canonical,
+			Verdict:    VerdictRefuted,
+			VerifiedAt: at,
+			ExpiresAt:  at.Add(r.ttl),
+		}
+		if h.Verified {
+			entry.Verdict = VerdictVerified
+		}
+		if err := r.put(ctx, entry); err != nil {
+			return false, err
+		}
+		imported++
+		return filter.Limit <= 0 || imported < filter.Limit, nil
+	})
+	return imported, err
+}
+
+// Prune deletes expired entries from the store and returns how many were
+// deleted.
+func (r *ClaimRegistry) Prune(ctx context.Context) (int, error) {
+	keys, err := r.store.List(ctx, "claims/")
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+	now := r.now()
+	for _, key := range keys {
+		entry, err := r.load(ctx, key)
+		if err != nil {
+			return pruned, err
+		}
+		if entry == nil || !entry.Expired(now) {
+			continue
+		}
+		if err := r.store.Delete(ctx, key); err != nil {
+			return pruned, err
+		}
+		pruned++
+	}
+	return pruned, nil
+}
+
+// get returns the stored entry for a canonical claim, or nil.
+func (r *ClaimRegistry) get(ctx context.Context, engine VerificationType, canonical string) (*RegistryEntry, error) {
+	return r.load(ctx, registryKey(engine, canonical))
+}
+
+func (r *ClaimRegistry) load(ctx context.Context, key string) (*RegistryEntry, error) {
+	data, err := r.store.Get(ctx, key)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load claim entry: %w", err)
+	}
+	var entry RegistryEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal claim entry %s: %w", key, err)
+	}
+	return &entry, nil
+}
+
+func (r *ClaimRegistry) put(ctx context.Context, entry *RegistryEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal claim entry: %w", err)
+	}
+	if err := r.store.Put(ctx, registryKey(entry.Type, entry.Claim), data); err != nil {
+		return fmt.Errorf("failed to store claim entry: %w", err)
+	}
+	return nil
+}
+
+// registryKey is the store key of a canonical claim.
+func registryKey(engine VerificationType, canonical string) string {
+	return "claims/" + strings.TrimPrefix(contentHash([]byte(CacheKey(engine, canonical))), "sha256:")
+}
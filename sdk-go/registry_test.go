@@ -0,0 +1,150 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCanonicalClaim(t *testing.T) {
+	tests := []struct {
+		engine VerificationType
+		a, b   string
+	}{
+		{TypeNaturalLanguage, "Paris is the capital of France.", "  paris is the  capital of france"},
+		{TypeNaturalLanguage, "It’s “true”!", `it's "true"`},
+		{TypeMath, "2 + 2 = 4", "2+2=4"},
+	}
+	for _, tt := range tests {
+		if a, b := CanonicalClaim(tt.engine, tt.a), CanonicalClaim(tt.engine, tt.b); a != b {
+			t.Errorf("expected %q and %q to be the same claim, got %q and %q", tt.a, tt.b, a, b)
+		}
+	}
+	if CanonicalClaim(TypeNaturalLanguage, "a b") == CanonicalClaim(TypeNaturalLanguage, "ab") {
+		t.Error("expected whitespace between words to be kept outside math")
+	}
+}
+
+func TestClaimRegistryVerify(t *testing.T) {
+	var calls int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		verified := req["expression"] == "2+2=4"
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   map[bool]string{true: "VERIFIED", false: "FAILED"}[verified],
+			"verified": verified,
+		})
+	})
+	defer server.Close()
+
+	store := NewMemoryStore()
+	ctx := context.Background()
+	registry := NewClaimRegistry(NewClient("test-key", WithBaseURL(server.URL)), ClaimRegistryOptions{Store: store, TTL: time.Hour})
+
+	entries, err := registry.Verify(ctx, TypeMath, []string{"2+2=4", "2 + 2 = 4", "2+2=5"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("expected duplicate claims to be verified once, got %d calls", n)
+	}
+	if len(entries) != 3 || entries["2 + 2 = 4"] != entries["2+2=4"] ||
+		entries["2+2=4"].Verdict != VerdictVerified || entries["2+2=5"].Verdict != VerdictRefuted {
+		t.Errorf("unexpected entries: %+v", entries)
+	}
+
+	// A second service sharing the store finds the verdicts without the API.
+	other := NewClaimRegistry(NewClient("test-key", WithBaseURL(server.URL)), ClaimRegistryOptions{Store: store, TTL: time.Hour})
+	found, err := other.Lookup(ctx, TypeMath, []string{"2+2 = 4", "3+3=6"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found["2+2 = 4"] == nil || found["2+2 = 4"].Response == nil {
+		t.Errorf("expected the shared verdict to be found, got %+v", found)
+	}
+
+	// Expired verdicts are verified again and pruned.
+	other.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if found, _ := other.Lookup(ctx, TypeMath, []string{"2+2=4"}); len(found) != 0 {
+		t.Errorf("expected expired verdicts to be missing, got %+v", found)
+	}
+	if pruned, err := other.Prune(ctx); err != nil || pruned != 2 {
+		t.Errorf("expected 2 entries pruned, got %d, %v", pruned, err)
+	}
+}
+
+func TestClaimRegistrySync(t *testing.T) {
+	now := time.Now().UTC()
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/logs" {
+			t.Errorf("expected path /logs, got %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"logs": []HistoryEntry{
+			{ID: 1, Query: "Water boils at 100C.", Verified: true, Domain: "natural_language", Timestamp: now.Add(-time.Hour).Format(time.RFC3339)},
+			{ID: 2, Query: "2+2=5", Verified: false, Domain: "math", Timestamp: now.Add(-2 * time.Hour).Format(time.RFC3339)},
+			{ID: 3, Query: "old claim", Verified: true, Domain: "natural_language", Timestamp: now.Add(-48 * time.Hour).Format(time.RFC3339)},
+		}})
+	})
+	defer server.Close()
+
+	ctx := context.Background()
+	registry := NewClaimRegistry(NewClient("test-key", WithBaseURL(server.URL)), ClaimRegistryOptions{})
+	if _, err := registry.Record(ctx, TypeMath, "2 + 2 = 5", &VerificationResponse{Status: StatusVerified, Verified: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := registry.Sync(ctx, HistoryFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if imported != 1 {
+		t.Errorf("expected only the recent, newer verdict to be imported, got %d", imported)
+	}
+
+	found, _ := registry.Lookup(ctx, TypeNaturalLanguage, []string{"water boils at 100c", "old claim"})
+	if len(found) != 1 || found["water boils at 100c"].Verdict != VerdictVerified || found["water boils at 100c"].Response != nil {
+		t.Errorf("unexpected synced entries: %+v", found)
+	}
+	if found, _ := registry.Lookup(ctx, TypeMath, []string{"2+2=5"}); found["2+2=5"] == nil || found["2+2=5"].Verdict != VerdictVerified {
+		t.Errorf("expected the newer local verdict to be kept, got %+v", found)
+	}
+}
+
+func TestClaimRegistrySyncServerIgnoringOffset(t *testing.T) {
+	now := time.Now().UTC()
+	requests := 0
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if requests++; requests > 10 {
+			t.Error("expected paging to stop")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var logs []HistoryEntry
+		for i := 0; i < historyPageSize; i++ {
+			logs = append(logs, HistoryEntry{
+				ID:        int64(historyPageSize - i),
+				Query:     fmt.Sprintf("%d+%d=%d", i, i, 2*i),
+				Verified:  true,
+				Domain:    "math",
+				Timestamp: now.Add(-time.Duration(i) * time.Minute).Format("2006-01-02T15:04:05.000000"),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"logs": logs})
+	})
+	defer server.Close()
+
+	registry := NewClaimRegistry(NewClient("test-key", WithBaseURL(server.URL)), ClaimRegistryOptions{})
+	imported, err := registry.Sync(context.Background(), HistoryFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if imported != historyPageSize {
+		t.Errorf("expected %d entries imported, got %d", historyPageSize, imported)
+	}
+}
@@ -0,0 +1,126 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Request Dumps
+// ============================================================================
+
+// Dump is a captured verification call: the request as sent and the
+// response as received. Dumps contain no credentials, so customers can
+// attach them to bug reports.
+type Dump struct {
+	CapturedAt time.Time             `json:"captured_at"`
+	Op         string                `json:"op"`
+	Engine     VerificationType      `json:"engine"`
+	Path       string                `json:"path"`
+	Body       json.RawMessage       `json:"body"`
+	Response   *VerificationResponse `json:"response,omitempty"`
+	Error      string                `json:"error,omitempty"`
+}
+
+// DumpInterceptor writes every verification call to a JSON file in dir for
+// later replay. Write failures are ignored so capturing never breaks
+// verification.
+//
+//	client := qwed.NewClient(key, qwed.WithInterceptor(qwed.DumpInterceptor("qwed-dumps")))
+func DumpInterceptor(dir string) Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) (*VerificationResponse, error) {
+		resp, err := next(ctx, req)
+
+		dump := &Dump{
+			CapturedAt: time.Now().UTC(),
+			Op:         req.Op,
+			Engine:     req.Engine,
+			Path:       req.Path,
+			Response:   resp,
+		}
+		if err != nil {
+			dump.Error = err.Error()
+		}
+		if body, marshalErr := json.Marshal(req.Body); marshalErr == nil {
+			dump.Body = body
+			name := fmt.Sprintf("%s-%s.json", dump.CapturedAt.Format("20060102T150405.000000000"), strings.ToLower(req.Op))
+			WriteDump(filepath.Join(dir, name), dump)
+		}
+		return resp, err
+	}
+}
+
+// WriteDump writes d to path as indented JSON, creating the directory if
+// needed.
+func WriteDump(path string, d *Dump) error {
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal dump: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create dump directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write dump: %w", err)
+	}
+	return nil
+}
+
+// LoadDump reads a dump written by DumpInterceptor or WriteDump.
+func LoadDump(path string) (*Dump, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dump: %w", err)
+	}
+	var d Dump
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("failed to parse dump %s: %w", path, err)
+	}
+	if d.Path == "" || len(d.Body) == 0 {
+		return nil, fmt.Errorf("dump %s has no request", path)
+	}
+	return &d, nil
+}
+
+// ============================================================================
+// Replay
+// ============================================================================
+
+// ReplayResult compares a replayed call with its recording.
+type ReplayResult struct {
+	Dump     *Dump                 `json:"dump"`
+	Response *VerificationResponse `json:"response,omitempty"`
+	Error    string                `json:"error,omitempty"`
+	Diff     VerdictDiff           `json:"diff"`
+}
+
+// Changed reports whether the replayed outcome differs from the recording.
+func (r *ReplayResult) Changed() bool {
+	return r.Diff.Changed() || (r.Error == "") != (r.Dump.Error == "")
+}
+
+// Replay re-sends a captured request to the client's API, bypassing the
+// cache and interceptors, and diffs the response against the recorded one.
+// Point the client at another environment with WithBaseURL to reproduce a
+// discrepancy there. Errors from the API are reported in the result; Replay
+// only returns an error if ctx is done.
+func (c *Client) Replay(ctx context.Context, d *Dump) (*ReplayResult, error) {
+	resp := &VerificationResponse{}
+	err := c.request(ctx, "POST", d.Path, d.Body, resp)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, ctxErr
+	}
+
+	result := &ReplayResult{Dump: d, Response: resp}
+	if err != nil {
+		result.Response = nil
+		result.Error = err.Error()
+	}
+	result.Diff = DiffResponses(d.Response, result.Response)
+	return result, nil
+}
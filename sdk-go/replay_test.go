@@ -0,0 +1,94 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDumpAndReplay(t *testing.T) {
+	verified := true
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/math" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["expression"] != "2+2=4" {
+			t.Errorf("unexpected body %v", req)
+		}
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: verified})
+	})
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClient("secret-key", WithBaseURL(server.URL), WithInterceptor(DumpInterceptor(dir)))
+	if _, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*-verifymath.json"))
+	if len(files) != 1 {
+		t.Fatalf("expected one dump, got %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "secret-key") {
+		t.Error("dump must not contain the API key")
+	}
+
+	dump, err := LoadDump(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dump.Op != "VerifyMath" || dump.Engine != TypeMath || !dump.Response.Verified {
+		t.Errorf("unexpected dump: %+v", dump)
+	}
+
+	// Same environment: no change.
+	replayer := NewClient("other-key", WithBaseURL(server.URL))
+	result, err := replayer.Replay(context.Background(), dump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed() {
+		t.Errorf("expected identical replay, got %s", result.Diff)
+	}
+
+	// The environment now disagrees with the recording.
+	verified = false
+	result, err = replayer.Replay(context.Background(), dump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Changed() || !result.Diff.VerdictChanged || result.Response.Verified {
+		t.Errorf("expected verdict change, got %+v", result)
+	}
+}
+
+func TestReplayError(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	dump := &Dump{Path: "/verify/logic", Body: json.RawMessage(`{"query":"p"}`), Response: &VerificationResponse{Verified: true}}
+	result, err := NewClient("k", WithBaseURL(server.URL)).Replay(context.Background(), dump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Error == "" || !result.Changed() {
+		t.Errorf("expected replay error to be reported as a change, got %+v", result)
+	}
+}
+
+func TestLoadDumpInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.json")
+	os.WriteFile(path, []byte(`{"op": "VerifyMath"}`), 0o644)
+	if _, err := LoadDump(path); err == nil {
+		t.Error("expected error for dump without a request")
+	}
+}
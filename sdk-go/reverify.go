@@ -0,0 +1,194 @@
+package qwed
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+)
+
+// ============================================================================
+// Bulk Re-verification
+// ============================================================================
+
+// RangeOptions configures ReverifyRange.
+type RangeOptions struct {
+	// EnginePins replays against pinned engine versions instead of the
+	// current ones, e.g. []string{"code@canary"} to try a release before it
+	// becomes current. See RequestOptions.EnginePin.
+	EnginePins []string `json:"engine_pins,omitempty"`
+	// SampleRate replays a random fraction (0 to 1) of the matching
+	// history. Zero replays all of it.
+	SampleRate float64 `json:"sample_rate,omitempty"`
+
+	// WebhookURL receives a BatchEvent when the job finishes; see
+	// WebhookHandler.
+	WebhookURL string `json:"webhook_url,omitempty"`
+
+	// OnProgress makes ReverifyRange wait for the job to finish, polling it
+	// every PollInterval (default 1 second) and reporting it after each
+	// poll. ReverifyRange then returns the finished job.
+	OnProgress   func(*ReverifyJob) `json:"-"`
+	PollInterval time.Duration      `json:"-"`
+}
+
+// ReverifyJob is a server-side job replaying historical verifications.
+type ReverifyJob struct {
+	JobID    string           `json:"job_id"`
+	Status   string           `json:"status"` // one of the Batch job states
+	Total    int              `json:"total"`  // verifications matching the filter, after sampling
+	Replayed int              `json:"replayed"`
+	Summary  *ReverifySummary `json:"summary,omitempty"`
+	// Flips lists the verifications whose verdict changed, once the job
+	// has finished.
+	Flips []VerdictFlip `json:"flips,omitempty"`
+}
+
+// ReverifySummary counts the outcomes of a re-verification job.
+type ReverifySummary struct {
+	Unchanged     int `json:"unchanged"`
+	NewlyVerified int `json:"newly_verified"` // failed before, verified now
+	NewlyFailed   int `json:"newly_failed"`   // verified before, failed now
+	Errors        int `json:"errors"`         // replays that returned an error
+}
+
+// Flipped returns the number of verdicts that changed.
+func (s *ReverifySummary) Flipped() int {
+	return s.NewlyVerified + s.NewlyFailed
+}
+
+// FlipRate returns the fraction of replayed verifications whose verdict
+// changed, not counting errors.
+func (s *ReverifySummary) FlipRate() float64 {
+	compared := s.Unchanged + s.Flipped()
+	if compared == 0 {
+		return 0
+	}
+	return float64(s.Flipped()) / float64(compared)
+}
+
+// VerdictFlip is a historical verification whose verdict changed on replay.
+type VerdictFlip struct {
+	HistoryID     int64  `json:"history_id"` // HistoryEntry.ID
+	Query         string `json:"query"`
+	Domain        string `json:"domain"`
+	OldVerified   bool   `json:"old_verified"`
+	NewVerified   bool   `json:"new_verified"`
+	OldStatus     Status `json:"old_status,omitempty"`
+	NewStatus     Status `json:"new_status,omitempty"`
+	EngineVersion string `json:"engine_version,omitempty"` // the version that replayed it
+	Reason        string `json:"reason,omitempty"`
+}
+
+// ReverifyRange replays the account's historical verifications matching
+// filter against the current engines as a server-side job, and reports the
+// verdicts that flip, so the impact of an engine upgrade can be quantified
+// before trusting it:
+//
+//	job, err := client.ReverifyRange(ctx, qwed.HistoryFilter{Domain: "code", Since: lastMonth}, &qwed.RangeOptions{
+//		OnProgress: func(j *qwed.ReverifyJob) { log.Printf("%d/%d replayed", j.Replayed, j.Total) },
+//	})
+//	log.Printf("%.1f%% of verdicts flipped", 100*job.Summary.FlipRate())
+//
+// Without OnProgress it returns the submitted job; poll it with
+// GetReverifyJob or register a webhook.
+func (c *Client) ReverifyRange(ctx context.Context, filter HistoryFilter, opts *RangeOptions) (*ReverifyJob, error) {
+	if opts != nil && (opts.SampleRate < 0 || opts.SampleRate > 1) {
+		return nil, invalidRequest("sample rate %v is outside [0, 1]", opts.SampleRate)
+	}
+	if opts != nil {
+		for _, pin := range opts.EnginePins {
+			if !enginePinPattern.MatchString(pin) {
+				return nil, invalidRequest("engine pin %q is not of the form engine@version", pin)
+			}
+		}
+	}
+
+	req := map[string]interface{}{
+		"filter":  filter.body(),
+		"options": opts,
+	}
+	ctx, end := c.instrument(ctx, "ReverifyRange", "")
+	var job ReverifyJob
+	err := c.request(ctx, "POST", "/reverify", req, &job)
+	end(nil, err)
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil || opts.OnProgress == nil {
+		return &job, nil
+	}
+	return c.waitReverify(ctx, &job, opts)
+}
+
+// GetReverifyJob fetches the status and results of a re-verification job.
+func (c *Client) GetReverifyJob(ctx context.Context, jobID string) (*ReverifyJob, error) {
+	ctx, end := c.instrument(ctx, "GetReverifyJob", "")
+	var job ReverifyJob
+	err := c.request(ctx, "GET", "/reverify/"+url.PathEscape(jobID), nil, &job)
+	end(nil, err)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// waitReverify polls a submitted job until it finishes, reporting it to
+// opts.OnProgress. Transient polling failures are retried like waitBatch
+// does.
+func (c *Client) waitReverify(ctx context.Context, job *ReverifyJob, opts *RangeOptions) (*ReverifyJob, error) {
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	failures := 0
+	for {
+		opts.OnProgress(job)
+		if job.Status != BatchPending && job.Status != BatchProcessing {
+			return job, nil
+		}
+
+		for {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return job, ctx.Err()
+			}
+			next, err := c.GetReverifyJob(ctx, job.JobID)
+			if err == nil {
+				job, failures = next, 0
+				break
+			}
+			if ctx.Err() != nil {
+				return job, ctx.Err()
+			}
+			if failures++; failures >= batchStreamRetries || !transient(ctx, err) {
+				return job, fmt.Errorf("failed to poll re-verification job %s: %w", job.JobID, err)
+			}
+			c.log(ctx, slog.LevelInfo, "qwed retrying request", slog.String("job_id", job.JobID), slog.Int("attempt", failures+1), slog.Any("error", err))
+		}
+	}
+}
+
+// body returns the filter as a request body field.
+func (f HistoryFilter) body() map[string]interface{} {
+	body := make(map[string]interface{})
+	if f.Domain != "" {
+		body["domain"] = f.Domain
+	}
+	if f.Verified != nil {
+		body["is_verified"] = *f.Verified
+	}
+	if !f.Since.IsZero() {
+		body["since"] = f.Since.UTC().Format(time.RFC3339)
+	}
+	if !f.Until.IsZero() {
+		body["until"] = f.Until.UTC().Format(time.RFC3339)
+	}
+	if f.Limit > 0 {
+		body["limit"] = f.Limit
+	}
+	return body
+}
@@ -0,0 +1,95 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestReverifyRange(t *testing.T) {
+	var polls int32
+	since := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/reverify":
+			var req struct {
+				Filter  map[string]interface{} `json:"filter"`
+				Options *RangeOptions          `json:"options"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			if req.Filter["domain"] != "code" || req.Filter["since"] != "2026-09-01T00:00:00Z" || req.Filter["is_verified"] != true {
+				t.Errorf("unexpected filter: %v", req.Filter)
+			}
+			if req.Options == nil || req.Options.SampleRate != 0.5 || len(req.Options.EnginePins) != 1 {
+				t.Errorf("unexpected options: %+v", req.Options)
+			}
+			json.NewEncoder(w).Encode(ReverifyJob{JobID: "rv-1", Status: BatchPending, Total: 4})
+		case r.Method == "GET" && r.URL.Path == "/reverify/rv-1":
+			if atomic.AddInt32(&polls, 1) == 1 {
+				json.NewEncoder(w).Encode(ReverifyJob{JobID: "rv-1", Status: BatchProcessing, Total: 4, Replayed: 2})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"job_id": "rv-1", "status": "completed", "total": 4, "replayed": 4,
+				"summary": map[string]int{"unchanged": 2, "newly_failed": 1, "errors": 1},
+				"flips": []map[string]interface{}{
+					{"history_id": 42, "query": "eval(input())", "domain": "code", "old_verified": true, "new_verified": false, "engine_version": "3.3.0"},
+				},
+			})
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	verified := true
+	var progress []int
+	job, err := client.ReverifyRange(context.Background(), HistoryFilter{Domain: "code", Verified: &verified, Since: since}, &RangeOptions{
+		EnginePins:   []string{"code@canary"},
+		SampleRate:   0.5,
+		OnProgress:   func(j *ReverifyJob) { progress = append(progress, j.Replayed) },
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(progress) != 3 || progress[2] != 4 {
+		t.Errorf("expected progress after each poll, got %v", progress)
+	}
+	if job.Status != BatchCompleted || job.Summary == nil || len(job.Flips) != 1 {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+	if f := job.Flips[0]; f.HistoryID != 42 || !f.OldVerified || f.NewVerified || f.EngineVersion != "3.3.0" {
+		t.Errorf("unexpected flip: %+v", f)
+	}
+	if job.Summary.Flipped() != 1 || job.Summary.FlipRate() != 1.0/3 {
+		t.Errorf("unexpected summary: %+v, flip rate %v", job.Summary, job.Summary.FlipRate())
+	}
+}
+
+func TestReverifyRangeSubmitOnly(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(ReverifyJob{JobID: "rv-2", Status: BatchPending})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	job, err := client.ReverifyRange(context.Background(), HistoryFilter{}, nil)
+	if err != nil || job.JobID != "rv-2" || job.Status != BatchPending {
+		t.Errorf("expected the submitted job, got %+v, %v", job, err)
+	}
+
+	for _, opts := range []*RangeOptions{{SampleRate: 1.5}, {EnginePins: []string{"3.2.1"}}} {
+		if _, err := client.ReverifyRange(context.Background(), HistoryFilter{}, opts); !errors.Is(err, ErrInvalidRequest) {
+			t.Errorf("expected ErrInvalidRequest for %+v, got %v", opts, err)
+		}
+	}
+	if (&ReverifySummary{}).FlipRate() != 0 {
+		t.Error("expected a zero flip rate without replays")
+	}
+}
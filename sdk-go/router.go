@@ -0,0 +1,213 @@
+package qwed
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Routing
+// ============================================================================
+
+// Route is the engine chosen for an input and why.
+type Route struct {
+	Engine   VerificationType `json:"engine"`
+	Reason   string           `json:"reason"`
+	Input    string           `json:"input"`              // the text sent to the engine
+	Language string           `json:"language,omitempty"` // for TypeCode
+}
+
+// RoutedResponse is a response together with the route that produced it.
+type RoutedResponse struct {
+	Route    Route                 `json:"route"`
+	Response *VerificationResponse `json:"response"`
+}
+
+// Router classifies inputs locally and sends each to the matching engine,
+// instead of sending everything to the natural-language endpoint.
+//
+//	router := qwed.NewRouter(client, qwed.WithRouterSQLSchema(ddl, "postgresql"))
+//	routed, err := router.Verify(ctx, "SELECT name FROM users")
+//	fmt.Println(routed.Route.Engine, routed.Route.Reason) // sql starts with SQL keyword SELECT
+type Router struct {
+	verifier    Verifier
+	schemaDDL   string
+	dialect     string
+	factContext string
+}
+
+// RouterOption configures a Router.
+type RouterOption func(*Router)
+
+// WithRouterSQLSchema sets the schema and dialect SQL inputs are verified
+// against.
+func WithRouterSQLSchema(schemaDDL, dialect string) RouterOption {
+	return func(r *Router) {
+		r.schemaDDL = schemaDDL
+		r.dialect = dialect
+	}
+}
+
+// WithRouterFactContext sets the source text factual claims are checked
+// against. Without it, factual claims go to the natural-language engine.
+func WithRouterFactContext(factContext string) RouterOption {
+	return func(r *Router) {
+		r.factContext = factContext
+	}
+}
+
+// NewRouter creates a router dispatching to v.
+func NewRouter(v Verifier, opts ...RouterOption) *Router {
+	r := &Router{verifier: v}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Route classifies query without calling the API. Inputs are checked in
+// order:
+//   - a fenced code block goes to the code engine, or to the SQL engine if
+//     it is labelled sql; unlabelled blocks go to the natural-language engine
+//   - text starting with a SQL statement keyword goes to the SQL engine
+//   - an equation or arithmetic expression goes to the math engine
+//   - a statement goes to the fact engine if the router has a fact context
+//   - anything else goes to the natural-language engine
+func (r *Router) Route(query string) Route {
+	text := strings.TrimSpace(query)
+
+	if label, code, ok := firstCodeBlock(text); ok {
+		switch lang := FenceLanguage(label); {
+		case lang == "sql":
+			return Route{Engine: TypeSQL, Reason: "fenced code block labelled " + label, Input: code}
+		case lang != "":
+			return Route{Engine: TypeCode, Reason: "fenced code block labelled " + label, Input: code, Language: lang}
+		default:
+			return Route{Engine: TypeNaturalLanguage, Reason: "code block without a recognised language", Input: query}
+		}
+	}
+	if keyword := sqlKeyword(text); keyword != "" {
+		return Route{Engine: TypeSQL, Reason: "starts with SQL keyword " + keyword, Input: text}
+	}
+	if isArithmetic(text) {
+		return Route{Engine: TypeMath, Reason: "equation between numeric expressions", Input: text}
+	}
+	if strings.ContainsAny(text, "0123456789") && strings.ContainsAny(text, "+-*/^") {
+		if _, err := evalArithmetic(text); err == nil {
+			return Route{Engine: TypeMath, Reason: "arithmetic expression", Input: text}
+		}
+	}
+	if r.factContext != "" && !strings.HasSuffix(text, "?") && len(strings.Fields(text)) >= 2 {
+		return Route{Engine: TypeFact, Reason: "factual claim checked against the fact context", Input: text}
+	}
+	return Route{Engine: TypeNaturalLanguage, Reason: "no specialised engine matched", Input: query}
+}
+
+// Verify routes query and verifies it with the chosen engine.
+func (r *Router) Verify(ctx context.Context, query string) (*RoutedResponse, error) {
+	route := r.Route(query)
+
+	var resp *VerificationResponse
+	var err error
+	switch route.Engine {
+	case TypeCode:
+		resp, err = r.verifier.VerifyCode(ctx, route.Input, route.Language)
+	case TypeSQL:
+		resp, err = r.verifier.VerifySQL(ctx, route.Input, r.schemaDDL, r.dialect)
+	case TypeMath:
+		resp, err = r.verifier.VerifyMath(ctx, route.Input)
+	case TypeFact:
+		resp, err = r.verifier.VerifyFact(ctx, route.Input, r.factContext)
+	default:
+		resp, err = r.verifier.Verify(ctx, route.Input)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify routed %s input: %w", route.Engine, err)
+	}
+	return &RoutedResponse{Route: route, Response: resp}, nil
+}
+
+// fenceLanguages maps Markdown fence labels to engine language names.
+var fenceLanguages = map[string]string{
+	"py":         "python",
+	"python":     "python",
+	"js":         "javascript",
+	"javascript": "javascript",
+	"jsx":        "javascript",
+	"ts":         "typescript",
+	"typescript": "typescript",
+	"tsx":        "typescript",
+	"go":         "go",
+	"golang":     "go",
+	"java":       "java",
+	"rb":         "ruby",
+	"ruby":       "ruby",
+	"rs":         "rust",
+	"rust":       "rust",
+	"sh":         "bash",
+	"bash":       "bash",
+	"php":        "php",
+	"c":          "c",
+	"cpp":        "cpp",
+	"c++":        "cpp",
+	"cs":         "csharp",
+	"csharp":     "csharp",
+	"sql":        "sql",
+}
+
+// FenceLanguage returns the engine language name for a Markdown code fence
+// label such as "py" or "golang", or "" if the label is not recognised.
+func FenceLanguage(label string) string {
+	return fenceLanguages[strings.ToLower(strings.TrimSpace(label))]
+}
+
+// firstCodeBlock returns the label and contents of the first fenced code
+// block in text. An unterminated fence runs to the end of the text.
+func firstCodeBlock(text string) (label, code string, ok bool) {
+	start := strings.Index(text, "```")
+	if start < 0 {
+		return "", "", false
+	}
+	rest := text[start+3:]
+	newline := strings.IndexByte(rest, '\n')
+	if newline < 0 {
+		return "", "", false
+	}
+	label, rest = strings.TrimSpace(rest[:newline]), rest[newline+1:]
+	if end := strings.Index(rest, "```"); end >= 0 {
+		rest = rest[:end]
+	}
+	return label, rest, true
+}
+
+// sqlClauses maps SQL statement keywords to the clauses that confirm the
+// statement, so prose such as "Update the docs" is not mistaken for SQL.
+var sqlClauses = map[string][]string{
+	"SELECT": {" FROM "},
+	"DELETE": {" FROM "},
+	"WITH":   {" AS "},
+	"INSERT": {" INTO "},
+	"UPDATE": {" SET "},
+	"MERGE":  {" INTO ", " USING "},
+	"CREATE": {" TABLE ", " VIEW ", " INDEX "},
+	"ALTER":  {" TABLE ", " VIEW ", " INDEX "},
+	"DROP":   {" TABLE ", " VIEW ", " INDEX "},
+}
+
+// sqlKeyword returns the SQL statement keyword text starts with, or "" if
+// text does not look like a SQL statement.
+func sqlKeyword(text string) string {
+	fields := strings.Fields(text)
+	if len(fields) < 2 {
+		return ""
+	}
+	keyword := strings.ToUpper(fields[0])
+	rest := " " + strings.ToUpper(strings.Join(fields[1:], " ")) + " "
+	for _, clause := range sqlClauses[keyword] {
+		if strings.Contains(rest, clause) {
+			return keyword
+		}
+	}
+	return ""
+}
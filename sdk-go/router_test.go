@@ -0,0 +1,84 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRouterRoute(t *testing.T) {
+	router := NewRouter(nil, WithRouterFactContext("Paris is the capital of France."))
+
+	tests := []struct {
+		query  string
+		engine VerificationType
+		input  string
+		reason string
+	}{
+		{"Here:\n```py\nprint(1)\n```\n", TypeCode, "print(1)\n", "fenced code block labelled py"},
+		{"```sql\nSELECT 1\n```", TypeSQL, "SELECT 1\n", "fenced code block labelled sql"},
+		{"select name from users where id = 1", TypeSQL, "select name from users where id = 1", "starts with SQL keyword SELECT"},
+		{"Update the docs before release", TypeFact, "Update the docs before release", "factual claim checked against the fact context"},
+		{"2 + 2 = 4", TypeMath, "2 + 2 = 4", "equation between numeric expressions"},
+		{"sqrt(16) * 3", TypeMath, "sqrt(16) * 3", "arithmetic expression"},
+		{"Paris is the capital of France", TypeFact, "Paris is the capital of France", "factual claim checked against the fact context"},
+		{"What is the capital of France?", TypeNaturalLanguage, "What is the capital of France?", "no specialised engine matched"},
+		{"```\nplain\n```", TypeNaturalLanguage, "```\nplain\n```", "code block without a recognised language"},
+	}
+	for _, tt := range tests {
+		route := router.Route(tt.query)
+		if route.Engine != tt.engine || route.Input != tt.input || route.Reason != tt.reason {
+			t.Errorf("Route(%q) = %+v, want %s %q (%s)", tt.query, route, tt.engine, tt.input, tt.reason)
+		}
+	}
+
+	if route := NewRouter(nil).Route("Paris is the capital of France"); route.Engine != TypeNaturalLanguage {
+		t.Errorf("expected claims without fact context to use natural language, got %s", route.Engine)
+	}
+	if route := router.Route("```golang\nfunc main() {}\n```"); route.Language != "go" {
+		t.Errorf("expected language go, got %q", route.Language)
+	}
+}
+
+func TestRouterVerify(t *testing.T) {
+	var paths []string
+	var body map[string]interface{}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true, Engine: Engine(strings.TrimPrefix(r.URL.Path, "/verify/"))})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	router := NewRouter(client, WithRouterSQLSchema("CREATE TABLE users (id INT)", "sqlite"))
+
+	routed, err := router.Verify(context.Background(), "SELECT id FROM users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if routed.Route.Engine != TypeSQL || !routed.Response.Verified {
+		t.Errorf("unexpected routed response: %+v", routed)
+	}
+	if body["schema_ddl"] != "CREATE TABLE users (id INT)" || body["dialect"] != "sqlite" {
+		t.Errorf("expected schema and dialect in request, got %v", body)
+	}
+
+	if _, err := router.Verify(context.Background(), "```python\nx = 1\n```"); err != nil {
+		t.Fatal(err)
+	}
+	if body["code"] != "x = 1\n" || body["language"] != "python" {
+		t.Errorf("unexpected code request: %v", body)
+	}
+
+	if _, err := router.Verify(context.Background(), "3 * 4 = 12"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/verify/sql", "/verify/code", "/verify/math"}
+	if strings.Join(paths, ",") != strings.Join(want, ",") {
+		t.Errorf("expected requests to %v, got %v", want, paths)
+	}
+}
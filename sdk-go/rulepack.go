@@ -0,0 +1,402 @@
+package qwed
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Rule Packs
+// ============================================================================
+
+// ErrInvalidRulePack is returned when a rule pack is malformed, not signed
+// by the expected key, or older than the pack already loaded.
+var ErrInvalidRulePack = errors.New("qwed: invalid rule pack")
+
+// RulePack is a versioned bundle of engine rules, policy presets and format
+// validators, distributed as a signed JWS so services can pick up new rules
+// without being redeployed:
+//
+//	{
+//	  "name": "acme-default",
+//	  "version": 42,
+//	  "rules": {"rules": {"weak_hash": {"severity": "CRITICAL"}}},
+//	  "policies": {"strict": {"min_confidence": 0.97, "fail_on_severity": "WARNING"}},
+//	  "validators": {"invoice_id": "^INV-[0-9]{6}$"}
+//	}
+type RulePack struct {
+	Name    string `json:"name"`
+	Version int64  `json:"version"` // must not decrease between reloads
+
+	// Rules are the default code and SQL rule settings. Policy rules and
+	// WithRuleConfig take precedence.
+	Rules *RuleConfig `json:"rules,omitempty"`
+
+	// Policies replace the client's policy of the same name, so a preset
+	// such as "strict" can be tightened centrally.
+	Policies map[string]Policy `json:"policies,omitempty"`
+
+	// Validators are named RE2 patterns checked by VerifyFormat. Patterns
+	// must match the whole value.
+	Validators map[string]string `json:"validators,omitempty"`
+
+	validators map[string]*regexp.Regexp
+}
+
+// compile validates the pack and compiles its validators.
+func (p *RulePack) compile() error {
+	if p.Name == "" {
+		return fmt.Errorf("%w: missing name", ErrInvalidRulePack)
+	}
+	if p.Rules != nil {
+		if err := p.Rules.Validate(); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRulePack, err)
+		}
+	}
+	for name, policy := range p.Policies {
+		if policy.Rules == nil {
+			continue
+		}
+		if err := policy.Rules.Validate(); err != nil {
+			return fmt.Errorf("%w: policy %q: %v", ErrInvalidRulePack, name, err)
+		}
+	}
+	p.validators = make(map[string]*regexp.Regexp, len(p.Validators))
+	for name, pattern := range p.Validators {
+		re, err := regexp.Compile(`^(?:` + pattern + `)$`)
+		if err != nil {
+			return fmt.Errorf("%w: validator %q: %v", ErrInvalidRulePack, name, err)
+		}
+		p.validators[name] = re
+	}
+	return nil
+}
+
+// SignRulePack encodes pack as a JWS signed with privateKey, an
+// ed25519.PrivateKey (EdDSA) or an *ecdsa.PrivateKey on P-256 (ES256).
+func SignRulePack(pack *RulePack, privateKey crypto.Signer) ([]byte, error) {
+	if err := pack.compile(); err != nil {
+		return nil, err
+	}
+
+	var alg string
+	switch key := privateKey.(type) {
+	case ed25519.PrivateKey:
+		alg = "EdDSA"
+	case *ecdsa.PrivateKey:
+		if key.Curve.Params().Name != "P-256" {
+			return nil, fmt.Errorf("unsupported signing key curve %s", key.Curve.Params().Name)
+		}
+		alg = "ES256"
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %T", privateKey)
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "qwed-rulepack"})
+	payload, err := json.Marshal(pack)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rule pack: %w", err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var sig []byte
+	switch key := privateKey.(type) {
+	case ed25519.PrivateKey:
+		sig = ed25519.Sign(key, []byte(signed))
+	case *ecdsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign rule pack: %w", err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+	return []byte(signed + "." + base64.RawURLEncoding.EncodeToString(sig)), nil
+}
+
+// ParseRulePack checks the signature of a signed rule pack against
+// publicKey, an ed25519.PublicKey or an *ecdsa.PublicKey on P-256, and
+// returns the pack.
+func ParseRulePack(signed []byte, publicKey crypto.PublicKey) (*RulePack, error) {
+	parts := strings.Split(string(bytes.TrimSpace(signed)), ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed signature envelope", ErrInvalidRulePack)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(data, &header) != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidRulePack)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidRulePack)
+	}
+	if err := verifySignature(header.Alg, publicKey, []byte(parts[0]+"."+parts[1]), sig, ErrInvalidRulePack); err != nil {
+		return nil, err
+	}
+
+	var pack RulePack
+	data, err = base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed payload", ErrInvalidRulePack)
+	}
+	if err := json.Unmarshal(data, &pack); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRulePack, err)
+	}
+	if err := pack.compile(); err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+// ============================================================================
+// Rule Pack Sources
+// ============================================================================
+
+// RulePackSource fetches a signed rule pack.
+type RulePackSource func(ctx context.Context) ([]byte, error)
+
+// RulePackFile reads a signed rule pack from a file, for packs synced to
+// disk by a config management tool.
+func RulePackFile(path string) RulePackSource {
+	return func(ctx context.Context) ([]byte, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rule pack: %w", err)
+		}
+		return data, nil
+	}
+}
+
+// RulePackURL downloads a signed rule pack from rawURL with httpClient, or
+// http.DefaultClient if nil.
+func RulePackURL(rawURL string, httpClient *http.Client) RulePackSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download rule pack: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to download rule pack: %s", resp.Status)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rule pack: %w", err)
+		}
+		return data, nil
+	}
+}
+
+// RulePackSource returns a source fetching the named rule pack from the
+// API's /rule-packs endpoint.
+func (c *Client) RulePackSource(name string) RulePackSource {
+	return func(ctx context.Context) ([]byte, error) {
+		var result struct {
+			Pack string `json:"pack"`
+		}
+		if err := c.request(ctx, "GET", "/rule-packs/"+url.PathEscape(name), nil, &result); err != nil {
+			return nil, err
+		}
+		return []byte(result.Pack), nil
+	}
+}
+
+// ============================================================================
+// Rule Pack Loader
+// ============================================================================
+
+// RulePackOptions configures a RulePackLoader.
+type RulePackOptions struct {
+	// PublicKey verifies pack signatures. Required.
+	PublicKey crypto.PublicKey
+	// Interval is how often Run checks the source. Defaults to 5 minutes.
+	Interval time.Duration
+
+	// OnReload is called after a new pack is loaded.
+	OnReload func(*RulePack)
+	// OnError is called when Run fails to load a pack; the previous pack
+	// stays in effect.
+	OnError func(error)
+}
+
+// RulePackLoader keeps the latest verified rule pack from a source. Pass it
+// to clients with WithRulePacks; packs it loads take effect on their next
+// call. It is safe for concurrent use.
+type RulePackLoader struct {
+	source RulePackSource
+	opts   RulePackOptions
+
+	mu      sync.RWMutex
+	current *RulePack
+	raw     []byte
+}
+
+// NewRulePackLoader creates a loader for packs from source. No pack is in
+// effect until Load or Run succeeds.
+func NewRulePackLoader(source RulePackSource, opts RulePackOptions) *RulePackLoader {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Minute
+	}
+	return &RulePackLoader{source: source, opts: opts}
+}
+
+// Current returns the pack in effect, or nil if none has been loaded.
+func (l *RulePackLoader) Current() *RulePack {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.current
+}
+
+// Load fetches the pack, verifies it and puts it into effect. A pack that
+// is unchanged is ignored; one with a lower version than the current pack
+// is rejected, so an old pack cannot be replayed to roll rules back.
+func (l *RulePackLoader) Load(ctx context.Context) error {
+	if l.opts.PublicKey == nil {
+		return fmt.Errorf("rule pack loader requires a public key")
+	}
+	data, err := l.source(ctx)
+	if err != nil {
+		return err
+	}
+
+	l.mu.RLock()
+	unchanged := l.raw != nil && bytes.Equal(data, l.raw)
+	l.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	pack, err := ParseRulePack(data, l.opts.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	if l.current != nil && pack.Version < l.current.Version {
+		l.mu.Unlock()
+		return fmt.Errorf("%w: version %d is older than loaded version %d", ErrInvalidRulePack, pack.Version, l.current.Version)
+	}
+	l.current, l.raw = pack, data
+	l.mu.Unlock()
+
+	if l.opts.OnReload != nil {
+		l.opts.OnReload(pack)
+	}
+	return nil
+}
+
+// Run loads the pack immediately and then every Interval until ctx is
+// cancelled. Load failures are reported to OnError.
+func (l *RulePackLoader) Run(ctx context.Context) error {
+	ticker := time.NewTicker(l.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := l.Load(ctx); err != nil && ctx.Err() == nil && l.opts.OnError != nil {
+			l.opts.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// WithRulePacks applies the rule pack loaded by loader: its rules become the
+// default rules, its policies replace the client's policy of the same name,
+// and its validators are used by VerifyFormat. Reloaded packs apply to the
+// following calls.
+func WithRulePacks(loader *RulePackLoader) ClientOption {
+	return func(c *Client) {
+		c.rulePacks = loader
+	}
+}
+
+// rulePack returns the client's current rule pack, or nil.
+func (c *Client) rulePack() *RulePack {
+	if c.rulePacks == nil {
+		return nil
+	}
+	return c.rulePacks.Current()
+}
+
+// ============================================================================
+// Format Validation
+// ============================================================================
+
+// TypeFormat identifies format checks against rule pack validators. They
+// run locally and are reported with Engine EngineLocalFormat.
+const TypeFormat VerificationType = "format"
+
+// EngineLocalFormat is the engine name reported by VerifyFormat.
+const EngineLocalFormat Engine = "local-format"
+
+// VerifyFormat checks that value, such as an ID or code extracted from an
+// LLM answer, matches the named validator of the client's rule pack. It is
+// StatusUnsupported if no pack is loaded or the pack has no such validator,
+// with the available validators in Result["validators"].
+func (c *Client) VerifyFormat(ctx context.Context, validator, value string) (resp *VerificationResponse, err error) {
+	_, end := c.instrument(ctx, "VerifyFormat", TypeFormat)
+	defer func() { end(resp, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	pack := c.rulePack()
+	if pack == nil {
+		return &VerificationResponse{Status: StatusUnsupported, Engine: EngineLocalFormat, Result: map[string]interface{}{
+			"reason": "no rule pack is loaded",
+		}}, nil
+	}
+	result := map[string]interface{}{
+		"validator": validator,
+		"rule_pack": pack.Name,
+		"version":   pack.Version,
+	}
+	re, ok := pack.validators[validator]
+	if !ok {
+		names := make([]string, 0, len(pack.validators))
+		for name := range pack.validators {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		result["reason"] = fmt.Sprintf("rule pack %s has no validator %q", pack.Name, validator)
+		result["validators"] = names
+		return &VerificationResponse{Status: StatusUnsupported, Engine: EngineLocalFormat, Result: result}, nil
+	}
+	result["pattern"] = pack.Validators[validator]
+	return localResponse(EngineLocalFormat, re.MatchString(value), result), nil
+}
@@ -0,0 +1,222 @@
+package qwed
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testRulePack(version int64, severity string) *RulePack {
+	return &RulePack{
+		Name:       "acme",
+		Version:    version,
+		Rules:      (&RuleConfig{}).SetSeverity("weak_hash", severity),
+		Policies:   map[string]Policy{"standard": {MinConfidence: 0.95, FailOnSeverity: SeverityCritical}},
+		Validators: map[string]string{"invoice_id": `INV-[0-9]{6}`},
+	}
+}
+
+func TestRulePackSignAndParse(t *testing.T) {
+	edPub, edPriv, _ := ed25519.GenerateKey(rand.Reader)
+	ecPriv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherPub, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	signed, err := SignRulePack(testRulePack(1, SeverityCritical), edPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pack, err := ParseRulePack(signed, edPub)
+	if err != nil {
+		t.Fatalf("expected a valid pack, got %v", err)
+	}
+	if pack.Name != "acme" || pack.Version != 1 || pack.Rules.Rules["weak_hash"].Severity != SeverityCritical {
+		t.Errorf("unexpected pack: %+v", pack)
+	}
+
+	signed, err = SignRulePack(testRulePack(1, SeverityCritical), ecPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseRulePack(signed, &ecPriv.PublicKey); err != nil {
+		t.Errorf("expected a valid ES256 pack, got %v", err)
+	}
+	if _, err := ParseRulePack(signed, edPub); !errors.Is(err, ErrInvalidRulePack) {
+		t.Errorf("expected an algorithm mismatch to be rejected, got %v", err)
+	}
+
+	signed, _ = SignRulePack(testRulePack(1, SeverityCritical), edPriv)
+	if _, err := ParseRulePack(signed, otherPub); !errors.Is(err, ErrInvalidRulePack) {
+		t.Errorf("expected a pack signed by another key to be rejected, got %v", err)
+	}
+	parts := strings.Split(string(signed), ".")
+	tampered, _ := json.Marshal(testRulePack(1, SeverityInfo))
+	parts[1] = base64.RawURLEncoding.EncodeToString(tampered)
+	if _, err := ParseRulePack([]byte(strings.Join(parts, ".")), edPub); !errors.Is(err, ErrInvalidRulePack) {
+		t.Errorf("expected a tampered pack to be rejected, got %v", err)
+	}
+
+	bad := testRulePack(1, SeverityCritical)
+	bad.Validators["broken"] = "("
+	if _, err := SignRulePack(bad, edPriv); !errors.Is(err, ErrInvalidRulePack) {
+		t.Errorf("expected an invalid validator to be rejected, got %v", err)
+	}
+}
+
+func TestRulePackHotReload(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	path := filepath.Join(t.TempDir(), "acme.pack")
+	publish := func(pack *RulePack) {
+		signed, err := SignRulePack(pack, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, signed, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var sent map[string]interface{}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&sent)
+		json.NewEncoder(w).Encode(VerificationResponse{
+			Status:   StatusVerified,
+			Verified: true,
+			Result:   map[string]interface{}{"confidence": 0.9},
+		})
+	})
+	defer server.Close()
+
+	var reloads int
+	loader := NewRulePackLoader(RulePackFile(path), RulePackOptions{
+		PublicKey: pub,
+		OnReload:  func(*RulePack) { reloads++ },
+	})
+	client := NewClient("test-key", WithBaseURL(server.URL),
+		WithPolicy(PolicyStandard),
+		WithRuleConfig((&RuleConfig{}).Disable("os_system")),
+		WithRulePacks(loader))
+	ctx := context.Background()
+
+	// Before any pack is loaded the built-in policy applies.
+	if resp, _ := client.VerifyMath(ctx, "2+2=4"); !resp.Verified {
+		t.Errorf("expected the built-in standard policy to pass, got %+v", resp)
+	}
+
+	publish(testRulePack(1, SeverityCritical))
+	if err := loader.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if resp, _ := client.VerifyMath(ctx, "2+2=4"); resp.Verified || resp.InconclusiveReason() != ReasonLowConfidence {
+		t.Errorf("expected the pack's standard policy to apply, got %+v", resp)
+	}
+	rules := client.requestOptions(nil).Rules.Rules
+	if rules["weak_hash"].Severity != SeverityCritical || rules["os_system"].Enabled == nil {
+		t.Errorf("expected pack and client rules, got %+v", rules)
+	}
+
+	publish(testRulePack(2, SeverityInfo))
+	if err := loader.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	client.VerifyCode(ctx, "import md5", "python")
+	opts, _ := sent["options"].(map[string]interface{})
+	if !strings.Contains(mustJSON(t, opts), `"weak_hash":{"severity":"INFO"}`) {
+		t.Errorf("expected the reloaded rule to be sent, got %v", opts)
+	}
+
+	publish(testRulePack(1, SeverityCritical))
+	if err := loader.Load(ctx); !errors.Is(err, ErrInvalidRulePack) {
+		t.Errorf("expected an older pack to be rejected, got %v", err)
+	}
+	if loader.Current().Version != 2 || reloads != 2 {
+		t.Errorf("expected version 2 to stay in effect after 2 reloads, got %d after %d", loader.Current().Version, reloads)
+	}
+	if err := loader.Load(ctx); !errors.Is(err, ErrInvalidRulePack) {
+		t.Errorf("expected the older pack to be rejected again, got %v", err)
+	}
+}
+
+func TestRulePackLoaderRun(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signed, _ := SignRulePack(testRulePack(7, SeverityCritical), priv)
+
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rule-packs/acme" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"pack": string(signed)})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	reloaded := make(chan *RulePack, 1)
+	loader := NewRulePackLoader(client.RulePackSource("acme"), RulePackOptions{
+		PublicKey: pub,
+		Interval:  time.Hour,
+		OnReload:  func(p *RulePack) { reloaded <- p },
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- loader.Run(ctx) }()
+
+	select {
+	case pack := <-reloaded:
+		if pack.Version != 7 {
+			t.Errorf("expected version 7, got %d", pack.Version)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("pack was not loaded")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("expected Run to stop with the context, got %v", err)
+	}
+}
+
+func TestVerifyFormat(t *testing.T) {
+	ctx := context.Background()
+	client := NewClient("test-key")
+	if resp, _ := client.VerifyFormat(ctx, "invoice_id", "INV-123456"); resp.Status != StatusUnsupported {
+		t.Errorf("expected unsupported without a pack, got %+v", resp)
+	}
+
+	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
+	signed, _ := SignRulePack(testRulePack(1, SeverityCritical), priv)
+	loader := NewRulePackLoader(func(context.Context) ([]byte, error) { return signed, nil }, RulePackOptions{PublicKey: pub})
+	if err := loader.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	client = NewClient("test-key", WithRulePacks(loader))
+
+	if resp, _ := client.VerifyFormat(ctx, "invoice_id", "INV-123456"); !resp.Verified || resp.Engine != EngineLocalFormat {
+		t.Errorf("expected a valid invoice ID, got %+v", resp)
+	}
+	if resp, _ := client.VerifyFormat(ctx, "invoice_id", "INV-123456-extra"); resp.Verified {
+		t.Errorf("expected validators to match the whole value, got %+v", resp)
+	}
+	resp, _ := client.VerifyFormat(ctx, "iban", "DE89")
+	if resp.Status != StatusUnsupported || !strings.Contains(mustJSON(t, resp.Result["validators"]), "invoice_id") {
+		t.Errorf("expected unknown validator to be unsupported with the available ones, got %+v", resp)
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
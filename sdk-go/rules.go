@@ -0,0 +1,207 @@
+package qwed
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ============================================================================
+// Rule Configuration
+// ============================================================================
+
+// RuleSetting tunes a single engine rule.
+type RuleSetting struct {
+	Enabled  *bool                  `json:"enabled,omitempty"`  // nil keeps the engine default
+	Severity string                 `json:"severity,omitempty"` // override, e.g. SeverityWarning
+	Params   map[string]interface{} `json:"params,omitempty"`   // rule-specific parameters
+}
+
+// RuleConfig tunes the strictness of the code and SQL engines per rule. It
+// is sent with VerifyCode and VerifySQL requests and can be stored in a
+// JSON config file:
+//
+//	{
+//	  "rules": {
+//	    "weak_hash":   {"severity": "INFO"},
+//	    "os_system":   {"enabled": false},
+//	    "max_joins":   {"params": {"limit": 4}}
+//	  }
+//	}
+type RuleConfig struct {
+	Rules map[string]RuleSetting `json:"rules"`
+}
+
+// LoadRuleConfig reads a rule configuration file.
+func LoadRuleConfig(path string) (*RuleConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule config: %w", err)
+	}
+
+	var cfg RuleConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse rule config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Save writes the configuration to path as indented JSON.
+func (r *RuleConfig) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule config: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write rule config: %w", err)
+	}
+	return nil
+}
+
+// Validate checks that severity overrides use known levels.
+func (r *RuleConfig) Validate() error {
+	for id, s := range r.Rules {
+		switch s.Severity {
+		case "", SeverityCritical, SeverityWarning, SeverityInfo:
+		default:
+			return fmt.Errorf("rule %q: unknown severity %q", id, s.Severity)
+		}
+	}
+	return nil
+}
+
+// Enable turns a rule on.
+func (r *RuleConfig) Enable(rule string) *RuleConfig {
+	return r.update(rule, func(s *RuleSetting) { s.Enabled = boolPtr(true) })
+}
+
+// Disable turns a rule off.
+func (r *RuleConfig) Disable(rule string) *RuleConfig {
+	return r.update(rule, func(s *RuleSetting) { s.Enabled = boolPtr(false) })
+}
+
+// SetSeverity overrides the severity reported for a rule.
+func (r *RuleConfig) SetSeverity(rule, severity string) *RuleConfig {
+	return r.update(rule, func(s *RuleSetting) { s.Severity = strings.ToUpper(severity) })
+}
+
+// SetParam sets a rule-specific parameter.
+func (r *RuleConfig) SetParam(rule, name string, value interface{}) *RuleConfig {
+	return r.update(rule, func(s *RuleSetting) {
+		if s.Params == nil {
+			s.Params = make(map[string]interface{})
+		}
+		s.Params[name] = value
+	})
+}
+
+func (r *RuleConfig) update(rule string, f func(*RuleSetting)) *RuleConfig {
+	if r.Rules == nil {
+		r.Rules = make(map[string]RuleSetting)
+	}
+	s := r.Rules[rule]
+	f(&s)
+	r.Rules[rule] = s
+	return r
+}
+
+// Merge returns a configuration combining r with override. Fields set in
+// override take precedence per rule. Either may be nil.
+func (r *RuleConfig) Merge(override *RuleConfig) *RuleConfig {
+	merged := &RuleConfig{Rules: make(map[string]RuleSetting)}
+	for _, cfg := range []*RuleConfig{r, override} {
+		if cfg == nil {
+			continue
+		}
+		for id, s := range cfg.Rules {
+			m := merged.Rules[id]
+			if s.Enabled != nil {
+				m.Enabled = s.Enabled
+			}
+			if s.Severity != "" {
+				m.Severity = s.Severity
+			}
+			for k, v := range s.Params {
+				if m.Params == nil {
+					m.Params = make(map[string]interface{})
+				}
+				m.Params[k] = v
+			}
+			merged.Rules[id] = m
+		}
+	}
+	return merged
+}
+
+// Apply enforces the configuration on findings locally: findings of
+// disabled rules are dropped and severity overrides are applied. Use it
+// when the server does not honor rule configuration.
+func (r *RuleConfig) Apply(findings []CodeFinding) []CodeFinding {
+	if r == nil {
+		return findings
+	}
+
+	out := findings[:0:0]
+	for _, f := range findings {
+		s, ok := r.Rules[f.Type]
+		if ok && s.Enabled != nil && !*s.Enabled {
+			continue
+		}
+		if ok && s.Severity != "" {
+			f.Severity = s.Severity
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+// WithRuleConfig sets the default rule configuration sent with VerifyCode
+// and VerifySQL requests. Per-call RequestOptions.Rules are merged on top.
+func WithRuleConfig(cfg *RuleConfig) ClientOption {
+	return func(c *Client) {
+		c.rules = cfg
+	}
+}
+
+// withRules returns opts with the client's default rules merged in, or opts
+// unchanged if there is nothing to merge.
+func (c *Client) withRules(opts *RequestOptions) *RequestOptions {
+	rules := c.defaultRules()
+	if rules == nil {
+		return opts
+	}
+	merged := RequestOptions{}
+	if opts != nil {
+		merged = *opts
+	}
+	merged.Rules = rules.Merge(merged.Rules)
+	return &merged
+}
+
+// defaultRules layers the client's rules over its policy's rules, over the
+// loaded rule pack's rules.
+func (c *Client) defaultRules() *RuleConfig {
+	var layers []*RuleConfig
+	if pack := c.rulePack(); pack != nil && pack.Rules != nil {
+		layers = append(layers, pack.Rules)
+	}
+	if policy := c.currentPolicy(); policy != nil && policy.Rules != nil {
+		layers = append(layers, policy.Rules)
+	}
+	if len(layers) == 0 {
+		return c.rules
+	}
+	rules := layers[0]
+	for _, layer := range append(layers[1:], c.rules) {
+		rules = rules.Merge(layer)
+	}
+	return rules
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,75 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestRuleConfigSentWithRequests(t *testing.T) {
+	var got map[string]interface{}
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	})
+	defer server.Close()
+
+	defaults := (&RuleConfig{}).Disable("os_system").SetSeverity("weak_hash", "info")
+	client := NewClient("test-key", WithBaseURL(server.URL), WithRuleConfig(defaults))
+
+	perCall := (&RuleConfig{}).Enable("os_system").SetParam("max_joins", "limit", 4)
+	if _, err := client.VerifySQLWithOptions(context.Background(), "SELECT 1", "", "postgresql",
+		&RequestOptions{Rules: perCall}); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := got["options"].(map[string]interface{})["rule_config"].(map[string]interface{})["rules"].(map[string]interface{})
+	if rules["os_system"].(map[string]interface{})["enabled"] != true {
+		t.Errorf("expected per-call enable to override default, got %v", rules["os_system"])
+	}
+	if rules["weak_hash"].(map[string]interface{})["severity"] != "INFO" {
+		t.Errorf("expected default severity override, got %v", rules["weak_hash"])
+	}
+	if rules["max_joins"].(map[string]interface{})["params"].(map[string]interface{})["limit"] != float64(4) {
+		t.Errorf("expected params to be sent, got %v", rules["max_joins"])
+	}
+}
+
+func TestRuleConfigApply(t *testing.T) {
+	cfg := (&RuleConfig{}).Disable("os_system").SetSeverity("eval_usage", SeverityWarning)
+	findings := cfg.Apply([]CodeFinding{
+		{Type: "os_system", Severity: SeverityCritical},
+		{Type: "eval_usage", Severity: SeverityCritical},
+		{Type: "weak_hash", Severity: SeverityInfo},
+	})
+
+	if len(findings) != 2 {
+		t.Fatalf("expected disabled rule to be dropped, got %+v", findings)
+	}
+	if findings[0].Severity != SeverityWarning {
+		t.Errorf("expected severity override, got %s", findings[0].Severity)
+	}
+}
+
+func TestRuleConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	cfg := (&RuleConfig{}).Disable("os_system")
+	if err := cfg.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadRuleConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := loaded.Rules["os_system"]; s.Enabled == nil || *s.Enabled {
+		t.Errorf("unexpected loaded setting: %+v", s)
+	}
+
+	bad := (&RuleConfig{}).SetSeverity("x", "fatal")
+	if err := bad.Validate(); err == nil {
+		t.Error("expected unknown severity to fail validation")
+	}
+}
@@ -0,0 +1,266 @@
+// Package sarif converts QWED code verification results to SARIF 2.1.0, the
+// format accepted by GitHub code scanning and most security dashboards.
+//
+// Example usage:
+//
+//	resp, err := client.VerifyCode(ctx, code, "python")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	report, err := sarif.FromFileResponse("app/handlers.py", resp)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	report.Write(os.Stdout)
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// Version and Schema identify the SARIF dialect produced by this package.
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// ToolName is the driver name recorded in generated logs.
+const ToolName = "QWED"
+
+// Log is a SARIF log file.
+type Log struct {
+	Version string `json:"version"`
+	Schema  string `json:"$schema,omitempty"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run is a single invocation of an analysis tool.
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the analysis tool.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver is the tool component that produced the results.
+type Driver struct {
+	Name           string `json:"name"`
+	InformationURI string `json:"informationUri,omitempty"`
+	Rules          []Rule `json:"rules,omitempty"`
+}
+
+// Rule describes a reporting rule.
+type Rule struct {
+	ID               string   `json:"id"`
+	ShortDescription *Message `json:"shortDescription,omitempty"`
+	Help             *Message `json:"help,omitempty"`
+}
+
+// Result is a single finding.
+type Result struct {
+	RuleID       string        `json:"ruleId"`
+	Level        string        `json:"level"`
+	Message      Message       `json:"message"`
+	Locations    []Location    `json:"locations,omitempty"`
+	Suppressions []Suppression `json:"suppressions,omitempty"`
+}
+
+// Message is a SARIF message string.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Location points at a region of an artifact.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is a file and optional region.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+// ArtifactLocation identifies a file by URI.
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region identifies lines within an artifact.
+type Region struct {
+	StartLine int `json:"startLine"`
+}
+
+// Suppression records that a result was intentionally suppressed.
+type Suppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// ============================================================================
+// Conversion
+// ============================================================================
+
+// FromResponse converts a VerifyCode response to a SARIF log without file
+// locations. If the API already returned SARIF (see qwed.OutputSARIF), that
+// log is used as-is.
+func FromResponse(resp *qwed.VerificationResponse) (*Log, error) {
+	return FromFileResponse("", resp)
+}
+
+// FromFileResponse converts a VerifyCode response for the given file to a
+// SARIF log. file should be relative to the repository root for GitHub code
+// scanning.
+func FromFileResponse(file string, resp *qwed.VerificationResponse) (*Log, error) {
+	if resp == nil {
+		return nil, fmt.Errorf("sarif: nil response")
+	}
+
+	if raw, ok := resp.Result["sarif"]; ok {
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return nil, fmt.Errorf("sarif: failed to re-encode server log: %w", err)
+		}
+		var log Log
+		if err := json.Unmarshal(data, &log); err != nil {
+			return nil, fmt.Errorf("sarif: invalid server log: %w", err)
+		}
+		if file != "" {
+			log.setMissingURIs(file)
+		}
+		return &log, nil
+	}
+
+	return FromFindings(file, qwed.CodeFindings(resp)), nil
+}
+
+// FromFindings builds a SARIF log from code findings in file.
+func FromFindings(file string, findings []qwed.CodeFinding) *Log {
+	run := Run{
+		Tool:    Tool{Driver: Driver{Name: ToolName, InformationURI: "https://github.com/QWED-AI/qwed-verification"}},
+		Results: []Result{},
+	}
+
+	rules := make(map[string]Rule)
+	for _, f := range findings {
+		ruleID := f.Type
+		if ruleID == "" {
+			ruleID = "unknown"
+		}
+		if _, ok := rules[ruleID]; !ok {
+			rule := Rule{ID: ruleID}
+			if f.Description != "" {
+				rule.ShortDescription = &Message{Text: f.Description}
+			}
+			if f.Recommendation != "" {
+				rule.Help = &Message{Text: f.Recommendation}
+			}
+			rules[ruleID] = rule
+		}
+
+		text := f.Description
+		if text == "" {
+			text = ruleID
+		}
+		result := Result{
+			RuleID:  ruleID,
+			Level:   Level(f.Severity),
+			Message: Message{Text: text},
+		}
+		if file != "" {
+			loc := PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: file}}
+			if f.Line > 0 {
+				loc.Region = &Region{StartLine: f.Line}
+			}
+			result.Locations = []Location{{PhysicalLocation: loc}}
+		}
+		if f.Suppressed {
+			result.Suppressions = []Suppression{{Kind: "inSource", Justification: f.SuppressionReason}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rules[id])
+	}
+
+	return &Log{Version: Version, Schema: Schema, Runs: []Run{run}}
+}
+
+// Level maps a QWED severity to a SARIF result level.
+func Level(severity string) string {
+	switch severity {
+	case qwed.SeverityCritical:
+		return "error"
+	case qwed.SeverityWarning:
+		return "warning"
+	case qwed.SeverityInfo:
+		return "note"
+	}
+	return "warning"
+}
+
+// Merge appends the results of other to l, combining rule definitions, so a
+// single log can cover several files.
+func (l *Log) Merge(other *Log) {
+	if len(l.Runs) == 0 {
+		l.Runs = append(l.Runs, other.Runs...)
+		return
+	}
+
+	run := &l.Runs[0]
+	known := make(map[string]bool)
+	for _, r := range run.Tool.Driver.Rules {
+		known[r.ID] = true
+	}
+	for _, o := range other.Runs {
+		for _, r := range o.Tool.Driver.Rules {
+			if !known[r.ID] {
+				known[r.ID] = true
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, r)
+			}
+		}
+		run.Results = append(run.Results, o.Results...)
+	}
+}
+
+// Write encodes the log as indented JSON.
+func (l *Log) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(l)
+}
+
+func (l *Log) setMissingURIs(file string) {
+	for i := range l.Runs {
+		for j := range l.Runs[i].Results {
+			r := &l.Runs[i].Results[j]
+			if len(r.Locations) == 0 {
+				r.Locations = []Location{{PhysicalLocation: PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: file}}}}
+				continue
+			}
+			for k := range r.Locations {
+				if r.Locations[k].PhysicalLocation.ArtifactLocation.URI == "" {
+					r.Locations[k].PhysicalLocation.ArtifactLocation.URI = file
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package sarif
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+func TestFromFileResponse(t *testing.T) {
+	resp := &qwed.VerificationResponse{Result: map[string]interface{}{
+		"issues": []interface{}{
+			map[string]interface{}{
+				"severity": "CRITICAL", "type": "eval_usage", "line_number": float64(3),
+				"description": "Use of eval()", "recommendation": "Use ast.literal_eval",
+			},
+			map[string]interface{}{
+				"severity": "WARNING", "type": "weak_hash", "line_number": float64(7),
+				"suppressed": true, "suppression_reason": "checksum only",
+			},
+		},
+	}}
+
+	log, err := FromFileResponse("app/main.py", resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if log.Version != Version || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log: %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].ID != "eval_usage" {
+		t.Errorf("unexpected rules: %+v", run.Tool.Driver.Rules)
+	}
+
+	first := run.Results[0]
+	if first.Level != "error" || first.Locations[0].PhysicalLocation.Region.StartLine != 3 {
+		t.Errorf("unexpected first result: %+v", first)
+	}
+	if first.Locations[0].PhysicalLocation.ArtifactLocation.URI != "app/main.py" {
+		t.Errorf("unexpected uri: %+v", first.Locations)
+	}
+
+	second := run.Results[1]
+	if second.Level != "warning" || len(second.Suppressions) != 1 || second.Suppressions[0].Justification != "checksum only" {
+		t.Errorf("unexpected suppressed result: %+v", second)
+	}
+
+	var buf bytes.Buffer
+	if err := log.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded["version"] != "2.1.0" {
+		t.Errorf("expected valid SARIF JSON, got %v", err)
+	}
+}
+
+func TestFromResponseUsesServerSARIF(t *testing.T) {
+	resp := &qwed.VerificationResponse{Result: map[string]interface{}{
+		"sarif": map[string]interface{}{
+			"version": "2.1.0",
+			"runs": []interface{}{map[string]interface{}{
+				"tool":    map[string]interface{}{"driver": map[string]interface{}{"name": "QWED"}},
+				"results": []interface{}{map[string]interface{}{"ruleId": "x", "level": "error", "message": map[string]interface{}{"text": "x"}}},
+			}},
+		},
+	}}
+
+	log, err := FromFileResponse("a.py", resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := log.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI; got != "a.py" {
+		t.Errorf("expected missing uri to be filled, got %q", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := FromFindings("a.py", []qwed.CodeFinding{{Type: "eval_usage", Severity: "CRITICAL"}})
+	b := FromFindings("b.py", []qwed.CodeFinding{{Type: "eval_usage"}, {Type: "os_system"}})
+
+	a.Merge(b)
+	run := a.Runs[0]
+	if len(run.Results) != 3 || len(run.Tool.Driver.Rules) != 2 {
+		t.Errorf("unexpected merged run: %d results, %d rules", len(run.Results), len(run.Tool.Driver.Rules))
+	}
+}
@@ -0,0 +1,283 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Scheduled Re-verification
+// ============================================================================
+
+// ScheduledClaim is a claim that is re-verified periodically.
+type ScheduledClaim struct {
+	ID      string
+	Type    VerificationType // math, logic, fact or natural_language (default)
+	Query   string
+	Context string // fact context; used when Type is TypeFact
+}
+
+// VerdictChange reports a claim whose verdict differs from its previous run.
+type VerdictChange struct {
+	Claim    ScheduledClaim
+	Previous *VerificationResponse
+	Current  *VerificationResponse
+	At       time.Time
+}
+
+// SchedulerOptions configures a Scheduler.
+type SchedulerOptions struct {
+	// OnChange is called when a claim's verdict flips between runs.
+	OnChange func(VerdictChange)
+	// OnError is called when re-verifying a claim fails.
+	OnError func(claim ScheduledClaim, err error)
+
+	// Store persists each claim's latest response, so verdict changes are
+	// detected across process restarts. Responses are stored under
+	// "scheduler/<claim ID>".
+	Store Store
+}
+
+// Scheduler re-verifies registered claims on an interval and reports verdict
+// changes, which is useful for monitoring knowledge-base drift.
+type Scheduler struct {
+	v    Verifier
+	opts SchedulerOptions
+
+	mu   sync.Mutex
+	jobs map[string]*scheduledJob
+	wake chan struct{}
+}
+
+type scheduledJob struct {
+	claim    ScheduledClaim
+	every    time.Duration
+	next     time.Time
+	running  bool
+	previous *VerificationResponse
+}
+
+// NewScheduler creates a scheduler that verifies claims through v.
+func NewScheduler(v Verifier, opts SchedulerOptions) *Scheduler {
+	return &Scheduler{
+		v:    v,
+		opts: opts,
+		jobs: make(map[string]*scheduledJob),
+		wake: make(chan struct{}, 1),
+	}
+}
+
+// Add registers claim to be re-verified according to spec, replacing any
+// claim with the same ID. The first verification runs immediately once the
+// scheduler is started. See ParseSchedule for the accepted specs.
+func (s *Scheduler) Add(claim ScheduledClaim, spec string) error {
+	every, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+	if claim.ID == "" {
+		return fmt.Errorf("scheduled claim requires an ID")
+	}
+
+	s.mu.Lock()
+	s.jobs[claim.ID] = &scheduledJob{claim: claim, every: every}
+	s.mu.Unlock()
+
+	s.poke()
+	return nil
+}
+
+// Remove unregisters the claim with the given ID.
+func (s *Scheduler) Remove(id string) {
+	s.mu.Lock()
+	delete(s.jobs, id)
+	s.mu.Unlock()
+}
+
+// Last returns the most recent response recorded for the claim.
+func (s *Scheduler) Last(id string) (*VerificationResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[id]
+	if !ok || job.previous == nil {
+		return nil, false
+	}
+	return job.previous, true
+}
+
+// Run executes due verifications until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		case <-s.wake:
+		}
+
+		wait := s.dispatchDue(ctx, time.Now())
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(wait)
+	}
+}
+
+// dispatchDue starts every job due at now and returns how long to wait
+// until the next one.
+func (s *Scheduler) dispatchDue(ctx context.Context, now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wait := time.Hour
+	for _, job := range s.jobs {
+		if !job.running && !job.next.After(now) {
+			job.running = true
+			job.next = now.Add(job.every)
+			go s.check(ctx, job)
+		}
+		if d := job.next.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+func (s *Scheduler) check(ctx context.Context, job *scheduledJob) {
+	resp, err := verifyClaim(ctx, s.v, job.claim)
+
+	s.mu.Lock()
+	job.running = false
+	previous := job.previous
+	if err == nil {
+		job.previous = resp
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		s.reportError(job.claim, err)
+		return
+	}
+
+	if s.opts.Store != nil {
+		if previous == nil {
+			previous = s.restore(ctx, job.claim)
+		}
+		s.persist(ctx, job.claim, resp)
+	}
+
+	if previous != nil && DiffResponses(previous, resp).VerdictChanged && s.opts.OnChange != nil {
+		s.opts.OnChange(VerdictChange{
+			Claim:    job.claim,
+			Previous: previous,
+			Current:  resp,
+			At:       time.Now(),
+		})
+	}
+}
+
+// restore loads the response stored for claim by a previous process.
+func (s *Scheduler) restore(ctx context.Context, claim ScheduledClaim) *VerificationResponse {
+	data, err := s.opts.Store.Get(ctx, schedulerKey(claim.ID))
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	var resp VerificationResponse
+	if err == nil {
+		err = json.Unmarshal(data, &resp)
+	}
+	if err != nil {
+		s.reportError(claim, fmt.Errorf("failed to restore previous verdict: %w", err))
+		return nil
+	}
+	return &resp
+}
+
+// persist stores resp as the latest response for claim.
+func (s *Scheduler) persist(ctx context.Context, claim ScheduledClaim, resp *VerificationResponse) {
+	data, err := json.Marshal(resp)
+	if err == nil {
+		err = s.opts.Store.Put(ctx, schedulerKey(claim.ID), data)
+	}
+	if err != nil {
+		s.reportError(claim, fmt.Errorf("failed to persist verdict: %w", err))
+	}
+}
+
+func (s *Scheduler) reportError(claim ScheduledClaim, err error) {
+	if s.opts.OnError != nil {
+		s.opts.OnError(claim, err)
+	}
+}
+
+func schedulerKey(id string) string {
+	return "scheduler/" + url.PathEscape(id)
+}
+
+func (s *Scheduler) poke() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// verifyClaim verifies a claim with the engine matching its type. The
+// response cache is bypassed, since a cached verdict cannot show drift.
+func verifyClaim(ctx context.Context, v Verifier, claim ScheduledClaim) (*VerificationResponse, error) {
+	switch claim.Type {
+	case TypeMath:
+		return v.VerifyMath(ctx, claim.Query, WithNoCache())
+	case TypeLogic:
+		return v.VerifyLogic(ctx, claim.Query, WithNoCache())
+	case TypeFact:
+		return v.VerifyFact(ctx, claim.Query, claim.Context, WithNoCache())
+	case TypeNaturalLanguage, "":
+		return v.Verify(ctx, claim.Query, WithNoCache())
+	default:
+		return nil, fmt.Errorf("unsupported claim type %q", claim.Type)
+	}
+}
+
+// ParseSchedule parses a cron-like interval spec: "@hourly", "@daily",
+// "@weekly", "@every <duration>" or a bare Go duration such as "15m".
+func ParseSchedule(spec string) (time.Duration, error) {
+	spec = strings.TrimSpace(spec)
+
+	var every time.Duration
+	switch spec {
+	case "@hourly":
+		every = time.Hour
+	case "@daily", "@midnight":
+		every = 24 * time.Hour
+	case "@weekly":
+		every = 7 * 24 * time.Hour
+	default:
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every")))
+		if err != nil {
+			return 0, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		every = d
+	}
+
+	if every <= 0 {
+		return 0, fmt.Errorf("invalid schedule %q: interval must be positive", spec)
+	}
+	return every, nil
+}
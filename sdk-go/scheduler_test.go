@@ -0,0 +1,140 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSchedulerDetectsVerdictChange(t *testing.T) {
+	var runs int32
+	mock := &MockClient{
+		VerifyMathFunc: func(ctx context.Context, expr string) (*VerificationResponse, error) {
+			n := atomic.AddInt32(&runs, 1)
+			if n >= 3 {
+				return &VerificationResponse{Status: StatusFailed, Verified: false}, nil
+			}
+			return &VerificationResponse{Status: StatusVerified, Verified: true}, nil
+		},
+	}
+
+	changes := make(chan VerdictChange, 1)
+	sched := NewScheduler(mock, SchedulerOptions{
+		OnChange: func(c VerdictChange) { changes <- c },
+	})
+	if err := sched.Add(ScheduledClaim{ID: "rate", Type: TypeMath, Query: "0.05 * 100 = 5"}, "@every 10ms"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	go sched.Run(ctx)
+
+	select {
+	case change := <-changes:
+		if change.Claim.ID != "rate" || !change.Previous.Verified || change.Current.Verified {
+			t.Errorf("unexpected change: %+v", change)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for verdict change")
+	}
+
+	if last, ok := sched.Last("rate"); !ok || last.Verified {
+		t.Errorf("expected last response to be the failed verdict, got %+v", last)
+	}
+}
+
+func TestSchedulerStoreSurvivesRestart(t *testing.T) {
+	store := NewMemoryStore()
+	verified := true
+	mock := &MockClient{
+		VerifyMathFunc: func(ctx context.Context, expr string) (*VerificationResponse, error) {
+			return &VerificationResponse{Status: StatusVerified, Verified: verified}, nil
+		},
+	}
+	claim := ScheduledClaim{ID: "rate/usd", Type: TypeMath, Query: "0.05 * 100 = 5"}
+
+	// First process: records the verified verdict.
+	first := NewScheduler(mock, SchedulerOptions{Store: store})
+	first.check(context.Background(), &scheduledJob{claim: claim})
+	if _, err := store.Get(context.Background(), "scheduler/rate%2Fusd"); err != nil {
+		t.Fatalf("expected the verdict to be stored, got %v", err)
+	}
+
+	// Second process: its first run compares against the stored verdict.
+	verified = false
+	var changes []VerdictChange
+	second := NewScheduler(mock, SchedulerOptions{
+		Store:    store,
+		OnChange: func(c VerdictChange) { changes = append(changes, c) },
+		OnError:  func(_ ScheduledClaim, err error) { t.Error(err) },
+	})
+	second.check(context.Background(), &scheduledJob{claim: claim})
+	if len(changes) != 1 || !changes[0].Previous.Verified || changes[0].Current.Verified {
+		t.Errorf("expected one change from the stored verdict, got %+v", changes)
+	}
+}
+
+func TestSchedulerBypassesCache(t *testing.T) {
+	var verified atomic.Bool
+	verified.Store(true)
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		status := StatusFailed
+		if verified.Load() {
+			status = StatusVerified
+		}
+		json.NewEncoder(w).Encode(VerificationResponse{Status: status, Verified: verified.Load()})
+	})
+	defer server.Close()
+
+	cache := NewLRUCache(10)
+	client := NewClient("test-key", WithBaseURL(server.URL), WithCache(cache, time.Hour))
+	var changes []VerdictChange
+	sched := NewScheduler(client, SchedulerOptions{
+		OnChange: func(c VerdictChange) { changes = append(changes, c) },
+		OnError:  func(_ ScheduledClaim, err error) { t.Error(err) },
+	})
+	job := &scheduledJob{claim: ScheduledClaim{ID: "rate", Type: TypeMath, Query: "0.05 * 100 = 5"}}
+
+	sched.check(context.Background(), job)
+	verified.Store(false)
+	sched.check(context.Background(), job)
+	if len(changes) != 1 || !changes[0].Previous.Verified || changes[0].Current.Verified {
+		t.Errorf("expected the flipped verdict to be detected despite the cache, got %+v", changes)
+	}
+
+	// The fresh verdict replaces the cached one for other callers.
+	if resp, err := client.VerifyMath(context.Background(), "0.05 * 100 = 5"); err != nil || resp.Verified {
+		t.Errorf("expected the cache to hold the fresh verdict, got %+v, %v", resp, err)
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"@hourly", time.Hour, false},
+		{"@daily", 24 * time.Hour, false},
+		{"@every 90s", 90 * time.Second, false},
+		{"15m", 15 * time.Minute, false},
+		{"@every -1m", 0, true},
+		{"*/5 * * * *", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseSchedule(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error state: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package qwed
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ============================================================================
+// Response Schema Versioning
+// ============================================================================
+
+// CurrentSchemaVersion is the newest response schema this SDK understands.
+//
+// Version 1 is every response without a schema_version field. Version 2
+// guarantees a Status on every response and result.
+//
+// Schema changes within a version are additive: fields this SDK does not
+// know are kept in Raw rather than dropped, and are written back when a
+// response is encoded, so caches, dumps and proxies built on an older SDK
+// pass them through unchanged.
+const CurrentSchemaVersion = 2
+
+// UnmarshalJSON decodes a response of any schema version, keeping unknown
+// fields in Raw and migrating older versions to CurrentSchemaVersion.
+func (r *VerificationResponse) UnmarshalJSON(data []byte) error {
+	type plain VerificationResponse
+	var v plain
+	raw, err := decodeVersioned(data, &v)
+	if err != nil {
+		return err
+	}
+	*r = VerificationResponse(v)
+	r.Raw = keepUnknownNames(raw, data, r.Status, r.Engine)
+	MigrateResponse(r)
+	return nil
+}
+
+// MarshalJSON encodes the response with its Raw fields. Responses built in
+// code are written with CurrentSchemaVersion.
+func (r VerificationResponse) MarshalJSON() ([]byte, error) {
+	type plain VerificationResponse
+	if r.SchemaVersion == 0 {
+		r.SchemaVersion = CurrentSchemaVersion
+	}
+	return encodeVersioned(plain(r), r.Raw, unknownNames(r.Status, r.Engine)...)
+}
+
+// UnmarshalJSON decodes a batch response of any schema version, keeping
+// unknown fields in Raw.
+func (r *BatchResponse) UnmarshalJSON(data []byte) error {
+	type plain BatchResponse
+	var v plain
+	raw, err := decodeVersioned(data, &v)
+	if err != nil {
+		return err
+	}
+	*r = BatchResponse(v)
+	r.Raw = raw
+	if r.SchemaVersion < CurrentSchemaVersion {
+		r.SchemaVersion = CurrentSchemaVersion
+	}
+	return nil
+}
+
+// MarshalJSON encodes the batch response with its Raw fields.
+func (r BatchResponse) MarshalJSON() ([]byte, error) {
+	type plain BatchResponse
+	if r.SchemaVersion == 0 {
+		r.SchemaVersion = CurrentSchemaVersion
+	}
+	return encodeVersioned(plain(r), r.Raw)
+}
+
+// UnmarshalJSON decodes a batch result of any schema version, keeping
+// unknown fields in Raw and migrating older versions.
+func (r *BatchResult) UnmarshalJSON(data []byte) error {
+	type plain BatchResult
+	var v plain
+	raw, err := decodeVersioned(data, &v)
+	if err != nil {
+		return err
+	}
+	*r = BatchResult(v)
+	r.Raw = keepUnknownNames(raw, data, r.Status, "")
+	if r.SchemaVersion < 2 {
+		r.Status = statusFromVerified(r.Status, r.Verified, r.Error)
+	}
+	if r.SchemaVersion < CurrentSchemaVersion {
+		r.SchemaVersion = CurrentSchemaVersion
+	}
+	return nil
+}
+
+// MarshalJSON encodes the batch result with its Raw fields.
+func (r BatchResult) MarshalJSON() ([]byte, error) {
+	type plain BatchResult
+	if r.SchemaVersion == 0 {
+		r.SchemaVersion = CurrentSchemaVersion
+	}
+	return encodeVersioned(plain(r), r.Raw, unknownNames(r.Status, "")...)
+}
+
+// MigrateResponse upgrades resp in place from an older schema version to
+// CurrentSchemaVersion. Decoding JSON does this automatically; call it for
+// responses built from data stored by older SDKs by other means. Responses
+// from a newer schema are left unchanged; see NewerSchema.
+func MigrateResponse(resp *VerificationResponse) {
+	if resp == nil || resp.SchemaVersion >= CurrentSchemaVersion {
+		return
+	}
+	if resp.SchemaVersion < 2 {
+		resp.Status = statusFromVerified(resp.Status, resp.Verified, resp.Error)
+	}
+	resp.SchemaVersion = CurrentSchemaVersion
+}
+
+// UpgradeResponseJSON rewrites a stored response, such as a cache entry or
+// request dump written by an older SDK, in the current schema. Unknown
+// fields are preserved.
+func UpgradeResponseJSON(data []byte) ([]byte, error) {
+	var resp VerificationResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, err
+	}
+	return json.Marshal(resp)
+}
+
+// NewerSchema reports whether the response was produced with a schema newer
+// than this SDK understands. Its known fields keep their meaning, but Raw
+// may hold new fields worth upgrading the SDK for.
+func (r *VerificationResponse) NewerSchema() bool {
+	return r != nil && r.SchemaVersion > CurrentSchemaVersion
+}
+
+// RawField decodes the unknown field name into v, for fields a server sends
+// before this SDK declares them. When the status or engine decoded as
+// StatusUnknown or UnknownEngine, "status" and "engine" hold the names the
+// server sent. It reports whether the field was present and decoded.
+func (r *VerificationResponse) RawField(name string, v interface{}) bool {
+	if r == nil {
+		return false
+	}
+	data, ok := r.Raw[name]
+	return ok && json.Unmarshal(data, v) == nil
+}
+
+// statusFromVerified fills in the status of version 1 responses, which
+// could omit it.
+func statusFromVerified(status Status, verified bool, errInfo *ErrorInfo) Status {
+	switch {
+	case status != "":
+		return status
+	case errInfo != nil:
+		return StatusError
+	case verified:
+		return StatusVerified
+	}
+	return StatusFailed
+}
+
+// decodeVersioned unmarshals data into v, a pointer to a struct, and
+// returns the top-level fields v does not declare.
+func decodeVersioned(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	for name := range fields {
+		if known[strings.ToLower(name)] {
+			delete(fields, name)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// encodeVersioned marshals v and adds the raw fields it does not declare.
+// The raw values of the fields named in overrides replace those of v.
+func encodeVersioned(v interface{}, raw map[string]json.RawMessage, overrides ...string) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(raw) == 0 {
+		return data, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name, value := range raw {
+		if _, ok := fields[name]; !ok {
+			fields[name] = value
+		}
+	}
+	for _, name := range overrides {
+		if value, ok := raw[name]; ok {
+			fields[name] = value
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// keepUnknownNames adds the "status" and "engine" fields of data to raw
+// when they decoded as StatusUnknown or UnknownEngine, so the names sent by
+// the server stay readable with RawField and are written back on encoding.
+func keepUnknownNames(raw map[string]json.RawMessage, data []byte, status Status, engine Engine) map[string]json.RawMessage {
+	names := unknownNames(status, engine)
+	if len(names) == 0 {
+		return raw
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return raw
+	}
+	for key, value := range fields {
+		for _, name := range names {
+			if strings.EqualFold(key, name) {
+				if raw == nil {
+					raw = make(map[string]json.RawMessage)
+				}
+				raw[name] = value
+			}
+		}
+	}
+	return raw
+}
+
+// unknownNames returns the names of the fields holding StatusUnknown or
+// UnknownEngine.
+func unknownNames(status Status, engine Engine) []string {
+	var names []string
+	if status == StatusUnknown {
+		names = append(names, "status")
+	}
+	if engine == UnknownEngine {
+		names = append(names, "engine")
+	}
+	return names
+}
+
+var fieldNameCache sync.Map // reflect.Type -> map[string]bool
+
+// jsonFieldNames returns the lowercased JSON names of t's fields, matching
+// encoding/json's case-insensitive decoding.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	if names, ok := fieldNameCache.Load(t); ok {
+		return names.(map[string]bool)
+	}
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	fieldNameCache.Store(t, names)
+	return names
+}
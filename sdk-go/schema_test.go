@@ -0,0 +1,110 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResponseUnknownFieldsRoundTrip(t *testing.T) {
+	data := []byte(`{"status":"VERIFIED","verified":true,"engine":"math","schema_version":3,"proof_steps":["a","b"],"cost":{"credits":2}}`)
+
+	var resp VerificationResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Verified || resp.Engine != "math" || resp.SchemaVersion != 3 {
+		t.Errorf("unexpected known fields: %+v", resp)
+	}
+	if len(resp.Raw) != 2 {
+		t.Fatalf("expected 2 raw fields, got %v", resp.Raw)
+	}
+	if !resp.NewerSchema() {
+		t.Error("expected schema 3 to be newer than this SDK")
+	}
+
+	var steps []string
+	if !resp.RawField("proof_steps", &steps) || len(steps) != 2 {
+		t.Errorf("expected proof_steps to decode, got %v", steps)
+	}
+	if resp.RawField("missing", &steps) {
+		t.Error("expected a missing field to report false")
+	}
+
+	out, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields map[string]interface{}
+	json.Unmarshal(out, &fields)
+	if fields["cost"] == nil || fields["proof_steps"] == nil || fields["schema_version"] != float64(3) {
+		t.Errorf("expected unknown fields and version to survive re-encoding, got %s", out)
+	}
+}
+
+func TestMigrateResponse(t *testing.T) {
+	tests := []struct {
+		json   string
+		status Status
+	}{
+		{`{"verified":true}`, StatusVerified},
+		{`{"verified":false}`, StatusFailed},
+		{`{"verified":false,"error":{"code":"X","message":"y"}}`, StatusError},
+		{`{"status":"CORRECTED","verified":false}`, StatusCorrected},
+	}
+	for _, tt := range tests {
+		var resp VerificationResponse
+		if err := json.Unmarshal([]byte(tt.json), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Status != tt.status || resp.SchemaVersion != CurrentSchemaVersion {
+			t.Errorf("%s: expected %s at version %d, got %s at %d", tt.json, tt.status, CurrentSchemaVersion, resp.Status, resp.SchemaVersion)
+		}
+		if resp.Raw != nil {
+			t.Errorf("%s: expected no raw fields, got %v", tt.json, resp.Raw)
+		}
+	}
+
+	resp := &VerificationResponse{Verified: true, SchemaVersion: 1}
+	MigrateResponse(resp)
+	if resp.Status != StatusVerified || resp.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("unexpected migrated response: %+v", resp)
+	}
+}
+
+func TestUpgradeResponseJSON(t *testing.T) {
+	out, err := UpgradeResponseJSON([]byte(`{"verified":true,"legacy_score":0.9}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"status":"VERIFIED"`, `"schema_version":2`, `"legacy_score":0.9`} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("expected %s in %s", want, out)
+		}
+	}
+	if _, err := UpgradeResponseJSON([]byte(`not json`)); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+func TestBatchResponseUnknownFields(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"job_id":"j1","status":"completed","region":"eu","items":[{"id":"1","verified":true,"trace":"abc"}]}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyBatch(context.Background(), []BatchItem{{Query: "2+2=4"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(resp.Raw["region"]) != `"eu"` {
+		t.Errorf("expected region in Raw, got %v", resp.Raw)
+	}
+	item := resp.Items[0]
+	if item.Status != StatusVerified || string(item.Raw["trace"]) != `"abc"` {
+		t.Errorf("unexpected item: %+v", item)
+	}
+}
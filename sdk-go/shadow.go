@@ -0,0 +1,136 @@
+package qwed
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Shadow Verification
+// ============================================================================
+
+// StatusShadow is the status of the immediate response returned in shadow
+// mode. It is never sent by the API.
+const StatusShadow Status = "SHADOW"
+
+// maxShadowInflight bounds background verifications in shadow mode; sampled
+// calls beyond it are dropped rather than queued.
+const maxShadowInflight = 100
+
+// ShadowResult is the outcome of a background verification in shadow mode.
+type ShadowResult struct {
+	Request  Request
+	Response *VerificationResponse
+	Err      error
+	Latency  time.Duration
+}
+
+// ShadowSink receives shadow results. It is called from a background
+// goroutine and must be safe for concurrent use.
+type ShadowSink func(ShadowResult)
+
+// ShadowStats counts shadow mode activity.
+type ShadowStats struct {
+	Calls   int64 // verification calls made in shadow mode
+	Sampled int64 // calls verified in the background
+	Dropped int64 // sampled calls skipped because too many were in flight
+}
+
+// WithShadowSampling puts the client in shadow mode for teams not yet ready
+// to gate on verification. Verification calls return immediately with a
+// passing StatusShadow response, and a fraction rate (0 to 1) of them is
+// verified in the background. Results go to the sinks and, like any other
+// call, to the tracer and metrics collector. Call FlushShadow before exiting
+// to wait for pending verifications.
+func WithShadowSampling(rate float64, sinks ...ShadowSink) ClientOption {
+	return func(c *Client) {
+		c.shadow = &shadowSampler{
+			rate:  rate,
+			sinks: sinks,
+			sem:   make(chan struct{}, maxShadowInflight),
+		}
+	}
+}
+
+// shadowSampler runs sampled verifications in the background.
+type shadowSampler struct {
+	rate  float64
+	sinks []ShadowSink
+	sem   chan struct{}
+	wg    sync.WaitGroup
+
+	mu    sync.Mutex
+	stats ShadowStats
+}
+
+// shadowVerify samples the call for background verification and returns
+// the immediate shadow response.
+func (c *Client) shadowVerify(ctx context.Context, req *Request) *VerificationResponse {
+	s := c.shadow
+	sampled := rand.Float64() < s.rate
+
+	s.mu.Lock()
+	s.stats.Calls++
+	if sampled {
+		select {
+		case s.sem <- struct{}{}:
+			s.stats.Sampled++
+		default:
+			s.stats.Dropped++
+			sampled = false
+		}
+	}
+	s.mu.Unlock()
+
+	if sampled {
+		// The caller's cancellation must not abort the background call, but
+		// trace context and other values are kept.
+		ctx := context.WithoutCancel(ctx)
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer func() { <-s.sem }()
+
+			start := time.Now()
+			resp, err := c.invoke(ctx, req)
+			result := ShadowResult{Request: *req, Response: resp, Err: err, Latency: time.Since(start)}
+			for _, sink := range s.sinks {
+				sink(result)
+			}
+		}()
+	}
+
+	return &VerificationResponse{Status: StatusShadow, Verified: true}
+}
+
+// FlushShadow waits for pending shadow verifications to finish, or until
+// ctx is done. It returns immediately if the client is not in shadow mode.
+func (c *Client) FlushShadow(ctx context.Context) error {
+	if c.shadow == nil {
+		return nil
+	}
+	done := make(chan struct{})
+	go func() {
+		c.shadow.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ShadowStats returns shadow mode counters, or zero values if the client is
+// not in shadow mode.
+func (c *Client) ShadowStats() ShadowStats {
+	if c.shadow == nil {
+		return ShadowStats{}
+	}
+	c.shadow.mu.Lock()
+	defer c.shadow.mu.Unlock()
+	return c.shadow.stats
+}
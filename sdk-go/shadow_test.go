@@ -0,0 +1,88 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShadowSampling(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		calls.Add(1)
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusFailed, Verified: false})
+	})
+	defer server.Close()
+
+	var mu sync.Mutex
+	var results []ShadowResult
+	sink := func(r ShadowResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		results = append(results, r)
+	}
+	metrics := NewPrometheusCollector()
+	client := NewClient("test-key", WithBaseURL(server.URL), WithShadowSampling(1, sink), WithMetrics(metrics))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	resp, err := client.VerifyMath(ctx, "2+2=5")
+	cancel()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Verified || resp.Status != StatusShadow {
+		t.Errorf("expected a passing shadow response, got %+v", resp)
+	}
+
+	// The caller's response does not wait for, or cancel, the real call.
+	close(release)
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := client.FlushShadow(flushCtx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 || calls.Load() != 1 {
+		t.Fatalf("expected one shadow result, got %d (%d calls)", len(results), calls.Load())
+	}
+	r := results[0]
+	if r.Err != nil || r.Response.Verified || r.Request.Op != "VerifyMath" || r.Request.Engine != TypeMath {
+		t.Errorf("unexpected shadow result: %+v", r)
+	}
+	if stats := client.ShadowStats(); stats.Calls != 1 || stats.Sampled != 1 || stats.Dropped != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if metrics.requests[[3]string{"VerifyMath", "math", "200"}] != 1 {
+		t.Error("expected the shadow verification to be recorded in metrics")
+	}
+}
+
+func TestShadowSamplingRate(t *testing.T) {
+	var calls atomic.Int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		json.NewEncoder(w).Encode(VerificationResponse{Verified: true})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithShadowSampling(0))
+	for i := 0; i < 10; i++ {
+		if resp, err := client.VerifyLogic(context.Background(), "p OR NOT p"); err != nil || resp.Status != StatusShadow {
+			t.Fatalf("unexpected response: %+v, %v", resp, err)
+		}
+	}
+	client.FlushShadow(context.Background())
+
+	if calls.Load() != 0 {
+		t.Errorf("expected no background calls at rate 0, got %d", calls.Load())
+	}
+	if stats := client.ShadowStats(); stats.Calls != 10 || stats.Sampled != 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+}
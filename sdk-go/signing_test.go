@@ -0,0 +1,66 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWithRequestSigning(t *testing.T) {
+	var attempts int
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		if err := VerifyRequestSignature(body, r.Header.Get(HeaderRequestSignature), "s3cret"); err != nil {
+			t.Errorf("attempt %d: unexpected signature error: %v", attempts, err)
+		}
+		if err := VerifyRequestSignature(append(body, ' '), r.Header.Get(HeaderRequestSignature), "s3cret"); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("expected a tampered body to be rejected, got %v", err)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithRequestSigning("s3cret"))
+	if _, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Health(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 2 {
+		t.Errorf("expected 2 requests, got %d", attempts)
+	}
+}
+
+func TestRequestNotSignedByDefault(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(HeaderRequestSignature) != "" {
+			t.Error("expected no signature without WithRequestSigning")
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	})
+	defer server.Close()
+
+	if _, err := NewClient("test-key", WithBaseURL(server.URL)).VerifyMath(context.Background(), "2+2=4"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyRequestSignatureRejectsStale(t *testing.T) {
+	body := []byte(`{"expression":"2+2=4"}`)
+	ts := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	header := fmt.Sprintf("t=%s,v1=%x", ts, bodySignature(body, ts, "s3cret"))
+	if err := VerifyRequestSignature(body, header, "s3cret"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected a stale signature to be rejected, got %v", err)
+	}
+	if err := VerifyRequestSignature(body, "", "s3cret"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected a missing signature to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,347 @@
+package qwed
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Simulation
+// ============================================================================
+
+// SimConfig configures a SimulatedClient. The zero value verifies every
+// claim instantly.
+type SimConfig struct {
+	// Seed selects the simulated outcomes. A call's outcome depends only on
+	// the seed, the method and its inputs, not on call order, so runs are
+	// reproducible even with concurrent calls.
+	Seed int64
+
+	// FailureRate is the fraction of claims refuted, and InconclusiveRate
+	// the fraction that time out in the engine.
+	FailureRate      float64
+	InconclusiveRate float64
+
+	// Evaluate gives math and logic claims their real verdicts using the
+	// embedded evaluators, falling back to the rates for inputs they cannot
+	// parse.
+	Evaluate bool
+
+	// ErrorRate is the fraction of calls that fail with one of Errors, by
+	// default a 503 engine failure or a 429 rate limit.
+	ErrorRate float64
+	Errors    []error
+
+	// Latency is added to every call, plus a uniformly distributed extra
+	// delay of up to Jitter. Calls return early if their context ends.
+	Latency time.Duration
+	Jitter  time.Duration
+
+	// Responses fixes the response for specific inputs: the query,
+	// expression, code or claim. They are returned as given.
+	Responses map[string]*VerificationResponse
+}
+
+// SimCall is a call made to a SimulatedClient.
+type SimCall struct {
+	Op     string
+	Engine VerificationType
+	Input  string
+}
+
+// SimulatedClient implements Verifier in-process, with deterministic
+// verdicts, latencies and errors, so tests and local development exercise
+// realistic response shapes without the network. Call options are accepted
+// and ignored. It is safe for concurrent use.
+type SimulatedClient struct {
+	cfg SimConfig
+
+	mu    sync.Mutex
+	calls []SimCall
+}
+
+// Ensure SimulatedClient implements Verifier
+var _ Verifier = (*SimulatedClient)(nil)
+
+// NewSimulatedClient creates a simulated client.
+func NewSimulatedClient(cfg SimConfig) *SimulatedClient {
+	if len(cfg.Errors) == 0 {
+		cfg.Errors = []error{
+			&QWEDError{Code: "ENGINE_UNAVAILABLE", Message: "simulated engine failure", StatusCode: http.StatusServiceUnavailable},
+			&QWEDError{Code: "RATE_LIMITED", Message: "simulated rate limit", StatusCode: http.StatusTooManyRequests},
+		}
+	}
+	return &SimulatedClient{cfg: cfg}
+}
+
+// Calls returns the calls made so far, in order.
+func (s *SimulatedClient) Calls() []SimCall {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]SimCall(nil), s.calls...)
+}
+
+// Health reports a healthy simulated API.
+func (s *SimulatedClient) Health(ctx context.Context) (map[string]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"status": "healthy", "version": "simulated", "simulated": true}, nil
+}
+
+// Verify simulates natural language verification.
+func (s *SimulatedClient) Verify(ctx context.Context, query string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return s.VerifyWithOptions(ctx, query, nil)
+}
+
+// VerifyWithOptions simulates natural language verification. Options do
+// not change the outcome.
+func (s *SimulatedClient) VerifyWithOptions(ctx context.Context, query string, opts *RequestOptions, callOpts ...CallOption) (*VerificationResponse, error) {
+	return s.simulate(ctx, "Verify", TypeNaturalLanguage, query)
+}
+
+// VerifyMath simulates math verification.
+func (s *SimulatedClient) VerifyMath(ctx context.Context, expression string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return s.simulate(ctx, "VerifyMath", TypeMath, expression)
+}
+
+// VerifyLogic simulates logic verification.
+func (s *SimulatedClient) VerifyLogic(ctx context.Context, query string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return s.simulate(ctx, "VerifyLogic", TypeLogic, query)
+}
+
+// VerifyCode simulates a code scan; refuted code carries findings.
+func (s *SimulatedClient) VerifyCode(ctx context.Context, code, language string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return s.simulate(ctx, "VerifyCode", TypeCode, code, language)
+}
+
+// VerifyFact simulates fact verification.
+func (s *SimulatedClient) VerifyFact(ctx context.Context, claim, factContext string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return s.simulate(ctx, "VerifyFact", TypeFact, claim, factContext)
+}
+
+// VerifySQL simulates SQL validation.
+func (s *SimulatedClient) VerifySQL(ctx context.Context, query, schemaDDL, dialect string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return s.simulate(ctx, "VerifySQL", TypeSQL, query, schemaDDL, dialect)
+}
+
+// VerifyBatch simulates a completed batch job. Each item gets the verdict
+// Verify, VerifyMath or VerifyLogic would give its query, and the job fails
+// as a whole with the configured error rate.
+func (s *SimulatedClient) VerifyBatch(ctx context.Context, items []BatchItem, opts *BatchOptions, callOpts ...CallOption) (*BatchResponse, error) {
+	var queries []string
+	for _, item := range items {
+		queries = append(queries, string(item.Type)+":"+item.Query)
+	}
+	rng, latency := s.begin("VerifyBatch", "batch", strings.Join(queries, "\x00"))
+	if err := s.wait(ctx, latency); err != nil {
+		return nil, err
+	}
+	if err := s.draw(rng); err != nil {
+		return nil, err
+	}
+
+	batch := &BatchResponse{
+		JobID:         "sim-" + hex.EncodeToString(simHash(s.cfg.Seed, "batch", queries...)[:8]),
+		Status:        "completed",
+		Summary:       &BatchSummary{Total: len(items)},
+		SchemaVersion: CurrentSchemaVersion,
+	}
+	for i, item := range items {
+		engine := item.Type
+		if engine == "" {
+			engine = TypeNaturalLanguage
+		}
+		itemRNG := rand.New(rand.NewSource(simSeed(s.cfg.Seed, string(engine), item.Query)))
+		itemRNG.Float64() // latency draw of a single call
+		itemRNG.Float64() // error draw of a single call
+		resp := s.respond(itemRNG, engine, item.Query)
+		batch.Items = append(batch.Items, BatchResult{
+			ID:       fmt.Sprint(i),
+			Status:   resp.Status,
+			Verified: resp.Verified,
+			Result:   resp.Result,
+
+			SchemaVersion: CurrentSchemaVersion,
+		})
+		if resp.Verified {
+			batch.Summary.Verified++
+		} else {
+			batch.Summary.Failed++
+		}
+	}
+	if len(items) > 0 {
+		batch.Summary.SuccessRate = float64(batch.Summary.Verified) / float64(len(items))
+	}
+	return batch, nil
+}
+
+// simulate records a call and returns its simulated outcome. input is the
+// claim; extra inputs, such as the fact context, also select the outcome.
+func (s *SimulatedClient) simulate(ctx context.Context, op string, engine VerificationType, input string, extra ...string) (*VerificationResponse, error) {
+	rng, latency := s.begin(op, engine, input, extra...)
+	if err := s.wait(ctx, latency); err != nil {
+		return nil, err
+	}
+	if resp, ok := s.cfg.Responses[input]; ok {
+		return resp, nil
+	}
+	if err := s.draw(rng); err != nil {
+		return nil, err
+	}
+
+	resp := s.respond(rng, engine, input)
+	resp.Metadata = &ResponseMetadata{
+		RequestID: "sim-" + hex.EncodeToString(simHash(s.cfg.Seed, string(engine), append([]string{input}, extra...)...)[:8]),
+		LatencyMs: float64(latency) / float64(time.Millisecond),
+	}
+	return resp, nil
+}
+
+// begin records the call and returns its random source and latency.
+func (s *SimulatedClient) begin(op string, engine VerificationType, input string, extra ...string) (*rand.Rand, time.Duration) {
+	s.mu.Lock()
+	s.calls = append(s.calls, SimCall{Op: op, Engine: engine, Input: input})
+	s.mu.Unlock()
+
+	rng := rand.New(rand.NewSource(simSeed(s.cfg.Seed, string(engine), append([]string{input}, extra...)...)))
+	latency := s.cfg.Latency + time.Duration(rng.Float64()*float64(s.cfg.Jitter))
+	return rng, latency
+}
+
+// wait sleeps for the simulated latency.
+func (s *SimulatedClient) wait(ctx context.Context, latency time.Duration) error {
+	if latency <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// draw returns a simulated error for the configured error rate.
+func (s *SimulatedClient) draw(rng *rand.Rand) error {
+	u := rng.Float64()
+	if u >= s.cfg.ErrorRate {
+		return nil
+	}
+	return s.cfg.Errors[int(u/s.cfg.ErrorRate*float64(len(s.cfg.Errors)))%len(s.cfg.Errors)]
+}
+
+// respond builds the simulated response for input.
+func (s *SimulatedClient) respond(rng *rand.Rand, engine VerificationType, input string) *VerificationResponse {
+	u := rng.Float64()
+
+	if s.cfg.Evaluate && (engine == TypeMath || engine == TypeLogic) {
+		evaluate := localVerifyMath
+		if engine == TypeLogic {
+			evaluate = localVerifyLogic
+		}
+		if resp, err := evaluate(input); err == nil {
+			delete(resp.Result, "offline")
+			resp.Engine = Engine(engine)
+			resp.SchemaVersion = CurrentSchemaVersion
+			return resp
+		}
+	}
+
+	resp := &VerificationResponse{Engine: Engine(engine), SchemaVersion: CurrentSchemaVersion}
+	switch {
+	case u < s.cfg.InconclusiveRate:
+		resp.Status = StatusTimeout
+		resp.Result = map[string]interface{}{"reason": "simulated engine timeout"}
+		return resp
+	case u < s.cfg.InconclusiveRate+s.cfg.FailureRate:
+		resp.Status = StatusFailed
+	default:
+		resp.Status = StatusVerified
+		resp.Verified = true
+	}
+
+	confidence := 0.9 + 0.1*rng.Float64()
+	switch engine {
+	case TypeMath:
+		resp.Result = map[string]interface{}{"expression": input, "confidence": 1.0}
+		if !resp.Verified {
+			resp.Result["reason"] = "the two sides of the equation are not equal"
+		}
+	case TypeLogic:
+		resp.Result = map[string]interface{}{"query": input, "result": "valid"}
+		if !resp.Verified {
+			resp.Result["result"] = "invalid"
+			resp.Result["counterexample"] = map[string]interface{}{"A": true, "B": false}
+		}
+	case TypeCode:
+		issues := []interface{}{}
+		if !resp.Verified {
+			lines := strings.Count(input, "\n") + 1
+			for n := 1 + rng.Intn(3); n > 0; n-- {
+				f := simFindings[rng.Intn(len(simFindings))]
+				f.Line = 1 + rng.Intn(lines)
+				issues = append(issues, map[string]interface{}{
+					"severity":       f.Severity,
+					"type":           f.Type,
+					"description":    f.Description,
+					"line_number":    f.Line,
+					"recommendation": f.Recommendation,
+				})
+			}
+		}
+		resp.Result = map[string]interface{}{"issues": issues}
+	case TypeSQL:
+		resp.Result = map[string]interface{}{"issues": []interface{}{}}
+		if !resp.Verified {
+			resp.Result["issues"] = []interface{}{map[string]interface{}{
+				"severity":    SeverityCritical,
+				"type":        "unknown_column",
+				"description": "column referenced in the query is not defined in the schema",
+			}}
+			resp.Result["reason"] = "query references a column that is not in the schema"
+		}
+	default:
+		resp.Result = map[string]interface{}{"confidence": confidence}
+		if !resp.Verified {
+			resp.Result["reason"] = "the claim contradicts the available evidence"
+		}
+	}
+	return resp
+}
+
+// simFindings are the code findings reported for refuted code.
+var simFindings = []CodeFinding{
+	{Severity: SeverityCritical, Type: "eval", Description: "eval() on untrusted input", Recommendation: "Parse the input instead of evaluating it"},
+	{Severity: SeverityCritical, Type: "sql_injection", Description: "SQL query built by string concatenation", Recommendation: "Use parameterized queries"},
+	{Severity: SeverityCritical, Type: "os_system", Description: "shell command built from input", Recommendation: "Pass arguments as a list without a shell"},
+	{Severity: SeverityWarning, Type: "weak_hash", Description: "MD5 used for hashing", Recommendation: "Use SHA-256 or a password hash"},
+	{Severity: SeverityWarning, Type: "hardcoded_secret", Description: "credential in source code", Recommendation: "Load secrets from the environment"},
+}
+
+// simHash hashes the seed with the call's inputs.
+func simHash(seed int64, engine string, inputs ...string) []byte {
+	h := sha256.New()
+	binary.Write(h, binary.BigEndian, seed)
+	h.Write([]byte(engine))
+	for _, in := range inputs {
+		h.Write([]byte{0})
+		h.Write([]byte(in))
+	}
+	return h.Sum(nil)
+}
+
+// simSeed derives the random seed of a call.
+func simSeed(seed int64, engine string, inputs ...string) int64 {
+	return int64(binary.BigEndian.Uint64(simHash(seed, engine, inputs...)))
+}
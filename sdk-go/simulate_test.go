@@ -0,0 +1,214 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSimulatedClientDefaultsVerify(t *testing.T) {
+	sim := NewSimulatedClient(SimConfig{})
+	ctx := context.Background()
+
+	resp, err := sim.VerifyCode(ctx, "print('hi')", "python")
+	if err != nil {
+		t.Fatalf("VerifyCode() error = %v", err)
+	}
+	if !resp.Verified || resp.Status != StatusVerified || resp.Engine != EngineCode {
+		t.Errorf("response = %+v, want verified code response", resp)
+	}
+	if resp.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("SchemaVersion = %d, want %d", resp.SchemaVersion, CurrentSchemaVersion)
+	}
+	if resp.Metadata == nil || resp.Metadata.RequestID == "" {
+		t.Errorf("Metadata = %+v, want request ID", resp.Metadata)
+	}
+
+	health, err := sim.Health(ctx)
+	if err != nil || health["status"] != "healthy" {
+		t.Errorf("Health() = %v, %v", health, err)
+	}
+}
+
+func TestSimulatedClientDeterministic(t *testing.T) {
+	cfg := SimConfig{Seed: 7, FailureRate: 0.3, InconclusiveRate: 0.2, ErrorRate: 0.1}
+	run := func() []string {
+		sim := NewSimulatedClient(cfg)
+		var out []string
+		for i := 0; i < 50; i++ {
+			resp, err := sim.Verify(context.Background(), fmt.Sprintf("claim %d", i))
+			if err != nil {
+				out = append(out, "error: "+err.Error())
+				continue
+			}
+			out = append(out, fmt.Sprint(resp.Status, resp.Result))
+		}
+		return out
+	}
+
+	first, second := run(), run()
+	if !reflect.DeepEqual(first, second) {
+		t.Error("outcomes differ between runs with the same seed")
+	}
+
+	cfg.Seed = 8
+	if reflect.DeepEqual(first, run()) {
+		t.Error("outcomes identical for a different seed")
+	}
+}
+
+func TestSimulatedClientRates(t *testing.T) {
+	sim := NewSimulatedClient(SimConfig{Seed: 1, FailureRate: 0.3, InconclusiveRate: 0.2, ErrorRate: 0.1})
+
+	counts := map[Verdict]int{}
+	errs := 0
+	const n = 2000
+	for i := 0; i < n; i++ {
+		resp, err := sim.VerifyFact(context.Background(), fmt.Sprintf("claim %d", i), "context")
+		if err != nil {
+			if !IsRetryable(err) {
+				t.Fatalf("simulated error %v is not retryable", err)
+			}
+			errs++
+			continue
+		}
+		counts[resp.Verdict()]++
+	}
+
+	within := func(got int, rate float64) bool {
+		want := rate * n
+		return float64(got) > want*0.8 && float64(got) < want*1.2
+	}
+	if !within(errs, 0.1) {
+		t.Errorf("errors = %d, want about %d", errs, n/10)
+	}
+	answered := float64(n - errs)
+	if !within(counts[VerdictRefuted], 0.3*answered/n) || !within(counts[VerdictInconclusive], 0.2*answered/n) {
+		t.Errorf("verdicts = %v, want about 30%% refuted and 20%% inconclusive", counts)
+	}
+}
+
+func TestSimulatedClientRefutedShapes(t *testing.T) {
+	sim := NewSimulatedClient(SimConfig{FailureRate: 1})
+	ctx := context.Background()
+
+	code, err := sim.VerifyCode(ctx, "a\nb\nc", "python")
+	if err != nil {
+		t.Fatalf("VerifyCode() error = %v", err)
+	}
+	findings := CodeFindings(code)
+	if code.Verified || len(findings) == 0 {
+		t.Fatalf("code response = %+v, want findings", code)
+	}
+	for _, f := range findings {
+		if f.Severity == "" || f.Line < 1 || f.Line > 3 {
+			t.Errorf("finding = %+v, want severity and line within the code", f)
+		}
+	}
+
+	sql, err := sim.VerifySQL(ctx, "SELECT x FROM t", "CREATE TABLE t (id INT)", "postgres")
+	if err != nil {
+		t.Fatalf("VerifySQL() error = %v", err)
+	}
+	if sql.Verdict() != VerdictRefuted || sql.Result["reason"] == nil {
+		t.Errorf("sql response = %+v, want refuted with reason", sql)
+	}
+}
+
+func TestSimulatedClientEvaluate(t *testing.T) {
+	sim := NewSimulatedClient(SimConfig{Evaluate: true})
+	ctx := context.Background()
+
+	resp, err := sim.VerifyMath(ctx, "2+2=5")
+	if err != nil {
+		t.Fatalf("VerifyMath() error = %v", err)
+	}
+	if resp.Verified || resp.Engine != EngineMath {
+		t.Errorf("response = %+v, want refuted math response", resp)
+	}
+	if _, ok := resp.Result["offline"]; ok {
+		t.Error("simulated result is marked offline")
+	}
+
+	resp, err = sim.VerifyLogic(ctx, "A OR NOT A")
+	if err != nil || !resp.Verified {
+		t.Errorf("VerifyLogic() = %+v, %v; want verified", resp, err)
+	}
+}
+
+func TestSimulatedClientResponsesAndCalls(t *testing.T) {
+	fixed := &VerificationResponse{Status: StatusFailed, Engine: "math"}
+	sim := NewSimulatedClient(SimConfig{Responses: map[string]*VerificationResponse{"1+1=3": fixed}})
+	ctx := context.Background()
+
+	if resp, _ := sim.VerifyMath(ctx, "1+1=3"); resp != fixed {
+		t.Errorf("VerifyMath() = %+v, want fixed response", resp)
+	}
+	sim.Verify(ctx, "claim")
+
+	want := []SimCall{
+		{Op: "VerifyMath", Engine: TypeMath, Input: "1+1=3"},
+		{Op: "Verify", Engine: TypeNaturalLanguage, Input: "claim"},
+	}
+	if got := sim.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Calls() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSimulatedClientLatency(t *testing.T) {
+	sim := NewSimulatedClient(SimConfig{Latency: 20 * time.Millisecond, Jitter: 10 * time.Millisecond})
+
+	start := time.Now()
+	resp, err := sim.Verify(context.Background(), "claim")
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least 20ms", elapsed)
+	}
+	if resp.Metadata.LatencyMs < 20 || resp.Metadata.LatencyMs > 30 {
+		t.Errorf("LatencyMs = %v, want 20-30", resp.Metadata.LatencyMs)
+	}
+
+	sim = NewSimulatedClient(SimConfig{Latency: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := sim.Verify(ctx, "claim"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Verify() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestSimulatedClientBatch(t *testing.T) {
+	sim := NewSimulatedClient(SimConfig{Seed: 3, FailureRate: 0.5})
+	ctx := context.Background()
+
+	items := []BatchItem{
+		{Query: "claim a", Type: TypeNaturalLanguage},
+		{Query: "claim b", Type: TypeNaturalLanguage},
+		{Query: "claim c", Type: TypeNaturalLanguage},
+		{Query: "claim d", Type: TypeNaturalLanguage},
+	}
+	batch, err := sim.VerifyBatch(ctx, items, nil)
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+	if batch.Status != "completed" || batch.Summary.Total != 4 || len(batch.Items) != 4 {
+		t.Fatalf("batch = %+v", batch)
+	}
+	if batch.Summary.Verified+batch.Summary.Failed != 4 {
+		t.Errorf("summary = %+v, want counts adding up to total", batch.Summary)
+	}
+
+	for i, item := range items {
+		resp, err := sim.Verify(ctx, item.Query)
+		if err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if resp.Verified != batch.Items[i].Verified {
+			t.Errorf("item %d verified = %v, single call = %v", i, batch.Items[i].Verified, resp.Verified)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+// Package s3 provides a qwed.Store kept in an S3 bucket, for state such as
+// baselines and scheduler verdicts that must outlive ephemeral containers.
+//
+// The package does not import an AWS SDK. Any client satisfying the small
+// Client interface can be used; for aws-sdk-go-v2 the adapter is:
+//
+//	type awsS3 struct{ *s3.Client }
+//
+//	func (c awsS3) GetObject(ctx context.Context, bucket, key string) ([]byte, error) {
+//	    out, err := c.Client.GetObject(ctx, &s3.GetObjectInput{Bucket: &bucket, Key: &key})
+//	    var missing *types.NoSuchKey
+//	    if errors.As(err, &missing) {
+//	        return nil, nil
+//	    }
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    defer out.Body.Close()
+//	    return io.ReadAll(out.Body)
+//	}
+//
+//	func (c awsS3) PutObject(ctx context.Context, bucket, key string, value []byte) error {
+//	    _, err := c.Client.PutObject(ctx, &s3.PutObjectInput{Bucket: &bucket, Key: &key, Body: bytes.NewReader(value)})
+//	    return err
+//	}
+//
+//	func (c awsS3) DeleteObject(ctx context.Context, bucket, key string) error {
+//	    _, err := c.Client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &bucket, Key: &key})
+//	    return err
+//	}
+//
+//	func (c awsS3) ListObjects(ctx context.Context, bucket, prefix string) ([]string, error) {
+//	    var keys []string
+//	    p := s3.NewListObjectsV2Paginator(c.Client, &s3.ListObjectsV2Input{Bucket: &bucket, Prefix: &prefix})
+//	    for p.HasMorePages() {
+//	        page, err := p.NextPage(ctx)
+//	        if err != nil {
+//	            return nil, err
+//	        }
+//	        for _, obj := range page.Contents {
+//	            keys = append(keys, *obj.Key)
+//	        }
+//	    }
+//	    return keys, nil
+//	}
+//
+// and the store is used with:
+//
+//	store := s3.New(awsS3{s3.NewFromConfig(cfg)}, "my-bucket", s3.WithPrefix("qwed/prod/"))
+//	scheduler := qwed.NewScheduler(client, qwed.SchedulerOptions{Store: store})
+package s3
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// DefaultPrefix is prepended to every object key written by the store.
+const DefaultPrefix = "qwed/"
+
+// Client is the subset of an S3 client used by the store.
+type Client interface {
+	// GetObject returns the object's contents, or a nil slice and nil error
+	// if the object does not exist.
+	GetObject(ctx context.Context, bucket, key string) ([]byte, error)
+
+	// PutObject creates or replaces the object.
+	PutObject(ctx context.Context, bucket, key string, value []byte) error
+
+	// DeleteObject removes the object. Deleting a missing object is not an
+	// error.
+	DeleteObject(ctx context.Context, bucket, key string) error
+
+	// ListObjects returns the keys of all objects starting with prefix.
+	ListObjects(ctx context.Context, bucket, prefix string) ([]string, error)
+}
+
+// Store is a qwed.Store kept in an S3 bucket. It is safe for concurrent use
+// if the client is.
+type Store struct {
+	client Client
+	bucket string
+	prefix string
+}
+
+var _ qwed.Store = (*Store)(nil)
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithPrefix sets the prefix prepended to every object key, to share a
+// bucket between environments. Defaults to DefaultPrefix.
+func WithPrefix(prefix string) Option {
+	return func(s *Store) {
+		s.prefix = prefix
+	}
+}
+
+// New creates a store keeping its objects in bucket.
+func New(client Client, bucket string, opts ...Option) *Store {
+	s := &Store{client: client, bucket: bucket, prefix: DefaultPrefix}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// ============================================================================
+// qwed.Store
+// ============================================================================
+
+// Get returns the object for key, or qwed.ErrNotFound.
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := s.client.GetObject(ctx, s.bucket, s.prefix+key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	if data == nil {
+		return nil, qwed.ErrNotFound
+	}
+	return data, nil
+}
+
+// Put writes the object for key.
+func (s *Store) Put(ctx context.Context, key string, value []byte) error {
+	if value == nil {
+		value = []byte{}
+	}
+	if err := s.client.PutObject(ctx, s.bucket, s.prefix+key, value); err != nil {
+		return fmt.Errorf("failed to put %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes the object for key.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if err := s.client.DeleteObject(ctx, s.bucket, s.prefix+key); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// List returns the keys starting with prefix, without the store's prefix.
+func (s *Store) List(ctx context.Context, prefix string) ([]string, error) {
+	objects, err := s.client.ListObjects(ctx, s.bucket, s.prefix+prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+	}
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		if key, ok := strings.CutPrefix(object, s.prefix); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
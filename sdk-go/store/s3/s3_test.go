@@ -0,0 +1,106 @@
+package s3
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// memoryS3 is an in-memory Client keyed by bucket and object key.
+type memoryS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemoryS3() *memoryS3 {
+	return &memoryS3{objects: make(map[string][]byte)}
+}
+
+func (m *memoryS3) GetObject(_ context.Context, bucket, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.objects[bucket+"/"+key], nil
+}
+
+func (m *memoryS3) PutObject(_ context.Context, bucket, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[bucket+"/"+key] = value
+	return nil
+}
+
+func (m *memoryS3) DeleteObject(_ context.Context, bucket, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, bucket+"/"+key)
+	return nil
+}
+
+func (m *memoryS3) ListObjects(_ context.Context, bucket, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var keys []string
+	for name := range m.objects {
+		if key, ok := strings.CutPrefix(name, bucket+"/"); ok && strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	client := newMemoryS3()
+	store := New(client, "bucket", WithPrefix("env/prod/"))
+
+	if _, err := store.Get(ctx, "baselines/main.json"); !errors.Is(err, qwed.ErrNotFound) {
+		t.Errorf("expected qwed.ErrNotFound, got %v", err)
+	}
+	if err := store.Put(ctx, "baselines/main.json", []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(ctx, "scheduler/rate", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := client.objects["bucket/env/prod/baselines/main.json"]; !ok {
+		t.Errorf("expected the prefixed object key, got %v", client.objects)
+	}
+	if data, err := store.Get(ctx, "scheduler/rate"); err != nil || len(data) != 0 {
+		t.Errorf("expected an empty value to be found, got %q, %v", data, err)
+	}
+
+	keys, err := store.List(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"baselines/main.json", "scheduler/rate"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected %v, got %v", want, keys)
+	}
+
+	if err := store.Delete(ctx, "baselines/main.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "baselines/main.json"); !errors.Is(err, qwed.ErrNotFound) {
+		t.Errorf("expected qwed.ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestStoreSharedBaseline(t *testing.T) {
+	ctx := context.Background()
+	store := New(newMemoryS3(), "bucket")
+
+	b := qwed.NewBaseline()
+	b.Add("app.py", "eval(x)\n", []qwed.CodeFinding{{Type: "eval_usage", Line: 1}})
+	if err := b.SaveTo(ctx, store, "baselines/main.json"); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := qwed.LoadBaselineFrom(ctx, store, "baselines/main.json")
+	if err != nil || len(loaded.Findings) != 1 {
+		t.Errorf("expected the baseline to round-trip, got %+v, %v", loaded, err)
+	}
+}
@@ -0,0 +1,163 @@
+// Package sqlite provides a qwed.Store kept in a SQLite table, for state
+// such as baselines and scheduler verdicts shared by processes on one host.
+//
+// The package uses database/sql and does not import a driver. Register one
+// in your program and pass the opened database:
+//
+//	import _ "modernc.org/sqlite"
+//
+//	db, err := sql.Open("sqlite", "qwed.db")
+//	if err != nil {
+//	    return err
+//	}
+//	store := sqlite.New(db)
+//	scheduler := qwed.NewScheduler(client, qwed.SchedulerOptions{Store: store})
+//
+// The table is created on first use.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// ============================================================================
+// Types
+// ============================================================================
+
+// DefaultTable is the table the store keeps its keys in.
+const DefaultTable = "qwed_state"
+
+// Store is a qwed.Store kept in a SQLite table. It is safe for concurrent
+// use.
+type Store struct {
+	db    *sql.DB
+	table string
+
+	mu    sync.Mutex
+	ready bool
+}
+
+var _ qwed.Store = (*Store)(nil)
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithTable sets the table name. Defaults to DefaultTable.
+func WithTable(name string) Option {
+	return func(s *Store) {
+		s.table = name
+	}
+}
+
+// New creates a store using db, an open SQLite database.
+func New(db *sql.DB, opts ...Option) *Store {
+	s := &Store{db: db, table: DefaultTable}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// init creates the table if it does not exist yet.
+func (s *Store) init(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ready {
+		return nil
+	}
+	if !tableName.MatchString(s.table) {
+		return fmt.Errorf("invalid table name %q", s.table)
+	}
+	_, err := s.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+s.table+
+		" (key TEXT PRIMARY KEY, value BLOB NOT NULL, updated_at INTEGER NOT NULL)")
+	if err != nil {
+		return fmt.Errorf("failed to create table %s: %w", s.table, err)
+	}
+	s.ready = true
+	return nil
+}
+
+// ============================================================================
+// qwed.Store
+// ============================================================================
+
+// Get returns the value stored under key, or qwed.ErrNotFound.
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := s.init(ctx); err != nil {
+		return nil, err
+	}
+	var value []byte
+	err := s.db.QueryRowContext(ctx, "SELECT value FROM "+s.table+" WHERE key = ?", key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, qwed.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// Put inserts or replaces the value for key.
+func (s *Store) Put(ctx context.Context, key string, value []byte) error {
+	if err := s.init(ctx); err != nil {
+		return err
+	}
+	if value == nil {
+		value = []byte{}
+	}
+	_, err := s.db.ExecContext(ctx, "INSERT INTO "+s.table+" (key, value, updated_at) VALUES (?, ?, ?)"+
+		" ON CONFLICT(key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at",
+		key, value, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to put %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes key.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if err := s.init(ctx); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM "+s.table+" WHERE key = ?", key); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// List returns the keys starting with prefix, sorted. The range condition
+// lets SQLite use the primary key index, which LIKE would not.
+func (s *Store) List(ctx context.Context, prefix string) ([]string, error) {
+	if err := s.init(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := s.db.QueryContext(ctx, "SELECT key FROM "+s.table+" WHERE key >= ? AND key < ? ORDER BY key",
+		prefix, prefix+"\U0010FFFF")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+	}
+	return keys, nil
+}
@@ -0,0 +1,201 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	qwed "github.com/QWED-AI/qwed-verification/sdk-go"
+)
+
+// fakeDriver is a database/sql driver understanding just the statements
+// the store issues, so the tests need no SQLite build. Each data source
+// name is a separate in-memory database.
+type fakeDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*fakeDB
+}
+
+type fakeDB struct {
+	mu     sync.Mutex
+	tables map[string]map[string][]byte
+}
+
+var fake = &fakeDriver{dbs: make(map[string]*fakeDB)}
+
+func init() {
+	sql.Register("qwed-fake-sqlite", fake)
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	db, ok := d.dbs[name]
+	if !ok {
+		db = &fakeDB{tables: make(map[string]map[string][]byte)}
+		d.dbs[name] = db
+	}
+	return fakeConn{db}, nil
+}
+
+type fakeConn struct{ d *fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.d, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type fakeStmt struct {
+	d     *fakeDB
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+// table returns the table named after the statement's first FROM or INTO.
+func (s fakeStmt) table() (map[string][]byte, error) {
+	fields := strings.Fields(s.query)
+	for i, f := range fields {
+		if (f == "FROM" || f == "INTO" || f == "EXISTS") && i+1 < len(fields) {
+			rows, ok := s.d.tables[fields[i+1]]
+			if !ok {
+				return nil, fmt.Errorf("no such table: %s", fields[i+1])
+			}
+			return rows, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported statement %q", s.query)
+}
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	if strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS ") {
+		name := strings.Fields(s.query)[5]
+		if _, ok := s.d.tables[name]; !ok {
+			s.d.tables[name] = make(map[string][]byte)
+		}
+		return driver.RowsAffected(0), nil
+	}
+	rows, err := s.table()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case strings.HasPrefix(s.query, "INSERT INTO ") && strings.Contains(s.query, "ON CONFLICT(key) DO UPDATE"):
+		rows[args[0].(string)] = args[1].([]byte)
+	case strings.HasPrefix(s.query, "DELETE FROM "):
+		delete(rows, args[0].(string))
+	default:
+		return nil, fmt.Errorf("unsupported statement %q", s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	rows, err := s.table()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case strings.HasPrefix(s.query, "SELECT value FROM "):
+		if value, ok := rows[args[0].(string)]; ok {
+			return &fakeRows{values: [][]driver.Value{{value}}}, nil
+		}
+		return &fakeRows{}, nil
+	case strings.HasPrefix(s.query, "SELECT key FROM ") && strings.Contains(s.query, "key >= ? AND key < ?"):
+		var keys []string
+		for key := range rows {
+			if key >= args[0].(string) && key < args[1].(string) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		result := &fakeRows{}
+		for _, key := range keys {
+			result.values = append(result.values, []driver.Value{key})
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("unsupported statement %q", s.query)
+}
+
+type fakeRows struct{ values [][]driver.Value }
+
+func (r *fakeRows) Columns() []string { return []string{"c"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+// openFake opens an empty database for the test, dropped when it ends.
+func openFake(t *testing.T) *sql.DB {
+	db, err := sql.Open("qwed-fake-sqlite", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		db.Close()
+		fake.mu.Lock()
+		delete(fake.dbs, t.Name())
+		fake.mu.Unlock()
+	})
+	return db
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := New(openFake(t), WithTable("state_roundtrip"))
+
+	if _, err := store.Get(ctx, "baselines/main.json"); !errors.Is(err, qwed.ErrNotFound) {
+		t.Errorf("expected qwed.ErrNotFound, got %v", err)
+	}
+	for _, key := range []string{"baselines/main.json", "baselines/dev.json", "scheduler/rate"} {
+		if err := store.Put(ctx, key, []byte(key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Put(ctx, "baselines/main.json", []byte("updated")); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := store.Get(ctx, "baselines/main.json"); err != nil || string(data) != "updated" {
+		t.Errorf("expected the updated value, got %q, %v", data, err)
+	}
+
+	keys, err := store.List(ctx, "baselines/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"baselines/dev.json", "baselines/main.json"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected %v, got %v", want, keys)
+	}
+
+	if err := store.Delete(ctx, "baselines/dev.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "baselines/dev.json"); !errors.Is(err, qwed.ErrNotFound) {
+		t.Errorf("expected qwed.ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestStoreInvalidTable(t *testing.T) {
+	store := New(openFake(t), WithTable("state; DROP TABLE users"))
+	if err := store.Put(context.Background(), "k", []byte("v")); err == nil {
+		t.Error("expected an error for an invalid table name")
+	}
+}
@@ -0,0 +1,72 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testStore exercises the Store contract.
+func testStore(t *testing.T, s Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := s.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	for key, value := range map[string]string{
+		"baselines/main.json": "a",
+		"baselines/dev.json":  "b",
+		"scheduler/rate":      "c",
+	} {
+		if err := s.Put(ctx, key, []byte(value)); err != nil {
+			t.Fatalf("put %s: %v", key, err)
+		}
+	}
+	if err := s.Put(ctx, "baselines/main.json", []byte("updated")); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.Get(ctx, "baselines/main.json"); err != nil || string(got) != "updated" {
+		t.Errorf("expected updated value, got %q, %v", got, err)
+	}
+
+	keys, err := s.List(ctx, "baselines/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"baselines/dev.json", "baselines/main.json"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("expected %v, got %v", want, keys)
+	}
+
+	if err := s.Delete(ctx, "baselines/dev.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "baselines/dev.json"); err != nil {
+		t.Errorf("expected deleting a missing key to succeed, got %v", err)
+	}
+	if _, err := s.Get(ctx, "baselines/dev.json"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if err := s.Put(ctx, "../escape", []byte("x")); err == nil {
+		t.Error("expected an error for a key outside the store")
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	testStore(t, NewFileStore(dir))
+
+	if data, err := os.ReadFile(filepath.Join(dir, "scheduler", "rate")); err != nil || string(data) != "c" {
+		t.Errorf("expected the value on disk, got %q, %v", data, err)
+	}
+	if keys, err := NewFileStore(filepath.Join(dir, "missing")).List(context.Background(), ""); err != nil || len(keys) != 0 {
+		t.Errorf("expected an empty list for a missing directory, got %v, %v", keys, err)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore())
+}
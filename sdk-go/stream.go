@@ -0,0 +1,204 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ============================================================================
+// Streaming Verification
+// ============================================================================
+
+// ErrStreamClosed is returned when writing to a closed StreamVerifier.
+var ErrStreamClosed = errors.New("qwed: stream verifier is closed")
+
+// StreamEvent reports the verification of one statement detected in a stream.
+type StreamEvent struct {
+	Index     int                   // position of the statement in the stream
+	Statement string                // the statement text, trimmed
+	Offset    int                   // byte offset of the statement in the stream
+	Response  *VerificationResponse // nil if Err is set
+	Err       error
+}
+
+// StreamOptions configures a StreamVerifier.
+type StreamOptions struct {
+	// Verify checks a single statement. Defaults to the verifier's Verify.
+	Verify func(ctx context.Context, statement string) (*VerificationResponse, error)
+	// Buffer is the number of statements that may be pending verification
+	// before Write blocks. Defaults to 64.
+	Buffer int
+}
+
+// StreamVerifier verifies LLM output incrementally. Tokens are appended with
+// Write; whenever a complete statement is detected it is verified in the
+// background and the result is delivered on Events, in stream order.
+type StreamVerifier struct {
+	ctx    context.Context
+	verify func(ctx context.Context, statement string) (*VerificationResponse, error)
+
+	mu      sync.Mutex
+	buf     strings.Builder
+	start   int // stream offset of buf[0]
+	index   int
+	closed  bool
+	pending chan StreamEvent
+	events  chan StreamEvent
+	done    chan struct{}
+}
+
+// NewStreamVerifier creates a StreamVerifier that checks statements with v.
+// Callers must drain Events and call Close when the stream ends.
+func NewStreamVerifier(ctx context.Context, v Verifier, opts *StreamOptions) *StreamVerifier {
+	if opts == nil {
+		opts = &StreamOptions{}
+	}
+	buffer := opts.Buffer
+	if buffer <= 0 {
+		buffer = 64
+	}
+
+	s := &StreamVerifier{
+		ctx:     ctx,
+		verify:  opts.Verify,
+		pending: make(chan StreamEvent, buffer),
+		events:  make(chan StreamEvent, buffer),
+		done:    make(chan struct{}),
+	}
+	if s.verify == nil {
+		s.verify = func(ctx context.Context, statement string) (*VerificationResponse, error) {
+			return v.Verify(ctx, statement)
+		}
+	}
+
+	go s.run()
+	return s
+}
+
+// Events returns the channel on which verification results are delivered.
+// It is closed after Close once all pending statements have been verified.
+func (s *StreamVerifier) Events() <-chan StreamEvent {
+	return s.events
+}
+
+// Write appends a token to the stream.
+func (s *StreamVerifier) Write(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStreamClosed
+	}
+
+	s.buf.WriteString(token)
+	return s.flush(false)
+}
+
+// Close verifies any trailing partial statement, waits for outstanding
+// verifications and closes the Events channel.
+func (s *StreamVerifier) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	err := s.flush(true)
+	s.closed = true
+	close(s.pending)
+	s.mu.Unlock()
+
+	<-s.done
+	return err
+}
+
+// flush queues every complete statement in the buffer. When final is set the
+// remainder is queued too. The caller must hold s.mu.
+func (s *StreamVerifier) flush(final bool) error {
+	text := s.buf.String()
+	consumed := 0
+
+	for {
+		end := statementEnd(text[consumed:])
+		if end < 0 {
+			break
+		}
+		if err := s.emit(text[consumed:consumed+end], s.start+consumed); err != nil {
+			return err
+		}
+		consumed += end
+	}
+
+	if final && consumed < len(text) {
+		if err := s.emit(text[consumed:], s.start+consumed); err != nil {
+			return err
+		}
+		consumed = len(text)
+	}
+
+	if consumed > 0 {
+		rest := text[consumed:]
+		s.buf.Reset()
+		s.buf.WriteString(rest)
+		s.start += consumed
+	}
+	return nil
+}
+
+// emit queues a raw statement for verification, skipping blank ones.
+func (s *StreamVerifier) emit(raw string, offset int) error {
+	statement := strings.TrimSpace(raw)
+	if statement == "" {
+		return nil
+	}
+	offset += strings.Index(raw, statement)
+
+	ev := StreamEvent{Index: s.index, Statement: statement, Offset: offset}
+	s.index++
+
+	select {
+	case s.pending <- ev:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+func (s *StreamVerifier) run() {
+	defer close(s.done)
+	defer close(s.events)
+
+	for ev := range s.pending {
+		if err := s.ctx.Err(); err != nil {
+			ev.Err = err
+		} else {
+			ev.Response, ev.Err = s.verify(s.ctx, ev.Statement)
+		}
+		if ev.Err != nil {
+			ev.Response = nil
+		}
+		s.events <- ev
+	}
+}
+
+// statementEnd returns the length of the first complete statement in text,
+// or -1 if text does not yet contain one. A statement ends at a newline, or
+// at '.', '!' or '?' followed by whitespace; a terminator at the very end of
+// text is not treated as final since the next token may continue it (as in
+// "2.5").
+func statementEnd(text string) int {
+	for i, r := range text {
+		switch r {
+		case '\n':
+			return i + 1
+		case '.', '!', '?':
+			next := i + 1
+			if next < len(text) && unicode.IsSpace(rune(text[next])) {
+				return next
+			}
+		}
+	}
+	return -1
+}
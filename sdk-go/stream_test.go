@@ -0,0 +1,54 @@
+package qwed
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStreamVerifier(t *testing.T) {
+	var seen []string
+	sv := NewStreamVerifier(context.Background(), &MockClient{}, &StreamOptions{
+		Verify: func(ctx context.Context, statement string) (*VerificationResponse, error) {
+			seen = append(seen, statement)
+			return &VerificationResponse{Verified: !strings.Contains(statement, "5")}, nil
+		},
+	})
+
+	tokens := []string{"2 + 2 ", "is 4.", " Pi is ", "about 3.", "14! Then ", "2 + 2 is 5"}
+	for _, tok := range tokens {
+		if err := sv.Write(tok); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := sv.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	var events []StreamEvent
+	for ev := range sv.Events() {
+		events = append(events, ev)
+	}
+
+	want := []string{"2 + 2 is 4.", "Pi is about 3.14!", "Then 2 + 2 is 5"}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(events), events)
+	}
+
+	stream := strings.Join(tokens, "")
+	for i, ev := range events {
+		if ev.Index != i || ev.Statement != want[i] {
+			t.Errorf("event %d: got %+v", i, ev)
+		}
+		if stream[ev.Offset:ev.Offset+len(ev.Statement)] != ev.Statement {
+			t.Errorf("event %d: offset %d does not point at statement", i, ev.Offset)
+		}
+	}
+	if events[2].Response.Verified {
+		t.Error("expected last statement to fail verification")
+	}
+
+	if err := sv.Write("more"); err != ErrStreamClosed {
+		t.Errorf("expected ErrStreamClosed, got %v", err)
+	}
+}
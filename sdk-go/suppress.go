@@ -0,0 +1,186 @@
+package qwed
+
+import (
+	"strings"
+)
+
+// ============================================================================
+// Inline Suppressions
+// ============================================================================
+
+// suppressionMarker introduces an inline suppression comment.
+const suppressionMarker = "qwed:ignore"
+
+// Suppression is a parsed inline suppression comment such as
+//
+//	eval(expr) // qwed:ignore eval_usage reason=input is a constant
+//
+// A trailing comment applies to its own line; a comment alone on a line
+// applies to the next line. With no rule IDs it suppresses every rule.
+type Suppression struct {
+	Line   int      // 1-based line the suppression applies to
+	Rules  []string // rule IDs; empty means all rules
+	Reason string
+}
+
+// Matches reports whether the suppression covers finding.
+func (s Suppression) Matches(f CodeFinding) bool {
+	if f.Line != s.Line {
+		return false
+	}
+	if len(s.Rules) == 0 {
+		return true
+	}
+	for _, rule := range s.Rules {
+		if strings.EqualFold(rule, f.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseSuppressions finds qwed:ignore comments in code. Comments may use
+// //, #, -- or /* */ syntax.
+func ParseSuppressions(code string) []Suppression {
+	if !strings.Contains(code, suppressionMarker) {
+		return nil
+	}
+
+	var out []Suppression
+	for i, line := range strings.Split(code, "\n") {
+		idx := strings.Index(line, suppressionMarker)
+		if idx < 0 {
+			continue
+		}
+
+		prefix := strings.TrimSpace(line[:idx])
+		if !isCommentOpener(prefix) {
+			continue
+		}
+
+		s := parseSuppression(line[idx+len(suppressionMarker):])
+		s.Line = i + 1
+		if commentOnly(prefix) {
+			s.Line = i + 2
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// ApplySuppressions marks findings covered by a suppression in code. Findings
+// are never removed, so suppressed issues stay visible in reports.
+func ApplySuppressions(code string, findings []CodeFinding) []CodeFinding {
+	suppressions := ParseSuppressions(code)
+	for i := range findings {
+		for _, s := range suppressions {
+			if s.Matches(findings[i]) {
+				findings[i].Suppressed = true
+				findings[i].SuppressionReason = s.Reason
+				break
+			}
+		}
+	}
+	return findings
+}
+
+// UnsuppressedFindings returns the findings in resp that are not covered by
+// an inline suppression.
+func UnsuppressedFindings(resp *VerificationResponse) []CodeFinding {
+	var out []CodeFinding
+	for _, f := range CodeFindings(resp) {
+		if !f.Suppressed {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// annotateSuppressions returns a copy of a VerifyCode response with
+// suppressed issues marked and counted. resp may be a cached response
+// shared with other callers, so it is not modified.
+func annotateSuppressions(code string, resp *VerificationResponse) *VerificationResponse {
+	suppressions := ParseSuppressions(code)
+	if len(suppressions) == 0 || resp == nil {
+		return resp
+	}
+
+	issues, _ := resp.Result["issues"].([]interface{})
+	annotated := make([]interface{}, len(issues))
+	suppressed := 0
+	for i, raw := range issues {
+		annotated[i] = raw
+		issue, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var f CodeFinding
+		if !decodeResult(issue, &f) {
+			continue
+		}
+		for _, s := range suppressions {
+			if s.Matches(f) {
+				marked := make(map[string]interface{}, len(issue)+2)
+				for k, v := range issue {
+					marked[k] = v
+				}
+				marked["suppressed"] = true
+				if s.Reason != "" {
+					marked["suppression_reason"] = s.Reason
+				}
+				annotated[i] = marked
+				suppressed++
+				break
+			}
+		}
+	}
+	if suppressed == 0 {
+		return resp
+	}
+
+	out := *resp
+	out.Result = make(map[string]interface{}, len(resp.Result)+1)
+	for k, v := range resp.Result {
+		out.Result[k] = v
+	}
+	out.Result["issues"] = annotated
+	out.Result["suppressed_count"] = suppressed
+	return &out
+}
+
+// parseSuppression parses the text after the marker: rule IDs separated by
+// spaces or commas, then an optional reason=... running to the end.
+func parseSuppression(text string) Suppression {
+	text = strings.TrimSuffix(strings.TrimSpace(text), "*/")
+
+	var s Suppression
+	if i := strings.Index(text, "reason="); i >= 0 {
+		s.Reason = strings.Trim(strings.TrimSpace(text[i+len("reason="):]), `"'`)
+		text = text[:i]
+	}
+	for _, rule := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		s.Rules = append(s.Rules, rule)
+	}
+	return s
+}
+
+// isCommentOpener reports whether the text before the marker ends with a
+// comment opener.
+func isCommentOpener(prefix string) bool {
+	for _, opener := range []string{"//", "#", "--", "/*"} {
+		if strings.HasSuffix(prefix, opener) {
+			return true
+		}
+	}
+	return false
+}
+
+// commentOnly reports whether the text before the marker is just the
+// comment opener, i.e. the comment occupies its own line.
+func commentOnly(prefix string) bool {
+	switch prefix {
+	case "//", "#", "--", "/*":
+		return true
+	}
+	return false
+}
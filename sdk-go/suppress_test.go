@@ -0,0 +1,123 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseSuppressions(t *testing.T) {
+	code := `import os
+# qwed:ignore os_system reason=fixed command
+os.system("ls")
+x = eval(y)  # qwed:ignore eval_usage, exec_usage reason="trusted input"
+/* qwed:ignore */
+danger()
+s = "qwed:ignore in a string"
+`
+	got := ParseSuppressions(code)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 suppressions, got %d: %+v", len(got), got)
+	}
+
+	if got[0].Line != 3 || got[0].Rules[0] != "os_system" || got[0].Reason != "fixed command" {
+		t.Errorf("unexpected own-line suppression: %+v", got[0])
+	}
+	if got[1].Line != 4 || len(got[1].Rules) != 2 || got[1].Reason != "trusted input" {
+		t.Errorf("unexpected trailing suppression: %+v", got[1])
+	}
+	if got[2].Line != 6 || len(got[2].Rules) != 0 {
+		t.Errorf("unexpected catch-all suppression: %+v", got[2])
+	}
+}
+
+func TestVerifyCodeMarksSuppressed(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": false,
+			"result": map[string]interface{}{
+				"issues": []map[string]interface{}{
+					{"severity": "CRITICAL", "type": "eval_usage", "line_number": 1},
+					{"severity": "CRITICAL", "type": "eval_usage", "line_number": 2},
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	code := "a = eval(x)  # qwed:ignore eval_usage reason=constant\nb = eval(y)\n"
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyCode(context.Background(), code, "python")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	findings := CodeFindings(resp)
+	if len(findings) != 2 {
+		t.Fatalf("expected suppressed finding to be kept, got %d findings", len(findings))
+	}
+	if !findings[0].Suppressed || findings[0].SuppressionReason != "constant" {
+		t.Errorf("expected first finding suppressed, got %+v", findings[0])
+	}
+	if remaining := UnsuppressedFindings(resp); len(remaining) != 1 || remaining[0].Line != 2 {
+		t.Errorf("unexpected unsuppressed findings: %+v", remaining)
+	}
+	if resp.Result["suppressed_count"] != 1 {
+		t.Errorf("expected suppressed_count 1, got %v", resp.Result["suppressed_count"])
+	}
+}
+
+func TestVerifyCodeSuppressionsLeaveCacheUnchanged(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": false,
+			"result": map[string]interface{}{
+				"issues": []map[string]interface{}{
+					{"severity": "CRITICAL", "type": "eval_usage", "line_number": 1},
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	code := "a = eval(x)  # qwed:ignore eval_usage reason=constant\n"
+	cache := NewLRUCache(10)
+	client := NewClient("test-key", WithBaseURL(server.URL), WithCache(cache, time.Minute))
+	if _, err := client.VerifyCode(context.Background(), code, "python"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Concurrent callers share the cached response; run with -race.
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.VerifyCode(context.Background(), code, "python")
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if resp.Result["suppressed_count"] != 1 || !CodeFindings(resp)[0].Suppressed {
+				t.Errorf("expected suppressed finding, got %v", resp.Result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	cached, ok, _ := cache.Get(context.Background(), CacheKey(TypeCode, "python", code, optionsKey(nil)))
+	if !ok {
+		t.Fatal("expected response to be cached")
+	}
+	if _, ok := cached.Result["suppressed_count"]; ok {
+		t.Errorf("expected cached response to be unannotated, got %v", cached.Result)
+	}
+	if CodeFindings(cached)[0].Suppressed {
+		t.Error("expected cached issue to be unannotated")
+	}
+}
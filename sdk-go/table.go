@@ -0,0 +1,453 @@
+package qwed
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Table Verification
+// ============================================================================
+
+// TypeTable identifies table consistency checks. They run locally and are
+// reported with Engine EngineLocalTable.
+const TypeTable VerificationType = "table"
+
+// EngineLocalTable is the engine name reported by VerifyTable.
+const EngineLocalTable Engine = "local-table"
+
+// TableDiscrepancyKind classifies a TableDiscrepancy.
+type TableDiscrepancyKind string
+
+const (
+	TableMismatch   TableDiscrepancyKind = "mismatch"   // a cell differs from the source record
+	TableBadTotal   TableDiscrepancyKind = "total"      // a total cell is not the sum of its rows
+	TableFabricated TableDiscrepancyKind = "fabricated" // a row has no source record
+	TableDuplicate  TableDiscrepancyKind = "duplicate"  // a row repeats an earlier row's source record
+	TableMissing    TableDiscrepancyKind = "missing"    // a source record has no row (TableOptions.Complete)
+)
+
+// TableDiscrepancy is a cell or row of a generated table that does not agree
+// with the source data. Row and Column locate the cell for highlighting.
+type TableDiscrepancy struct {
+	Kind     TableDiscrepancyKind `json:"kind"`
+	Row      int                  `json:"row"`              // 0-based body row of the table, -1 for missing rows
+	Column   string               `json:"column,omitempty"` // table header, or the source header for missing rows
+	Key      string               `json:"key,omitempty"`    // the row's key cells, joined by " / "
+	Expected string               `json:"expected,omitempty"`
+	Actual   string               `json:"actual,omitempty"`
+}
+
+// TableOptions configures VerifyTableWithOptions.
+type TableOptions struct {
+	// KeyColumns identify a row in both the table and the source, such as
+	// "Region" or "Year" and "Quarter". Defaults to the first table column
+	// that also appears in the source.
+	KeyColumns []string
+
+	// Tolerance is the relative difference allowed between numbers, e.g.
+	// 0.01 for 1%. Numbers always match when they agree to the precision
+	// written in the table, so "1.2M" style rounding needs no tolerance.
+	Tolerance float64
+
+	// Complete requires every source record to appear in the table. By
+	// default tables may show a subset, such as the top ten rows.
+	Complete bool
+}
+
+// VerifyTable checks an LLM-generated table against the data it was built
+// from. It is VerifyTableWithOptions with default options.
+func (c *Client) VerifyTable(ctx context.Context, markdownTable, sourceData string) (*VerificationResponse, error) {
+	return c.VerifyTableWithOptions(ctx, markdownTable, sourceData, nil)
+}
+
+// VerifyTableWithOptions checks markdownTable, a Markdown or CSV table,
+// against sourceData, CSV with a header row. The response is verified if
+// every row matches a source record, every cell in a column the source
+// also has agrees with it, and every total row (labeled "Total",
+// "Subtotal" and the like) sums the rows above it. Cell-level
+// discrepancies are reported in Result["discrepancies"], decoded by
+// TableDiscrepancies.
+//
+// Columns are matched by header, ignoring case and punctuation. Table
+// columns the source lacks, such as computed percentages, are listed in
+// Result["unchecked_columns"] and only their totals are checked. A table
+// that cannot be parsed returns StatusUnsupported; invalid source data is
+// an error.
+func (c *Client) VerifyTableWithOptions(ctx context.Context, markdownTable, sourceData string, opts *TableOptions) (resp *VerificationResponse, err error) {
+	_, end := c.instrument(ctx, "VerifyTable", TypeTable)
+	defer func() { end(resp, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	source, err := csv.NewReader(strings.NewReader(sourceData)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse source data: %w", err)
+	}
+	if len(source) == 0 {
+		return nil, fmt.Errorf("failed to parse source data: no header row")
+	}
+	var o TableOptions
+	if opts != nil {
+		o = *opts
+	}
+	return localVerifyTable(markdownTable, source, o), nil
+}
+
+// TableDiscrepancies extracts the discrepancies from a VerifyTable response.
+func TableDiscrepancies(resp *VerificationResponse) []TableDiscrepancy {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var discrepancies []TableDiscrepancy
+	decodeResult(resp.Result["discrepancies"], &discrepancies)
+	return discrepancies
+}
+
+// localVerifyTable compares table with source, whose first row is the
+// header.
+func localVerifyTable(table string, source [][]string, o TableOptions) *VerificationResponse {
+	header, rows := parseTable(table)
+	if len(header) == 0 {
+		return &VerificationResponse{
+			Status: StatusUnsupported,
+			Engine: EngineLocalTable,
+			Result: map[string]interface{}{"reason": "no Markdown or CSV table found"},
+		}
+	}
+
+	// Map table columns to source columns.
+	sourceColumn := make(map[string]int)
+	for i, name := range source[0] {
+		sourceColumn[columnName(name)] = i
+	}
+	columns := make([]int, len(header)) // source index per table column, or -1
+	unchecked := []string{}
+	for i, name := range header {
+		columns[i] = -1
+		if j, ok := sourceColumn[columnName(name)]; ok {
+			columns[i] = j
+		} else {
+			unchecked = append(unchecked, name)
+		}
+	}
+
+	keys, err := tableKeyColumns(header, columns, o.KeyColumns)
+	result := map[string]interface{}{"rows": len(rows), "unchecked_columns": unchecked}
+	if err != nil {
+		result["reason"] = err.Error()
+		return &VerificationResponse{Status: StatusUnsupported, Engine: EngineLocalTable, Result: result}
+	}
+
+	// Index the source records by key.
+	records := make(map[string]int)
+	for r, record := range source[1:] {
+		records[tableKey(record, keys, func(k int) int { return columns[k] })] = r + 1
+	}
+
+	discrepancies := []TableDiscrepancy{}
+	seen := make(map[int]bool)
+	var sectionStart int
+	for r, row := range rows {
+		if isTotalRow(row) {
+			start := sectionStart
+			if label := strings.ToLower(row[firstLabel(row)]); strings.Contains(label, "grand") || strings.Contains(label, "overall") {
+				start = 0
+			}
+			discrepancies = append(discrepancies, checkTotals(header, rows, start, r, o.Tolerance)...)
+			sectionStart = r + 1
+			continue
+		}
+
+		s, ok := records[tableKey(row, keys, func(k int) int { return k })]
+		key := displayKey(row, keys, func(k int) int { return k })
+		if !ok {
+			discrepancies = append(discrepancies, TableDiscrepancy{Kind: TableFabricated, Row: r, Column: header[keys[0]], Key: key})
+			continue
+		}
+		if seen[s] {
+			discrepancies = append(discrepancies, TableDiscrepancy{Kind: TableDuplicate, Row: r, Column: header[keys[0]], Key: key})
+			continue
+		}
+		seen[s] = true
+
+		for c, j := range columns {
+			if j < 0 || j >= len(source[s]) || c >= len(row) {
+				continue
+			}
+			if !cellsMatch(row[c], source[s][j], o.Tolerance) {
+				discrepancies = append(discrepancies, TableDiscrepancy{
+					Kind: TableMismatch, Row: r, Column: header[c], Key: key, Expected: source[s][j], Actual: row[c],
+				})
+			}
+		}
+	}
+
+	if o.Complete {
+		for r, record := range source[1:] {
+			if !seen[r+1] {
+				key := displayKey(record, keys, func(k int) int { return columns[k] })
+				discrepancies = append(discrepancies, TableDiscrepancy{Kind: TableMissing, Row: -1, Column: source[0][columns[keys[0]]], Key: key})
+			}
+		}
+	}
+
+	result["discrepancies"] = discrepancies
+	return localResponse(EngineLocalTable, len(discrepancies) == 0, result)
+}
+
+// tableKeyColumns returns the table column indexes identifying a row.
+func tableKeyColumns(header []string, columns []int, names []string) ([]int, error) {
+	if len(names) == 0 {
+		for i, j := range columns {
+			if j >= 0 {
+				return []int{i}, nil
+			}
+		}
+		return nil, fmt.Errorf("no table column matches a source column")
+	}
+
+	var keys []int
+	for _, name := range names {
+		found := false
+		for i, h := range header {
+			if columnName(h) == columnName(name) {
+				if columns[i] < 0 {
+					return nil, fmt.Errorf("key column %q is not in the source data", name)
+				}
+				keys, found = append(keys, i), true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("key column %q is not in the table", name)
+		}
+	}
+	return keys, nil
+}
+
+// tableKey joins the normalized key cells of row; index maps a table column
+// to the row's column.
+func tableKey(row []string, keys []int, index func(int) int) string {
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		if j := index(k); j < len(row) {
+			parts[i] = normalizeCell(row[j])
+		}
+	}
+	return strings.Join(parts, "\x1f")
+}
+
+// displayKey joins the key cells of row as written, for reporting.
+func displayKey(row []string, keys []int, index func(int) int) string {
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if j := index(k); j < len(row) {
+			parts = append(parts, normalizeCellText(row[j]))
+		}
+	}
+	return strings.Join(parts, " / ")
+}
+
+// checkTotals compares each numeric cell of the total row rows[at] with the
+// sum of rows[start:at], skipping other total rows.
+func checkTotals(header []string, rows [][]string, start, at int, tolerance float64) []TableDiscrepancy {
+	var discrepancies []TableDiscrepancy
+	label := firstLabel(rows[at])
+	for c := range header {
+		if c == label || c >= len(rows[at]) {
+			continue
+		}
+		total, totalDecimals, ok := parseTableNumber(rows[at][c])
+		if !ok {
+			continue
+		}
+
+		var sum float64
+		decimals := totalDecimals
+		summed := 0
+		for r := start; r < at; r++ {
+			if isTotalRow(rows[r]) || c >= len(rows[r]) {
+				continue
+			}
+			if v, d, ok := parseTableNumber(rows[r][c]); ok {
+				sum += v
+				decimals = min(decimals, d)
+				summed++
+			}
+		}
+		// Percentage and average columns rarely sum; only check totals of
+		// columns whose rows are all numbers.
+		if summed == 0 || strings.HasSuffix(strings.TrimSpace(rows[at][c]), "%") {
+			continue
+		}
+		if !numbersMatch(total, sum, decimals, tolerance) {
+			discrepancies = append(discrepancies, TableDiscrepancy{
+				Kind:     TableBadTotal,
+				Row:      at,
+				Column:   header[c],
+				Key:      normalizeCellText(rows[at][label]),
+				Expected: strconv.FormatFloat(sum, 'f', max(decimals, 0), 64),
+				Actual:   rows[at][c],
+			})
+		}
+	}
+	return discrepancies
+}
+
+var totalLabel = regexp.MustCompile(`(?i)^(grand |overall |sub-?)?totals?\b`)
+
+// isTotalRow reports whether the row's label reads like a total.
+func isTotalRow(row []string) bool {
+	return len(row) > 0 && totalLabel.MatchString(normalizeCell(row[firstLabel(row)]))
+}
+
+// firstLabel returns the index of the row's first non-empty cell.
+func firstLabel(row []string) int {
+	for i, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return i
+		}
+	}
+	return 0
+}
+
+// cellsMatch compares a table cell with a source value, numerically when
+// both are numbers.
+func cellsMatch(cell, value string, tolerance float64) bool {
+	if a, decimals, ok := parseTableNumber(cell); ok {
+		if b, _, ok := parseTableNumber(value); ok {
+			return numbersMatch(a, b, decimals, tolerance)
+		}
+	}
+	return normalizeCell(cell) == normalizeCell(value)
+}
+
+// numbersMatch reports whether got equals want to decimals places, or within
+// the relative tolerance.
+func numbersMatch(got, want float64, decimals int, tolerance float64) bool {
+	diff := math.Abs(got - want)
+	return diff <= 0.5*math.Pow(10, -float64(decimals))+1e-9 || diff <= tolerance*math.Abs(want)
+}
+
+var tableNumber = regexp.MustCompile(`^([-+−]?)[$€£¥]?\s*([0-9][0-9,]*(?:\.[0-9]+)?|\.[0-9]+)\s*([kKmMbB]|bn|%)?$`)
+
+// parseTableNumber parses a formatted number such as "$1,234.50", "(12)",
+// "45%" or "1.2M", returning its value and the decimal places written,
+// negative for the k, M and B suffixes.
+func parseTableNumber(s string) (float64, int, bool) {
+	s = normalizeCellText(s)
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s, negative = s[1:len(s)-1], true
+	}
+	m := tableNumber.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, false
+	}
+	digits := strings.ReplaceAll(m[2], ",", "")
+	v, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	decimals := 0
+	if _, frac, ok := strings.Cut(digits, "."); ok {
+		decimals = len(frac)
+	}
+	switch strings.ToLower(m[3]) {
+	case "k":
+		v, decimals = v*1e3, decimals-3
+	case "m":
+		v, decimals = v*1e6, decimals-6
+	case "b", "bn":
+		v, decimals = v*1e9, decimals-9
+	}
+	if negative || m[1] == "-" || m[1] == "−" {
+		v = -v
+	}
+	return v, decimals, true
+}
+
+// normalizeCellText strips Markdown emphasis and code marks from a cell.
+func normalizeCellText(s string) string {
+	return strings.TrimSpace(strings.NewReplacer("**", "", "__", "", "`", "", "*", "").Replace(s))
+}
+
+// normalizeCell folds case and whitespace for comparing text cells.
+func normalizeCell(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(normalizeCellText(s))), " ")
+}
+
+// columnName folds a header for matching: "Unit Price ($)" and
+// "unit_price" are the same column.
+func columnName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(normalizeCellText(s)) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127 {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+var markdownSeparator = regexp.MustCompile(`^\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?$`)
+
+// parseTable extracts the first Markdown table from text, which may
+// contain surrounding prose, or parses text as CSV if it has none. It
+// returns nil if neither yields a header.
+func parseTable(text string) ([]string, [][]string) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	for i := 1; i < len(lines); i++ {
+		sep := strings.TrimSpace(lines[i])
+		if !markdownSeparator.MatchString(sep) || !strings.Contains(lines[i-1], "|") {
+			continue
+		}
+		header := splitMarkdownRow(lines[i-1])
+		var rows [][]string
+		for _, line := range lines[i+1:] {
+			if !strings.Contains(line, "|") {
+				break
+			}
+			rows = append(rows, splitMarkdownRow(line))
+		}
+		return header, rows
+	}
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimSpace(text))).ReadAll()
+	if err != nil || len(records) < 2 || len(records[0]) < 2 {
+		return nil, nil
+	}
+	return records[0], records[1:]
+}
+
+// splitMarkdownRow splits a Markdown table row on unescaped pipes.
+func splitMarkdownRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
@@ -0,0 +1,148 @@
+package qwed
+
+import (
+	"context"
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const salesCSV = `region,revenue,units
+North,1200.50,10
+South,800,7
+East,450.25,3
+`
+
+func TestVerifyTable(t *testing.T) {
+	table := `Here is the summary you asked for:
+
+| Region | Revenue ($) | Units |
+|:-------|------------:|------:|
+| North  | $1,200.50   | 10    |
+| **South** | $800.00  | 7     |
+| **Total** | **$2,000.50** | **17** |
+
+Let me know if you need more.`
+
+	client := NewClient("test")
+	resp, err := client.VerifyTable(context.Background(), table, salesCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Verified || resp.Engine != EngineLocalTable {
+		t.Errorf("expected verified by %s, got %+v %v", EngineLocalTable, resp, resp.Result)
+	}
+}
+
+func TestVerifyTableDiscrepancies(t *testing.T) {
+	table := `| Region | Revenue | Units |
+| --- | --- | --- |
+| North | 1,250.50 | 10 |
+| West | 300 | 2 |
+| South | 800 | 7 |
+| North | 1200.5 | 10 |
+| Total | 2,600 | 29 |`
+
+	resp := localVerifyTable(table, parseCSV(t, salesCSV), TableOptions{Complete: true})
+	if resp.Verified {
+		t.Fatal("expected discrepancies")
+	}
+	want := []TableDiscrepancy{
+		{Kind: TableMismatch, Row: 0, Column: "Revenue", Key: "North", Expected: "1200.50", Actual: "1,250.50"},
+		{Kind: TableFabricated, Row: 1, Column: "Region", Key: "West"},
+		{Kind: TableDuplicate, Row: 3, Column: "Region", Key: "North"},
+		{Kind: TableBadTotal, Row: 4, Column: "Revenue", Key: "Total", Expected: "3551", Actual: "2,600"},
+		{Kind: TableMissing, Row: -1, Column: "region", Key: "East"},
+	}
+	if got := TableDiscrepancies(resp); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v\ngot      %+v", want, got)
+	}
+}
+
+func TestVerifyTableOptions(t *testing.T) {
+	source := "year,quarter,revenue\n2024,Q1,1234567\n2024,Q2,2345678\n2025,Q1,3000000\n"
+	table := "Year,Quarter,Revenue,Share\n2024,Q1,1.2M,14%\n2024,Q2,2.3M,27%\n2025,Q1,3.0M,35%\n"
+
+	resp := localVerifyTable(table, parseCSV(t, source), TableOptions{KeyColumns: []string{"Year", "Quarter"}})
+	if !resp.Verified {
+		t.Errorf("expected rounded millions to match, got %v", resp.Result)
+	}
+	if got := resp.Result["unchecked_columns"]; !reflect.DeepEqual(got, []string{"Share"}) {
+		t.Errorf("expected Share to be unchecked, got %v", got)
+	}
+
+	// Keyed by year alone, the two 2024 rows collide.
+	resp = localVerifyTable(table, parseCSV(t, source), TableOptions{KeyColumns: []string{"Year"}})
+	if resp.Verified {
+		t.Error("expected a duplicate row")
+	}
+
+	resp = localVerifyTable("Year,Revenue\n2024,1250000\n", parseCSV(t, "year,revenue\n2024,1234567\n"), TableOptions{Tolerance: 0.02})
+	if !resp.Verified {
+		t.Errorf("expected a 1.3%% difference within 2%% tolerance, got %v", resp.Result)
+	}
+
+	if resp := localVerifyTable(table, parseCSV(t, source), TableOptions{KeyColumns: []string{"Share"}}); resp.Status != StatusUnsupported {
+		t.Errorf("expected an unsupported key column, got %s", resp.Status)
+	}
+	if resp := localVerifyTable("no table here", parseCSV(t, source), TableOptions{}); resp.Status != StatusUnsupported {
+		t.Errorf("expected unsupported for prose, got %s", resp.Status)
+	}
+}
+
+func TestVerifyTableSubtotals(t *testing.T) {
+	source := "item,amount\nA,1\nB,2\nC,3\nD,4\n"
+	table := `| Item | Amount |
+|---|---|
+| A | 1 |
+| B | 2 |
+| Subtotal | 3 |
+| C | 3 |
+| D | 4 |
+| Subtotal | 7 |
+| Grand total | 10 |`
+
+	if resp := localVerifyTable(table, parseCSV(t, source), TableOptions{}); !resp.Verified {
+		t.Errorf("expected subtotals and grand total to add up, got %v", resp.Result)
+	}
+}
+
+func TestParseTableNumber(t *testing.T) {
+	tests := []struct {
+		in       string
+		value    float64
+		decimals int
+		ok       bool
+	}{
+		{"$1,234.50", 1234.5, 2, true},
+		{"(12)", -12, 0, true},
+		{"-3", -3, 0, true},
+		{"45%", 45, 0, true},
+		{"1.2M", 1.2e6, -5, true},
+		{"**17**", 17, 0, true},
+		{"North", 0, 0, false},
+	}
+	for _, tt := range tests {
+		v, d, ok := parseTableNumber(tt.in)
+		if ok != tt.ok || (ok && (v != tt.value || d != tt.decimals)) {
+			t.Errorf("parseTableNumber(%q) = %v, %d, %v", tt.in, v, d, ok)
+		}
+	}
+}
+
+func TestVerifyTableInvalidSource(t *testing.T) {
+	client := NewClient("test")
+	if _, err := client.VerifyTable(context.Background(), "a,b\n1,2\n", `a,"b`); err == nil {
+		t.Error("expected an error for invalid source CSV")
+	}
+}
+
+func parseCSV(t *testing.T, data string) [][]string {
+	t.Helper()
+	records, err := csv.NewReader(strings.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
@@ -0,0 +1,73 @@
+package qwed
+
+import "context"
+
+// ============================================================================
+// Generated Test Verification
+// ============================================================================
+
+// TestOutcome is what happened to a generated test in the sandbox.
+type TestOutcome string
+
+const (
+	TestPassed       TestOutcome = "passed"
+	TestFailed       TestOutcome = "failed"        // fails against the implementation
+	TestTautological TestOutcome = "tautological"  // passes whatever the implementation does
+	TestCompileError TestOutcome = "compile_error" // does not compile or import
+)
+
+// testLanguages are the languages the test engine runs tests in.
+var testLanguages = map[string]bool{
+	"python": true, "py": true,
+	"javascript": true, "js": true,
+	"typescript": true, "ts": true,
+	"java": true,
+	"go":   true,
+}
+
+// GeneratedTest is the verdict on one test from VerifyTests.
+type GeneratedTest struct {
+	Name    string      `json:"name"`
+	Outcome TestOutcome `json:"outcome"`
+	Line    int         `json:"line,omitempty"`    // 1-based line of the test in the test source
+	Message string      `json:"message,omitempty"` // assertion, compiler or mutation report
+}
+
+// VerifyTests runs generated unit tests against implementation in the
+// sandbox, for validating model-written tests before merging them. Tests
+// that do not compile, fail against the implementation, or are
+// tautological (they still pass when the implementation is mutated, e.g.
+// assert True or comparing a value with itself) are reported. The response
+// is verified when every test passes and none is tautological; use
+// GeneratedTests to decode the verdict on each.
+func (c *Client) VerifyTests(ctx context.Context, implementation, tests, language string, callOpts ...CallOption) (*VerificationResponse, error) {
+	return c.VerifyTestsWithOptions(ctx, implementation, tests, language, nil, callOpts...)
+}
+
+// VerifyTestsWithOptions runs generated unit tests with custom options.
+func (c *Client) VerifyTestsWithOptions(ctx context.Context, implementation, tests, language string, opts *RequestOptions, callOpts ...CallOption) (*VerificationResponse, error) {
+	req := map[string]interface{}{
+		"implementation": implementation,
+		"tests":          tests,
+		"language":       language,
+	}
+
+	if opts = c.requestOptions(opts); opts != nil {
+		req["options"] = opts
+	}
+
+	key := CacheKey(TypeTests, language, contentHash([]byte(implementation)), contentHash([]byte(tests)), optionsKey(opts))
+	return c.verify(ctx, "VerifyTests", TypeTests, key, req, callOpts...)
+}
+
+// GeneratedTests extracts the verdict on each test from a VerifyTests
+// response, in the order the tests appear in the test source.
+func GeneratedTests(resp *VerificationResponse) []GeneratedTest {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+
+	var tests []GeneratedTest
+	decodeResult(resp.Result["tests"], &tests)
+	return tests
+}
@@ -0,0 +1,75 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestVerifyTests(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/tests" {
+			t.Errorf("expected path /verify/tests, got %s", r.URL.Path)
+		}
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["implementation"] != "def add(a, b):\n    return a + b\n" || req["language"] != "python" || req["tests"] == "" {
+			t.Errorf("unexpected request: %+v", req)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "FAILED",
+			"verified": false,
+			"engine":   "tests",
+			"result": map[string]interface{}{
+				"tests": []map[string]interface{}{
+					{"name": "test_add", "outcome": "passed", "line": 3},
+					{"name": "test_add_negative", "outcome": "failed", "line": 6, "message": "assert add(-1, -1) == 0"},
+					{"name": "test_add_type", "outcome": "tautological", "line": 9, "message": "passes with add replaced by a constant"},
+					{"name": "test_add_float", "outcome": "compile_error", "line": 12, "message": "NameError: name 'approx' is not defined"},
+				},
+			},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyTests(context.Background(), "def add(a, b):\n    return a + b\n", "from impl import add\n\ndef test_add(): ...", "python")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Verified {
+		t.Error("expected broken tests to fail verification")
+	}
+
+	tests := GeneratedTests(resp)
+	want := []TestOutcome{TestPassed, TestFailed, TestTautological, TestCompileError}
+	if len(tests) != len(want) {
+		t.Fatalf("expected %d tests, got %+v", len(want), tests)
+	}
+	for i, test := range tests {
+		if test.Outcome != want[i] {
+			t.Errorf("test %s: outcome %s, want %s", test.Name, test.Outcome, want[i])
+		}
+	}
+	if tests[1].Line != 6 || tests[1].Message == "" {
+		t.Errorf("unexpected failure details: %+v", tests[1])
+	}
+	if GeneratedTests(&VerificationResponse{}) != nil {
+		t.Error("expected no tests without a result")
+	}
+}
+
+func TestVerifyTestsStrictValidation(t *testing.T) {
+	client := NewClient("test-key", WithBaseURL("http://127.0.0.1:1"), WithStrictValidation())
+	ctx := context.Background()
+
+	if _, err := client.VerifyTests(ctx, "func Add(a, b int) int { return a + b }", "", "go"); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest without tests, got %v", err)
+	}
+	if _, err := client.VerifyTests(ctx, "SELECT 1", "SELECT 1", "sql"); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for an unsupported language, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package qwed
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// ============================================================================
+// TLS
+// ============================================================================
+
+// WithTLSConfig sets the TLS configuration used to connect to the API, for
+// deployments that need settings the other TLS options do not cover. The
+// config is cloned; WithClientCertificate and WithCACert applied after it
+// add to the clone.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.tlsConfig = config.Clone()
+	}
+}
+
+// WithClientCertificate authenticates the client with the certificate and
+// private key in the given PEM files, for deployments requiring mutual TLS.
+// If the files cannot be loaded, every request fails with the error.
+func WithClientCertificate(certFile, keyFile string) ClientOption {
+	return func(c *Client) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			c.optionErr = fmt.Errorf("failed to load client certificate: %w", err)
+			return
+		}
+		config := c.tlsSettings()
+		config.Certificates = append(config.Certificates, cert)
+	}
+}
+
+// WithCACert trusts the certificate authorities in the PEM file caFile, for
+// APIs served with certificates from an internal CA. They are trusted in
+// addition to the system roots. If the file cannot be loaded, every request
+// fails with the error.
+func WithCACert(caFile string) ClientOption {
+	return func(c *Client) {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			c.optionErr = fmt.Errorf("failed to read CA certificate: %w", err)
+			return
+		}
+
+		config := c.tlsSettings()
+		if config.RootCAs != nil {
+			config.RootCAs = config.RootCAs.Clone()
+		} else if config.RootCAs, err = x509.SystemCertPool(); err != nil {
+			config.RootCAs = x509.NewCertPool()
+		}
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			c.optionErr = fmt.Errorf("failed to load CA certificate: no certificates in %s", caFile)
+		}
+	}
+}
+
+// tlsSettings returns the TLS configuration being built by the options.
+func (c *Client) tlsSettings() *tls.Config {
+	if c.tlsConfig == nil {
+		c.tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return c.tlsConfig
+}
@@ -0,0 +1,177 @@
+package qwed
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCert is a certificate and key, signed by parent or self-signed.
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	der  []byte
+}
+
+func newTestCert(t *testing.T, template *x509.Certificate, parent *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key, der: der}
+}
+
+// writePEM writes the certificate and its key to PEM files in dir.
+func (c *testCert) writePEM(t *testing.T, dir, name string) (certFile, keyFile string) {
+	t.Helper()
+	certFile = filepath.Join(dir, name+".crt")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile = filepath.Join(dir, name+".key")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.der}, PrivateKey: c.key}
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "internal CA"},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}, nil)
+	serverCert := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "qwed.internal"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, ca)
+	clientCert := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "billing-service"},
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != "billing-service" {
+			t.Error("expected the client certificate")
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert.tlsCertificate()},
+		ClientCAs:    clientCAs,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile, _ := ca.writePEM(t, dir, "ca")
+	certFile, keyFile := clientCert.writePEM(t, dir, "client")
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithCACert(caFile), WithClientCertificate(certFile, keyFile))
+	if _, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Without the client certificate the server refuses the handshake.
+	client = NewClient("test-key", WithBaseURL(server.URL), WithCACert(caFile))
+	if _, err := client.VerifyMath(context.Background(), "2+2=4"); err == nil {
+		t.Error("expected the handshake to fail without a client certificate")
+	}
+
+	// WithTLSConfig takes a full configuration.
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	client = NewClient("test-key", WithBaseURL(server.URL), WithTLSConfig(&tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{clientCert.tlsCertificate()},
+	}))
+	if _, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil {
+		t.Errorf("unexpected error with a TLS config: %v", err)
+	}
+}
+
+func TestTLSOptionErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.crt")
+	os.WriteFile(notPEM, []byte("not a certificate"), 0o600)
+
+	tests := []struct {
+		name string
+		opt  ClientOption
+		want string
+	}{
+		{"missing certificate", WithClientCertificate(filepath.Join(dir, "missing.crt"), filepath.Join(dir, "missing.key")), "failed to load client certificate"},
+		{"missing CA", WithCACert(filepath.Join(dir, "missing.crt")), "failed to read CA certificate"},
+		{"invalid CA", WithCACert(notPEM), "no certificates"},
+	}
+	for _, tt := range tests {
+		client := NewClient("test-key", WithBaseURL("https://127.0.0.1:1"), tt.opt)
+		if _, err := client.VerifyMath(context.Background(), "2+2=4"); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected %q error, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestTLSLeavesHTTPClientUntouched(t *testing.T) {
+	custom := &http.Client{Timeout: time.Second}
+	client := NewClient("test-key", WithHTTPClient(custom), WithTLSConfig(&tls.Config{ServerName: "qwed.internal"}))
+	if custom.Transport != nil {
+		t.Error("expected the caller's HTTP client not to be modified")
+	}
+	transport, ok := client.httpClient.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig.ServerName != "qwed.internal" || client.httpClient.Timeout != time.Second {
+		t.Errorf("expected a copy of the HTTP client with the TLS config, got %+v", client.httpClient)
+	}
+
+	type roundTripper struct{ http.RoundTripper }
+	client = NewClient("test-key", WithHTTPClient(&http.Client{Transport: roundTripper{}}), WithTLSConfig(&tls.Config{}))
+	if _, err := client.Health(context.Background()); err == nil || !strings.Contains(err.Error(), "not *http.Transport") {
+		t.Errorf("expected an error for a custom transport, got %v", err)
+	}
+}
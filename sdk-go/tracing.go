@@ -0,0 +1,88 @@
+package qwed
+
+import (
+	"context"
+	"time"
+)
+
+// ============================================================================
+// Tracing
+// ============================================================================
+
+// TracerName is the instrumentation name passed to TracerProvider.Tracer.
+const TracerName = "github.com/QWED-AI/qwed-verification/sdk-go"
+
+// TracerProvider creates tracers. It mirrors the subset of the OpenTelemetry
+// trace API the client needs, so the SDK stays dependency-free; see
+// the README for a ten-line adapter around an OpenTelemetry TracerProvider.
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+// Tracer starts spans.
+type Tracer interface {
+	Start(ctx context.Context, spanName string) (context.Context, Span)
+}
+
+// Span is a single traced operation.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Attribute is a key/value pair attached to a span.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Span attribute keys recorded by the client.
+const (
+	AttrEngine     = "qwed.engine"
+	AttrVerified   = "qwed.verified"
+	AttrStatus     = "qwed.status"
+	AttrLatencyMs  = "qwed.latency_ms"
+	AttrStatusCode = "http.status_code"
+)
+
+// WithTracerProvider creates a span named "qwed.<Method>" for every API call.
+func WithTracerProvider(tp TracerProvider) ClientOption {
+	return func(c *Client) {
+		c.tracer = tp.Tracer(TracerName)
+	}
+}
+
+// startSpan begins a span for op and returns a function that records the
+// outcome and ends it. Without a tracer both are no-ops.
+func (c *Client) startSpan(ctx context.Context, op string, engine VerificationType) (context.Context, func(*VerificationResponse, error)) {
+	if c.tracer == nil {
+		return ctx, func(*VerificationResponse, error) {}
+	}
+
+	start := time.Now()
+	ctx, span := c.tracer.Start(ctx, "qwed."+op)
+	if engine != "" {
+		span.SetAttributes(Attribute{AttrEngine, string(engine)})
+	}
+
+	return ctx, func(resp *VerificationResponse, err error) {
+		defer span.End()
+
+		span.SetAttributes(Attribute{AttrLatencyMs, float64(time.Since(start).Microseconds()) / 1000})
+		if code := statusCode(err); code != 0 {
+			span.SetAttributes(Attribute{AttrStatusCode, code})
+		}
+		if err != nil {
+			span.RecordError(err)
+			return
+		}
+
+		if resp != nil {
+			span.SetAttributes(
+				Attribute{AttrVerified, resp.Verified},
+				Attribute{AttrStatus, string(resp.Status)},
+			)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+)
+
+type recordedSpan struct {
+	name  string
+	attrs map[string]interface{}
+	err   error
+	ended bool
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (r *recordingTracer) Tracer(name string) Tracer { return r }
+
+func (r *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordedSpan{name: name, attrs: map[string]interface{}{}}
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+	return ctx, span
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...Attribute) {
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) { s.err = err }
+func (s *recordedSpan) End()                  { s.ended = true }
+
+func TestTracingSpans(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/verify/sql" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		json.NewEncoder(w).Encode(VerificationResponse{Status: StatusVerified, Verified: true})
+	})
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	client := NewClient("test-key", WithBaseURL(server.URL), WithTracerProvider(tracer))
+
+	client.VerifyMath(context.Background(), "2 + 2 = 4")
+	client.VerifySQL(context.Background(), "SELECT 1", "", "postgresql")
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(tracer.spans))
+	}
+
+	math := tracer.spans[0]
+	if math.name != "qwed.VerifyMath" || !math.ended {
+		t.Errorf("unexpected math span: %+v", math)
+	}
+	if math.attrs[AttrEngine] != "math" || math.attrs[AttrVerified] != true || math.attrs[AttrStatusCode] != 200 {
+		t.Errorf("unexpected math attributes: %v", math.attrs)
+	}
+	if _, ok := math.attrs[AttrLatencyMs]; !ok {
+		t.Error("expected latency attribute")
+	}
+
+	sql := tracer.spans[1]
+	if sql.err == nil || sql.attrs[AttrStatusCode] != http.StatusBadGateway {
+		t.Errorf("expected recorded error with 502, got %+v", sql)
+	}
+}
@@ -0,0 +1,267 @@
+package qwed
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ============================================================================
+// Training Data Export
+// ============================================================================
+
+// TrainingSchemaVersion is the version of the TrainingExample format,
+// written in every record.
+const TrainingSchemaVersion = 1
+
+// TrainingSchema is the JSON Schema of one line written by
+// TrainingExporter, for data pipelines that validate their inputs.
+const TrainingSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "https://qwedai.com/schemas/training-example/v1.json",
+  "title": "QWED training example",
+  "type": "object",
+  "required": ["schema_version", "id", "timestamp", "engine", "output", "verdict"],
+  "properties": {
+    "schema_version": {"const": 1},
+    "id": {"type": "string", "description": "sha256 of engine, input, context and output, for deduplication"},
+    "timestamp": {"type": "string", "format": "date-time"},
+    "engine": {"type": "string"},
+    "input": {"type": "string", "description": "the prompt the output answers, if known"},
+    "context": {"type": "string", "description": "source text, schema or other context the output was checked against"},
+    "output": {"type": "string", "description": "the LLM output that was verified"},
+    "verdict": {"enum": ["verified", "refuted", "inconclusive"]},
+    "status": {"type": "string"},
+    "reason": {"type": "string", "description": "why verification failed or was inconclusive"}
+  }
+}`
+
+// TrainingExample is a verification outcome labeled for training, such as
+// a model that corrects the outputs QWED refutes. Text fields are masked
+// for PII before export.
+type TrainingExample struct {
+	SchemaVersion int              `json:"schema_version"`
+	ID            string           `json:"id"`
+	Timestamp     time.Time        `json:"timestamp"`
+	Engine        VerificationType `json:"engine"`
+	Input         string           `json:"input,omitempty"`
+	Context       string           `json:"context,omitempty"`
+	Output        string           `json:"output"`
+	Verdict       Verdict          `json:"verdict"`
+	Status        Status           `json:"status,omitempty"`
+	Reason        string           `json:"reason,omitempty"`
+}
+
+// TrainingExporter writes verification outcomes as JSONL training
+// examples, one TrainingExample per line. It is safe for concurrent use.
+type TrainingExporter struct {
+	rate     float64
+	mask     func(string) string
+	verdicts map[Verdict]bool
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// TrainingOption configures a TrainingExporter.
+type TrainingOption func(*TrainingExporter)
+
+// WithTrainingSampleRate exports a fraction rate (0 to 1) of outcomes.
+// Defaults to 1.
+func WithTrainingSampleRate(rate float64) TrainingOption {
+	return func(e *TrainingExporter) {
+		e.rate = rate
+	}
+}
+
+// WithTrainingMasker replaces MaskPII as the function applied to every text
+// field, for example to add masking of customer IDs.
+func WithTrainingMasker(mask func(string) string) TrainingOption {
+	return func(e *TrainingExporter) {
+		e.mask = mask
+	}
+}
+
+// WithTrainingVerdicts exports only outcomes with the given verdicts, such
+// as VerdictRefuted for a correction model. By default all are exported.
+func WithTrainingVerdicts(verdicts ...Verdict) TrainingOption {
+	return func(e *TrainingExporter) {
+		e.verdicts = make(map[Verdict]bool, len(verdicts))
+		for _, v := range verdicts {
+			e.verdicts[v] = true
+		}
+	}
+}
+
+// NewTrainingExporter creates an exporter writing JSONL to w.
+func NewTrainingExporter(w io.Writer, opts ...TrainingOption) *TrainingExporter {
+	e := &TrainingExporter{rate: 1, mask: MaskPII, enc: json.NewEncoder(w)}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+type trainingInputKey struct{}
+
+// WithTrainingInput attaches the prompt that produced the output being
+// verified, so the exporter's interceptor can record it.
+func WithTrainingInput(ctx context.Context, prompt string) context.Context {
+	return context.WithValue(ctx, trainingInputKey{}, prompt)
+}
+
+// Interceptor returns an interceptor exporting every verification call's
+// outcome. The verified text (expression, code, claim or query) is the
+// output; the prompt comes from WithTrainingInput. Calls that return an
+// error are not exported, and write failures are ignored so exporting
+// never breaks verification.
+//
+//	exporter := qwed.NewTrainingExporter(f, qwed.WithTrainingSampleRate(0.1))
+//	client := qwed.NewClient(key, qwed.WithInterceptor(exporter.Interceptor()))
+func (e *TrainingExporter) Interceptor() Interceptor {
+	return func(ctx context.Context, req *Request, next Invoker) (*VerificationResponse, error) {
+		resp, err := next(ctx, req)
+		if err == nil && resp != nil && resp.Status != StatusShadow {
+			output, source := trainingFields(req.Body)
+			input, _ := ctx.Value(trainingInputKey{}).(string)
+			e.write(TrainingExample{Engine: req.Engine, Input: input, Context: source, Output: output}, resp)
+		}
+		return resp, err
+	}
+}
+
+// Record exports the verification of output, produced by an LLM for input,
+// for outcomes checked outside the client, such as with a local engine.
+// Sampled-out and filtered outcomes return nil without writing.
+func (e *TrainingExporter) Record(input, output string, resp *VerificationResponse) error {
+	if resp == nil {
+		return fmt.Errorf("no verification response to record")
+	}
+	return e.write(TrainingExample{Engine: VerificationType(resp.Engine), Input: input, Output: output}, resp)
+}
+
+// write labels ex with resp, masks and writes it.
+func (e *TrainingExporter) write(ex TrainingExample, resp *VerificationResponse) error {
+	ex.Verdict = resp.Verdict()
+	if e.verdicts != nil && !e.verdicts[ex.Verdict] {
+		return nil
+	}
+	if e.rate < 1 && rand.Float64() >= e.rate {
+		return nil
+	}
+
+	ex.SchemaVersion = TrainingSchemaVersion
+	ex.Timestamp = time.Now().UTC()
+	ex.Status = resp.Status
+	ex.Reason = failureReason(resp)
+	if e.mask != nil {
+		ex.Input, ex.Context, ex.Output, ex.Reason = e.mask(ex.Input), e.mask(ex.Context), e.mask(ex.Output), e.mask(ex.Reason)
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{string(ex.Engine), ex.Input, ex.Context, ex.Output}, "\x00")))
+	ex.ID = hex.EncodeToString(sum[:16])
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err := e.enc.Encode(ex); err != nil {
+		return fmt.Errorf("failed to write training example: %w", err)
+	}
+	return nil
+}
+
+// trainingFields extracts the verified text and its context from a
+// request body.
+func trainingFields(body interface{}) (output, source string) {
+	var fields map[string]interface{}
+	if !decodeResult(body, &fields) {
+		return "", ""
+	}
+	pick := func(names ...string) string {
+		for _, name := range names {
+			if s, ok := fields[name].(string); ok && s != "" {
+				return s
+			}
+		}
+		return ""
+	}
+	return pick("expression", "code", "claim", "query", "json"), pick("context", "schema_ddl", "schema")
+}
+
+// failureReason explains a response that is not verified.
+func failureReason(resp *VerificationResponse) string {
+	if resp.Verdict() == VerdictVerified {
+		return ""
+	}
+	if resp.Error != nil && resp.Error.Message != "" {
+		return resp.Error.Message
+	}
+	for _, key := range []string{"reason", "error", "explanation", "message"} {
+		if s, ok := resp.Result[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	if reason := resp.InconclusiveReason(); reason != "" {
+		return string(reason)
+	}
+	return ""
+}
+
+// ============================================================================
+// PII Masking
+// ============================================================================
+
+var piiPatterns = []struct {
+	re          *regexp.Regexp
+	placeholder string
+	valid       func(string) bool
+}{
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`), "[EMAIL]", nil},
+	{regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){3,7}(?: ?[A-Z0-9]{1,4})?\b`), "[IBAN]", nil},
+	{regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), "[CARD]", luhnValid},
+	{regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), "[SSN]", nil},
+	{regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?(?:\(\d{3}\)|\b\d{3})[ .-]\d{3}[ .-]\d{4}\b`), "[PHONE]", nil},
+	{regexp.MustCompile(`\b(?:(?:25[0-5]|2[0-4]\d|1?\d?\d)\.){3}(?:25[0-5]|2[0-4]\d|1?\d?\d)\b`), "[IP]", nil},
+}
+
+// MaskPII replaces email addresses, IBANs, payment card numbers,
+// US Social Security numbers, phone numbers and IPv4 addresses in s with
+// placeholders such as "[EMAIL]". Card numbers must pass the Luhn check,
+// and phone numbers need separators, so plain numbers in math and code
+// are kept.
+func MaskPII(s string) string {
+	for _, p := range piiPatterns {
+		s = p.re.ReplaceAllStringFunc(s, func(m string) string {
+			if p.valid != nil && !p.valid(m) {
+				return m
+			}
+			return p.placeholder
+		})
+	}
+	return s
+}
+
+// luhnValid reports whether the digits of s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] < '0' || s[i] > '9' {
+			continue
+		}
+		d := int(s[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
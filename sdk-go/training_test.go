@@ -0,0 +1,126 @@
+package qwed
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func readTrainingExamples(t *testing.T, data []byte) []TrainingExample {
+	t.Helper()
+	var examples []TrainingExample
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var ex TrainingExample
+		if err := json.Unmarshal(scanner.Bytes(), &ex); err != nil {
+			t.Fatalf("invalid JSONL line %q: %v", scanner.Text(), err)
+		}
+		examples = append(examples, ex)
+	}
+	return examples
+}
+
+func TestTrainingExporterInterceptor(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["claim"] != "" {
+			w.Write([]byte(`{"status":"FAILED","verified":false,"result":{"reason":"context says 1887, not 1889"}}`))
+			return
+		}
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	})
+	defer server.Close()
+
+	var buf bytes.Buffer
+	exporter := NewTrainingExporter(&buf)
+	client := NewClient("test-key", WithBaseURL(server.URL), WithInterceptor(exporter.Interceptor()))
+
+	ctx := WithTrainingInput(context.Background(), "When was the tower finished? Reply to jane@example.com")
+	client.VerifyFact(ctx, "The tower was finished in 1889.", "Construction finished in 1887.")
+	client.VerifyMath(context.Background(), "2+2=4")
+
+	examples := readTrainingExamples(t, buf.Bytes())
+	if len(examples) != 2 {
+		t.Fatalf("expected 2 examples, got %d", len(examples))
+	}
+	fact := examples[0]
+	if fact.Engine != TypeFact || fact.Output != "The tower was finished in 1889." || fact.Context != "Construction finished in 1887." {
+		t.Errorf("unexpected fact example: %+v", fact)
+	}
+	if fact.Input != "When was the tower finished? Reply to [EMAIL]" {
+		t.Errorf("expected the masked prompt as input, got %q", fact.Input)
+	}
+	if fact.Verdict != VerdictRefuted || fact.Reason != "context says 1887, not 1889" {
+		t.Errorf("expected a refuted example with its reason, got %+v", fact)
+	}
+	if fact.SchemaVersion != TrainingSchemaVersion || len(fact.ID) != 32 || fact.Timestamp.IsZero() {
+		t.Errorf("expected schema version, ID and timestamp, got %+v", fact)
+	}
+	if math := examples[1]; math.Output != "2+2=4" || math.Verdict != VerdictVerified || math.Reason != "" {
+		t.Errorf("unexpected math example: %+v", math)
+	}
+}
+
+func TestTrainingExporterOptions(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := NewTrainingExporter(&buf,
+		WithTrainingVerdicts(VerdictRefuted),
+		WithTrainingMasker(strings.ToUpper))
+
+	exporter.Record("prompt", "verified output", &VerificationResponse{Status: StatusVerified, Verified: true})
+	exporter.Record("prompt", "wrong output", &VerificationResponse{Status: StatusFailed, Engine: "local-units"})
+	if err := exporter.Record("prompt", "x", nil); err == nil {
+		t.Error("expected an error without a response")
+	}
+
+	examples := readTrainingExamples(t, buf.Bytes())
+	if len(examples) != 1 || examples[0].Output != "WRONG OUTPUT" || examples[0].Engine != "local-units" {
+		t.Errorf("expected only the refuted example, masked, got %+v", examples)
+	}
+
+	buf.Reset()
+	exporter = NewTrainingExporter(&buf, WithTrainingSampleRate(0))
+	exporter.Record("prompt", "output", &VerificationResponse{Status: StatusFailed})
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing exported at rate 0, got %s", buf.String())
+	}
+}
+
+func TestTrainingSchema(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(TrainingSchema), &schema); err != nil {
+		t.Fatalf("TrainingSchema is not valid JSON: %v", err)
+	}
+	data, _ := json.Marshal(TrainingExample{})
+	var fields map[string]interface{}
+	json.Unmarshal(data, &fields)
+	properties := schema["properties"].(map[string]interface{})
+	for name := range fields {
+		if properties[name] == nil {
+			t.Errorf("field %s is missing from TrainingSchema", name)
+		}
+	}
+}
+
+func TestMaskPII(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"mail bob.smith+x@corp.example.org now", "mail [EMAIL] now"},
+		{"card 4111 1111 1111 1111 ok", "card [CARD] ok"},
+		{"not a card 1234567890123456", "not a card 1234567890123456"},
+		{"ssn 123-45-6789", "ssn [SSN]"},
+		{"call (555) 123-4567 or +1 555.123.4567", "call [PHONE] or [PHONE]"},
+		{"server 192.168.1.20 down", "server [IP] down"},
+		{"iban DE89 3704 0044 0532 0130 00", "iban [IBAN]"},
+		{"2+2=4 and 12345 * 678 = 8369910", "2+2=4 and 12345 * 678 = 8369910"},
+	}
+	for _, tt := range tests {
+		if got := MaskPII(tt.in); got != tt.want {
+			t.Errorf("MaskPII(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
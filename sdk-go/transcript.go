@@ -0,0 +1,317 @@
+package qwed
+
+import (
+	"context"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Speech Transcripts
+// ============================================================================
+
+// AuditTranscript audits the numeric and factual claims in a speech
+// transcript, such as the output of a voice agent. The transcript is
+// rewritten with NormalizeTranscript so spoken numbers, percentages and
+// arithmetic reach the engines in written form, then checked with
+// AuditAnswer. Claim offsets in the report refer to the normalized text,
+// which is the report's Answer.
+func (c *Client) AuditTranscript(ctx context.Context, transcript, factContext string, opts *AuditOptions) (*AnswerAudit, error) {
+	return c.AuditAnswer(ctx, "", NormalizeTranscript(transcript), factContext, opts)
+}
+
+// NormalizeTranscript rewrites spoken numbers in a speech transcript in
+// written form: "twenty three point five million" becomes "23500000",
+// "twelve per cent" becomes "12%", "forty dollars" becomes "$40", and
+// "plus", "minus", "times", "divided by" and "equals" between numbers
+// become operators, so "six times seven equals forty two" reads
+// "6 * 7 = 42". "is" becomes "=" after an arithmetic expression, as in
+// "ten percent of two hundred is twenty". Whitespace is collapsed to single
+// spaces.
+func NormalizeTranscript(text string) string {
+	tokens := strings.Fields(text)
+	out := make([]transcriptToken, 0, len(tokens))
+	for i := 0; i < len(tokens); {
+		if value, n := spokenNumber(tokens, i); n > 0 {
+			_, trailing := splitTrailing(tokens[i+n-1])
+			out = append(out, transcriptToken{text: value, number: true, trailing: trailing})
+			i += n
+			continue
+		}
+		word, trailing := splitTrailing(tokens[i])
+		out = append(out, transcriptToken{text: word, trailing: trailing})
+		i++
+	}
+
+	out = spokenOperators(out)
+	words := make([]string, len(out))
+	for i, t := range out {
+		words[i] = t.text + t.trailing
+	}
+	return strings.Join(words, " ")
+}
+
+// transcriptToken is a word of a transcript being normalized.
+type transcriptToken struct {
+	text     string
+	trailing string // punctuation following the word
+	number   bool   // text is a number, possibly with a unit
+	operator bool   // text is an arithmetic operator
+}
+
+var (
+	spokenUnits = map[string]int{
+		"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+		"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+		"thirteen": 13, "fourteen": 14, "fifteen": 15, "sixteen": 16, "seventeen": 17,
+		"eighteen": 18, "nineteen": 19,
+	}
+	spokenTens = map[string]int{
+		"twenty": 20, "thirty": 30, "forty": 40, "fifty": 50,
+		"sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+	}
+	spokenScales = map[string]float64{
+		"thousand": 1e3, "million": 1e6, "billion": 1e9, "trillion": 1e12,
+	}
+	spokenCurrencies = map[string]string{
+		"dollar": "$", "dollars": "$", "euro": "€", "euros": "€",
+	}
+	// loneOneBefore are words after which "one" is a pronoun, as in "no
+	// one", rather than a number.
+	loneOneBefore = wordSet("no any every some anyone someone the this that which")
+)
+
+// splitTrailing splits trailing punctuation off a word.
+func splitTrailing(word string) (string, string) {
+	core := strings.TrimRight(word, ".,;:!?")
+	return core, word[len(core):]
+}
+
+// spokenNumber parses the number spoken by the words starting at
+// tokens[i], returning it in digits and the number of words it spans, or
+// zero words if there is none. A number ends at a word followed by
+// punctuation. A leading number in digits may be scaled, as in
+// "3.5 million".
+func spokenNumber(tokens []string, i int) (string, int) {
+	var (
+		total, current float64
+		digits         string // decimal digits after "point"
+		words          int    // number words consumed
+		n              int    // tokens consumed
+		scaled         bool
+		last           string
+	)
+
+	for j := i; j < len(tokens); j++ {
+		raw, trailing := splitTrailing(tokens[j])
+		word := strings.ToLower(raw)
+		parts := strings.Split(word, "-") // "twenty-three"
+
+		ok := true
+		switch {
+		case j == i && isDecimal(word):
+			current, _ = strconv.ParseFloat(strings.ReplaceAll(word, ",", ""), 64)
+			if trailing != "" || j+1 >= len(tokens) || spokenScales[strings.ToLower(strings.TrimRight(tokens[j+1], ".,;:!?"))] == 0 {
+				return "", 0 // plain digits need no rewriting
+			}
+		case word == "a" && j == i:
+			next := ""
+			if j+1 < len(tokens) {
+				next = strings.ToLower(tokens[j+1])
+			}
+			if next != "hundred" && spokenScales[next] == 0 {
+				ok = false
+				break
+			}
+			current = 1
+		case word == "and" && words > 0 && j+1 < len(tokens) && (scaled || last == "hundred"):
+			next := strings.ToLower(strings.TrimRight(tokens[j+1], ".,;:!?"))
+			if _, unit := spokenUnits[next]; !unit && spokenTens[next] == 0 {
+				ok = false
+			}
+		case word == "point" && words > 0 && digits == "" && j+1 < len(tokens):
+			for j+1 < len(tokens) {
+				raw, t := splitTrailing(tokens[j+1])
+				d, unit := spokenUnits[strings.ToLower(raw)]
+				if !unit || d > 9 {
+					break
+				}
+				digits += strconv.Itoa(d)
+				j++
+				if t != "" {
+					trailing = t
+					break
+				}
+			}
+			if digits == "" {
+				ok = false
+			}
+		case word == "hundred" && (words > 0 || current > 0):
+			current *= 100
+			if current == 0 {
+				current = 100
+			}
+		case spokenScales[word] > 0 && (words > 0 || current > 0):
+			if digits != "" {
+				current, _ = strconv.ParseFloat(strconv.FormatFloat(current, 'f', -1, 64)+"."+digits, 64)
+				digits = ""
+			}
+			total += current * spokenScales[word]
+			current = 0
+			scaled = true
+		default:
+			value, valid := spokenWords(parts)
+			if !valid || digits != "" || (words > 0 && !followsNumber(last, value)) {
+				ok = false
+				break
+			}
+			current += float64(value)
+		}
+		if !ok {
+			break
+		}
+
+		words++
+		last = word
+		n = j - i + 1
+		if trailing != "" {
+			break
+		}
+	}
+
+	if words == 0 || last == "and" || last == "a" {
+		return "", 0
+	}
+	if words == 1 && last == "one" && i > 0 && loneOneBefore[strings.ToLower(tokens[i-1])] {
+		return "", 0
+	}
+
+	number := strconv.FormatFloat(total+current, 'f', -1, 64)
+	if digits != "" {
+		number += "." + digits
+	}
+	return number, n
+}
+
+// spokenWords parses "seven", "twenty" or "twenty-three".
+func spokenWords(parts []string) (int, bool) {
+	switch len(parts) {
+	case 1:
+		if v, ok := spokenUnits[parts[0]]; ok {
+			return v, true
+		}
+		v, ok := spokenTens[parts[0]]
+		return v, ok
+	case 2:
+		tens, unit := spokenTens[parts[0]], spokenUnits[parts[1]]
+		return tens + unit, tens > 0 && unit > 0 && unit < 10
+	}
+	return 0, false
+}
+
+// followsNumber reports whether value can follow the number word last in
+// one number, as "three" follows "twenty" but not "four".
+func followsNumber(last string, value int) bool {
+	switch {
+	case last == "hundred", last == "and", spokenScales[last] > 0:
+		return true
+	case spokenTens[last] > 0:
+		return value > 0 && value < 10
+	}
+	return false
+}
+
+// isDecimal reports whether word is a number in digits, such as "3.5" or
+// "1,200".
+func isDecimal(word string) bool {
+	_, err := strconv.ParseFloat(strings.ReplaceAll(word, ",", ""), 64)
+	return err == nil && word != "" && isDigit(word[0])
+}
+
+// spokenOperators rewrites percentages, currencies and arithmetic operators
+// around numbers.
+func spokenOperators(tokens []transcriptToken) []transcriptToken {
+	out := make([]transcriptToken, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		word := strings.ToLower(t.text)
+		prev := len(out) > 0 && out[len(out)-1].number && out[len(out)-1].trailing == ""
+
+		switch {
+		case prev && (word == "percent" || (word == "per" && i+1 < len(tokens) && strings.ToLower(tokens[i+1].text) == "cent")):
+			if word == "per" {
+				i++
+				t = tokens[i]
+			}
+			out[len(out)-1].text += "%"
+			out[len(out)-1].trailing = t.trailing
+			continue
+		case prev && spokenCurrencies[word] != "":
+			out[len(out)-1].text = spokenCurrencies[word] + out[len(out)-1].text
+			out[len(out)-1].trailing = t.trailing
+			continue
+		case prev && t.trailing == "":
+			op, n := spokenOperator(tokens[i:], out)
+			if op != "" && i+n < len(tokens) && tokens[i+n].number {
+				out = append(out, transcriptToken{text: op, operator: true})
+				i += n - 1
+				continue
+			}
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// spokenOperator matches an operator spoken at the start of tokens, given
+// the words before it, returning its symbol and the number of words it
+// spans.
+func spokenOperator(tokens []transcriptToken, before []transcriptToken) (string, int) {
+	word := strings.ToLower(tokens[0].text)
+	next := ""
+	if len(tokens) > 1 && tokens[0].trailing == "" {
+		next = strings.ToLower(tokens[1].text)
+	}
+
+	switch {
+	case word == "plus":
+		return "+", 1
+	case word == "minus":
+		return "-", 1
+	case word == "times":
+		return "*", 1
+	case (word == "multiplied" || word == "divided") && next == "by":
+		if word == "divided" {
+			return "/", 2
+		}
+		return "*", 2
+	case word == "equals":
+		return "=", 1
+	case word == "is" && arithmeticBefore(before):
+		if next == "equal" && len(tokens) > 2 && strings.ToLower(tokens[2].text) == "to" {
+			return "=", 3
+		}
+		return "=", 1
+	}
+	return "", 0
+}
+
+// arithmeticBefore reports whether the words before end in an arithmetic
+// expression, such as "6 * 7" or "10% of 200", rather than a lone number.
+func arithmeticBefore(before []transcriptToken) bool {
+	numbers, operators := 0, 0
+	for i := len(before) - 1; i >= 0; i-- {
+		t := before[i]
+		if i < len(before)-1 && t.trailing != "" {
+			break // the expression starts after punctuation
+		}
+		if t.number {
+			numbers++
+		} else if t.operator || (strings.ToLower(t.text) == "of" && numbers > 0) {
+			operators++
+		} else {
+			break
+		}
+	}
+	return numbers >= 2 && operators > 0
+}
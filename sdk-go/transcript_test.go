@@ -0,0 +1,65 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTranscript(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"revenue grew twelve per cent last year", "revenue grew 12% last year"},
+		{"we shipped twenty-three thousand four hundred and five units", "we shipped 23405 units"},
+		{"about three point five million users", "about 3500000 users"},
+		{"about 3.5 million users", "about 3500000 users"},
+		{"a hundred and twenty people", "120 people"},
+		{"the total is forty dollars.", "the total is $40."},
+		{"six times seven equals forty two", "6 * 7 = 42"},
+		{"one hundred divided by four is twenty five", "100 / 4 = 25"},
+		{"ten percent of two hundred is twenty", "10% of 200 = 20"},
+		{"nine minus two is equal to seven", "9 - 2 = 7"},
+		{"the score is ninety", "the score is 90"},
+		{"no one said zero point two five", "no one said 0.25"},
+		{"in twenty twenty four we grew", "in 20 24 we grew"},
+		{"five, six and seven", "5, 6 and 7"},
+		{"I have 42 apples", "I have 42 apples"},
+		{"a   b\n c", "a b c"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeTranscript(tt.in); got != tt.want {
+			t.Errorf("NormalizeTranscript(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAuditTranscript(t *testing.T) {
+	var expressions []string
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		json.NewDecoder(r.Body).Decode(&req)
+		expr := strings.TrimRight(req["expression"], ".")
+		expressions = append(expressions, expr)
+		verified := expr == "6 * 7 = 42"
+		json.NewEncoder(w).Encode(VerificationResponse{Status: map[bool]Status{true: StatusVerified, false: StatusFailed}[verified], Verified: verified})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	audit, err := client.AuditTranscript(context.Background(), "Six times seven equals forty two. Ten percent of two hundred is thirty.", "", &AuditOptions{Concurrency: 1})
+	if err != nil {
+		t.Fatalf("AuditTranscript() error = %v", err)
+	}
+	if audit.Answer != "6 * 7 = 42. 10% of 200 = 30." {
+		t.Errorf("Answer = %q, want the normalized transcript", audit.Answer)
+	}
+	if len(expressions) != 2 || expressions[1] != "10% of 200 = 30" {
+		t.Errorf("math engine got %q", expressions)
+	}
+	if audit.Verdict != AuditFail || audit.Verified != 1 || audit.Failed != 1 {
+		t.Errorf("audit = %+v, want one verified and one failed claim", audit)
+	}
+}
@@ -0,0 +1,413 @@
+package qwed
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ============================================================================
+// Answer Transforms
+// ============================================================================
+
+// TransformRule rewrites the claims of an audited answer that it matches,
+// for example to mark unverified sentences for the reader.
+type TransformRule struct {
+	// Name identifies the rule. Parsed rules are named by their source.
+	Name string
+
+	// Match selects the claims the rule applies to.
+	Match func(claim ClaimAudit) bool
+
+	// Rewrite returns the replacement for the claim's text.
+	Rewrite func(text string, claim ClaimAudit) string
+}
+
+// AnnotateUnverified returns a rule appending marker, separated by a space,
+// to every claim that was not verified.
+//
+//	shown := audit.Transform(qwed.AnnotateUnverified("⚠ unverified"))
+func AnnotateUnverified(marker string) TransformRule {
+	return TransformRule{
+		Name:  "annotate unverified",
+		Match: func(claim ClaimAudit) bool { return claimVerdict(claim) != VerdictVerified },
+		Rewrite: func(text string, claim ClaimAudit) string {
+			return text + " " + marker
+		},
+	}
+}
+
+// Transform applies rules to the answer and returns the rewritten text.
+// Each claim is rewritten by the first rule matching it; text outside
+// claims is kept unchanged.
+func (a *AnswerAudit) Transform(rules ...TransformRule) string {
+	claims := append([]ClaimAudit(nil), a.Claims...)
+	sort.Slice(claims, func(i, j int) bool { return claims[i].Claim.Start > claims[j].Claim.Start })
+
+	text := a.Answer
+	end := len(text)
+	for _, claim := range claims {
+		start, stop := claim.Claim.Start, claim.Claim.End
+		if start < 0 || stop > end || start > stop {
+			continue // overlapping or out of range
+		}
+		for _, rule := range rules {
+			if rule.Match != nil && rule.Match(claim) {
+				text = text[:start] + rule.Rewrite(text[start:stop], claim) + text[stop:]
+				break
+			}
+		}
+		end = start
+	}
+	return text
+}
+
+// claimVerdict returns the verdict of an audited claim. Claims that errored
+// are inconclusive.
+func claimVerdict(claim ClaimAudit) Verdict {
+	if claim.Error != "" {
+		return VerdictInconclusive
+	}
+	return claim.Response.Verdict()
+}
+
+// claimReason returns why an audited claim is inconclusive, or "".
+func claimReason(claim ClaimAudit) InconclusiveReason {
+	if claim.Error != "" {
+		return ReasonEngineError
+	}
+	return claim.Response.InconclusiveReason()
+}
+
+// ============================================================================
+// Transform Rule Language
+// ============================================================================
+
+// ParseTransformRules parses rules written one per line. Blank lines and
+// lines starting with # are ignored. See ParseTransformRule for the syntax.
+func ParseTransformRules(src string) ([]TransformRule, error) {
+	var rules []TransformRule
+	for i, line := range strings.Split(src, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := ParseTransformRule(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// ParseTransformRule parses a rule of the form "when CONDITION then ACTION":
+//
+//	when verdict == refuted then append " ⚠ unverified"
+//	when engine == math and confidence < 0.9 then wrap "[" "?]"
+//	when verdict == inconclusive and reason != timeout then replace "(unchecked: {text})"
+//	when not verified and engine == fact then remove
+//
+// Conditions compare the fields verdict (verified, refuted, inconclusive),
+// reason (an InconclusiveReason), engine, status, confidence (a number;
+// comparisons are false if the engine reported none) and verified (true or
+// false) with ==, !=, <, <=, > and >=, combined with and, or, not and
+// parentheses. A bare "verified" is the same as "verified == true".
+//
+// Actions are append TEXT, prepend TEXT, replace TEXT, wrap BEFORE AFTER
+// and remove. Quoted texts may use the placeholders {text}, {verdict},
+// {reason}, {engine} and {confidence}.
+func ParseTransformRule(expr string) (TransformRule, error) {
+	tokens, err := lexTransform(expr)
+	if err != nil {
+		return TransformRule{}, fmt.Errorf("transform rule %q: %w", expr, err)
+	}
+	p := &transformParser{tokens: tokens}
+
+	if !p.accept("when") {
+		return TransformRule{}, fmt.Errorf("transform rule %q: must start with \"when\"", expr)
+	}
+	cond, err := p.parseOr()
+	if err != nil {
+		return TransformRule{}, fmt.Errorf("transform rule %q: %w", expr, err)
+	}
+	if !p.accept("then") {
+		return TransformRule{}, fmt.Errorf("transform rule %q: expected \"then\" at %q", expr, p.peek().text)
+	}
+	rewrite, err := p.parseAction()
+	if err != nil {
+		return TransformRule{}, fmt.Errorf("transform rule %q: %w", expr, err)
+	}
+	if p.pos < len(p.tokens) {
+		return TransformRule{}, fmt.Errorf("transform rule %q: unexpected %q after action", expr, p.peek().text)
+	}
+	return TransformRule{Name: expr, Match: cond, Rewrite: rewrite}, nil
+}
+
+type transformToken struct {
+	text   string
+	quoted bool // a string literal, already unquoted
+}
+
+// lexTransform splits a rule into words, numbers, operators and string
+// literals.
+func lexTransform(expr string) ([]transformToken, error) {
+	var tokens []transformToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '"':
+			j := i + 1
+			for ; j < len(expr) && expr[j] != '"'; j++ {
+				if expr[j] == '\\' {
+					j++
+				}
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			s, err := strconv.Unquote(expr[i : j+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s: %w", expr[i:j+1], err)
+			}
+			tokens = append(tokens, transformToken{text: s, quoted: true})
+			i = j + 1
+		case c == '(' || c == ')':
+			tokens = append(tokens, transformToken{text: string(c)})
+			i++
+		case strings.ContainsRune("=!<>", rune(c)):
+			j := i + 1
+			if j < len(expr) && expr[j] == '=' {
+				j++
+			}
+			op := expr[i:j]
+			if op == "=" || op == "!" {
+				return nil, fmt.Errorf("unknown operator %q", op)
+			}
+			tokens = append(tokens, transformToken{text: op})
+			i = j
+		default:
+			j := i
+			for j < len(expr) && (isDigit(expr[j]) || expr[j] == '.' || expr[j] == '_' || expr[j] == '-' || unicode.IsLetter(rune(expr[j]))) {
+				j++
+			}
+			if j == i {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+			tokens = append(tokens, transformToken{text: expr[i:j]})
+			i = j
+		}
+	}
+	return tokens, nil
+}
+
+type transformParser struct {
+	tokens []transformToken
+	pos    int
+}
+
+func (p *transformParser) peek() transformToken {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return transformToken{}
+}
+
+func (p *transformParser) next() transformToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+// accept consumes the keyword word if it is next.
+func (p *transformParser) accept(word string) bool {
+	if t := p.peek(); !t.quoted && strings.EqualFold(t.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+type claimPredicate func(ClaimAudit) bool
+
+func (p *transformParser) parseOr() (claimPredicate, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(c ClaimAudit) bool { return l(c) || right(c) }
+	}
+	return left, nil
+}
+
+func (p *transformParser) parseAnd() (claimPredicate, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(c ClaimAudit) bool { return l(c) && right(c) }
+	}
+	return left, nil
+}
+
+func (p *transformParser) parseNot() (claimPredicate, error) {
+	if p.accept("not") {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(c ClaimAudit) bool { return !x(c) }, nil
+	}
+	if p.accept("(") {
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		return x, nil
+	}
+	return p.parseComparison()
+}
+
+// transformFields returns the string value of each comparable field.
+var transformFields = map[string]func(ClaimAudit) string{
+	"verdict": func(c ClaimAudit) string { return string(claimVerdict(c)) },
+	"reason":  func(c ClaimAudit) string { return string(claimReason(c)) },
+	"engine":  func(c ClaimAudit) string { return string(c.Claim.Type) },
+	"status": func(c ClaimAudit) string {
+		if c.Response == nil {
+			return ""
+		}
+		return string(c.Response.Status)
+	},
+	"verified": func(c ClaimAudit) string { return strconv.FormatBool(c.Verified) },
+}
+
+func (p *transformParser) parseComparison() (claimPredicate, error) {
+	field := p.next()
+	if field.quoted || field.text == "" {
+		return nil, fmt.Errorf("expected a field, got %q", field.text)
+	}
+	name := strings.ToLower(field.text)
+
+	op := p.peek().text
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.pos++
+	default:
+		if name == "verified" {
+			return func(c ClaimAudit) bool { return c.Verified }, nil
+		}
+		return nil, fmt.Errorf("expected a comparison after %q", field.text)
+	}
+	value := p.next()
+	if value.text == "" && !value.quoted {
+		return nil, fmt.Errorf("missing value after %s %s", field.text, op)
+	}
+
+	if name == "confidence" {
+		want, err := strconv.ParseFloat(value.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("confidence must be compared with a number, got %q", value.text)
+		}
+		return func(c ClaimAudit) bool {
+			if c.Response == nil {
+				return false
+			}
+			got, ok := confidence(c.Response)
+			return ok && compare(got, op, want)
+		}, nil
+	}
+
+	get, ok := transformFields[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown field %q", field.text)
+	}
+	want := strings.ToLower(value.text)
+	switch op {
+	case "==":
+		return func(c ClaimAudit) bool { return strings.ToLower(get(c)) == want }, nil
+	case "!=":
+		return func(c ClaimAudit) bool { return strings.ToLower(get(c)) != want }, nil
+	}
+	return nil, fmt.Errorf("%s only supports == and !=", field.text)
+}
+
+func (p *transformParser) parseAction() (func(string, ClaimAudit) string, error) {
+	action := strings.ToLower(p.next().text)
+	text := func() (string, error) {
+		t := p.next()
+		if !t.quoted {
+			return "", fmt.Errorf("%s needs a quoted text", action)
+		}
+		return t.text, nil
+	}
+
+	switch action {
+	case "remove":
+		return func(string, ClaimAudit) string { return "" }, nil
+	case "append", "prepend", "replace":
+		tmpl, err := text()
+		if err != nil {
+			return nil, err
+		}
+		return func(s string, c ClaimAudit) string {
+			expanded := expandTransform(tmpl, s, c)
+			switch action {
+			case "append":
+				return s + expanded
+			case "prepend":
+				return expanded + s
+			}
+			return expanded
+		}, nil
+	case "wrap":
+		before, err := text()
+		if err != nil {
+			return nil, err
+		}
+		after, err := text()
+		if err != nil {
+			return nil, err
+		}
+		return func(s string, c ClaimAudit) string {
+			return expandTransform(before, s, c) + s + expandTransform(after, s, c)
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown action %q", action)
+}
+
+// expandTransform substitutes the placeholders of an action text.
+func expandTransform(tmpl, text string, c ClaimAudit) string {
+	conf := ""
+	if c.Response != nil {
+		if v, ok := confidence(c.Response); ok {
+			conf = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	return strings.NewReplacer(
+		"{text}", text,
+		"{verdict}", string(claimVerdict(c)),
+		"{reason}", string(claimReason(c)),
+		"{engine}", string(c.Claim.Type),
+		"{confidence}", conf,
+	).Replace(tmpl)
+}
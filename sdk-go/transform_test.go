@@ -0,0 +1,109 @@
+package qwed
+
+import (
+	"strings"
+	"testing"
+)
+
+// transformAudit is an audit of a three-sentence answer: a verified math
+// claim, a refuted fact and a fact that timed out.
+func transformAudit() *AnswerAudit {
+	answer := "2 + 2 = 4. Paris is in Spain. Berlin has 4 million people."
+	claim := func(text string, typ VerificationType) AtomicClaim {
+		start := strings.Index(answer, text)
+		return AtomicClaim{Text: text, Start: start, End: start + len(text), Type: typ}
+	}
+	return &AnswerAudit{
+		Answer: answer,
+		Claims: []ClaimAudit{
+			{Claim: claim("2 + 2 = 4.", TypeMath), Verified: true, Response: &VerificationResponse{Verified: true, Status: StatusVerified, Result: map[string]interface{}{"confidence": 0.99}}},
+			{Claim: claim("Paris is in Spain.", TypeFact), Response: &VerificationResponse{Status: StatusFailed, Result: map[string]interface{}{"confidence": 0.97}}},
+			{Claim: claim("Berlin has 4 million people.", TypeFact), Response: &VerificationResponse{Status: StatusTimeout}},
+		},
+	}
+}
+
+func TestAnnotateUnverified(t *testing.T) {
+	got := transformAudit().Transform(AnnotateUnverified("⚠ unverified"))
+	want := "2 + 2 = 4. Paris is in Spain. ⚠ unverified Berlin has 4 million people. ⚠ unverified"
+	if got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestParseTransformRules(t *testing.T) {
+	rules, err := ParseTransformRules(`
+# mark refuted claims, flag unchecked ones
+when verdict == refuted then wrap "~~" "~~ (incorrect)"
+when verdict == inconclusive and reason == timeout then append " [{verdict}: {reason}]"
+when engine == math and confidence >= 0.9 then prepend "✓ "
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := transformAudit().Transform(rules...)
+	want := "✓ 2 + 2 = 4. ~~Paris is in Spain.~~ (incorrect) Berlin has 4 million people. [inconclusive: timeout]"
+	if got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestTransformRuleConditions(t *testing.T) {
+	audit := transformAudit()
+	tests := []struct {
+		cond string
+		want []bool // per claim
+	}{
+		{"verified", []bool{true, false, false}},
+		{"not verified", []bool{false, true, true}},
+		{"verified == false and engine == fact", []bool{false, true, true}},
+		{"confidence < 0.98", []bool{false, true, false}},
+		{`status != "TIMEOUT" and (verdict == verified or engine == fact)`, []bool{true, true, false}},
+		{"reason == timeout or verdict == REFUTED", []bool{false, true, true}},
+	}
+	for _, tt := range tests {
+		rule, err := ParseTransformRule("when " + tt.cond + " then remove")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.cond, err)
+		}
+		for i, claim := range audit.Claims {
+			if got := rule.Match(claim); got != tt.want[i] {
+				t.Errorf("%s: claim %d matched %v, want %v", tt.cond, i, got, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestTransformErrorClaims(t *testing.T) {
+	audit := &AnswerAudit{
+		Answer: "Water boils at 90 C.",
+		Claims: []ClaimAudit{{Claim: AtomicClaim{Text: "Water boils at 90 C.", End: 20, Type: TypeFact}, Error: "connection refused"}},
+	}
+	rule, err := ParseTransformRule(`when reason == engine_error then replace "({text} could not be checked)"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := audit.Transform(rule); got != "(Water boils at 90 C. could not be checked)" {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
+
+func TestParseTransformRuleErrors(t *testing.T) {
+	for _, expr := range []string{
+		"verdict == refuted then remove",
+		"when verdict == refuted",
+		"when verdict = refuted then remove",
+		"when colour == red then remove",
+		"when confidence < high then remove",
+		"when verdict < refuted then remove",
+		"when verdict == refuted then append marker",
+		"when verdict == refuted then explode",
+		`when verdict == refuted then append "x`,
+		"when (verdict == refuted then remove",
+		`when verdict == refuted then remove "extra"`,
+	} {
+		if _, err := ParseTransformRule(expr); err == nil {
+			t.Errorf("expected %q to be rejected", expr)
+		}
+	}
+}
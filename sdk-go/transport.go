@@ -0,0 +1,84 @@
+package qwed
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// ============================================================================
+// Transport
+// ============================================================================
+
+// WithProxy sends requests through the HTTP or HTTPS proxy at proxyURL,
+// e.g. "http://proxy.internal:3128", instead of the proxy set in the
+// HTTP_PROXY and HTTPS_PROXY environment variables. If proxyURL does not
+// parse, every request fails with the error.
+func WithProxy(proxyURL string) ClientOption {
+	return func(c *Client) {
+		u, err := url.Parse(proxyURL)
+		if err == nil && (u.Scheme == "" || u.Host == "") {
+			err = fmt.Errorf("missing scheme or host")
+		}
+		if err != nil {
+			c.optionErr = fmt.Errorf("failed to parse proxy URL %q: %w", proxyURL, err)
+			return
+		}
+		c.proxy = u
+	}
+}
+
+// WithUnixSocket connects to the API over the Unix domain socket at path,
+// for sidecar deployments where qwed-gateway listens on a local socket
+// rather than TCP. The base URL still sets the scheme and path prefix of
+// requests; its host is ignored. Proxies are not used.
+func WithUnixSocket(path string) ClientOption {
+	return func(c *Client) {
+		c.unixSocket = path
+	}
+}
+
+// applyTransport installs the configured TLS, proxy and socket settings on
+// a copy of the HTTP client's transport, leaving a client passed to
+// WithHTTPClient untouched.
+func (c *Client) applyTransport() {
+	if c.tlsConfig == nil && c.proxy == nil && c.unixSocket == "" || c.optionErr != nil {
+		return
+	}
+	if c.proxy != nil && c.unixSocket != "" {
+		c.optionErr = fmt.Errorf("failed to configure transport: WithProxy and WithUnixSocket cannot be combined")
+		return
+	}
+
+	var transport *http.Transport
+	switch t := c.httpClient.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		c.optionErr = fmt.Errorf("failed to configure transport: HTTP client transport is %T, not *http.Transport", t)
+		return
+	}
+
+	if c.tlsConfig != nil {
+		transport.TLSClientConfig = c.tlsConfig
+	}
+	if c.proxy != nil {
+		transport.Proxy = http.ProxyURL(c.proxy)
+	}
+	if c.unixSocket != "" {
+		path := c.unixSocket
+		transport.Proxy = nil
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", path)
+		}
+	}
+
+	httpClient := *c.httpClient
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+}
@@ -0,0 +1,71 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithProxy(t *testing.T) {
+	proxied := make(chan string, 1)
+	proxy := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute URL of the target.
+		proxied <- r.URL.String()
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	})
+	defer proxy.Close()
+
+	client := NewClient("test-key", WithBaseURL("http://qwed.internal:8000"), WithProxy(proxy.URL))
+	if _, err := client.VerifyMath(context.Background(), "2+2=4"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := <-proxied; got != "http://qwed.internal:8000/verify/math" {
+		t.Errorf("expected the request to go through the proxy, got %s", got)
+	}
+}
+
+func TestWithUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "qwed.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/verify/math" {
+			t.Errorf("expected the base URL path prefix, got %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	}))
+	server.Listener = listener
+	server.Start()
+	defer server.Close()
+
+	t.Setenv("HTTP_PROXY", "http://127.0.0.1:1")
+	client := NewClient("test-key", WithBaseURL("http://gateway/v1"), WithUnixSocket(socket))
+	resp, err := client.VerifyMath(context.Background(), "2+2=4")
+	if err != nil || !resp.Verified {
+		t.Errorf("expected the request over the socket, got %+v, %v", resp, err)
+	}
+}
+
+func TestTransportOptionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ClientOption
+		want string
+	}{
+		{"bad proxy", []ClientOption{WithProxy("proxy.internal")}, "failed to parse proxy URL"},
+		{"proxy and socket", []ClientOption{WithProxy("http://proxy.internal:3128"), WithUnixSocket("/run/qwed.sock")}, "cannot be combined"},
+	}
+	for _, tt := range tests {
+		client := NewClient("test-key", tt.opts...)
+		if _, err := client.Health(context.Background()); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected %q error, got %v", tt.name, tt.want, err)
+		}
+	}
+}
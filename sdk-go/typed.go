@@ -0,0 +1,85 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ============================================================================
+// Typed Verification
+// ============================================================================
+
+// VerifyInto sends req to the engine named by req.Type and decodes the
+// engine's result into a T, sparing callers type assertions on the
+// response's map:
+//
+//	type mathResult struct {
+//		Value      float64 `json:"value"`
+//		Simplified string  `json:"simplified"`
+//	}
+//	result, resp, err := qwed.VerifyInto[mathResult](ctx, client, &qwed.VerificationRequest{
+//		Type:  qwed.TypeMath,
+//		Query: "2*(3+4)",
+//	})
+//
+// req.Params are sent as top-level fields of the request body alongside
+// the query, so engines taking structured input are reached with e.g.
+// Params: {"code": src, "language": "python"}. A Type of "" means
+// TypeNaturalLanguage. The response goes through the client's cache,
+// retries and interceptors like those of the Verify methods, and is
+// returned with the decoded result. A response without a result decodes
+// as the zero T; a result that does not fit T is an error.
+func VerifyInto[T any](ctx context.Context, client *Client, req *VerificationRequest, callOpts ...CallOption) (T, *VerificationResponse, error) {
+	var result T
+	if req == nil {
+		return result, nil, invalidRequest("request is nil")
+	}
+
+	engine := req.Type
+	if engine == "" {
+		engine = TypeNaturalLanguage
+	}
+	body := make(map[string]interface{}, len(req.Params)+3)
+	for name, value := range req.Params {
+		body[name] = value
+	}
+	if req.Query != "" {
+		body["query"] = req.Query
+	}
+	opts := client.requestOptions(req.Options)
+	if opts != nil {
+		body["options"] = opts
+	}
+	if len(req.Metadata) > 0 {
+		body["metadata"] = req.Metadata
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return result, nil, invalidRequest("failed to marshal request: %v", err)
+	}
+	resp, err := client.verify(ctx, "VerifyInto", engine, CacheKey(engine, contentHash(data)), body, callOpts...)
+	if err != nil {
+		return result, resp, err
+	}
+	if err := decodeInto(resp, &result); err != nil {
+		return result, resp, err
+	}
+	return result, resp, nil
+}
+
+// decodeInto decodes the result of resp into v.
+func decodeInto(resp *VerificationResponse, v interface{}) error {
+	if resp == nil || resp.Result == nil {
+		return nil
+	}
+	data, err := json.Marshal(resp.Result)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s result: %w", resp.Engine, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode %s result: %w", resp.Engine, err)
+	}
+	return nil
+}
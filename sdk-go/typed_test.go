@@ -0,0 +1,79 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type mathResult struct {
+	Value      float64 `json:"value"`
+	Simplified string  `json:"simplified"`
+}
+
+func TestVerifyInto(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/verify/math" {
+			t.Errorf("expected path /verify/math, got %s", r.URL.Path)
+		}
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req["query"] != "2*(3+4)" || req["precision"] != float64(4) {
+			t.Errorf("unexpected request: %v", req)
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status":   "VERIFIED",
+			"verified": true,
+			"engine":   "math",
+			"result":   map[string]interface{}{"value": 14, "simplified": "14"},
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	result, resp, err := VerifyInto[mathResult](context.Background(), client, &VerificationRequest{
+		Type:   TypeMath,
+		Query:  "2*(3+4)",
+		Params: map[string]interface{}{"precision": 4},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Verified || result.Value != 14 || result.Simplified != "14" {
+		t.Errorf("unexpected result %+v from %+v", result, resp)
+	}
+}
+
+func TestVerifyIntoDefaultsAndErrors(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		result := map[string]interface{}{"value": "not a number"}
+		if r.URL.Path == "/verify/natural_language" {
+			result = nil
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "FAILED",
+			"result": result,
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	result, resp, err := VerifyInto[mathResult](ctx, client, &VerificationRequest{Query: "is the sky blue?"})
+	if err != nil || resp == nil || result != (mathResult{}) {
+		t.Errorf("expected a zero result without error, got %+v: %v", result, err)
+	}
+
+	_, resp, err = VerifyInto[mathResult](ctx, client, &VerificationRequest{Type: TypeMath, Query: "x"})
+	if err == nil || !strings.Contains(err.Error(), "failed to decode") || resp == nil {
+		t.Errorf("expected a decode error with the response, got %v", err)
+	}
+
+	if _, _, err := VerifyInto[mathResult](ctx, client, nil); err == nil {
+		t.Error("expected a nil request to fail")
+	}
+}
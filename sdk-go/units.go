@@ -0,0 +1,942 @@
+package qwed
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ============================================================================
+// Unit Verification
+// ============================================================================
+
+// TypeUnits identifies unit and dimensional analysis checks. They run
+// locally and are reported with Engine EngineLocalUnits.
+const TypeUnits VerificationType = "units"
+
+// EngineLocalUnits is the engine name reported by VerifyUnits.
+const EngineLocalUnits Engine = "local-units"
+
+// approxTolerance is the relative tolerance for claims stated as
+// approximate ("about", "roughly", "≈").
+const approxTolerance = 0.05
+
+// VerifyUnits checks a claim involving physical quantities, such as
+// "5 km in 20 minutes is 15 km/h" or "72 °F = 22.2 °C". Quantities are
+// parsed and converted to SI units, both sides are checked for dimensional
+// consistency, and the stated value is compared allowing for the precision
+// it is written with: "1 mile is 1.6 km" verifies, "1 mile is 1.5 km" does
+// not. Words such as "about" or "≈" widen the tolerance to 5%.
+//
+// Both sides may combine quantities with + - * / and the words per, times,
+// over and in ("5 km in 20 minutes" is a speed). "in", "to" or "as"
+// followed by a unit converts the left side, so "3 ft in cm is 91.44"
+// compares in centimetres. Dimension mismatches and inconsistent sums fail
+// with Result["reason"]; claims that cannot be parsed are reported with
+// StatusUnsupported.
+func (c *Client) VerifyUnits(ctx context.Context, claim string) (resp *VerificationResponse, err error) {
+	_, end := c.instrument(ctx, "VerifyUnits", TypeUnits)
+	defer func() { end(resp, err) }()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return localVerifyUnits(claim), nil
+}
+
+// localVerifyUnits evaluates a units claim.
+func localVerifyUnits(claim string) *VerificationResponse {
+	unsupported := func(reason string) *VerificationResponse {
+		return &VerificationResponse{
+			Status: StatusUnsupported,
+			Engine: EngineLocalUnits,
+			Result: map[string]interface{}{"reason": reason},
+		}
+	}
+
+	tokens, err := lexUnits(strings.TrimRight(strings.TrimSpace(claim), ".!;"))
+	if err != nil {
+		return unsupported(err.Error())
+	}
+	cmp := comparisonIndex(tokens)
+	if cmp < 0 {
+		return unsupported("no comparison between two quantities found")
+	}
+
+	left, err := parseUnitSide(tokens[:cmp])
+	if err != nil {
+		return failedOrUnsupported(err, unsupported)
+	}
+	right, err := parseUnitSide(tokens[cmp+1:])
+	if err != nil {
+		return failedOrUnsupported(err, unsupported)
+	}
+	approx := tokens[cmp].approx || right.approx
+
+	// A bare number on the right is in the left side's target unit:
+	// "3 ft in cm is 91.44".
+	if left.target != nil && right.unit == nil && right.simple && right.value.dim == (dimension{}) {
+		right = right.in(*left.target)
+	}
+	if left.target != nil && left.target.dim != left.value.dim {
+		return dimensionMismatch(left.value.dim, left.target.dim, "cannot convert")
+	}
+	if left.value.dim != right.value.dim {
+		return dimensionMismatch(left.value.dim, right.value.dim, "dimension mismatch")
+	}
+
+	lhs, rhs := left.value.si(), right.value.si()
+	tolerance := mathTolerance * math.Max(math.Abs(lhs), math.Abs(rhs))
+	if right.simple {
+		scale := 1.0
+		if right.unit != nil {
+			scale = right.unit.factor
+		}
+		tolerance = math.Max(tolerance, right.precision*math.Abs(scale))
+		if approx {
+			tolerance = math.Max(tolerance, approxTolerance*math.Abs(right.number*scale))
+		}
+	} else if approx {
+		tolerance = math.Max(tolerance, approxTolerance*math.Abs(rhs))
+	}
+
+	result := map[string]interface{}{
+		"dimension": left.value.dim.String(),
+		"lhs_si":    lhs,
+		"rhs_si":    rhs,
+		"tolerance": tolerance,
+	}
+	if right.simple {
+		expected := lhs
+		if right.unit != nil {
+			expected = lhs/right.unit.factor - right.unit.offset
+			result["unit"] = right.unitText
+		}
+		result["expected"] = expected
+		result["claimed"] = right.number
+	}
+	return localResponse(EngineLocalUnits, math.Abs(lhs-rhs) <= tolerance, result)
+}
+
+// failedOrUnsupported reports inconsistent expressions as failures and
+// everything else as unsupported.
+func failedOrUnsupported(err error, unsupported func(string) *VerificationResponse) *VerificationResponse {
+	if inconsistent, ok := err.(*dimensionError); ok {
+		return dimensionMismatch(inconsistent.a, inconsistent.b, "cannot "+inconsistent.op)
+	}
+	return unsupported(err.Error())
+}
+
+func dimensionMismatch(a, b dimension, what string) *VerificationResponse {
+	return localResponse(EngineLocalUnits, false, map[string]interface{}{
+		"reason":        fmt.Sprintf("%s %s and %s", what, a, b),
+		"lhs_dimension": a.String(),
+		"rhs_dimension": b.String(),
+	})
+}
+
+// ============================================================================
+// Dimensions and Units
+// ============================================================================
+
+// dimension holds the exponents of the SI base units m, kg, s, A, K, mol.
+type dimension [6]int8
+
+var baseUnitSymbols = [6]string{"m", "kg", "s", "A", "K", "mol"}
+
+func (d dimension) plus(o dimension, sign int8) dimension {
+	for i := range d {
+		d[i] += sign * o[i]
+	}
+	return d
+}
+
+func (d dimension) times(e int8) dimension {
+	for i := range d {
+		d[i] *= e
+	}
+	return d
+}
+
+// String formats d as a product of SI base units, such as "m·s^-1".
+func (d dimension) String() string {
+	var parts []string
+	for i, e := range d {
+		switch {
+		case e == 1:
+			parts = append(parts, baseUnitSymbols[i])
+		case e != 0:
+			parts = append(parts, fmt.Sprintf("%s^%d", baseUnitSymbols[i], e))
+		}
+	}
+	if len(parts) == 0 {
+		return "1"
+	}
+	return strings.Join(parts, "·")
+}
+
+// dimensionError reports an operation on incompatible dimensions.
+type dimensionError struct {
+	op   string
+	a, b dimension
+}
+
+func (e *dimensionError) Error() string {
+	return fmt.Sprintf("cannot %s %s and %s", e.op, e.a, e.b)
+}
+
+// unitDef converts a unit to SI: si = (value + offset) * factor. Only
+// temperature scales have an offset.
+type unitDef struct {
+	factor float64
+	dim    dimension
+	offset float64
+}
+
+func (u unitDef) pow(e int8) unitDef {
+	return unitDef{factor: math.Pow(u.factor, float64(e)), dim: u.dim.times(e)}
+}
+
+func (u unitDef) mul(o unitDef, sign int8) unitDef {
+	return unitDef{factor: u.factor * math.Pow(o.factor, float64(sign)), dim: u.dim.plus(o.dim, sign)}
+}
+
+var (
+	dimLength      = dimension{1, 0, 0}
+	dimMass        = dimension{0, 1, 0}
+	dimTime        = dimension{0, 0, 1}
+	dimCurrent     = dimension{0, 0, 0, 1}
+	dimTemperature = dimension{0, 0, 0, 0, 1}
+	dimAmount      = dimension{0, 0, 0, 0, 0, 1}
+	dimArea        = dimension{2, 0, 0}
+	dimVolume      = dimension{3, 0, 0}
+	dimSpeed       = dimension{1, 0, -1}
+	dimForce       = dimension{1, 1, -2}
+	dimEnergy      = dimension{2, 1, -2}
+	dimPower       = dimension{2, 1, -3}
+	dimPressure    = dimension{-1, 1, -2}
+	dimFrequency   = dimension{0, 0, -1}
+	dimVoltage     = dimension{2, 1, -3, -1}
+	dimResistance  = dimension{2, 1, -3, -2}
+)
+
+// prefixedUnits are symbols that accept SI prefixes, such as km or mW.
+var prefixedUnits = map[string]unitDef{
+	"m":   {factor: 1, dim: dimLength},
+	"g":   {factor: 1e-3, dim: dimMass},
+	"s":   {factor: 1, dim: dimTime},
+	"A":   {factor: 1, dim: dimCurrent},
+	"K":   {factor: 1, dim: dimTemperature},
+	"mol": {factor: 1, dim: dimAmount},
+	"L":   {factor: 1e-3, dim: dimVolume},
+	"l":   {factor: 1e-3, dim: dimVolume},
+	"N":   {factor: 1, dim: dimForce},
+	"J":   {factor: 1, dim: dimEnergy},
+	"eV":  {factor: 1.602176634e-19, dim: dimEnergy},
+	"Wh":  {factor: 3600, dim: dimEnergy},
+	"W":   {factor: 1, dim: dimPower},
+	"Pa":  {factor: 1, dim: dimPressure},
+	"Hz":  {factor: 1, dim: dimFrequency},
+	"V":   {factor: 1, dim: dimVoltage},
+	"Ω":   {factor: 1, dim: dimResistance},
+}
+
+// siPrefixes are the multipliers of SI prefixes.
+var siPrefixes = map[string]float64{
+	"T": 1e12, "G": 1e9, "M": 1e6, "k": 1e3, "h": 1e2, "d": 1e-1,
+	"c": 1e-2, "m": 1e-3, "µ": 1e-6, "μ": 1e-6, "u": 1e-6, "n": 1e-9, "p": 1e-12,
+}
+
+// unitSymbols are case-sensitive symbols without prefixes. They take
+// precedence over prefixed units, so "min" is a minute and "h" an hour.
+var unitSymbols = map[string]unitDef{
+	"min":  {factor: 60, dim: dimTime},
+	"h":    {factor: 3600, dim: dimTime},
+	"hr":   {factor: 3600, dim: dimTime},
+	"hrs":  {factor: 3600, dim: dimTime},
+	"d":    {factor: 86400, dim: dimTime},
+	"yr":   {factor: 31557600, dim: dimTime},
+	"ft":   {factor: 0.3048, dim: dimLength},
+	"yd":   {factor: 0.9144, dim: dimLength},
+	"mi":   {factor: 1609.344, dim: dimLength},
+	"nmi":  {factor: 1852, dim: dimLength},
+	"lb":   {factor: 0.45359237, dim: dimMass},
+	"lbs":  {factor: 0.45359237, dim: dimMass},
+	"oz":   {factor: 0.028349523125, dim: dimMass},
+	"t":    {factor: 1000, dim: dimMass},
+	"mph":  {factor: 0.44704, dim: dimSpeed},
+	"kph":  {factor: 1 / 3.6, dim: dimSpeed},
+	"kmh":  {factor: 1 / 3.6, dim: dimSpeed},
+	"kn":   {factor: 1852.0 / 3600, dim: dimSpeed},
+	"gal":  {factor: 3.785411784e-3, dim: dimVolume},
+	"ha":   {factor: 1e4, dim: dimArea},
+	"cal":  {factor: 4.184, dim: dimEnergy},
+	"kcal": {factor: 4184, dim: dimEnergy},
+	"Cal":  {factor: 4184, dim: dimEnergy},
+	"hp":   {factor: 745.69987158227022, dim: dimPower},
+	"bar":  {factor: 1e5, dim: dimPressure},
+	"mbar": {factor: 100, dim: dimPressure},
+	"atm":  {factor: 101325, dim: dimPressure},
+	"psi":  {factor: 6894.757293168, dim: dimPressure},
+	"°C":   {factor: 1, dim: dimTemperature, offset: 273.15},
+	"℃":    {factor: 1, dim: dimTemperature, offset: 273.15},
+	"degC": {factor: 1, dim: dimTemperature, offset: 273.15},
+	"C":    {factor: 1, dim: dimTemperature, offset: 273.15},
+	"°F":   {factor: 5.0 / 9, dim: dimTemperature, offset: 459.67},
+	"℉":    {factor: 5.0 / 9, dim: dimTemperature, offset: 459.67},
+	"degF": {factor: 5.0 / 9, dim: dimTemperature, offset: 459.67},
+	"F":    {factor: 5.0 / 9, dim: dimTemperature, offset: 459.67},
+	"°K":   {factor: 1, dim: dimTemperature},
+}
+
+// unitNames are lowercase singular unit names. Plurals ending in "s" and
+// the prefixes in namePrefixes are accepted.
+var unitNames = map[string]unitDef{
+	"metre":        {factor: 1, dim: dimLength},
+	"meter":        {factor: 1, dim: dimLength},
+	"foot":         {factor: 0.3048, dim: dimLength},
+	"feet":         {factor: 0.3048, dim: dimLength},
+	"inch":         {factor: 0.0254, dim: dimLength},
+	"inches":       {factor: 0.0254, dim: dimLength},
+	"yard":         {factor: 0.9144, dim: dimLength},
+	"mile":         {factor: 1609.344, dim: dimLength},
+	"gram":         {factor: 1e-3, dim: dimMass},
+	"tonne":        {factor: 1000, dim: dimMass},
+	"pound":        {factor: 0.45359237, dim: dimMass},
+	"ounce":        {factor: 0.028349523125, dim: dimMass},
+	"second":       {factor: 1, dim: dimTime},
+	"sec":          {factor: 1, dim: dimTime},
+	"minute":       {factor: 60, dim: dimTime},
+	"hour":         {factor: 3600, dim: dimTime},
+	"day":          {factor: 86400, dim: dimTime},
+	"week":         {factor: 604800, dim: dimTime},
+	"year":         {factor: 31557600, dim: dimTime},
+	"litre":        {factor: 1e-3, dim: dimVolume},
+	"liter":        {factor: 1e-3, dim: dimVolume},
+	"gallon":       {factor: 3.785411784e-3, dim: dimVolume},
+	"hectare":      {factor: 1e4, dim: dimArea},
+	"acre":         {factor: 4046.8564224, dim: dimArea},
+	"knot":         {factor: 1852.0 / 3600, dim: dimSpeed},
+	"newton":       {factor: 1, dim: dimForce},
+	"joule":        {factor: 1, dim: dimEnergy},
+	"calorie":      {factor: 4.184, dim: dimEnergy},
+	"electronvolt": {factor: 1.602176634e-19, dim: dimEnergy},
+	"watt":         {factor: 1, dim: dimPower},
+	"horsepower":   {factor: 745.69987158227022, dim: dimPower},
+	"pascal":       {factor: 1, dim: dimPressure},
+	"atmosphere":   {factor: 101325, dim: dimPressure},
+	"hertz":        {factor: 1, dim: dimFrequency},
+	"ampere":       {factor: 1, dim: dimCurrent},
+	"amp":          {factor: 1, dim: dimCurrent},
+	"volt":         {factor: 1, dim: dimVoltage},
+	"ohm":          {factor: 1, dim: dimResistance},
+	"mole":         {factor: 1, dim: dimAmount},
+	"kelvin":       {factor: 1, dim: dimTemperature},
+	"celsius":      {factor: 1, dim: dimTemperature, offset: 273.15},
+	"fahrenheit":   {factor: 5.0 / 9, dim: dimTemperature, offset: 459.67},
+}
+
+// namePrefixes are the multipliers of spelled-out SI prefixes.
+var namePrefixes = map[string]float64{
+	"tera": 1e12, "giga": 1e9, "mega": 1e6, "kilo": 1e3,
+	"centi": 1e-2, "milli": 1e-3, "micro": 1e-6, "nano": 1e-9,
+}
+
+// lookupUnit resolves a unit symbol or name.
+func lookupUnit(word string) (unitDef, bool) {
+	if u, ok := unitSymbols[word]; ok {
+		return u, true
+	}
+	if u, ok := prefixedUnits[word]; ok {
+		return u, true
+	}
+	for prefix, scale := range siPrefixes {
+		if rest := strings.TrimPrefix(word, prefix); rest != word && rest != "" {
+			if u, ok := prefixedUnits[rest]; ok {
+				u.factor *= scale
+				return u, true
+			}
+		}
+	}
+
+	name := strings.ToLower(word)
+	if u, ok := lookupUnitName(name); ok {
+		return u, true
+	}
+	for prefix, scale := range namePrefixes {
+		if rest := strings.TrimPrefix(name, prefix); rest != name {
+			if u, ok := lookupUnitName(rest); ok && u.offset == 0 {
+				u.factor *= scale
+				return u, true
+			}
+		}
+	}
+	return unitDef{}, false
+}
+
+func lookupUnitName(name string) (unitDef, bool) {
+	if u, ok := unitNames[name]; ok {
+		return u, true
+	}
+	if strings.HasSuffix(name, "s") {
+		u, ok := unitNames[strings.TrimSuffix(name, "s")]
+		return u, ok
+	}
+	return unitDef{}, false
+}
+
+// ============================================================================
+// Quantity Parser
+// ============================================================================
+
+type unitTokenKind int
+
+const (
+	unitNumber unitTokenKind = iota
+	unitWord
+	unitOp
+	unitCompare
+	unitPunct
+)
+
+type unitToken struct {
+	kind      unitTokenKind
+	text      string
+	value     float64 // numbers
+	precision float64 // numbers: half of the last written digit
+	exponent  int8    // ^n or superscript after the token, 1 if none
+	approx    bool    // comparisons: "≈", "about"
+}
+
+// unitComparisons are the words and symbols separating the two sides of a
+// claim. approximate ones widen the tolerance.
+var unitComparisons = map[string]bool{
+	"=": false, "==": false, "is": false, "are": false, "was": false, "equals": false,
+	"means": false, "gives": false, "makes": false, "yields": false,
+	"≈": true, "~": true, "≅": true,
+}
+
+// approxWords after a comparison widen the tolerance.
+var approxWords = wordSet("about approximately approx roughly around nearly almost circa")
+
+// unitConnectives may follow a number without being a unit.
+var unitConnectives = wordSet("in to into as per times over divided by and or of which that so for at on then with from a an")
+
+var superscripts = map[rune]rune{'⁰': '0', '¹': '1', '²': '2', '³': '3', '⁴': '4', '⁵': '5', '⁶': '6', '⁷': '7', '⁸': '8', '⁹': '9', '⁻': '-'}
+
+// lexUnits splits a claim into numbers, words, operators and comparisons.
+func lexUnits(s string) ([]unitToken, error) {
+	var tokens []unitToken
+	rs := []rune(s)
+	exponent := func(i int, text string) error {
+		if len(tokens) == 0 {
+			return fmt.Errorf("exponent without a base")
+		}
+		e, err := strconv.ParseInt(text, 10, 8)
+		if err != nil {
+			return fmt.Errorf("invalid exponent %q", text)
+		}
+		tokens[len(tokens)-1].exponent *= int8(e)
+		return nil
+	}
+
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(rs) && unicode.IsDigit(rs[i+1])) ||
+			((r == '-' || r == '−') && i+1 < len(rs) && unicode.IsDigit(rs[i+1]) && startsOperand(tokens)):
+			j := i + 1
+			for j < len(rs) {
+				switch {
+				case unicode.IsDigit(rs[j]) || rs[j] == '.':
+					j++
+					continue
+				case rs[j] == ',' && j+3 < len(rs)+1 && isDigitRun(rs, j+1, 3) && (j+4 >= len(rs) || !unicode.IsDigit(rs[j+4])):
+					j++
+					continue
+				case (rs[j] == 'e' || rs[j] == 'E') && j+1 < len(rs) && (unicode.IsDigit(rs[j+1]) || (rs[j+1] == '-' && j+2 < len(rs) && unicode.IsDigit(rs[j+2]))):
+					j += 2
+					continue
+				}
+				break
+			}
+			text := strings.NewReplacer(",", "", "−", "-").Replace(string(rs[i:j]))
+			value, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q", string(rs[i:j]))
+			}
+			tokens = append(tokens, unitToken{kind: unitNumber, text: string(rs[i:j]), value: value, precision: numberPrecision(text), exponent: 1})
+			i = j
+		case r == '^':
+			j := i + 1
+			if j < len(rs) && (rs[j] == '-' || rs[j] == '−') {
+				j++
+			}
+			for j < len(rs) && unicode.IsDigit(rs[j]) {
+				j++
+			}
+			if err := exponent(i, strings.ReplaceAll(string(rs[i+1:j]), "−", "-")); err != nil {
+				return nil, err
+			}
+			i = j
+		case superscripts[r] != 0:
+			var text []rune
+			for i < len(rs) && superscripts[rs[i]] != 0 {
+				text = append(text, superscripts[rs[i]])
+				i++
+			}
+			if err := exponent(i, string(text)); err != nil {
+				return nil, err
+			}
+		case strings.ContainsRune("+-−*×·/÷()", r):
+			op := string(r)
+			switch r {
+			case '−':
+				op = "-"
+			case '×', '·':
+				op = "*"
+			case '÷':
+				op = "/"
+			}
+			tokens = append(tokens, unitToken{kind: unitOp, text: op, exponent: 1})
+			i++
+		case strings.ContainsRune("=≈~≅", r):
+			j := i + 1
+			for j < len(rs) && rs[j] == '=' {
+				j++
+			}
+			text := string(rs[i:j])
+			if text != "=" && text != "==" && j-i > 1 {
+				text = string(r)
+			}
+			tokens = append(tokens, unitToken{kind: unitCompare, text: text, approx: unitComparisons[text], exponent: 1})
+			i = j
+		case unicode.IsLetter(r) || strings.ContainsRune("°µμΩ℃℉", r):
+			j := i + 1
+			for j < len(rs) && (unicode.IsLetter(rs[j]) || strings.ContainsRune("°µμΩ℃℉", rs[j])) {
+				j++
+			}
+			word := string(rs[i:j])
+			if approx, ok := unitComparisons[strings.ToLower(word)]; ok {
+				tokens = append(tokens, unitToken{kind: unitCompare, text: strings.ToLower(word), approx: approx, exponent: 1})
+			} else {
+				tokens = append(tokens, unitToken{kind: unitWord, text: word, exponent: 1})
+			}
+			i = j
+		default:
+			tokens = append(tokens, unitToken{kind: unitPunct, text: string(r), exponent: 1})
+			i++
+		}
+	}
+	return tokens, nil
+}
+
+// startsOperand reports whether a minus sign after tokens is a sign rather
+// than a subtraction.
+func startsOperand(tokens []unitToken) bool {
+	if len(tokens) == 0 {
+		return true
+	}
+	last := tokens[len(tokens)-1]
+	return last.kind == unitCompare || last.kind == unitPunct || (last.kind == unitOp && last.text != ")") ||
+		(last.kind == unitWord && unitConnectives[strings.ToLower(last.text)])
+}
+
+func isDigitRun(rs []rune, start, n int) bool {
+	if start+n > len(rs) {
+		return false
+	}
+	for _, r := range rs[start : start+n] {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// numberPrecision returns half of the last written digit's place value, so
+// "1.6" is precise to 0.05.
+func numberPrecision(text string) float64 {
+	mantissa, exp := text, 0
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		mantissa = text[:i]
+		exp, _ = strconv.Atoi(text[i+1:])
+	}
+	decimals := 0
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		decimals = len(mantissa) - i - 1
+	}
+	return 0.5 * math.Pow(10, float64(exp-decimals))
+}
+
+// comparisonIndex returns the index of the comparison separating the two
+// sides, preferring symbols over words and later words over earlier ones,
+// or -1 if no comparison has a quantity on both sides.
+func comparisonIndex(tokens []unitToken) int {
+	best := -1
+	for i, t := range tokens {
+		if t.kind != unitCompare || !hasQuantity(tokens[:i]) || !hasQuantity(tokens[i+1:]) {
+			continue
+		}
+		symbol := !unicode.IsLetter([]rune(t.text)[0])
+		if best >= 0 && !symbol && !unicode.IsLetter([]rune(tokens[best].text)[0]) {
+			continue
+		}
+		best = i
+	}
+	if best >= 0 && best+1 < len(tokens) && tokens[best+1].kind == unitWord && approxWords[strings.ToLower(tokens[best+1].text)] {
+		tokens[best].approx = true
+	}
+	return best
+}
+
+// hasQuantity reports whether tokens contain a number or "a"/"an"/"one"
+// followed by a unit.
+func hasQuantity(tokens []unitToken) bool {
+	p := &unitParser{tokens: tokens}
+	for ; p.pos < len(tokens); p.pos++ {
+		if p.startsFactor(0) && !p.op(0, "(") {
+			return true
+		}
+	}
+	return false
+}
+
+// quantity is a value in SI units. abs is set for single temperatures on
+// an offset scale, such as 20 °C, where the absolute value differs from
+// the temperature difference in value.
+type quantity struct {
+	value float64
+	dim   dimension
+	abs   *float64
+}
+
+// si returns the quantity's SI value, absolute for temperatures.
+func (q quantity) si() float64 {
+	if q.abs != nil {
+		return *q.abs
+	}
+	return q.value
+}
+
+// unitSide is one parsed side of a claim.
+type unitSide struct {
+	value  quantity
+	target *unitDef // "in km/h" conversion target
+	approx bool
+
+	// simple sides are a single number with an optional unit.
+	simple    bool
+	number    float64
+	precision float64
+	unit      *unitDef
+	unitText  string
+}
+
+// in reinterprets a bare number as a quantity in unit u.
+func (s unitSide) in(u unitDef) unitSide {
+	s.unit = &u
+	s.value = quantity{value: s.number * u.factor, dim: u.dim}
+	if u.offset != 0 {
+		abs := (s.number + u.offset) * u.factor
+		s.value.abs = &abs
+	}
+	return s
+}
+
+type unitParser struct {
+	tokens  []unitToken
+	pos     int
+	factors int
+	last    unitSide // the most recent number factor
+}
+
+func (p *unitParser) peek(offset int) unitToken {
+	if p.pos+offset < len(p.tokens) {
+		return p.tokens[p.pos+offset]
+	}
+	return unitToken{kind: unitPunct}
+}
+
+// word reports whether the token at offset is the word w.
+func (p *unitParser) word(offset int, w string) bool {
+	t := p.peek(offset)
+	return t.kind == unitWord && strings.EqualFold(t.text, w)
+}
+
+func (p *unitParser) op(offset int, op string) bool {
+	t := p.peek(offset)
+	return t.kind == unitOp && t.text == op
+}
+
+// isUnit reports whether the token at offset starts a unit.
+func (p *unitParser) isUnit(offset int) bool {
+	t := p.peek(offset)
+	if t.kind != unitWord {
+		return false
+	}
+	if _, ok := lookupUnit(t.text); ok {
+		return true
+	}
+	switch strings.ToLower(t.text) {
+	case "square", "sq", "cubic":
+		return p.isUnit(offset + 1)
+	}
+	return false
+}
+
+// startsFactor reports whether the token at offset starts a quantity.
+func (p *unitParser) startsFactor(offset int) bool {
+	switch t := p.peek(offset); {
+	case t.kind == unitNumber, t.kind == unitOp && t.text == "(":
+		return true
+	case t.kind == unitWord:
+		switch strings.ToLower(t.text) {
+		case "a", "an", "one":
+			return p.isUnit(offset + 1)
+		}
+	}
+	return false
+}
+
+// parseUnitSide parses a side of a claim, skipping leading and trailing
+// words.
+func parseUnitSide(tokens []unitToken) (unitSide, error) {
+	p := &unitParser{tokens: tokens}
+	for p.pos < len(tokens) && !p.startsFactor(0) {
+		p.pos++
+	}
+	if p.pos == len(tokens) {
+		return unitSide{}, fmt.Errorf("no quantity found")
+	}
+
+	q, err := p.parseSum()
+	if err != nil {
+		return unitSide{}, err
+	}
+	side := unitSide{value: q}
+	if p.factors == 1 && p.last.simple {
+		side.simple, side.number, side.precision = true, p.last.number, p.last.precision
+		side.unit, side.unitText = p.last.unit, p.last.unitText
+	}
+
+	if (p.word(0, "in") || p.word(0, "to") || p.word(0, "into") || p.word(0, "as")) && p.isUnit(1) {
+		p.pos++
+		target, _, err := p.parseUnits()
+		if err != nil {
+			return unitSide{}, err
+		}
+		side.target = &target
+	}
+	for ; p.pos < len(tokens); p.pos++ {
+		switch t := tokens[p.pos]; t.kind {
+		case unitNumber:
+			return unitSide{}, fmt.Errorf("cannot parse quantity at %q", t.text)
+		case unitWord:
+			if approxWords[strings.ToLower(t.text)] {
+				side.approx = true
+			}
+		}
+	}
+	return side, nil
+}
+
+func (p *unitParser) parseSum() (quantity, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return quantity{}, err
+	}
+	for p.op(0, "+") || p.op(0, "-") {
+		sign := 1.0
+		if p.peek(0).text == "-" {
+			sign = -1
+		}
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return quantity{}, err
+		}
+		if left.dim != right.dim {
+			return quantity{}, &dimensionError{op: "add", a: left.dim, b: right.dim}
+		}
+		left = quantity{value: left.value + sign*right.value, dim: left.dim}
+	}
+	return left, nil
+}
+
+func (p *unitParser) parseProduct() (quantity, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return quantity{}, err
+	}
+	for {
+		var sign int8
+		switch {
+		case p.op(0, "*"):
+			sign, p.pos = 1, p.pos+1
+		case p.word(0, "times"):
+			sign, p.pos = 1, p.pos+1
+		case p.op(0, "/"), p.word(0, "per"), p.word(0, "over"):
+			sign, p.pos = -1, p.pos+1
+		case p.word(0, "divided") && p.word(1, "by"):
+			sign, p.pos = -1, p.pos+2
+		case p.word(0, "in") && p.startsFactor(1):
+			sign, p.pos = -1, p.pos+1
+		default:
+			return left, nil
+		}
+		right, err := p.parseFactor()
+		if err != nil {
+			return quantity{}, err
+		}
+		if sign < 0 && right.value == 0 {
+			return quantity{}, fmt.Errorf("division by zero")
+		}
+		left = quantity{value: left.value * math.Pow(right.value, float64(sign)), dim: left.dim.plus(right.dim, sign)}
+	}
+}
+
+func (p *unitParser) parseFactor() (quantity, error) {
+	p.factors++
+	t := p.peek(0)
+	switch {
+	case t.kind == unitOp && t.text == "(":
+		p.pos++
+		q, err := p.parseSum()
+		if err != nil {
+			return quantity{}, err
+		}
+		if !p.op(0, ")") {
+			return quantity{}, fmt.Errorf("missing )")
+		}
+		p.pos++
+		p.factors++ // a parenthesised expression is never simple
+		return p.withUnits(q, false)
+	case t.kind == unitNumber:
+		p.pos++
+		number := math.Pow(t.value, float64(t.exponent))
+		p.last = unitSide{simple: t.exponent == 1, number: number, precision: t.precision}
+		return p.withUnits(quantity{value: number}, true)
+	case t.kind == unitWord && (strings.EqualFold(t.text, "a") || strings.EqualFold(t.text, "an") || strings.EqualFold(t.text, "one")):
+		p.pos++
+		p.last = unitSide{simple: true, number: 1, precision: 0}
+		return p.withUnits(quantity{value: 1}, true)
+	case p.isUnit(0):
+		p.last = unitSide{}
+		return p.withUnits(quantity{value: 1}, false)
+	}
+	if t.text == "" {
+		return quantity{}, fmt.Errorf("missing quantity")
+	}
+	return quantity{}, fmt.Errorf("unexpected %q", t.text)
+}
+
+// withUnits applies the units following a number or expression, if any.
+func (p *unitParser) withUnits(q quantity, number bool) (quantity, error) {
+	if !p.isUnit(0) {
+		if number && p.peek(0).kind == unitWord && !unitConnectives[strings.ToLower(p.peek(0).text)] {
+			return quantity{}, fmt.Errorf("unknown unit %q", p.peek(0).text)
+		}
+		return q, nil
+	}
+	start := p.pos
+	u, single, err := p.parseUnits()
+	if err != nil {
+		return quantity{}, err
+	}
+	if number {
+		p.last.unit = &u
+		p.last.unitText = unitText(p.tokens[start:p.pos])
+	}
+
+	out := quantity{value: q.value * u.factor, dim: q.dim.plus(u.dim, 1)}
+	if single && u.offset != 0 {
+		abs := (q.value + u.offset) * u.factor
+		out.abs = &abs
+	}
+	return out, nil
+}
+
+// parseUnits parses a unit expression such as "km/h", "kg·m/s^2" or
+// "metres per second squared". single reports whether it is one unit with
+// exponent 1, the only form an offset temperature scale may take.
+func (p *unitParser) parseUnits() (unitDef, bool, error) {
+	u, err := p.parseUnit()
+	if err != nil {
+		return unitDef{}, false, err
+	}
+	count, single := 1, u.offset != 0
+	for {
+		var sign int8
+		switch {
+		case p.isUnit(0):
+			sign = 1
+		case (p.op(0, "*") || p.op(0, "/") || p.word(0, "per")) && p.isUnit(1):
+			sign = 1
+			if !p.op(0, "*") {
+				sign = -1
+			}
+			p.pos++
+		default:
+			if count > 1 || !single {
+				u.offset = 0
+			}
+			return u, count == 1 && single, nil
+		}
+		next, err := p.parseUnit()
+		if err != nil {
+			return unitDef{}, false, err
+		}
+		u = u.mul(next, sign)
+		count++
+	}
+}
+
+// parseUnit parses one unit with its exponent, including "square metres"
+// and "seconds squared".
+func (p *unitParser) parseUnit() (unitDef, error) {
+	var power int8 = 1
+	switch strings.ToLower(p.peek(0).text) {
+	case "square", "sq":
+		power, p.pos = 2, p.pos+1
+	case "cubic":
+		power, p.pos = 3, p.pos+1
+	}
+	t := p.peek(0)
+	u, ok := lookupUnit(t.text)
+	if t.kind != unitWord || !ok {
+		return unitDef{}, fmt.Errorf("unknown unit %q", t.text)
+	}
+	p.pos++
+	power *= t.exponent
+	switch {
+	case p.word(0, "squared"):
+		power, p.pos = power*2, p.pos+1
+	case p.word(0, "cubed"):
+		power, p.pos = power*3, p.pos+1
+	}
+	if power == 1 {
+		return u, nil
+	}
+	return u.pow(power), nil
+}
+
+// unitText reconstructs the written unit from its tokens.
+func unitText(tokens []unitToken) string {
+	var b strings.Builder
+	for i, t := range tokens {
+		if i > 0 && t.kind == unitWord && tokens[i-1].kind == unitWord {
+			b.WriteByte(' ')
+		}
+		b.WriteString(t.text)
+		if t.exponent != 1 {
+			fmt.Fprintf(&b, "^%d", t.exponent)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,97 @@
+package qwed
+
+import (
+	"context"
+	"testing"
+)
+
+func TestVerifyUnits(t *testing.T) {
+	tests := []struct {
+		claim    string
+		verified bool
+	}{
+		{"5 km in 20 minutes is 15 km/h", true},
+		{"5 km in 20 minutes is 16 km/h", false},
+		{"Running 5 km in 20 minutes gives an average speed of 15 km/h.", true},
+		{"100 km/h = 27.78 m/s", true},
+		{"100 km/h = 27.7 m/s", false},
+		{"1 mile is 1.6 km", true},
+		{"1 mile is 1.5 km", false},
+		{"1 mile is about 1.55 km", true},
+		{"3 ft in cm is 91.44", true},
+		{"72 °F = 22.2 °C", true},
+		{"0 °C = 273.15 K", true},
+		{"100 degC is 212 degF", true},
+		{"2 kg * 9.81 m/s^2 = 19.62 N", true},
+		{"1 kWh = 3.6 MJ", true},
+		{"60 miles per hour is 96.6 kph", true},
+		{"1 square metre = 10000 cm²", true},
+		{"3 hours + 30 minutes = 210 min", true},
+		{"a day is 86400 seconds", true},
+		{"10 kg ≈ 22 lbs", true},
+	}
+
+	client := NewClient("test")
+	for _, tt := range tests {
+		t.Run(tt.claim, func(t *testing.T) {
+			resp, err := client.VerifyUnits(context.Background(), tt.claim)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Verified != tt.verified {
+				t.Errorf("expected verified=%v, got %v (%v)", tt.verified, resp.Verified, resp.Result)
+			}
+			if resp.Engine != EngineLocalUnits {
+				t.Errorf("expected engine %s, got %s", EngineLocalUnits, resp.Engine)
+			}
+		})
+	}
+}
+
+func TestVerifyUnitsDimensionMismatch(t *testing.T) {
+	for _, claim := range []string{
+		"5 km in 20 minutes is 15 km",
+		"3 kg + 2 m = 5 kg",
+		"10 N = 10 J",
+		"3 ft in seconds is 1",
+	} {
+		resp := localVerifyUnits(claim)
+		if resp.Status != StatusFailed {
+			t.Errorf("%q: expected %s, got %s (%v)", claim, StatusFailed, resp.Status, resp.Result)
+			continue
+		}
+		if resp.Result["reason"] == nil {
+			t.Errorf("%q: expected a reason, got %v", claim, resp.Result)
+		}
+	}
+}
+
+func TestVerifyUnitsUnsupported(t *testing.T) {
+	for _, claim := range []string{
+		"",
+		"the sky is blue",
+		"5 parsecs is 3 widgets",
+		"5 km",
+	} {
+		resp := localVerifyUnits(claim)
+		if resp.Status != StatusUnsupported {
+			t.Errorf("%q: expected %s, got %s (%v)", claim, StatusUnsupported, resp.Status, resp.Result)
+		}
+		if resp.Verdict() != VerdictInconclusive {
+			t.Errorf("%q: expected an inconclusive verdict, got %s", claim, resp.Verdict())
+		}
+	}
+}
+
+func TestVerifyUnitsResult(t *testing.T) {
+	resp := localVerifyUnits("5 km in 20 minutes is 15 km/h")
+	if resp.Result["unit"] != "km/h" {
+		t.Errorf("expected unit km/h, got %v", resp.Result["unit"])
+	}
+	if expected, _ := resp.Result["expected"].(float64); expected < 14.999 || expected > 15.001 {
+		t.Errorf("expected 15 km/h, got %v", resp.Result["expected"])
+	}
+	if resp.Result["dimension"] != "m·s^-1" {
+		t.Errorf("expected dimension m·s^-1, got %v", resp.Result["dimension"])
+	}
+}
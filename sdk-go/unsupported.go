@@ -0,0 +1,54 @@
+package qwed
+
+import (
+	"context"
+	"strings"
+)
+
+// ============================================================================
+// Unsupported Input
+// ============================================================================
+
+// UnsupportedInput describes input an engine could not verify because it
+// uses a construct the engine does not handle.
+type UnsupportedInput struct {
+	Construct  string `json:"construct,omitempty"`  // the construct, e.g. "integral" or "function XLOOKUP"
+	Reason     string `json:"reason,omitempty"`     // why the engine rejected it
+	Suggestion string `json:"suggestion,omitempty"` // a rewrite the engine can verify, if it has one
+}
+
+// UnsupportedInput returns what the engine could not handle when the
+// response has StatusUnsupported, or nil otherwise. Engines that report
+// only a reason leave Construct and Suggestion empty.
+func (r *VerificationResponse) UnsupportedInput() *UnsupportedInput {
+	if r == nil || r.Status != StatusUnsupported {
+		return nil
+	}
+
+	var u UnsupportedInput
+	decodeResult(r.Result["unsupported"], &u)
+	if u.Reason == "" {
+		u.Reason, _ = r.Result["reason"].(string)
+	}
+	return &u
+}
+
+// GapReport flags input the service should support.
+type GapReport struct {
+	VerificationID string `json:"verification_id"`
+	Note           string `json:"note,omitempty"`
+}
+
+// ReportGap flags a coverage gap to the QWED team from code, typically
+// after a response with StatusUnsupported. verificationID is the
+// response's Metadata.RequestID; note describes what should be supported.
+func (c *Client) ReportGap(ctx context.Context, verificationID, note string) error {
+	if strings.TrimSpace(verificationID) == "" {
+		return invalidRequest("verification ID is required")
+	}
+
+	ctx, end := c.instrument(ctx, "ReportGap", "")
+	err := c.request(ctx, "POST", "/feedback/gaps", GapReport{VerificationID: verificationID, Note: note}, nil)
+	end(nil, err)
+	return err
+}
@@ -0,0 +1,63 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestUnsupportedInput(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"UNSUPPORTED","verified":false,"result":{"unsupported":{"construct":"integral","reason":"symbolic integration is not supported","suggestion":"evaluate the definite integral numerically"}},"metadata":{"request_id":"req-1"}}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	resp, err := client.VerifyMath(context.Background(), "integrate(x^2, 0, 1) = 1/3")
+	if err != nil {
+		t.Fatalf("VerifyMath() error = %v", err)
+	}
+	want := UnsupportedInput{Construct: "integral", Reason: "symbolic integration is not supported", Suggestion: "evaluate the definite integral numerically"}
+	if got := resp.UnsupportedInput(); got == nil || *got != want {
+		t.Errorf("UnsupportedInput() = %+v, want %+v", got, want)
+	}
+
+	if (&VerificationResponse{Status: StatusVerified, Verified: true}).UnsupportedInput() != nil {
+		t.Error("verified response should have no unsupported input")
+	}
+	reasonOnly := &VerificationResponse{Status: StatusUnsupported, Result: map[string]interface{}{"reason": "no parse"}}
+	if got := reasonOnly.UnsupportedInput(); got == nil || got.Reason != "no parse" || got.Construct != "" {
+		t.Errorf("UnsupportedInput() = %+v, want reason only", got)
+	}
+
+	formula := localVerifyFormula("=XLOOKUP(A1,B1:B3,C1:C3)", nil, 1.0)
+	if got := formula.UnsupportedInput(); got == nil || got.Construct != "function XLOOKUP" {
+		t.Errorf("formula UnsupportedInput() = %+v, want function XLOOKUP", got)
+	}
+}
+
+func TestReportGap(t *testing.T) {
+	var path string
+	var report GapReport
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		json.NewDecoder(r.Body).Decode(&report)
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	ctx := context.Background()
+	if err := client.ReportGap(ctx, "req-1", "please support integrals"); err != nil {
+		t.Fatalf("ReportGap() error = %v", err)
+	}
+	if path != "/feedback/gaps" || report != (GapReport{VerificationID: "req-1", Note: "please support integrals"}) {
+		t.Errorf("sent %s %+v", path, report)
+	}
+
+	if err := client.ReportGap(ctx, " ", "note"); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("ReportGap() error = %v, want ErrInvalidRequest", err)
+	}
+}
@@ -0,0 +1,120 @@
+package qwed
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ============================================================================
+// Usage and Rate Limits
+// ============================================================================
+
+// Rate limit headers sent by the API with every response.
+const (
+	HeaderRateLimitLimit     = "X-RateLimit-Limit"
+	HeaderRateLimitRemaining = "X-RateLimit-Remaining"
+	HeaderRateLimitReset     = "X-RateLimit-Reset" // Unix seconds, or seconds from now
+)
+
+// RateLimit is the API rate limit state reported with a response.
+type RateLimit struct {
+	Limit     int       // requests allowed per window, 0 if not reported
+	Remaining int       // requests left in the current window
+	Reset     time.Time // when the window resets, zero if not reported
+}
+
+// rateLimited is implemented by responses carrying a RateLimit.
+type rateLimited interface {
+	setRateLimit(*RateLimit)
+}
+
+func (r *VerificationResponse) setRateLimit(limit *RateLimit) { r.RateLimit = limit }
+func (u *Usage) setRateLimit(limit *RateLimit)                { u.RateLimit = limit }
+
+// parseRateLimit reads the rate limit headers of a response received at
+// now, or returns nil if it has none.
+func parseRateLimit(h http.Header, now time.Time) *RateLimit {
+	remaining, err := strconv.Atoi(h.Get(HeaderRateLimitRemaining))
+	if err != nil {
+		return nil
+	}
+	limit := &RateLimit{Remaining: remaining}
+	limit.Limit, _ = strconv.Atoi(h.Get(HeaderRateLimitLimit))
+	if reset, err := strconv.ParseInt(h.Get(HeaderRateLimitReset), 10, 64); err == nil {
+		// Values too small to be a recent timestamp count from now.
+		if reset < 1e9 {
+			limit.Reset = now.Add(time.Duration(reset) * time.Second)
+		} else {
+			limit.Reset = time.Unix(reset, 0)
+		}
+	}
+	return limit
+}
+
+// RateLimit returns the rate limit state reported by the most recent API
+// response, whichever call it answered, or nil before the first. Callers
+// sharing a client can check it to slow down before the API starts
+// rejecting requests.
+func (c *Client) RateLimit() *RateLimit {
+	return c.rateLimit.Load()
+}
+
+// UsagePeriod is the period Usage reports on.
+type UsagePeriod string
+
+const (
+	UsageDay   UsagePeriod = "day"
+	UsageWeek  UsagePeriod = "week"
+	UsageMonth UsagePeriod = "month" // the current billing month
+)
+
+// Usage is the account's API usage over a period.
+type Usage struct {
+	Period        UsagePeriod            `json:"period"`
+	Start         time.Time              `json:"start"`
+	End           time.Time              `json:"end"`
+	Verifications int                    `json:"verifications"`
+	Engines       map[string]EngineUsage `json:"engines,omitempty"` // by engine name
+	// Quota is the number of verifications allowed in the billing period,
+	// 0 if unlimited; Remaining is what is left of it.
+	Quota         int     `json:"quota"`
+	Remaining     int     `json:"remaining"`
+	EstimatedCost float64 `json:"estimated_cost"`
+	Currency      string  `json:"currency,omitempty"` // e.g. "USD"
+
+	RateLimit *RateLimit `json:"-"`
+}
+
+// EngineUsage is the usage of one engine.
+type EngineUsage struct {
+	Verifications int     `json:"verifications"`
+	Failed        int     `json:"failed"`
+	EstimatedCost float64 `json:"estimated_cost"`
+}
+
+// Usage returns the verification counts per engine, remaining quota and
+// estimated cost of the account over period, which is the current billing
+// month if empty.
+func (c *Client) Usage(ctx context.Context, period UsagePeriod) (*Usage, error) {
+	switch period {
+	case "", UsageDay, UsageWeek, UsageMonth:
+	default:
+		return nil, invalidRequest("unsupported usage period %q", period)
+	}
+
+	path := "/usage"
+	if period != "" {
+		path += "?period=" + url.QueryEscape(string(period))
+	}
+	ctx, end := c.instrument(ctx, "Usage", "")
+	var usage Usage
+	err := c.request(ctx, "GET", path, nil, &usage)
+	end(nil, err)
+	if err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
@@ -0,0 +1,109 @@
+package qwed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestUsage(t *testing.T) {
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/usage" || r.URL.Query().Get("period") != "month" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set(HeaderRateLimitRemaining, "42")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"period":        "month",
+			"verifications": 1200,
+			"engines": map[string]interface{}{
+				"math": map[string]interface{}{"verifications": 1000, "failed": 12, "estimated_cost": 1.5},
+				"code": map[string]interface{}{"verifications": 200, "estimated_cost": 2.0},
+			},
+			"quota":          10000,
+			"remaining":      8800,
+			"estimated_cost": 3.5,
+			"currency":       "USD",
+		})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	usage, err := client.Usage(context.Background(), UsageMonth)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if usage.Verifications != 1200 || usage.Remaining != 8800 || usage.Engines["math"].Failed != 12 || usage.EstimatedCost != 3.5 {
+		t.Errorf("unexpected usage: %+v", usage)
+	}
+	if usage.RateLimit == nil || usage.RateLimit.Remaining != 42 {
+		t.Errorf("expected the rate limit on the usage, got %+v", usage.RateLimit)
+	}
+
+	if _, err := client.Usage(context.Background(), "year"); !errors.Is(err, ErrInvalidRequest) {
+		t.Errorf("expected ErrInvalidRequest for an unknown period, got %v", err)
+	}
+}
+
+func TestResponseRateLimit(t *testing.T) {
+	reset := time.Now().Add(time.Minute).Truncate(time.Second)
+	remaining := 10
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderRateLimitLimit, "100")
+		w.Header().Set(HeaderRateLimitRemaining, strconv.Itoa(remaining))
+		w.Header().Set(HeaderRateLimitReset, strconv.FormatInt(reset.Unix(), 10))
+		if remaining == 0 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"code": "RATE_LIMITED", "message": "slow down"}})
+			return
+		}
+		remaining--
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "VERIFIED", "verified": true})
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithCache(NewLRUCache(10), time.Minute))
+	if client.RateLimit() != nil {
+		t.Error("expected no rate limit before the first response")
+	}
+
+	resp, err := client.VerifyMath(context.Background(), "2+2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	limit := resp.RateLimit
+	if limit == nil || limit.Limit != 100 || limit.Remaining != 10 || !limit.Reset.Equal(reset) {
+		t.Fatalf("unexpected rate limit: %+v", limit)
+	}
+	if client.RateLimit() != limit {
+		t.Errorf("expected the client to report the latest rate limit")
+	}
+
+	if cached, _ := client.VerifyMath(context.Background(), "2+2"); cached.RateLimit != nil {
+		t.Errorf("expected no rate limit on a cached response, got %+v", cached.RateLimit)
+	}
+
+	remaining = 0
+	_, err = client.VerifyMath(context.Background(), "3+3")
+	var apiErr *QWEDError
+	if !errors.As(err, &apiErr) || apiErr.RateLimit == nil || apiErr.RateLimit.Remaining != 0 {
+		t.Errorf("expected the rate limit on the error, got %v", err)
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	h := http.Header{}
+	if parseRateLimit(h, now) != nil {
+		t.Error("expected nil without headers")
+	}
+	h.Set(HeaderRateLimitRemaining, "5")
+	h.Set(HeaderRateLimitReset, "30")
+	limit := parseRateLimit(h, now)
+	if limit == nil || limit.Remaining != 5 || limit.Limit != 0 || !limit.Reset.Equal(now.Add(30*time.Second)) {
+		t.Errorf("expected a relative reset, got %+v", limit)
+	}
+}
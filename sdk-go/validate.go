@@ -0,0 +1,263 @@
+package qwed
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// Request Validation
+// ============================================================================
+
+// MaxPayloadSize is the largest request body, in bytes, that strict
+// validation lets through.
+const MaxPayloadSize = 1 << 20
+
+// WithStrictValidation checks requests on the client before sending them
+// and rejects obviously invalid ones with an error wrapping
+// ErrInvalidRequest: empty inputs, code languages and SQL dialects the API
+// does not support, and bodies over MaxPayloadSize. Rejected requests cost
+// no round trip and no quota.
+func WithStrictValidation() ClientOption {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
+
+// requiredFields lists the body fields each engine needs to be non-empty.
+var requiredFields = map[VerificationType][]string{
+	TypeNaturalLanguage: {"query"},
+	TypeMath:            {"expression"},
+	TypeLogic:           {"query"},
+	TypeCode:            {"code", "language"},
+	TypeFact:            {"claim", "context"},
+	TypeSQL:             {"query"},
+	TypeJSON:            {"json", "schema"},
+	TypePromptSafety:    {"input"},
+	TypeInfra:           {"content", "kind"},
+	TypeGraphQL:         {"query", "schema_sdl"},
+	TypeChart:           {"image"},
+	TypeTests:           {"implementation", "tests", "language"},
+	TypeExtraction:      {"source", "extracted"},
+}
+
+// codeLanguages are the languages the code engine scans, with their
+// aliases.
+var codeLanguages = map[string]bool{
+	"python": true, "py": true,
+	"javascript": true, "js": true,
+	"typescript": true, "ts": true,
+	"java": true,
+	"go":   true,
+	"sql":  true,
+}
+
+// sqlDialects are the dialects the SQL engine parses.
+var sqlDialects = map[string]bool{
+	"postgres": true, "postgresql": true, "mysql": true, "sqlite": true,
+	"bigquery": true, "snowflake": true, "redshift": true, "tsql": true,
+	"oracle": true, "duckdb": true, "spark": true, "databricks": true,
+	"hive": true, "presto": true, "trino": true, "athena": true,
+	"clickhouse": true, "teradata": true,
+}
+
+// validateRequest checks a verification request body for engine.
+func validateRequest(engine VerificationType, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	if len(data) > MaxPayloadSize {
+		return invalidRequest("request body is %d bytes, over the %d byte limit", len(data), MaxPayloadSize)
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return fmt.Errorf("failed to unmarshal request: %w", err)
+	}
+	for _, name := range requiredFields[engine] {
+		if s, _ := fields[name].(string); strings.TrimSpace(s) == "" {
+			return invalidRequest("%s is empty", name)
+		}
+	}
+
+	options, _ := fields["options"].(map[string]interface{})
+	if pin, _ := options["engine_pin"].(string); pin != "" && !enginePinPattern.MatchString(pin) {
+		return invalidRequest("engine pin %q is not of the form engine@version", pin)
+	}
+
+	switch engine {
+	case TypeCode:
+		if lang, _ := fields["language"].(string); !codeLanguages[strings.ToLower(lang)] {
+			return invalidRequest("unsupported code language %q", lang)
+		}
+		if mode, _ := options["check_mode"].(string); mode != "" && !checkModes[CheckMode(mode)] {
+			return invalidRequest("unsupported code check mode %q", mode)
+		}
+	case TypeSQL:
+		if dialect, _ := fields["dialect"].(string); dialect != "" && !sqlDialects[strings.ToLower(dialect)] {
+			return invalidRequest("unsupported SQL dialect %q", dialect)
+		}
+	case TypeInfra:
+		switch kind, _ := fields["kind"].(string); InfraKind(kind) {
+		case InfraDockerfile, InfraTerraform, InfraKubernetes:
+		default:
+			return invalidRequest("unsupported infrastructure kind %q", kind)
+		}
+	case TypeChart:
+		if mediaType, _ := fields["media_type"].(string); !chartMediaTypes[mediaType] {
+			return invalidRequest("unsupported chart image type %q", mediaType)
+		}
+		if claims, _ := fields["claims"].([]interface{}); len(claims) == 0 {
+			return invalidRequest("claims is empty")
+		}
+	case TypeConstraints:
+		return validateConstraints(fields)
+	case TypeCompliance:
+		return validateCompliance(fields)
+	case TypeExtraction:
+		if extracted, _ := fields["extracted"].(string); !json.Valid([]byte(extracted)) {
+			return invalidRequest("extracted is not valid JSON")
+		}
+	case TypeOptimization:
+		problem, _ := fields["problem"].(map[string]interface{})
+		if sense, _ := problem["sense"].(string); OptimizationSense(sense) != Maximize && OptimizationSense(sense) != Minimize {
+			return invalidRequest("unsupported optimization sense %q", sense)
+		}
+		if objective, _ := problem["objective"].(string); strings.TrimSpace(objective) == "" {
+			return invalidRequest("objective is empty")
+		}
+		if solution, _ := fields["solution"].(map[string]interface{}); len(solution) == 0 {
+			return invalidRequest("solution is empty")
+		}
+		if tolerance, _ := fields["tolerance"].(float64); tolerance < 0 {
+			return invalidRequest("tolerance %v is negative", tolerance)
+		}
+	case TypeGraph:
+		edges, _ := fields["edges"].([]interface{})
+		if len(edges) == 0 {
+			return invalidRequest("edges is empty")
+		}
+		for i, e := range edges {
+			edge, _ := e.(map[string]interface{})
+			from, _ := edge["from"].(string)
+			to, _ := edge["to"].(string)
+			if strings.TrimSpace(from) == "" || strings.TrimSpace(to) == "" {
+				return invalidRequest("edge %d: node name is empty", i)
+			}
+		}
+		if claim, _ := fields["claim"].(string); strings.TrimSpace(claim) == "" {
+			return invalidRequest("claim is empty")
+		}
+	case TypeTests:
+		if lang, _ := fields["language"].(string); !testLanguages[strings.ToLower(lang)] {
+			return invalidRequest("unsupported test language %q", lang)
+		}
+	}
+	return nil
+}
+
+// validateBatch checks the items of a batch request.
+func validateBatch(items []BatchItem) error {
+	if len(items) == 0 {
+		return invalidRequest("batch has no items")
+	}
+	for i, item := range items {
+		if strings.TrimSpace(item.Query) == "" {
+			return invalidRequest("item %d: query is empty", i)
+		}
+		if _, ok := requiredFields[item.Type]; item.Type != "" && !ok {
+			return invalidRequest("item %d: unsupported type %q", i, item.Type)
+		}
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	if len(data) > MaxPayloadSize {
+		return invalidRequest("request body is %d bytes, over the %d byte limit", len(data), MaxPayloadSize)
+	}
+	return nil
+}
+
+// validateConstraints checks that a constraint problem declares its
+// variables once each and that the solution assigns only those.
+func validateConstraints(fields map[string]interface{}) error {
+	variables, _ := fields["variables"].([]interface{})
+	if len(variables) == 0 {
+		return invalidRequest("variables is empty")
+	}
+	if constraints, _ := fields["constraints"].([]interface{}); len(constraints) == 0 {
+		return invalidRequest("constraints is empty")
+	}
+	solution, _ := fields["solution"].(map[string]interface{})
+	if len(solution) == 0 {
+		return invalidRequest("solution is empty")
+	}
+
+	declared := make(map[string]bool, len(variables))
+	for i, v := range variables {
+		variable, _ := v.(map[string]interface{})
+		name, _ := variable["name"].(string)
+		if strings.TrimSpace(name) == "" {
+			return invalidRequest("variable %d: name is empty", i)
+		}
+		if declared[name] {
+			return invalidRequest("variable %q is declared twice", name)
+		}
+		declared[name] = true
+	}
+	for name := range solution {
+		if !declared[name] {
+			return invalidRequest("solution assigns undeclared variable %q", name)
+		}
+	}
+	return nil
+}
+
+// validateCompliance checks that compliance rules have unique IDs and a
+// known kind, and that required content is described.
+func validateCompliance(fields map[string]interface{}) error {
+	if text, _ := fields["text"].(string); strings.TrimSpace(text) == "" {
+		return invalidRequest("text is empty")
+	}
+	rules, _ := fields["rules"].([]interface{})
+	if len(rules) == 0 {
+		return invalidRequest("rules is empty")
+	}
+
+	ids := make(map[string]bool, len(rules))
+	for i, r := range rules {
+		rule, _ := r.(map[string]interface{})
+		id, _ := rule["id"].(string)
+		if strings.TrimSpace(id) == "" {
+			return invalidRequest("rule %d: id is empty", i)
+		}
+		if ids[id] {
+			return invalidRequest("rule %q is declared twice", id)
+		}
+		ids[id] = true
+
+		description, _ := rule["description"].(string)
+		phrases, _ := rule["phrases"].([]interface{})
+		switch kind, _ := rule["kind"].(string); ComplianceRuleKind(kind) {
+		case RuleProhibited:
+			if strings.TrimSpace(description) == "" {
+				return invalidRequest("rule %q: description is empty", id)
+			}
+		case RuleRequired:
+			if strings.TrimSpace(description) == "" && len(phrases) == 0 {
+				return invalidRequest("rule %q: description and phrases are empty", id)
+			}
+		default:
+			return invalidRequest("rule %q: unsupported kind %q", id, kind)
+		}
+	}
+	return nil
+}
+
+// invalidRequest returns an error wrapping ErrInvalidRequest.
+func invalidRequest(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidRequest, fmt.Sprintf(format, args...))
+}
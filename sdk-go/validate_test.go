@@ -0,0 +1,82 @@
+package qwed
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithStrictValidation(t *testing.T) {
+	var calls int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL), WithStrictValidation())
+	ctx := context.Background()
+
+	invalid := []struct {
+		name string
+		call func() error
+		want string
+	}{
+		{"empty query", func() error { _, err := client.Verify(ctx, "  "); return err }, "query is empty"},
+		{"empty expression", func() error { _, err := client.VerifyMath(ctx, ""); return err }, "expression is empty"},
+		{"missing language", func() error { _, err := client.VerifyCode(ctx, "x = 1", ""); return err }, "language is empty"},
+		{"unsupported language", func() error { _, err := client.VerifyCode(ctx, "x = 1", "cobol"); return err }, `unsupported code language "cobol"`},
+		{"unsupported dialect", func() error { _, err := client.VerifySQL(ctx, "SELECT 1", "", "access"); return err }, `unsupported SQL dialect "access"`},
+		{"empty fact context", func() error { _, err := client.VerifyFact(ctx, "Paris is in France", ""); return err }, "context is empty"},
+		{"unsupported infra kind", func() error { _, err := client.VerifyInfra(ctx, "FROM alpine", "helm"); return err }, `unsupported infrastructure kind "helm"`},
+		{"oversized payload", func() error {
+			_, err := client.Verify(ctx, strings.Repeat("a", MaxPayloadSize))
+			return err
+		}, "over the 1048576 byte limit"},
+		{"empty batch item", func() error {
+			_, err := client.VerifyBatch(ctx, []BatchItem{{Query: "2+2=4", Type: TypeMath}, {Query: ""}}, nil)
+			return err
+		}, "item 1: query is empty"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, ErrInvalidRequest) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want ErrInvalidRequest with %q", err, tt.want)
+			}
+		})
+	}
+	if n := atomic.LoadInt32(&calls); n != 0 {
+		t.Fatalf("invalid requests made %d API calls, want 0", n)
+	}
+
+	if _, err := client.VerifyCode(ctx, "x = 1", "Python"); err != nil {
+		t.Errorf("VerifyCode() error = %v", err)
+	}
+	if _, err := client.VerifySQL(ctx, "SELECT 1", "", ""); err != nil {
+		t.Errorf("VerifySQL() with default dialect error = %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("valid requests made %d API calls, want 2", n)
+	}
+}
+
+func TestValidationDisabledByDefault(t *testing.T) {
+	var calls int32
+	server := mockServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"status":"VERIFIED","verified":true}`))
+	})
+	defer server.Close()
+
+	client := NewClient("test-key", WithBaseURL(server.URL))
+	if _, err := client.VerifyCode(context.Background(), "x = 1", "cobol"); err != nil {
+		t.Fatalf("VerifyCode() error = %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("API calls = %d, want the request sent unchecked", calls)
+	}
+}
@@ -0,0 +1,91 @@
+package qwed
+
+// ============================================================================
+// Verdicts
+// ============================================================================
+
+// Verdict is the three-state outcome of a verification. Unlike the Verified
+// flag, it separates claims shown to be wrong from claims that could not be
+// checked.
+type Verdict string
+
+const (
+	VerdictVerified     Verdict = "verified"     // the claim was proven correct
+	VerdictRefuted      Verdict = "refuted"      // the claim was proven wrong
+	VerdictInconclusive Verdict = "inconclusive" // the claim could not be checked
+)
+
+// InconclusiveReason explains an inconclusive verdict.
+type InconclusiveReason string
+
+const (
+	ReasonTimeout        InconclusiveReason = "timeout"         // the engine timed out
+	ReasonUnsupported    InconclusiveReason = "unsupported"     // the engine cannot handle the input
+	ReasonLowConfidence  InconclusiveReason = "low_confidence"  // confidence below the policy threshold
+	ReasonBudgetExceeded InconclusiveReason = "budget_exceeded" // the client's latency budget ran out
+	ReasonEngineError    InconclusiveReason = "engine_error"    // the engine reported an error
+	ReasonUnreachable    InconclusiveReason = "unreachable"     // a cited source could not be fetched
+)
+
+// inconclusiveReasonKey is the Result field holding the reason for
+// responses made inconclusive by the client.
+const inconclusiveReasonKey = "inconclusive_reason"
+
+// Verdict returns the three-state outcome of the response. Timeouts,
+// unsupported inputs, engine errors and client-side inconclusive results
+// (low confidence, exceeded latency budget) are inconclusive rather than
+// refuted.
+func (r *VerificationResponse) Verdict() Verdict {
+	switch {
+	case r == nil:
+		return VerdictInconclusive
+	case r.Verified:
+		return VerdictVerified
+	}
+	switch r.Status {
+	case StatusTimeout, StatusUnsupported, StatusError, StatusInconclusive:
+		return VerdictInconclusive
+	case "":
+		if r.Error != nil {
+			return VerdictInconclusive
+		}
+	}
+	return VerdictRefuted
+}
+
+// InconclusiveReason returns why the verdict is inconclusive, or an empty
+// reason if it is not.
+func (r *VerificationResponse) InconclusiveReason() InconclusiveReason {
+	if r == nil || r.Verdict() != VerdictInconclusive {
+		return ""
+	}
+	if reason, ok := r.Result[inconclusiveReasonKey].(string); ok && reason != "" {
+		return InconclusiveReason(reason)
+	}
+	switch r.Status {
+	case StatusTimeout:
+		return ReasonTimeout
+	case StatusUnsupported:
+		return ReasonUnsupported
+	}
+	return ReasonEngineError
+}
+
+// inconclusive returns a copy of resp marked inconclusive for reason. The
+// copy keeps cached results unchanged.
+func inconclusive(resp *VerificationResponse, reason InconclusiveReason) *VerificationResponse {
+	out := VerificationResponse{}
+	if resp != nil {
+		out = *resp
+	}
+	out.Verified = false
+	out.Status = StatusInconclusive
+	out.Result = make(map[string]interface{}, len(out.Result)+1)
+	if resp != nil {
+		for k, v := range resp.Result {
+			out.Result[k] = v
+		}
+	}
+	out.Result[inconclusiveReasonKey] = string(reason)
+	return &out
+}
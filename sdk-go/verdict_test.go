@@ -0,0 +1,46 @@
+package qwed
+
+import "testing"
+
+func TestVerdict(t *testing.T) {
+	tests := []struct {
+		name    string
+		resp    *VerificationResponse
+		verdict Verdict
+		reason  InconclusiveReason
+	}{
+		{"verified", &VerificationResponse{Status: StatusVerified, Verified: true}, VerdictVerified, ""},
+		{"failed", &VerificationResponse{Status: StatusFailed}, VerdictRefuted, ""},
+		{"corrected", &VerificationResponse{Status: StatusCorrected}, VerdictRefuted, ""},
+		{"blocked", &VerificationResponse{Status: StatusBlocked}, VerdictRefuted, ""},
+		{"timeout", &VerificationResponse{Status: StatusTimeout}, VerdictInconclusive, ReasonTimeout},
+		{"unsupported", &VerificationResponse{Status: StatusUnsupported}, VerdictInconclusive, ReasonUnsupported},
+		{"engine error", &VerificationResponse{Status: StatusError}, VerdictInconclusive, ReasonEngineError},
+		{"error without status", &VerificationResponse{Error: &ErrorInfo{Code: "E", Message: "boom"}}, VerdictInconclusive, ReasonEngineError},
+		{"low confidence", inconclusive(&VerificationResponse{Verified: true}, ReasonLowConfidence), VerdictInconclusive, ReasonLowConfidence},
+		{"nil", nil, VerdictInconclusive, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resp.Verdict(); got != tt.verdict {
+				t.Errorf("expected verdict %s, got %s", tt.verdict, got)
+			}
+			if got := tt.resp.InconclusiveReason(); got != tt.reason {
+				t.Errorf("expected reason %q, got %q", tt.reason, got)
+			}
+		})
+	}
+}
+
+func TestInconclusiveCopiesResponse(t *testing.T) {
+	orig := &VerificationResponse{Status: StatusVerified, Verified: true, Result: map[string]interface{}{"confidence": 0.5}}
+	out := inconclusive(orig, ReasonLowConfidence)
+
+	if out.Verified || out.Status != StatusInconclusive || out.Result["confidence"] != 0.5 {
+		t.Errorf("unexpected inconclusive response: %+v", out)
+	}
+	if !orig.Verified || orig.Status != StatusVerified || orig.Result[inconclusiveReasonKey] != nil {
+		t.Errorf("expected original response to be unchanged, got %+v", orig)
+	}
+}
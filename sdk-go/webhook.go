@@ -0,0 +1,146 @@
+package qwed
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ============================================================================
+// Batch Webhooks
+// ============================================================================
+
+// HeaderWebhookSignature carries the signature of a webhook delivery, as
+// "t=<unix seconds>,v1=<hex HMAC-SHA256>". The HMAC is computed with the
+// webhook secret over the timestamp, a dot and the request body.
+const HeaderWebhookSignature = "X-QWED-Signature"
+
+// signatureTolerance is how old a signed delivery or request's timestamp
+// may be, bounding replays of captured ones.
+const signatureTolerance = 5 * time.Minute
+
+// ErrInvalidSignature is returned when the signature of a webhook delivery
+// or signed request is missing, malformed, too old, or does not match its
+// body.
+var ErrInvalidSignature = errors.New("qwed: invalid signature")
+
+// Batch event types.
+const (
+	BatchEventCompleted = "batch.completed" // the job finished; Status tells how
+	BatchEventFailed    = "batch.failed"    // the job could not run
+)
+
+// BatchEvent is a batch job notification delivered to a webhook registered
+// with BatchOptions.WebhookURL.
+type BatchEvent struct {
+	ID        string        `json:"id"` // unique per event; redeliveries repeat it
+	Type      string        `json:"type"`
+	JobID     string        `json:"job_id"`
+	Status    string        `json:"status"`
+	Summary   *BatchSummary `json:"summary,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// WebhookHandler returns an http.Handler receiving batch events, so long
+// jobs need no polling:
+//
+//	http.Handle("/hooks/qwed", qwed.WebhookHandler(secret, func(e qwed.BatchEvent) {
+//		go fetchResults(e.JobID)
+//	}))
+//
+// Deliveries with an invalid signature are rejected with 401 and events
+// that do not parse with 400; fn is called for the rest before replying
+// 204, so hand long work off to a goroutine. A delivery that fails is
+// retried, so fn may see an event more than once; use its ID to
+// deduplicate.
+func WebhookHandler(secret string, fn func(BatchEvent)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxPayloadSize))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if err := VerifyWebhookSignature(body, r.Header.Get(HeaderWebhookSignature), secret); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		var event BatchEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			http.Error(w, "failed to parse event", http.StatusBadRequest)
+			return
+		}
+		fn(event)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// VerifyWebhookSignature checks the HeaderWebhookSignature value of a
+// webhook delivery against its body, for receiving events without
+// WebhookHandler. It returns an error wrapping ErrInvalidSignature if the
+// delivery was not signed with secret in the last five minutes.
+func VerifyWebhookSignature(body []byte, header, secret string) error {
+	return verifySignatureHeader(body, header, secret)
+}
+
+// verifySignatureHeader checks a "t=<unix seconds>,v1=<hex HMAC-SHA256>"
+// signature of body, as sent with webhook deliveries and signed requests.
+func verifySignatureHeader(body []byte, header, secret string) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return invalidSignature("missing timestamp or signature")
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return invalidSignature("malformed timestamp %q", timestamp)
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > signatureTolerance || age < -signatureTolerance {
+		return invalidSignature("timestamp is %s off, over the %s tolerance", age.Round(time.Second), signatureTolerance)
+	}
+
+	expected := bodySignature(body, timestamp, secret)
+	for _, signature := range signatures { // several during secret rotation
+		if sig, err := hex.DecodeString(signature); err == nil && hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return invalidSignature("signature does not match")
+}
+
+// bodySignature computes the HMAC of a timestamped body.
+func bodySignature(body []byte, timestamp, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// invalidSignature returns an error wrapping ErrInvalidSignature.
+func invalidSignature(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s", ErrInvalidSignature, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,73 @@
+package qwed
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// signWebhook signs body with secret at t, as the API does.
+func signWebhook(body, secret string, t time.Time) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(bodySignature([]byte(body), ts, secret)))
+}
+
+func TestWebhookHandler(t *testing.T) {
+	const body = `{"id":"evt_1","type":"batch.completed","job_id":"job-1","status":"partial","summary":{"total":4,"verified":3,"failed":1,"success_rate":0.75},"created_at":"2026-10-16T12:00:00Z"}`
+
+	var events []BatchEvent
+	handler := WebhookHandler("s3cret", func(e BatchEvent) { events = append(events, e) })
+
+	tests := []struct {
+		name, method, body, signature string
+		status                        int
+	}{
+		{"valid", "POST", body, signWebhook(body, "s3cret", time.Now()), http.StatusNoContent},
+		{"wrong secret", "POST", body, signWebhook(body, "other", time.Now()), http.StatusUnauthorized},
+		{"tampered body", "POST", strings.Replace(body, "partial", "completed", 1), signWebhook(body, "s3cret", time.Now()), http.StatusUnauthorized},
+		{"replayed", "POST", body, signWebhook(body, "s3cret", time.Now().Add(-time.Hour)), http.StatusUnauthorized},
+		{"unsigned", "POST", body, "", http.StatusUnauthorized},
+		{"not an event", "POST", "[]", signWebhook("[]", "s3cret", time.Now()), http.StatusBadRequest},
+		{"wrong method", "GET", "", "", http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/hooks/qwed", strings.NewReader(tt.body))
+		if tt.signature != "" {
+			req.Header.Set(HeaderWebhookSignature, tt.signature)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.status, rec.Code)
+		}
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("expected one event, got %+v", events)
+	}
+	e := events[0]
+	if e.ID != "evt_1" || e.Type != BatchEventCompleted || e.JobID != "job-1" || e.Status != BatchPartial || e.Summary.Failed != 1 || e.CreatedAt.IsZero() {
+		t.Errorf("unexpected event: %+v", e)
+	}
+}
+
+func TestVerifyWebhookSignatureRotation(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	now := time.Now()
+	old := signWebhook(string(body), "old-secret", now)
+	current := signWebhook(string(body), "new-secret", now)
+	header := old + ",v1=" + strings.SplitN(current, "v1=", 2)[1]
+
+	if err := VerifyWebhookSignature(body, header, "new-secret"); err != nil {
+		t.Errorf("expected either signature to be accepted, got %v", err)
+	}
+	if err := VerifyWebhookSignature(body, "t=abc,v1=00", "new-secret"); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for a malformed header, got %v", err)
+	}
+}